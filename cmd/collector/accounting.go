@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/accounting"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/collector"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/export"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/store"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Accounting report flags
+var (
+	reportInputs  []string
+	reportStore   string
+	reportSince   time.Duration
+	reportGroupBy []string
+	reportFormat  string
+	reportOutput  string
+
+	chargebackPeriod string
+	chargebackFormat string
+)
+
+// Output format of chargeback reports besides text, csv and json
+const formatHTML = "html"
+
+// accountingAggregator is a report fed with accounting records
+type accountingAggregator interface {
+	Add(acct *pcf.AccountingData) bool
+	ReadExport(in io.Reader) error
+}
+
+func createAccountingCmd() *cobra.Command {
+	accountingCmd := &cobra.Command{
+		Use:   "accounting",
+		Short: "Work with MQI accounting records",
+	}
+
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarise accounting records by application and user",
+		Long: `Aggregate MQI accounting records by application and/or user and print the
+number of connections, API calls and bytes put and got for each, as a
+lightweight chargeback report.
+
+By default the statistics and accounting queues are drained once, consuming the
+messages as in normal collection. Use --input to report on files written by
+"export --type accounting" instead, e.g. a day of exports collected from cron,
+or --store to report on the history kept by a collector with store.enabled.`,
+		RunE: runAccountingReport,
+	}
+
+	reportCmd.Flags().StringSliceVarP(&reportInputs, "input", "i", nil, "Export JSON lines file to read instead of the accounting queue (repeatable)")
+	reportCmd.Flags().StringVar(&reportStore, "store", "", "History store database to read instead of the accounting queue")
+	reportCmd.Flags().DurationVar(&reportSince, "since", 0, "Only include records from this far back (0 = all)")
+	reportCmd.Flags().StringSliceVar(&reportGroupBy, "group-by", []string{accounting.GroupApplication, accounting.GroupUser}, "Fields to group by (application, user)")
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", outputText, "Report format (text, csv)")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file (default stdout)")
+
+	chargebackCmd := &cobra.Command{
+		Use:   "chargeback",
+		Short: "Summarise accounting records per day or month for chargeback",
+		Long: `Aggregate MQI accounting records by application and/or user separately for
+every UTC day or month, with the connections, API calls, bytes put and got and
+each row's share of the period's API calls, for chargeback or showback.
+
+Records are read from the queues, export files (--input) or a history store
+(--store) as for "accounting report". Use --format html for a standalone page
+to publish or mail, or csv for a spreadsheet.`,
+		RunE: runAccountingChargeback,
+	}
+
+	chargebackCmd.Flags().StringSliceVarP(&reportInputs, "input", "i", nil, "Export JSON lines file to read instead of the accounting queue (repeatable)")
+	chargebackCmd.Flags().StringVar(&reportStore, "store", "", "History store database to read instead of the accounting queue")
+	chargebackCmd.Flags().DurationVar(&reportSince, "since", 0, "Only include records from this far back (0 = all)")
+	chargebackCmd.Flags().StringSliceVar(&reportGroupBy, "group-by", []string{accounting.GroupApplication, accounting.GroupUser}, "Fields to group by (application, user)")
+	chargebackCmd.Flags().StringVar(&chargebackPeriod, "period", accounting.PeriodDay, "Aggregation period (day, month)")
+	chargebackCmd.Flags().StringVarP(&chargebackFormat, "format", "f", outputText, "Report format (text, csv, html, json)")
+	chargebackCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file (default stdout)")
+
+	accountingCmd.AddCommand(reportCmd, chargebackCmd)
+	return accountingCmd
+}
+
+func runAccountingReport(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	if reportFormat != outputText && reportFormat != export.FormatCSV {
+		return fmt.Errorf("invalid report format: %s (use text or csv)", reportFormat)
+	}
+
+	var since time.Time
+	if reportSince > 0 {
+		since = time.Now().Add(-reportSince)
+	}
+
+	report, err := accounting.NewReport(reportGroupBy, since, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	parseErrors, err := readReportRecords(report, since, logger)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"records": report.Records(),
+		"skipped": report.Skipped(),
+	}).Info("Accounting report aggregated")
+
+	out, closeOutput, err := reportWriter(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	if reportFormat == export.FormatCSV {
+		err = report.WriteCSV(out)
+	} else if !quiet {
+		err = report.WriteText(out)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if parseErrors > 0 {
+		return withExitCode(exitPartialCollection, fmt.Errorf("%d message(s) could not be parsed", parseErrors))
+	}
+	return nil
+}
+
+func runAccountingChargeback(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	switch chargebackFormat {
+	case outputText, export.FormatCSV, formatHTML, outputJSON:
+	default:
+		return fmt.Errorf("invalid chargeback format: %s (use text, csv, html or json)", chargebackFormat)
+	}
+
+	var since time.Time
+	if reportSince > 0 {
+		since = time.Now().Add(-reportSince)
+	}
+
+	chargeback, err := accounting.NewChargeback(chargebackPeriod, reportGroupBy, since, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	parseErrors, err := readReportRecords(chargeback, since, logger)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"records": chargeback.Records(),
+		"skipped": chargeback.Skipped(),
+		"period":  chargebackPeriod,
+	}).Info("Chargeback report aggregated")
+
+	out, closeOutput, err := reportWriter(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	switch chargebackFormat {
+	case export.FormatCSV:
+		err = chargeback.WriteCSV(out)
+	case formatHTML:
+		err = chargeback.WriteHTML(out)
+	case outputJSON:
+		err = writeJSON(out, map[string]interface{}{"period": chargebackPeriod, "periods": chargeback.Periods()})
+	default:
+		if !quiet {
+			err = chargeback.WriteText(out)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	if parseErrors > 0 {
+		return withExitCode(exitPartialCollection, fmt.Errorf("%d message(s) could not be parsed", parseErrors))
+	}
+	return nil
+}
+
+// readReportRecords feeds the report from the history store, the export files or the
+// queues, returning the number of queue messages that could not be parsed
+func readReportRecords(report accountingAggregator, since time.Time, logger *logrus.Logger) (int, error) {
+	if reportStore != "" {
+		return 0, readReportStore(report, reportStore, since, logger)
+	}
+	if len(reportInputs) > 0 {
+		for _, path := range reportInputs {
+			if err := readReportInput(report, path); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+	return collectReportRecords(report, logger)
+}
+
+// reportWriter returns the --output file, or the command's output, and a function
+// closing it
+func reportWriter(cmd *cobra.Command) (io.Writer, func(), error) {
+	if reportOutput == "" {
+		return cmd.OutOrStdout(), func() {}, nil
+	}
+	file, err := os.Create(reportOutput)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return file, func() { file.Close() }, nil
+}
+
+// readReportInput adds the accounting records of an export file to the report
+func readReportInput(report accountingAggregator, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer file.Close()
+
+	if err := report.ReadExport(file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// readReportStore adds the accounting records kept in a history store to the report
+func readReportStore(report accountingAggregator, path string, since time.Time, logger *logrus.Logger) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("history store not found: %w", err)
+	}
+
+	history, err := store.Open(&config.StoreConfig{Path: path}, logger)
+	if err != nil {
+		return err
+	}
+	defer history.Close()
+
+	records, err := history.Accounting(context.Background(), store.Query{Since: since})
+	if err != nil {
+		return err
+	}
+	for _, acct := range records {
+		report.Add(acct)
+	}
+	return nil
+}
+
+// collectReportRecords drains the queues once and adds the accounting records to the
+// report, returning the number of messages that could not be parsed
+func collectReportRecords(report accountingAggregator, logger *logrus.Logger) (int, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return 0, configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return 0, configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	// The report never needs the metrics HTTP server
+	cfg.Prometheus.EnableOTel = false
+	cfg.API.Enabled = false
+	cfg.WebSocket.Enabled = false
+
+	col, err := collector.NewCollector(cfg, logging.NewLogrus(logger))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create collector: %w", err)
+	}
+	defer col.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	records, err := col.CollectRecords(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("collection failed: %w", err)
+	}
+
+	for _, acct := range records.Accounting {
+		report.Add(acct)
+	}
+
+	return records.ParseErrors, nil
+}
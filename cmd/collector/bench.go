@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/simulator"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Bench flags
+var (
+	benchIterations int
+	benchSimulated  int
+)
+
+func createBenchCmd() *cobra.Command {
+	benchCmd := &cobra.Command{
+		Use:   "bench [capture-dir]",
+		Short: "Measure PCF parser throughput, allocations and latency",
+		Long: `Parse a directory of captured PCF messages repeatedly and report messages per
+second, allocations per message and parse latency percentiles, to help size the
+collector for large queue managers.
+
+Every file in the directory except *.json is read as one raw PCF message. Without
+a directory, statistics and accounting messages from the simulator are used.
+Captures saved with pcf-dumper -save can be passed directly.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runBench,
+	}
+
+	benchCmd.Flags().IntVar(&benchIterations, "iterations", 10, "Number of passes over the messages")
+	benchCmd.Flags().IntVar(&benchSimulated, "simulated", 100, "Simulated intervals to generate when no directory is given")
+	addOutputFlag(benchCmd)
+
+	return benchCmd
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+	if benchIterations < 1 {
+		return fmt.Errorf("--iterations must be at least 1")
+	}
+
+	var messages []benchMessage
+	var err error
+	if len(args) == 1 {
+		messages, err = loadCaptureDir(args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		messages = simulatedBenchMessages(benchSimulated)
+	}
+
+	// Parser logging is part of the measured cost, but must not reach the terminal
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.ErrorLevel)
+
+	result := runParserBench(pcf.NewParser(logging.NewLogrus(logger)), messages, benchIterations)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), result)
+	}
+	if textOutput() {
+		result.print(cmd.OutOrStdout())
+	}
+	return nil
+}
+
+// benchMessage is one raw PCF message fed to the parser
+type benchMessage struct {
+	name    string
+	msgType string
+	data    []byte
+}
+
+// loadCaptureDir reads every non-JSON file in dir as a raw PCF message
+func loadCaptureDir(dir string) ([]benchMessage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture directory: %w", err)
+	}
+
+	var messages []benchMessage
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read capture: %w", err)
+		}
+		messages = append(messages, benchMessage{name: entry.Name(), msgType: "capture", data: data})
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no captured messages found in %s", dir)
+	}
+	return messages, nil
+}
+
+// simulatedBenchMessages generates the given number of intervals of synthetic messages
+func simulatedBenchMessages(intervals int) []benchMessage {
+	opts := simulator.DefaultOptions()
+	opts.Seed = 1
+	generator := simulator.NewGenerator(opts)
+
+	var messages []benchMessage
+	for i := 0; i < intervals; i++ {
+		stats, acct := generator.Next()
+		for _, msg := range append(stats, acct...) {
+			messages = append(messages, benchMessage{
+				name:    fmt.Sprintf("simulated-%d", len(messages)),
+				msgType: msg.Type,
+				data:    msg.Data,
+			})
+		}
+	}
+	return messages
+}
+
+// benchResult summarises a parser benchmark run
+type benchResult struct {
+	Messages          int     `json:"messages"`
+	Iterations        int     `json:"iterations"`
+	Parsed            int     `json:"parsed"`
+	Errors            int     `json:"errors"`
+	Bytes             int64   `json:"bytes"`
+	DurationSeconds   float64 `json:"duration_seconds"`
+	MessagesPerSecond float64 `json:"messages_per_second"`
+	AllocsPerMessage  float64 `json:"allocs_per_message"`
+	BytesPerMessage   float64 `json:"alloc_bytes_per_message"`
+	P50Micros         float64 `json:"p50_us"`
+	P99Micros         float64 `json:"p99_us"`
+	MaxMicros         float64 `json:"max_us"`
+}
+
+// runParserBench parses every message iterations times, timing each parse individually
+func runParserBench(parser *pcf.Parser, messages []benchMessage, iterations int) *benchResult {
+	result := &benchResult{Messages: len(messages), Iterations: iterations}
+	latencies := make([]time.Duration, 0, len(messages)*iterations)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for i := 0; i < iterations; i++ {
+		for _, msg := range messages {
+			parseStart := time.Now()
+			data, err := parser.ParseMessage(msg.data, msg.msgType)
+			latencies = append(latencies, time.Since(parseStart))
+			if err != nil {
+				result.Errors++
+			} else {
+				// As the collector does once a record has been processed
+				pcf.Release(data)
+			}
+			result.Bytes += int64(len(msg.data))
+		}
+	}
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	result.Parsed = len(latencies)
+	result.DurationSeconds = elapsed.Seconds()
+	if result.Parsed == 0 {
+		return result
+	}
+	if elapsed > 0 {
+		result.MessagesPerSecond = float64(result.Parsed) / elapsed.Seconds()
+	}
+	result.AllocsPerMessage = float64(after.Mallocs-before.Mallocs) / float64(result.Parsed)
+	result.BytesPerMessage = float64(after.TotalAlloc-before.TotalAlloc) / float64(result.Parsed)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50Micros = micros(percentile(latencies, 0.50))
+	result.P99Micros = micros(percentile(latencies, 0.99))
+	result.MaxMicros = micros(latencies[len(latencies)-1])
+
+	return result
+}
+
+// percentile returns the p-th percentile of sorted latencies using the nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// print writes the benchmark summary
+func (r *benchResult) print(out io.Writer) {
+	fmt.Fprintf(out, "Parsed %d messages (%d distinct x %d iterations, %d bytes) in %.3fs\n",
+		r.Parsed, r.Messages, r.Iterations, r.Bytes, r.DurationSeconds)
+	fmt.Fprintf(out, "  throughput:   %.0f msg/s\n", r.MessagesPerSecond)
+	fmt.Fprintf(out, "  allocations:  %.1f allocs/msg, %.0f bytes/msg\n", r.AllocsPerMessage, r.BytesPerMessage)
+	fmt.Fprintf(out, "  latency:      p50 %.1fus  p99 %.1fus  max %.1fus\n", r.P50Micros, r.P99Micros, r.MaxMicros)
+	fmt.Fprintf(out, "  errors:       %d\n", r.Errors)
+}
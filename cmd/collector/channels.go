@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/spf13/cobra"
+)
+
+// Channels flags
+var channelPattern string
+
+func createChannelsCmd() *cobra.Command {
+	channelsCmd := &cobra.Command{
+		Use:   "channels",
+		Short: "Show live channel status",
+		Long: `Inquire the status of current channel instances matching a name or generic
+pattern and print their state, message and byte counts and the time of the last
+message. Channels that are not running have no status and are not listed.`,
+		RunE: runChannels,
+	}
+
+	channelsCmd.Flags().StringVarP(&channelPattern, "channel", "n", "*", "Channel name or generic pattern (e.g. APP.*)")
+	addOutputFlag(channelsCmd)
+
+	return channelsCmd
+}
+
+func runChannels(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to IBM MQ: %w", err)
+	}
+	defer client.Disconnect()
+
+	channels, err := client.InquireChannelStatus(channelPattern)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		if channels == nil {
+			channels = []*mqclient.ChannelStatus{}
+		}
+		return writeJSON(cmd.OutOrStdout(), channels)
+	}
+	if !textOutput() {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANNEL\tCONNECTION\tSTATE\tMSGS\tBYTES SENT\tBYTES RECEIVED\tLAST MESSAGE")
+	for _, ch := range channels {
+		lastMessage := ch.LastMessageTime
+		if lastMessage == "" {
+			lastMessage = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			ch.ChannelName, ch.ConnectionName, ch.Status, ch.Messages, ch.BytesSent, ch.BytesReceived, lastMessage)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/spf13/cobra"
+)
+
+// Outcome of a single capability check
+const (
+	checkPass = "PASS"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+)
+
+// checkResult is one line of the capability report
+type checkResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// capabilityReport collects the results of the test command
+type capabilityReport struct {
+	Results      []checkResult `json:"results"`
+	unauthorized bool
+}
+
+func (r *capabilityReport) add(name, status, detail string) {
+	r.Results = append(r.Results, checkResult{Name: name, Status: status, Detail: detail})
+}
+
+// addError records a failed check, calling out authorization problems explicitly
+func (r *capabilityReport) addError(name string, err error) {
+	if mqclient.IsNotAuthorized(err) {
+		r.unauthorized = true
+		r.add(name, checkFail, fmt.Sprintf("not authorized (MQRC_NOT_AUTHORIZED): %v", err))
+		return
+	}
+	r.add(name, checkFail, err.Error())
+}
+
+// count returns the number of results with the given status
+func (r *capabilityReport) count(status string) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// print writes the report and a pass/fail summary
+func (r *capabilityReport) print(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, result := range r.Results {
+		fmt.Fprintf(w, "[%s]\t%s\t%s\n", result.Status, result.Name, result.Detail)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed\n",
+		r.count(checkPass), r.count(checkWarn), r.count(checkFail))
+}
+
+// printJSON writes the report and summary counts as a JSON document
+func (r *capabilityReport) printJSON(out io.Writer) error {
+	return writeJSON(out, struct {
+		Results  []checkResult `json:"results"`
+		Passed   int           `json:"passed"`
+		Warnings int           `json:"warnings"`
+		Failed   int           `json:"failed"`
+	}{
+		Results:  r.Results,
+		Passed:   r.count(checkPass),
+		Warnings: r.count(checkWarn),
+		Failed:   r.count(checkFail),
+	})
+}
+
+// addMonitoringCheck classifies a queue manager STATQ/ACCTQ style attribute, where
+// OFF still allows queues that override it with ON and NONE disables collection entirely
+func (r *capabilityReport) addMonitoringCheck(attribute string, value int32) {
+	name := fmt.Sprintf("queue manager %s", attribute)
+	setting := mqclient.MonitoringString(value)
+
+	switch value {
+	case ibmmq.MQMON_NONE:
+		r.add(name, checkFail, fmt.Sprintf("%s(NONE) disables collection for all queues", attribute))
+	case ibmmq.MQMON_OFF:
+		r.add(name, checkWarn, fmt.Sprintf("%s(OFF) only reports queues defined with %s(ON)", attribute, attribute))
+	default:
+		r.add(name, checkPass, fmt.Sprintf("%s(%s)", attribute, setting))
+	}
+}
+
+// addSwitchCheck classifies an ON/OFF queue manager attribute such as STATMQI
+func (r *capabilityReport) addSwitchCheck(attribute string, value int32, impact string) {
+	name := fmt.Sprintf("queue manager %s", attribute)
+	if mqclient.MonitoringEnabled(value) {
+		r.add(name, checkPass, fmt.Sprintf("%s(%s)", attribute, mqclient.MonitoringString(value)))
+		return
+	}
+	r.add(name, checkWarn, fmt.Sprintf("%s(%s): %s", attribute, mqclient.MonitoringString(value), impact))
+}
+
+func runConnectionTest(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+	out := cmd.OutOrStdout()
+	report := &capabilityReport{}
+
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if outputFormat == outputJSON {
+			report.printJSON(out)
+		} else if textOutput() {
+			report.print(out)
+		}
+	}()
+
+	// Load configuration
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		report.add("configuration", checkFail, err.Error())
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		report.add("configuration", checkFail, err.Error())
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+	report.add("configuration", checkPass, "loaded and valid")
+
+	client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
+	if err := client.Connect(); err != nil {
+		report.addError("connect", err)
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	defer client.Disconnect()
+	report.add("connect", checkPass, fmt.Sprintf("%s via %s (%s)", cfg.MQ.QueueManager, cfg.MQ.Channel, client.ConnectionName()))
+
+	if settings, err := client.InquireQueueManager(); err != nil {
+		report.addError("inquire queue manager", err)
+	} else {
+		report.add("inquire queue manager", checkPass, settings.Name)
+		report.addMonitoringCheck("STATQ", settings.StatisticsQueue)
+		report.addSwitchCheck("STATMQI", settings.StatisticsMQI, "no MQI statistics will be collected")
+		report.addMonitoringCheck("ACCTQ", settings.AccountingQueue)
+		report.addSwitchCheck("ACCTMQI", settings.AccountingMQI, "no accounting records will be collected")
+		report.add("queue manager STATINT", checkPass, fmt.Sprintf("%d seconds", settings.StatisticsInterval))
+	}
+
+	access := "input"
+	if cfg.MQ.Browse {
+		access = "browse"
+	}
+	for _, q := range []struct{ name, queue string }{
+		{"statistics queue", cfg.Collector.StatsQueue},
+		{"accounting queue", cfg.Collector.AccountingQueue},
+	} {
+		name := fmt.Sprintf("%s %s", q.name, q.queue)
+		depth, err := client.CheckQueue(q.queue)
+		if err != nil {
+			report.addError(name, err)
+			continue
+		}
+		report.add(name, checkPass, fmt.Sprintf("open for %s, current depth %d", access, depth))
+	}
+
+	if err := client.PingQueueManager(); err != nil {
+		detail := fmt.Sprintf("command server unavailable, reset-stats will not work: %v", err)
+		if mqclient.IsNotAuthorized(err) {
+			detail = fmt.Sprintf("not authorized to %s, reset-stats will not work", client.CommandQueue())
+		}
+		report.add("command server", checkWarn, detail)
+	} else {
+		report.add("command server", checkPass, "ping succeeded")
+	}
+
+	if failed := report.count(checkFail); failed > 0 {
+		err := fmt.Errorf("%d capability check(s) failed", failed)
+		if report.unauthorized {
+			return withExitCode(exitAuthorizationError, err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/spf13/cobra"
+)
+
+// Events tail flags
+var (
+	eventQueues  []string
+	eventFollow  bool
+	eventConsume bool
+	eventWait    time.Duration
+)
+
+func createEventsCmd() *cobra.Command {
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Work with queue manager instrumentation events",
+	}
+
+	tailCmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Print decoded events from the event queues as they arrive",
+		Long: `Read the configured event queues (collector.event_queues, by default the
+queue manager, performance and channel event queues) and print one line per
+decoded event.
+
+Without --follow the events currently on the queues are printed and the command
+exits; with --follow it keeps waiting for new events until interrupted, which is
+useful to watch a queue manager during a change window.
+
+Events are browsed and left on the queues unless --consume is given.`,
+		RunE: runEventsTail,
+	}
+
+	tailCmd.Flags().StringSliceVarP(&eventQueues, "queue", "q", nil, "Event queue to read (repeatable, overrides collector.event_queues)")
+	tailCmd.Flags().BoolVarP(&eventFollow, "follow", "f", false, "Keep waiting for new events")
+	tailCmd.Flags().BoolVar(&eventConsume, "consume", false, "Remove events from the queues instead of browsing them")
+	tailCmd.Flags().DurationVar(&eventWait, "wait", time.Second, "How long to wait for an event on each queue per poll when following")
+	addOutputFlag(tailCmd)
+
+	eventsCmd.AddCommand(tailCmd)
+	return eventsCmd
+}
+
+func runEventsTail(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	queues := cfg.Collector.EventQueues
+	if len(eventQueues) > 0 {
+		queues = eventQueues
+	}
+	if len(queues) == 0 {
+		return configError(fmt.Errorf("no event queues configured (set collector.event_queues or use --queue)"))
+	}
+
+	client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to IBM MQ: %w", err)
+	}
+	defer client.Disconnect()
+
+	for _, queue := range queues {
+		if err := client.OpenQueue(queue, !eventConsume); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var wait time.Duration
+	if eventFollow {
+		wait = eventWait
+	}
+
+	parser := pcf.NewParser(logging.NewLogrus(logger))
+	out := cmd.OutOrStdout()
+
+	for {
+		received := 0
+		for _, queue := range queues {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			msg, err := client.GetQueueMessage(queue, wait)
+			if err != nil {
+				return err
+			}
+			if msg == nil {
+				continue
+			}
+			received++
+
+			event, err := parser.ParseEvent(msg.Data)
+			if err != nil {
+				logger.WithError(err).WithField("queue", queue).Warn("Skipping message that is not a PCF event")
+				continue
+			}
+			event.Timestamp = msg.GetTimestamp()
+
+			if err := printEvent(out, queue, event); err != nil {
+				return err
+			}
+		}
+
+		if received == 0 && !eventFollow {
+			return nil
+		}
+	}
+}
+
+// tailedEvent is the JSON form of an event printed by events tail
+type tailedEvent struct {
+	Queue string `json:"queue"`
+	*pcf.EventData
+}
+
+// printEvent writes an event as a single text line, or as one JSON object per line
+func printEvent(out io.Writer, queue string, event *pcf.EventData) error {
+	if outputFormat == outputJSON {
+		if quiet {
+			return nil
+		}
+		return json.NewEncoder(out).Encode(tailedEvent{Queue: queue, EventData: event})
+	}
+	if !textOutput() {
+		return nil
+	}
+	_, err := fmt.Fprintln(out, formatEvent(queue, event))
+	return err
+}
+
+// formatEvent renders an event as a single line: time, queue, type, reason, object and parameters
+func formatEvent(queue string, event *pcf.EventData) string {
+	fields := []string{
+		event.Timestamp.UTC().Format(time.RFC3339),
+		queue,
+		event.EventType,
+		event.ReasonName,
+	}
+	if event.ObjectName != "" {
+		fields = append(fields, event.ObjectName)
+	}
+
+	keys := make([]string, 0, len(event.Parameters))
+	for key := range event.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, event.Parameters[key]))
+	}
+
+	return strings.Join(fields, "  ")
+}
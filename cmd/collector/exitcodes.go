@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+)
+
+// Process exit codes. A clean shutdown, including one triggered by SIGINT or
+// SIGTERM, exits with exitOK.
+const (
+	exitOK                 = 0
+	exitFailure            = 1 // any error not covered below
+	exitConfigError        = 2 // configuration could not be loaded or is invalid
+	exitConnectionError    = 3 // the queue manager could not be reached
+	exitAuthorizationError = 4 // MQRC_NOT_AUTHORIZED or another authorization failure on connect or on an object
+	exitPartialCollection  = 5 // collection finished but some messages could not be processed
+)
+
+// exitCodeError attaches an exit code to an error returned from a command
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode marks err to terminate the process with the given exit code
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: code, err: err}
+}
+
+// configError marks err as a configuration error
+func configError(err error) error {
+	return withExitCode(exitConfigError, err)
+}
+
+// exitCodeFor maps an error returned by a command to the process exit code
+func exitCodeFor(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	// Authorization failures are reported as such wherever they occur
+	if mqclient.Classify(err) == mqclient.ClassAuth {
+		return exitAuthorizationError
+	}
+
+	var coded *exitCodeError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+
+	var connErr *mqclient.ConnectionError
+	if errors.As(err, &connErr) || errors.Is(err, mqclient.ErrNotConnected) {
+		return exitConnectionError
+	}
+
+	return exitFailure
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/collector"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/export"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/store"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Export flags
+var (
+	exportFormat string
+	exportOutput string
+	exportType   string
+	exportStore  string
+	exportSince  time.Duration
+)
+
+func createExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Collect once and write parsed records as JSON lines, CSV or Parquet",
+		Long: `Perform a single collection from the statistics and accounting queues and
+write the parsed records to stdout or a file, for ad-hoc analysis and piping
+into other tools. Messages are consumed from the queues as in normal collection.
+
+With --format parquet the records are written to the --output directory as
+Parquet files partitioned by record type, date and queue manager
+(record_type=.../date=.../queue_manager=.../part-*.parquet). Each run adds new
+part files, so the directory can be queried as one dataset with DuckDB or Spark.
+Use --store to export the history kept by a collector with store.enabled
+instead of draining the queues.`,
+		RunE: runExport,
+	}
+
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", export.FormatJSON, "Output format (json, csv, parquet)")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Output file, or directory for parquet (default stdout)")
+	exportCmd.Flags().StringVar(&exportType, "type", "all", "Record types to export (all, statistics, accounting)")
+	exportCmd.Flags().StringVar(&exportStore, "store", "", "History store database to export instead of draining the queues")
+	exportCmd.Flags().DurationVar(&exportSince, "since", 0, "With --store, only export records from this far back (0 = all)")
+
+	return exportCmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	switch exportType {
+	case "all", "statistics", "accounting":
+	default:
+		return fmt.Errorf("invalid record type: %s (use all, statistics or accounting)", exportType)
+	}
+
+	if exportFormat == export.FormatParquet && exportOutput == "" {
+		return fmt.Errorf("parquet export requires --output DIR")
+	}
+
+	var records *collector.Records
+	if exportStore != "" {
+		var err error
+		if records, err = readExportStore(exportStore, exportSince, logger); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if records, err = collectExportRecords(logger); err != nil {
+			return err
+		}
+	}
+
+	var writer export.Writer
+	if exportFormat == export.FormatParquet {
+		writer = export.NewParquetWriter(exportOutput)
+	} else {
+		var out io.Writer = os.Stdout
+		if exportOutput != "" {
+			file, err := os.Create(exportOutput)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		var err error
+		if writer, err = export.NewWriter(exportFormat, out); err != nil {
+			return err
+		}
+	}
+
+	if exportType != "accounting" {
+		for _, stats := range records.Statistics {
+			if err := writer.WriteStatistics(stats); err != nil {
+				return fmt.Errorf("failed to write statistics record: %w", err)
+			}
+		}
+	}
+
+	if exportType != "statistics" {
+		for _, acct := range records.Accounting {
+			if err := writer.WriteAccounting(acct); err != nil {
+				return fmt.Errorf("failed to write accounting record: %w", err)
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to flush output: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"statistics_records": len(records.Statistics),
+		"accounting_records": len(records.Accounting),
+		"parse_errors":       records.ParseErrors,
+		"format":             exportFormat,
+	}).Info("Export completed")
+
+	if records.ParseErrors > 0 {
+		return withExitCode(exitPartialCollection, fmt.Errorf("%d message(s) could not be parsed", records.ParseErrors))
+	}
+	return nil
+}
+
+// collectExportRecords drains the statistics and accounting queues once
+func collectExportRecords(logger *logrus.Logger) (*collector.Records, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	// Exporting never needs the metrics HTTP server
+	cfg.Prometheus.EnableOTel = false
+	cfg.API.Enabled = false
+	cfg.WebSocket.Enabled = false
+
+	col, err := collector.NewCollector(cfg, logging.NewLogrus(logger))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collector: %w", err)
+	}
+	defer col.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	records, err := col.CollectRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("collection failed: %w", err)
+	}
+	return records, nil
+}
+
+// readExportStore reads the records kept in a history store, oldest first
+func readExportStore(path string, since time.Duration, logger *logrus.Logger) (*collector.Records, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("history store not found: %w", err)
+	}
+
+	history, err := store.Open(&config.StoreConfig{Path: path}, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer history.Close()
+
+	query := store.Query{}
+	if since > 0 {
+		query.Since = time.Now().Add(-since)
+	}
+
+	records := &collector.Records{}
+	ctx := context.Background()
+	if exportType != "accounting" {
+		if records.Statistics, err = history.Statistics(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	if exportType != "statistics" {
+		if records.Accounting, err = history.Accounting(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
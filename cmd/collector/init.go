@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/redact"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Init flags
+var (
+	initOutput   string
+	initForce    bool
+	initSkipTest bool
+)
+
+func createInitCmd() *cobra.Command {
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Interactively create a configuration file",
+		Long: `Prompt for the queue manager connection, credentials and exporter settings,
+test the connection and write a validated configuration file.
+
+Press Enter to accept the default shown in brackets. Leave the password empty to
+supply it at runtime through the IBMMQ_PASSWORD environment variable instead of
+storing it in the file.`,
+		RunE: runInit,
+	}
+
+	initCmd.Flags().StringVarP(&initOutput, "output", "o", "config.yaml", "Configuration file to write")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing file without asking")
+	initCmd.Flags().BoolVar(&initSkipTest, "skip-test", false, "Do not test the connection before writing")
+
+	return initCmd
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	testConnection := func(cfg *config.Config) error {
+		client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
+		if err := client.Connect(); err != nil {
+			return err
+		}
+		defer client.Disconnect()
+
+		_, err := client.InquireQueueManager()
+		return err
+	}
+	if initSkipTest {
+		testConnection = nil
+	}
+
+	return runInitWizard(cmd.InOrStdin(), cmd.OutOrStdout(), initOutput, initForce, testConnection)
+}
+
+// errInitAborted is returned when the user declines to write the configuration
+var errInitAborted = errors.New("configuration not written")
+
+// runInitWizard prompts for the configuration, optionally tests it and writes it to path
+func runInitWizard(in io.Reader, out io.Writer, path string, force bool, testConnection func(cfg *config.Config) error) error {
+	p := &prompter{in: bufio.NewReader(in), out: out}
+
+	if _, err := os.Stat(path); err == nil && !force {
+		overwrite, err := confirm(p.in, out, fmt.Sprintf("%s already exists. Overwrite?", path))
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			return errInitAborted
+		}
+	}
+
+	cfg := config.DefaultConfig()
+
+	fmt.Fprintln(out, "Queue manager connection")
+	cfg.MQ.QueueManager = p.ask("Queue manager name", "QM1")
+	cfg.MQ.Host = p.ask("Host", "localhost")
+	cfg.MQ.Port = p.askInt("Listener port", 1414)
+	cfg.MQ.Channel = p.ask("Server-connection channel", "DEV.APP.SVRCONN")
+	cfg.MQ.User = p.ask("User (empty for none)", "")
+	if cfg.MQ.User != "" {
+		cfg.MQ.Password = p.ask("Password (empty to use IBMMQ_PASSWORD)", "")
+		redact.Register(cfg.MQ.Password)
+	}
+	cfg.MQ.ConnectionName = cfg.MQ.GetConnectionName()
+
+	fmt.Fprintln(out, "\nCollector")
+	cfg.Collector.StatsQueue = p.ask("Statistics queue", "SYSTEM.ADMIN.STATISTICS.QUEUE")
+	cfg.Collector.AccountingQueue = p.ask("Accounting queue", "SYSTEM.ADMIN.ACCOUNTING.QUEUE")
+	cfg.Collector.Interval = p.askDuration("Collection interval", 60*time.Second)
+
+	fmt.Fprintln(out, "\nExporter")
+	cfg.Prometheus.Port = p.askInt("Metrics port", cfg.Prometheus.Port)
+	cfg.Prometheus.Namespace = p.ask("Metric namespace", cfg.Prometheus.Namespace)
+
+	if p.err != nil {
+		return p.err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	if testConnection != nil {
+		fmt.Fprintf(out, "\nTesting connection to %s via %s (%s)...\n", cfg.MQ.QueueManager, cfg.MQ.Channel, cfg.MQ.ConnectionName)
+		if err := testConnection(cfg); err != nil {
+			fmt.Fprintf(out, "Connection test failed: %v\n", err)
+			write, confirmErr := confirm(p.in, out, "Write the configuration anyway?")
+			if confirmErr != nil {
+				return confirmErr
+			}
+			if !write {
+				return errInitAborted
+			}
+		} else {
+			fmt.Fprintln(out, "Connection test succeeded")
+		}
+	}
+
+	if err := writeConfigFile(path, cfg); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nWrote %s\n", path)
+	if cfg.MQ.Password != "" {
+		fmt.Fprintln(out, "The file contains the password and is readable by the owner only; consider IBMMQ_PASSWORD instead.")
+	}
+	fmt.Fprintf(out, "Next: ibmmq-collector test -c %s\n", path)
+	return nil
+}
+
+// writeConfigFile writes cfg as YAML, readable by the owner only since it may hold credentials
+func writeConfigFile(path string, cfg *config.Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+
+	header := "# IBM MQ Statistics Collector configuration, generated by ibmmq-collector init\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0600); err != nil {
+		return fmt.Errorf("failed to write configuration: %w", err)
+	}
+	return nil
+}
+
+// prompter asks questions on out and reads the answers line by line from in.
+// The first read error is kept in err and makes later questions return their defaults.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	err error
+}
+
+// ask prompts for a string, returning def for an empty answer
+func (p *prompter) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "  %s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "  %s: ", question)
+	}
+	if p.err != nil {
+		return def
+	}
+
+	answer, err := p.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		p.err = fmt.Errorf("failed to read answer: %w", err)
+		return def
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+// askInt prompts for an integer, asking again until the answer is valid
+func (p *prompter) askInt(question string, def int) int {
+	for {
+		answer := p.ask(question, strconv.Itoa(def))
+		value, err := strconv.Atoi(answer)
+		if err == nil {
+			return value
+		}
+		if p.err != nil {
+			return def
+		}
+		fmt.Fprintf(p.out, "  %q is not a number\n", answer)
+	}
+}
+
+// askDuration prompts for a duration such as 60s or 5m, asking again until the answer is valid
+func (p *prompter) askDuration(question string, def time.Duration) time.Duration {
+	for {
+		answer := p.ask(question, def.String())
+		value, err := time.ParseDuration(answer)
+		if err == nil {
+			return value
+		}
+		if p.err != nil {
+			return def
+		}
+		fmt.Fprintf(p.out, "  %q is not a duration (e.g. 60s, 5m)\n", answer)
+	}
+}
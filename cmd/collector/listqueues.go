@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/spf13/cobra"
+)
+
+// List-queues flags
+var listQueuePattern string
+
+func createListQueuesCmd() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list-queues",
+		Short: "List local queues with their depth and open handle counts",
+		Long: `Inquire the local queues matching a name or generic pattern through the command
+server and print their current and maximum depth and open input/output counts.`,
+		RunE: runListQueues,
+	}
+
+	listCmd.Flags().StringVarP(&listQueuePattern, "queue", "q", "*", "Queue name or generic pattern (e.g. APP.*)")
+	addOutputFlag(listCmd)
+
+	return listCmd
+}
+
+func runListQueues(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to IBM MQ: %w", err)
+	}
+	defer client.Disconnect()
+
+	queues, err := client.InquireQueues(listQueuePattern)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		if queues == nil {
+			queues = []*mqclient.QueueInfo{}
+		}
+		return writeJSON(cmd.OutOrStdout(), queues)
+	}
+	if !textOutput() {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUEUE\tDEPTH\tMAX DEPTH\tINPUT\tOUTPUT")
+	for _, q := range queues {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", q.QueueName, q.CurrentDepth, q.MaxDepth, q.OpenInputCount, q.OpenOutputCount)
+	}
+	return w.Flush()
+}
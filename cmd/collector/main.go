@@ -1,17 +1,17 @@
 package main
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"runtime"
 	"time"
 
-	"github.com/atulksin/ibmmq-go-stat-otel/pkg/collector"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/redact"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -53,6 +53,7 @@ data as Prometheus metrics with the 'ibmmq' prefix.`,
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
 	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "json", "Log format (json, text)")
+	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Suppress log and result output; rely on the exit status")
 
 	// Collection flags
 	rootCmd.Flags().BoolVar(&continuous, "continuous", false, "Run continuous monitoring")
@@ -63,89 +64,49 @@ data as Prometheus metrics with the 'ibmmq' prefix.`,
 	// Prometheus flags
 	rootCmd.Flags().IntVar(&prometheusPort, "prometheus-port", 9090, "Prometheus metrics HTTP server port")
 	rootCmd.Flags().BoolVar(&otelEnabled, "otel", true, "Enable OpenTelemetry integration")
+	rootCmd.Flags().MarkDeprecated("continuous", "use the serve subcommand instead")
 
 	// Add subcommands
+	rootCmd.AddCommand(createServeCmd())
+	rootCmd.AddCommand(createCollectCmd())
 	rootCmd.AddCommand(createVersionCmd())
 	rootCmd.AddCommand(createTestCmd())
 	rootCmd.AddCommand(createConfigCmd())
+	rootCmd.AddCommand(createSimulateCmd())
+	rootCmd.AddCommand(createExportCmd())
+	rootCmd.AddCommand(createResetStatsCmd())
+	rootCmd.AddCommand(createTopCmd())
+	rootCmd.AddCommand(createListQueuesCmd())
+	rootCmd.AddCommand(createChannelsCmd())
+	rootCmd.AddCommand(createQmgrCmd())
+	rootCmd.AddCommand(createEventsCmd())
+	rootCmd.AddCommand(createAccountingCmd())
+	rootCmd.AddCommand(createBenchCmd())
+	rootCmd.AddCommand(createVerifyMetricsCmd())
+	rootCmd.AddCommand(createInitCmd())
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %s\n", redact.String(err.Error()))
+		os.Exit(exitCodeFor(err))
 	}
 }
 
+// runCollector keeps the original root command behaviour, where --continuous selects
+// between the serve and collect modes
 func runCollector(cmd *cobra.Command, args []string) error {
-	// Setup logging
 	logger := setupLogger()
 
-	logger.WithFields(logrus.Fields{
-		"version": version,
-		"commit":  commit,
-		"date":    date,
-	}).Info("Starting IBM MQ Statistics Collector")
-
-	// Load configuration
-	cfg, err := config.LoadConfig(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
-	}
-
-	// Override config with command line flags
-	overrideConfigWithFlags(cfg)
-
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("configuration validation failed: %w", err)
-	}
-
-	logger.WithField("config", cfg.String()).Info("Configuration loaded successfully")
-
-	// Create collector
-	col, err := collector.NewCollector(cfg, logger)
+	cfg, err := loadCollectorConfig()
 	if err != nil {
-		return fmt.Errorf("failed to create collector: %w", err)
-	}
-
-	// Setup context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Handle graceful shutdown
-	go func() {
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-		sig := <-sigChan
-
-		logger.WithField("signal", sig).Info("Received shutdown signal")
-		cancel()
-	}()
-
-	// Start collector
-	logger.Info("Starting collector...")
-	if err := col.Start(ctx); err != nil {
-		if err == context.Canceled {
-			logger.Info("Collector stopped by user")
-			return nil
-		}
-		return fmt.Errorf("collector failed: %w", err)
-	}
-
-	// Stop collector
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
-
-	if err := col.Stop(shutdownCtx); err != nil {
-		logger.WithError(err).Error("Error during collector shutdown")
 		return err
 	}
 
-	logger.Info("IBM MQ Statistics Collector stopped successfully")
-	return nil
+	return runCollection(cfg, nil, logger, nil)
 }
 
 func setupLogger() *logrus.Logger {
 	logger := logrus.New()
+	logger.AddHook(redact.NewHook())
 
 	// Set log level
 	level, err := logrus.ParseLevel(logLevel)
@@ -155,6 +116,9 @@ func setupLogger() *logrus.Logger {
 	if verbose {
 		level = logrus.DebugLevel
 	}
+	if quiet {
+		level = logrus.ErrorLevel
+	}
 	logger.SetLevel(level)
 
 	// Set log format
@@ -206,27 +170,70 @@ func overrideConfigWithFlags(cfg *config.Config) {
 	}
 }
 
+// versionInfo is the machine-readable form of the version command output
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+func currentVersionInfo() versionInfo {
+	return versionInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
 func createVersionCmd() *cobra.Command {
-	return &cobra.Command{
+	var jsonOutput bool
+
+	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("IBM MQ Statistics Collector\n")
-			fmt.Printf("Version: %s\n", version)
-			fmt.Printf("Commit: %s\n", commit)
-			fmt.Printf("Built: %s\n", date)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := currentVersionInfo()
+			out := cmd.OutOrStdout()
+
+			if jsonOutput {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(info)
+			}
+
+			fmt.Fprintf(out, "IBM MQ Statistics Collector\n")
+			fmt.Fprintf(out, "Version: %s\n", info.Version)
+			fmt.Fprintf(out, "Commit: %s\n", info.Commit)
+			fmt.Fprintf(out, "Built: %s\n", info.Date)
+			fmt.Fprintf(out, "Go: %s (%s)\n", info.GoVersion, info.Platform)
+			return nil
 		},
 	}
+
+	versionCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print version information as JSON")
+
+	return versionCmd
 }
 
 func createTestCmd() *cobra.Command {
 	testCmd := &cobra.Command{
 		Use:   "test",
 		Short: "Test IBM MQ connection and configuration",
-		RunE:  runConnectionTest,
+		Long: `Connect to the queue manager and report, check by check, whether the collector
+can do its job: the statistics and accounting queues can be opened, the queue
+manager STATQ/STATMQI/ACCTQ/ACCTMQI settings produce data, and the command
+server answers. Authorization failures are reported per object.
+
+Exits with an error if any check fails; warnings do not fail the command.`,
+		RunE: runConnectionTest,
 	}
 
 	testCmd.Flags().StringVarP(&configFile, "config", "c", "", "Configuration file path")
+	addOutputFlag(testCmd)
 
 	return testCmd
 }
@@ -249,52 +256,16 @@ func createConfigCmd() *cobra.Command {
 		RunE:  validateConfig,
 	}
 
-	configCmd.AddCommand(generateCmd, validateCmd)
-	return configCmd
-}
-
-func runConnectionTest(cmd *cobra.Command, args []string) error {
-	logger := setupLogger()
-
-	logger.Info("Testing IBM MQ connection")
-
-	// Load configuration
-	cfg, err := config.LoadConfig(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration with credentials masked",
+		RunE:  showConfig,
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("configuration validation failed: %w", err)
-	}
-
-	// Create collector (this will test the connection)
-	col, err := collector.NewCollector(cfg, logger)
-	if err != nil {
-		return fmt.Errorf("failed to create collector: %w", err)
-	}
-
-	// Test connection by starting and immediately stopping
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	// Create a test collector that just connects and disconnects
-	logger.Info("Attempting connection to IBM MQ...")
-
-	// This is a simplified test - in practice you might want to create a separate test method
-	go func() {
-		time.Sleep(2 * time.Second) // Give it time to connect
-		cancel()                    // Cancel to stop the test
-	}()
-
-	err = col.Start(ctx)
-	if err != nil && err != context.Canceled {
-		return fmt.Errorf("connection test failed: %w", err)
-	}
+	addOutputFlag(validateCmd)
 
-	logger.Info("IBM MQ connection test completed successfully")
-	return nil
+	configCmd.AddCommand(generateCmd, validateCmd, showCmd)
+	return configCmd
 }
 
 func generateConfig(cmd *cobra.Command, args []string) error {
@@ -331,30 +302,86 @@ func generateConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// showConfig prints the configuration loaded from the file and environment as YAML.
+// Passwords, tokens and other credentials are masked, so the output can be shared.
+func showConfig(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	data, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	_, err = cmd.OutOrStdout().Write(data)
+	return err
+}
+
+// configValidation is the machine-readable result of config validate
+type configValidation struct {
+	ConfigFile     string `json:"config_file"`
+	Valid          bool   `json:"valid"`
+	Error          string `json:"error,omitempty"`
+	QueueManager   string `json:"queue_manager,omitempty"`
+	Channel        string `json:"channel,omitempty"`
+	ConnectionName string `json:"connection_name,omitempty"`
+	PrometheusPort int    `json:"prometheus_port,omitempty"`
+}
+
 func validateConfig(cmd *cobra.Command, args []string) error {
 	logger := setupLogger()
 
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
 	if configFile == "" {
 		return fmt.Errorf("configuration file path is required")
 	}
 
 	logger.WithField("config_file", configFile).Info("Validating configuration")
 
+	result := configValidation{ConfigFile: configFile}
+
 	cfg, err := config.LoadConfig(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+	if err == nil {
+		err = cfg.Validate()
+		if err != nil {
+			err = configError(fmt.Errorf("configuration validation failed: %w", err))
+		}
+	} else {
+		err = configError(fmt.Errorf("failed to load configuration: %w", err))
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("configuration validation failed: %w", err)
+	if err != nil {
+		result.Error = err.Error()
+		if outputFormat == outputJSON {
+			writeJSON(cmd.OutOrStdout(), result)
+		}
+		return err
 	}
 
+	result.Valid = true
+	result.QueueManager = cfg.MQ.QueueManager
+	result.Channel = cfg.MQ.Channel
+	result.ConnectionName = cfg.MQ.ConnectionName
+	result.PrometheusPort = cfg.Prometheus.Port
+
 	logger.Info("Configuration is valid")
-	fmt.Printf("✓ Configuration file '%s' is valid\n", configFile)
-	fmt.Printf("✓ Queue Manager: %s\n", cfg.MQ.QueueManager)
-	fmt.Printf("✓ Channel: %s\n", cfg.MQ.Channel)
-	fmt.Printf("✓ Connection: %s\n", cfg.MQ.ConnectionName)
-	fmt.Printf("✓ Prometheus Port: %d\n", cfg.Prometheus.Port)
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), result)
+	}
+
+	if textOutput() {
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "✓ Configuration file '%s' is valid\n", configFile)
+		fmt.Fprintf(out, "✓ Queue Manager: %s\n", cfg.MQ.QueueManager)
+		fmt.Fprintf(out, "✓ Channel: %s\n", cfg.MQ.Channel)
+		fmt.Fprintf(out, "✓ Connection: %s\n", cfg.MQ.ConnectionName)
+		fmt.Fprintf(out, "✓ Prometheus Port: %d\n", cfg.Prometheus.Port)
+	}
 
 	return nil
 }
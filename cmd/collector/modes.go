@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/collector"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Collect flags
+var (
+	collectOTel     bool
+	collectTextfile string
+)
+
+func createServeCmd() *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the long-lived exporter, collecting on an interval and serving metrics",
+		Long: `Continuously collect statistics and accounting data on the configured interval
+and serve the resulting metrics over HTTP until interrupted (or until --max-cycles
+collections have run).
+
+This is the mode to use for deployments scraped by Prometheus.`,
+		RunE: runServe,
+	}
+
+	serveCmd.Flags().DurationVar(&interval, "interval", 60*time.Second, "Collection interval")
+	serveCmd.Flags().IntVar(&maxCycles, "max-cycles", 0, "Maximum number of collection cycles (0 = infinite)")
+	serveCmd.Flags().BoolVar(&resetStats, "reset-stats", false, "Reset statistics after reading")
+	serveCmd.Flags().IntVar(&prometheusPort, "prometheus-port", 9090, "Prometheus metrics HTTP server port")
+	serveCmd.Flags().BoolVar(&otelEnabled, "otel", true, "Enable OpenTelemetry integration and the metrics HTTP server")
+
+	return serveCmd
+}
+
+func createCollectCmd() *cobra.Command {
+	collectCmd := &cobra.Command{
+		Use:   "collect",
+		Short: "Run a single collection and exit",
+		Long: `Drain the statistics and accounting queues once, update the metrics and exit.
+
+No HTTP server is started by default. Use --textfile to write the collected
+metrics in Prometheus text format, e.g. for the node_exporter textfile
+collector when running from cron.`,
+		RunE: runCollect,
+	}
+
+	collectCmd.Flags().BoolVar(&resetStats, "reset-stats", false, "Reset statistics after reading")
+	collectCmd.Flags().BoolVar(&collectOTel, "otel", false, "Enable OpenTelemetry integration for this run")
+	collectCmd.Flags().StringVar(&collectTextfile, "textfile", "", "Write the collected metrics to this file in Prometheus text format")
+
+	return collectCmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	cfg, err := loadCollectorConfig()
+	if err != nil {
+		return err
+	}
+	cfg.Collector.Continuous = true
+
+	return runCollection(cfg, nil, logger, nil)
+}
+
+func runCollect(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	cfg, err := loadCollectorConfig()
+	if err != nil {
+		return err
+	}
+	cfg.Collector.Continuous = false
+	cfg.Prometheus.EnableOTel = collectOTel
+	if !collectOTel {
+		// A single cycle without the metrics HTTP server has nothing to serve the API or /ws on
+		cfg.API.Enabled = false
+		cfg.WebSocket.Enabled = false
+	}
+
+	return runCollection(cfg, nil, logger, func(col *collector.Collector) error {
+		if collectTextfile == "" {
+			return nil
+		}
+		if err := col.WriteMetricsTextfile(collectTextfile); err != nil {
+			return fmt.Errorf("failed to write metrics textfile: %w", err)
+		}
+		logger.WithField("path", collectTextfile).Info("Wrote metrics textfile")
+		return nil
+	})
+}
+
+// loadCollectorConfig loads and validates the configuration, applying command line overrides
+func loadCollectorConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	// Override config with command line flags
+	overrideConfigWithFlags(cfg)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	return cfg, nil
+}
+
+// applyResources sets the Go runtime limits configured for the collector
+func applyResources(resources *config.ResourcesConfig, logger *logrus.Logger) {
+	if resources.MemoryLimitMB > 0 {
+		limit := int64(resources.MemoryLimitMB) << 20
+		previous := debug.SetMemoryLimit(limit)
+		logger.WithFields(logrus.Fields{
+			"limit_bytes":    limit,
+			"previous_bytes": previous,
+		}).Info("Set Go memory limit")
+	}
+
+	if resources.MaxProcs > 0 {
+		previous := runtime.GOMAXPROCS(resources.MaxProcs)
+		logger.WithFields(logrus.Fields{
+			"max_procs": resources.MaxProcs,
+			"previous":  previous,
+		}).Info("Set GOMAXPROCS")
+	}
+}
+
+// runCollection runs the collector in the mode selected by cfg until it completes or is
+// interrupted. It gets its messages through client, or from the configured queue manager
+// when client is nil. onComplete, if set, is called after a run that finished without error.
+func runCollection(cfg *config.Config, client mqclient.MQClientAPI, logger *logrus.Logger, onComplete func(col *collector.Collector) error) (err error) {
+	logger.WithFields(logrus.Fields{
+		"version":    version,
+		"commit":     commit,
+		"date":       date,
+		"continuous": cfg.Collector.Continuous,
+	}).Info("Starting IBM MQ Statistics Collector")
+
+	logger.WithField("config", cfg.String()).Info("Configuration loaded successfully")
+
+	// Before the collector sizes its parsing workers by GOMAXPROCS
+	applyResources(&cfg.Resources, logger)
+
+	// Create collector
+	var col *collector.Collector
+	if client != nil {
+		col, err = collector.NewCollectorWithClient(cfg, client, logging.NewLogrus(logger))
+	} else {
+		col, err = collector.NewCollector(cfg, logging.NewLogrus(logger))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create collector: %w", err)
+	}
+	col.SetBuildInfo(version, commit, date)
+
+	// Stop the collector however the run ends, including a shutdown signal, so that its
+	// sinks are flushed and closed and the connection to IBM MQ is ended
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer shutdownCancel()
+
+		if stopErr := col.Stop(shutdownCtx); stopErr != nil {
+			logger.WithError(stopErr).Error("Error during collector shutdown")
+			if err == nil {
+				err = stopErr
+			}
+			return
+		}
+		logger.Info("IBM MQ Statistics Collector stopped successfully")
+	}()
+
+	// Setup context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle graceful shutdown
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		sig := <-sigChan
+
+		logger.WithField("signal", sig).Info("Received shutdown signal")
+		cancel()
+	}()
+
+	// Start collector
+	logger.Info("Starting collector...")
+	if err := col.Start(ctx); err != nil {
+		if err == context.Canceled {
+			logger.Info("Collector stopped by user")
+			return nil
+		}
+		return fmt.Errorf("collector failed: %w", err)
+	}
+
+	if onComplete != nil {
+		if err := onComplete(col); err != nil {
+			return err
+		}
+	}
+
+	// A run that completed despite failed cycles or unparseable messages is partial
+	if col.ErrorCount() > 0 || col.ParseErrors() > 0 {
+		return withExitCode(exitPartialCollection, fmt.Errorf("collection incomplete: %d failed cycle(s), %d unparseable message(s)",
+			col.ErrorCount(), col.ParseErrors()))
+	}
+	return nil
+}
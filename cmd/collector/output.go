@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats for commands that print results
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Output flags shared by reporting commands
+var (
+	quiet        bool
+	outputFormat string
+)
+
+// addOutputFlag registers --output on a command that can print machine-readable results
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outputFormat, "output", outputText, "Output format (text, json)")
+}
+
+// checkOutputFormat validates the --output flag value
+func checkOutputFormat() error {
+	switch outputFormat {
+	case outputText, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format: %s (use text or json)", outputFormat)
+	}
+}
+
+// textOutput reports whether human-readable output should be printed
+func textOutput() bool {
+	return !quiet && outputFormat != outputJSON
+}
+
+// writeJSON prints v as indented JSON unless --quiet is set
+func writeJSON(out io.Writer, v interface{}) error {
+	if quiet {
+		return nil
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
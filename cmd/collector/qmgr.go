@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/spf13/cobra"
+)
+
+func createQmgrCmd() *cobra.Command {
+	qmgrCmd := &cobra.Command{
+		Use:   "qmgr",
+		Short: "Show queue manager attributes relevant to statistics and accounting",
+		Long: `Print the queue manager attributes that decide what this collector can see:
+STATQ, STATMQI, STATCHL, STATINT, ACCTQ, ACCTMQI, ACCTINT, the command level and
+the dead letter queue, with a note on each setting that limits collection.`,
+		RunE: runQmgr,
+	}
+
+	addOutputFlag(qmgrCmd)
+
+	return qmgrCmd
+}
+
+func runQmgr(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to IBM MQ: %w", err)
+	}
+	defer client.Disconnect()
+
+	settings, err := client.InquireQueueManager()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), qmgrAttributes(settings))
+	}
+	if textOutput() {
+		printQmgrSettings(cmd.OutOrStdout(), settings)
+	}
+	return nil
+}
+
+// qmgrAttribute is one row of the qmgr command output
+type qmgrAttribute struct {
+	Name  string `json:"attribute"`
+	Value string `json:"value"`
+	Note  string `json:"note,omitempty"`
+}
+
+// qmgrAttributes converts queue manager settings to MQSC-style rows with diagnostic notes
+func qmgrAttributes(s *mqclient.QueueManagerSettings) []qmgrAttribute {
+	monitoringNote := func(value int32, attribute, data string) string {
+		switch value {
+		case ibmmq.MQMON_NONE:
+			return fmt.Sprintf("no %s for any queue", data)
+		case ibmmq.MQMON_OFF:
+			return fmt.Sprintf("%s only for queues with %s(ON)", data, attribute)
+		}
+		return ""
+	}
+	switchNote := func(value int32, data string) string {
+		if !mqclient.MonitoringEnabled(value) {
+			return fmt.Sprintf("no %s", data)
+		}
+		return ""
+	}
+
+	dlq := s.DeadLetterQueue
+	dlqNote := ""
+	if dlq == "" {
+		dlq = "-"
+		dlqNote = "no dead letter queue defined"
+	}
+
+	return []qmgrAttribute{
+		{"QMNAME", s.Name, ""},
+		{"CMDLEVEL", fmt.Sprintf("%d", s.CommandLevel), ""},
+		{"STATQ", mqclient.MonitoringString(s.StatisticsQueue), monitoringNote(s.StatisticsQueue, "STATQ", "queue statistics")},
+		{"STATMQI", mqclient.MonitoringString(s.StatisticsMQI), switchNote(s.StatisticsMQI, "MQI statistics")},
+		{"STATCHL", mqclient.MonitoringString(s.StatisticsChannel), monitoringNote(s.StatisticsChannel, "STATCHL", "channel statistics")},
+		{"STATINT", fmt.Sprintf("%d", s.StatisticsInterval), "seconds between statistics messages"},
+		{"ACCTQ", mqclient.MonitoringString(s.AccountingQueue), monitoringNote(s.AccountingQueue, "ACCTQ", "queue accounting")},
+		{"ACCTMQI", mqclient.MonitoringString(s.AccountingMQI), switchNote(s.AccountingMQI, "MQI accounting")},
+		{"ACCTINT", fmt.Sprintf("%d", s.AccountingInterval), "seconds between accounting messages"},
+		{"DEADQ", dlq, dlqNote},
+	}
+}
+
+// printQmgrSettings writes the queue manager attributes as a table
+func printQmgrSettings(out io.Writer, s *mqclient.QueueManagerSettings) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ATTRIBUTE\tVALUE\tNOTE")
+	for _, attr := range qmgrAttributes(s) {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", attr.Name, attr.Value, attr.Note)
+	}
+	w.Flush()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/spf13/cobra"
+)
+
+// Reset-stats flags
+var (
+	resetQueuePattern string
+	resetConfirm      bool
+)
+
+func createResetStatsCmd() *cobra.Command {
+	resetCmd := &cobra.Command{
+		Use:   "reset-stats",
+		Short: "Reset queue statistics (RESET QSTATS) and print the values before reset",
+		Long: `Issue Reset Queue Statistics for every queue matching the given name or generic
+pattern, printing the high depth, enqueue and dequeue counts that were reset.
+
+The command asks for confirmation unless --yes is given.`,
+		RunE: runResetStats,
+	}
+
+	resetCmd.Flags().StringVarP(&resetQueuePattern, "queue", "q", "", "Queue name or generic pattern (e.g. APP.*)")
+	resetCmd.Flags().BoolVarP(&resetConfirm, "yes", "y", false, "Do not ask for confirmation")
+	resetCmd.MarkFlagRequired("queue")
+	addOutputFlag(resetCmd)
+
+	return resetCmd
+}
+
+func runResetStats(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	if !resetConfirm && !textOutput() {
+		return fmt.Errorf("--yes is required with --quiet or --output json")
+	}
+
+	if !resetConfirm {
+		confirmed, err := confirm(cmd.InOrStdin(), cmd.OutOrStdout(),
+			fmt.Sprintf("Reset statistics for queues matching '%s' on %s?", resetQueuePattern, cfg.MQ.QueueManager))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(cmd.OutOrStdout(), "Aborted")
+			return nil
+		}
+	}
+
+	client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to IBM MQ: %w", err)
+	}
+	defer client.Disconnect()
+
+	// The queues reset before a failure are still reported, as their statistics are gone
+	results, resetErr := client.ResetQueueStatistics(resetQueuePattern)
+	if resetErr != nil && len(results) == 0 {
+		return resetErr
+	}
+
+	if outputFormat == outputJSON {
+		if err := writeJSON(cmd.OutOrStdout(), results); err != nil {
+			return err
+		}
+		return resetErr
+	}
+	if !textOutput() {
+		return resetErr
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUEUE\tHIGH DEPTH\tENQUEUED\tDEQUEUED\tSECONDS SINCE RESET")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", r.QueueName, r.HighDepth, r.EnqueueCount, r.DequeueCount, r.TimeSinceReset)
+	}
+	w.Flush()
+
+	fmt.Fprintf(cmd.OutOrStdout(), "\nReset statistics for %d queue(s)\n", len(results))
+	return resetErr
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
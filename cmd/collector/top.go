@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/collector"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/spf13/cobra"
+)
+
+// Top flags
+var (
+	topRefresh time.Duration
+	topSort    string
+	topLimit   int
+)
+
+// Sort keys accepted by the top view, and the single-key shortcuts that select them
+var topSortKeys = map[string]string{
+	"n": "name",
+	"d": "depth",
+	"h": "high",
+	"e": "enq",
+	"x": "deq",
+}
+
+const clearScreen = "\033[H\033[2J"
+
+func createTopCmd() *cobra.Command {
+	topCmd := &cobra.Command{
+		Use:   "top",
+		Short: "Live terminal view of queue depth, rates and reader/writer status",
+		Long: `Continuously collect statistics and show a refreshing per-queue table with the
+current and high depth, enqueue/dequeue rates and whether the queue has readers
+and writers.
+
+While running, type a key followed by Enter to change the sort order:
+  n name   d depth   h high depth   e enqueue rate   x dequeue rate
+  r reverse the order   q quit
+
+Rates are computed from the enqueue and dequeue counts in the statistics
+messages received since the previous refresh. Queues keep their last reported
+values until the queue manager writes new statistics for them.`,
+		RunE: runTop,
+	}
+
+	topCmd.Flags().DurationVar(&topRefresh, "refresh", 5*time.Second, "Refresh interval")
+	topCmd.Flags().StringVar(&topSort, "sort", "depth", "Initial sort column (name, depth, high, enq, deq)")
+	topCmd.Flags().IntVar(&topLimit, "limit", 0, "Maximum number of queues to show (0 = all)")
+
+	return topCmd
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	view := newTopView()
+	if err := view.setSort(topSort); err != nil {
+		return err
+	}
+	view.limit = topLimit
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return configError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return configError(fmt.Errorf("configuration validation failed: %w", err))
+	}
+
+	// The view replaces the metrics endpoint, and log lines would corrupt the screen
+	cfg.Prometheus.EnableOTel = false
+	cfg.API.Enabled = false
+	cfg.WebSocket.Enabled = false
+	if !verbose {
+		logger.SetOutput(io.Discard)
+	}
+	view.queueManager = cfg.MQ.QueueManager
+
+	col, err := collector.NewCollector(cfg, logging.NewLogrus(logger))
+	if err != nil {
+		return fmt.Errorf("failed to create collector: %w", err)
+	}
+	defer col.Disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	keys := make(chan string)
+	go readTopKeys(cmd.InOrStdin(), keys)
+
+	out := cmd.OutOrStdout()
+	ticker := time.NewTicker(topRefresh)
+	defer ticker.Stop()
+
+	last := time.Now()
+	refresh := func() error {
+		records, err := col.CollectRecords(ctx)
+		if err != nil {
+			return fmt.Errorf("collection failed: %w", err)
+		}
+		now := time.Now()
+		view.update(records.Statistics, now.Sub(last), now)
+		last = now
+		return nil
+	}
+
+	if err := refresh(); err != nil {
+		return err
+	}
+	view.render(out, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok {
+				// Input closed (e.g. not a terminal); keep refreshing
+				keys = nil
+				continue
+			}
+			if view.handleKey(key) {
+				return nil
+			}
+			view.render(out, time.Now())
+		case <-ticker.C:
+			if err := refresh(); err != nil {
+				view.status = err.Error()
+			} else {
+				view.status = ""
+			}
+			view.render(out, time.Now())
+		}
+	}
+}
+
+// readTopKeys forwards the first character of every input line as a key press
+func readTopKeys(in io.Reader, keys chan<- string) {
+	defer close(keys)
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		keys <- line[:1]
+	}
+}
+
+// topRow is the latest known state of a single queue
+type topRow struct {
+	Queue      string
+	Depth      int64
+	HighDepth  int64
+	EnqRate    float64
+	DeqRate    float64
+	HasReaders bool
+	HasWriters bool
+	Updated    time.Time
+}
+
+// topView accumulates queue statistics across refreshes and renders them as a table
+type topView struct {
+	queueManager string
+	rows         map[string]*topRow
+	sortBy       string
+	reverse      bool
+	limit        int
+	status       string
+}
+
+func newTopView() *topView {
+	return &topView{
+		rows:   make(map[string]*topRow),
+		sortBy: "depth",
+	}
+}
+
+// setSort selects the sort column by name or shortcut key
+func (v *topView) setSort(key string) error {
+	key = strings.ToLower(key)
+	if name, ok := topSortKeys[key]; ok {
+		key = name
+	}
+	for _, name := range topSortKeys {
+		if name == key {
+			v.sortBy = key
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sort column: %s (use name, depth, high, enq or deq)", key)
+}
+
+// handleKey applies an interactive key press and reports whether the view should exit
+func (v *topView) handleKey(key string) bool {
+	switch key {
+	case "q":
+		return true
+	case "r":
+		v.reverse = !v.reverse
+	default:
+		if err := v.setSort(key); err != nil {
+			v.status = fmt.Sprintf("unknown key %q", key)
+			return false
+		}
+	}
+	v.status = ""
+	return false
+}
+
+// update merges the queue statistics received during elapsed into the view
+func (v *topView) update(stats []*pcf.StatisticsData, elapsed time.Duration, now time.Time) {
+	type counts struct{ enq, deq int64 }
+	totals := make(map[string]*counts)
+
+	for _, s := range stats {
+		q := s.QueueStats
+		if q == nil || q.QueueName == "" {
+			continue
+		}
+
+		row, ok := v.rows[q.QueueName]
+		if !ok {
+			row = &topRow{Queue: q.QueueName}
+			v.rows[q.QueueName] = row
+		}
+		row.Depth = q.CurrentDepth
+		row.HighDepth = q.HighDepth
+		row.HasReaders = q.HasReaders || q.InputCount > 0
+		row.HasWriters = q.HasWriters || q.OutputCount > 0
+		row.Updated = now
+
+		c, ok := totals[q.QueueName]
+		if !ok {
+			c = &counts{}
+			totals[q.QueueName] = c
+		}
+		c.enq += int64(q.EnqueueCount)
+		c.deq += int64(q.DequeueCount)
+	}
+
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		return
+	}
+	for name, c := range totals {
+		row := v.rows[name]
+		row.EnqRate = float64(c.enq) / seconds
+		row.DeqRate = float64(c.deq) / seconds
+	}
+}
+
+// sorted returns the rows in display order
+func (v *topView) sorted() []*topRow {
+	rows := make([]*topRow, 0, len(v.rows))
+	for _, row := range v.rows {
+		rows = append(rows, row)
+	}
+
+	// Numeric columns sort largest first, names alphabetically; ties fall back to the name
+	less := func(a, b *topRow) bool {
+		switch v.sortBy {
+		case "depth":
+			if a.Depth != b.Depth {
+				return a.Depth > b.Depth
+			}
+		case "high":
+			if a.HighDepth != b.HighDepth {
+				return a.HighDepth > b.HighDepth
+			}
+		case "enq":
+			if a.EnqRate != b.EnqRate {
+				return a.EnqRate > b.EnqRate
+			}
+		case "deq":
+			if a.DeqRate != b.DeqRate {
+				return a.DeqRate > b.DeqRate
+			}
+		}
+		return a.Queue < b.Queue
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if v.reverse {
+			return less(rows[j], rows[i])
+		}
+		return less(rows[i], rows[j])
+	})
+
+	if v.limit > 0 && len(rows) > v.limit {
+		rows = rows[:v.limit]
+	}
+	return rows
+}
+
+// render clears the terminal and draws the table
+func (v *topView) render(out io.Writer, now time.Time) {
+	order := "desc"
+	if v.sortBy == "name" {
+		order = "asc"
+	}
+	if v.reverse {
+		if order == "asc" {
+			order = "desc"
+		} else {
+			order = "asc"
+		}
+	}
+
+	fmt.Fprint(out, clearScreen)
+	fmt.Fprintf(out, "ibmmq-collector top - %s - %s - %d queues - sort: %s (%s)\n\n",
+		v.queueManager, now.Format("15:04:05"), len(v.rows), v.sortBy, order)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUEUE\tDEPTH\tHIGH\tENQ/s\tDEQ/s\tREADERS\tWRITERS\tAGE")
+	for _, row := range v.sorted() {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%.1f\t%s\t%s\t%s\n",
+			row.Queue, row.Depth, row.HighDepth, row.EnqRate, row.DeqRate,
+			yesNo(row.HasReaders), yesNo(row.HasWriters),
+			now.Sub(row.Updated).Truncate(time.Second))
+	}
+	w.Flush()
+
+	if len(v.rows) == 0 {
+		fmt.Fprintln(out, "\nWaiting for queue statistics...")
+	}
+	if v.status != "" {
+		fmt.Fprintf(out, "\n%s\n", v.status)
+	}
+	fmt.Fprintln(out, "\nkeys: n name  d depth  h high  e enq  x deq  r reverse  q quit (then Enter)")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
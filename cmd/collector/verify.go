@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Verify-metrics flags
+var (
+	verifyGolden string
+	verifyUpdate bool
+)
+
+// Golden exposition produced by the sample messages with the default configuration
+//
+//go:embed golden/metrics.prom
+var defaultGoldenMetrics []byte
+
+// Queue manager and channel the sample messages are attributed to
+const (
+	goldenQueueManager = "GOLDENQM"
+	goldenChannel      = "GOLDEN.SVRCONN"
+)
+
+// Metric families whose values depend on when or how the collector was built
+var volatileMetrics = []string{"last_collection_timestamp", "collector_build_info"}
+
+func createVerifyMetricsCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify-metrics",
+		Short: "Check the metrics produced from sample PCF messages against a golden file",
+		Long: `Feed a fixed set of sample statistics and accounting messages through the PCF
+parser and Prometheus exporter and compare the resulting exposition with a golden
+file, to check that a build and configuration produce the expected metric names,
+labels and values before deploying.
+
+The built-in golden file matches the default configuration. Settings that change
+metric names, such as prometheus.namespace, need their own golden file: create it
+with --update --golden path and pass --golden path afterwards.`,
+		RunE: runVerifyMetrics,
+	}
+
+	verifyCmd.Flags().StringVar(&verifyGolden, "golden", "", "Golden exposition file (default built-in)")
+	verifyCmd.Flags().BoolVar(&verifyUpdate, "update", false, "Write the current exposition to --golden instead of comparing")
+
+	return verifyCmd
+}
+
+func runVerifyMetrics(cmd *cobra.Command, args []string) error {
+	logger := setupLogger()
+
+	if verifyUpdate && verifyGolden == "" {
+		return fmt.Errorf("--update requires --golden")
+	}
+
+	// Configuration is optional here: it only supplies exporter settings
+	cfg := config.DefaultConfig()
+	if configFile != "" {
+		loaded, err := config.LoadConfig(configFile)
+		if err != nil {
+			return configError(fmt.Errorf("failed to load configuration: %w", err))
+		}
+		cfg = loaded
+	}
+
+	actual, err := sampleExposition(cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	if verifyUpdate {
+		if err := os.WriteFile(verifyGolden, actual, 0644); err != nil {
+			return fmt.Errorf("failed to write golden file: %w", err)
+		}
+		if textOutput() {
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", verifyGolden)
+		}
+		return nil
+	}
+
+	expected := defaultGoldenMetrics
+	if verifyGolden != "" {
+		expected, err = os.ReadFile(verifyGolden)
+		if err != nil {
+			return fmt.Errorf("failed to read golden file: %w", err)
+		}
+	}
+
+	missing, unexpected := diffExposition(expected, actual)
+	if len(missing) == 0 && len(unexpected) == 0 {
+		if textOutput() {
+			fmt.Fprintln(cmd.OutOrStdout(), "Metrics match the golden file")
+		}
+		return nil
+	}
+
+	if !quiet {
+		printExpositionDiff(cmd.OutOrStdout(), missing, unexpected)
+	}
+	return fmt.Errorf("metrics differ from the golden file: %d missing, %d unexpected line(s)", len(missing), len(unexpected))
+}
+
+// sampleExposition runs the sample messages through a fresh metrics collector and returns
+// the resulting exposition in the Prometheus text format, without volatile metrics
+func sampleExposition(cfg *config.Config, logger *logrus.Logger) ([]byte, error) {
+	cfg.MQ.QueueManager = goldenQueueManager
+	cfg.MQ.Channel = goldenChannel
+
+	metrics := prometheus.NewMetricsCollector(cfg, nil, logger)
+	statsMessages, accountingMessages := sampleMessages()
+	metrics.ProcessMessages(statsMessages, accountingMessages)
+	if metrics.ParseErrors() > 0 {
+		return nil, fmt.Errorf("%d sample message(s) could not be parsed", metrics.ParseErrors())
+	}
+
+	families, err := metrics.GetRegistry().Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		if isVolatileMetric(family.GetName()) {
+			continue
+		}
+		if _, err := expfmt.MetricFamilyToText(&buf, family); err != nil {
+			return nil, fmt.Errorf("failed to encode metrics: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func isVolatileMetric(name string) bool {
+	for _, suffix := range volatileMetrics {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleMessages returns the fixed statistics and accounting messages behind the golden file
+func sampleMessages() (statsMessages, accountingMessages []*mqclient.MQMessage) {
+	queue := pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_MGR_NAME, goldenQueueManager).
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, 42).
+		AddInteger(pcf.MQIA_HIGH_Q_DEPTH, 120).
+		AddInteger(pcf.MQIA_MSG_ENQ_COUNT, 500).
+		AddInteger(pcf.MQIA_MSG_DEQ_COUNT, 458).
+		AddInteger(pcf.MQIA_OPEN_INPUT_COUNT, 2).
+		AddInteger(pcf.MQIA_OPEN_OUTPUT_COUNT, 1).
+		Bytes()
+
+	channel := pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_CHANNEL).
+		AddString(pcf.MQCA_Q_MGR_NAME, goldenQueueManager).
+		AddString(pcf.MQCA_CHANNEL_NAME, "TO.PARTNER").
+		AddString(pcf.MQCA_CONNECTION_NAME, "10.0.0.5(1414)").
+		AddInteger(pcf.MQIACH_MSGS, 250).
+		AddInteger(pcf.MQIACH_BYTES, 512000).
+		AddInteger(pcf.MQIACH_BATCHES, 25).
+		Bytes()
+
+	mqi := pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_MQI).
+		AddString(pcf.MQCA_Q_MGR_NAME, goldenQueueManager).
+		AddString(pcf.MQCA_APPL_NAME, "orders-service").
+		AddInteger(pcf.MQIAMO_OPENS, 10).
+		AddInteger(pcf.MQIAMO_CLOSES, 9).
+		AddInteger(pcf.MQIAMO_PUTS, 500).
+		AddInteger(pcf.MQIAMO_GETS, 458).
+		AddInteger(pcf.MQIAMO_COMMITS, 95).
+		AddInteger(pcf.MQIAMO_BACKOUTS, 1).
+		Bytes()
+
+	accounting := pcf.NewMessageBuilder(pcf.MQCFT_ACCOUNTING, pcf.MQCMD_ACCOUNTING_MQI).
+		AddString(pcf.MQCA_Q_MGR_NAME, goldenQueueManager).
+		AddString(pcf.MQCA_APPL_NAME, "billing-batch").
+		AddInteger(pcf.MQIAMO_OPENS, 2).
+		AddInteger(pcf.MQIAMO_CLOSES, 2).
+		AddInteger(pcf.MQIAMO_PUTS, 30).
+		AddInteger(pcf.MQIAMO_GETS, 12).
+		AddInteger(pcf.MQIAMO_COMMITS, 4).
+		AddInteger(pcf.MQIAMO_BACKOUTS, 0).
+		Bytes()
+
+	wrap := func(queueType string, data []byte) *mqclient.MQMessage {
+		return &mqclient.MQMessage{Data: data, Type: queueType}
+	}
+
+	statsMessages = []*mqclient.MQMessage{wrap("stats", queue), wrap("stats", channel), wrap("stats", mqi)}
+	accountingMessages = []*mqclient.MQMessage{wrap("accounting", accounting)}
+	return statsMessages, accountingMessages
+}
+
+// diffExposition compares two expositions line by line, ignoring order
+func diffExposition(expected, actual []byte) (missing, unexpected []string) {
+	expectedLines := expositionLines(expected)
+	actualLines := expositionLines(actual)
+
+	for line := range expectedLines {
+		if !actualLines[line] {
+			missing = append(missing, line)
+		}
+	}
+	for line := range actualLines {
+		if !expectedLines[line] {
+			unexpected = append(unexpected, line)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return missing, unexpected
+}
+
+func expositionLines(data []byte) map[string]bool {
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines[line] = true
+		}
+	}
+	return lines
+}
+
+// printExpositionDiff lists golden lines that were not produced and produced lines not in the golden file
+func printExpositionDiff(out io.Writer, missing, unexpected []string) {
+	for _, line := range missing {
+		fmt.Fprintf(out, "- %s\n", line)
+	}
+	for _, line := range unexpected {
+		fmt.Fprintf(out, "+ %s\n", line)
+	}
+}
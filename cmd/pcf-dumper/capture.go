@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+)
+
+// captureIndexFile is the manifest written next to the saved messages
+const captureIndexFile = "index.json"
+
+// capturedMD holds the MQMD fields worth keeping with a saved message
+type capturedMD struct {
+	Format         string `json:"format"`
+	MsgType        int32  `json:"msg_type"`
+	Encoding       int32  `json:"encoding"`
+	CodedCharSetId int32  `json:"coded_char_set_id"`
+	Persistence    int32  `json:"persistence"`
+	MsgID          string `json:"msg_id"`
+	CorrelID       string `json:"correl_id"`
+	UserIdentifier string `json:"user_identifier"`
+	PutApplType    int32  `json:"put_appl_type"`
+	PutApplName    string `json:"put_appl_name"`
+	PutDate        string `json:"put_date"`
+	PutTime        string `json:"put_time"`
+}
+
+// capturedMessage describes one saved message in the manifest and its MQMD file
+type capturedMessage struct {
+	File    string      `json:"file"`
+	MDFile  string      `json:"mqmd_file,omitempty"`
+	Queue   string      `json:"queue"`
+	Type    string      `json:"type"`
+	Command int32       `json:"command"`
+	Length  int         `json:"length"`
+	MD      *capturedMD `json:"mqmd,omitempty"`
+}
+
+// captureManifest is the index of a capture directory
+type captureManifest struct {
+	Created      time.Time         `json:"created"`
+	QueueManager string            `json:"queue_manager"`
+	Messages     []capturedMessage `json:"messages"`
+}
+
+// captureWriter saves raw messages into a directory, one payload file and one MQMD
+// file per message. The payloads can be replayed with `ibmmq-collector bench <dir>`.
+type captureWriter struct {
+	dir      string
+	manifest captureManifest
+}
+
+func newCaptureWriter(dir, queueManager string) (*captureWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+	return &captureWriter{
+		dir: dir,
+		manifest: captureManifest{
+			Created:      time.Now().UTC(),
+			QueueManager: queueManager,
+		},
+	}, nil
+}
+
+// save writes a message payload and its MQMD and records them in the manifest
+func (w *captureWriter) save(queue string, msg *mqclient.MQMessage) error {
+	base := fmt.Sprintf("%04d-%s", len(w.manifest.Messages)+1, msg.Type)
+	entry := capturedMessage{
+		File:   base + ".bin",
+		Queue:  queue,
+		Type:   msg.Type,
+		Length: len(msg.Data),
+	}
+	if len(msg.Data) >= 16 {
+		entry.Command = int32(binary.LittleEndian.Uint32(msg.Data[12:16]))
+	}
+
+	if err := os.WriteFile(filepath.Join(w.dir, entry.File), msg.Data, 0644); err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+
+	if msg.MD != nil {
+		entry.MD = &capturedMD{
+			Format:         msg.MD.Format,
+			MsgType:        msg.MD.MsgType,
+			Encoding:       msg.MD.Encoding,
+			CodedCharSetId: msg.MD.CodedCharSetId,
+			Persistence:    msg.MD.Persistence,
+			MsgID:          hex.EncodeToString(msg.MD.MsgId),
+			CorrelID:       hex.EncodeToString(msg.MD.CorrelId),
+			UserIdentifier: msg.MD.UserIdentifier,
+			PutApplType:    msg.MD.PutApplType,
+			PutApplName:    msg.MD.PutApplName,
+			PutDate:        msg.MD.PutDate,
+			PutTime:        msg.MD.PutTime,
+		}
+		entry.MDFile = base + ".mqmd.json"
+		if err := writeJSONFile(filepath.Join(w.dir, entry.MDFile), entry.MD); err != nil {
+			return fmt.Errorf("failed to save message descriptor: %w", err)
+		}
+	}
+
+	w.manifest.Messages = append(w.manifest.Messages, entry)
+	return nil
+}
+
+// close writes the manifest
+func (w *captureWriter) close() error {
+	if err := writeJSONFile(filepath.Join(w.dir, captureIndexFile), w.manifest); err != nil {
+		return fmt.Errorf("failed to write capture index: %w", err)
+	}
+	return nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// Parameters that change in every interval and would hide the interesting differences
+var volatileParameters = map[string]bool{
+	fmt.Sprintf("param_%d", pcf.MQCACF_COMMAND_TIME):    true,
+	fmt.Sprintf("param_%d", pcf.MQIACF_SEQUENCE_NUMBER): true,
+}
+
+// Object diff states
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+// parameterChange is a single parameter that differs between two snapshots
+type parameterChange struct {
+	Parameter string
+	Before    string
+	After     string
+}
+
+// objectDiff lists the differences for one object (e.g. STATISTICS_Q APP.ORDERS)
+type objectDiff struct {
+	Object  string
+	Status  string
+	Changes []parameterChange
+}
+
+// snapshot maps an object key to the parameters last reported for it
+type snapshot map[string]map[string]string
+
+// loadCapture reads the messages of a directory written with -save
+func loadCapture(dir string) ([]*mqclient.MQMessage, error) {
+	data, err := os.ReadFile(filepath.Join(dir, captureIndexFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture index: %w", err)
+	}
+
+	var manifest captureManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse capture index %s: %w", dir, err)
+	}
+
+	messages := make([]*mqclient.MQMessage, 0, len(manifest.Messages))
+	for _, entry := range manifest.Messages {
+		payload, err := os.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read captured message: %w", err)
+		}
+		messages = append(messages, &mqclient.MQMessage{Data: payload, Type: entry.Type})
+	}
+	return messages, nil
+}
+
+// buildSnapshot parses the messages that pass the filter and indexes their parameters by object
+func buildSnapshot(filter *messageFilter, messages []*mqclient.MQMessage) snapshot {
+	snap := make(snapshot)
+	for _, msg := range messages {
+		if !filter.matches(msg.Data) {
+			continue
+		}
+		parsed, err := filter.parser.ParseMessage(msg.Data, msg.Type)
+		if err != nil {
+			continue
+		}
+
+		var parameters map[string]interface{}
+		switch data := parsed.(type) {
+		case *pcf.StatisticsData:
+			parameters = data.Parameters
+		case *pcf.AccountingData:
+			parameters = data.Parameters
+		default:
+			continue
+		}
+
+		key := objectKey(msg.Data, parsed)
+		values := make(map[string]string, len(parameters))
+		for name, value := range parameters {
+			if volatileParameters[name] {
+				continue
+			}
+			values[name] = strings.TrimSpace(strings.TrimRight(fmt.Sprint(value), "\x00"))
+		}
+		snap[key] = values
+	}
+	return snap
+}
+
+// objectKey identifies the object a message reports on by command type and name
+func objectKey(data []byte, parsed interface{}) string {
+	command := int32(binary.LittleEndian.Uint32(data[12:16]))
+	name := fmt.Sprintf("%d", command)
+	for commandName, value := range commandNames {
+		if value == command {
+			name = commandName
+			break
+		}
+	}
+
+	if names := objectNames(parsed); len(names) > 0 {
+		return name + " " + strings.Join(names, "/")
+	}
+	return name
+}
+
+// diffSnapshots reports the objects and parameters that differ between two snapshots
+func diffSnapshots(before, after snapshot) []objectDiff {
+	keys := make(map[string]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	var diffs []objectDiff
+	for key := range keys {
+		old, inBefore := before[key]
+		cur, inAfter := after[key]
+		switch {
+		case !inBefore:
+			diffs = append(diffs, objectDiff{Object: key, Status: diffAdded})
+		case !inAfter:
+			diffs = append(diffs, objectDiff{Object: key, Status: diffRemoved})
+		default:
+			if changes := diffParameters(old, cur); len(changes) > 0 {
+				diffs = append(diffs, objectDiff{Object: key, Status: diffChanged, Changes: changes})
+			}
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Object < diffs[j].Object })
+	return diffs
+}
+
+func diffParameters(before, after map[string]string) []parameterChange {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	var changes []parameterChange
+	for name := range names {
+		if before[name] != after[name] {
+			changes = append(changes, parameterChange{Parameter: name, Before: before[name], After: after[name]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Parameter < changes[j].Parameter })
+	return changes
+}
+
+// printDiff writes the differences, one object per block
+func printDiff(out io.Writer, diffs []objectDiff) {
+	if len(diffs) == 0 {
+		fmt.Fprintln(out, "No differences")
+		return
+	}
+
+	for _, d := range diffs {
+		fmt.Fprintf(out, "%s: %s\n", d.Object, d.Status)
+		for _, c := range d.Changes {
+			fmt.Fprintf(out, "  %s: %q -> %q\n", c.Parameter, c.Before, c.After)
+		}
+	}
+	fmt.Fprintf(out, "\n%d object(s) differ\n", len(diffs))
+}
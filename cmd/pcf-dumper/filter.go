@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// Command types accepted by -command, with or without the MQCMD_ prefix
+var commandNames = map[string]int32{
+	"STATISTICS_Q":       pcf.MQCMD_STATISTICS_Q,
+	"STATISTICS_CHANNEL": pcf.MQCMD_STATISTICS_CHANNEL,
+	"STATISTICS_MQI":     pcf.MQCMD_STATISTICS_MQI,
+	"ACCOUNTING_Q":       pcf.MQCMD_ACCOUNTING_Q,
+	"ACCOUNTING_MQI":     pcf.MQCMD_ACCOUNTING_MQI,
+}
+
+// parseCommand resolves a command type name such as MQCMD_STATISTICS_Q or a numeric value
+func parseCommand(value string) (int32, error) {
+	name := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "MQCMD_")
+	if command, ok := commandNames[name]; ok {
+		return command, nil
+	}
+	if n, err := strconv.ParseInt(value, 0, 32); err == nil {
+		return int32(n), nil
+	}
+	return 0, fmt.Errorf("unknown command type: %s (use e.g. MQCMD_STATISTICS_Q or a number)", value)
+}
+
+// messageFilter selects messages by PCF command and by a glob pattern on the queue,
+// channel or application name they describe. Zero values match everything.
+type messageFilter struct {
+	command int32
+	object  string
+	parser  *pcf.Parser
+}
+
+// active reports whether the filter restricts anything
+func (f *messageFilter) active() bool {
+	return f.command != 0 || f.object != ""
+}
+
+// matches reports whether a raw PCF message passes the filter
+func (f *messageFilter) matches(data []byte) bool {
+	if !f.active() {
+		return true
+	}
+	if len(data) < 36 {
+		return false
+	}
+
+	if f.command != 0 && int32(binary.LittleEndian.Uint32(data[12:16])) != f.command {
+		return false
+	}
+
+	if f.object != "" {
+		parsed, err := f.parser.ParseMessage(data, "dump")
+		if err != nil {
+			return false
+		}
+		for _, name := range objectNames(parsed) {
+			if ok, _ := path.Match(f.object, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// objectNames returns the queue, channel and application names a parsed message refers to
+func objectNames(parsed interface{}) []string {
+	var names []string
+	add := func(name string) {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	switch data := parsed.(type) {
+	case *pcf.StatisticsData:
+		if data.QueueStats != nil {
+			add(data.QueueStats.QueueName)
+		}
+		if data.ChannelStats != nil {
+			add(data.ChannelStats.ChannelName)
+		}
+		if data.MQIStats != nil {
+			add(data.MQIStats.ApplicationName)
+		}
+	case *pcf.AccountingData:
+		if data.ConnectionInfo != nil {
+			add(data.ConnectionInfo.ApplicationName)
+			add(data.ConnectionInfo.ChannelName)
+		}
+	}
+	return names
+}
@@ -6,21 +6,63 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/redact"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
 	// Parse command line flags
-	var configPath = flag.String("config", "configs/default.yaml", "Configuration file path")
+	var configPath string
+	flag.StringVar(&configPath, "config", "", "Configuration file path (default: search ./config.yaml and the standard locations)")
+	flag.StringVar(&configPath, "c", "", "Configuration file path (shorthand for -config)")
+	var consume = flag.Bool("consume", false, "Remove messages from the queues instead of browsing them")
+	var command = flag.String("command", "", "Only dump messages of this command type (e.g. MQCMD_STATISTICS_Q)")
+	var object = flag.String("object", "", "Only dump messages for queues, channels or applications matching this pattern (e.g. APP.*)")
+	var limit = flag.Int("limit", 0, "Maximum number of messages to dump per queue (0 = all)")
+	var saveDir = flag.String("save", "", "Save each dumped message and its MQMD to this directory, with an index.json manifest")
+	var follow = flag.Bool("follow", false, "Keep waiting for new messages and dump them as they arrive, until interrupted")
+	var diff = flag.Bool("diff", false, "Compare two captures (-diff before/ after/), or one capture with the queues (-diff before/)")
 	flag.Parse()
 
+	if *diff && (flag.NArg() < 1 || flag.NArg() > 2) {
+		log.Fatalf("-diff needs one or two capture directories")
+	}
+
+	// Create logger
+	logger := logrus.New()
+	logger.SetLevel(logrus.InfoLevel)
+	logger.AddHook(redact.NewHook())
+
+	filter := &messageFilter{object: *object, parser: pcf.NewParser(logging.NewLogrus(logger))}
+	if *command != "" {
+		var err error
+		if filter.command, err = parseCommand(*command); err != nil {
+			log.Fatalf("Invalid -command: %v", err)
+		}
+	}
+
+	// Two saved captures can be compared without connecting
+	if *diff && flag.NArg() == 2 {
+		before := loadSnapshot(filter, flag.Arg(0))
+		printDiff(os.Stdout, diffSnapshots(before, loadSnapshot(filter, flag.Arg(1))))
+		return
+	}
+
 	// Load configuration from file
-	cfg, err := config.LoadConfig(*configPath)
+	// IBMMQ_* environment variables override the file, as for the collector
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
-		log.Fatalf("Failed to load configuration from %s: %v", *configPath, err)
+		log.Fatalf("Failed to load configuration from %s: %v", configSource(configPath), err)
 	}
 
 	// Validate configuration
@@ -34,20 +76,28 @@ func main() {
 	fmt.Printf("Accounting Queue: %s\n", cfg.Collector.AccountingQueue)
 	fmt.Println()
 
-	// Create logger
-	logger := logrus.New()
-	logger.SetLevel(logrus.InfoLevel)
-
 	fmt.Printf("=== IBM MQ PCF Data Dumper ===\n")
-	fmt.Printf("Configuration loaded from: %s\n", *configPath)
+	fmt.Printf("Configuration loaded from: %s\n", configSource(configPath))
 	fmt.Printf("Queue Manager: %s\n", cfg.MQ.QueueManager)
 	fmt.Printf("Connection: %s via %s\n", cfg.MQ.ConnectionName, cfg.MQ.Channel)
 	fmt.Printf("Statistics Queue: %s\n", cfg.Collector.StatsQueue)
 	fmt.Printf("Accounting Queue: %s\n", cfg.Collector.AccountingQueue)
+	if *consume {
+		fmt.Printf("Mode: consume (messages are removed from the queues)\n")
+	} else {
+		fmt.Printf("Mode: browse (messages are left on the queues)\n")
+	}
 	fmt.Printf("\n")
 
+	d := &dumper{filter: filter, limit: *limit}
+	if *saveDir != "" {
+		if d.capture, err = newCaptureWriter(*saveDir, cfg.MQ.QueueManager); err != nil {
+			log.Fatalf("Failed to prepare capture: %v", err)
+		}
+	}
+
 	// Create MQ client
-	client := mqclient.NewMQClient(&cfg.MQ, logger)
+	client := mqclient.NewMQClient(&cfg.MQ, logging.NewLogrus(logger))
 
 	// Connect
 	if err := client.Connect(); err != nil {
@@ -55,92 +105,222 @@ func main() {
 	}
 	defer client.Disconnect()
 
-	// Open queues using configuration
-	if err := client.OpenStatsQueue(cfg.Collector.StatsQueue); err != nil {
+	// Open queues using configuration; browsing leaves the data for the collector
+	if err := client.OpenQueue(cfg.Collector.StatsQueue, !*consume); err != nil {
 		log.Printf("Failed to open statistics queue %s: %v", cfg.Collector.StatsQueue, err)
 	}
-	if err := client.OpenAccountingQueue(cfg.Collector.AccountingQueue); err != nil {
+	if err := client.OpenQueue(cfg.Collector.AccountingQueue, !*consume); err != nil {
 		log.Printf("Failed to open accounting queue %s: %v", cfg.Collector.AccountingQueue, err)
 	}
 
+	if *diff {
+		before := loadSnapshot(filter, flag.Arg(0))
+		var live []*mqclient.MQMessage
+		for _, q := range []struct{ name, msgType string }{
+			{cfg.Collector.AccountingQueue, "accounting"},
+			{cfg.Collector.StatsQueue, "stats"},
+		} {
+			messages, err := readAll(client, q.name, q.msgType)
+			if err != nil {
+				log.Fatalf("Error getting messages from %s: %v", q.name, err)
+			}
+			live = append(live, messages...)
+		}
+
+		fmt.Printf("--- DIFF %s -> %s ---\n", flag.Arg(0), cfg.MQ.QueueManager)
+		printDiff(os.Stdout, diffSnapshots(before, buildSnapshot(filter, live)))
+		return
+	}
+
 	// Get accounting messages
 	fmt.Println("\n--- ACCOUNTING MESSAGES ---")
-	acctMessages, err := client.GetAllMessages("accounting")
+	acctMessages, err := readAll(client, cfg.Collector.AccountingQueue, "accounting")
 	if err != nil {
 		log.Printf("Error getting accounting messages: %v", err)
 	} else {
-		fmt.Printf("Retrieved %d accounting messages\n", len(acctMessages))
-
-		for i, msg := range acctMessages {
-			msgData := msg.Data
-			fmt.Printf("\n=== Accounting Message %d ===\n", i+1)
-			fmt.Printf("Length: %d bytes\n", len(msgData))
-
-			// Show hex dump of first 64 bytes
-			fmt.Printf("Hex dump (first 64 bytes):\n")
-			if len(msgData) > 64 {
-				fmt.Printf("%s\n", hex.Dump(msgData[:64]))
-			} else {
-				fmt.Printf("%s\n", hex.Dump(msgData))
-			}
-
-			// Try to parse PCF header
-			if len(msgData) >= 36 {
-				fmt.Printf("PCF Header Analysis:\n")
-				fmt.Printf("  Type (BE):           %d\n", binary.BigEndian.Uint32(msgData[0:4]))
-				fmt.Printf("  Type (LE):           %d\n", binary.LittleEndian.Uint32(msgData[0:4]))
-				fmt.Printf("  StrucLength (BE):    %d\n", binary.BigEndian.Uint32(msgData[4:8]))
-				fmt.Printf("  StrucLength (LE):    %d\n", binary.LittleEndian.Uint32(msgData[4:8]))
-				fmt.Printf("  Command (BE):        %d\n", binary.BigEndian.Uint32(msgData[12:16]))
-				fmt.Printf("  Command (LE):        %d\n", binary.LittleEndian.Uint32(msgData[12:16]))
-				fmt.Printf("  ParamCount (BE):     %d\n", binary.BigEndian.Uint32(msgData[32:36]))
-				fmt.Printf("  ParamCount (LE):     %d\n", binary.LittleEndian.Uint32(msgData[32:36]))
-			}
-
-			if i >= 2 { // Limit to first 3 messages
-				fmt.Printf("... (showing first 3 messages only)\n")
-				break
-			}
-		}
+		d.dumpMessages("Accounting", cfg.Collector.AccountingQueue, acctMessages)
 	}
 
 	// Get statistics messages
 	fmt.Println("\n--- STATISTICS MESSAGES ---")
-	statsMessages, err := client.GetAllMessages("stats")
+	statsMessages, err := readAll(client, cfg.Collector.StatsQueue, "stats")
 	if err != nil {
 		log.Printf("Error getting statistics messages: %v", err)
 	} else {
-		fmt.Printf("Retrieved %d statistics messages\n", len(statsMessages))
-
-		for i, msg := range statsMessages {
-			msgData := msg.Data
-			fmt.Printf("\n=== Statistics Message %d ===\n", i+1)
-			fmt.Printf("Length: %d bytes\n", len(msgData))
-
-			// Show hex dump of first 64 bytes
-			fmt.Printf("Hex dump (first 64 bytes):\n")
-			if len(msgData) > 64 {
-				fmt.Printf("%s\n", hex.Dump(msgData[:64]))
-			} else {
-				fmt.Printf("%s\n", hex.Dump(msgData))
-			}
+		d.dumpMessages("Statistics", cfg.Collector.StatsQueue, statsMessages)
+	}
 
-			// Try to parse PCF header
-			if len(msgData) >= 36 {
-				fmt.Printf("PCF Header Analysis:\n")
-				fmt.Printf("  Type (BE):           %d\n", binary.BigEndian.Uint32(msgData[0:4]))
-				fmt.Printf("  Type (LE):           %d\n", binary.LittleEndian.Uint32(msgData[0:4]))
-				fmt.Printf("  StrucLength (BE):    %d\n", binary.BigEndian.Uint32(msgData[4:8]))
-				fmt.Printf("  StrucLength (LE):    %d\n", binary.LittleEndian.Uint32(msgData[4:8]))
-				fmt.Printf("  Command (BE):        %d\n", binary.BigEndian.Uint32(msgData[12:16]))
-				fmt.Printf("  Command (LE):        %d\n", binary.LittleEndian.Uint32(msgData[12:16]))
-				fmt.Printf("  ParamCount (BE):     %d\n", binary.BigEndian.Uint32(msgData[32:36]))
-				fmt.Printf("  ParamCount (LE):     %d\n", binary.LittleEndian.Uint32(msgData[32:36]))
-			}
+	if *follow {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+		fmt.Println("\n--- FOLLOWING (Ctrl+C to stop) ---")
+		d.follow(client, []followQueue{
+			{"Accounting", cfg.Collector.AccountingQueue, "accounting"},
+			{"Statistics", cfg.Collector.StatsQueue, "stats"},
+		}, stop)
+	}
+
+	if d.capture != nil {
+		if err := d.capture.close(); err != nil {
+			log.Fatalf("Failed to save capture: %v", err)
 		}
+		fmt.Printf("\nSaved %d messages to %s\n", len(d.capture.manifest.Messages), *saveDir)
 	}
 
 	fmt.Println("\n=== Analysis Complete ===")
 	fmt.Println("This raw data shows the actual PCF format used by IBM MQ.")
 	fmt.Println("Look for ipprocs (input processes/readers) and opprocs (output processes/writers) in the data.")
 }
+
+// dumper prints, and optionally saves, the messages that pass its filter
+type dumper struct {
+	filter  *messageFilter
+	limit   int
+	capture *captureWriter
+	dumped  map[string]int
+}
+
+// followQueue is a queue watched in follow mode
+type followQueue struct {
+	label   string
+	name    string
+	msgType string
+}
+
+// How long each queue is waited on per pass in follow mode
+const followWait = time.Second
+
+// dumpMessages prints the messages that pass the filter, up to limit (0 = all)
+func (d *dumper) dumpMessages(label, queue string, messages []*mqclient.MQMessage) {
+	fmt.Printf("Retrieved %d %s messages\n", len(messages), strings.ToLower(label))
+
+	matched := 0
+	for _, msg := range messages {
+		if !d.filter.matches(msg.Data) {
+			continue
+		}
+		matched++
+		if !d.dump(label, queue, msg, time.Time{}) {
+			fmt.Printf("... (showing first %d messages only)\n", d.limit)
+			break
+		}
+	}
+
+	if d.filter.active() {
+		fmt.Printf("%d of %d %s messages matched the filter\n", matched, len(messages), strings.ToLower(label))
+	}
+}
+
+// dump prints and saves a message that already passed the filter, and reports false once
+// the limit for its queue has been reached. received is shown when set.
+func (d *dumper) dump(label, queue string, msg *mqclient.MQMessage, received time.Time) bool {
+	if d.dumped == nil {
+		d.dumped = make(map[string]int)
+	}
+	if d.limit > 0 && d.dumped[queue] >= d.limit {
+		return false
+	}
+	d.dumped[queue]++
+	dumpMessage(label, d.dumped[queue], msg, received)
+
+	if d.capture != nil {
+		if err := d.capture.save(queue, msg); err != nil {
+			log.Printf("Failed to save message: %v", err)
+		}
+	}
+	return true
+}
+
+// follow waits for new messages on the queues and dumps them as they arrive, until stop
+// receives a signal. Browsing continues from where the initial read stopped.
+func (d *dumper) follow(client *mqclient.MQClient, queues []followQueue, stop <-chan os.Signal) {
+	for {
+		for _, q := range queues {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			msg, err := client.GetQueueMessage(q.name, followWait)
+			if err != nil {
+				log.Printf("Error getting messages from %s: %v", q.name, err)
+				time.Sleep(followWait)
+				continue
+			}
+			if msg == nil || !d.filter.matches(msg.Data) {
+				continue
+			}
+			msg.Type = q.msgType
+			d.dump(q.label, q.name, msg, time.Now())
+		}
+	}
+}
+
+// dumpMessage prints the start of a message as hex and its PCF header in both byte orders
+func dumpMessage(label string, n int, msg *mqclient.MQMessage, received time.Time) {
+	msgData := msg.Data
+	fmt.Printf("\n=== %s Message %d ===\n", label, n)
+	if !received.IsZero() {
+		fmt.Printf("Received: %s\n", received.Format(time.RFC3339))
+		if msg.MD != nil {
+			fmt.Printf("Put:      %s\n", msg.GetTimestamp().Format(time.RFC3339))
+		}
+	}
+	fmt.Printf("Length: %d bytes\n", len(msgData))
+
+	// Show hex dump of first 64 bytes
+	fmt.Printf("Hex dump (first 64 bytes):\n")
+	if len(msgData) > 64 {
+		fmt.Printf("%s\n", hex.Dump(msgData[:64]))
+	} else {
+		fmt.Printf("%s\n", hex.Dump(msgData))
+	}
+
+	// Try to parse PCF header
+	if len(msgData) >= 36 {
+		fmt.Printf("PCF Header Analysis:\n")
+		fmt.Printf("  Type (BE):           %d\n", binary.BigEndian.Uint32(msgData[0:4]))
+		fmt.Printf("  Type (LE):           %d\n", binary.LittleEndian.Uint32(msgData[0:4]))
+		fmt.Printf("  StrucLength (BE):    %d\n", binary.BigEndian.Uint32(msgData[4:8]))
+		fmt.Printf("  StrucLength (LE):    %d\n", binary.LittleEndian.Uint32(msgData[4:8]))
+		fmt.Printf("  Command (BE):        %d\n", binary.BigEndian.Uint32(msgData[12:16]))
+		fmt.Printf("  Command (LE):        %d\n", binary.LittleEndian.Uint32(msgData[12:16]))
+		fmt.Printf("  ParamCount (BE):     %d\n", binary.BigEndian.Uint32(msgData[32:36]))
+		fmt.Printf("  ParamCount (LE):     %d\n", binary.LittleEndian.Uint32(msgData[32:36]))
+	}
+}
+
+// configSource describes where the configuration was loaded from
+func configSource(configPath string) string {
+	if configPath == "" {
+		return "standard locations and IBMMQ_* environment variables"
+	}
+	return configPath
+}
+
+// loadSnapshot reads a saved capture for comparison, exiting if it cannot be read
+func loadSnapshot(filter *messageFilter, dir string) snapshot {
+	messages, err := loadCapture(dir)
+	if err != nil {
+		log.Fatalf("Failed to load capture: %v", err)
+	}
+	return buildSnapshot(filter, messages)
+}
+
+// readAll reads every message currently on a queue opened with OpenQueue
+func readAll(client *mqclient.MQClient, queueName, queueType string) ([]*mqclient.MQMessage, error) {
+	var messages []*mqclient.MQMessage
+	for {
+		msg, err := client.GetQueueMessage(queueName, 0)
+		if err != nil {
+			return nil, err
+		}
+		if msg == nil {
+			return messages, nil
+		}
+		msg.Type = queueType
+		messages = append(messages, msg)
+	}
+}
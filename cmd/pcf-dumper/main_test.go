@@ -1,11 +1,18 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -202,3 +209,196 @@ metrics:
 		})
 	}
 }
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int32
+	}{
+		{"MQCMD_STATISTICS_Q", pcf.MQCMD_STATISTICS_Q},
+		{"statistics_channel", pcf.MQCMD_STATISTICS_CHANNEL},
+		{"ACCOUNTING_MQI", pcf.MQCMD_ACCOUNTING_MQI},
+		{"113", pcf.MQCMD_STATISTICS_Q},
+	}
+
+	for _, tt := range tests {
+		command, err := parseCommand(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, command, tt.value)
+	}
+
+	_, err := parseCommand("MQCMD_INQUIRE_Q")
+	assert.Error(t, err)
+}
+
+func TestMessageFilter(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	queueStats := pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_MGR_NAME, "TESTQM").
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, 3).
+		Bytes()
+	accounting := pcf.NewMessageBuilder(pcf.MQCFT_ACCOUNTING, pcf.MQCMD_ACCOUNTING_MQI).
+		AddString(pcf.MQCA_APPL_NAME, "orders-service").
+		AddInteger(pcf.MQIAMO_PUTS, 10).
+		Bytes()
+
+	tests := []struct {
+		name       string
+		filter     messageFilter
+		queueStats bool
+		accounting bool
+	}{
+		{"no filter", messageFilter{}, true, true},
+		{"command", messageFilter{command: pcf.MQCMD_STATISTICS_Q}, true, false},
+		{"queue pattern", messageFilter{object: "APP.*"}, true, false},
+		{"application pattern", messageFilter{object: "orders-*"}, false, true},
+		{"command and pattern", messageFilter{command: pcf.MQCMD_ACCOUNTING_MQI, object: "APP.*"}, false, false},
+		{"no match", messageFilter{object: "SYSTEM.*"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.parser = pcf.NewParser(logging.NewLogrus(logger))
+			assert.Equal(t, tt.queueStats, tt.filter.matches(queueStats))
+			assert.Equal(t, tt.accounting, tt.filter.matches(accounting))
+		})
+	}
+
+	filter := &messageFilter{command: pcf.MQCMD_STATISTICS_Q}
+	assert.False(t, filter.matches([]byte{0x01, 0x02}), "truncated messages never match an active filter")
+}
+
+func TestCaptureWriter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "capture")
+
+	writer, err := newCaptureWriter(dir, "TESTQM")
+	require.NoError(t, err)
+
+	payload := pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		Bytes()
+	md := ibmmq.NewMQMD()
+	md.Format = "MQADMIN"
+	md.PutApplName = "TESTQM"
+	md.MsgId = []byte{0xde, 0xad}
+
+	require.NoError(t, writer.save("SYSTEM.ADMIN.STATISTICS.QUEUE", &mqclient.MQMessage{MD: md, Data: payload, Type: "stats"}))
+	require.NoError(t, writer.save("SYSTEM.ADMIN.ACCOUNTING.QUEUE", &mqclient.MQMessage{Data: []byte{0x01}, Type: "accounting"}))
+	require.NoError(t, writer.close())
+
+	saved, err := os.ReadFile(filepath.Join(dir, "0001-stats.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, payload, saved)
+
+	mdData, err := os.ReadFile(filepath.Join(dir, "0001-stats.mqmd.json"))
+	require.NoError(t, err)
+	var savedMD capturedMD
+	require.NoError(t, json.Unmarshal(mdData, &savedMD))
+	assert.Equal(t, "MQADMIN", savedMD.Format)
+	assert.Equal(t, "dead", savedMD.MsgID)
+
+	indexData, err := os.ReadFile(filepath.Join(dir, captureIndexFile))
+	require.NoError(t, err)
+	var manifest captureManifest
+	require.NoError(t, json.Unmarshal(indexData, &manifest))
+
+	assert.Equal(t, "TESTQM", manifest.QueueManager)
+	require.Len(t, manifest.Messages, 2)
+	assert.Equal(t, "0001-stats.bin", manifest.Messages[0].File)
+	assert.Equal(t, int32(pcf.MQCMD_STATISTICS_Q), manifest.Messages[0].Command)
+	assert.Equal(t, "SYSTEM.ADMIN.STATISTICS.QUEUE", manifest.Messages[0].Queue)
+	assert.Equal(t, "0002-accounting.bin", manifest.Messages[1].File)
+	assert.Empty(t, manifest.Messages[1].MDFile, "messages without an MQMD have no descriptor file")
+}
+
+func TestDiffCaptures(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	filter := &messageFilter{parser: pcf.NewParser(logging.NewLogrus(logger))}
+
+	queueStats := func(name string, depth int32) *mqclient.MQMessage {
+		return &mqclient.MQMessage{
+			Type: "stats",
+			Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+				AddString(pcf.MQCA_Q_NAME, name).
+				AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, depth).
+				AddInteger(pcf.MQIACF_SEQUENCE_NUMBER, depth).
+				Bytes(),
+		}
+	}
+
+	save := func(dir string, messages ...*mqclient.MQMessage) {
+		writer, err := newCaptureWriter(dir, "TESTQM")
+		require.NoError(t, err)
+		for _, msg := range messages {
+			require.NoError(t, writer.save("SYSTEM.ADMIN.STATISTICS.QUEUE", msg))
+		}
+		require.NoError(t, writer.close())
+	}
+
+	beforeDir := filepath.Join(t.TempDir(), "before")
+	afterDir := filepath.Join(t.TempDir(), "after")
+	save(beforeDir, queueStats("APP.ORDERS", 5), queueStats("APP.OLD", 1), queueStats("APP.SAME", 2))
+	save(afterDir, queueStats("APP.ORDERS", 9), queueStats("APP.NEW", 0), queueStats("APP.SAME", 2))
+
+	beforeMessages, err := loadCapture(beforeDir)
+	require.NoError(t, err)
+	afterMessages, err := loadCapture(afterDir)
+	require.NoError(t, err)
+
+	diffs := diffSnapshots(buildSnapshot(filter, beforeMessages), buildSnapshot(filter, afterMessages))
+	require.Len(t, diffs, 3)
+
+	assert.Equal(t, objectDiff{Object: "STATISTICS_Q APP.NEW", Status: diffAdded}, diffs[0])
+	assert.Equal(t, objectDiff{Object: "STATISTICS_Q APP.OLD", Status: diffRemoved}, diffs[1])
+	assert.Equal(t, "STATISTICS_Q APP.ORDERS", diffs[2].Object)
+	assert.Equal(t, diffChanged, diffs[2].Status)
+	assert.Equal(t, []parameterChange{{Parameter: "param_3", Before: "5", After: "9"}}, diffs[2].Changes,
+		"the sequence number changes every interval and is ignored")
+
+	_, err = loadCapture(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestEnvironmentOverridesConfigFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "dumper.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+mq:
+  queue_manager: "FILEQM"
+  channel: "FILE.SVRCONN"
+  connection_name: "file.host(1414)"
+`), 0644))
+
+	t.Setenv("IBMMQ_QUEUE_MANAGER", "ENVQM")
+	t.Setenv("IBMMQ_CHANNEL", "ENV.SVRCONN")
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ENVQM", cfg.MQ.QueueManager)
+	assert.Equal(t, "ENV.SVRCONN", cfg.MQ.Channel)
+	assert.Equal(t, "file.host(1414)", cfg.MQ.ConnectionName)
+
+	assert.Equal(t, configPath, configSource(configPath))
+	assert.Contains(t, configSource(""), "IBMMQ_*")
+}
+
+func TestDumperLimitPerQueue(t *testing.T) {
+	writer, err := newCaptureWriter(t.TempDir(), "TESTQM")
+	require.NoError(t, err)
+
+	d := &dumper{filter: &messageFilter{}, limit: 1, capture: writer}
+	msg := &mqclient.MQMessage{
+		Type: "stats",
+		Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).Bytes(),
+	}
+
+	assert.True(t, d.dump("Statistics", "STATS.Q", msg, time.Time{}))
+	assert.False(t, d.dump("Statistics", "STATS.Q", msg, time.Now()), "the limit is reached for this queue")
+	assert.True(t, d.dump("Accounting", "ACCT.Q", msg, time.Now()), "each queue has its own limit")
+
+	assert.Len(t, writer.manifest.Messages, 2, "only dumped messages are saved")
+}
@@ -6,27 +6,41 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/api"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/servertls"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/sirupsen/logrus"
+)
+
+// Readiness states reported by the /ready endpoint
+const (
+	ReadinessStarting       = "starting"
+	ReadinessWaitingForData = "waiting_for_data"
+	ReadinessReady          = "ready"
 )
 
 // OTelProvider manages OpenTelemetry metrics provider and Prometheus exporter
 // For now, this is a simplified version that focuses on Prometheus integration
 type OTelProvider struct {
-	config   *config.Config
-	logger   *logrus.Logger
-	registry *prometheus.Registry
-	server   *http.Server
+	config    *config.Config
+	logger    logging.Logger
+	registry  *prometheus.Registry
+	gatherers prometheus.Gatherers
+	server    *http.Server
+	readiness func() string
+	handlers  map[string]http.Handler
 }
 
 // NewOTelProvider creates a new OpenTelemetry provider
-func NewOTelProvider(cfg *config.Config, logger *logrus.Logger) (*OTelProvider, error) {
+func NewOTelProvider(cfg *config.Config, logger logging.Logger) (*OTelProvider, error) {
+	registry := prometheus.NewRegistry()
 	provider := &OTelProvider{
-		config:   cfg,
-		logger:   logger,
-		registry: prometheus.NewRegistry(),
+		config:    cfg,
+		logger:    logger,
+		registry:  registry,
+		gatherers: prometheus.Gatherers{registry},
 	}
 
 	logger.Info("OpenTelemetry provider initialized successfully")
@@ -38,23 +52,49 @@ func (p *OTelProvider) StartHTTPServer(ctx context.Context) error {
 	addr := fmt.Sprintf(":%d", p.config.Prometheus.Port)
 
 	mux := http.NewServeMux()
-	mux.Handle(p.config.Prometheus.Path, promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{}))
+	// OpenMetrics carries the cycle IDs of the error counters as exemplars, for scrapers
+	// that ask for it
+	metrics := promhttp.HandlerFor(p.gatherers, promhttp.HandlerOpts{EnableOpenMetrics: true})
+	if p.config.Prometheus.Auth.Metrics {
+		metrics = api.RequireToken(p.config.Prometheus.Auth.Tokens, metrics)
+	}
+	mux.Handle(p.config.Prometheus.Path, metrics)
 	mux.HandleFunc("/health", p.healthHandler)
 	mux.HandleFunc("/ready", p.readyHandler)
+	for pattern, handler := range p.handlers {
+		mux.Handle(pattern, handler)
+	}
 
 	p.server = &http.Server{
 		Addr:    addr,
 		Handler: mux,
 	}
 
-	p.logger.WithFields(logrus.Fields{
-		"address": addr,
-		"path":    p.config.Prometheus.Path,
+	tlsSettings := p.config.Prometheus.TLS
+	if tlsSettings.Enabled() {
+		tlsConfig, err := servertls.New(&tlsSettings, p.config.FIPS)
+		if err != nil {
+			return fmt.Errorf("failed to configure metrics server TLS: %w", err)
+		}
+		p.server.TLSConfig = tlsConfig
+	}
+
+	p.logger.WithFields(logging.Fields{
+		"address":     addr,
+		"path":        p.config.Prometheus.Path,
+		"tls":         tlsSettings.Enabled(),
+		"client_auth": tlsSettings.ClientCAFile != "",
+		"token_auth":  p.config.Prometheus.Auth.Enabled(),
 	}).Info("Starting Prometheus metrics HTTP server")
 
 	// Start server in a goroutine
 	go func() {
-		if err := p.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		serve := p.server.ListenAndServe
+		if p.server.TLSConfig != nil {
+			// The certificate is already in TLSConfig
+			serve = func() error { return p.server.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); err != nil && err != http.ErrServerClosed {
 			p.logger.WithError(err).Error("Prometheus HTTP server failed")
 		}
 	}()
@@ -82,17 +122,26 @@ func (p *OTelProvider) healthHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, `{"status":"healthy","timestamp":"%s"}`, time.Now().Format(time.RFC3339))
 }
 
-// readyHandler returns readiness status
+// readyHandler returns readiness status, answering 503 until the collector reports ready
 func (p *OTelProvider) readyHandler(w http.ResponseWriter, r *http.Request) {
+	status := ReadinessReady
+	if p.readiness != nil {
+		status = p.readiness()
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, `{"status":"ready","timestamp":"%s"}`, time.Now().Format(time.RFC3339))
+	if status == ReadinessReady {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	fmt.Fprintf(w, `{"status":"%s","timestamp":"%s"}`, status, time.Now().Format(time.RFC3339))
 }
 
 // RecordQueueMetrics records queue-related metrics (simplified version)
 func (p *OTelProvider) RecordQueueMetrics(ctx context.Context, queueManager, queueName string, depth, enqCount, deqCount int64) {
 	// For now, this is a no-op - metrics are handled by the Prometheus collector
-	p.logger.WithFields(logrus.Fields{
+	p.logger.WithFields(logging.Fields{
 		"queue_manager": queueManager,
 		"queue_name":    queueName,
 		"depth":         depth,
@@ -103,7 +152,7 @@ func (p *OTelProvider) RecordQueueMetrics(ctx context.Context, queueManager, que
 
 // RecordChannelMetrics records channel-related metrics (simplified version)
 func (p *OTelProvider) RecordChannelMetrics(ctx context.Context, queueManager, channelName, connectionName string, messages, bytes int64) {
-	p.logger.WithFields(logrus.Fields{
+	p.logger.WithFields(logging.Fields{
 		"queue_manager":   queueManager,
 		"channel_name":    channelName,
 		"connection_name": connectionName,
@@ -114,7 +163,7 @@ func (p *OTelProvider) RecordChannelMetrics(ctx context.Context, queueManager, c
 
 // RecordMQIMetrics records MQI operation metrics (simplified version)
 func (p *OTelProvider) RecordMQIMetrics(ctx context.Context, queueManager, appName, operation string, count int64) {
-	p.logger.WithFields(logrus.Fields{
+	p.logger.WithFields(logging.Fields{
 		"queue_manager":    queueManager,
 		"application_name": appName,
 		"operation":        operation,
@@ -127,6 +176,26 @@ func (p *OTelProvider) GetRegistry() *prometheus.Registry {
 	return p.registry
 }
 
+// AddGatherer exposes an additional registry on the metrics endpoint.
+// Must be called before StartHTTPServer.
+func (p *OTelProvider) AddGatherer(g prometheus.Gatherer) {
+	p.gatherers = append(p.gatherers, g)
+}
+
+// Handle registers an additional handler on the HTTP server; call it before StartHTTPServer
+func (p *OTelProvider) Handle(pattern string, handler http.Handler) {
+	if p.handlers == nil {
+		p.handlers = make(map[string]http.Handler)
+	}
+	p.handlers[pattern] = handler
+}
+
+// SetReadiness sets the function the /ready endpoint uses to report the collector state.
+// Must be called before StartHTTPServer.
+func (p *OTelProvider) SetReadiness(fn func() string) {
+	p.readiness = fn
+}
+
 // Shutdown gracefully shuts down the OTel provider
 func (p *OTelProvider) Shutdown(ctx context.Context) error {
 	p.logger.Info("Shutting down OpenTelemetry provider")
@@ -0,0 +1,208 @@
+//go:build integration
+
+package testharness
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultImage is the IBM MQ developer image run unless IBMMQ_TEST_IMAGE names another
+const DefaultImage = "icr.io/ibm-messaging/mq:latest"
+
+const (
+	queueManagerName = "QM1"
+	adminPassword    = "passw0rd"
+	startTimeout     = 3 * time.Minute
+)
+
+// QueueManager is a queue manager of the IBM MQ developer image running in a container
+// for the length of a test. The developer configuration provides the DEV.ADMIN.SVRCONN
+// channel and the DEV.QUEUE.1 to DEV.QUEUE.3 local queues.
+type QueueManager struct {
+	Name           string
+	ConnectionName string // host(port) of the published listener port
+
+	t         testing.TB
+	container string
+}
+
+// Start runs a queue manager container and waits until it accepts client connections.
+// The container is removed when the test ends. The test is skipped when Docker is not
+// available.
+func Start(t testing.TB) *QueueManager {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker is not available")
+	}
+
+	image := os.Getenv("IBMMQ_TEST_IMAGE")
+	if image == "" {
+		image = DefaultImage
+	}
+
+	qm := &QueueManager{Name: queueManagerName, t: t}
+	qm.container = qm.docker(nil, "run", "--detach", "--rm",
+		"--env", "LICENSE=accept",
+		"--env", "MQ_QMGR_NAME="+queueManagerName,
+		"--env", "MQ_ADMIN_PASSWORD="+adminPassword,
+		"--env", "MQ_APP_PASSWORD="+adminPassword,
+		"--publish", "127.0.0.1::1414",
+		image)
+	t.Cleanup(func() {
+		exec.Command("docker", "rm", "--force", qm.container).Run()
+	})
+
+	// docker port prints one line per address, such as 127.0.0.1:49153
+	address := strings.Fields(qm.docker(nil, "port", qm.container, "1414/tcp"))[0]
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatalf("unexpected published port %q: %v", address, err)
+	}
+	qm.ConnectionName = fmt.Sprintf("%s(%s)", host, port)
+
+	qm.waitRunning(address)
+	return qm
+}
+
+// waitRunning waits until the queue manager is running and its listener accepts
+// connections
+func (qm *QueueManager) waitRunning(address string) {
+	qm.t.Helper()
+
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		out, err := exec.Command("docker", "exec", qm.container, "dspmq", "-m", qm.Name).Output()
+		if err == nil && strings.Contains(string(out), "STATUS(Running)") {
+			if conn, err := net.DialTimeout("tcp", address, time.Second); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		time.Sleep(time.Second)
+	}
+
+	logs, _ := exec.Command("docker", "logs", "--tail", "50", qm.container).CombinedOutput()
+	qm.t.Fatalf("queue manager did not start within %s:\n%s", startTimeout, logs)
+}
+
+// Config returns a collector configuration connecting to the queue manager as its
+// administrator, who may get the statistics and accounting messages
+func (qm *QueueManager) Config() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.MQ.QueueManager = qm.Name
+	cfg.MQ.Channel = "DEV.ADMIN.SVRCONN"
+	cfg.MQ.ConnectionName = qm.ConnectionName
+	cfg.MQ.User = "admin"
+	cfg.MQ.Password = adminPassword
+	cfg.Collector.StatsQueue = "SYSTEM.ADMIN.STATISTICS.QUEUE"
+	cfg.Collector.AccountingQueue = "SYSTEM.ADMIN.ACCOUNTING.QUEUE"
+	return cfg
+}
+
+// Client returns a client connected to the queue manager, disconnected when the test ends
+func (qm *QueueManager) Client() *mqclient.MQClient {
+	qm.t.Helper()
+
+	cfg := qm.Config()
+	client := mqclient.NewMQClient(&cfg.MQ, Logger())
+	if err := client.Connect(); err != nil {
+		qm.t.Fatalf("failed to connect to %s: %v", qm.ConnectionName, err)
+	}
+	qm.t.Cleanup(func() { client.Disconnect() })
+	return client
+}
+
+// MQSC runs MQSC commands against the queue manager and returns their output
+func (qm *QueueManager) MQSC(commands ...string) string {
+	qm.t.Helper()
+	script := strings.Join(commands, "\n") + "\n"
+	return qm.docker(strings.NewReader(script), "exec", "--interactive", qm.container, "runmqsc", qm.Name)
+}
+
+// EnableMonitoring switches on queue, MQI and channel statistics and MQI and queue
+// accounting, written every second
+func (qm *QueueManager) EnableMonitoring() {
+	qm.t.Helper()
+	qm.MQSC("ALTER QMGR STATQ(ON) STATMQI(ON) STATCHL(LOW) STATINT(1) ACCTQ(ON) ACCTMQI(ON) ACCTINT(1)")
+}
+
+// FlushStatistics makes the queue manager write the statistics collected so far
+// without waiting for the end of the interval
+func (qm *QueueManager) FlushStatistics() {
+	qm.t.Helper()
+	qm.MQSC("RESET QMGR TYPE(STATISTICS)")
+}
+
+// GenerateTraffic puts messages to a queue and gets them again, on a connection of its
+// own. Disconnecting makes the queue manager write the accounting of the connection.
+func (qm *QueueManager) GenerateTraffic(queueName string, messages int) {
+	qm.t.Helper()
+
+	cfg := qm.Config()
+	client := mqclient.NewMQClient(&cfg.MQ, Logger())
+	if err := client.Connect(); err != nil {
+		qm.t.Fatalf("failed to connect to %s: %v", qm.ConnectionName, err)
+	}
+	defer client.Disconnect()
+
+	for i := 0; i < messages; i++ {
+		msg := &mqclient.MQMessage{Data: []byte(fmt.Sprintf("test message %d", i))}
+		if err := client.PutQueueMessage(queueName, msg); err != nil {
+			qm.t.Fatalf("failed to put message %d: %v", i, err)
+		}
+	}
+
+	if err := client.OpenQueue(queueName, false); err != nil {
+		qm.t.Fatal(err)
+	}
+	for i := 0; i < messages; i++ {
+		msg, err := client.GetQueueMessage(queueName, time.Second)
+		if err != nil {
+			qm.t.Fatalf("failed to get message %d: %v", i, err)
+		}
+		if msg == nil {
+			qm.t.Fatalf("got %d of the %d messages put", i, messages)
+		}
+	}
+}
+
+// docker runs a docker command with the input, if any, and returns its trimmed output,
+// failing the test when it fails
+func (qm *QueueManager) docker(input *strings.Reader, args ...string) string {
+	qm.t.Helper()
+
+	cmd := exec.Command("docker", args...)
+	if input != nil {
+		cmd.Stdin = input
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		qm.t.Fatalf("docker %s failed: %v\n%s%s", args[0], err, out, stderr.Bytes())
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Logger returns the logger of the harness and the clients it creates, logging warnings
+// and errors only unless IBMMQ_TEST_DEBUG is set
+func Logger() logging.Logger {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	if os.Getenv("IBMMQ_TEST_DEBUG") != "" {
+		logger.SetLevel(logrus.DebugLevel)
+	}
+	return logging.NewLogrus(logger)
+}
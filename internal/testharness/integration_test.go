@@ -0,0 +1,101 @@
+//go:build integration
+
+package testharness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drain gets the statistics and accounting messages until both queues have delivered
+// some, as the queue manager writes them asynchronously
+func drain(t *testing.T, client *mqclient.MQClient) (stats, accounting []*mqclient.MQMessage) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Minute)
+	for len(stats) == 0 || len(accounting) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d statistics and %d accounting messages within a minute", len(stats), len(accounting))
+		}
+		s, a, err := client.GetStatisticsAndAccounting()
+		require.NoError(t, err)
+		stats = append(stats, s...)
+		accounting = append(accounting, a...)
+		time.Sleep(time.Second)
+	}
+	return stats, accounting
+}
+
+func TestStatisticsAndAccountingEndToEnd(t *testing.T) {
+	qm := Start(t)
+	qm.EnableMonitoring()
+	qm.GenerateTraffic("DEV.QUEUE.1", 25)
+	qm.FlushStatistics()
+
+	cfg := qm.Config()
+	client := qm.Client()
+	require.NoError(t, client.OpenStatsQueue(cfg.Collector.StatsQueue))
+	require.NoError(t, client.OpenAccountingQueue(cfg.Collector.AccountingQueue))
+	stats, accounting := drain(t, client)
+
+	// mqclient → pcf: every message parses, and the traffic shows in the accounting
+	parser := pcf.NewConcurrentParser(Logger(), 2)
+	for _, result := range parser.ParseMessages(mqclient.Payloads(stats), "statistics") {
+		require.NoError(t, result.Err)
+		data, ok := result.Data.(*pcf.StatisticsData)
+		require.True(t, ok)
+		assert.Equal(t, qm.Name, data.QueueManager)
+	}
+	var puts int64
+	for _, result := range parser.ParseMessages(mqclient.Payloads(accounting), "accounting") {
+		require.NoError(t, result.Err)
+		data, ok := result.Data.(*pcf.AccountingData)
+		require.True(t, ok)
+		if data.Operations != nil {
+			puts += data.Operations.Puts
+		}
+	}
+	assert.GreaterOrEqual(t, puts, int64(25))
+
+	// pcf → exporters: the Prometheus metrics of the queue manager are published
+	metrics := prometheus.NewMetricsCollector(cfg, client, logging.ToLogrus(Logger()))
+	metrics.ProcessMessages(stats, accounting)
+	assert.Zero(t, metrics.ParseErrors())
+
+	families, err := metrics.Gatherer().Gather()
+	require.NoError(t, err)
+	queueManagers := map[string]bool{}
+	var queueMetrics int
+	for _, family := range families {
+		if strings.HasPrefix(family.GetName(), "ibmmq_queue_") {
+			queueMetrics++
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "queue_manager" {
+					queueManagers[label.GetValue()] = true
+				}
+			}
+		}
+	}
+	assert.True(t, queueManagers[qm.Name])
+	assert.NotZero(t, queueMetrics)
+}
+
+func TestQueueManagerInquiry(t *testing.T) {
+	qm := Start(t)
+	qm.EnableMonitoring()
+
+	settings, err := qm.Client().InquireQueueManager()
+	require.NoError(t, err)
+	assert.True(t, mqclient.MonitoringEnabled(settings.StatisticsQueue))
+	assert.True(t, mqclient.MonitoringEnabled(settings.AccountingMQI))
+}
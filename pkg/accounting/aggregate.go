@@ -0,0 +1,193 @@
+package accounting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// Operations are the accounting counts of an application
+type Operations struct {
+	Connections int64 `json:"connections"`
+	Opens       int64 `json:"opens"`
+	Closes      int64 `json:"closes"`
+	Puts        int64 `json:"puts"`
+	Gets        int64 `json:"gets"`
+	Browses     int64 `json:"browses"`
+	Commits     int64 `json:"commits"`
+	Backouts    int64 `json:"backouts"`
+	PutBytes    int64 `json:"put_bytes"`
+	GetBytes    int64 `json:"get_bytes"`
+}
+
+// add counts one accounting record
+func (o *Operations) add(acct *pcf.AccountingData) {
+	o.Connections++
+	if ops := acct.Operations; ops != nil {
+		o.Opens += int64(ops.Opens)
+		o.Closes += int64(ops.Closes)
+		o.Puts += int64(ops.Puts)
+		o.Gets += int64(ops.Gets)
+		o.Browses += int64(ops.Browses)
+		o.Commits += int64(ops.Commits)
+		o.Backouts += int64(ops.Backouts)
+		o.PutBytes += ops.PutBytes
+		o.GetBytes += ops.GetBytes
+	}
+}
+
+// ApplicationTotals are the aggregated accounting counts of one application
+type ApplicationTotals struct {
+	QueueManager string `json:"queue_manager"`
+	Application  string `json:"application"`
+	// Interval holds the counts of the last collection cycle with records of the application
+	Interval Operations `json:"interval"`
+	// Total holds the counts of every record since the aggregates were started
+	Total    Operations `json:"total"`
+	LastSeen time.Time  `json:"last_seen"`
+}
+
+// applicationKey identifies an application on a queue manager
+type applicationKey struct {
+	queueManager string
+	application  string
+}
+
+// Aggregator keeps running totals of accounting operations per application across
+// collection cycles. A single accounting record only covers one connection for one
+// accounting interval, so the totals are what answers how much an application did.
+type Aggregator struct {
+	mu      sync.Mutex
+	apps    map[applicationKey]*ApplicationTotals
+	pending map[applicationKey]*Operations
+}
+
+// NewAggregator creates an empty aggregator
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		apps:    make(map[applicationKey]*ApplicationTotals),
+		pending: make(map[applicationKey]*Operations),
+	}
+}
+
+// Add counts an accounting record of the current cycle. Records without a queue
+// manager are counted under qmgr.
+func (a *Aggregator) Add(qmgr string, acct *pcf.AccountingData) {
+	if acct.QueueManager != "" {
+		qmgr = acct.QueueManager
+	}
+	application := ""
+	if acct.ConnectionInfo != nil {
+		application = acct.ConnectionInfo.ApplicationName
+	}
+	key := applicationKey{queueManager: qmgr, application: application}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	app := a.apps[key]
+	if app == nil {
+		app = &ApplicationTotals{QueueManager: qmgr, Application: application}
+		a.apps[key] = app
+	}
+	app.Total.add(acct)
+	app.LastSeen = time.Now()
+
+	ops := a.pending[key]
+	if ops == nil {
+		ops = &Operations{}
+		a.pending[key] = ops
+	}
+	ops.add(acct)
+}
+
+// EndCycle makes the counts added since the previous call the interval counts of their
+// applications; applications without records in the cycle keep their last interval
+func (a *Aggregator) EndCycle() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, ops := range a.pending {
+		a.apps[key].Interval = *ops
+	}
+	a.pending = make(map[applicationKey]*Operations)
+}
+
+// Applications returns a copy of the totals of every application, sorted by queue
+// manager and application
+func (a *Aggregator) Applications() []ApplicationTotals {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	apps := make([]ApplicationTotals, 0, len(a.apps))
+	for _, app := range a.apps {
+		apps = append(apps, *app)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].QueueManager != apps[j].QueueManager {
+			return apps[i].QueueManager < apps[j].QueueManager
+		}
+		return apps[i].Application < apps[j].Application
+	})
+	return apps
+}
+
+// Save writes the totals to path as JSON, replacing the file atomically
+func (a *Aggregator) Save(path string) error {
+	data, err := json.Marshal(map[string]interface{}{"applications": a.Applications()})
+	if err != nil {
+		return fmt.Errorf("failed to encode application aggregates: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save application aggregates: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save application aggregates: %w", err)
+	}
+	return nil
+}
+
+// Load replaces the totals with those saved in path. A missing file is not an error.
+func (a *Aggregator) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read application aggregates: %w", err)
+	}
+
+	var saved struct {
+		Applications []ApplicationTotals `json:"applications"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid application aggregates in %s: %w", path, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.apps = make(map[applicationKey]*ApplicationTotals, len(saved.Applications))
+	for i := range saved.Applications {
+		app := saved.Applications[i]
+		a.apps[applicationKey{queueManager: app.QueueManager, application: app.Application}] = &app
+	}
+	a.pending = make(map[applicationKey]*Operations)
+	return nil
+}
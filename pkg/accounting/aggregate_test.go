@@ -0,0 +1,75 @@
+package accounting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatorAcrossCycles(t *testing.T) {
+	now := time.Now()
+	aggregator := NewAggregator()
+
+	aggregator.Add("QM1", accountingRecord("orders", "app1", now, 10, 5, 1000))
+	aggregator.Add("QM1", accountingRecord("orders", "app2", now, 2, 0, 200))
+	aggregator.EndCycle()
+
+	aggregator.Add("QM1", accountingRecord("orders", "app1", now, 1, 1, 10))
+	aggregator.Add("QM1", accountingRecord("billing", "app3", now, 0, 3, 0))
+	aggregator.EndCycle()
+
+	// A cycle without records keeps the last intervals
+	aggregator.EndCycle()
+
+	apps := aggregator.Applications()
+	require.Len(t, apps, 2)
+	assert.Equal(t, "billing", apps[0].Application)
+
+	orders := apps[1]
+	assert.Equal(t, "QM1", orders.QueueManager)
+	assert.Equal(t, int64(3), orders.Total.Connections)
+	assert.Equal(t, int64(13), orders.Total.Puts)
+	assert.Equal(t, int64(1210), orders.Total.PutBytes)
+	assert.Equal(t, int64(1), orders.Interval.Connections)
+	assert.Equal(t, int64(1), orders.Interval.Puts)
+	assert.False(t, orders.LastSeen.IsZero())
+}
+
+func TestAggregatorRecordQueueManager(t *testing.T) {
+	aggregator := NewAggregator()
+	record := accountingRecord("orders", "app1", time.Now(), 1, 0, 0)
+	record.QueueManager = "QM2"
+	aggregator.Add("QM1", record)
+	aggregator.EndCycle()
+
+	apps := aggregator.Applications()
+	require.Len(t, apps, 1)
+	assert.Equal(t, "QM2", apps[0].QueueManager)
+}
+
+func TestAggregatorPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aggregates.json")
+
+	aggregator := NewAggregator()
+	require.NoError(t, aggregator.Load(path), "a missing file starts empty")
+	aggregator.Add("QM1", accountingRecord("orders", "app1", time.Now(), 10, 5, 1000))
+	aggregator.EndCycle()
+	require.NoError(t, aggregator.Save(path))
+
+	restored := NewAggregator()
+	require.NoError(t, restored.Load(path))
+	restored.Add("QM1", accountingRecord("orders", "app1", time.Now(), 1, 0, 0))
+	restored.EndCycle()
+
+	apps := restored.Applications()
+	require.Len(t, apps, 1)
+	assert.Equal(t, int64(11), apps[0].Total.Puts)
+	assert.Equal(t, int64(2), apps[0].Total.Connections)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	assert.Error(t, restored.Load(path))
+}
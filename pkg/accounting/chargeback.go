@@ -0,0 +1,301 @@
+package accounting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// Chargeback periods
+const (
+	PeriodDay   = "day"
+	PeriodMonth = "month"
+)
+
+// ChargebackRow is the usage of one application/user combination within a period
+type ChargebackRow struct {
+	*Usage
+	APICalls     int64   `json:"api_calls"`
+	MessageBytes int64   `json:"message_bytes"`
+	Share        float64 `json:"share"` // fraction of the period's API calls
+}
+
+// Period is the usage of every application/user combination in one day or month
+type Period struct {
+	Period string          `json:"period"` // 2026-03-14 or 2026-03
+	Start  time.Time       `json:"start"`
+	End    time.Time       `json:"end"`
+	Rows   []ChargebackRow `json:"rows"`
+	Total  ChargebackRow   `json:"total"`
+}
+
+// Chargeback aggregates accounting records by application and/or user separately for
+// every UTC day or month, as the basis for charging back or showing back MQ usage
+type Chargeback struct {
+	period  string
+	groupBy []string
+	since   time.Time
+	until   time.Time
+	reports map[string]*Report
+	records int
+	skipped int
+}
+
+// NewChargeback creates a chargeback report over daily or monthly periods. A zero since
+// or until leaves that end of the window open.
+func NewChargeback(period string, groupBy []string, since, until time.Time) (*Chargeback, error) {
+	if period != PeriodDay && period != PeriodMonth {
+		return nil, fmt.Errorf("invalid chargeback period: %s (use day or month)", period)
+	}
+	// Validates the grouping
+	if _, err := NewReport(groupBy, time.Time{}, time.Time{}); err != nil {
+		return nil, err
+	}
+
+	return &Chargeback{
+		period:  period,
+		groupBy: groupBy,
+		since:   since,
+		until:   until,
+		reports: make(map[string]*Report),
+	}, nil
+}
+
+// Add aggregates an accounting record into its period, returning false if it lies
+// outside the window
+func (c *Chargeback) Add(acct *pcf.AccountingData) bool {
+	if (!c.since.IsZero() && acct.Timestamp.Before(c.since)) || (!c.until.IsZero() && acct.Timestamp.After(c.until)) {
+		c.skipped++
+		return false
+	}
+
+	key, _, _ := c.bounds(acct.Timestamp)
+	report, ok := c.reports[key]
+	if !ok {
+		report, _ = NewReport(c.groupBy, time.Time{}, time.Time{})
+		c.reports[key] = report
+	}
+	report.Add(acct)
+	c.records++
+	return true
+}
+
+// ReadExport adds the accounting records from JSON lines written by the export command
+func (c *Chargeback) ReadExport(in io.Reader) error {
+	return readExport(in, c.Add)
+}
+
+// Records returns the number of records included in the report
+func (c *Chargeback) Records() int {
+	return c.records
+}
+
+// Skipped returns the number of records that fell outside the time window
+func (c *Chargeback) Skipped() int {
+	return c.skipped
+}
+
+// Periods returns the usage of each period, oldest first, with rows busiest first
+func (c *Chargeback) Periods() []Period {
+	keys := make([]string, 0, len(c.reports))
+	for key := range c.reports {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	periods := make([]Period, 0, len(keys))
+	for _, key := range keys {
+		usage := c.reports[key].Rows()
+
+		total := &Usage{}
+		for _, row := range usage {
+			total.Connections += row.Connections
+			total.Opens += row.Opens
+			total.Closes += row.Closes
+			total.Puts += row.Puts
+			total.Gets += row.Gets
+			total.Commits += row.Commits
+			total.Backouts += row.Backouts
+			total.PutBytes += row.PutBytes
+			total.GetBytes += row.GetBytes
+			if total.First.IsZero() || row.First.Before(total.First) {
+				total.First = row.First
+			}
+			if row.Last.After(total.Last) {
+				total.Last = row.Last
+			}
+		}
+
+		rows := make([]ChargebackRow, 0, len(usage))
+		for _, row := range usage {
+			rows = append(rows, chargebackRow(row, total.APICalls()))
+		}
+
+		_, start, end := c.bounds(usage[0].First)
+		periods = append(periods, Period{
+			Period: key,
+			Start:  start,
+			End:    end,
+			Rows:   rows,
+			Total:  chargebackRow(total, total.APICalls()),
+		})
+	}
+	return periods
+}
+
+// bounds returns the key, start and exclusive end of the period containing t
+func (c *Chargeback) bounds(t time.Time) (string, time.Time, time.Time) {
+	t = t.UTC()
+	if c.period == PeriodMonth {
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start.Format("2006-01"), start, start.AddDate(0, 1, 0)
+	}
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01-02"), start, start.AddDate(0, 0, 1)
+}
+
+// columns returns the header of the report table for the configured grouping
+func (c *Chargeback) columns() []string {
+	columns := append([]string{"period"}, c.groupBy...)
+	return append(columns, "connections", "api_calls", "api_call_share", "puts", "gets",
+		"put_bytes", "get_bytes", "message_bytes")
+}
+
+// values returns the cells of a row in column order
+func (c *Chargeback) values(period string, row ChargebackRow) []string {
+	values := append([]string{period}, c.groups(row)...)
+	return append(values,
+		itoa(row.Connections), itoa(row.APICalls), strconv.FormatFloat(row.Share*100, 'f', 1, 64),
+		itoa(row.Puts), itoa(row.Gets), itoa(row.PutBytes), itoa(row.GetBytes), itoa(row.MessageBytes))
+}
+
+// groups returns the group-by values of a row
+func (c *Chargeback) groups(row ChargebackRow) []string {
+	var values []string
+	for _, field := range c.groupBy {
+		switch field {
+		case GroupApplication:
+			values = append(values, row.Application)
+		case GroupUser:
+			values = append(values, row.User)
+		}
+	}
+	return values
+}
+
+// WriteText prints the report as an aligned table with a total line per period
+func (c *Chargeback) WriteText(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(c.columns(), "\t")))
+	for _, period := range c.Periods() {
+		for _, row := range period.Rows {
+			fmt.Fprintln(w, strings.Join(c.values(period.Period, row), "\t"))
+		}
+		total := c.values(period.Period, period.Total)
+		for i := range c.groupBy {
+			total[i+1] = "TOTAL"
+		}
+		fmt.Fprintln(w, strings.Join(total, "\t"))
+	}
+	return w.Flush()
+}
+
+// WriteCSV writes one row per period and application/user with a header row
+func (c *Chargeback) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(c.columns()); err != nil {
+		return err
+	}
+	for _, period := range c.Periods() {
+		for _, row := range period.Rows {
+			if err := w.Write(c.values(period.Period, row)); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+var chargebackTemplate = template.Must(template.New("chargeback").Funcs(template.FuncMap{
+	"bytes":   formatBytes,
+	"percent": func(share float64) string { return strconv.FormatFloat(share*100, 'f', 1, 64) + "%" },
+	"title":   func(s string) string { return strings.ToUpper(s[:1]) + s[1:] },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>IBM MQ usage by {{.Period}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #ddd; }
+th { background: #f3f3f3; text-align: left; }
+td.n { text-align: right; font-variant-numeric: tabular-nums; }
+tfoot td { font-weight: bold; border-top: 2px solid #999; }
+</style>
+</head>
+<body>
+<h1>IBM MQ usage by {{.Period}}</h1>
+<p>{{.Records}} accounting records.</p>
+{{range .Periods}}
+<h2>{{.Period}}</h2>
+<table>
+<thead><tr>{{range $.GroupBy}}<th>{{title .}}</th>{{end}}<th>Connections</th><th>API calls</th><th>Share</th><th>Puts</th><th>Gets</th><th>Put bytes</th><th>Get bytes</th><th>Message bytes</th></tr></thead>
+<tbody>
+{{range .Rows}}<tr>{{range call $.Groups .}}<td>{{.}}</td>{{end}}<td class="n">{{.Connections}}</td><td class="n">{{.APICalls}}</td><td class="n">{{percent .Share}}</td><td class="n">{{.Puts}}</td><td class="n">{{.Gets}}</td><td class="n">{{bytes .PutBytes}}</td><td class="n">{{bytes .GetBytes}}</td><td class="n">{{bytes .MessageBytes}}</td></tr>
+{{end}}</tbody>
+{{with .Total}}<tfoot><tr><td colspan="{{len $.GroupBy}}">Total</td><td class="n">{{.Connections}}</td><td class="n">{{.APICalls}}</td><td class="n">{{percent .Share}}</td><td class="n">{{.Puts}}</td><td class="n">{{.Gets}}</td><td class="n">{{bytes .PutBytes}}</td><td class="n">{{bytes .GetBytes}}</td><td class="n">{{bytes .MessageBytes}}</td></tr></tfoot>{{end}}
+</table>
+{{else}}
+<p>No accounting records.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML renders the report as a standalone HTML page with one table per period
+func (c *Chargeback) WriteHTML(out io.Writer) error {
+	return chargebackTemplate.Execute(out, map[string]interface{}{
+		"Period":  c.period,
+		"Records": c.records,
+		"GroupBy": c.groupBy,
+		"Groups":  c.groups,
+		"Periods": c.Periods(),
+	})
+}
+
+// chargebackRow derives the totals of a usage row and its share of the period's API calls
+func chargebackRow(usage *Usage, periodCalls int64) ChargebackRow {
+	row := ChargebackRow{
+		Usage:        usage,
+		APICalls:     usage.APICalls(),
+		MessageBytes: usage.PutBytes + usage.GetBytes,
+	}
+	if periodCalls > 0 {
+		row.Share = float64(row.APICalls) / float64(periodCalls)
+	}
+	return row
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
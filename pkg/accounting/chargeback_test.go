@@ -0,0 +1,97 @@
+package accounting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargebackDailyPeriods(t *testing.T) {
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	chargeback, err := NewChargeback(PeriodDay, []string{GroupApplication}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	chargeback.Add(accountingRecord("orders", "app1", day.Add(time.Hour), 10, 0, 1000))
+	chargeback.Add(accountingRecord("orders", "app2", day.Add(23*time.Hour), 4, 4, 500))
+	chargeback.Add(accountingRecord("billing", "app3", day.Add(2*time.Hour), 0, 0, 0))
+	chargeback.Add(accountingRecord("orders", "app1", day.Add(25*time.Hour), 1, 0, 10))
+
+	periods := chargeback.Periods()
+	require.Len(t, periods, 2)
+
+	first := periods[0]
+	assert.Equal(t, "2026-03-14", first.Period)
+	assert.Equal(t, day, first.Start)
+	assert.Equal(t, day.AddDate(0, 0, 1), first.End)
+	require.Len(t, first.Rows, 2)
+
+	orders := first.Rows[0]
+	assert.Equal(t, "orders", orders.Application)
+	assert.Equal(t, int64(2), orders.Connections)
+	assert.Equal(t, int64(22), orders.APICalls)
+	assert.Equal(t, int64(1500), orders.MessageBytes)
+	assert.InDelta(t, 22.0/24.0, orders.Share, 0.0001)
+
+	assert.Equal(t, int64(3), first.Total.Connections)
+	assert.Equal(t, int64(24), first.Total.APICalls)
+	assert.Equal(t, 1.0, first.Total.Share)
+
+	assert.Equal(t, "2026-03-15", periods[1].Period)
+	assert.Equal(t, 4, chargeback.Records())
+}
+
+func TestChargebackMonthlyWindow(t *testing.T) {
+	since := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	chargeback, err := NewChargeback(PeriodMonth, []string{GroupApplication, GroupUser}, since, time.Time{})
+	require.NoError(t, err)
+
+	assert.False(t, chargeback.Add(accountingRecord("orders", "app1", since.Add(-time.Hour), 1, 0, 0)))
+	assert.True(t, chargeback.Add(accountingRecord("orders", "app1", since.AddDate(0, 0, 27), 1, 0, 0)))
+	assert.True(t, chargeback.Add(accountingRecord("orders", "app1", since.AddDate(0, 1, 0), 1, 0, 0)))
+
+	periods := chargeback.Periods()
+	require.Len(t, periods, 2)
+	assert.Equal(t, "2026-02", periods[0].Period)
+	assert.Equal(t, since.AddDate(0, 1, 0), periods[0].End)
+	assert.Equal(t, "2026-03", periods[1].Period)
+	assert.Equal(t, 1, chargeback.Skipped())
+
+	_, err = NewChargeback("week", []string{GroupApplication}, time.Time{}, time.Time{})
+	assert.Error(t, err)
+	_, err = NewChargeback(PeriodDay, nil, time.Time{}, time.Time{})
+	assert.Error(t, err)
+}
+
+func TestChargebackOutput(t *testing.T) {
+	at := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	chargeback, err := NewChargeback(PeriodDay, []string{GroupApplication, GroupUser}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	chargeback.Add(accountingRecord("orders", "app1", at, 10, 5, 2048))
+	chargeback.Add(accountingRecord("<script>", "app2", at, 1, 0, 0))
+
+	var csvOut strings.Builder
+	require.NoError(t, chargeback.WriteCSV(&csvOut))
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "period,application,user,connections,api_calls,api_call_share,puts,gets,put_bytes,get_bytes,message_bytes", lines[0])
+	assert.Equal(t, "2026-03-14,orders,app1,1,17,85.0,10,5,2048,0,2048", lines[1])
+
+	var textOut strings.Builder
+	require.NoError(t, chargeback.WriteText(&textOut))
+	assert.Contains(t, textOut.String(), "TOTAL")
+
+	var htmlOut strings.Builder
+	require.NoError(t, chargeback.WriteHTML(&htmlOut))
+	page := htmlOut.String()
+	assert.Contains(t, page, "<h2>2026-03-14</h2>")
+	assert.Contains(t, page, "<th>Application</th><th>User</th>")
+	assert.Contains(t, page, "<td>orders</td><td>app1</td>")
+	assert.Contains(t, page, "2.0 KiB")
+	assert.Contains(t, page, "85.0%")
+	assert.Contains(t, page, "&lt;script&gt;")
+	assert.NotContains(t, page, "<td><script>")
+}
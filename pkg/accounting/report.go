@@ -0,0 +1,254 @@
+package accounting
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// Fields a usage report can be grouped by
+const (
+	GroupApplication = "application"
+	GroupUser        = "user"
+)
+
+// Usage is the aggregated MQI activity of one application/user combination
+type Usage struct {
+	Application string    `json:"application,omitempty"`
+	User        string    `json:"user,omitempty"`
+	Connections int64     `json:"connections"`
+	Opens       int64     `json:"opens"`
+	Closes      int64     `json:"closes"`
+	Puts        int64     `json:"puts"`
+	Gets        int64     `json:"gets"`
+	Commits     int64     `json:"commits"`
+	Backouts    int64     `json:"backouts"`
+	PutBytes    int64     `json:"put_bytes"`
+	GetBytes    int64     `json:"get_bytes"`
+	First       time.Time `json:"first"`
+	Last        time.Time `json:"last"`
+}
+
+// APICalls returns the total number of MQI calls counted for the usage row
+func (u *Usage) APICalls() int64 {
+	return u.Opens + u.Closes + u.Puts + u.Gets + u.Commits + u.Backouts
+}
+
+// Report aggregates accounting records within a time window. Each accounting record
+// counts as one connection; long-running connections write one record per ACCTINT.
+type Report struct {
+	groupBy []string
+	since   time.Time
+	until   time.Time
+	usage   map[string]*Usage
+	records int
+	skipped int
+}
+
+// NewReport creates a report grouped by the given fields. A zero since or until leaves
+// that end of the window open.
+func NewReport(groupBy []string, since, until time.Time) (*Report, error) {
+	if len(groupBy) == 0 {
+		return nil, fmt.Errorf("at least one group-by field is required")
+	}
+	for _, field := range groupBy {
+		switch field {
+		case GroupApplication, GroupUser:
+		default:
+			return nil, fmt.Errorf("invalid group-by field: %s (use application or user)", field)
+		}
+	}
+
+	return &Report{
+		groupBy: groupBy,
+		since:   since,
+		until:   until,
+		usage:   make(map[string]*Usage),
+	}, nil
+}
+
+// Add aggregates an accounting record, returning false if it lies outside the window
+func (r *Report) Add(acct *pcf.AccountingData) bool {
+	if (!r.since.IsZero() && acct.Timestamp.Before(r.since)) || (!r.until.IsZero() && acct.Timestamp.After(r.until)) {
+		r.skipped++
+		return false
+	}
+
+	var application, user string
+	if info := acct.ConnectionInfo; info != nil {
+		application = info.ApplicationName
+		user = info.UserIdentifier
+	}
+
+	row := &Usage{}
+	var keyParts []string
+	for _, field := range r.groupBy {
+		switch field {
+		case GroupApplication:
+			row.Application = valueOrUnknown(application)
+			keyParts = append(keyParts, row.Application)
+		case GroupUser:
+			row.User = valueOrUnknown(user)
+			keyParts = append(keyParts, row.User)
+		}
+	}
+
+	key := strings.Join(keyParts, "\x00")
+	if existing, ok := r.usage[key]; ok {
+		row = existing
+	} else {
+		r.usage[key] = row
+	}
+
+	row.Connections++
+	if ops := acct.Operations; ops != nil {
+		row.Opens += int64(ops.Opens)
+		row.Closes += int64(ops.Closes)
+		row.Puts += int64(ops.Puts)
+		row.Gets += int64(ops.Gets)
+		row.Commits += int64(ops.Commits)
+		row.Backouts += int64(ops.Backouts)
+		row.PutBytes += ops.PutBytes
+		row.GetBytes += ops.GetBytes
+	}
+	if row.First.IsZero() || acct.Timestamp.Before(row.First) {
+		row.First = acct.Timestamp
+	}
+	if acct.Timestamp.After(row.Last) {
+		row.Last = acct.Timestamp
+	}
+
+	r.records++
+	return true
+}
+
+// Records returns the number of records included in the report
+func (r *Report) Records() int {
+	return r.records
+}
+
+// Skipped returns the number of records that fell outside the time window
+func (r *Report) Skipped() int {
+	return r.skipped
+}
+
+// Rows returns the usage rows, busiest first
+func (r *Report) Rows() []*Usage {
+	rows := make([]*Usage, 0, len(r.usage))
+	for _, row := range r.usage {
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].APICalls() != rows[j].APICalls() {
+			return rows[i].APICalls() > rows[j].APICalls()
+		}
+		if rows[i].Application != rows[j].Application {
+			return rows[i].Application < rows[j].Application
+		}
+		return rows[i].User < rows[j].User
+	})
+	return rows
+}
+
+// columns returns the header of the report table for the configured grouping
+func (r *Report) columns() []string {
+	columns := append([]string{}, r.groupBy...)
+	return append(columns, "connections", "api_calls", "opens", "closes", "puts", "gets",
+		"commits", "backouts", "put_bytes", "get_bytes", "first", "last")
+}
+
+// values returns the cells of a row in column order
+func (r *Report) values(row *Usage) []string {
+	var values []string
+	for _, field := range r.groupBy {
+		switch field {
+		case GroupApplication:
+			values = append(values, row.Application)
+		case GroupUser:
+			values = append(values, row.User)
+		}
+	}
+	return append(values,
+		itoa(row.Connections), itoa(row.APICalls()), itoa(row.Opens), itoa(row.Closes),
+		itoa(row.Puts), itoa(row.Gets), itoa(row.Commits), itoa(row.Backouts),
+		itoa(row.PutBytes), itoa(row.GetBytes),
+		row.First.UTC().Format(time.RFC3339), row.Last.UTC().Format(time.RFC3339))
+}
+
+// WriteText prints the report as an aligned table
+func (r *Report) WriteText(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(r.columns(), "\t")))
+	for _, row := range r.Rows() {
+		fmt.Fprintln(w, strings.Join(r.values(row), "\t"))
+	}
+	return w.Flush()
+}
+
+// WriteCSV writes the report as CSV with a header row
+func (r *Report) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(r.columns()); err != nil {
+		return err
+	}
+	for _, row := range r.Rows() {
+		if err := w.Write(r.values(row)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// ReadExport adds the accounting records from JSON lines written by the export command
+// to the report. Statistics records in the same stream are ignored.
+func (r *Report) ReadExport(in io.Reader) error {
+	return readExport(in, r.Add)
+}
+
+// readExport passes each accounting record of an export stream to add
+func readExport(in io.Reader, add func(*pcf.AccountingData) bool) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var acct pcf.AccountingData
+		if err := json.Unmarshal([]byte(text), &acct); err != nil {
+			return fmt.Errorf("invalid record on line %d: %w", line, err)
+		}
+		if acct.Type != "accounting" {
+			continue
+		}
+		add(&acct)
+	}
+
+	return scanner.Err()
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "(unknown)"
+	}
+	return s
+}
+
+func itoa(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
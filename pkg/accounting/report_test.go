@@ -0,0 +1,104 @@
+package accounting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accountingRecord(app, user string, at time.Time, puts, gets int64, putBytes int64) *pcf.AccountingData {
+	return &pcf.AccountingData{
+		Type:           "accounting",
+		Timestamp:      at,
+		ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: app, UserIdentifier: user},
+		Operations:     &pcf.OperationCounts{Opens: 1, Closes: 1, Puts: puts, Gets: gets, PutBytes: putBytes},
+	}
+}
+
+func TestReportAggregation(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	report, err := NewReport([]string{GroupApplication, GroupUser}, base, time.Time{})
+	require.NoError(t, err)
+
+	assert.True(t, report.Add(accountingRecord("orders", "app1", base.Add(time.Minute), 10, 5, 1000)))
+	assert.True(t, report.Add(accountingRecord("orders", "app1", base.Add(2*time.Minute), 20, 0, 3000)))
+	assert.True(t, report.Add(accountingRecord("billing", "", base.Add(time.Minute), 1, 1, 10)))
+	assert.False(t, report.Add(accountingRecord("orders", "app1", base.Add(-time.Minute), 99, 99, 99)), "records before the window are skipped")
+
+	assert.Equal(t, 3, report.Records())
+	assert.Equal(t, 1, report.Skipped())
+
+	rows := report.Rows()
+	require.Len(t, rows, 2)
+
+	orders := rows[0]
+	assert.Equal(t, "orders", orders.Application)
+	assert.Equal(t, "app1", orders.User)
+	assert.Equal(t, int64(2), orders.Connections)
+	assert.Equal(t, int64(30), orders.Puts)
+	assert.Equal(t, int64(39), orders.APICalls())
+	assert.Equal(t, int64(4000), orders.PutBytes)
+	assert.Equal(t, base.Add(time.Minute), orders.First)
+	assert.Equal(t, base.Add(2*time.Minute), orders.Last)
+
+	assert.Equal(t, "(unknown)", rows[1].User)
+}
+
+func TestReportGroupByApplication(t *testing.T) {
+	now := time.Now()
+	report, err := NewReport([]string{GroupApplication}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	report.Add(accountingRecord("orders", "app1", now, 1, 0, 0))
+	report.Add(accountingRecord("orders", "app2", now, 1, 0, 0))
+
+	rows := report.Rows()
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(2), rows[0].Connections)
+	assert.Empty(t, rows[0].User)
+
+	_, err = NewReport([]string{"channel"}, time.Time{}, time.Time{})
+	assert.Error(t, err)
+}
+
+func TestReportOutput(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	report, err := NewReport([]string{GroupApplication, GroupUser}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	report.Add(accountingRecord("orders", "app1", at, 10, 5, 1000))
+
+	var csvOut strings.Builder
+	require.NoError(t, report.WriteCSV(&csvOut))
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "application,user,connections,api_calls"))
+	assert.Equal(t, "orders,app1,1,17,1,1,10,5,0,0,1000,0,2024-03-01T12:00:00Z,2024-03-01T12:00:00Z", lines[1])
+
+	var textOut strings.Builder
+	require.NoError(t, report.WriteText(&textOut))
+	assert.Contains(t, textOut.String(), "APPLICATION")
+	assert.Contains(t, textOut.String(), "orders")
+}
+
+func TestReportReadExport(t *testing.T) {
+	input := `{"type":"statistics","queue_manager":"QM1","timestamp":"2024-03-01T12:00:00Z","parameters":{}}
+{"type":"accounting","queue_manager":"QM1","timestamp":"2024-03-01T12:00:00Z","parameters":{},"connection_info":{"channel_name":"","connection_name":"","application_name":"orders","user_identifier":"app1","connect_time":"0001-01-01T00:00:00Z","disconnect_time":"0001-01-01T00:00:00Z"},"operations":{"gets":2,"puts":3,"browses":0,"opens":1,"closes":1,"commits":0,"backouts":0,"put_bytes":300,"get_bytes":200}}
+
+`
+	report, err := NewReport([]string{GroupApplication}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.NoError(t, report.ReadExport(strings.NewReader(input)))
+
+	rows := report.Rows()
+	require.Len(t, rows, 1)
+	assert.Equal(t, "orders", rows[0].Application)
+	assert.Equal(t, int64(3), rows[0].Puts)
+	assert.Equal(t, int64(200), rows[0].GetBytes)
+
+	assert.Error(t, report.ReadExport(strings.NewReader("not json\n")))
+}
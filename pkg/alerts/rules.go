@@ -0,0 +1,349 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+)
+
+// Rule types
+const (
+	TypeQueueDepth = "queue_depth"
+	TypeNoReaders  = "no_readers"
+	TypeBackouts   = "backouts"
+	TypeNoConsumer = "no_consumer"
+)
+
+// Alert states
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Alert is one notification about a rule and object changing state
+type Alert struct {
+	Rule         string    `json:"rule"`
+	Type         string    `json:"type"`
+	Status       string    `json:"status"`
+	QueueManager string    `json:"queue_manager"`
+	Object       string    `json:"object"`
+	Value        int64     `json:"value"`
+	Threshold    int64     `json:"threshold"`
+	Intervals    int       `json:"intervals"`
+	Time         time.Time `json:"time"`
+}
+
+// Summary describes the alert in one line for chat messages
+func (a Alert) Summary() string {
+	var condition string
+	switch a.Type {
+	case TypeQueueDepth:
+		condition = fmt.Sprintf("queue %s on %s has depth %d (threshold %d)", a.Object, a.QueueManager, a.Value, a.Threshold)
+	case TypeNoReaders:
+		condition = fmt.Sprintf("queue %s on %s has no readers with %d messages waiting (threshold %d)", a.Object, a.QueueManager, a.Value, a.Threshold)
+	case TypeNoConsumer:
+		condition = fmt.Sprintf("queue %s on %s had %d messages put but none got and no readers (threshold %d)", a.Object, a.QueueManager, a.Value, a.Threshold)
+	case TypeBackouts:
+		condition = fmt.Sprintf("application %s on %s backed out %d units of work (threshold %d)", a.Object, a.QueueManager, a.Value, a.Threshold)
+	default:
+		condition = fmt.Sprintf("%s on %s: %d (threshold %d)", a.Object, a.QueueManager, a.Value, a.Threshold)
+	}
+
+	if a.Status == StatusResolved {
+		return fmt.Sprintf("[RESOLVED] %s: %s", a.Rule, condition)
+	}
+	if a.Intervals > 1 {
+		condition += fmt.Sprintf(" for %d cycles", a.Intervals)
+	}
+	return fmt.Sprintf("[FIRING] %s: %s", a.Rule, condition)
+}
+
+// objectKey identifies an object a rule is evaluated for
+type objectKey struct {
+	queueManager string
+	object       string
+}
+
+// ruleState tracks how long a rule's condition has held for one object
+type ruleState struct {
+	breaches int
+	firing   bool
+}
+
+// observation is the value of an object in one cycle and whether it breaches the rule
+type observation struct {
+	value    int64
+	breached bool
+}
+
+// Notifier evaluates the alert rules against the records of every collection cycle and
+// posts an alert to the rule's webhooks when an object has breached a threshold for the
+// configured number of consecutive cycles, and again when it recovers. It implements
+// sinks.Sink so the collector feeds it like any other output.
+type Notifier struct {
+	config   *config.AlertsConfig
+	webhooks map[string]*Webhook
+	logger   *logrus.Logger
+	now      func() time.Time
+
+	mu    sync.Mutex
+	state map[string]map[objectKey]*ruleState
+}
+
+// NewNotifier creates a notifier for the configured rules and webhooks
+func NewNotifier(cfg *config.AlertsConfig, logger *logrus.Logger) *Notifier {
+	webhooks := make(map[string]*Webhook, len(cfg.Webhooks))
+	for i := range cfg.Webhooks {
+		webhooks[cfg.Webhooks[i].Name] = NewWebhook(&cfg.Webhooks[i], cfg.Timeout)
+	}
+
+	state := make(map[string]map[objectKey]*ruleState, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		state[rule.Name] = make(map[objectKey]*ruleState)
+	}
+
+	return &Notifier{
+		config:   cfg,
+		webhooks: webhooks,
+		logger:   logger,
+		now:      time.Now,
+		state:    state,
+	}
+}
+
+// Name identifies the notifier in sink logs
+func (n *Notifier) Name() string {
+	return "alerts"
+}
+
+// Write evaluates every rule against the batch and sends the resulting alerts. Objects
+// that are not in the batch keep their state, so cycles without statistics messages
+// neither fire nor resolve alerts.
+func (n *Notifier) Write(ctx context.Context, batch *sinks.Batch) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	n.mu.Lock()
+	var alerts []Alert
+	var targets [][]string
+	for _, rule := range n.config.Rules {
+		for _, alert := range n.evaluate(rule, observe(rule, batch)) {
+			alerts = append(alerts, alert)
+			targets = append(targets, rule.Webhooks)
+		}
+	}
+	n.mu.Unlock()
+
+	var errs []error
+	for i, alert := range alerts {
+		n.logger.WithFields(logrus.Fields{
+			"rule":          alert.Rule,
+			"status":        alert.Status,
+			"queue_manager": alert.QueueManager,
+			"object":        alert.Object,
+			"value":         alert.Value,
+		}).Info("Alert " + alert.Status)
+
+		if err := n.notify(ctx, alert, targets[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close does nothing; alerts are sent synchronously
+func (n *Notifier) Close() error {
+	return nil
+}
+
+// evaluate updates the rule's state with the cycle's observations, returning the alerts
+// for objects that started firing or resolved
+func (n *Notifier) evaluate(rule config.AlertRule, observations map[objectKey]observation) []Alert {
+	intervals := rule.Intervals
+	if intervals < 1 {
+		intervals = 1
+	}
+
+	keys := make([]objectKey, 0, len(observations))
+	for key := range observations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].queueManager != keys[j].queueManager {
+			return keys[i].queueManager < keys[j].queueManager
+		}
+		return keys[i].object < keys[j].object
+	})
+
+	var alerts []Alert
+	states := n.state[rule.Name]
+	for _, key := range keys {
+		obs := observations[key]
+		state := states[key]
+		if state == nil {
+			state = &ruleState{}
+			states[key] = state
+		}
+
+		alert := Alert{
+			Rule:         rule.Name,
+			Type:         rule.Type,
+			QueueManager: key.queueManager,
+			Object:       key.object,
+			Value:        obs.value,
+			Threshold:    rule.Threshold,
+			Intervals:    intervals,
+			Time:         n.now(),
+		}
+
+		if !obs.breached {
+			if state.firing && n.config.SendResolved {
+				alert.Status = StatusResolved
+				alerts = append(alerts, alert)
+			}
+			delete(states, key)
+			continue
+		}
+
+		state.breaches++
+		if !state.firing && state.breaches >= intervals {
+			state.firing = true
+			alert.Status = StatusFiring
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// notify posts the alert to the named webhooks, or to all webhooks if none are named
+func (n *Notifier) notify(ctx context.Context, alert Alert, names []string) error {
+	if len(names) == 0 {
+		for _, webhook := range n.config.Webhooks {
+			names = append(names, webhook.Name)
+		}
+	}
+
+	var errs []error
+	for _, name := range names {
+		if err := n.webhooks[name].Send(ctx, alert); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// observe returns the value of every object in the batch the rule applies to
+func observe(rule config.AlertRule, batch *sinks.Batch) map[objectKey]observation {
+	observations := make(map[objectKey]observation)
+
+	switch rule.Type {
+	case TypeQueueDepth, TypeNoReaders:
+		readers := make(map[objectKey]bool)
+		for _, stats := range batch.Statistics {
+			q := stats.QueueStats
+			if q == nil || !matches(rule.Objects, q.QueueName) {
+				continue
+			}
+			key := objectKey{queueManager: queueManager(batch, stats.QueueManager), object: q.QueueName}
+			obs := observations[key]
+			obs.value = max(obs.value, int64(q.CurrentDepth))
+			observations[key] = obs
+			readers[key] = readers[key] || q.HasReaders
+		}
+		for key, obs := range observations {
+			obs.breached = obs.value > rule.Threshold
+			if rule.Type == TypeNoReaders {
+				obs.breached = obs.breached && !readers[key]
+			}
+			observations[key] = obs
+		}
+
+	case TypeNoConsumer:
+		// A queue is judged across all its records of the cycle, so one record showing
+		// a get or a reader clears it
+		consumed := make(map[objectKey]bool)
+		for _, stats := range batch.Statistics {
+			q := stats.QueueStats
+			if q == nil || !matches(rule.Objects, q.QueueName) {
+				continue
+			}
+			key := objectKey{queueManager: queueManager(batch, stats.QueueManager), object: q.QueueName}
+			obs := observations[key]
+			obs.value += int64(q.EnqueueCount)
+			observations[key] = obs
+			consumed[key] = consumed[key] || !q.ProducerWithoutConsumer()
+		}
+		for key, obs := range observations {
+			obs.breached = !consumed[key] && obs.value > rule.Threshold
+			observations[key] = obs
+		}
+
+	case TypeBackouts:
+		// Statistics and accounting both count backouts; an application is judged by
+		// whichever reports more, so collecting both does not double the count
+		fromStats := make(map[objectKey]int64)
+		for _, stats := range batch.Statistics {
+			mqi := stats.MQIStats
+			if mqi == nil || !matches(rule.Objects, mqi.ApplicationName) {
+				continue
+			}
+			key := objectKey{queueManager: queueManager(batch, stats.QueueManager), object: mqi.ApplicationName}
+			fromStats[key] += int64(mqi.Backouts)
+		}
+
+		fromAccounting := make(map[objectKey]int64)
+		for _, acct := range batch.Accounting {
+			if acct.ConnectionInfo == nil || acct.Operations == nil || !matches(rule.Objects, acct.ConnectionInfo.ApplicationName) {
+				continue
+			}
+			key := objectKey{queueManager: queueManager(batch, acct.QueueManager), object: acct.ConnectionInfo.ApplicationName}
+			fromAccounting[key] += int64(acct.Operations.Backouts)
+		}
+
+		for key, value := range fromStats {
+			observations[key] = observation{value: value}
+		}
+		for key, value := range fromAccounting {
+			if value > observations[key].value {
+				observations[key] = observation{value: value}
+			}
+		}
+		for key, obs := range observations {
+			obs.breached = obs.value > rule.Threshold
+			observations[key] = obs
+		}
+	}
+
+	return observations
+}
+
+// matches reports whether the object name matches one of the patterns; no patterns
+// match every object
+func matches(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// queueManager returns the record's queue manager, falling back to the batch's
+func queueManager(batch *sinks.Batch, name string) string {
+	if name != "" {
+		return name
+	}
+	return batch.QueueManager
+}
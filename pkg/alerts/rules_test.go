@@ -0,0 +1,187 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a webhook endpoint recording the generic alerts it receives
+type receiver struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func newReceiver(t *testing.T) (*receiver, *httptest.Server) {
+	r := &receiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		var alert Alert
+		if err := json.Unmarshal(body, &alert); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.mu.Lock()
+		r.alerts = append(r.alerts, alert)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func (r *receiver) received() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Alert(nil), r.alerts...)
+}
+
+func queueBatch(name string, depth int64, readers bool) *sinks.Batch {
+	return &sinks.Batch{
+		QueueManager: "QM1",
+		Statistics: []*pcf.StatisticsData{{
+			Type:       "statistics",
+			QueueStats: &pcf.QueueStatistics{QueueName: name, CurrentDepth: depth, HasReaders: readers},
+		}},
+	}
+}
+
+func testNotifier(url string, rules ...config.AlertRule) *Notifier {
+	return NewNotifier(&config.AlertsConfig{
+		Enabled:      true,
+		SendResolved: true,
+		Timeout:      5 * time.Second,
+		Webhooks:     []config.WebhookConfig{{Name: "ops", URL: url, Format: "generic"}},
+		Rules:        rules,
+	}, logrus.New())
+}
+
+func TestQueueDepthFiresAfterIntervalsAndResolves(t *testing.T) {
+	r, server := newReceiver(t)
+	notifier := testNotifier(server.URL, config.AlertRule{
+		Name: "backlog", Type: TypeQueueDepth, Objects: []string{"ORDERS.*"}, Threshold: 100, Intervals: 2,
+	})
+	ctx := context.Background()
+
+	require.NoError(t, notifier.Write(ctx, queueBatch("ORDERS.IN", 150, true)))
+	assert.Empty(t, r.received(), "one breach is not enough")
+
+	require.NoError(t, notifier.Write(ctx, queueBatch("ORDERS.IN", 180, true)))
+	require.NoError(t, notifier.Write(ctx, queueBatch("ORDERS.IN", 200, true)))
+	alerts := r.received()
+	require.Len(t, alerts, 1, "a firing alert is sent once")
+	assert.Equal(t, StatusFiring, alerts[0].Status)
+	assert.Equal(t, "QM1", alerts[0].QueueManager)
+	assert.Equal(t, "ORDERS.IN", alerts[0].Object)
+	assert.Equal(t, int64(180), alerts[0].Value)
+
+	// Empty cycles and other queues leave the state alone
+	require.NoError(t, notifier.Write(ctx, &sinks.Batch{}))
+	require.NoError(t, notifier.Write(ctx, queueBatch("PAYMENTS.IN", 500, true)))
+	assert.Len(t, r.received(), 1)
+
+	require.NoError(t, notifier.Write(ctx, queueBatch("ORDERS.IN", 20, true)))
+	alerts = r.received()
+	require.Len(t, alerts, 2)
+	assert.Equal(t, StatusResolved, alerts[1].Status)
+	assert.Equal(t, int64(20), alerts[1].Value)
+}
+
+func TestNoReaders(t *testing.T) {
+	r, server := newReceiver(t)
+	notifier := testNotifier(server.URL, config.AlertRule{Name: "orphaned", Type: TypeNoReaders, Intervals: 1})
+	ctx := context.Background()
+
+	require.NoError(t, notifier.Write(ctx, queueBatch("IDLE.Q", 0, false)))
+	require.NoError(t, notifier.Write(ctx, queueBatch("ORDERS.IN", 10, true)))
+	assert.Empty(t, r.received())
+
+	require.NoError(t, notifier.Write(ctx, queueBatch("ORDERS.IN", 10, false)))
+	alerts := r.received()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "[FIRING] orphaned: queue ORDERS.IN on QM1 has no readers with 10 messages waiting (threshold 0)", alerts[0].Summary())
+}
+
+func TestNoConsumer(t *testing.T) {
+	r, server := newReceiver(t)
+	notifier := testNotifier(server.URL, config.AlertRule{Name: "unconsumed", Type: TypeNoConsumer, Intervals: 2})
+	ctx := context.Background()
+
+	batch := func(stats ...*pcf.QueueStatistics) *sinks.Batch {
+		b := &sinks.Batch{QueueManager: "QM1"}
+		for _, q := range stats {
+			b.Statistics = append(b.Statistics, &pcf.StatisticsData{Type: "statistics", QueueStats: q})
+		}
+		return b
+	}
+
+	require.NoError(t, notifier.Write(ctx, batch(&pcf.QueueStatistics{QueueName: "ORDERS.IN", EnqueueCount: 40, HasWriters: true})))
+	assert.Empty(t, r.received(), "not yet breached for two cycles")
+
+	// A get in one of the cycle's records clears the queue
+	require.NoError(t, notifier.Write(ctx, batch(
+		&pcf.QueueStatistics{QueueName: "ORDERS.IN", EnqueueCount: 40},
+		&pcf.QueueStatistics{QueueName: "ORDERS.IN", DequeueCount: 1},
+	)))
+	assert.Empty(t, r.received())
+
+	require.NoError(t, notifier.Write(ctx, batch(&pcf.QueueStatistics{QueueName: "ORDERS.IN", EnqueueCount: 25})))
+	require.NoError(t, notifier.Write(ctx, batch(&pcf.QueueStatistics{QueueName: "ORDERS.IN", EnqueueCount: 15})))
+	alerts := r.received()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "[FIRING] unconsumed: queue ORDERS.IN on QM1 had 15 messages put but none got and no readers (threshold 0) for 2 cycles", alerts[0].Summary())
+
+	require.NoError(t, notifier.Write(ctx, batch(&pcf.QueueStatistics{QueueName: "ORDERS.IN", EnqueueCount: 5, HasReaders: true})))
+	alerts = r.received()
+	require.Len(t, alerts, 2)
+	assert.Equal(t, StatusResolved, alerts[1].Status)
+}
+
+func TestBackoutsDoNotDoubleCount(t *testing.T) {
+	r, server := newReceiver(t)
+	notifier := testNotifier(server.URL, config.AlertRule{Name: "rollbacks", Type: TypeBackouts, Threshold: 5})
+
+	batch := &sinks.Batch{
+		QueueManager: "QM1",
+		Statistics: []*pcf.StatisticsData{
+			{MQIStats: &pcf.MQIStatistics{ApplicationName: "orders", Backouts: 4}},
+		},
+		Accounting: []*pcf.AccountingData{
+			{ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "orders"}, Operations: &pcf.OperationCounts{Backouts: 3}},
+			{ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "orders"}, Operations: &pcf.OperationCounts{Backouts: 3}},
+			{ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "billing"}, Operations: &pcf.OperationCounts{Backouts: 1}},
+		},
+	}
+	require.NoError(t, notifier.Write(context.Background(), batch))
+
+	alerts := r.received()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "orders", alerts[0].Object)
+	assert.Equal(t, int64(6), alerts[0].Value)
+}
+
+func TestWriteReportsWebhookFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	notifier := testNotifier(server.URL, config.AlertRule{Name: "backlog", Type: TypeQueueDepth, Threshold: 1})
+	err := notifier.Write(context.Background(), queueBatch("ORDERS.IN", 2, true))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook ops: unexpected status 403: invalid token")
+
+	// The alert is not repeated while the condition persists
+	assert.NoError(t, notifier.Write(context.Background(), queueBatch("ORDERS.IN", 2, true)))
+}
@@ -0,0 +1,99 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+)
+
+// Webhook posts alerts to one URL in the format of the receiving service
+type Webhook struct {
+	config *config.WebhookConfig
+	client *http.Client
+}
+
+// NewWebhook creates a webhook with the given request timeout
+func NewWebhook(cfg *config.WebhookConfig, timeout time.Duration) *Webhook {
+	return &Webhook{
+		config: cfg,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the alert, failing on any status other than 2xx
+func (w *Webhook) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(w.payload(alert))
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// payload returns the request body for the webhook's format
+func (w *Webhook) payload(alert Alert) interface{} {
+	switch w.config.Format {
+	case "slack":
+		// Slack incoming webhooks, and Mattermost and Rocket.Chat compatible ones
+		return map[string]string{"text": alert.Summary()}
+
+	case "teams":
+		// An Adaptive Card message, as accepted by Teams workflow webhooks
+		color := "Attention"
+		if alert.Status == StatusResolved {
+			color = "Good"
+		}
+		return map[string]interface{}{
+			"type": "message",
+			"attachments": []map[string]interface{}{{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"body": []map[string]interface{}{
+						{"type": "TextBlock", "text": alert.Summary(), "wrap": true, "weight": "Bolder", "color": color},
+						{"type": "FactSet", "facts": []map[string]string{
+							{"title": "Queue manager", "value": alert.QueueManager},
+							{"title": "Object", "value": alert.Object},
+							{"title": "Value", "value": fmt.Sprint(alert.Value)},
+							{"title": "Threshold", "value": fmt.Sprint(alert.Threshold)},
+							{"title": "Time", "value": alert.Time.UTC().Format(time.RFC3339)},
+						}},
+					},
+				},
+			}},
+		}
+
+	default:
+		return struct {
+			Alert
+			Summary string `json:"summary"`
+		}{alert, alert.Summary()}
+	}
+}
@@ -0,0 +1,80 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAlert = Alert{
+	Rule:         "backlog",
+	Type:         TypeQueueDepth,
+	Status:       StatusFiring,
+	QueueManager: "QM1",
+	Object:       "ORDERS.IN",
+	Value:        6200,
+	Threshold:    5000,
+	Intervals:    2,
+	Time:         time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC),
+}
+
+// capture posts the alert through a webhook of the given format and returns the request
+func capture(t *testing.T, format string, headers map[string]string) (*http.Request, map[string]interface{}) {
+	var req *http.Request
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		data, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(data, &body))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(&config.WebhookConfig{Name: "test", URL: server.URL, Format: format, Headers: headers}, 5*time.Second)
+	require.NoError(t, webhook.Send(context.Background(), testAlert))
+	return req, body
+}
+
+func TestSlackPayload(t *testing.T) {
+	_, body := capture(t, "slack", nil)
+	assert.Equal(t, "[FIRING] backlog: queue ORDERS.IN on QM1 has depth 6200 (threshold 5000) for 2 cycles", body["text"])
+}
+
+func TestTeamsPayload(t *testing.T) {
+	_, body := capture(t, "teams", nil)
+	assert.Equal(t, "message", body["type"])
+
+	attachment := body["attachments"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+	card := attachment["content"].(map[string]interface{})
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	text := card["body"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, testAlert.Summary(), text["text"])
+}
+
+func TestGenericPayloadAndHeaders(t *testing.T) {
+	req, body := capture(t, "generic", map[string]string{"Authorization": "Bearer abc"})
+	assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	assert.Equal(t, "backlog", body["rule"])
+	assert.Equal(t, "firing", body["status"])
+	assert.Equal(t, "ORDERS.IN", body["object"])
+	assert.Equal(t, float64(6200), body["value"])
+	assert.Equal(t, "2026-03-14T10:00:00Z", body["time"])
+	assert.Equal(t, testAlert.Summary(), body["summary"])
+}
+
+func TestResolvedSummary(t *testing.T) {
+	alert := testAlert
+	alert.Status = StatusResolved
+	alert.Value = 12
+	assert.Equal(t, "[RESOLVED] backlog: queue ORDERS.IN on QM1 has depth 12 (threshold 5000)", alert.Summary())
+}
@@ -0,0 +1,134 @@
+package anomaly
+
+import (
+	"maps"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+)
+
+// Baseline methods
+const (
+	MethodEWMA    = "ewma"
+	MethodRolling = "rolling"
+)
+
+// Smallest standard deviation a score is divided by, in messages, so a queue whose
+// depth never changed is not infinitely anomalous on its first message
+const minStdDev = 1.0
+
+// Detector scores each new value of a series against a baseline learnt from the earlier
+// values, as the number of standard deviations it lies above the baseline mean. The
+// baseline is an exponentially weighted moving mean and variance (ewma) or the mean and
+// standard deviation of the last window values (rolling).
+type Detector struct {
+	method     string
+	window     int
+	threshold  float64
+	minSamples int
+	alpha      float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is the baseline of one series
+type series struct {
+	samples  int
+	mean     float64
+	variance float64
+	values   []float64 // rolling only, oldest first
+}
+
+// NewDetector creates a detector from the anomaly configuration
+func NewDetector(cfg *config.AnomalyConfig) *Detector {
+	return &Detector{
+		method:     cfg.Method,
+		window:     cfg.Window,
+		threshold:  cfg.Threshold,
+		minSamples: cfg.MinSamples,
+		alpha:      2 / float64(cfg.Window+1),
+		series:     make(map[string]*series),
+	}
+}
+
+// Observe scores value against the baseline of the series and then adds it to the
+// baseline. The score is 0 until the series has min_samples values; a value is
+// anomalous when its score exceeds the threshold, so only unusually high values are.
+func (d *Detector) Observe(key string, value float64) (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s := d.series[key]
+	if s == nil {
+		s = &series{}
+		d.series[key] = s
+	}
+
+	var score float64
+	if s.samples >= d.minSamples {
+		score = (value - s.mean) / math.Max(math.Sqrt(s.variance), minStdDev)
+	}
+
+	if d.method == MethodRolling {
+		s.addRolling(value, d.window)
+	} else {
+		s.addEWMA(value, d.alpha)
+	}
+
+	return score, score > d.threshold
+}
+
+// Forget drops the baseline of a series
+func (d *Detector) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.series, key)
+}
+
+// ResetQueueManager drops the baselines of the queues of a queue manager, such as after it
+// restarted and their depths start over
+func (d *Detector) ResetQueueManager(qmgr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	prefix := qmgr + "/"
+	maps.DeleteFunc(d.series, func(key string, _ *series) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// addEWMA updates the exponentially weighted mean and variance
+func (s *series) addEWMA(value, alpha float64) {
+	s.samples++
+	if s.samples == 1 {
+		s.mean = value
+		return
+	}
+	diff := value - s.mean
+	s.mean += alpha * diff
+	s.variance = (1 - alpha) * (s.variance + alpha*diff*diff)
+}
+
+// addRolling adds the value to the window and recomputes its mean and variance
+func (s *series) addRolling(value float64, window int) {
+	s.samples++
+	s.values = append(s.values, value)
+	if len(s.values) > window {
+		s.values = s.values[len(s.values)-window:]
+	}
+
+	var sum float64
+	for _, v := range s.values {
+		sum += v
+	}
+	s.mean = sum / float64(len(s.values))
+
+	var squares float64
+	for _, v := range s.values {
+		squares += (v - s.mean) * (v - s.mean)
+	}
+	s.variance = squares / float64(len(s.values))
+}
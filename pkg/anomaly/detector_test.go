@@ -0,0 +1,103 @@
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDetector(method string) *Detector {
+	return NewDetector(&config.AnomalyConfig{
+		Enabled:    true,
+		Method:     method,
+		Window:     10,
+		Threshold:  3,
+		MinSamples: 5,
+	})
+}
+
+func TestDetectorFlagsBacklog(t *testing.T) {
+	for _, method := range []string{MethodEWMA, MethodRolling} {
+		t.Run(method, func(t *testing.T) {
+			detector := testDetector(method)
+
+			// A queue oscillating between 90 and 110 messages
+			for i := 0; i < 20; i++ {
+				score, anomalous := detector.Observe("QM1/APP.ORDERS", float64(90+20*(i%2)))
+				assert.False(t, anomalous)
+				assert.Less(t, score, 3.0)
+			}
+
+			// Unusually low depth scores negative but is not flagged
+			score, anomalous := detector.Observe("QM1/APP.ORDERS", 0)
+			assert.Less(t, score, -3.0)
+			assert.False(t, anomalous)
+
+			score, anomalous = detector.Observe("QM1/APP.ORDERS", 500)
+			assert.True(t, anomalous)
+			assert.Greater(t, score, 10.0)
+		})
+	}
+}
+
+func TestDetectorWarmUp(t *testing.T) {
+	detector := testDetector(MethodEWMA)
+	for i := 0; i < 5; i++ {
+		score, anomalous := detector.Observe("q", float64(i*1000))
+		assert.Zero(t, score, "sample %d", i)
+		assert.False(t, anomalous)
+	}
+
+	_, anomalous := detector.Observe("q", 100000)
+	assert.True(t, anomalous)
+}
+
+func TestDetectorFlatSeries(t *testing.T) {
+	detector := testDetector(MethodRolling)
+	for i := 0; i < 10; i++ {
+		detector.Observe("q", 0)
+	}
+
+	// The standard deviation is floored at one message
+	score, anomalous := detector.Observe("q", 2)
+	assert.Equal(t, 2.0, score)
+	assert.False(t, anomalous)
+
+	score, anomalous = detector.Observe("q", 50)
+	assert.True(t, anomalous)
+	assert.Greater(t, score, 3.0)
+
+	detector.Forget("q")
+	score, _ = detector.Observe("q", 1000)
+	assert.Zero(t, score)
+}
+
+func TestRollingWindowForgetsOldValues(t *testing.T) {
+	detector := testDetector(MethodRolling)
+	for i := 0; i < 10; i++ {
+		detector.Observe("q", 1000)
+	}
+	for i := 0; i < 10; i++ {
+		detector.Observe("q", 10)
+	}
+
+	s := detector.series["q"]
+	assert.Len(t, s.values, 10)
+	assert.Equal(t, 10.0, s.mean)
+	assert.Zero(t, s.variance)
+}
+
+func TestDetectorResetQueueManager(t *testing.T) {
+	detector := testDetector(MethodEWMA)
+	for i := 0; i < 10; i++ {
+		detector.Observe("QM1/APP.ORDERS", 100)
+		detector.Observe("QM2/APP.ORDERS", 100)
+	}
+
+	detector.ResetQueueManager("QM1")
+	score, _ := detector.Observe("QM1/APP.ORDERS", 0)
+	assert.Zero(t, score, "the baseline is learnt again")
+	score, _ = detector.Observe("QM2/APP.ORDERS", 0)
+	assert.Less(t, score, -3.0)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// RequireToken wraps a handler so that only requests carrying one of tokens reach it,
+// either as "Authorization: Bearer <token>" or as "X-API-Key: <token>". Others are
+// answered 401. Without tokens the handler is returned as it is.
+func RequireToken(tokens []string, next http.Handler) http.Handler {
+	if len(tokens) == 0 {
+		return next
+	}
+
+	// Comparing digests keeps the comparison constant time whatever the token lengths
+	digests := make([][sha256.Size]byte, len(tokens))
+	for i, token := range tokens {
+		digests[i] = sha256.Sum256([]byte(token))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r)
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ibmmq-collector"`)
+			writeError(w, http.StatusUnauthorized, "missing bearer token or API key")
+			return
+		}
+
+		digest := sha256.Sum256([]byte(token))
+		valid := 0
+		for _, d := range digests {
+			valid |= subtle.ConstantTimeCompare(digest[:], d[:])
+		}
+		if valid == 0 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ibmmq-collector", error="invalid_token"`)
+			writeError(w, http.StatusUnauthorized, "invalid bearer token or API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestToken returns the bearer token or API key of a request, or "" if it has none
+func requestToken(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return r.Header.Get("X-API-Key")
+}
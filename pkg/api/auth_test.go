@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireToken(t *testing.T) {
+	handler := RequireToken([]string{"first-token", "second-token"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/queues", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, request("Authorization", "Bearer first-token").Code)
+	assert.Equal(t, http.StatusNoContent, request("Authorization", "bearer second-token").Code)
+	assert.Equal(t, http.StatusNoContent, request("X-API-Key", "second-token").Code)
+
+	missing := request("", "")
+	assert.Equal(t, http.StatusUnauthorized, missing.Code)
+	assert.Contains(t, missing.Header().Get("WWW-Authenticate"), "Bearer")
+	assert.Equal(t, http.StatusUnauthorized, request("Authorization", "Bearer wrong-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, request("Authorization", "Basic first-token").Code)
+	assert.Equal(t, http.StatusUnauthorized, request("X-API-Key", "first").Code)
+
+	// Without tokens the handler is open
+	open := RequireToken(nil, http.NotFoundHandler())
+	rec := httptest.NewRecorder()
+	open.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
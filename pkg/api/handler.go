@@ -0,0 +1,340 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/accounting"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/correlation"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/store"
+	"github.com/sirupsen/logrus"
+)
+
+// QueueSummary is the latest state of a queue within the recorded cycles
+type QueueSummary struct {
+	QueueManager  string    `json:"queue_manager"`
+	Name          string    `json:"name"`
+	Depth         int64     `json:"depth"`
+	HighDepth     int64     `json:"high_depth"`
+	Enqueued      int64     `json:"enqueued"`
+	Dequeued      int64     `json:"dequeued"`
+	InputHandles  int64     `json:"input_handles"`
+	OutputHandles int64     `json:"output_handles"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+// QueueInterval is one statistics record of a queue
+type QueueInterval struct {
+	CollectedAt  time.Time            `json:"collected_at"`
+	Timestamp    time.Time            `json:"timestamp"`
+	QueueManager string               `json:"queue_manager"`
+	Stats        *pcf.QueueStatistics `json:"stats"`
+}
+
+// ChannelSummary is the latest state of a channel within the recorded cycles
+type ChannelSummary struct {
+	QueueManager   string    `json:"queue_manager"`
+	Name           string    `json:"name"`
+	ConnectionName string    `json:"connection_name"`
+	Messages       int64     `json:"messages"`
+	Bytes          int64     `json:"bytes"`
+	Batches        int64     `json:"batches"`
+	LastSeen       time.Time `json:"last_seen"`
+}
+
+// CycleCorrelation is the correlated queue activity of one recorded cycle
+type CycleCorrelation struct {
+	CollectedAt time.Time                    `json:"collected_at"`
+	Queues      []*correlation.QueueActivity `json:"queues"`
+}
+
+// Handler serves the recorded cycles as versioned JSON endpoints under /api/v1/
+type Handler struct {
+	recorder *Recorder
+	history  *store.Store
+	logger   *logrus.Logger
+	mux      *http.ServeMux
+}
+
+// NewHandler creates the API handler for a recorder. The history store, if not nil,
+// feeds the chargeback report instead of the recorded cycles.
+func NewHandler(recorder *Recorder, history *store.Store, logger *logrus.Logger) *Handler {
+	h := &Handler{recorder: recorder, history: history, logger: logger, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /api/v1/cycles", h.listCycles)
+	h.mux.HandleFunc("GET /api/v1/queues", h.listQueues)
+	h.mux.HandleFunc("GET /api/v1/queues/{name}/stats", h.queueStats)
+	h.mux.HandleFunc("GET /api/v1/channels", h.listChannels)
+	h.mux.HandleFunc("GET /api/v1/accounting", h.listAccounting)
+	h.mux.HandleFunc("GET /api/v1/chargeback", h.chargeback)
+	h.mux.HandleFunc("GET /api/v1/correlation", h.correlation)
+	h.mux.HandleFunc("/api/v1/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "unknown endpoint "+r.URL.Path)
+	})
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// listCycles returns the time and record counts of each recorded cycle
+func (h *Handler) listCycles(w http.ResponseWriter, r *http.Request) {
+	type cycleSummary struct {
+		CollectedAt time.Time `json:"collected_at"`
+		Statistics  int       `json:"statistics"`
+		Accounting  int       `json:"accounting"`
+	}
+
+	cycles := []cycleSummary{}
+	for _, cycle := range h.recorder.Cycles() {
+		cycles = append(cycles, cycleSummary{
+			CollectedAt: cycle.Time,
+			Statistics:  len(cycle.Batch.Statistics),
+			Accounting:  len(cycle.Batch.Accounting),
+		})
+	}
+	h.writeJSON(w, map[string]interface{}{"cycles": cycles})
+}
+
+// listQueues returns the latest statistics of every queue, sorted by name
+func (h *Handler) listQueues(w http.ResponseWriter, r *http.Request) {
+	latest := make(map[string]*QueueSummary)
+	for _, cycle := range h.recorder.Cycles() {
+		for _, stats := range cycle.Batch.Statistics {
+			q := stats.QueueStats
+			if q == nil {
+				continue
+			}
+			qmgr := queueManager(cycle, stats.QueueManager)
+			latest[qmgr+"/"+q.QueueName] = &QueueSummary{
+				QueueManager:  qmgr,
+				Name:          q.QueueName,
+				Depth:         q.CurrentDepth,
+				HighDepth:     q.HighDepth,
+				Enqueued:      q.EnqueueCount,
+				Dequeued:      q.DequeueCount,
+				InputHandles:  q.InputCount,
+				OutputHandles: q.OutputCount,
+				LastSeen:      recordTime(stats.Timestamp, cycle.Time),
+			}
+		}
+	}
+
+	queues := make([]*QueueSummary, 0, len(latest))
+	for _, q := range latest {
+		queues = append(queues, q)
+	}
+	sort.Slice(queues, func(i, j int) bool {
+		if queues[i].Name != queues[j].Name {
+			return queues[i].Name < queues[j].Name
+		}
+		return queues[i].QueueManager < queues[j].QueueManager
+	})
+	h.writeJSON(w, map[string]interface{}{"queues": queues})
+}
+
+// queueStats returns every recorded statistics interval of one queue, oldest first
+func (h *Handler) queueStats(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	intervals := []QueueInterval{}
+	for _, cycle := range h.recorder.Cycles() {
+		for _, stats := range cycle.Batch.Statistics {
+			if stats.QueueStats == nil || stats.QueueStats.QueueName != name {
+				continue
+			}
+			intervals = append(intervals, QueueInterval{
+				CollectedAt:  cycle.Time,
+				Timestamp:    stats.Timestamp,
+				QueueManager: queueManager(cycle, stats.QueueManager),
+				Stats:        stats.QueueStats,
+			})
+		}
+	}
+
+	if len(intervals) == 0 {
+		writeError(w, http.StatusNotFound, "no statistics recorded for queue "+name)
+		return
+	}
+	h.writeJSON(w, map[string]interface{}{"queue": name, "stats": intervals})
+}
+
+// listChannels returns the latest statistics of every channel, sorted by name
+func (h *Handler) listChannels(w http.ResponseWriter, r *http.Request) {
+	latest := make(map[string]*ChannelSummary)
+	for _, cycle := range h.recorder.Cycles() {
+		for _, stats := range cycle.Batch.Statistics {
+			ch := stats.ChannelStats
+			if ch == nil {
+				continue
+			}
+			qmgr := queueManager(cycle, stats.QueueManager)
+			latest[qmgr+"/"+ch.ChannelName+"/"+ch.ConnectionName] = &ChannelSummary{
+				QueueManager:   qmgr,
+				Name:           ch.ChannelName,
+				ConnectionName: ch.ConnectionName,
+				Messages:       ch.Messages,
+				Bytes:          ch.Bytes,
+				Batches:        ch.Batches,
+				LastSeen:       recordTime(stats.Timestamp, cycle.Time),
+			}
+		}
+	}
+
+	channels := make([]*ChannelSummary, 0, len(latest))
+	for _, ch := range latest {
+		channels = append(channels, ch)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		if channels[i].Name != channels[j].Name {
+			return channels[i].Name < channels[j].Name
+		}
+		return channels[i].ConnectionName < channels[j].ConnectionName
+	})
+	h.writeJSON(w, map[string]interface{}{"channels": channels})
+}
+
+// listAccounting returns the recorded accounting records, optionally filtered by
+// application (app) and user, oldest first
+func (h *Handler) listAccounting(w http.ResponseWriter, r *http.Request) {
+	app := r.URL.Query().Get("app")
+	user := r.URL.Query().Get("user")
+
+	records := []*pcf.AccountingData{}
+	for _, cycle := range h.recorder.Cycles() {
+		for _, acct := range cycle.Batch.Accounting {
+			info := acct.ConnectionInfo
+			if info == nil {
+				info = &pcf.ConnectionInfo{}
+			}
+			if app != "" && !strings.EqualFold(info.ApplicationName, app) {
+				continue
+			}
+			if user != "" && info.UserIdentifier != user {
+				continue
+			}
+			records = append(records, acct)
+		}
+	}
+	h.writeJSON(w, map[string]interface{}{"accounting": records})
+}
+
+// correlation joins the queue statistics of every recorded cycle with its queue accounting
+// records, optionally for one queue, oldest first
+func (h *Handler) correlation(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("queue")
+
+	cycles := []CycleCorrelation{}
+	for _, cycle := range h.recorder.Cycles() {
+		queues := []*correlation.QueueActivity{}
+		for _, q := range correlation.Correlate(cycle.Batch.QueueManager, cycle.Batch.Statistics, cycle.Batch.Accounting) {
+			if name == "" || q.Queue == name {
+				queues = append(queues, q)
+			}
+		}
+		cycles = append(cycles, CycleCorrelation{CollectedAt: cycle.Time, Queues: queues})
+	}
+	h.writeJSON(w, map[string]interface{}{"cycles": cycles})
+}
+
+// chargeback aggregates accounting records per day or month (period) by application
+// and/or user (group_by), optionally only those of the last since, as JSON, CSV or HTML
+// (format)
+func (h *Handler) chargeback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	period := query.Get("period")
+	if period == "" {
+		period = accounting.PeriodDay
+	}
+	groupBy := []string{accounting.GroupApplication, accounting.GroupUser}
+	if value := query.Get("group_by"); value != "" {
+		groupBy = strings.Split(value, ",")
+	}
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid since duration: "+value)
+			return
+		}
+		since = time.Now().Add(-d)
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" && format != "html" {
+		writeError(w, http.StatusBadRequest, "invalid format: "+format+" (use json, csv or html)")
+		return
+	}
+
+	report, err := accounting.NewChargeback(period, groupBy, since, time.Time{})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if h.history != nil {
+		records, err := h.history.Accounting(r.Context(), store.Query{Since: since})
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to read accounting history")
+			writeError(w, http.StatusInternalServerError, "failed to read accounting history")
+			return
+		}
+		for _, acct := range records {
+			report.Add(acct)
+		}
+	} else {
+		for _, cycle := range h.recorder.Cycles() {
+			for _, acct := range cycle.Batch.Accounting {
+				report.Add(acct)
+			}
+		}
+	}
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		err = report.WriteCSV(w)
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err = report.WriteHTML(w)
+	default:
+		h.writeJSON(w, map[string]interface{}{"period": period, "periods": report.Periods()})
+	}
+	if err != nil {
+		h.logger.WithError(err).Debug("Failed to write API response")
+	}
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.WithError(err).Debug("Failed to write API response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// queueManager returns the record's queue manager, or the cycle's configured one
+func queueManager(cycle Cycle, name string) string {
+	if name != "" {
+		return name
+	}
+	return cycle.Batch.QueueManager
+}
+
+// recordTime returns the record timestamp, or the collection time for records without one
+func recordTime(ts, collected time.Time) time.Time {
+	if ts.IsZero() {
+		return collected
+	}
+	return ts
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queueBatch(depth int64, at time.Time) *sinks.Batch {
+	return &sinks.Batch{
+		QueueManager: "QM1",
+		Statistics: []*pcf.StatisticsData{
+			{Timestamp: at, QueueStats: &pcf.QueueStatistics{QueueName: "APP.ORDERS", CurrentDepth: depth}},
+			{Timestamp: at, QueueStats: &pcf.QueueStatistics{QueueName: "APP.INVOICES", CurrentDepth: 1}},
+			{Timestamp: at, ChannelStats: &pcf.ChannelStatistics{ChannelName: "TO.QM2", ConnectionName: "10.0.0.2(1414)", Messages: 8}},
+		},
+		Accounting: []*pcf.AccountingData{
+			{Timestamp: at, ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "orders", UserIdentifier: "app1"}},
+			{Timestamp: at, ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "billing", UserIdentifier: "app2"}},
+		},
+	}
+}
+
+func get(t *testing.T, handler http.Handler, path string, body interface{}) int {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), body), rec.Body.String())
+	return rec.Code
+}
+
+func TestRecorderKeepsLastCycles(t *testing.T) {
+	recorder := NewRecorder(2)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, recorder.Write(context.Background(), queueBatch(int64(i), base.Add(time.Duration(i)*time.Minute))))
+	}
+
+	cycles := recorder.Cycles()
+	require.Len(t, cycles, 2)
+	assert.Equal(t, int64(1), cycles[0].Batch.Statistics[0].QueueStats.CurrentDepth)
+	assert.Equal(t, int64(2), cycles[1].Batch.Statistics[0].QueueStats.CurrentDepth)
+}
+
+func TestHandlerEndpoints(t *testing.T) {
+	recorder := NewRecorder(10)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, recorder.Write(context.Background(), queueBatch(3, base)))
+	require.NoError(t, recorder.Write(context.Background(), queueBatch(7, base.Add(time.Minute))))
+	handler := NewHandler(recorder, nil, logrus.New())
+
+	var queues struct{ Queues []QueueSummary }
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/queues", &queues))
+	require.Len(t, queues.Queues, 2)
+	assert.Equal(t, "APP.INVOICES", queues.Queues[0].Name)
+	assert.Equal(t, "APP.ORDERS", queues.Queues[1].Name)
+	assert.Equal(t, int64(7), queues.Queues[1].Depth, "latest cycle wins")
+	assert.Equal(t, "QM1", queues.Queues[1].QueueManager)
+	assert.True(t, queues.Queues[1].LastSeen.Equal(base.Add(time.Minute)))
+
+	var stats struct {
+		Queue string
+		Stats []QueueInterval
+	}
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/queues/APP.ORDERS/stats", &stats))
+	assert.Equal(t, "APP.ORDERS", stats.Queue)
+	require.Len(t, stats.Stats, 2)
+	assert.Equal(t, int64(3), stats.Stats[0].Stats.CurrentDepth)
+	assert.Equal(t, int64(7), stats.Stats[1].Stats.CurrentDepth)
+
+	var missing struct{ Error string }
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/api/v1/queues/NO.SUCH.QUEUE/stats", &missing))
+	assert.Contains(t, missing.Error, "NO.SUCH.QUEUE")
+
+	var channels struct{ Channels []ChannelSummary }
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/channels", &channels))
+	require.Len(t, channels.Channels, 1)
+	assert.Equal(t, "10.0.0.2(1414)", channels.Channels[0].ConnectionName)
+
+	var acct struct{ Accounting []pcf.AccountingData }
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/accounting?app=orders", &acct))
+	require.Len(t, acct.Accounting, 2, "one record per cycle")
+	assert.Equal(t, "orders", acct.Accounting[0].ConnectionInfo.ApplicationName)
+
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/accounting?user=app2", &acct))
+	require.Len(t, acct.Accounting, 2)
+	assert.Equal(t, "billing", acct.Accounting[0].ConnectionInfo.ApplicationName)
+
+	var cycles struct {
+		Cycles []struct{ Statistics, Accounting int }
+	}
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/cycles", &cycles))
+	require.Len(t, cycles.Cycles, 2)
+	assert.Equal(t, 3, cycles.Cycles[0].Statistics)
+
+	assert.Equal(t, http.StatusNotFound, get(t, handler, "/api/v1/topics", &missing))
+}
+
+func TestHandlerCorrelation(t *testing.T) {
+	recorder := NewRecorder(10)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	batch := queueBatch(3, base)
+	batch.Statistics[0].QueueStats.EnqueueCount = 12
+	batch.Accounting[0].Queues = []pcf.QueueOperations{{QueueName: "APP.ORDERS", Puts: 10}}
+	require.NoError(t, recorder.Write(context.Background(), batch))
+	handler := NewHandler(recorder, nil, logrus.New())
+
+	var body struct{ Cycles []CycleCorrelation }
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/correlation?queue=APP.ORDERS", &body))
+	require.Len(t, body.Cycles, 1)
+	require.Len(t, body.Cycles[0].Queues, 1)
+
+	orders := body.Cycles[0].Queues[0]
+	assert.Equal(t, "QM1", orders.QueueManager)
+	assert.Equal(t, int64(2), orders.UnattributedPuts)
+	require.Len(t, orders.Applications, 1)
+	assert.Equal(t, "orders", orders.Applications[0].Application)
+	assert.Equal(t, int64(10), orders.Applications[0].Puts)
+
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/correlation", &body))
+	assert.Len(t, body.Cycles[0].Queues, 2)
+}
+
+func TestHandlerEmptyRecorder(t *testing.T) {
+	handler := NewHandler(NewRecorder(5), nil, logrus.New())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/queues", nil))
+	assert.JSONEq(t, `{"queues":[]}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/accounting", nil))
+	assert.JSONEq(t, `{"accounting":[]}`, rec.Body.String())
+}
+
+func TestHandlerChargeback(t *testing.T) {
+	recorder := NewRecorder(10)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, recorder.Write(context.Background(), queueBatch(3, base)))
+	require.NoError(t, recorder.Write(context.Background(), queueBatch(3, base.Add(24*time.Hour))))
+	handler := NewHandler(recorder, nil, logrus.New())
+
+	var body struct {
+		Period  string `json:"period"`
+		Periods []struct {
+			Period string `json:"period"`
+			Rows   []struct {
+				Application string `json:"application"`
+				User        string `json:"user"`
+				Connections int64  `json:"connections"`
+			} `json:"rows"`
+		} `json:"periods"`
+	}
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/chargeback?group_by=application", &body))
+	assert.Equal(t, "day", body.Period)
+	require.Len(t, body.Periods, 2)
+	assert.Equal(t, "2026-03-14", body.Periods[0].Period)
+	require.Len(t, body.Periods[0].Rows, 2)
+	assert.Empty(t, body.Periods[0].Rows[0].User)
+
+	body.Periods = nil
+	assert.Equal(t, http.StatusOK, get(t, handler, "/api/v1/chargeback?period=month", &body))
+	require.Len(t, body.Periods, 1)
+	assert.Equal(t, "2026-03", body.Periods[0].Period)
+	assert.Equal(t, int64(2), body.Periods[0].Rows[0].Connections)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/chargeback?format=csv&period=month", nil))
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "2026-03,billing,app2,2,")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/chargeback?format=html", nil))
+	assert.Contains(t, rec.Body.String(), "<h2>2026-03-15</h2>")
+
+	var failure map[string]string
+	assert.Equal(t, http.StatusBadRequest, get(t, handler, "/api/v1/chargeback?period=week", &failure))
+	assert.Equal(t, http.StatusBadRequest, get(t, handler, "/api/v1/chargeback?since=yesterday", &failure))
+}
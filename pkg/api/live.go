@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// Types of live events
+const (
+	EventStatistics = "statistics"
+	EventAccounting = "accounting"
+	EventStatus     = "status"
+)
+
+// Collector states reported in status events
+const (
+	StateConnected = "connected"
+	StateCollected = "collected"
+	StatePartial   = "partial" // one of the queues could not be drained
+	StateFailed    = "failed"
+)
+
+// Event is one JSON message pushed to live clients
+type Event struct {
+	Type         string      `json:"type"`
+	Time         time.Time   `json:"time"`
+	QueueManager string      `json:"queue_manager,omitempty"`
+	Data         interface{} `json:"data"`
+}
+
+// Status describes a change in the collector's state
+type Status struct {
+	State              string `json:"state"`
+	Collections        int64  `json:"collections"`
+	StatisticsMessages int    `json:"statistics_messages,omitempty"`
+	AccountingMessages int    `json:"accounting_messages,omitempty"`
+	DurationMillis     int64  `json:"duration_ms,omitempty"`
+	Error              string `json:"error,omitempty"`
+	CycleID            string `json:"cycle_id,omitempty"`
+}
+
+// Hub pushes parsed records and collector status events to WebSocket clients. It
+// implements sinks.Sink so records arrive once per collection cycle; status events are
+// published by the collector. Each client has its own queue, and a client that cannot
+// keep up is disconnected instead of delaying collection.
+type Hub struct {
+	bufferSize int
+	logger     *logrus.Logger
+
+	mu      sync.Mutex
+	clients map[*liveClient]struct{}
+	closed  bool
+}
+
+// liveClient is one connected browser and its queue of pending events
+type liveClient struct {
+	types   map[string]bool // nil for all event types
+	events  chan Event
+	done    chan struct{}
+	stopped bool
+}
+
+// NewHub creates a hub queueing up to bufferSize events per client
+func NewHub(bufferSize int, logger *logrus.Logger) *Hub {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Hub{
+		bufferSize: bufferSize,
+		logger:     logger,
+		clients:    make(map[*liveClient]struct{}),
+	}
+}
+
+// Name identifies the hub in sink logs
+func (h *Hub) Name() string {
+	return "websocket"
+}
+
+// Write pushes the records of a collection cycle to every client
+func (h *Hub) Write(ctx context.Context, batch *sinks.Batch) error {
+	now := time.Now()
+	cycle := Cycle{Time: now, Batch: batch}
+
+	events := make([]Event, 0, batch.Len())
+	for _, stats := range batch.Statistics {
+		events = append(events, Event{
+			Type:         EventStatistics,
+			Time:         recordTime(stats.Timestamp, now),
+			QueueManager: queueManager(cycle, stats.QueueManager),
+			Data:         stats,
+		})
+	}
+	for _, acct := range batch.Accounting {
+		events = append(events, Event{
+			Type:         EventAccounting,
+			Time:         recordTime(acct.Timestamp, now),
+			QueueManager: queueManager(cycle, acct.QueueManager),
+			Data:         acct,
+		})
+	}
+
+	h.broadcast(events...)
+	return nil
+}
+
+// Publish pushes a collector status event to every client
+func (h *Hub) Publish(status Status) {
+	h.broadcast(Event{Type: EventStatus, Time: time.Now(), Data: status})
+}
+
+// Close disconnects all clients
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for client := range h.clients {
+		client.stop()
+	}
+	return nil
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection. The optional types query
+// parameter limits the stream to a comma-separated list of event types.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Handler(h.serve).ServeHTTP(w, r)
+}
+
+// serve streams events to one client until it disconnects or falls behind
+func (h *Hub) serve(ws *websocket.Conn) {
+	defer ws.Close()
+
+	client := &liveClient{
+		events: make(chan Event, h.bufferSize),
+		done:   make(chan struct{}),
+	}
+	if types := ws.Request().URL.Query().Get("types"); types != "" {
+		client.types = make(map[string]bool)
+		for _, t := range strings.Split(types, ",") {
+			client.types[strings.TrimSpace(t)] = true
+		}
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, client)
+		h.mu.Unlock()
+	}()
+
+	// Clients only listen; reading detects when they go away
+	gone := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, ws)
+		close(gone)
+	}()
+
+	remote := ws.Request().RemoteAddr
+	h.logger.WithField("remote", remote).Debug("WebSocket client connected")
+
+	for {
+		select {
+		case event := <-client.events:
+			ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				h.logger.WithError(err).WithField("remote", remote).Debug("WebSocket client write failed")
+				return
+			}
+		case <-gone:
+			h.logger.WithField("remote", remote).Debug("WebSocket client disconnected")
+			return
+		case <-client.done:
+			return
+		}
+	}
+}
+
+// broadcast queues events to every client that wants them
+func (h *Hub) broadcast(events ...Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients {
+		for _, event := range events {
+			if client.stopped {
+				break
+			}
+			if client.types != nil && !client.types[event.Type] {
+				continue
+			}
+			select {
+			case client.events <- event:
+			default:
+				h.logger.WithField("buffer_size", h.bufferSize).Warn("Disconnecting slow WebSocket client")
+				client.stop()
+			}
+		}
+	}
+}
+
+// stop ends the client's stream; called with the hub lock held
+func (c *liveClient) stop() {
+	if !c.stopped {
+		c.stopped = true
+		close(c.done)
+	}
+}
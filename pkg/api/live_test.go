@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// dialHub connects a WebSocket client and waits until the hub has registered it
+func dialHub(t *testing.T, hub *Hub, query string) *websocket.Conn {
+	server := httptest.NewServer(hub)
+	t.Cleanup(server.Close)
+
+	before := hubClients(hub)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws" + query
+	ws, err := websocket.Dial(url, "", "http://localhost/")
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+
+	require.Eventually(t, func() bool { return hubClients(hub) > before }, 5*time.Second, 10*time.Millisecond)
+	return ws
+}
+
+func hubClients(hub *Hub) int {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return len(hub.clients)
+}
+
+// receive reads the next event, keeping the data as raw JSON
+func receive(t *testing.T, ws *websocket.Conn) (string, json.RawMessage) {
+	var event struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	require.NoError(t, websocket.JSON.Receive(ws, &event))
+	return event.Type, event.Data
+}
+
+func TestHubStreamsRecordsAndStatus(t *testing.T) {
+	hub := NewHub(16, logrus.New())
+	ws := dialHub(t, hub, "")
+
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, hub.Write(context.Background(), queueBatch(5, base)))
+	hub.Publish(Status{State: StateCollected, Collections: 1, StatisticsMessages: 3})
+
+	var types []string
+	for i := 0; i < 5; i++ {
+		eventType, _ := receive(t, ws)
+		types = append(types, eventType)
+	}
+	assert.Equal(t, []string{EventStatistics, EventStatistics, EventStatistics, EventAccounting, EventAccounting}, types)
+
+	eventType, data := receive(t, ws)
+	assert.Equal(t, EventStatus, eventType)
+	var status Status
+	require.NoError(t, json.Unmarshal(data, &status))
+	assert.Equal(t, StateCollected, status.State)
+	assert.Equal(t, int64(1), status.Collections)
+}
+
+func TestHubFiltersEventTypes(t *testing.T) {
+	hub := NewHub(16, logrus.New())
+	ws := dialHub(t, hub, "?types=status")
+
+	require.NoError(t, hub.Write(context.Background(), queueBatch(5, time.Now())))
+	hub.Publish(Status{State: StateFailed, Error: "queue manager unavailable"})
+
+	eventType, data := receive(t, ws)
+	assert.Equal(t, EventStatus, eventType)
+	assert.Contains(t, string(data), "queue manager unavailable")
+}
+
+func TestHubDisconnectsSlowClient(t *testing.T) {
+	hub := NewHub(2, logrus.New())
+	client := &liveClient{events: make(chan Event, 2), done: make(chan struct{})}
+	hub.clients[client] = struct{}{}
+
+	require.NoError(t, hub.Write(context.Background(), queueBatch(5, time.Now())))
+
+	assert.True(t, client.stopped)
+	assert.Len(t, client.events, 2)
+	select {
+	case <-client.done:
+	default:
+		t.Fatal("slow client was not stopped")
+	}
+}
+
+func TestHubCloseEndsStreams(t *testing.T) {
+	hub := NewHub(16, logrus.New())
+	ws := dialHub(t, hub, "")
+
+	require.NoError(t, hub.Close())
+
+	var event Event
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	assert.Error(t, websocket.JSON.Receive(ws, &event))
+	assert.Equal(t, "websocket", hub.Name())
+}
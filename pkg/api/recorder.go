@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+)
+
+// Cycle is the set of records parsed in one collection cycle
+type Cycle struct {
+	Time  time.Time
+	Batch *sinks.Batch
+}
+
+// Recorder keeps the records of the last N collection cycles in memory for the API.
+// It implements sinks.Sink so the collector feeds it like any other output.
+type Recorder struct {
+	mu     sync.RWMutex
+	size   int
+	cycles []Cycle
+}
+
+// NewRecorder creates a recorder keeping size cycles
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+	return &Recorder{size: size}
+}
+
+// Name identifies the recorder in sink logs
+func (r *Recorder) Name() string {
+	return "api"
+}
+
+// Write records a collection cycle, dropping the oldest once the recorder is full
+func (r *Recorder) Write(ctx context.Context, batch *sinks.Batch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cycles = append(r.cycles, Cycle{Time: time.Now(), Batch: batch})
+	if len(r.cycles) > r.size {
+		r.cycles = append([]Cycle(nil), r.cycles[len(r.cycles)-r.size:]...)
+	}
+	return nil
+}
+
+// Close does nothing; the recorder holds no resources
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Cycles returns the recorded cycles, oldest first
+func (r *Recorder) Cycles() []Cycle {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Cycle(nil), r.cycles...)
+}
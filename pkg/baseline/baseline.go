@@ -0,0 +1,163 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+)
+
+// Slots is the number of hours in a week; each series has a baseline per hour of the week
+const Slots = 7 * 24
+
+// Smallest standard deviation a deviation is divided by, so a slot whose values never
+// changed does not make the first different value infinitely unusual
+const minStdDev = 1.0
+
+// Deviation compares a value with the baseline of its hour of the week
+type Deviation struct {
+	// Expected is the baseline mean of the hour of the week
+	Expected float64
+	// Score is the number of standard deviations the value lies above (positive) or
+	// below (negative) the baseline; 0 until the slot is Ready
+	Score float64
+	// Ready is true once the slot has min_samples values
+	Ready bool
+}
+
+// slot is the baseline of one hour of the week
+type slot struct {
+	Samples  int     `json:"samples"`
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+}
+
+// add updates the running mean and variance. Once the slot has maxSamples values, older
+// values decay exponentially so the baseline follows gradual changes.
+func (s *slot) add(value float64, maxSamples int) {
+	s.Samples++
+	weight := 1 / float64(min(s.Samples, maxSamples))
+	diff := value - s.Mean
+	s.Mean += weight * diff
+	s.Variance = (1 - weight) * (s.Variance + weight*diff*diff)
+}
+
+// Learner learns a weekly seasonality baseline per series: the mean and variance of its
+// values in each hour of the week, so a value can be judged against what is usual for
+// that time rather than against the last hour
+type Learner struct {
+	minSamples int
+	maxSamples int
+	location   *time.Location
+
+	mu     sync.Mutex
+	series map[string]*[Slots]slot
+}
+
+// NewLearner creates a learner from the baseline configuration
+func NewLearner(cfg *config.BaselineConfig) (*Learner, error) {
+	location := time.Local
+	if cfg.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid baseline timezone %s: %w", cfg.Timezone, err)
+		}
+	}
+	return &Learner{
+		minSamples: cfg.MinSamples,
+		maxSamples: cfg.MaxSamples,
+		location:   location,
+		series:     make(map[string]*[Slots]slot),
+	}, nil
+}
+
+// Observe scores a value of the series at the given time against the baseline of its
+// hour of the week and then adds it to that baseline
+func (l *Learner) Observe(key string, at time.Time, value float64) Deviation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots := l.series[key]
+	if slots == nil {
+		slots = &[Slots]slot{}
+		l.series[key] = slots
+	}
+	s := &slots[l.slotOf(at)]
+
+	deviation := Deviation{Expected: s.Mean, Ready: s.Samples >= l.minSamples}
+	if deviation.Ready {
+		deviation.Score = (value - s.Mean) / math.Max(math.Sqrt(s.Variance), minStdDev)
+	} else if s.Samples == 0 {
+		deviation.Expected = value
+	}
+
+	s.add(value, l.maxSamples)
+	return deviation
+}
+
+// slotOf returns the hour of the week of the time, counted from Sunday midnight
+func (l *Learner) slotOf(at time.Time) int {
+	at = at.In(l.location)
+	return int(at.Weekday())*24 + at.Hour()
+}
+
+// Save writes the baselines to path as JSON, replacing the file atomically
+func (l *Learner) Save(path string) error {
+	l.mu.Lock()
+	data, err := json.Marshal(map[string]interface{}{"series": l.series})
+	l.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode baselines: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save baselines: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save baselines: %w", err)
+	}
+	return nil
+}
+
+// Load replaces the baselines with those saved in path. A missing file is not an error.
+func (l *Learner) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read baselines: %w", err)
+	}
+
+	var saved struct {
+		Series map[string]*[Slots]slot `json:"series"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid baselines in %s: %w", path, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.series = make(map[string]*[Slots]slot, len(saved.Series))
+	for key, slots := range saved.Series {
+		if slots != nil {
+			l.series[key] = slots
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLearner(t *testing.T) *Learner {
+	learner, err := NewLearner(&config.BaselineConfig{MinSamples: 3, MaxSamples: 10, Timezone: "UTC"})
+	require.NoError(t, err)
+	return learner
+}
+
+func TestLearnerHourOfWeek(t *testing.T) {
+	learner := testLearner(t)
+	// Monday 09:00, busy every week, and Monday 03:00, quiet every week
+	busy := time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC)
+	quiet := time.Date(2026, 3, 2, 3, 15, 0, 0, time.UTC)
+
+	for week := 0; week < 3; week++ {
+		offset := time.Duration(week) * 7 * 24 * time.Hour
+		learner.Observe("QM1/ORDERS", busy.Add(offset), float64(1000+week*10))
+		learner.Observe("QM1/ORDERS", quiet.Add(offset), float64(10+week))
+	}
+
+	fourth := 3 * 7 * 24 * time.Hour
+	deviation := learner.Observe("QM1/ORDERS", busy.Add(fourth), 1000)
+	require.True(t, deviation.Ready)
+	assert.InDelta(t, 1010, deviation.Expected, 0.001)
+	assert.Less(t, deviation.Score, 0.0)
+	assert.Greater(t, deviation.Score, -2.0, "a usual Monday morning")
+
+	deviation = learner.Observe("QM1/ORDERS", quiet.Add(fourth), 1000)
+	require.True(t, deviation.Ready)
+	assert.Greater(t, deviation.Score, 100.0, "unusual for the night")
+}
+
+func TestLearnerNotReady(t *testing.T) {
+	learner := testLearner(t)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	deviation := learner.Observe("q", at, 50)
+	assert.False(t, deviation.Ready)
+	assert.Zero(t, deviation.Score)
+	assert.Equal(t, 50.0, deviation.Expected)
+}
+
+func TestLearnerInvalidTimezone(t *testing.T) {
+	_, err := NewLearner(&config.BaselineConfig{MinSamples: 3, MaxSamples: 10, Timezone: "Mars/Olympus"})
+	assert.Error(t, err)
+}
+
+func TestLearnerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baselines.json")
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	learner := testLearner(t)
+	require.NoError(t, learner.Load(path), "a missing file starts empty")
+	for week := 0; week < 3; week++ {
+		learner.Observe("q", at.Add(time.Duration(week)*7*24*time.Hour), 100)
+	}
+	require.NoError(t, learner.Save(path))
+
+	restored := testLearner(t)
+	require.NoError(t, restored.Load(path))
+	deviation := restored.Observe("q", at.Add(3*7*24*time.Hour), 100)
+	assert.True(t, deviation.Ready)
+	assert.Equal(t, 100.0, deviation.Expected)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	assert.Error(t, restored.Load(path))
+}
@@ -2,48 +2,98 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/atulksin/ibmmq-go-stat-otel/internal/otel"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/alerts"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/api"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/grpcapi"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/prometheus"
-	"github.com/sirupsen/logrus"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/resmon"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/spool"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/store"
 )
 
 // Collector is the main IBM MQ statistics collector
 type Collector struct {
 	config              *config.Config
-	logger              *logrus.Logger
-	mqClient            *mqclient.MQClient
-	pcfParser           *pcf.Parser
+	logger              logging.Logger
+	mqClient            mqclient.MQClientAPI
+	pcfParser           *pcf.ConcurrentParser
 	prometheusCollector *prometheus.MetricsCollector
 	otelProvider        *otel.OTelProvider
+	sinks               []sinks.Sink
+	history             *store.Store
+	grpcServer          *grpcapi.Server
+	live                *api.Hub
+	sampler             *logging.Sampler // nil when log sampling is disabled
+	resourceMonitor     *resmon.Monitor  // nil when resource monitoring is disabled
+	eventParser         *pcf.Parser      // nil when performance events are disabled
+	cycle               *logging.Cycle
 
 	// Runtime state
-	running        bool
-	cycleCount     int
-	lastCollection time.Time
+	running          bool
+	stopped          bool
+	cycleCount       int
+	lastCollection   time.Time
+	maxDepthsRefresh time.Time
+	intervalsInquiry bool      // STATINT and ACCTINT were inquired for the gap detection
+	eventQueueOpen   bool      // the performance event queue is open on this connection
+	qmgrStarted      time.Time // start time of the queue manager, to detect restarts
+	emptyStatistics  int       // cycles in a row without statistics messages
+	emptyAccounting  int       // cycles in a row without accounting messages
+
+	// Readiness, read concurrently by the /ready handler
+	connected atomic.Bool
+	collected atomic.Bool
 
 	// Collection statistics
 	totalStatsMessages      int64
 	totalAccountingMessages int64
 	totalCollections        int64
 	errorCount              int64
+	parseErrors             int64
 }
 
 // NewCollector creates a new IBM MQ statistics collector
-func NewCollector(cfg *config.Config, logger *logrus.Logger) (*Collector, error) {
-	// Create MQ client
-	mqClient := mqclient.NewMQClient(&cfg.MQ, logger)
+func NewCollector(cfg *config.Config, logger logging.Logger) (*Collector, error) {
+	// Every entry logged during a cycle, by whichever package, carries the cycle's ID
+	cycle := &logging.Cycle{}
+	logger = logging.WithCycle(logger, cycle)
+
+	return newCollector(cfg, mqclient.NewMQClient(&cfg.MQ, logger), cycle, logger)
+}
+
+// NewCollectorWithClient creates a collector getting its messages through mqClient, such
+// as an in-memory client of package mqfake
+func NewCollectorWithClient(cfg *config.Config, mqClient mqclient.MQClientAPI, logger logging.Logger) (*Collector, error) {
+	cycle := &logging.Cycle{}
+	return newCollector(cfg, mqClient, cycle, logging.WithCycle(logger, cycle))
+}
+
+// newCollector creates a collector logging through logger, which adds the ID of cycle
+func newCollector(cfg *config.Config, mqClient mqclient.MQClientAPI, cycle *logging.Cycle, logger logging.Logger) (*Collector, error) {
 
 	// Create PCF parser
-	pcfParser := pcf.NewParser(logger)
+	pcfParser := pcf.NewConcurrentParser(logger, cfg.Collector.ParseWorkers)
+
+	// The packages below log through logrus; entries reach logger either way
+	logrusLogger := logging.ToLogrus(logger)
 
 	// Create Prometheus collector
-	prometheusCollector := prometheus.NewMetricsCollector(cfg, mqClient, logger)
+	prometheusCollector := prometheus.NewMetricsCollector(cfg, mqClient, logrusLogger)
+	prometheusCollector.SetCycle(cycle)
 
 	// Create OpenTelemetry provider if enabled
 	var otelProvider *otel.OTelProvider
@@ -53,6 +103,66 @@ func NewCollector(cfg *config.Config, logger *logrus.Logger) (*Collector, error)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create OTel provider: %w", err)
 		}
+		// The metrics endpoint serves the queue, channel and application metrics
+		// alongside those of the provider
+		otelProvider.AddGatherer(prometheusCollector.Gatherer())
+	}
+
+	// Create the additional sinks fed with every collected record
+	sinkList, err := sinks.NewFromConfig(&cfg.Sinks, logrusLogger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sinks: %w", err)
+	}
+
+	// The history store is written like a sink
+	var history *store.Store
+	if cfg.Store.Enabled {
+		history, err = store.Open(&cfg.Store, logrusLogger)
+		if err != nil {
+			return nil, err
+		}
+		sinkList = append(sinkList, history)
+	}
+
+	// The REST API serves the last cycles recorded like a sink, on the metrics HTTP server
+	if cfg.API.Enabled {
+		if otelProvider == nil {
+			return nil, fmt.Errorf("the REST API is served by the metrics HTTP server and requires prometheus.enable_otel")
+		}
+		recorder := api.NewRecorder(cfg.API.Cycles)
+		sinkList = append(sinkList, recorder)
+		var handler http.Handler = api.NewHandler(recorder, history, logrusLogger)
+		if cfg.Prometheus.Auth.API {
+			handler = api.RequireToken(cfg.Prometheus.Auth.Tokens, handler)
+		}
+		otelProvider.Handle("/api/v1/", handler)
+	}
+
+	// The live event stream pushes records and status changes to browsers on /ws
+	var live *api.Hub
+	if cfg.WebSocket.Enabled {
+		if otelProvider == nil {
+			return nil, fmt.Errorf("the websocket stream is served by the metrics HTTP server and requires prometheus.enable_otel")
+		}
+		live = api.NewHub(cfg.WebSocket.BufferSize, logrusLogger)
+		sinkList = append(sinkList, live)
+		var handler http.Handler = live
+		if cfg.Prometheus.Auth.WebSocket {
+			handler = api.RequireToken(cfg.Prometheus.Auth.Tokens, handler)
+		}
+		otelProvider.Handle("/ws", handler)
+	}
+
+	// Alert rules are evaluated against the records of every cycle
+	if cfg.Alerts.Enabled {
+		sinkList = append(sinkList, alerts.NewNotifier(&cfg.Alerts, logrusLogger))
+	}
+
+	// The gRPC server streams every cycle to its subscribers
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		grpcServer = grpcapi.NewServer(&cfg.GRPC, logrusLogger)
+		sinkList = append(sinkList, grpcServer)
 	}
 
 	collector := &Collector{
@@ -62,19 +172,53 @@ func NewCollector(cfg *config.Config, logger *logrus.Logger) (*Collector, error)
 		pcfParser:           pcfParser,
 		prometheusCollector: prometheusCollector,
 		otelProvider:        otelProvider,
+		sinks:               sinkList,
+		history:             history,
+		grpcServer:          grpcServer,
+		live:                live,
+		cycle:               cycle,
 		running:             false,
 		cycleCount:          0,
 	}
+	if cfg.PerformanceEvents.Enabled {
+		collector.eventParser = pcf.NewParser(logger)
+	}
+	if cfg.ResourceMonitor.Enabled {
+		collector.resourceMonitor = resmon.NewMonitor(cfg.ResourceMonitor, cfg.MQ.QueueManager, mqClient, logger)
+	}
+	if sampling := cfg.Logging.Sampling; sampling.Enabled {
+		collector.sampler = logging.NewSampler(sampling.First, sampling.Every)
+	}
+
+	if otelProvider != nil {
+		otelProvider.SetReadiness(collector.Readiness)
+	}
 
-	logger.WithFields(logrus.Fields{
+	logger.WithFields(logging.Fields{
 		"queue_manager": cfg.MQ.QueueManager,
 		"channel":       cfg.MQ.Channel,
 		"otel_enabled":  cfg.Prometheus.EnableOTel,
+		"sinks":         len(sinkList),
 	}).Info("Created IBM MQ statistics collector")
 
 	return collector, nil
 }
 
+// History returns the local history store, or nil when it is disabled
+func (c *Collector) History() *store.Store {
+	return c.history
+}
+
+// SetBuildInfo exposes the collector version, commit and build date as metrics
+func (c *Collector) SetBuildInfo(version, commit, date string) {
+	c.prometheusCollector.SetBuildInfo(version, commit, date)
+}
+
+// WriteMetricsTextfile writes the collected metrics to path in the Prometheus text format
+func (c *Collector) WriteMetricsTextfile(path string) error {
+	return c.prometheusCollector.WriteTextfile(path)
+}
+
 // Start starts the collector and begins collecting metrics
 func (c *Collector) Start(ctx context.Context) error {
 	if c.running {
@@ -83,11 +227,51 @@ func (c *Collector) Start(ctx context.Context) error {
 
 	c.logger.Info("Starting IBM MQ statistics collector")
 
+	// Start OpenTelemetry HTTP server if enabled; /ready reports 503 until data has been collected
+	if c.otelProvider != nil {
+		if err := c.otelProvider.StartHTTPServer(ctx); err != nil {
+			return fmt.Errorf("failed to start OTel HTTP server: %w", err)
+		}
+	}
+
+	if c.grpcServer != nil {
+		if err := c.grpcServer.Start(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := c.connect(); err != nil {
+		return err
+	}
+
+	c.running = true
+
+	// Start collection based on configuration
+	if c.config.Collector.Continuous {
+		c.detectRestart()
+		return c.runContinuous(ctx)
+	} else {
+		return c.runOnce(ctx)
+	}
+}
+
+// connect connects to IBM MQ and opens the statistics and accounting queues
+func (c *Collector) connect() error {
+	if c.mqClient.IsConnected() {
+		return nil
+	}
+
 	// Connect to IBM MQ
 	if err := c.mqClient.Connect(); err != nil {
 		return fmt.Errorf("failed to connect to IBM MQ: %w", err)
 	}
 
+	// Subscriptions and queues opened later end with the connection they were made on
+	if c.resourceMonitor != nil {
+		c.resourceMonitor.Reset()
+	}
+	c.eventQueueOpen = false
+
 	// Open statistics queue
 	if err := c.mqClient.OpenStatsQueue(c.config.Collector.StatsQueue); err != nil {
 		c.logger.WithError(err).Warn("Failed to open statistics queue, continuing without it")
@@ -98,28 +282,173 @@ func (c *Collector) Start(ctx context.Context) error {
 		c.logger.WithError(err).Warn("Failed to open accounting queue, continuing without it")
 	}
 
-	// Start OpenTelemetry HTTP server if enabled
-	if c.otelProvider != nil {
-		if err := c.otelProvider.StartHTTPServer(ctx); err != nil {
-			return fmt.Errorf("failed to start OTel HTTP server: %w", err)
+	c.connected.Store(true)
+	c.publishStatus(api.Status{State: api.StateConnected})
+
+	// A connection made again while running may follow a restart of the queue manager
+	if c.running {
+		c.detectRestart()
+	}
+	return nil
+}
+
+// detectRestart inquires when the queue manager was started. A later start time than
+// the one seen before means it restarted since, so the metrics derived from successive
+// intervals start over. Without the authority to inquire the queue manager status,
+// restarts go undetected.
+func (c *Collector) detectRestart() {
+	started, err := c.mqClient.InquireStartTime()
+	if err != nil {
+		c.logger.WithError(err).Debug("Failed to inquire queue manager start time")
+		return
+	}
+
+	previous := c.qmgrStarted
+	c.qmgrStarted = started
+	if previous.IsZero() || !started.After(previous) {
+		return
+	}
+
+	c.logger.WithFields(logging.Fields{
+		"queue_manager": c.config.MQ.QueueManager,
+		"started":       started,
+		"previous":      previous,
+	}).Warn("Queue manager restarted, resetting the derived metrics baselines")
+	c.prometheusCollector.QueueManagerRestarted(c.config.MQ.QueueManager)
+}
+
+// checkConnection verifies that the connection to IBM MQ still works and makes it again
+// if it was lost. A check failing for another reason, such as a missing authority to
+// inquire the queue manager, leaves the connection as it is.
+func (c *Collector) checkConnection() {
+	if c.mqClient.IsConnected() {
+		err := c.mqClient.CheckConnection()
+		if err == nil {
+			return
+		}
+		if !mqclient.IsConnectionBroken(err) {
+			c.logger.WithError(err).Debug("Connection check failed")
+			return
 		}
+
+		c.logger.WithError(err).Warn("Connection to IBM MQ lost, reconnecting")
+		c.mqClient.Disconnect()
+		c.connected.Store(false)
 	}
 
-	c.running = true
+	if err := c.connect(); err != nil {
+		c.logger.WithError(err).Error("Failed to reconnect to IBM MQ")
+		return
+	}
+	c.logger.Info("Reconnected to IBM MQ")
+}
 
-	// Start collection based on configuration
-	if c.config.Collector.Continuous {
-		return c.runContinuous(ctx)
-	} else {
-		return c.runOnce(ctx)
+// publishStatus sends a collector status event to live clients, if enabled
+func (c *Collector) publishStatus(status api.Status) {
+	if c.live == nil {
+		return
 	}
+	status.Collections = c.totalCollections
+	status.CycleID = c.cycle.ID()
+	c.live.Publish(status)
 }
 
-// Stop stops the collector
+// Records holds the parsed data from a single drain of the statistics and accounting queues
+type Records struct {
+	Statistics  []*pcf.StatisticsData
+	Accounting  []*pcf.AccountingData
+	ParseErrors int
+	CycleID     string // ID of the cycle in the log entries
+}
+
+// CollectRecords connects to IBM MQ if necessary, drains both queues once and returns
+// the parsed records without updating any exporters
+func (c *Collector) CollectRecords(ctx context.Context) (*Records, error) {
+	records := &Records{CycleID: c.cycle.Start()}
+	defer c.cycle.End()
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+
+	statsMessages, accountingMessages, err := c.mqClient.GetStatisticsAndAccounting()
+	if len(mqclient.FailedQueues(err)) == 2 {
+		return nil, err
+	}
+	if err != nil {
+		c.logger.WithError(err).Warn("Returning the records of one queue only")
+	}
+	for _, result := range c.pcfParser.ParseMessages(mqclient.Payloads(statsMessages), "statistics") {
+		c.parseInto(records, result, "statistics")
+	}
+	for _, result := range c.pcfParser.ParseMessages(mqclient.Payloads(accountingMessages), "accounting") {
+		c.parseInto(records, result, "accounting")
+	}
+	if err := c.mqClient.Commit(); err != nil {
+		return nil, err
+	}
+
+	c.totalStatsMessages += int64(len(statsMessages))
+	c.totalAccountingMessages += int64(len(accountingMessages))
+	c.logSuppressed()
+
+	return records, nil
+}
+
+// parseInto appends a parsed message to records
+func (c *Collector) parseInto(records *Records, result pcf.Result, msgType string) {
+	if result.Err != nil {
+		if logger := c.sampled(msgType + " parse: " + logging.ErrorClass(result.Err)); logger != nil {
+			logger.WithError(result.Err).WithField("message_type", msgType).Warn("Failed to parse message")
+		}
+		records.ParseErrors++
+		c.parseErrors++
+		return
+	}
+
+	switch d := result.Data.(type) {
+	case *pcf.StatisticsData:
+		records.Statistics = append(records.Statistics, d)
+	case *pcf.AccountingData:
+		records.Accounting = append(records.Accounting, d)
+	}
+}
+
+// sampled returns the logger for a problem that may repeat on every message, or nil
+// while the entries of its class are suppressed
+func (c *Collector) sampled(class string) logging.Logger {
+	ok, suppressed := c.sampler.Allow(class)
+	if !ok {
+		return nil
+	}
+	if suppressed > 0 {
+		return c.logger.WithField("suppressed", suppressed)
+	}
+	return c.logger
+}
+
+// logSuppressed logs how many entries of each class were suppressed in the cycle
+func (c *Collector) logSuppressed() {
+	for class, suppressed := range c.sampler.Flush() {
+		c.logger.WithFields(logging.Fields{
+			"class":      class,
+			"suppressed": suppressed,
+		}).Warn("Suppressed repeated log entries")
+	}
+}
+
+// Disconnect closes the IBM MQ connection without requiring the collector to be running
+func (c *Collector) Disconnect() error {
+	return c.mqClient.Disconnect()
+}
+
+// Stop stops the collector, closing its sinks and servers and disconnecting from IBM MQ,
+// whether or not it is still running. Only the first call does anything.
 func (c *Collector) Stop(ctx context.Context) error {
-	if !c.running {
+	if c.stopped {
 		return nil
 	}
+	c.stopped = true
 
 	c.logger.Info("Stopping IBM MQ statistics collector")
 	c.running = false
@@ -131,17 +460,25 @@ func (c *Collector) Stop(ctx context.Context) error {
 		}
 	}
 
+	// Flush and close sinks
+	for _, sink := range c.sinks {
+		if err := sink.Close(); err != nil {
+			c.logger.WithError(err).WithField("sink", sink.Name()).Error("Error closing sink")
+		}
+	}
+
 	// Disconnect from IBM MQ
 	if err := c.mqClient.Disconnect(); err != nil {
 		c.logger.WithError(err).Error("Error disconnecting from IBM MQ")
 		return err
 	}
 
-	c.logger.WithFields(logrus.Fields{
+	c.logger.WithFields(logging.Fields{
 		"total_collections":         c.totalCollections,
 		"total_stats_messages":      c.totalStatsMessages,
 		"total_accounting_messages": c.totalAccountingMessages,
 		"error_count":               c.errorCount,
+		"parse_errors":              c.ParseErrors(),
 	}).Info("IBM MQ statistics collector stopped")
 
 	return nil
@@ -153,7 +490,6 @@ func (c *Collector) runOnce(ctx context.Context) error {
 
 	err := c.collectMetrics(ctx)
 	if err != nil {
-		c.errorCount++
 		return fmt.Errorf("collection failed: %w", err)
 	}
 
@@ -163,7 +499,7 @@ func (c *Collector) runOnce(ctx context.Context) error {
 
 // runContinuous runs continuous collection based on configured interval
 func (c *Collector) runContinuous(ctx context.Context) error {
-	c.logger.WithFields(logrus.Fields{
+	c.logger.WithFields(logging.Fields{
 		"interval":   c.config.Collector.Interval,
 		"max_cycles": c.config.Collector.MaxCycles,
 	}).Info("Starting continuous collection")
@@ -171,24 +507,30 @@ func (c *Collector) runContinuous(ctx context.Context) error {
 	ticker := time.NewTicker(c.config.Collector.Interval)
 	defer ticker.Stop()
 
-	// Run initial collection immediately
-	if err := c.collectMetrics(ctx); err != nil {
-		c.logger.WithError(err).Error("Initial collection failed")
-		c.errorCount++
+	// The connection is checked between cycles, so one lost while idle is made again
+	// before the next cycle instead of failing it
+	var liveness <-chan time.Time
+	if interval := c.config.Collector.LivenessInterval; interval > 0 {
+		livenessTicker := time.NewTicker(interval)
+		defer livenessTicker.Stop()
+		liveness = livenessTicker.C
 	}
 
+	// Run initial collection immediately
+	c.collectMetrics(ctx)
+
 	for c.running {
 		select {
 		case <-ctx.Done():
 			c.logger.Info("Context cancelled, stopping continuous collection")
 			return ctx.Err()
 
+		case <-liveness:
+			c.checkConnection()
+
 		case <-ticker.C:
-			if err := c.collectMetrics(ctx); err != nil {
-				c.logger.WithError(err).Error("Collection cycle failed")
-				c.errorCount++
-				// Continue running even if a cycle fails
-			}
+			// Continue running even if a cycle fails
+			c.collectMetrics(ctx)
 
 			c.cycleCount++
 
@@ -204,72 +546,417 @@ func (c *Collector) runContinuous(ctx context.Context) error {
 	return nil
 }
 
-// collectMetrics performs a single metrics collection cycle
+// collectMetrics performs a single metrics collection cycle under a new cycle ID. A
+// failed cycle is logged, counted and reported to live clients before its ID is cleared.
 func (c *Collector) collectMetrics(ctx context.Context) error {
+	c.cycle.Start()
+	defer c.cycle.End()
+
+	err := c.collectCycle(ctx)
+	if err != nil {
+		c.logger.WithError(err).WithField("error_class", mqclient.Classify(err).String()).Error("Collection cycle failed")
+		c.errorCount++
+		c.publishStatus(api.Status{State: api.StateFailed, Error: err.Error()})
+	}
+	return err
+}
+
+// collectCycle drains the queues once and updates every exporter and sink
+func (c *Collector) collectCycle(ctx context.Context) error {
 	c.logger.Debug("Starting metrics collection cycle")
 	startTime := time.Now()
 
-	// Collect from Prometheus collector
-	if err := c.prometheusCollector.CollectMetrics(ctx); err != nil {
-		return fmt.Errorf("prometheus collection failed: %w", err)
+	// Scrapes see the updates of the whole cycle at once, as it ends
+	defer c.prometheusCollector.EndCycle()
+
+	// Reconnect if the connection was lost and could not be made again since
+	if err := c.connect(); err != nil {
+		return err
 	}
 
-	// Get messages for OTel processing if enabled
-	if c.otelProvider != nil {
-		if err := c.collectForOTel(ctx); err != nil {
-			c.logger.WithError(err).Error("OTel collection failed")
-			// Don't return error, continue with prometheus-only collection
+	// Drain both queues once so that every exporter and sink sees the same messages. With
+	// the spool enabled, messages beyond its memory limit wait on disk.
+	var memoryLimit int64
+	if c.config.Spool.Enabled {
+		memoryLimit = int64(c.config.Spool.MemoryLimitMB) << 20
+	}
+	buffer := spool.NewBuffer(c.config.Spool.Directory, memoryLimit)
+	defer buffer.Close()
+
+	add := buffer.Add
+	if limit := c.config.Resources.MaxInFlight; limit > 0 {
+		// Later messages stay on their queue for the next cycle
+		add = func(msg *mqclient.MQMessage) error {
+			if err := buffer.Add(msg); err != nil {
+				return err
+			}
+			if buffer.Count(msg.Type) >= limit {
+				return mqclient.ErrStopDrain
+			}
+			return nil
 		}
 	}
+	// The queues fail independently; the messages got are processed either way, as
+	// they are already off their queues
+	drainErr := c.mqClient.DrainStatisticsAndAccounting(add)
+	failedQueues := mqclient.FailedQueues(drainErr)
+	c.prometheusCollector.SetDrainResult(drainErr)
+	for _, queueType := range failedQueues {
+		c.logger.WithError(drainErr).WithField("queue_type", queueType).Error("Failed to drain queue")
+	}
+
+	statsCount, accountingCount := buffer.Count("stats"), buffer.Count("accounting")
+	c.totalStatsMessages += int64(statsCount)
+	c.totalAccountingMessages += int64(accountingCount)
+	if spilled := buffer.Spilled(); spilled > 0 {
+		c.logger.WithField("messages", spilled).Info("Spilled messages beyond the memory limit to disk")
+	}
+	c.checkEmptyQueues(statsCount, accountingCount, failedQueues)
+
+	if c.config.Forecast.Enabled && time.Since(c.maxDepthsRefresh) >= c.config.Forecast.MaxDepthRefresh {
+		c.refreshMaxDepths()
+	}
+
+	if c.config.LiveDepth.Enabled {
+		c.inquireLiveDepths()
+	}
+
+	if c.resourceMonitor != nil {
+		c.collectResourceMetrics()
+	}
+
+	if c.eventParser != nil {
+		c.collectPerformanceEvents()
+	}
+
+	if c.config.Intervals.Enabled && !c.intervalsInquiry {
+		c.inquireIntervals()
+	}
+
+	err := buffer.Batches(func(messages []*mqclient.MQMessage) error {
+		statsMessages, accountingMessages := splitMessages(messages)
+
+		// Each batch is parsed once, for Prometheus, OTel and the sinks alike
+		statsResults := c.pcfParser.ParseMessages(mqclient.Payloads(statsMessages), "statistics")
+		accountingResults := c.pcfParser.ParseMessages(mqclient.Payloads(accountingMessages), "accounting")
+
+		// Update Prometheus metrics
+		c.prometheusCollector.ProcessResults(statsMessages, statsResults, accountingMessages, accountingResults)
+
+		// Record the same records for OTel if enabled
+		if c.otelProvider != nil {
+			c.collectForOTel(ctx, statsResults, accountingResults)
+		}
+
+		// Deliver the parsed records to the configured sinks. Sinks may keep them, such
+		// as the REST API recorder, so they only go back to the pools without any.
+		c.writeSinks(ctx, statsResults, accountingResults)
+		if len(c.sinks) == 0 {
+			pcf.ReleaseResults(statsResults)
+			pcf.ReleaseResults(accountingResults)
+		}
+		return nil
+	})
+	if err != nil {
+		// Under syncpoint the messages go back to their queues for the next cycle
+		if backoutErr := c.mqClient.Backout(); backoutErr != nil {
+			c.logger.WithError(backoutErr).Error("Failed to back out messages")
+		}
+		return err
+	}
+	if err := c.mqClient.Commit(); err != nil {
+		return err
+	}
+	if len(failedQueues) == 2 {
+		return drainErr
+	}
 
 	c.totalCollections++
 	c.lastCollection = time.Now()
+	c.collected.Store(true)
+	c.logSuppressed()
 
 	duration := time.Since(startTime)
-	c.logger.WithFields(logrus.Fields{
+	status := api.Status{
+		State:              api.StateCollected,
+		StatisticsMessages: statsCount,
+		AccountingMessages: accountingCount,
+		DurationMillis:     duration.Milliseconds(),
+	}
+	if drainErr != nil {
+		status.State, status.Error = api.StatePartial, drainErr.Error()
+	}
+	c.publishStatus(status)
+
+	c.logger.WithFields(logging.Fields{
 		"duration":          duration,
 		"cycle_count":       c.cycleCount,
 		"total_collections": c.totalCollections,
 	}).Info("Metrics collection cycle completed")
 
-	// Reset statistics if configured
 	if c.config.Collector.ResetStats {
-		c.logger.Debug("Resetting statistics as configured")
-		// Note: Actual MQ statistics reset would require additional MQ administration commands
-		// This is a placeholder for that functionality
+		c.resetStatistics()
 	}
 
 	return nil
 }
 
-// collectForOTel collects and records metrics specifically for OpenTelemetry
-func (c *Collector) collectForOTel(ctx context.Context) error {
-	// Get statistics messages
-	statsMessages, err := c.mqClient.GetAllMessages("stats")
+// resetStatistics resets the statistics of the configured queues with Reset Queue
+// Statistics, as amqsmon-style tools do, and exports the values reported for the time
+// since the previous reset. A failed reset is logged without failing the cycle, and the
+// values of the queues reset before a failure are still exported, as their counters are
+// already cleared on the queue manager.
+func (c *Collector) resetStatistics() {
+	var reset []*mqclient.QueueResetStatistics
+	for _, pattern := range c.config.Collector.ResetQueues {
+		stats, err := c.mqClient.ResetQueueStatistics(pattern)
+		reset = append(reset, stats...)
+		if err != nil {
+			fields := logging.Fields{"queue_pattern": pattern, "reset_queues": len(stats)}
+			var resetErr *mqclient.ResetError
+			if errors.As(err, &resetErr) && len(resetErr.Queues) > 0 {
+				fields["failed_queues"] = strings.Join(resetErr.Queues, ",")
+			}
+			c.logger.WithError(err).WithFields(fields).Warn("Failed to reset queue statistics")
+		}
+	}
+	c.prometheusCollector.SetResetStatistics(c.config.MQ.QueueManager, reset)
+}
+
+// splitMessages separates statistics from accounting messages, keeping their order
+func splitMessages(messages []*mqclient.MQMessage) (stats, accounting []*mqclient.MQMessage) {
+	for _, msg := range messages {
+		if msg.Type == "stats" {
+			stats = append(stats, msg)
+		} else {
+			accounting = append(accounting, msg)
+		}
+	}
+	return stats, accounting
+}
+
+// refreshMaxDepths inquires the MAXDEPTH of every local queue for the time-to-full
+// forecast. Failures, such as a missing command server authority, are retried at the
+// next refresh and only disable the forecast until then.
+func (c *Collector) refreshMaxDepths() {
+	c.maxDepthsRefresh = time.Now()
+
+	queues, err := c.mqClient.InquireQueues("*")
 	if err != nil {
-		return fmt.Errorf("failed to get stats messages: %w", err)
+		c.logger.WithError(err).Warn("Failed to inquire queue maximum depths for the backlog forecast")
+		return
+	}
+
+	for _, queue := range queues {
+		c.prometheusCollector.SetMaxDepth(c.config.MQ.QueueManager, queue.QueueName, queue.MaxDepth)
 	}
 
-	// Get accounting messages
-	accountingMessages, err := c.mqClient.GetAllMessages("accounting")
+	c.logger.WithField("queues", len(queues)).Debug("Refreshed queue maximum depths")
+}
+
+// inquireLiveDepths inquires the current depth of the configured queues, which the
+// statistics only report at the end of each interval. Queues that cannot be inquired are
+// logged and left out until the next cycle.
+func (c *Collector) inquireLiveDepths() {
+	queues, err := c.mqClient.InquireQueueDepths(c.config.LiveDepth.Queues)
 	if err != nil {
-		return fmt.Errorf("failed to get accounting messages: %w", err)
+		c.logger.WithError(err).Warn("Failed to inquire the depth of some queues")
 	}
+	c.prometheusCollector.SetLiveDepths(c.config.MQ.QueueManager, queues)
+}
 
-	c.totalStatsMessages += int64(len(statsMessages))
-	c.totalAccountingMessages += int64(len(accountingMessages))
+// collectResourceMetrics exports the resource monitoring publications received since the
+// previous cycle, subscribing first if not yet subscribed on this connection. A failure is
+// logged and the subscription tried again in the next cycle. Without new publications,
+// as when the collection interval is shorter than the publication interval, the previous
+// values stay.
+func (c *Collector) collectResourceMetrics() {
+	if !c.resourceMonitor.Subscribed() {
+		if err := c.resourceMonitor.Subscribe(); err != nil {
+			c.logger.WithError(err).Warn("Failed to subscribe to resource monitoring topics")
+			return
+		}
+	}
+
+	metrics, err := c.resourceMonitor.Collect()
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to get resource monitoring publications")
+		c.resourceMonitor.Reset()
+		return
+	}
+	if len(metrics) > 0 {
+		c.prometheusCollector.SetResourceMetrics(c.config.MQ.QueueManager, metrics)
+	}
+}
 
-	// Process statistics messages for OTel
-	for _, msg := range statsMessages {
-		if err := c.processStatsMessageForOTel(ctx, msg); err != nil {
-			c.logger.WithError(err).Error("Failed to process stats message for OTel")
+// collectPerformanceEvents consumes the events on the performance event queue, opening
+// it first if not yet open on this connection, and counts the queue depth, queue full
+// and service interval events per queue. Messages that are not performance events are
+// logged and discarded. A failure is logged and the queue tried again in the next cycle.
+func (c *Collector) collectPerformanceEvents() {
+	queue := c.config.PerformanceEvents.Queue
+	if !c.eventQueueOpen {
+		if err := c.mqClient.OpenQueue(queue, false); err != nil {
+			c.logger.WithError(err).WithField("queue", queue).Warn("Failed to open performance event queue")
+			return
 		}
+		c.eventQueueOpen = true
 	}
 
-	// Process accounting messages for OTel
-	for _, msg := range accountingMessages {
-		if err := c.processAccountingMessageForOTel(ctx, msg); err != nil {
-			c.logger.WithError(err).Error("Failed to process accounting message for OTel")
+	// The events got before a failure are off the queue, so they are counted either way
+	var events []*pcf.EventData
+	for {
+		msg, err := c.mqClient.GetQueueMessage(queue, 0)
+		if err != nil {
+			c.logger.WithError(err).WithField("queue", queue).Warn("Failed to get performance events")
+			c.eventQueueOpen = false
+			break
+		}
+		if msg == nil {
+			break
+		}
+
+		event, err := c.eventParser.ParseEvent(msg.Data)
+		if err == nil && event.EventType != pcf.EventTypeName(pcf.MQCMD_PERFM_EVENT) {
+			err = fmt.Errorf("%s is not a performance event", event.EventType)
+		}
+		if err != nil {
+			if logger := c.sampled("performance event: " + logging.ErrorClass(err)); logger != nil {
+				logger.WithError(err).WithField("queue", queue).Warn("Discarding message from performance event queue")
+			}
+			continue
+		}
+		event.Timestamp = msg.GetTimestamp()
+		events = append(events, event)
+	}
+
+	c.prometheusCollector.AddPerformanceEvents(c.config.MQ.QueueManager, events)
+	if len(events) > 0 {
+		c.logger.WithField("events", len(events)).Debug("Consumed performance events")
+	}
+}
+
+// inquireIntervals reads the STATINT and ACCTINT of the queue manager for the interval
+// gap detection. It is tried once; without them the intervals are learned from the records.
+func (c *Collector) inquireIntervals() {
+	c.intervalsInquiry = true
+
+	settings, err := c.mqClient.InquireQueueManager()
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to inquire the statistics and accounting intervals, learning them from the records")
+		return
+	}
+
+	c.prometheusCollector.SetExpectedIntervals(c.config.MQ.QueueManager,
+		time.Duration(settings.StatisticsInterval)*time.Second,
+		time.Duration(settings.AccountingInterval)*time.Second)
+
+	c.logger.WithFields(logging.Fields{
+		"statint": settings.StatisticsInterval,
+		"acctint": settings.AccountingInterval,
+	}).Debug("Inquired statistics and accounting intervals")
+}
+
+// checkEmptyQueues counts the cycles in a row in which each queue returned no messages.
+// Every EmptyCycles such cycles the queue manager is inquired whether it writes the data
+// at all, so a queue manager with statistics or accounting disabled is reported instead
+// of nothing being exported without a word. A queue that failed to drain keeps its count.
+func (c *Collector) checkEmptyQueues(statsCount, accountingCount int, failedQueues []string) {
+	limit := c.config.Collector.EmptyCycles
+	if limit == 0 {
+		return
+	}
+
+	inquire := false
+	var hints []string
+	for _, q := range []struct {
+		queueType string
+		count     int
+		streak    *int
+	}{
+		{"stats", statsCount, &c.emptyStatistics},
+		{"accounting", accountingCount, &c.emptyAccounting},
+	} {
+		switch {
+		case slices.Contains(failedQueues, q.queueType):
+		case q.count > 0:
+			*q.streak = 0
+		default:
+			*q.streak++
+			if *q.streak%limit == 0 {
+				inquire = true
+			}
+			// The hint is logged once per streak
+			if *q.streak == limit {
+				hints = append(hints, q.queueType)
+			}
+		}
+	}
+	if !inquire {
+		return
+	}
+
+	settings, err := c.mqClient.InquireQueueManager()
+	if err != nil {
+		c.logger.WithError(err).WithField("empty_cycles", limit).Warn("No messages arrived and the queue manager statistics and accounting settings could not be inquired")
+		return
+	}
+	c.prometheusCollector.SetMonitoringSettings(c.config.MQ.QueueManager, settings)
+	for _, queueType := range hints {
+		c.logMonitoringHint(queueType, settings)
+	}
+}
+
+// logMonitoringHint explains why a queue returned no messages for EmptyCycles cycles,
+// from the queue manager settings
+func (c *Collector) logMonitoringHint(queueType string, settings *mqclient.QueueManagerSettings) {
+	if queueType == "stats" {
+		fields := logging.Fields{
+			"queue":        c.config.Collector.StatsQueue,
+			"empty_cycles": c.config.Collector.EmptyCycles,
+			"statq":        mqclient.MonitoringString(settings.StatisticsQueue),
+			"statmqi":      mqclient.MonitoringString(settings.StatisticsMQI),
+			"statchl":      mqclient.MonitoringString(settings.StatisticsChannel),
+			"statint":      settings.StatisticsInterval,
+		}
+		if !mqclient.MonitoringEnabled(settings.StatisticsQueue) && !mqclient.MonitoringEnabled(settings.StatisticsMQI) &&
+			!mqclient.MonitoringEnabled(settings.StatisticsChannel) {
+			c.logger.WithFields(fields).Warn("No statistics messages arrived: statistics are disabled on the queue manager, enable them with ALTER QMGR STATQ(ON) STATMQI(ON) STATCHL(MEDIUM)")
+			return
+		}
+		c.logger.WithFields(fields).Info("No statistics messages arrived yet: the queue manager writes them every STATINT seconds")
+		return
+	}
+
+	fields := logging.Fields{
+		"queue":        c.config.Collector.AccountingQueue,
+		"empty_cycles": c.config.Collector.EmptyCycles,
+		"acctq":        mqclient.MonitoringString(settings.AccountingQueue),
+		"acctmqi":      mqclient.MonitoringString(settings.AccountingMQI),
+		"acctint":      settings.AccountingInterval,
+	}
+	if !mqclient.MonitoringEnabled(settings.AccountingQueue) && !mqclient.MonitoringEnabled(settings.AccountingMQI) {
+		c.logger.WithFields(fields).Warn("No accounting messages arrived: accounting is disabled on the queue manager, enable it with ALTER QMGR ACCTQ(ON) ACCTMQI(ON)")
+		return
+	}
+	c.logger.WithFields(fields).Info("No accounting messages arrived yet: the queue manager writes them every ACCTINT seconds and when applications disconnect")
+}
+
+// collectForOTel records the parsed records of a batch for OpenTelemetry
+func (c *Collector) collectForOTel(ctx context.Context, statsResults, accountingResults []pcf.Result) {
+	for _, result := range statsResults {
+		if err := c.processStatsMessageForOTel(ctx, result); err != nil {
+			if logger := c.sampled("statistics otel: " + logging.ErrorClass(err)); logger != nil {
+				logger.WithError(err).Error("Failed to process stats message for OTel")
+			}
+		}
+	}
+
+	for _, result := range accountingResults {
+		if err := c.processAccountingMessageForOTel(ctx, result); err != nil {
+			if logger := c.sampled("accounting otel: " + logging.ErrorClass(err)); logger != nil {
+				logger.WithError(err).Error("Failed to process accounting message for OTel")
+			}
 		}
 	}
 
@@ -277,18 +964,42 @@ func (c *Collector) collectForOTel(ctx context.Context) error {
 	if err := c.otelProvider.ForceFlush(ctx); err != nil {
 		c.logger.WithError(err).Error("Failed to flush OTel metrics")
 	}
+}
 
-	return nil
+// writeSinks delivers the parsed records of a batch to every sink. Messages that could
+// not be parsed are skipped here; the Prometheus collector already counts them as parse
+// errors.
+func (c *Collector) writeSinks(ctx context.Context, statsResults, accountingResults []pcf.Result) {
+	if len(c.sinks) == 0 {
+		return
+	}
+
+	batch := &sinks.Batch{QueueManager: c.config.MQ.QueueManager}
+	for _, result := range statsResults {
+		if stats, ok := result.Data.(*pcf.StatisticsData); ok && result.Err == nil {
+			batch.Statistics = append(batch.Statistics, stats)
+		}
+	}
+	for _, result := range accountingResults {
+		if acct, ok := result.Data.(*pcf.AccountingData); ok && result.Err == nil {
+			batch.Accounting = append(batch.Accounting, acct)
+		}
+	}
+
+	for _, sink := range c.sinks {
+		if err := sink.Write(ctx, batch); err != nil {
+			c.logger.WithError(err).WithField("sink", sink.Name()).Error("Failed to write records to sink")
+		}
+	}
 }
 
-// processStatsMessageForOTel processes a statistics message for OpenTelemetry
-func (c *Collector) processStatsMessageForOTel(ctx context.Context, msg *mqclient.MQMessage) error {
-	data, err := c.pcfParser.ParseMessage(msg.Data, "statistics")
-	if err != nil {
-		return fmt.Errorf("failed to parse statistics message: %w", err)
+// processStatsMessageForOTel processes a parsed statistics message for OpenTelemetry
+func (c *Collector) processStatsMessageForOTel(ctx context.Context, result pcf.Result) error {
+	if result.Err != nil {
+		return fmt.Errorf("failed to parse statistics message: %w", result.Err)
 	}
 
-	stats, ok := data.(*pcf.StatisticsData)
+	stats, ok := result.Data.(*pcf.StatisticsData)
 	if !ok {
 		return fmt.Errorf("invalid statistics data type")
 	}
@@ -335,14 +1046,13 @@ func (c *Collector) processStatsMessageForOTel(ctx context.Context, msg *mqclien
 	return nil
 }
 
-// processAccountingMessageForOTel processes an accounting message for OpenTelemetry
-func (c *Collector) processAccountingMessageForOTel(ctx context.Context, msg *mqclient.MQMessage) error {
-	data, err := c.pcfParser.ParseMessage(msg.Data, "accounting")
-	if err != nil {
-		return fmt.Errorf("failed to parse accounting message: %w", err)
+// processAccountingMessageForOTel processes a parsed accounting message for OpenTelemetry
+func (c *Collector) processAccountingMessageForOTel(ctx context.Context, result pcf.Result) error {
+	if result.Err != nil {
+		return fmt.Errorf("failed to parse accounting message: %w", result.Err)
 	}
 
-	acct, ok := data.(*pcf.AccountingData)
+	acct, ok := result.Data.(*pcf.AccountingData)
 	if !ok {
 		return fmt.Errorf("invalid accounting data type")
 	}
@@ -385,6 +1095,29 @@ func (c *Collector) GetStats() map[string]interface{} {
 	}
 }
 
+// ParseErrors returns the number of messages that could not be parsed
+func (c *Collector) ParseErrors() int64 {
+	return c.parseErrors + c.prometheusCollector.ParseErrors()
+}
+
+// ErrorCount returns the number of collection cycles that failed
+func (c *Collector) ErrorCount() int64 {
+	return c.errorCount
+}
+
+// Readiness returns the state reported by the /ready endpoint: starting until connected to
+// the queue manager, waiting_for_data until the first successful collection, then ready
+func (c *Collector) Readiness() string {
+	switch {
+	case c.collected.Load():
+		return otel.ReadinessReady
+	case c.connected.Load():
+		return otel.ReadinessWaitingForData
+	default:
+		return otel.ReadinessStarting
+	}
+}
+
 // IsRunning returns true if the collector is currently running
 func (c *Collector) IsRunning() bool {
 	return c.running
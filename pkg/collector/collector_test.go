@@ -2,11 +2,28 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqfake"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/simulator"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -17,24 +34,75 @@ func TestNewCollector(t *testing.T) {
 
 	cfg := config.DefaultConfig()
 
-	collector, err := NewCollector(cfg, logger)
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
 	require.NoError(t, err)
 	require.NotNil(t, collector)
 
 	assert.Equal(t, cfg, collector.config)
-	assert.Equal(t, logger, collector.logger)
+	assert.Equal(t, logging.WithCycle(logging.NewLogrus(logger), collector.cycle), collector.logger)
 	assert.False(t, collector.running)
 	assert.Equal(t, 0, collector.cycleCount)
 	assert.Equal(t, int64(0), collector.totalCollections)
 }
 
+func TestNewCollectorServesAPIAndWebSocketOnMetricsServer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	tests := []struct {
+		name    string
+		config  func(cfg *config.Config)
+		wantErr bool
+	}{
+		{
+			name:   "api with the metrics HTTP server",
+			config: func(cfg *config.Config) { cfg.API.Enabled = true },
+		},
+		{
+			name: "api without the metrics HTTP server",
+			config: func(cfg *config.Config) {
+				cfg.API.Enabled = true
+				cfg.Prometheus.EnableOTel = false
+			},
+			wantErr: true,
+		},
+		{
+			name:   "websocket with the metrics HTTP server",
+			config: func(cfg *config.Config) { cfg.WebSocket.Enabled = true },
+		},
+		{
+			name: "websocket without the metrics HTTP server",
+			config: func(cfg *config.Config) {
+				cfg.WebSocket.Enabled = true
+				cfg.Prometheus.EnableOTel = false
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			tt.config(cfg)
+
+			collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, collector.otelProvider)
+		})
+	}
+}
+
 func TestCollectorGetStats(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
 	cfg := config.DefaultConfig()
 
-	collector, err := NewCollector(cfg, logger)
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
 	require.NoError(t, err)
 
 	// Set some test values
@@ -64,7 +132,7 @@ func TestCollectorIsRunning(t *testing.T) {
 
 	cfg := config.DefaultConfig()
 
-	collector, err := NewCollector(cfg, logger)
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
 	require.NoError(t, err)
 
 	// Initially not running
@@ -110,7 +178,7 @@ func TestCollectorValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			collector, err := NewCollector(tt.config, logger)
+			collector, err := NewCollector(tt.config, logging.NewLogrus(logger))
 
 			if tt.wantErr {
 				// We expect the validation to happen when trying to start
@@ -136,7 +204,7 @@ func TestCollectorLifecycle(t *testing.T) {
 	cfg.Collector.MaxCycles = 2
 	cfg.Collector.Continuous = true
 
-	collector, err := NewCollector(cfg, logger)
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
 	require.NoError(t, err)
 
 	// Test that we can create and validate the collector
@@ -182,7 +250,7 @@ func TestCollectorConfiguration(t *testing.T) {
 		},
 	}
 
-	collector, err := NewCollector(cfg, logger)
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
 	require.NoError(t, err)
 	require.NotNil(t, collector)
 
@@ -209,7 +277,7 @@ func TestCollectorStatsTracking(t *testing.T) {
 	logger.SetLevel(logrus.ErrorLevel)
 
 	cfg := config.DefaultConfig()
-	collector, err := NewCollector(cfg, logger)
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
 	require.NoError(t, err)
 
 	// Test initial stats
@@ -233,3 +301,993 @@ func TestCollectorStatsTracking(t *testing.T) {
 	assert.Equal(t, int64(1), stats["error_count"])
 	assert.Equal(t, 3, stats["cycle_count"])
 }
+
+func TestCollectorBuildInfoMetric(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	collector.SetBuildInfo("2.1.0", "abc123", "2024-03-01T12:00:00Z")
+
+	families, err := collector.prometheusCollector.GetRegistry().Gather()
+	require.NoError(t, err)
+
+	var found bool
+	for _, family := range families {
+		if family.GetName() != "ibmmq_collector_build_info" {
+			continue
+		}
+		found = true
+		require.Len(t, family.GetMetric(), 1)
+
+		labels := map[string]string{}
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Equal(t, "2.1.0", labels["version"])
+		assert.Equal(t, "abc123", labels["commit"])
+		assert.Equal(t, "2024-03-01T12:00:00Z", labels["build_date"])
+		assert.NotEmpty(t, labels["go_version"])
+		assert.Equal(t, float64(1), family.GetMetric()[0].GetGauge().GetValue())
+	}
+	assert.True(t, found, "ibmmq_collector_build_info should be registered")
+}
+
+func TestCollectorWriteMetricsTextfile(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	collector.SetBuildInfo("2.1.0", "abc123", "unknown")
+	collector.prometheusCollector.EndCycle()
+
+	path := filepath.Join(t.TempDir(), "ibmmq.prom")
+	require.NoError(t, collector.WriteMetricsTextfile(path))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `ibmmq_collector_build_info{build_date="unknown",commit="abc123"`)
+}
+
+func TestCollectorReadiness(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	assert.Equal(t, "starting", collector.Readiness())
+
+	collector.connected.Store(true)
+	assert.Equal(t, "waiting_for_data", collector.Readiness())
+
+	collector.collected.Store(true)
+	assert.Equal(t, "ready", collector.Readiness())
+}
+
+// recordingSink keeps the batches written to it
+type recordingSink struct {
+	batches []*sinks.Batch
+	closed  bool
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Write(ctx context.Context, batch *sinks.Batch) error {
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestCollectorWriteSinks(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	assert.Empty(t, collector.sinks, "no sinks are enabled by default")
+
+	sink := &recordingSink{}
+	collector.sinks = []sinks.Sink{sink}
+
+	stats := []*mqclient.MQMessage{{
+		Type: "stats",
+		Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+			AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+			Bytes(),
+	}}
+	accounting := []*mqclient.MQMessage{
+		{Type: "accounting", Data: pcf.NewMessageBuilder(pcf.MQCFT_ACCOUNTING, pcf.MQCMD_ACCOUNTING_MQI).Bytes()},
+		{Type: "accounting", Data: []byte{0x01, 0x02}}, // unparseable, skipped
+	}
+
+	collector.writeSinks(context.Background(),
+		collector.pcfParser.ParseMessages(mqclient.Payloads(stats), "statistics"),
+		collector.pcfParser.ParseMessages(mqclient.Payloads(accounting), "accounting"))
+
+	require.Len(t, sink.batches, 1)
+	require.Len(t, sink.batches[0].Statistics, 1)
+	assert.Equal(t, "APP.ORDERS", sink.batches[0].Statistics[0].QueueStats.QueueName)
+	assert.Len(t, sink.batches[0].Accounting, 1)
+
+	// The sinks are closed when a run ends too, such as on a shutdown signal
+	require.NoError(t, collector.Stop(context.Background()))
+	assert.True(t, sink.closed)
+}
+
+func TestCollectorServesMetricsEndpoint(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.Port = port
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, 7).
+		Bytes()}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, collector.otelProvider.StartHTTPServer(ctx))
+
+	var body string
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, cfg.Prometheus.Path))
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		body = string(data)
+		return err == nil && resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Contains(t, body, `ibmmq_queue_depth_current{queue_manager="",queue_name="APP.ORDERS"} 7`)
+}
+
+func TestCollectorSharesParsedRecords(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.MQ.QueueManager = "FAKE"
+
+	client := mqfake.New(nil)
+	client.AddStatistics(pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, 7).
+		Bytes())
+	collector, err := NewCollectorWithClient(cfg, client, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	sink := &recordingSink{}
+	collector.sinks = []sinks.Sink{sink}
+	require.NoError(t, collector.collectMetrics(context.Background()))
+
+	// The records the metrics were set from reach the sink intact, not released
+	require.Len(t, sink.batches, 1)
+	require.Len(t, sink.batches[0].Statistics, 1)
+	require.NotNil(t, sink.batches[0].Statistics[0].QueueStats)
+	assert.Equal(t, "APP.ORDERS", sink.batches[0].Statistics[0].QueueStats.QueueName)
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	var depth float64
+	for _, family := range families {
+		if family.GetName() == "ibmmq_queue_depth_current" {
+			depth = family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, float64(7), depth)
+}
+
+func TestCollectorServesLastCycle(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.Forecast.Enabled = true
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	metrics := collector.prometheusCollector
+
+	hasMaxDepth := func(g interface {
+		Gather() ([]*dto.MetricFamily, error)
+	}) bool {
+		families, err := g.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "ibmmq_queue_depth_max" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Updates outside a cycle are staged until the next cycle ends
+	metrics.SetMaxDepth("QM1", "ORDERS.IN", 5000)
+	assert.True(t, hasMaxDepth(metrics.GetRegistry()))
+	assert.False(t, hasMaxDepth(metrics.Gatherer()))
+
+	metrics.ProcessMessages(nil, nil)
+	assert.True(t, hasMaxDepth(metrics.Gatherer()))
+}
+
+func TestCollectorCountsTruncatedMessages(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	message := pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, 5).
+		Bytes()
+	stats := []*mqclient.MQMessage{
+		{Type: "stats", Data: message},
+		{Type: "stats", Data: message[:len(message)-4]},
+	}
+	collector.prometheusCollector.ProcessMessages(stats, nil)
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	var truncated float64
+	for _, family := range families {
+		if family.GetName() == "ibmmq_truncated_messages_total" {
+			for _, metric := range family.GetMetric() {
+				truncated += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	assert.Equal(t, float64(1), truncated)
+}
+
+func TestCollectorWithFakeClient(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	client := mqfake.New(simulator.NewGenerator(simulator.DefaultOptions()))
+	collector, err := NewCollectorWithClient(cfg, client, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	require.NoError(t, collector.collectMetrics(context.Background()))
+	assert.NotZero(t, collector.totalStatsMessages)
+	assert.NotZero(t, collector.totalAccountingMessages)
+	assert.Equal(t, 1, client.Commits())
+	assert.Equal(t, "ready", collector.Readiness())
+
+	// A queue that cannot be drained makes a partial cycle, counted by queue
+	client.Fail("accounting", errors.New("MQRC_GET_INHIBITED"))
+	require.NoError(t, collector.collectMetrics(context.Background()))
+	assert.Equal(t, 2, client.Commits())
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] += metric.GetGauge().GetValue() + metric.GetCounter().GetValue()
+		}
+	}
+	assert.Contains(t, values, "ibmmq_queue_depth_current")
+	assert.Equal(t, float64(1), values["ibmmq_queue_collection_errors_total"])
+}
+
+func TestCollectorLiveDepths(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.MQ.QueueManager = "FAKE"
+	cfg.LiveDepth.Enabled = true
+	cfg.LiveDepth.Queues = []string{"APP.ORDERS", "APP.PAY*"}
+
+	client := mqfake.New(nil)
+	client.Queues = []*mqclient.QueueInfo{
+		{QueueName: "APP.ORDERS", CurrentDepth: 42, MaxDepth: 5000, OpenInputCount: 1},
+		{QueueName: "APP.PAYMENTS", CurrentDepth: 7, MaxDepth: 5000, OpenOutputCount: 2},
+		{QueueName: "APP.OTHER", CurrentDepth: 3},
+	}
+	collector, err := NewCollectorWithClient(cfg, client, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	require.NoError(t, collector.collectMetrics(context.Background()))
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	depths := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "ibmmq_queue_live_depth" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "queue_name" {
+					depths[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{"APP.ORDERS": 42, "APP.PAYMENTS": 7}, depths)
+}
+
+func TestCollectorResetStatistics(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.MQ.QueueManager = "FAKE"
+	cfg.Collector.ResetStats = true
+	cfg.Collector.ResetQueues = []string{"APP.*"}
+
+	client := mqfake.New(nil)
+	client.ResetStatistics = []*mqclient.QueueResetStatistics{
+		{QueueName: "APP.ORDERS", HighDepth: 12, EnqueueCount: 300, DequeueCount: 290, TimeSinceReset: 60},
+		{QueueName: "SYSTEM.DEFAULT.LOCAL.QUEUE", EnqueueCount: 1},
+	}
+	collector, err := NewCollectorWithClient(cfg, client, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	require.NoError(t, collector.collectMetrics(context.Background()))
+	assert.Equal(t, 1, client.Resets())
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		if !strings.HasPrefix(family.GetName(), "ibmmq_queue_reset_") {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "queue_name" {
+					values[family.GetName()+"/"+label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"ibmmq_queue_reset_high_depth/APP.ORDERS":       12,
+		"ibmmq_queue_reset_enqueue_count/APP.ORDERS":    300,
+		"ibmmq_queue_reset_dequeue_count/APP.ORDERS":    290,
+		"ibmmq_queue_reset_interval_seconds/APP.ORDERS": 60,
+	}, values)
+
+	// A queue that fails to reset does not lose the values of those that were reset
+	client.ResetStatistics = append(client.ResetStatistics,
+		&mqclient.QueueResetStatistics{QueueName: "APP.PAYMENTS", EnqueueCount: 40, DequeueCount: 38, TimeSinceReset: 60})
+	client.ResetFailures = []string{"APP.ORDERS"}
+	require.NoError(t, collector.collectMetrics(context.Background()))
+
+	families, err = collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	queues := map[string]bool{}
+	for _, family := range families {
+		if family.GetName() != "ibmmq_queue_reset_enqueue_count" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "queue_name" {
+					queues[label.GetValue()] = true
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{"APP.PAYMENTS": true}, queues)
+}
+
+func TestCollectorResourceMonitor(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.MQ.QueueManager = "FAKE"
+	cfg.ResourceMonitor.Enabled = true
+	cfg.ResourceMonitor.Classes = []string{"DISK"}
+
+	const metadata = "$SYS/MQ/INFO/QMGR/FAKE/Monitor/METADATA/"
+	client := mqfake.New(nil)
+	client.Publish(metadata+"CLASSES", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddGroup(pcf.MQGACF_MONITOR_CLASS, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_CLASS, 1).
+		AddString(pcf.MQCAMO_MONITOR_CLASS, "DISK").
+		AddString(pcf.MQCA_TOPIC_STRING, metadata+"DISK/TYPES").
+		Bytes())
+	client.Publish(metadata+"DISK/TYPES", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddGroup(pcf.MQGACF_MONITOR_TYPE, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_TYPE, 3).
+		AddString(pcf.MQCAMO_MONITOR_TYPE, "Log").
+		AddString(pcf.MQCA_TOPIC_STRING, metadata+"DISK/Log").
+		Bytes())
+	client.Publish(metadata+"DISK/Log", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddString(pcf.MQCA_TOPIC_STRING, "$SYS/MQ/INFO/QMGR/FAKE/Monitor/DISK/Log").
+		AddGroup(pcf.MQGACF_MONITOR_ELEMENT, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_ELEMENT, 9).
+		AddInteger(pcf.MQIAMO_MONITOR_DATATYPE, pcf.MQIAMO_MONITOR_PERCENT).
+		AddString(pcf.MQCAMO_MONITOR_DESC, "Log file system - in use percentage").
+		Bytes())
+	client.Publish("$SYS/MQ/INFO/QMGR/FAKE/Monitor/DISK/Log", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddInteger(pcf.MQIAMO_MONITOR_CLASS, 1).
+		AddInteger(pcf.MQIAMO_MONITOR_TYPE, 3).
+		AddInteger64(9, 4250).
+		Bytes())
+
+	collector, err := NewCollectorWithClient(cfg, client, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	require.NoError(t, collector.collectMetrics(context.Background()))
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	var value float64
+	for _, family := range families {
+		if family.GetName() != "ibmmq_resource_metric" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "element" && label.GetValue() == "log_file_system_in_use_percentage" {
+					value = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	assert.Equal(t, 42.5, value)
+}
+
+func TestCollectorPerformanceEvents(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.MQ.QueueManager = "FAKE"
+	cfg.PerformanceEvents.Enabled = true
+
+	event := func(reason int32, queue string) []byte {
+		return pcf.NewMessageBuilder(pcf.MQCFT_EVENT, pcf.MQCMD_PERFM_EVENT).
+			SetReason(1, reason).
+			AddString(pcf.MQCA_EVENT_Q_MGR_NAME, "FAKE").
+			AddString(pcf.MQCA_Q_NAME, queue).
+			Bytes()
+	}
+	client := mqfake.New(nil)
+	client.AddMessages("SYSTEM.ADMIN.PERFM.EVENT",
+		event(ibmmq.MQRC_Q_DEPTH_HIGH, "APP.ORDERS"),
+		event(ibmmq.MQRC_Q_DEPTH_LOW, "APP.ORDERS"),
+		event(ibmmq.MQRC_Q_DEPTH_HIGH, "APP.ORDERS"),
+		event(ibmmq.MQRC_Q_SERVICE_INTERVAL_HIGH, "APP.PAYMENTS"),
+		pcf.NewMessageBuilder(pcf.MQCFT_EVENT, pcf.MQCMD_Q_MGR_EVENT).SetReason(1, ibmmq.MQRC_NOT_AUTHORIZED).Bytes(),
+	)
+
+	collector, err := NewCollectorWithClient(cfg, client, logging.NewLogrus(logger))
+	require.NoError(t, err)
+	require.NoError(t, collector.collectMetrics(context.Background()))
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	counts := map[string]float64{}
+	var timestamps int
+	for _, family := range families {
+		switch family.GetName() {
+		case "ibmmq_performance_events_total":
+			for _, metric := range family.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				counts[labels["queue_name"]+"/"+labels["event"]] = metric.GetCounter().GetValue()
+			}
+		case "ibmmq_performance_event_last_timestamp_seconds":
+			timestamps = len(family.GetMetric())
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"APP.ORDERS/q_depth_high":              2,
+		"APP.ORDERS/q_depth_low":               1,
+		"APP.PAYMENTS/q_service_interval_high": 1,
+	}, counts)
+	assert.Equal(t, 3, timestamps)
+}
+
+func TestCollectorSamplesRepeatedParseErrors(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.Logging.Sampling.First = 2
+	cfg.Logging.Sampling.Every = 10
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	// Every message of a backlog fails to parse the same way
+	var stats []*mqclient.MQMessage
+	for i := 0; i < 25; i++ {
+		stats = append(stats, &mqclient.MQMessage{Type: "stats", Data: []byte("not a PCF message")})
+	}
+	collector.prometheusCollector.ProcessMessages(stats, nil)
+
+	var logged []interface{}
+	var summary *logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		switch entry.Message {
+		case "Failed to parse statistics message":
+			logged = append(logged, entry.Data["suppressed"])
+		case "Suppressed repeated log entries":
+			summary = entry
+		}
+	}
+	// The first two, then the 12th and 22nd with the count suppressed before them
+	assert.Equal(t, []interface{}{nil, nil, 9, 9}, logged)
+	require.NotNil(t, summary)
+	assert.Equal(t, 3, summary.Data["suppressed"])
+	assert.Equal(t, int64(25), collector.prometheusCollector.ParseErrors())
+}
+
+func TestCollectorErrorExemplarsCarryCycleID(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	message := pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, 5).
+		Bytes()
+	hook.Reset()
+	id := collector.cycle.Start()
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{{Type: "stats", Data: message[:len(message)-4]}}, nil)
+	collector.cycle.End()
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	var exemplar *dto.Exemplar
+	for _, family := range families {
+		if family.GetName() == "ibmmq_truncated_messages_total" {
+			exemplar = family.GetMetric()[0].GetCounter().GetExemplar()
+		}
+	}
+	require.NotNil(t, exemplar)
+	require.Len(t, exemplar.GetLabel(), 1)
+	assert.Equal(t, logging.CycleIDKey, exemplar.GetLabel()[0].GetName())
+	assert.Equal(t, id, exemplar.GetLabel()[0].GetValue())
+
+	// The entries logged while processing carry the same ID
+	require.NotEmpty(t, hook.AllEntries())
+	for _, entry := range hook.AllEntries() {
+		assert.Equal(t, id, entry.Data[logging.CycleIDKey], entry.Message)
+	}
+}
+
+func TestCollectorCountsMissedIntervals(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.Intervals.Enabled = true
+	cfg.Intervals.Statistics = 10 * time.Minute
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	statistics := func(at string) *mqclient.MQMessage {
+		return &mqclient.MQMessage{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+			AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+			AddString(pcf.MQCACF_COMMAND_TIME, at).
+			AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+			Bytes()}
+	}
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{
+		statistics("2026-03-14 10:00:00"),
+		statistics("2026-03-14 10:10:00"),
+		statistics("2026-03-14 10:40:00"), // 10:20 and 10:30 never arrived
+	}, nil)
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] = metric.GetCounter().GetValue() + metric.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, float64(2), values["ibmmq_missed_intervals_total"])
+	assert.Equal(t, float64(time.Date(2026, 3, 14, 10, 40, 0, 0, time.UTC).Unix()), values["ibmmq_last_interval_timestamp"])
+}
+
+func TestCollectorIntervalTimestamps(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	message := &mqclient.MQMessage{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+		AddString(pcf.MQCACF_COMMAND_TIME, "2026-03-14 10:10:00").
+		AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, 7).
+		Bytes()}
+
+	timestamp := func(enabled bool, name string) *int64 {
+		cfg := config.DefaultConfig()
+		cfg.Prometheus.EnableOTel = false
+		cfg.Prometheus.IntervalTimestamps = enabled
+		cfg.LiveDepth.Enabled = true
+		cfg.Collector.ResetStats = true
+
+		collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+		require.NoError(t, err)
+		collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{message}, nil)
+		collector.prometheusCollector.SetLiveDepths("QM1", []*mqclient.QueueInfo{{QueueName: "APP.ORDERS", CurrentDepth: 9}})
+		collector.prometheusCollector.SetResetStatistics("QM1", []*mqclient.QueueResetStatistics{{QueueName: "APP.ORDERS", EnqueueCount: 30}})
+		collector.prometheusCollector.EndCycle()
+
+		families, err := collector.prometheusCollector.Gatherer().Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == name {
+				require.Len(t, family.GetMetric(), 1)
+				return family.GetMetric()[0].TimestampMs
+			}
+		}
+		t.Fatalf("%s not exposed", name)
+		return nil
+	}
+
+	end := time.Date(2026, 3, 14, 10, 10, 0, 0, time.UTC).UnixMilli()
+	if stamped := timestamp(true, "ibmmq_queue_depth_current"); assert.NotNil(t, stamped) {
+		assert.Equal(t, end, *stamped)
+	}
+	assert.Nil(t, timestamp(false, "ibmmq_queue_depth_current"))
+
+	// Live depths and reset values are current, whatever the labels they share with the
+	// statistics
+	assert.Nil(t, timestamp(true, "ibmmq_queue_live_depth"))
+	assert.Nil(t, timestamp(true, "ibmmq_queue_reset_enqueue_count"))
+}
+
+func TestCollectorCountsAccountingRecordsOnce(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	record := func(at string, puts int32) *mqclient.MQMessage {
+		return &mqclient.MQMessage{Type: "accounting", Data: pcf.NewMessageBuilder(pcf.MQCFT_ACCOUNTING, pcf.MQCMD_ACCOUNTING_MQI).
+			AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+			AddString(pcf.MQCACF_COMMAND_TIME, at).
+			AddString(pcf.MQCA_APPL_NAME, "orders-service").
+			AddInteger(pcf.MQIAMO_PUTS, puts).
+			Bytes()}
+	}
+	first, second := record("2026-03-14 10:00:00", 5), record("2026-03-14 10:10:00", 3)
+
+	// The first record is delivered again in the next cycle
+	collector.prometheusCollector.ProcessMessages(nil, []*mqclient.MQMessage{first})
+	collector.prometheusCollector.ProcessMessages(nil, []*mqclient.MQMessage{first, second})
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] += metric.GetCounter().GetValue() + metric.GetGauge().GetValue()
+		}
+	}
+	assert.Equal(t, float64(8), values["ibmmq_accounting_puts_total"])
+	assert.Equal(t, float64(1), values["ibmmq_accounting_duplicate_records_total"])
+	assert.NotContains(t, values, "ibmmq_mqi_puts_total")
+}
+
+func TestCollectorQuarantinesUnparseableMessages(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.Quarantine.Enabled = true
+	cfg.Quarantine.Directory = dir
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{
+		{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).Bytes()},
+		{Type: "stats", Data: []byte{0x01, 0x02}},
+	}, nil)
+
+	payloads, err := filepath.Glob(filepath.Join(dir, "*.pcf"))
+	require.NoError(t, err)
+	assert.Len(t, payloads, 1)
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	quarantined := 0.0
+	for _, family := range families {
+		if family.GetName() == "ibmmq_quarantined_messages_total" {
+			quarantined = family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, float64(1), quarantined)
+}
+
+func TestCollectorRecordsQueueDrainResults(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.MQ.QueueManager = "QM1"
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	drainResults := func() (failures, success map[string]float64) {
+		failures, success = map[string]float64{}, map[string]float64{}
+		families, err := collector.prometheusCollector.Gatherer().Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				queueType := ""
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "type" {
+						queueType = label.GetValue()
+					}
+				}
+				switch family.GetName() {
+				case "ibmmq_queue_collection_errors_total":
+					failures[queueType] = metric.GetCounter().GetValue()
+				case "ibmmq_queue_collection_success":
+					success[queueType] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+		return failures, success
+	}
+
+	collector.prometheusCollector.SetDrainResult(&mqclient.QueueError{Type: "stats", Err: assert.AnError})
+	collector.prometheusCollector.EndCycle()
+	failures, success := drainResults()
+	assert.Equal(t, map[string]float64{"statistics": 1}, failures)
+	assert.Equal(t, map[string]float64{"statistics": 0, "accounting": 1}, success)
+
+	collector.prometheusCollector.SetDrainResult(nil)
+	collector.prometheusCollector.EndCycle()
+	failures, success = drainResults()
+	assert.Equal(t, map[string]float64{"statistics": 1}, failures)
+	assert.Equal(t, map[string]float64{"statistics": 1, "accounting": 1}, success)
+}
+
+func TestCollectorSkipsFailedResponses(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{{
+		Type: "stats",
+		Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+			SetReason(pcf.MQCC_FAILED, 2035).
+			AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+			AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+			Bytes(),
+	}}, nil)
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	names := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			names[family.GetName()] += metric.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, float64(1), names["ibmmq_pcf_reason_codes_total"])
+	assert.NotContains(t, names, "ibmmq_queue_depth_current", "a failed response is not a record")
+}
+
+func TestCollectorResetsIntervalsOnRestart(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.Intervals.Enabled = true
+	cfg.Intervals.Statistics = 10 * time.Minute
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	statistics := func(at string) *mqclient.MQMessage {
+		return &mqclient.MQMessage{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+			AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+			AddString(pcf.MQCACF_COMMAND_TIME, at).
+			AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+			Bytes()}
+	}
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{
+		statistics("2026-03-14 10:00:00"),
+		statistics("2026-03-14 10:10:00"),
+	}, nil)
+
+	// The intervals of the outage are not counted as missed after a restart
+	collector.prometheusCollector.QueueManagerRestarted("QM1")
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{statistics("2026-03-14 10:50:00")}, nil)
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()] += metric.GetCounter().GetValue()
+		}
+	}
+	assert.Equal(t, float64(1), values["ibmmq_queue_manager_restarts_total"])
+	assert.Zero(t, values["ibmmq_missed_intervals_total"])
+}
+
+func TestCollectorCountsEmptyCycles(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+	cfg.Collector.EmptyCycles = 2
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	collector.checkEmptyQueues(0, 5, nil)
+	assert.Equal(t, 1, collector.emptyStatistics)
+	assert.Zero(t, collector.emptyAccounting)
+
+	// A queue that failed to drain keeps its count; without a connection the settings
+	// cannot be inquired, which only logs
+	collector.checkEmptyQueues(0, 0, []string{"accounting"})
+	assert.Equal(t, 2, collector.emptyStatistics)
+	assert.Zero(t, collector.emptyAccounting)
+
+	collector.checkEmptyQueues(3, 0, nil)
+	assert.Zero(t, collector.emptyStatistics)
+	assert.Equal(t, 1, collector.emptyAccounting)
+
+	collector.prometheusCollector.SetMonitoringSettings("QM1", &mqclient.QueueManagerSettings{
+		StatisticsQueue:   ibmmq.MQMON_ON,
+		StatisticsMQI:     ibmmq.MQMON_OFF,
+		StatisticsChannel: ibmmq.MQMON_MEDIUM,
+		AccountingQueue:   ibmmq.MQMON_NONE,
+		AccountingMQI:     ibmmq.MQMON_OFF,
+	})
+	collector.prometheusCollector.EndCycle()
+
+	families, err := collector.prometheusCollector.Gatherer().Gather()
+	require.NoError(t, err)
+	enabled := map[string]float64{}
+	for _, family := range families {
+		if family.GetName() != "ibmmq_statistics_enabled" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "type" {
+					enabled[label.GetValue()] = metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"queue_statistics":   1,
+		"mqi_statistics":     0,
+		"channel_statistics": 1,
+		"queue_accounting":   0,
+		"mqi_accounting":     0,
+	}, enabled)
+}
+
+func TestCollectorCollapsesDynamicQueues(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cfg := config.DefaultConfig()
+	cfg.Prometheus.EnableOTel = false
+
+	collector, err := NewCollector(cfg, logging.NewLogrus(logger))
+	require.NoError(t, err)
+
+	statistics := func(at, queue string, depth int32) *mqclient.MQMessage {
+		return &mqclient.MQMessage{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+			AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+			AddString(pcf.MQCACF_COMMAND_TIME, at).
+			AddString(pcf.MQCA_Q_NAME, queue).
+			AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, depth).
+			Bytes()}
+	}
+	depths := func() map[string]float64 {
+		families, err := collector.prometheusCollector.Gatherer().Gather()
+		require.NoError(t, err)
+		values := map[string]float64{}
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "queue_name" {
+						values[family.GetName()+"/"+label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return values
+	}
+
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{
+		statistics("2026-03-14 10:00:00", "AMQ.5F3A1B2C00010203", 2),
+		statistics("2026-03-14 10:00:00", "AMQ.5F3A1B2C00010204", 3),
+		statistics("2026-03-14 10:00:00", "APP.ORDERS", 7),
+	}, nil)
+	values := depths()
+	assert.Equal(t, float64(5), values["ibmmq_queue_depth_current/AMQ.*"])
+	assert.Equal(t, float64(2), values["ibmmq_dynamic_queue_instances/AMQ.*"])
+	assert.Equal(t, float64(7), values["ibmmq_queue_depth_current/APP.ORDERS"])
+	assert.NotContains(t, values, "ibmmq_queue_depth_current/AMQ.5F3A1B2C00010203")
+
+	// A record delivered again, as after a backout, is summed once
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{
+		statistics("2026-03-14 10:00:00", "AMQ.5F3A1B2C00010203", 2),
+	}, nil)
+	values = depths()
+	assert.Equal(t, float64(5), values["ibmmq_queue_depth_current/AMQ.*"])
+	assert.Equal(t, float64(2), values["ibmmq_dynamic_queue_instances/AMQ.*"])
+
+	// The next interval starts the sums over
+	collector.prometheusCollector.ProcessMessages([]*mqclient.MQMessage{
+		statistics("2026-03-14 10:10:00", "AMQ.5F3A1B2C00010205", 1),
+	}, nil)
+	values = depths()
+	assert.Equal(t, float64(1), values["ibmmq_queue_depth_current/AMQ.*"])
+	assert.Equal(t, float64(1), values["ibmmq_dynamic_queue_instances/AMQ.*"])
+}
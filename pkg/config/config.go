@@ -1,10 +1,14 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
+	"strings"
 	"time"
 
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/redact"
 	"github.com/spf13/viper"
 )
 
@@ -20,6 +24,60 @@ type MQConfig struct {
 	Password       string `mapstructure:"password" yaml:"password" json:"password"`
 	KeyRepository  string `mapstructure:"key_repository" yaml:"key_repository" json:"key_repository"`
 	CipherSpec     string `mapstructure:"cipher_spec" yaml:"cipher_spec" json:"cipher_spec"`
+
+	// Name the collector's connections show under in DISPLAY CONN and in accounting,
+	// at most 28 characters; empty for the name of the executable
+	ApplicationName string `mapstructure:"application_name" yaml:"application_name" json:"application_name"`
+
+	// Client certificate presented to channels with SSLCAUTH(REQUIRED), by its label in
+	// the key repository; empty for the default ibmwebspheremq<user> label. The key
+	// repository password is needed for repositories without a stash file. The peer name
+	// restricts the queue manager certificates accepted, such as "CN=QM1,O=Example".
+	CertificateLabel      string `mapstructure:"certificate_label" yaml:"certificate_label" json:"certificate_label"`
+	KeyRepositoryPassword string `mapstructure:"key_repository_password" yaml:"key_repository_password" json:"key_repository_password"`
+	SSLPeerName           string `mapstructure:"ssl_peer_name" yaml:"ssl_peer_name" json:"ssl_peer_name"`
+	MaxGetRate            int    `mapstructure:"max_get_rate" yaml:"max_get_rate" json:"max_get_rate"` // messages per second, 0 for no limit
+	GetBurst              int    `mapstructure:"get_burst" yaml:"get_burst" json:"get_burst"`          // messages got unpaced at the start of a drain
+	ParallelDrain         bool   `mapstructure:"parallel_drain" yaml:"parallel_drain" json:"parallel_drain"`
+
+	// How long a get waits for a message to arrive, 0 to return at once, and the gets in a
+	// row that find no message before a drain ends
+	GetWaitInterval time.Duration `mapstructure:"get_wait_interval" yaml:"get_wait_interval" json:"get_wait_interval"`
+	MaxEmptyGets    int           `mapstructure:"max_empty_gets" yaml:"max_empty_gets" json:"max_empty_gets"`
+
+	// Largest message got in full, in bytes, 0 for no limit. It requires AcceptTruncated,
+	// with which the first MaxMessageLength bytes of larger messages are got, since a
+	// larger message that failed the get would stay at the head of its queue.
+	MaxMessageLength int  `mapstructure:"max_message_length" yaml:"max_message_length" json:"max_message_length"`
+	AcceptTruncated  bool `mapstructure:"accept_truncated" yaml:"accept_truncated" json:"accept_truncated"`
+
+	// Get messages under syncpoint and commit them once processed. A message backed out
+	// BackoutThreshold times is moved to BackoutQueue instead of being processed again;
+	// left unset, both are taken from the queue's BOTHRESH and BOQNAME.
+	Syncpoint        bool   `mapstructure:"syncpoint" yaml:"syncpoint" json:"syncpoint"`
+	BackoutThreshold int    `mapstructure:"backout_threshold" yaml:"backout_threshold" json:"backout_threshold"`
+	BackoutQueue     string `mapstructure:"backout_queue" yaml:"backout_queue" json:"backout_queue"`
+
+	// Browse the statistics and accounting queues instead of getting from them, leaving
+	// the messages for another consumer such as amqsmon
+	Browse bool `mapstructure:"browse" yaml:"browse" json:"browse"`
+
+	// Get only the statistics and accounting messages matching these, so several
+	// collectors can share the queues: a correlation or message identifier in hex, and a
+	// message selector such as "Root.MQMD.PutApplName LIKE 'payments%'"
+	MatchCorrelID string `mapstructure:"match_correl_id" yaml:"match_correl_id" json:"match_correl_id"`
+	MatchMsgID    string `mapstructure:"match_msg_id" yaml:"match_msg_id" json:"match_msg_id"`
+	Selector      string `mapstructure:"selector" yaml:"selector" json:"selector"`
+
+	// Queues of the PCF administration commands, such as reset-stats and Inquire Queue:
+	// the command queue, the model queue each reply queue is created from, and how long
+	// to wait for each response
+	CommandQueue    string        `mapstructure:"command_queue" yaml:"command_queue" json:"command_queue"`
+	ReplyModelQueue string        `mapstructure:"reply_model_queue" yaml:"reply_model_queue" json:"reply_model_queue"`
+	CommandTimeout  time.Duration `mapstructure:"command_timeout" yaml:"command_timeout" json:"command_timeout"`
+
+	// Require FIPS-approved algorithms on the channel; set from the top-level fips option
+	FIPSRequired bool `mapstructure:"-" yaml:"-" json:"-"`
 }
 
 // GetConnectionName returns the connection name, building it from host/port if connection_name is empty
@@ -33,6 +91,19 @@ func (m *MQConfig) GetConnectionName() string {
 	return "" // No fallback - must be provided via YAML or environment variables
 }
 
+// ConnectionNames returns the entries of the connection name, which may be a comma
+// separated list of host(port) entries for the instances of a multi-instance or HA
+// queue manager, in the order they are tried
+func (m *MQConfig) ConnectionNames() []string {
+	var names []string
+	for _, name := range strings.Split(m.GetConnectionName(), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // GetUser returns the user, preferring username over user field
 func (m *MQConfig) GetUser() string {
 	if m.Username != "" {
@@ -46,9 +117,19 @@ type CollectorConfig struct {
 	StatsQueue      string        `mapstructure:"stats_queue" yaml:"stats_queue" json:"stats_queue"`
 	AccountingQueue string        `mapstructure:"accounting_queue" yaml:"accounting_queue" json:"accounting_queue"`
 	ResetStats      bool          `mapstructure:"reset_stats" yaml:"reset_stats" json:"reset_stats"`
+	ResetQueues     []string      `mapstructure:"reset_queues" yaml:"reset_queues" json:"reset_queues"` // queue names or generic names reset after each cycle
 	Interval        time.Duration `mapstructure:"interval" yaml:"interval" json:"interval"`
 	MaxCycles       int           `mapstructure:"max_cycles" yaml:"max_cycles" json:"max_cycles"`
 	Continuous      bool          `mapstructure:"continuous" yaml:"continuous" json:"continuous"`
+	EventQueues     []string      `mapstructure:"event_queues" yaml:"event_queues" json:"event_queues"`
+	ParseWorkers    int           `mapstructure:"parse_workers" yaml:"parse_workers" json:"parse_workers"` // 0 for one per GOMAXPROCS
+
+	// Interval of the connection checks between cycles in continuous mode, 0 to disable
+	LivenessInterval time.Duration `mapstructure:"liveness_interval" yaml:"liveness_interval" json:"liveness_interval"`
+
+	// Cycles in a row without messages from a queue after which the queue manager is
+	// inquired whether it writes them at all, 0 to disable
+	EmptyCycles int `mapstructure:"empty_cycles" yaml:"empty_cycles" json:"empty_cycles"`
 }
 
 // PrometheusConfig holds Prometheus exporter configuration
@@ -58,6 +139,72 @@ type PrometheusConfig struct {
 	Namespace  string `mapstructure:"namespace" yaml:"namespace" json:"namespace"`
 	Subsystem  string `mapstructure:"subsystem" yaml:"subsystem" json:"subsystem"`
 	EnableOTel bool   `mapstructure:"enable_otel" yaml:"enable_otel" json:"enable_otel"`
+
+	// Expose queue, channel and application samples with the end of their statistics
+	// interval as timestamp instead of the scrape time
+	IntervalTimestamps bool `mapstructure:"interval_timestamps" yaml:"interval_timestamps" json:"interval_timestamps"`
+
+	// TLS of the metrics HTTP server, which also serves the health checks, REST API and
+	// live stream
+	TLS ServerTLSConfig `mapstructure:"tls" yaml:"tls" json:"tls"`
+
+	// Tokens required by the endpoints of the metrics HTTP server
+	Auth HTTPAuthConfig `mapstructure:"auth" yaml:"auth" json:"auth"`
+}
+
+// HTTPAuthConfig holds the tokens accepted by the metrics HTTP server and the endpoints
+// that require one. A token is sent as "Authorization: Bearer <token>" or as
+// "X-API-Key: <token>". Without tokens every endpoint is open.
+type HTTPAuthConfig struct {
+	Tokens    []string `mapstructure:"tokens" yaml:"tokens" json:"tokens"`
+	Metrics   bool     `mapstructure:"metrics" yaml:"metrics" json:"metrics"`       // the Prometheus metrics path
+	API       bool     `mapstructure:"api" yaml:"api" json:"api"`                   // the REST API under /api/v1/
+	WebSocket bool     `mapstructure:"websocket" yaml:"websocket" json:"websocket"` // the live event stream on /ws
+}
+
+// Enabled reports whether any endpoint requires a token
+func (a HTTPAuthConfig) Enabled() bool {
+	return len(a.Tokens) > 0 && (a.Metrics || a.API || a.WebSocket)
+}
+
+// validate checks that no token is empty
+func (a HTTPAuthConfig) validate() error {
+	for _, token := range a.Tokens {
+		if token == "" {
+			return fmt.Errorf("auth tokens must not be empty")
+		}
+	}
+	return nil
+}
+
+// ServerTLSConfig holds the TLS settings of an HTTP server. With a client CA bundle,
+// clients must present a certificate signed by one of its CAs (mutual TLS).
+type ServerTLSConfig struct {
+	CertFile     string `mapstructure:"cert_file" yaml:"cert_file" json:"cert_file"`
+	KeyFile      string `mapstructure:"key_file" yaml:"key_file" json:"key_file"`
+	ClientCAFile string `mapstructure:"client_ca_file" yaml:"client_ca_file" json:"client_ca_file"`
+
+	// Common names of the client certificates accepted, empty to accept any verified one
+	AllowedClientCNs []string `mapstructure:"allowed_client_cns" yaml:"allowed_client_cns" json:"allowed_client_cns"`
+}
+
+// Enabled reports whether the server is served over TLS
+func (t ServerTLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// validate checks that the TLS settings are complete
+func (t ServerTLSConfig) validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("tls cert_file and key_file must be set together")
+	}
+	if t.ClientCAFile != "" && !t.Enabled() {
+		return fmt.Errorf("tls client_ca_file requires cert_file and key_file")
+	}
+	if len(t.AllowedClientCNs) > 0 && t.ClientCAFile == "" {
+		return fmt.Errorf("tls allowed_client_cns requires client_ca_file")
+	}
+	return nil
 }
 
 // LoggingConfig holds logging configuration
@@ -66,14 +213,384 @@ type LoggingConfig struct {
 	Format     string `mapstructure:"format" yaml:"format" json:"format"`
 	OutputFile string `mapstructure:"output_file" yaml:"output_file" json:"output_file"`
 	Verbose    bool   `mapstructure:"verbose" yaml:"verbose" json:"verbose"`
+
+	Sampling LogSamplingConfig `mapstructure:"sampling" yaml:"sampling" json:"sampling"`
+}
+
+// LogSamplingConfig limits the entries logged for a problem repeated on every message,
+// such as a parse error throughout a backlog. Of each class of entry, the first are
+// logged, then every Every-th with the count suppressed since the previous one, and the
+// remainder is summarized at the end of the collection cycle.
+type LogSamplingConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	First   int  `mapstructure:"first" yaml:"first" json:"first"`
+	Every   int  `mapstructure:"every" yaml:"every" json:"every"` // 0 logs none past the first until the summary
+}
+
+// ElasticsearchConfig holds the Elasticsearch/OpenSearch sink configuration
+type ElasticsearchConfig struct {
+	Enabled         bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	URLs            []string      `mapstructure:"urls" yaml:"urls" json:"urls"`
+	Username        string        `mapstructure:"username" yaml:"username" json:"username"`
+	Password        string        `mapstructure:"password" yaml:"password" json:"password"`
+	IndexPrefix     string        `mapstructure:"index_prefix" yaml:"index_prefix" json:"index_prefix"`
+	IndexDateFormat string        `mapstructure:"index_date_format" yaml:"index_date_format" json:"index_date_format"`
+	InstallTemplate bool          `mapstructure:"install_template" yaml:"install_template" json:"install_template"`
+	BatchSize       int           `mapstructure:"batch_size" yaml:"batch_size" json:"batch_size"`
+	Timeout         time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// StatsDConfig holds the StatsD/DogStatsD sink configuration
+type StatsDConfig struct {
+	Enabled       bool     `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Address       string   `mapstructure:"address" yaml:"address" json:"address"`
+	Prefix        string   `mapstructure:"prefix" yaml:"prefix" json:"prefix"`
+	Flavor        string   `mapstructure:"flavor" yaml:"flavor" json:"flavor"` // dogstatsd or statsd
+	Tags          []string `mapstructure:"tags" yaml:"tags" json:"tags"`
+	MaxPacketSize int      `mapstructure:"max_packet_size" yaml:"max_packet_size" json:"max_packet_size"`
+}
+
+// GraphiteConfig holds the Graphite plaintext protocol sink configuration
+type GraphiteConfig struct {
+	Enabled      bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Address      string        `mapstructure:"address" yaml:"address" json:"address"`
+	PathTemplate string        `mapstructure:"path_template" yaml:"path_template" json:"path_template"`
+	BatchSize    int           `mapstructure:"batch_size" yaml:"batch_size" json:"batch_size"`
+	Timeout      time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// CloudWatchConfig holds the AWS CloudWatch metrics sink configuration
+type CloudWatchConfig struct {
+	Enabled         bool              `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Region          string            `mapstructure:"region" yaml:"region" json:"region"`
+	Namespace       string            `mapstructure:"namespace" yaml:"namespace" json:"namespace"`
+	Endpoint        string            `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"`
+	AccessKeyID     string            `mapstructure:"access_key_id" yaml:"access_key_id" json:"access_key_id"`
+	SecretAccessKey string            `mapstructure:"secret_access_key" yaml:"secret_access_key" json:"secret_access_key"`
+	SessionToken    string            `mapstructure:"session_token" yaml:"session_token" json:"session_token"`
+	Dimensions      map[string]string `mapstructure:"dimensions" yaml:"dimensions" json:"dimensions"`
+	BatchSize       int               `mapstructure:"batch_size" yaml:"batch_size" json:"batch_size"`
+	Timeout         time.Duration     `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// AzureMonitorConfig holds the Azure Monitor custom metrics sink configuration
+type AzureMonitorConfig struct {
+	Enabled      bool              `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Region       string            `mapstructure:"region" yaml:"region" json:"region"`
+	ResourceID   string            `mapstructure:"resource_id" yaml:"resource_id" json:"resource_id"`
+	Namespace    string            `mapstructure:"namespace" yaml:"namespace" json:"namespace"`
+	Auth         string            `mapstructure:"auth" yaml:"auth" json:"auth"` // managed_identity or service_principal
+	TenantID     string            `mapstructure:"tenant_id" yaml:"tenant_id" json:"tenant_id"`
+	ClientID     string            `mapstructure:"client_id" yaml:"client_id" json:"client_id"`
+	ClientSecret string            `mapstructure:"client_secret" yaml:"client_secret" json:"client_secret"`
+	Dimensions   map[string]string `mapstructure:"dimensions" yaml:"dimensions" json:"dimensions"`
+	Timeout      time.Duration     `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// GoogleCloudConfig holds the Google Cloud Monitoring sink configuration
+type GoogleCloudConfig struct {
+	Enabled         bool              `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	ProjectID       string            `mapstructure:"project_id" yaml:"project_id" json:"project_id"`
+	CredentialsFile string            `mapstructure:"credentials_file" yaml:"credentials_file" json:"credentials_file"`
+	MetricPrefix    string            `mapstructure:"metric_prefix" yaml:"metric_prefix" json:"metric_prefix"`
+	ResourceType    string            `mapstructure:"resource_type" yaml:"resource_type" json:"resource_type"` // generic_node, gce_instance, k8s_container or global
+	ResourceLabels  map[string]string `mapstructure:"resource_labels" yaml:"resource_labels" json:"resource_labels"`
+	BatchSize       int               `mapstructure:"batch_size" yaml:"batch_size" json:"batch_size"`
+	Timeout         time.Duration     `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// SplunkConfig holds the Splunk HTTP Event Collector sink configuration
+type SplunkConfig struct {
+	Enabled            bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	URL                string        `mapstructure:"url" yaml:"url" json:"url"`
+	Token              string        `mapstructure:"token" yaml:"token" json:"token"`
+	Index              string        `mapstructure:"index" yaml:"index" json:"index"`
+	Source             string        `mapstructure:"source" yaml:"source" json:"source"`
+	SourceType         string        `mapstructure:"sourcetype" yaml:"sourcetype" json:"sourcetype"` // suffixed with :statistics or :accounting
+	Host               string        `mapstructure:"host" yaml:"host" json:"host"`
+	BatchSize          int           `mapstructure:"batch_size" yaml:"batch_size" json:"batch_size"`
+	MaxRetries         int           `mapstructure:"max_retries" yaml:"max_retries" json:"max_retries"`
+	RetryBackoff       time.Duration `mapstructure:"retry_backoff" yaml:"retry_backoff" json:"retry_backoff"`
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify" yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	Timeout            time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// SyslogConfig holds the syslog sink configuration for accounting connection records
+type SyslogConfig struct {
+	Enabled            bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Network            string        `mapstructure:"network" yaml:"network" json:"network"` // udp, tcp or tls
+	Address            string        `mapstructure:"address" yaml:"address" json:"address"`
+	Format             string        `mapstructure:"format" yaml:"format" json:"format"` // cef or leef
+	Facility           string        `mapstructure:"facility" yaml:"facility" json:"facility"`
+	AppName            string        `mapstructure:"app_name" yaml:"app_name" json:"app_name"`
+	Hostname           string        `mapstructure:"hostname" yaml:"hostname" json:"hostname"`
+	CAFile             string        `mapstructure:"ca_file" yaml:"ca_file" json:"ca_file"`
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify" yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	Timeout            time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// FileConfig holds the rotating JSON lines file sink configuration
+type FileConfig struct {
+	Enabled        bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Path           string        `mapstructure:"path" yaml:"path" json:"path"`
+	MaxSizeMB      int           `mapstructure:"max_size_mb" yaml:"max_size_mb" json:"max_size_mb"`             // 0 disables size rotation
+	RotateInterval time.Duration `mapstructure:"rotate_interval" yaml:"rotate_interval" json:"rotate_interval"` // 0 disables time rotation
+	MaxFiles       int           `mapstructure:"max_files" yaml:"max_files" json:"max_files"`                   // rotated files kept; 0 keeps all
+	Compress       bool          `mapstructure:"compress" yaml:"compress" json:"compress"`
+}
+
+// NATSConfig holds the NATS publishing sink configuration
+type NATSConfig struct {
+	Enabled            bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	URL                string        `mapstructure:"url" yaml:"url" json:"url"` // nats:// or tls://
+	SubjectPrefix      string        `mapstructure:"subject_prefix" yaml:"subject_prefix" json:"subject_prefix"`
+	Username           string        `mapstructure:"username" yaml:"username" json:"username"`
+	Password           string        `mapstructure:"password" yaml:"password" json:"password"`
+	Token              string        `mapstructure:"token" yaml:"token" json:"token"`
+	JetStream          bool          `mapstructure:"jetstream" yaml:"jetstream" json:"jetstream"`
+	Stream             string        `mapstructure:"stream" yaml:"stream" json:"stream"` // created if set
+	CAFile             string        `mapstructure:"ca_file" yaml:"ca_file" json:"ca_file"`
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify" yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	Timeout            time.Duration `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+}
+
+// SinksConfig holds the configuration of the additional outputs fed with every collected record
+type SinksConfig struct {
+	Elasticsearch ElasticsearchConfig `mapstructure:"elasticsearch" yaml:"elasticsearch" json:"elasticsearch"`
+	StatsD        StatsDConfig        `mapstructure:"statsd" yaml:"statsd" json:"statsd"`
+	Graphite      GraphiteConfig      `mapstructure:"graphite" yaml:"graphite" json:"graphite"`
+	CloudWatch    CloudWatchConfig    `mapstructure:"cloudwatch" yaml:"cloudwatch" json:"cloudwatch"`
+	AzureMonitor  AzureMonitorConfig  `mapstructure:"azure_monitor" yaml:"azure_monitor" json:"azure_monitor"`
+	GoogleCloud   GoogleCloudConfig   `mapstructure:"google_cloud" yaml:"google_cloud" json:"google_cloud"`
+	Splunk        SplunkConfig        `mapstructure:"splunk" yaml:"splunk" json:"splunk"`
+	Syslog        SyslogConfig        `mapstructure:"syslog" yaml:"syslog" json:"syslog"`
+	File          FileConfig          `mapstructure:"file" yaml:"file" json:"file"`
+	NATS          NATSConfig          `mapstructure:"nats" yaml:"nats" json:"nats"`
+}
+
+// StoreConfig holds the local SQLite history store configuration
+type StoreConfig struct {
+	Enabled   bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Path      string        `mapstructure:"path" yaml:"path" json:"path"`
+	Retention time.Duration `mapstructure:"retention" yaml:"retention" json:"retention"` // 0 keeps everything
+}
+
+// APIConfig holds the REST API configuration; the API is served by the metrics HTTP server
+type APIConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Cycles  int  `mapstructure:"cycles" yaml:"cycles" json:"cycles"` // collection cycles kept in memory
+}
+
+// GRPCConfig holds the gRPC record streaming server configuration
+type GRPCConfig struct {
+	Enabled    bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Address    string `mapstructure:"address" yaml:"address" json:"address"`
+	BufferSize int    `mapstructure:"buffer_size" yaml:"buffer_size" json:"buffer_size"` // records queued per subscriber
+}
+
+// WebSocketConfig holds the live event stream configuration; /ws is served by the metrics HTTP server
+type WebSocketConfig struct {
+	Enabled    bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	BufferSize int  `mapstructure:"buffer_size" yaml:"buffer_size" json:"buffer_size"` // events queued per client
+}
+
+// AlertsConfig holds the threshold rules evaluated every collection cycle and the
+// webhooks they notify
+type AlertsConfig struct {
+	Enabled      bool            `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	SendResolved bool            `mapstructure:"send_resolved" yaml:"send_resolved" json:"send_resolved"`
+	Timeout      time.Duration   `mapstructure:"timeout" yaml:"timeout" json:"timeout"`
+	Webhooks     []WebhookConfig `mapstructure:"webhooks" yaml:"webhooks" json:"webhooks"`
+	Rules        []AlertRule     `mapstructure:"rules" yaml:"rules" json:"rules"`
+}
+
+// WebhookConfig is one notification target
+type WebhookConfig struct {
+	Name    string            `mapstructure:"name" yaml:"name" json:"name"`
+	URL     string            `mapstructure:"url" yaml:"url" json:"url"`
+	Format  string            `mapstructure:"format" yaml:"format" json:"format"` // slack, teams or generic
+	Headers map[string]string `mapstructure:"headers" yaml:"headers" json:"headers"`
+}
+
+// AlertRule is a threshold checked for every matching object in each cycle
+type AlertRule struct {
+	Name      string   `mapstructure:"name" yaml:"name" json:"name"`
+	Type      string   `mapstructure:"type" yaml:"type" json:"type"`          // queue_depth, no_readers, no_consumer or backouts
+	Objects   []string `mapstructure:"objects" yaml:"objects" json:"objects"` // name patterns; empty matches all
+	Threshold int64    `mapstructure:"threshold" yaml:"threshold" json:"threshold"`
+	Intervals int      `mapstructure:"intervals" yaml:"intervals" json:"intervals"` // consecutive cycles before firing
+	Webhooks  []string `mapstructure:"webhooks" yaml:"webhooks" json:"webhooks"`    // webhook names; empty notifies all
+}
+
+// AnomalyConfig holds the queue depth anomaly detector configuration
+type AnomalyConfig struct {
+	Enabled    bool    `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Method     string  `mapstructure:"method" yaml:"method" json:"method"`                // ewma or rolling
+	Window     int     `mapstructure:"window" yaml:"window" json:"window"`                // samples in the baseline
+	Threshold  float64 `mapstructure:"threshold" yaml:"threshold" json:"threshold"`       // standard deviations above the mean
+	MinSamples int     `mapstructure:"min_samples" yaml:"min_samples" json:"min_samples"` // samples before scoring
+}
+
+// ForecastConfig holds the queue backlog trend and time-to-full configuration
+type ForecastConfig struct {
+	Enabled         bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Window          int           `mapstructure:"window" yaml:"window" json:"window"` // statistics intervals the drift is averaged over
+	MaxDepthRefresh time.Duration `mapstructure:"max_depth_refresh" yaml:"max_depth_refresh" json:"max_depth_refresh"`
+}
+
+// AggregatesConfig holds the per-application accounting aggregates configuration
+type AggregatesConfig struct {
+	Enabled   bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	StateFile string `mapstructure:"state_file" yaml:"state_file" json:"state_file"` // totals kept across restarts if set
+}
+
+// IdleConfig holds the idle queue detection configuration
+type IdleConfig struct {
+	Enabled   bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Intervals int  `mapstructure:"intervals" yaml:"intervals" json:"intervals"` // consecutive idle intervals before a queue is flagged
+}
+
+// ImbalanceConfig holds the enqueue/dequeue imbalance detection configuration
+type ImbalanceConfig struct {
+	Enabled   bool    `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Window    int     `mapstructure:"window" yaml:"window" json:"window"`          // statistics intervals the ratio is computed over
+	Threshold float64 `mapstructure:"threshold" yaml:"threshold" json:"threshold"` // enqueued per dequeued message that counts as imbalanced
+	Sustain   int     `mapstructure:"sustain" yaml:"sustain" json:"sustain"`       // consecutive imbalanced intervals before a queue is flagged
+}
+
+// ThroughputConfig holds the channel throughput percentile configuration
+type ThroughputConfig struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Window  int  `mapstructure:"window" yaml:"window" json:"window"` // statistics intervals the percentiles are computed over
+}
+
+// PeaksConfig holds the daily peak usage configuration
+type PeaksConfig struct {
+	Enabled   bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	StateFile string `mapstructure:"state_file" yaml:"state_file" json:"state_file"` // peaks kept across restarts if set
+}
+
+// BaselineConfig holds the weekly seasonality baseline configuration
+type BaselineConfig struct {
+	Enabled    bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	MinSamples int    `mapstructure:"min_samples" yaml:"min_samples" json:"min_samples"` // values per hour of the week before scoring
+	MaxSamples int    `mapstructure:"max_samples" yaml:"max_samples" json:"max_samples"` // values per hour of the week before older ones decay
+	Timezone   string `mapstructure:"timezone" yaml:"timezone" json:"timezone"`          // hours of the week are taken in this zone; empty for local time
+	StateFile  string `mapstructure:"state_file" yaml:"state_file" json:"state_file"`    // baselines kept across restarts if set
+}
+
+// CorrelationConfig holds the statistics and accounting correlation configuration
+type CorrelationConfig struct {
+	Metrics bool `mapstructure:"metrics" yaml:"metrics" json:"metrics"` // export per-queue application traffic
+}
+
+// SpoolConfig holds the memory cap on the raw messages of a collection cycle
+type SpoolConfig struct {
+	Enabled       bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Directory     string `mapstructure:"directory" yaml:"directory" json:"directory"` // system temporary directory if empty
+	MemoryLimitMB int    `mapstructure:"memory_limit_mb" yaml:"memory_limit_mb" json:"memory_limit_mb"`
+}
+
+// ResourcesConfig holds the runtime limits applied at startup, for running in
+// constrained containers
+type ResourcesConfig struct {
+	MemoryLimitMB int `mapstructure:"memory_limit_mb" yaml:"memory_limit_mb" json:"memory_limit_mb"` // Go soft memory limit, 0 for GOMEMLIMIT or none
+	MaxProcs      int `mapstructure:"max_procs" yaml:"max_procs" json:"max_procs"`                   // GOMAXPROCS, 0 for the runtime default
+	MaxInFlight   int `mapstructure:"max_in_flight" yaml:"max_in_flight" json:"max_in_flight"`       // messages got per queue and cycle, 0 for no limit
+}
+
+// IntervalsConfig holds the statistics and accounting interval gap detection configuration
+type IntervalsConfig struct {
+	Enabled    bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Statistics time.Duration `mapstructure:"statistics" yaml:"statistics" json:"statistics"` // expected STATINT, 0 to inquire or learn it
+	Accounting time.Duration `mapstructure:"accounting" yaml:"accounting" json:"accounting"` // expected ACCTINT, 0 to inquire or learn it
+}
+
+// QuarantineConfig holds where messages that cannot be parsed are kept
+type QuarantineConfig struct {
+	Enabled     bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Directory   string `mapstructure:"directory" yaml:"directory" json:"directory"`          // files of payload and MQMD, none if empty
+	Queue       string `mapstructure:"queue" yaml:"queue" json:"queue"`                      // queue the messages are put to, none if empty
+	MaxMessages int    `mapstructure:"max_messages" yaml:"max_messages" json:"max_messages"` // messages kept in the directory, 0 for no limit
+}
+
+// LiveDepthConfig holds the queues whose depth is inquired in every cycle, between
+// statistics intervals
+type LiveDepthConfig struct {
+	Enabled bool     `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Queues  []string `mapstructure:"queues" yaml:"queues" json:"queues"` // queue names, or generic names ending in *
+}
+
+// ResourceMonitorConfig holds the resource monitoring classes subscribed to, published
+// by queue managers from IBM MQ 9.0 on the $SYS/MQ/INFO/QMGR/<qmgr>/Monitor topics
+type ResourceMonitorConfig struct {
+	Enabled bool     `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Classes []string `mapstructure:"classes" yaml:"classes" json:"classes"` // such as CPU, DISK, STATMQI and STATQ
+	Queues  []string `mapstructure:"queues" yaml:"queues" json:"queues"`    // queue names, or generic names ending in *, of the STATQ class
+}
+
+// PerformanceEventsConfig holds the queue the performance events are consumed from, to
+// count the queue depth, queue full and service interval events per queue
+type PerformanceEventsConfig struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Queue   string `mapstructure:"queue" yaml:"queue" json:"queue"`
+}
+
+// DynamicQueuesConfig holds the name patterns of temporary and dynamic queues whose
+// statistics are collapsed into one series per pattern
+type DynamicQueuesConfig struct {
+	Enabled  bool     `mapstructure:"enabled" yaml:"enabled" json:"enabled"`
+	Patterns []string `mapstructure:"patterns" yaml:"patterns" json:"patterns"` // queue name patterns as in path.Match
+}
+
+// Collapse returns the first pattern matching a queue name, or the name itself when none
+// matches or collapsing is disabled
+func (d *DynamicQueuesConfig) Collapse(queue string) string {
+	if !d.Enabled {
+		return queue
+	}
+	for _, pattern := range d.Patterns {
+		if ok, _ := path.Match(pattern, queue); ok {
+			return pattern
+		}
+	}
+	return queue
 }
 
 // Config holds the complete application configuration
 type Config struct {
-	MQ         MQConfig         `mapstructure:"mq" yaml:"mq" json:"mq"`
-	Collector  CollectorConfig  `mapstructure:"collector" yaml:"collector" json:"collector"`
-	Prometheus PrometheusConfig `mapstructure:"prometheus" yaml:"prometheus" json:"prometheus"`
-	Logging    LoggingConfig    `mapstructure:"logging" yaml:"logging" json:"logging"`
+	MQ          MQConfig          `mapstructure:"mq" yaml:"mq" json:"mq"`
+	Collector   CollectorConfig   `mapstructure:"collector" yaml:"collector" json:"collector"`
+	Prometheus  PrometheusConfig  `mapstructure:"prometheus" yaml:"prometheus" json:"prometheus"`
+	Logging     LoggingConfig     `mapstructure:"logging" yaml:"logging" json:"logging"`
+	Sinks       SinksConfig       `mapstructure:"sinks" yaml:"sinks" json:"sinks"`
+	Store       StoreConfig       `mapstructure:"store" yaml:"store" json:"store"`
+	API         APIConfig         `mapstructure:"api" yaml:"api" json:"api"`
+	GRPC        GRPCConfig        `mapstructure:"grpc" yaml:"grpc" json:"grpc"`
+	WebSocket   WebSocketConfig   `mapstructure:"websocket" yaml:"websocket" json:"websocket"`
+	Alerts      AlertsConfig      `mapstructure:"alerts" yaml:"alerts" json:"alerts"`
+	Anomaly     AnomalyConfig     `mapstructure:"anomaly" yaml:"anomaly" json:"anomaly"`
+	Forecast    ForecastConfig    `mapstructure:"forecast" yaml:"forecast" json:"forecast"`
+	Aggregates  AggregatesConfig  `mapstructure:"aggregates" yaml:"aggregates" json:"aggregates"`
+	Idle        IdleConfig        `mapstructure:"idle" yaml:"idle" json:"idle"`
+	Imbalance   ImbalanceConfig   `mapstructure:"imbalance" yaml:"imbalance" json:"imbalance"`
+	Throughput  ThroughputConfig  `mapstructure:"throughput" yaml:"throughput" json:"throughput"`
+	Peaks       PeaksConfig       `mapstructure:"peaks" yaml:"peaks" json:"peaks"`
+	Baseline    BaselineConfig    `mapstructure:"baseline" yaml:"baseline" json:"baseline"`
+	Correlation CorrelationConfig `mapstructure:"correlation" yaml:"correlation" json:"correlation"`
+	Spool       SpoolConfig       `mapstructure:"spool" yaml:"spool" json:"spool"`
+	Resources   ResourcesConfig   `mapstructure:"resources" yaml:"resources" json:"resources"`
+	Intervals   IntervalsConfig   `mapstructure:"intervals" yaml:"intervals" json:"intervals"`
+	Quarantine  QuarantineConfig  `mapstructure:"quarantine" yaml:"quarantine" json:"quarantine"`
+	LiveDepth   LiveDepthConfig   `mapstructure:"live_depth" yaml:"live_depth" json:"live_depth"`
+
+	DynamicQueues   DynamicQueuesConfig   `mapstructure:"dynamic_queues" yaml:"dynamic_queues" json:"dynamic_queues"`
+	ResourceMonitor ResourceMonitorConfig `mapstructure:"resource_monitor" yaml:"resource_monitor" json:"resource_monitor"`
+
+	PerformanceEvents PerformanceEventsConfig `mapstructure:"performance_events" yaml:"performance_events" json:"performance_events"`
+
+	// Restrict the MQ channel and the HTTP servers to FIPS-approved algorithms
+	FIPS bool `mapstructure:"fips" yaml:"fips" json:"fips"`
 }
 
 // DefaultConfig returns a configuration with minimal defaults
@@ -92,14 +609,45 @@ func DefaultConfig() *Config {
 			Password:       "",
 			KeyRepository:  "",
 			CipherSpec:     "",
+
+			ApplicationName: "ibmmq-stat-otel",
+
+			MaxGetRate:    0,
+			GetBurst:      1000,
+			ParallelDrain: false,
+
+			GetWaitInterval: 0,
+			MaxEmptyGets:    1,
+
+			MaxMessageLength: 0,
+			AcceptTruncated:  false,
+			Syncpoint:        false,
+			Browse:           false,
+
+			MatchCorrelID: "",
+			MatchMsgID:    "",
+			Selector:      "",
+
+			CommandQueue:    "SYSTEM.ADMIN.COMMAND.QUEUE",
+			ReplyModelQueue: "SYSTEM.DEFAULT.MODEL.QUEUE",
+			CommandTimeout:  5 * time.Second,
 		},
 		Collector: CollectorConfig{
 			StatsQueue:      "", // Will be loaded from YAML
 			AccountingQueue: "", // Will be loaded from YAML
 			ResetStats:      false,
+			ResetQueues:     []string{"*"},
 			Interval:        60 * time.Second, // Sensible default
 			MaxCycles:       0,                // 0 means infinite
 			Continuous:      false,
+			EventQueues: []string{
+				"SYSTEM.ADMIN.QMGR.EVENT",
+				"SYSTEM.ADMIN.PERFM.EVENT",
+				"SYSTEM.ADMIN.CHANNEL.EVENT",
+			},
+			ParseWorkers:     0,
+			LivenessInterval: 30 * time.Second,
+			EmptyCycles:      3,
 		},
 		Prometheus: PrometheusConfig{
 			Port:       9090,
@@ -107,12 +655,216 @@ func DefaultConfig() *Config {
 			Namespace:  "ibmmq",
 			Subsystem:  "",
 			EnableOTel: true,
+
+			IntervalTimestamps: false,
+			Auth: HTTPAuthConfig{
+				API:       true,
+				WebSocket: true,
+			},
 		},
 		Logging: LoggingConfig{
 			Level:      "info",
 			Format:     "json",
 			OutputFile: "",
 			Verbose:    false,
+			Sampling: LogSamplingConfig{
+				Enabled: true,
+				First:   10,
+				Every:   100,
+			},
+		},
+		Sinks: SinksConfig{
+			Elasticsearch: ElasticsearchConfig{
+				Enabled:         false,
+				IndexPrefix:     "ibmmq",
+				IndexDateFormat: "2006.01.02", // Daily indices
+				InstallTemplate: true,
+				BatchSize:       500,
+				Timeout:         30 * time.Second,
+			},
+			StatsD: StatsDConfig{
+				Enabled:       false,
+				Address:       "localhost:8125",
+				Prefix:        "ibmmq",
+				Flavor:        "dogstatsd",
+				MaxPacketSize: 1432, // Fits a single Ethernet frame
+			},
+			Graphite: GraphiteConfig{
+				Enabled:      false,
+				Address:      "localhost:2003",
+				PathTemplate: "ibmmq.{qmgr}.{group}.{object}.{metric}",
+				BatchSize:    500,
+				Timeout:      10 * time.Second,
+			},
+			CloudWatch: CloudWatchConfig{
+				Enabled:   false,
+				Namespace: "IBM/MQ",
+				// Labels without a dimension name are not sent
+				Dimensions: map[string]string{
+					"qmgr":        "QueueManager",
+					"queue":       "Queue",
+					"channel":     "Channel",
+					"application": "Application",
+				},
+				BatchSize: 1000, // PutMetricData limit
+				Timeout:   30 * time.Second,
+			},
+			AzureMonitor: AzureMonitorConfig{
+				Enabled:   false,
+				Namespace: "IBMMQ",
+				Auth:      "managed_identity",
+				Dimensions: map[string]string{
+					"qmgr":        "QueueManager",
+					"queue":       "Queue",
+					"channel":     "Channel",
+					"application": "Application",
+				},
+				Timeout: 30 * time.Second,
+			},
+			GoogleCloud: GoogleCloudConfig{
+				Enabled:      false,
+				MetricPrefix: "custom.googleapis.com/ibmmq",
+				ResourceType: "generic_node",
+				BatchSize:    200, // CreateTimeSeries limit
+				Timeout:      30 * time.Second,
+			},
+			Splunk: SplunkConfig{
+				Enabled:      false,
+				Source:       "ibmmq-collector",
+				SourceType:   "ibmmq",
+				BatchSize:    100,
+				MaxRetries:   3,
+				RetryBackoff: time.Second,
+				Timeout:      30 * time.Second,
+			},
+			Syslog: SyslogConfig{
+				Enabled:  false,
+				Network:  "udp",
+				Address:  "localhost:514",
+				Format:   "cef",
+				Facility: "local0",
+				AppName:  "ibmmq-collector",
+				Timeout:  10 * time.Second,
+			},
+			File: FileConfig{
+				Enabled:        false,
+				Path:           "ibmmq-records.jsonl",
+				MaxSizeMB:      100,
+				RotateInterval: 24 * time.Hour,
+				MaxFiles:       7,
+				Compress:       true,
+			},
+			NATS: NATSConfig{
+				Enabled:       false,
+				URL:           "nats://localhost:4222",
+				SubjectPrefix: "ibmmq",
+				Timeout:       10 * time.Second,
+			},
+		},
+		Store: StoreConfig{
+			Enabled:   false,
+			Path:      "ibmmq-history.db",
+			Retention: 7 * 24 * time.Hour,
+		},
+		API: APIConfig{
+			Enabled: false,
+			Cycles:  10,
+		},
+		GRPC: GRPCConfig{
+			Enabled:    false,
+			Address:    ":9095",
+			BufferSize: 1024,
+		},
+		WebSocket: WebSocketConfig{
+			Enabled:    false,
+			BufferSize: 256,
+		},
+		Alerts: AlertsConfig{
+			Enabled:      false,
+			SendResolved: true,
+			Timeout:      10 * time.Second,
+		},
+		Anomaly: AnomalyConfig{
+			Enabled:    false,
+			Method:     "ewma",
+			Window:     30,
+			Threshold:  3,
+			MinSamples: 10,
+		},
+		Forecast: ForecastConfig{
+			Enabled:         false,
+			Window:          5,
+			MaxDepthRefresh: 10 * time.Minute,
+		},
+		Aggregates: AggregatesConfig{
+			Enabled:   false,
+			StateFile: "",
+		},
+		Idle: IdleConfig{
+			Enabled:   false,
+			Intervals: 24,
+		},
+		Imbalance: ImbalanceConfig{
+			Enabled:   false,
+			Window:    5,
+			Threshold: 1.5,
+			Sustain:   3,
+		},
+		Throughput: ThroughputConfig{
+			Enabled: false,
+			Window:  288,
+		},
+		Peaks: PeaksConfig{
+			Enabled:   false,
+			StateFile: "",
+		},
+		Baseline: BaselineConfig{
+			Enabled:    false,
+			MinSamples: 3,
+			MaxSamples: 50,
+			Timezone:   "",
+			StateFile:  "",
+		},
+		Correlation: CorrelationConfig{
+			Metrics: false,
+		},
+		Spool: SpoolConfig{
+			Enabled:       false,
+			Directory:     "",
+			MemoryLimitMB: 256,
+		},
+		Resources: ResourcesConfig{
+			MemoryLimitMB: 0,
+			MaxProcs:      0,
+			MaxInFlight:   0,
+		},
+		Intervals: IntervalsConfig{
+			Enabled:    false,
+			Statistics: 0,
+			Accounting: 0,
+		},
+		Quarantine: QuarantineConfig{
+			Enabled:     false,
+			Directory:   "",
+			Queue:       "",
+			MaxMessages: 1000,
+		},
+		LiveDepth: LiveDepthConfig{
+			Enabled: false,
+			Queues:  nil,
+		},
+		DynamicQueues: DynamicQueuesConfig{
+			Enabled:  true,
+			Patterns: []string{"AMQ.*", "IBMMQSTAT.REPLY.*"},
+		},
+		ResourceMonitor: ResourceMonitorConfig{
+			Enabled: false,
+			Classes: []string{"CPU", "DISK"},
+			Queues:  nil,
+		},
+		PerformanceEvents: PerformanceEventsConfig{
+			Enabled: false,
+			Queue:   "SYSTEM.ADMIN.PERFM.EVENT",
 		},
 	}
 }
@@ -147,6 +899,20 @@ func LoadConfig(configPath string) (*Config, error) {
 	viper.BindEnv("mq.password", "IBMMQ_PASSWORD")
 	viper.BindEnv("mq.key_repository", "IBMMQ_KEY_REPOSITORY")
 	viper.BindEnv("mq.cipher_spec", "IBMMQ_CIPHER_SPEC")
+	viper.BindEnv("mq.certificate_label", "IBMMQ_CERTIFICATE_LABEL")
+	viper.BindEnv("mq.application_name", "IBMMQ_APPLICATION_NAME")
+	viper.BindEnv("mq.key_repository_password", "IBMMQ_KEY_REPOSITORY_PASSWORD")
+	viper.BindEnv("sinks.elasticsearch.password", "IBMMQ_ELASTICSEARCH_PASSWORD")
+	viper.BindEnv("sinks.cloudwatch.region", "AWS_REGION")
+	viper.BindEnv("sinks.azure_monitor.tenant_id", "AZURE_TENANT_ID")
+	viper.BindEnv("sinks.azure_monitor.client_id", "AZURE_CLIENT_ID")
+	viper.BindEnv("sinks.azure_monitor.client_secret", "AZURE_CLIENT_SECRET")
+	viper.BindEnv("sinks.google_cloud.project_id", "GOOGLE_CLOUD_PROJECT")
+	viper.BindEnv("sinks.google_cloud.credentials_file", "GOOGLE_APPLICATION_CREDENTIALS")
+	viper.BindEnv("sinks.splunk.token", "IBMMQ_SPLUNK_TOKEN")
+	viper.BindEnv("sinks.nats.password", "IBMMQ_NATS_PASSWORD")
+	viper.BindEnv("sinks.nats.token", "IBMMQ_NATS_TOKEN")
+	viper.BindEnv("prometheus.auth.tokens", "IBMMQ_API_TOKENS") // comma separated
 
 	// Read configuration file
 	if err := viper.ReadInConfig(); err != nil {
@@ -166,6 +932,8 @@ func LoadConfig(configPath string) (*Config, error) {
 		config.MQ.ConnectionName = fmt.Sprintf("%s(%d)", config.MQ.Host, config.MQ.Port)
 	}
 
+	config.MQ.FIPSRequired = config.FIPS
+
 	// Override with environment variables for sensitive data
 	if user := os.Getenv("IBMMQ_USER"); user != "" {
 		config.MQ.User = user
@@ -174,6 +942,9 @@ func LoadConfig(configPath string) (*Config, error) {
 		config.MQ.Password = password
 	}
 
+	// From here on the credentials are masked in logs and printed errors
+	redact.Register(config.Secrets()...)
+
 	return config, nil
 }
 
@@ -190,15 +961,437 @@ func (c *Config) Validate() error {
 	if c.MQ.GetConnectionName() == "" {
 		return fmt.Errorf("connection name is required (provide either connection_name or host/port)")
 	}
+	for _, name := range strings.Split(c.MQ.GetConnectionName(), ",") {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("connection name list %q has an empty entry", c.MQ.GetConnectionName())
+		}
+	}
+
+	if len(c.MQ.ApplicationName) > 28 {
+		return fmt.Errorf("mq application name %q is longer than 28 characters", c.MQ.ApplicationName)
+	}
+
+	if c.MQ.MaxGetRate < 0 || c.MQ.GetBurst < 0 {
+		return fmt.Errorf("max get rate and get burst must not be negative")
+	}
+
+	if c.MQ.GetWaitInterval < 0 || c.MQ.MaxEmptyGets < 0 {
+		return fmt.Errorf("get wait interval and max empty gets must not be negative")
+	}
+
+	if c.MQ.MaxMessageLength < 0 {
+		return fmt.Errorf("max message length must not be negative")
+	}
+
+	if c.MQ.AcceptTruncated && c.MQ.MaxMessageLength == 0 {
+		return fmt.Errorf("accept truncated requires a max message length")
+	}
+
+	if c.MQ.MaxMessageLength > 0 && !c.MQ.AcceptTruncated {
+		return fmt.Errorf("max message length requires accept truncated, as a larger message would block its queue")
+	}
+
+	if c.MQ.BackoutThreshold < 0 {
+		return fmt.Errorf("backout threshold must not be negative")
+	}
+
+	if !c.MQ.Syncpoint && (c.MQ.BackoutThreshold > 0 || c.MQ.BackoutQueue != "") {
+		return fmt.Errorf("backout threshold and backout queue require syncpoint")
+	}
+
+	if c.MQ.Browse && c.MQ.Syncpoint {
+		return fmt.Errorf("browse cannot be combined with syncpoint")
+	}
+
+	for name, id := range map[string]string{"correl id": c.MQ.MatchCorrelID, "msg id": c.MQ.MatchMsgID} {
+		if _, err := hex.DecodeString(id); err != nil || len(id) > 48 {
+			return fmt.Errorf("match %s %q must be at most 24 bytes in hex", name, id)
+		}
+	}
+
+	if c.MQ.CommandTimeout < 0 {
+		return fmt.Errorf("mq command timeout must not be negative")
+	}
+
+	if c.MQ.CipherSpec == "" && (c.MQ.KeyRepository != "" || c.MQ.CertificateLabel != "" ||
+		c.MQ.KeyRepositoryPassword != "" || c.MQ.SSLPeerName != "") {
+		return fmt.Errorf("mq key repository, certificate label and ssl peer name require a cipher spec")
+	}
+
+	if err := c.validateFIPS(); err != nil {
+		return err
+	}
 
 	if c.Collector.Interval < time.Second {
 		return fmt.Errorf("collection interval must be at least 1 second")
 	}
 
+	if c.Collector.ParseWorkers < 0 {
+		return fmt.Errorf("parse workers must not be negative")
+	}
+
+	if c.Collector.LivenessInterval < 0 {
+		return fmt.Errorf("liveness interval must not be negative")
+	}
+
+	if c.Collector.EmptyCycles < 0 {
+		return fmt.Errorf("empty cycles must not be negative")
+	}
+
+	if c.Logging.Sampling.First < 0 || c.Logging.Sampling.Every < 0 {
+		return fmt.Errorf("log sampling first and every must not be negative")
+	}
+
 	if c.Prometheus.Port < 1 || c.Prometheus.Port > 65535 {
 		return fmt.Errorf("prometheus port must be between 1 and 65535")
 	}
+	if err := c.Prometheus.TLS.validate(); err != nil {
+		return fmt.Errorf("prometheus %w", err)
+	}
+	if err := c.Prometheus.Auth.validate(); err != nil {
+		return fmt.Errorf("prometheus %w", err)
+	}
+
+	if es := c.Sinks.Elasticsearch; es.Enabled {
+		if len(es.URLs) == 0 {
+			return fmt.Errorf("elasticsearch sink requires at least one URL")
+		}
+		if es.IndexPrefix == "" {
+			return fmt.Errorf("elasticsearch sink requires an index prefix")
+		}
+		if es.BatchSize < 1 {
+			return fmt.Errorf("elasticsearch batch size must be at least 1")
+		}
+	}
+
+	if sd := c.Sinks.StatsD; sd.Enabled {
+		if sd.Address == "" {
+			return fmt.Errorf("statsd sink requires an address")
+		}
+		if sd.Flavor != "dogstatsd" && sd.Flavor != "statsd" {
+			return fmt.Errorf("invalid statsd flavor: %s (use dogstatsd or statsd)", sd.Flavor)
+		}
+		if sd.MaxPacketSize < 64 {
+			return fmt.Errorf("statsd max packet size must be at least 64 bytes")
+		}
+	}
+
+	if g := c.Sinks.Graphite; g.Enabled {
+		if g.Address == "" {
+			return fmt.Errorf("graphite sink requires an address")
+		}
+		if g.PathTemplate == "" {
+			return fmt.Errorf("graphite sink requires a path template")
+		}
+		if g.BatchSize < 1 {
+			return fmt.Errorf("graphite batch size must be at least 1")
+		}
+	}
+
+	if cw := c.Sinks.CloudWatch; cw.Enabled {
+		if cw.Region == "" && cw.Endpoint == "" {
+			return fmt.Errorf("cloudwatch sink requires a region")
+		}
+		if cw.Namespace == "" {
+			return fmt.Errorf("cloudwatch sink requires a namespace")
+		}
+		if cw.BatchSize < 1 || cw.BatchSize > 1000 {
+			return fmt.Errorf("cloudwatch batch size must be between 1 and 1000")
+		}
+	}
+
+	if az := c.Sinks.AzureMonitor; az.Enabled {
+		if az.Region == "" || az.ResourceID == "" {
+			return fmt.Errorf("azure monitor sink requires a region and a resource ID")
+		}
+		switch az.Auth {
+		case "managed_identity":
+		case "service_principal":
+			if az.TenantID == "" || az.ClientID == "" || az.ClientSecret == "" {
+				return fmt.Errorf("azure monitor service principal auth requires tenant_id, client_id and client_secret")
+			}
+		default:
+			return fmt.Errorf("invalid azure monitor auth: %s (use managed_identity or service_principal)", az.Auth)
+		}
+	}
+
+	if gc := c.Sinks.GoogleCloud; gc.Enabled {
+		switch gc.ResourceType {
+		case "generic_node", "gce_instance", "k8s_container", "global":
+		default:
+			return fmt.Errorf("invalid google cloud resource type: %s (use generic_node, gce_instance, k8s_container or global)", gc.ResourceType)
+		}
+		if gc.MetricPrefix == "" {
+			return fmt.Errorf("google cloud sink requires a metric prefix")
+		}
+		if gc.BatchSize < 1 || gc.BatchSize > 200 {
+			return fmt.Errorf("google cloud batch size must be between 1 and 200")
+		}
+	}
+
+	if sp := c.Sinks.Splunk; sp.Enabled {
+		if sp.URL == "" || sp.Token == "" {
+			return fmt.Errorf("splunk sink requires a URL and an HEC token")
+		}
+		if sp.BatchSize < 1 {
+			return fmt.Errorf("splunk batch size must be positive")
+		}
+		if sp.MaxRetries < 0 {
+			return fmt.Errorf("splunk max retries cannot be negative")
+		}
+	}
+
+	if sl := c.Sinks.Syslog; sl.Enabled {
+		if sl.Network != "udp" && sl.Network != "tcp" && sl.Network != "tls" {
+			return fmt.Errorf("invalid syslog network: %s (use udp, tcp or tls)", sl.Network)
+		}
+		if sl.Format != "cef" && sl.Format != "leef" {
+			return fmt.Errorf("invalid syslog format: %s (use cef or leef)", sl.Format)
+		}
+		if sl.Address == "" {
+			return fmt.Errorf("syslog sink requires an address")
+		}
+	}
+
+	if f := c.Sinks.File; f.Enabled {
+		if f.Path == "" {
+			return fmt.Errorf("file sink requires a path")
+		}
+		if f.MaxSizeMB < 0 || f.RotateInterval < 0 || f.MaxFiles < 0 {
+			return fmt.Errorf("file sink rotation settings cannot be negative")
+		}
+	}
+
+	if n := c.Sinks.NATS; n.Enabled {
+		if n.URL == "" {
+			return fmt.Errorf("nats sink requires a url")
+		}
+		if n.SubjectPrefix == "" || strings.ContainsAny(n.SubjectPrefix, " *>") {
+			return fmt.Errorf("invalid nats subject prefix: %q", n.SubjectPrefix)
+		}
+		if n.Stream != "" && !n.JetStream {
+			return fmt.Errorf("nats stream requires jetstream")
+		}
+	}
+
+	if c.Store.Enabled {
+		if c.Store.Path == "" {
+			return fmt.Errorf("history store requires a path")
+		}
+		if c.Store.Retention < 0 {
+			return fmt.Errorf("history store retention cannot be negative")
+		}
+	}
+
+	if c.API.Enabled {
+		if !c.Prometheus.EnableOTel {
+			return fmt.Errorf("the REST API is served by the metrics HTTP server and requires prometheus.enable_otel")
+		}
+		if c.API.Cycles < 1 {
+			return fmt.Errorf("api cycles must be positive")
+		}
+	}
+
+	if c.GRPC.Enabled {
+		if c.GRPC.Address == "" {
+			return fmt.Errorf("grpc server requires an address")
+		}
+		if c.GRPC.BufferSize < 1 {
+			return fmt.Errorf("grpc buffer size must be positive")
+		}
+	}
+
+	if c.WebSocket.Enabled {
+		if !c.Prometheus.EnableOTel {
+			return fmt.Errorf("the websocket stream is served by the metrics HTTP server and requires prometheus.enable_otel")
+		}
+		if c.WebSocket.BufferSize < 1 {
+			return fmt.Errorf("websocket buffer size must be positive")
+		}
+	}
+
+	if c.Alerts.Enabled {
+		if err := c.Alerts.validate(); err != nil {
+			return err
+		}
+	}
+
+	if a := c.Anomaly; a.Enabled {
+		if a.Method != "ewma" && a.Method != "rolling" {
+			return fmt.Errorf("invalid anomaly method: %s (use ewma or rolling)", a.Method)
+		}
+		if a.Window < 2 {
+			return fmt.Errorf("anomaly window must be at least 2 samples")
+		}
+		if a.Threshold <= 0 {
+			return fmt.Errorf("anomaly threshold must be positive")
+		}
+		if a.MinSamples < 1 {
+			return fmt.Errorf("anomaly min samples must be positive")
+		}
+	}
+
+	if f := c.Forecast; f.Enabled {
+		if f.Window < 1 {
+			return fmt.Errorf("forecast window must be positive")
+		}
+		if f.MaxDepthRefresh <= 0 {
+			return fmt.Errorf("forecast max depth refresh must be positive")
+		}
+	}
+
+	if c.Idle.Enabled && c.Idle.Intervals < 1 {
+		return fmt.Errorf("idle intervals must be positive")
+	}
+
+	if i := c.Imbalance; i.Enabled {
+		if i.Window < 1 {
+			return fmt.Errorf("imbalance window must be positive")
+		}
+		if i.Threshold <= 0 {
+			return fmt.Errorf("imbalance threshold must be positive")
+		}
+		if i.Sustain < 1 {
+			return fmt.Errorf("imbalance sustain must be positive")
+		}
+	}
+
+	if c.Throughput.Enabled && c.Throughput.Window < 1 {
+		return fmt.Errorf("throughput window must be positive")
+	}
+
+	if b := c.Baseline; b.Enabled {
+		if b.MinSamples < 1 {
+			return fmt.Errorf("baseline min samples must be positive")
+		}
+		if b.MaxSamples < b.MinSamples {
+			return fmt.Errorf("baseline max samples must be at least min samples")
+		}
+		if _, err := time.LoadLocation(b.Timezone); err != nil {
+			return fmt.Errorf("invalid baseline timezone: %w", err)
+		}
+	}
+
+	if c.Spool.Enabled && c.Spool.MemoryLimitMB < 1 {
+		return fmt.Errorf("spool memory limit must be positive")
+	}
+
+	if r := c.Resources; r.MemoryLimitMB < 0 || r.MaxProcs < 0 || r.MaxInFlight < 0 {
+		return fmt.Errorf("resource limits must not be negative")
+	}
 
+	if i := c.Intervals; i.Statistics < 0 || i.Accounting < 0 {
+		return fmt.Errorf("expected intervals must not be negative")
+	}
+
+	if q := c.Quarantine; q.Enabled {
+		if q.Directory == "" && q.Queue == "" {
+			return fmt.Errorf("quarantine requires a directory or a queue")
+		}
+		if q.MaxMessages < 0 {
+			return fmt.Errorf("quarantine max messages must not be negative")
+		}
+	}
+
+	if c.Collector.ResetStats {
+		if len(c.Collector.ResetQueues) == 0 {
+			return fmt.Errorf("reset stats requires at least one reset queue")
+		}
+		for _, queue := range c.Collector.ResetQueues {
+			if queue == "" {
+				return fmt.Errorf("reset queues must not be empty")
+			}
+		}
+	}
+
+	if d := c.LiveDepth; d.Enabled {
+		if len(d.Queues) == 0 {
+			return fmt.Errorf("live depth requires at least one queue")
+		}
+		for _, queue := range d.Queues {
+			if queue == "" {
+				return fmt.Errorf("live depth queues must not be empty")
+			}
+		}
+	}
+
+	if m := c.ResourceMonitor; m.Enabled {
+		if len(m.Classes) == 0 {
+			return fmt.Errorf("resource monitor requires at least one class")
+		}
+		for _, class := range m.Classes {
+			if class == "" {
+				return fmt.Errorf("resource monitor classes must not be empty")
+			}
+			if class == "STATQ" && len(m.Queues) == 0 {
+				return fmt.Errorf("resource monitor class STATQ requires at least one queue")
+			}
+		}
+		for _, queue := range m.Queues {
+			if queue == "" {
+				return fmt.Errorf("resource monitor queues must not be empty")
+			}
+		}
+	}
+
+	if c.PerformanceEvents.Enabled && c.PerformanceEvents.Queue == "" {
+		return fmt.Errorf("performance events require a queue")
+	}
+
+	for _, pattern := range c.DynamicQueues.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid dynamic queue pattern %q", pattern)
+		}
+	}
+
+	return nil
+}
+
+// validate checks the webhooks and the rules referring to them
+func (a *AlertsConfig) validate() error {
+	if len(a.Webhooks) == 0 || len(a.Rules) == 0 {
+		return fmt.Errorf("alerts require at least one webhook and one rule")
+	}
+
+	webhooks := make(map[string]bool)
+	for _, webhook := range a.Webhooks {
+		if webhook.Name == "" || webhook.URL == "" {
+			return fmt.Errorf("alert webhooks require a name and a url")
+		}
+		if webhooks[webhook.Name] {
+			return fmt.Errorf("duplicate alert webhook: %s", webhook.Name)
+		}
+		webhooks[webhook.Name] = true
+		switch webhook.Format {
+		case "slack", "teams", "generic":
+		default:
+			return fmt.Errorf("invalid format for alert webhook %s: %s (use slack, teams or generic)", webhook.Name, webhook.Format)
+		}
+	}
+
+	for _, rule := range a.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("alert rules require a name")
+		}
+		switch rule.Type {
+		case "queue_depth", "no_readers", "no_consumer", "backouts":
+		default:
+			return fmt.Errorf("invalid type for alert rule %s: %s (use queue_depth, no_readers, no_consumer or backouts)", rule.Name, rule.Type)
+		}
+		if rule.Threshold < 0 || rule.Intervals < 0 {
+			return fmt.Errorf("alert rule %s: threshold and intervals cannot be negative", rule.Name)
+		}
+		for _, pattern := range rule.Objects {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("alert rule %s: invalid object pattern %q", rule.Name, pattern)
+			}
+		}
+		for _, name := range rule.Webhooks {
+			if !webhooks[name] {
+				return fmt.Errorf("alert rule %s: unknown webhook %s", rule.Name, name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -26,6 +26,8 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, 9090, cfg.Prometheus.Port)              // This has a default
 	assert.Equal(t, "/metrics", cfg.Prometheus.Path)        // This has a default
 	assert.Equal(t, "ibmmq", cfg.Prometheus.Namespace)      // This has a default
+	assert.Equal(t, []string{"SYSTEM.ADMIN.QMGR.EVENT", "SYSTEM.ADMIN.PERFM.EVENT", "SYSTEM.ADMIN.CHANNEL.EVENT"},
+		cfg.Collector.EventQueues)
 }
 
 func TestLoadDefaultYAMLConfig(t *testing.T) {
@@ -129,6 +131,498 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "elasticsearch sink without URLs",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.Elasticsearch.Enabled = true
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "elasticsearch sink with URL",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.Elasticsearch.Enabled = true
+				cfg.Sinks.Elasticsearch.URLs = []string{"http://localhost:9200"}
+				return cfg
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid statsd flavor",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.StatsD.Enabled = true
+				cfg.Sinks.StatsD.Flavor = "influx"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "graphite sink without path template",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.Graphite.Enabled = true
+				cfg.Sinks.Graphite.PathTemplate = ""
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "cloudwatch sink without region",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.CloudWatch.Enabled = true
+				cfg.Sinks.CloudWatch.Region = ""
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "azure monitor service principal without secret",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.AzureMonitor.Enabled = true
+				cfg.Sinks.AzureMonitor.Region = "westeurope"
+				cfg.Sinks.AzureMonitor.ResourceID = "/subscriptions/1/resourceGroups/mq"
+				cfg.Sinks.AzureMonitor.Auth = "service_principal"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "google cloud sink with unknown resource type",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.GoogleCloud.Enabled = true
+				cfg.Sinks.GoogleCloud.ResourceType = "aws_ec2_instance"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "splunk sink without token",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.Splunk.Enabled = true
+				cfg.Sinks.Splunk.URL = "https://splunk:8088"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "syslog sink with unknown format",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.Syslog.Enabled = true
+				cfg.Sinks.Syslog.Format = "json"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "api without the metrics HTTP server",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.API.Enabled = true
+				cfg.Prometheus.EnableOTel = false
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "file sink without a path",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.File.Enabled = true
+				cfg.Sinks.File.Path = ""
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "nats stream without jetstream",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Sinks.NATS.Enabled = true
+				cfg.Sinks.NATS.Stream = "IBMMQ"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "alert rule with an unknown webhook",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Alerts.Enabled = true
+				cfg.Alerts.Webhooks = []WebhookConfig{{Name: "ops", URL: "http://localhost/hook", Format: "slack"}}
+				cfg.Alerts.Rules = []AlertRule{{Name: "backlog", Type: "queue_depth", Webhooks: []string{"oncall"}}}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "anomaly detection with an unknown method",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Anomaly.Enabled = true
+				cfg.Anomaly.Method = "holt-winters"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "forecast without a window",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Forecast.Enabled = true
+				cfg.Forecast.Window = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "imbalance without a threshold",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Imbalance.Enabled = true
+				cfg.Imbalance.Threshold = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "throughput without a window",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Throughput.Enabled = true
+				cfg.Throughput.Window = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "baseline with an unknown timezone",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Baseline.Enabled = true
+				cfg.Baseline.Timezone = "Mars/Olympus"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative max in-flight messages",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Resources.MaxInFlight = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "spool without memory limit",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Spool.Enabled = true
+				cfg.Spool.MemoryLimitMB = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative max get rate",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.MaxGetRate = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative max message length",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.MaxMessageLength = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "accept truncated without max message length",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.AcceptTruncated = true
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "max message length without accept truncated",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.MaxMessageLength = 1 << 20
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "backout queue without syncpoint",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.BackoutQueue = "COLLECTOR.BACKOUT"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative backout threshold",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.Syncpoint = true
+				cfg.MQ.BackoutThreshold = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative expected statistics interval",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Intervals.Statistics = -time.Minute
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "quarantine without a destination",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Quarantine.Enabled = true
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative parse workers",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Collector.ParseWorkers = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative empty cycles",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Collector.EmptyCycles = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "invalid dynamic queue pattern",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.DynamicQueues.Patterns = []string{"AMQ.["}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "prometheus tls certificate without key",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Prometheus.TLS.CertFile = "/etc/collector/tls.crt"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "prometheus client CA without server certificate",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Prometheus.TLS.ClientCAFile = "/etc/collector/clients.crt"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "prometheus allowed client names without client CA",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Prometheus.TLS.CertFile = "/etc/collector/tls.crt"
+				cfg.Prometheus.TLS.KeyFile = "/etc/collector/tls.key"
+				cfg.Prometheus.TLS.AllowedClientCNs = []string{"prometheus"}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "prometheus empty auth token",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Prometheus.Auth.Tokens = []string{"scrape-token", ""}
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative log sampling",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Logging.Sampling.Every = -1
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "connection name list with an empty entry",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.ConnectionName = "mq1(1414),,mq2(1414)"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "key repository without cipher spec",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.KeyRepository = "/var/mqm/ssl/key"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "certificate label without cipher spec",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.CertificateLabel = "collector"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "browse under syncpoint",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.Browse = true
+				cfg.MQ.Syncpoint = true
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative get wait interval",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.GetWaitInterval = -time.Second
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "match correl id not hex",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.MatchCorrelID = "collector-1"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "application name too long",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.ApplicationName = "ibmmq-stat-otel-collector-production"
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "live depth without queues",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.LiveDepth.Enabled = true
+				cfg.LiveDepth.Queues = nil
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "negative command timeout",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.MQ.CommandTimeout = -time.Second
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "reset stats without queues",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Collector.ResetStats = true
+				cfg.Collector.ResetQueues = nil
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "resource monitor STATQ without queues",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.ResourceMonitor.Enabled = true
+				cfg.ResourceMonitor.Classes = []string{"CPU", "STATQ"}
+				cfg.ResourceMonitor.Queues = nil
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "performance events without queue",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.PerformanceEvents.Enabled = true
+				cfg.PerformanceEvents.Queue = ""
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "idle detection without intervals",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.Idle.Enabled = true
+				cfg.Idle.Intervals = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "grpc without a buffer",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.GRPC.Enabled = true
+				cfg.GRPC.BufferSize = 0
+				return cfg
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "websocket without the metrics HTTP server",
+			config: func() *Config {
+				cfg, _ := LoadConfig("../../configs/default.yaml")
+				cfg.WebSocket.Enabled = true
+				cfg.Prometheus.EnableOTel = false
+				return cfg
+			}(),
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -231,6 +725,14 @@ collector:
 	assert.Equal(t, "testhost(2414)", cfg.MQ.ConnectionName)
 }
 
+func TestConnectionNames(t *testing.T) {
+	mq := MQConfig{ConnectionName: "mq1.example.com(1414), mq2.example.com(1414)"}
+	assert.Equal(t, []string{"mq1.example.com(1414)", "mq2.example.com(1414)"}, mq.ConnectionNames())
+
+	mq = MQConfig{Host: "testhost", Port: 2414}
+	assert.Equal(t, []string{"testhost(2414)"}, mq.ConnectionNames())
+}
+
 func TestConfigYAMLParsing(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -258,6 +760,27 @@ collector:
 				assert.Equal(t, "localhost(1414)", cfg.MQ.ConnectionName)
 			},
 		},
+		{
+			name: "custom event queues",
+			yaml: `
+mq:
+  queue_manager: "EVENT_QM"
+  host: "localhost"
+  port: 1414
+  channel: "TEST.SVRCONN"
+
+collector:
+  stats_queue: "SYSTEM.ADMIN.STATISTICS.QUEUE"
+  accounting_queue: "SYSTEM.ADMIN.ACCOUNTING.QUEUE"
+  event_queues:
+    - "SYSTEM.ADMIN.PERFM.EVENT"
+    - "SYSTEM.ADMIN.CONFIG.EVENT"
+`,
+			wantErr: false,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"SYSTEM.ADMIN.PERFM.EVENT", "SYSTEM.ADMIN.CONFIG.EVENT"}, cfg.Collector.EventQueues)
+			},
+		},
 		{
 			name: "config with all sections",
 			yaml: `
@@ -499,3 +1022,43 @@ func TestConfigurationEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamicQueuesCollapse(t *testing.T) {
+	cfg, err := LoadConfig("../../configs/default.yaml")
+	require.NoError(t, err)
+	queues := &cfg.DynamicQueues
+
+	assert.Equal(t, "AMQ.*", queues.Collapse("AMQ.5F3A1B2C00010203"))
+	assert.Equal(t, "IBMMQSTAT.REPLY.*", queues.Collapse("IBMMQSTAT.REPLY.65F0A1B2"))
+	assert.Equal(t, "APP.ORDERS", queues.Collapse("APP.ORDERS"))
+
+	queues.Enabled = false
+	assert.Equal(t, "AMQ.5F3A1B2C00010203", queues.Collapse("AMQ.5F3A1B2C00010203"))
+}
+
+func TestAuthTokensFromEnvironment(t *testing.T) {
+	t.Setenv("IBMMQ_API_TOKENS", "scrape-token,admin-token")
+
+	cfg, err := LoadConfig("../../configs/default.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scrape-token", "admin-token"}, cfg.Prometheus.Auth.Tokens)
+	assert.True(t, cfg.Prometheus.Auth.Enabled())
+}
+
+func TestFIPSValidation(t *testing.T) {
+	defer func(enabled func() bool) { fipsEnabled = enabled }(fipsEnabled)
+
+	cfg, err := LoadConfig("../../configs/default.yaml")
+	require.NoError(t, err)
+	cfg.FIPS = true
+
+	// The Go cryptographic module must run in FIPS 140-3 mode
+	fipsEnabled = func() bool { return false }
+	assert.Error(t, cfg.Validate())
+
+	fipsEnabled = func() bool { return true }
+	cfg.MQ.CipherSpec = "ECDHE_RSA_AES_256_GCM_SHA384"
+	assert.NoError(t, cfg.Validate())
+	cfg.MQ.CipherSpec = "TLS_CHACHA20_POLY1305_SHA256"
+	assert.Error(t, cfg.Validate())
+}
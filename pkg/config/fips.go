@@ -0,0 +1,50 @@
+package config
+
+import (
+	"crypto/fips140"
+	"fmt"
+	"slices"
+)
+
+// FIPSCipherSpecs are the MQ channel cipher specs that use FIPS-approved algorithms only.
+// The ANY_ specs negotiate among these when the connection requires FIPS.
+var FIPSCipherSpecs = []string{
+	"TLS_RSA_WITH_AES_128_CBC_SHA256",
+	"TLS_RSA_WITH_AES_256_CBC_SHA256",
+	"TLS_RSA_WITH_AES_128_GCM_SHA256",
+	"TLS_RSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE_ECDSA_AES_128_CBC_SHA256",
+	"ECDHE_ECDSA_AES_256_CBC_SHA384",
+	"ECDHE_ECDSA_AES_128_GCM_SHA256",
+	"ECDHE_ECDSA_AES_256_GCM_SHA384",
+	"ECDHE_RSA_AES_128_CBC_SHA256",
+	"ECDHE_RSA_AES_256_CBC_SHA384",
+	"ECDHE_RSA_AES_128_GCM_SHA256",
+	"ECDHE_RSA_AES_256_GCM_SHA384",
+	"TLS_AES_128_GCM_SHA256",
+	"TLS_AES_256_GCM_SHA384",
+	"ANY_TLS12",
+	"ANY_TLS12_OR_HIGHER",
+	"ANY_TLS13",
+	"ANY_TLS13_OR_HIGHER",
+}
+
+// fipsEnabled reports whether the Go cryptographic module runs in FIPS 140-3 mode; a
+// variable so tests can run either way
+var fipsEnabled = fips140.Enabled
+
+// validateFIPS checks that the MQ channel and the HTTP servers can be restricted to
+// FIPS-approved algorithms and that the process runs in FIPS 140-3 mode
+func (c *Config) validateFIPS() error {
+	if !c.FIPS {
+		return nil
+	}
+
+	if !fipsEnabled() {
+		return fmt.Errorf("fips requires the Go cryptographic module in FIPS 140-3 mode: run with GODEBUG=fips140=on or build with GOFIPS140")
+	}
+	if c.MQ.CipherSpec != "" && !slices.Contains(FIPSCipherSpecs, c.MQ.CipherSpec) {
+		return fmt.Errorf("fips does not allow mq cipher spec %s", c.MQ.CipherSpec)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"maps"
+	"net/url"
+	"slices"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/redact"
+)
+
+// Redacted returns a copy of the configuration with its passwords, tokens, keys, URL
+// credentials and webhook header values masked, for logging and printing. The
+// configuration itself is left unchanged.
+func (c *Config) Redacted() *Config {
+	r := *c
+
+	mask(&r.MQ.Password)
+	mask(&r.MQ.KeyRepositoryPassword)
+	mask(&r.Sinks.Elasticsearch.Password)
+	mask(&r.Sinks.CloudWatch.SecretAccessKey)
+	mask(&r.Sinks.CloudWatch.SessionToken)
+	mask(&r.Sinks.AzureMonitor.ClientSecret)
+	mask(&r.Sinks.Splunk.Token)
+	mask(&r.Sinks.NATS.Password)
+	mask(&r.Sinks.NATS.Token)
+
+	r.Prometheus.Auth.Tokens = slices.Clone(c.Prometheus.Auth.Tokens)
+	for i := range r.Prometheus.Auth.Tokens {
+		mask(&r.Prometheus.Auth.Tokens[i])
+	}
+
+	r.Sinks.Elasticsearch.URLs = slices.Clone(c.Sinks.Elasticsearch.URLs)
+	for i, u := range r.Sinks.Elasticsearch.URLs {
+		r.Sinks.Elasticsearch.URLs[i] = redactURL(u)
+	}
+	r.Sinks.Splunk.URL = redactURL(c.Sinks.Splunk.URL)
+	r.Sinks.NATS.URL = redactURL(c.Sinks.NATS.URL)
+
+	r.Alerts.Webhooks = slices.Clone(c.Alerts.Webhooks)
+	for i := range r.Alerts.Webhooks {
+		webhook := &r.Alerts.Webhooks[i]
+		webhook.URL = redactURL(webhook.URL)
+		webhook.Headers = maps.Clone(webhook.Headers)
+		for name := range webhook.Headers {
+			webhook.Headers[name] = redact.Mask
+		}
+	}
+	return &r
+}
+
+// Secrets returns the credentials held by the configuration, as masked by Redacted
+func (c *Config) Secrets() []string {
+	secrets := []string{
+		c.MQ.Password,
+		c.MQ.KeyRepositoryPassword,
+		c.Sinks.Elasticsearch.Password,
+		c.Sinks.CloudWatch.SecretAccessKey,
+		c.Sinks.CloudWatch.SessionToken,
+		c.Sinks.AzureMonitor.ClientSecret,
+		c.Sinks.Splunk.Token,
+		c.Sinks.NATS.Password,
+		c.Sinks.NATS.Token,
+		urlPassword(c.Sinks.Splunk.URL),
+		urlPassword(c.Sinks.NATS.URL),
+	}
+	secrets = append(secrets, c.Prometheus.Auth.Tokens...)
+	for _, u := range c.Sinks.Elasticsearch.URLs {
+		secrets = append(secrets, urlPassword(u))
+	}
+	for _, webhook := range c.Alerts.Webhooks {
+		secrets = append(secrets, urlPassword(webhook.URL))
+		for _, value := range webhook.Headers {
+			secrets = append(secrets, value)
+		}
+	}
+
+	return slices.DeleteFunc(secrets, func(s string) bool { return s == "" })
+}
+
+// mask replaces a set value with the redaction mask
+func mask(value *string) {
+	if *value != "" {
+		*value = redact.Mask
+	}
+}
+
+// redactURL masks the password of a URL, as url.URL.Redacted does
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// urlPassword returns the password of a URL, or "" if it has none
+func urlPassword(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return ""
+	}
+	password, _ := u.User.Password()
+	return password
+}
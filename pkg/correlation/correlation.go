@@ -0,0 +1,152 @@
+package correlation
+
+import (
+	"sort"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// ApplicationActivity is the traffic of one application on a queue
+type ApplicationActivity struct {
+	Application string `json:"application"`
+	User        string `json:"user,omitempty"`
+	Puts        int64  `json:"puts"`
+	Gets        int64  `json:"gets"`
+	PutBytes    int64  `json:"put_bytes"`
+	GetBytes    int64  `json:"get_bytes"`
+	// PutShare and GetShare are the application's fraction of the puts and gets that
+	// accounting attributes to the queue
+	PutShare float64 `json:"put_share"`
+	GetShare float64 `json:"get_share"`
+}
+
+// QueueActivity joins the statistics of a queue with the accounting records of the
+// applications that used it
+type QueueActivity struct {
+	QueueManager string `json:"queue_manager"`
+	Queue        string `json:"queue"`
+	// HasStatistics is false for queues only seen in accounting records
+	HasStatistics bool  `json:"has_statistics"`
+	Depth         int64 `json:"depth"`
+	Enqueued      int64 `json:"enqueued"`
+	Dequeued      int64 `json:"dequeued"`
+	// UnattributedPuts and UnattributedGets are the enqueues and dequeues of the
+	// statistics that no accounting record accounts for, for example from applications
+	// whose connections have not ended their accounting interval yet
+	UnattributedPuts int64                  `json:"unattributed_puts"`
+	UnattributedGets int64                  `json:"unattributed_gets"`
+	Applications     []*ApplicationActivity `json:"applications"`
+}
+
+type queueKey struct {
+	queueManager string
+	queue        string
+}
+
+type applicationKey struct {
+	application string
+	user        string
+}
+
+// Correlate joins the queue statistics of a collection cycle with the queue accounting
+// records of the same cycle, telling per queue which applications drove its traffic.
+// Records without a queue manager belong to qmgr. Queues are sorted by queue manager and
+// name, and their applications by puts plus gets, busiest first.
+func Correlate(qmgr string, statistics []*pcf.StatisticsData, accounting []*pcf.AccountingData) []*QueueActivity {
+	queues := make(map[queueKey]*QueueActivity)
+	apps := make(map[queueKey]map[applicationKey]*ApplicationActivity)
+
+	queue := func(key queueKey) *QueueActivity {
+		q := queues[key]
+		if q == nil {
+			q = &QueueActivity{QueueManager: key.queueManager, Queue: key.queue}
+			queues[key] = q
+			apps[key] = make(map[applicationKey]*ApplicationActivity)
+		}
+		return q
+	}
+
+	for _, stats := range statistics {
+		qs := stats.QueueStats
+		if qs == nil {
+			continue
+		}
+		q := queue(queueKey{queueManager: orDefault(stats.QueueManager, qmgr), queue: qs.QueueName})
+		q.HasStatistics = true
+		q.Depth = qs.CurrentDepth
+		q.Enqueued += int64(qs.EnqueueCount)
+		q.Dequeued += int64(qs.DequeueCount)
+	}
+
+	for _, acct := range accounting {
+		var appKey applicationKey
+		if info := acct.ConnectionInfo; info != nil {
+			appKey = applicationKey{application: info.ApplicationName, user: info.UserIdentifier}
+		}
+		for _, ops := range acct.Queues {
+			key := queueKey{queueManager: orDefault(acct.QueueManager, qmgr), queue: ops.QueueName}
+			queue(key)
+			app := apps[key][appKey]
+			if app == nil {
+				app = &ApplicationActivity{Application: appKey.application, User: appKey.user}
+				apps[key][appKey] = app
+			}
+			app.Puts += int64(ops.Puts)
+			app.Gets += int64(ops.Gets)
+			app.PutBytes += ops.PutBytes
+			app.GetBytes += ops.GetBytes
+		}
+	}
+
+	result := make([]*QueueActivity, 0, len(queues))
+	for key, q := range queues {
+		var puts, gets int64
+		for _, app := range apps[key] {
+			puts += app.Puts
+			gets += app.Gets
+			q.Applications = append(q.Applications, app)
+		}
+		for _, app := range q.Applications {
+			if puts > 0 {
+				app.PutShare = float64(app.Puts) / float64(puts)
+			}
+			if gets > 0 {
+				app.GetShare = float64(app.Gets) / float64(gets)
+			}
+		}
+		sort.Slice(q.Applications, func(i, j int) bool {
+			a, b := q.Applications[i], q.Applications[j]
+			if a.Puts+a.Gets != b.Puts+b.Gets {
+				return a.Puts+a.Gets > b.Puts+b.Gets
+			}
+			if a.Application != b.Application {
+				return a.Application < b.Application
+			}
+			return a.User < b.User
+		})
+		if q.Applications == nil {
+			q.Applications = []*ApplicationActivity{}
+		}
+
+		if q.HasStatistics {
+			q.UnattributedPuts = max(0, q.Enqueued-puts)
+			q.UnattributedGets = max(0, q.Dequeued-gets)
+		}
+		result = append(result, q)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].QueueManager != result[j].QueueManager {
+			return result[i].QueueManager < result[j].QueueManager
+		}
+		return result[i].Queue < result[j].Queue
+	})
+	return result
+}
+
+func orDefault(name, fallback string) string {
+	if name != "" {
+		return name
+	}
+	return fallback
+}
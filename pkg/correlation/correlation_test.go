@@ -0,0 +1,72 @@
+package correlation
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accountingRecord(app string, queues ...pcf.QueueOperations) *pcf.AccountingData {
+	return &pcf.AccountingData{
+		Type:           "accounting",
+		ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: app, UserIdentifier: "mqapp"},
+		Queues:         queues,
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	statistics := []*pcf.StatisticsData{
+		{QueueStats: &pcf.QueueStatistics{QueueName: "ORDERS.IN", CurrentDepth: 7, EnqueueCount: 100, DequeueCount: 93}},
+		{ChannelStats: &pcf.ChannelStatistics{ChannelName: "TO.QM2"}},
+	}
+	accounting := []*pcf.AccountingData{
+		accountingRecord("shop", pcf.QueueOperations{QueueName: "ORDERS.IN", Puts: 60, PutBytes: 6000}),
+		accountingRecord("shop", pcf.QueueOperations{QueueName: "ORDERS.IN", Puts: 15, PutBytes: 1500}),
+		accountingRecord("batch", pcf.QueueOperations{QueueName: "ORDERS.IN", Puts: 5}),
+		accountingRecord("fulfilment",
+			pcf.QueueOperations{QueueName: "ORDERS.IN", Gets: 93},
+			pcf.QueueOperations{QueueName: "SHIPPING.OUT", Puts: 93},
+		),
+	}
+
+	activity := Correlate("QM1", statistics, accounting)
+	require.Len(t, activity, 2)
+
+	orders := activity[0]
+	assert.Equal(t, "QM1", orders.QueueManager)
+	assert.Equal(t, "ORDERS.IN", orders.Queue)
+	assert.True(t, orders.HasStatistics)
+	assert.Equal(t, int64(7), orders.Depth)
+	assert.Equal(t, int64(20), orders.UnattributedPuts)
+	assert.Zero(t, orders.UnattributedGets)
+
+	require.Len(t, orders.Applications, 3)
+	assert.Equal(t, "fulfilment", orders.Applications[0].Application)
+	assert.Equal(t, 1.0, orders.Applications[0].GetShare)
+	shop := orders.Applications[1]
+	assert.Equal(t, "shop", shop.Application)
+	assert.Equal(t, int64(75), shop.Puts)
+	assert.Equal(t, int64(7500), shop.PutBytes)
+	assert.InDelta(t, 0.9375, shop.PutShare, 0.0001)
+
+	shipping := activity[1]
+	assert.Equal(t, "SHIPPING.OUT", shipping.Queue)
+	assert.False(t, shipping.HasStatistics)
+	assert.Zero(t, shipping.UnattributedPuts)
+	require.Len(t, shipping.Applications, 1)
+}
+
+func TestCorrelateWithoutAccounting(t *testing.T) {
+	statistics := []*pcf.StatisticsData{
+		{QueueManager: "QM2", QueueStats: &pcf.QueueStatistics{QueueName: "IDLE.Q", EnqueueCount: 3}},
+	}
+
+	activity := Correlate("QM1", statistics, nil)
+	require.Len(t, activity, 1)
+	assert.Equal(t, "QM2", activity[0].QueueManager)
+	assert.Equal(t, int64(3), activity[0].UnattributedPuts)
+	assert.NotNil(t, activity[0].Applications)
+	assert.Empty(t, activity[0].Applications)
+}
@@ -0,0 +1,172 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// Supported output formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Writer writes parsed statistics and accounting records to an output stream
+type Writer interface {
+	WriteStatistics(stats *pcf.StatisticsData) error
+	WriteAccounting(acct *pcf.AccountingData) error
+	// Close flushes buffered output. It does not close the underlying stream.
+	Close() error
+}
+
+// NewWriter creates a writer for the given format ("json"/"jsonl" or "csv")
+func NewWriter(format string, out io.Writer) (Writer, error) {
+	switch strings.ToLower(format) {
+	case FormatJSON, "jsonl":
+		return &jsonWriter{encoder: json.NewEncoder(out)}, nil
+	case FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(out)}, nil
+	case FormatParquet:
+		return nil, fmt.Errorf("parquet output is written to a directory, use NewParquetWriter")
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s (use json, csv or parquet)", format)
+	}
+}
+
+// jsonWriter emits one JSON document per line
+type jsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonWriter) WriteStatistics(stats *pcf.StatisticsData) error {
+	return w.encoder.Encode(stats)
+}
+
+func (w *jsonWriter) WriteAccounting(acct *pcf.AccountingData) error {
+	return w.encoder.Encode(acct)
+}
+
+func (w *jsonWriter) Close() error {
+	return nil
+}
+
+// CSVColumns is the flattened column layout shared by statistics and accounting rows
+var CSVColumns = []string{
+	"record_type", "timestamp", "queue_manager", "object_type", "object_name",
+	"channel_name", "connection_name", "application_name",
+	"current_depth", "high_depth", "enqueue_count", "dequeue_count", "input_count", "output_count",
+	"messages", "bytes", "batches",
+	"opens", "closes", "puts", "gets", "browses", "commits", "backouts",
+}
+
+// csvWriter flattens records into a single CSV table
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) WriteStatistics(stats *pcf.StatisticsData) error {
+	row := w.newRow(stats.Type, stats.Timestamp, stats.QueueManager)
+
+	if q := stats.QueueStats; q != nil {
+		row["object_type"] = "queue"
+		row["object_name"] = q.QueueName
+		row["current_depth"] = itoa(int64(q.CurrentDepth))
+		row["high_depth"] = itoa(int64(q.HighDepth))
+		row["enqueue_count"] = itoa(int64(q.EnqueueCount))
+		row["dequeue_count"] = itoa(int64(q.DequeueCount))
+		row["input_count"] = itoa(int64(q.InputCount))
+		row["output_count"] = itoa(int64(q.OutputCount))
+	}
+
+	if ch := stats.ChannelStats; ch != nil {
+		row["object_type"] = "channel"
+		row["object_name"] = ch.ChannelName
+		row["channel_name"] = ch.ChannelName
+		row["connection_name"] = ch.ConnectionName
+		row["messages"] = itoa(int64(ch.Messages))
+		row["bytes"] = itoa(ch.Bytes)
+		row["batches"] = itoa(int64(ch.Batches))
+	}
+
+	if mqi := stats.MQIStats; mqi != nil {
+		row["object_type"] = "mqi"
+		row["object_name"] = mqi.ApplicationName
+		row["application_name"] = mqi.ApplicationName
+		row["opens"] = itoa(int64(mqi.Opens))
+		row["closes"] = itoa(int64(mqi.Closes))
+		row["puts"] = itoa(int64(mqi.Puts))
+		row["gets"] = itoa(int64(mqi.Gets))
+		row["commits"] = itoa(int64(mqi.Commits))
+		row["backouts"] = itoa(int64(mqi.Backouts))
+	}
+
+	return w.write(row)
+}
+
+func (w *csvWriter) WriteAccounting(acct *pcf.AccountingData) error {
+	row := w.newRow(acct.Type, acct.Timestamp, acct.QueueManager)
+	row["object_type"] = "connection"
+
+	if info := acct.ConnectionInfo; info != nil {
+		row["object_name"] = info.ApplicationName
+		row["channel_name"] = info.ChannelName
+		row["connection_name"] = info.ConnectionName
+		row["application_name"] = info.ApplicationName
+	}
+
+	if ops := acct.Operations; ops != nil {
+		row["opens"] = itoa(int64(ops.Opens))
+		row["closes"] = itoa(int64(ops.Closes))
+		row["puts"] = itoa(int64(ops.Puts))
+		row["gets"] = itoa(int64(ops.Gets))
+		row["browses"] = itoa(int64(ops.Browses))
+		row["commits"] = itoa(int64(ops.Commits))
+		row["backouts"] = itoa(int64(ops.Backouts))
+	}
+
+	return w.write(row)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) newRow(recordType string, timestamp time.Time, qmgr string) map[string]string {
+	return map[string]string{
+		"record_type":   recordType,
+		"timestamp":     timestamp.UTC().Format(time.RFC3339),
+		"queue_manager": qmgr,
+	}
+}
+
+func (w *csvWriter) write(row map[string]string) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(CSVColumns); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		w.headerWritten = true
+	}
+
+	record := make([]string, len(CSVColumns))
+	for i, column := range CSVColumns {
+		record[i] = row[column]
+	}
+
+	if err := w.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write CSV row: %w", err)
+	}
+	return nil
+}
+
+func itoa(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatistics() *pcf.StatisticsData {
+	return &pcf.StatisticsData{
+		Type:         "statistics",
+		QueueManager: "TESTQM",
+		Timestamp:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		QueueStats: &pcf.QueueStatistics{
+			QueueName:    "APP.ORDERS",
+			CurrentDepth: 10,
+			HighDepth:    50,
+			EnqueueCount: 200,
+			DequeueCount: 190,
+			InputCount:   1,
+			OutputCount:  2,
+		},
+	}
+}
+
+func testAccounting() *pcf.AccountingData {
+	return &pcf.AccountingData{
+		Type:         "accounting",
+		QueueManager: "TESTQM",
+		Timestamp:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		ConnectionInfo: &pcf.ConnectionInfo{
+			ApplicationName: "order-service",
+			ChannelName:     "APP1.SVRCONN",
+			ConnectionName:  "10.0.0.1",
+		},
+		Operations: &pcf.OperationCounts{Puts: 5, Gets: 3},
+	}
+}
+
+func TestNewWriterFormats(t *testing.T) {
+	for _, format := range []string{"json", "jsonl", "JSON", "csv"} {
+		w, err := NewWriter(format, &bytes.Buffer{})
+		assert.NoError(t, err, format)
+		assert.NotNil(t, w, format)
+	}
+
+	_, err := NewWriter("xml", &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestJSONWriterEmitsOneRecordPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatJSON, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.WriteStatistics(testStatistics()))
+	require.NoError(t, w.WriteAccounting(testAccounting()))
+	require.NoError(t, w.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var stats pcf.StatisticsData
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &stats))
+	assert.Equal(t, "APP.ORDERS", stats.QueueStats.QueueName)
+
+	var acct pcf.AccountingData
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &acct))
+	assert.Equal(t, "order-service", acct.ConnectionInfo.ApplicationName)
+}
+
+func TestCSVWriterFlattensRecords(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.WriteStatistics(testStatistics()))
+	require.NoError(t, w.WriteAccounting(testAccounting()))
+	require.NoError(t, w.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, CSVColumns, records[0])
+
+	column := func(name string) int {
+		for i, c := range CSVColumns {
+			if c == name {
+				return i
+			}
+		}
+		t.Fatalf("unknown column %s", name)
+		return -1
+	}
+
+	assert.Equal(t, "queue", records[1][column("object_type")])
+	assert.Equal(t, "APP.ORDERS", records[1][column("object_name")])
+	assert.Equal(t, "10", records[1][column("current_depth")])
+	assert.Equal(t, "2024-03-01T12:00:00Z", records[1][column("timestamp")])
+	assert.Equal(t, "", records[1][column("puts")])
+
+	assert.Equal(t, "connection", records[2][column("object_type")])
+	assert.Equal(t, "order-service", records[2][column("application_name")])
+	assert.Equal(t, "5", records[2][column("puts")])
+}
@@ -0,0 +1,264 @@
+package export
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/parquet-go/parquet-go"
+)
+
+// FormatParquet writes partitioned Parquet files to a directory instead of a stream
+const FormatParquet = "parquet"
+
+// StatisticsRow is the Parquet row of a statistics record. Only the columns of the
+// record's object type are set; the others are null.
+type StatisticsRow struct {
+	Timestamp       time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	ObjectType      string    `parquet:"object_type,dict"`
+	ObjectName      string    `parquet:"object_name,dict"`
+	CurrentDepth    *int64    `parquet:"current_depth,optional"`
+	HighDepth       *int64    `parquet:"high_depth,optional"`
+	EnqueueCount    *int64    `parquet:"enqueue_count,optional"`
+	DequeueCount    *int64    `parquet:"dequeue_count,optional"`
+	InputCount      *int64    `parquet:"input_count,optional"`
+	OutputCount     *int64    `parquet:"output_count,optional"`
+	ConnectionName  *string   `parquet:"connection_name,optional"`
+	Messages        *int64    `parquet:"messages,optional"`
+	Bytes           *int64    `parquet:"bytes,optional"`
+	Batches         *int64    `parquet:"batches,optional"`
+	Opens           *int64    `parquet:"opens,optional"`
+	Closes          *int64    `parquet:"closes,optional"`
+	Puts            *int64    `parquet:"puts,optional"`
+	Gets            *int64    `parquet:"gets,optional"`
+	Commits         *int64    `parquet:"commits,optional"`
+	Backouts        *int64    `parquet:"backouts,optional"`
+	ApplicationName *string   `parquet:"application_name,optional,dict"`
+}
+
+// AccountingRow is the Parquet row of an accounting record
+type AccountingRow struct {
+	Timestamp       time.Time  `parquet:"timestamp,timestamp(millisecond)"`
+	ChannelName     string     `parquet:"channel_name,dict"`
+	ConnectionName  string     `parquet:"connection_name"`
+	ApplicationName string     `parquet:"application_name,dict"`
+	UserIdentifier  string     `parquet:"user_identifier,dict"`
+	ConnectTime     *time.Time `parquet:"connect_time,optional"`
+	DisconnectTime  *time.Time `parquet:"disconnect_time,optional"`
+	Opens           int64      `parquet:"opens"`
+	Closes          int64      `parquet:"closes"`
+	Puts            int64      `parquet:"puts"`
+	Gets            int64      `parquet:"gets"`
+	Browses         int64      `parquet:"browses"`
+	Commits         int64      `parquet:"commits"`
+	Backouts        int64      `parquet:"backouts"`
+	PutBytes        int64      `parquet:"put_bytes"`
+	GetBytes        int64      `parquet:"get_bytes"`
+}
+
+// partition identifies one output directory
+type partition struct {
+	recordType   string
+	date         string
+	queueManager string
+}
+
+// ParquetWriter buffers records and writes them on Close as Snappy-compressed Parquet
+// files partitioned Hive-style by record type, UTC date and queue manager:
+//
+//	<dir>/record_type=statistics/date=2026-03-14/queue_manager=QM1/part-<time>.parquet
+//
+// Each Close adds new part files, so repeated exports into the same directory build up
+// a dataset that DuckDB (hive_partitioning) and Spark read as one table.
+type ParquetWriter struct {
+	dir        string
+	now        func() time.Time
+	statistics map[partition][]StatisticsRow
+	accounting map[partition][]AccountingRow
+}
+
+// NewParquetWriter creates a writer adding part files under dir
+func NewParquetWriter(dir string) *ParquetWriter {
+	return &ParquetWriter{
+		dir:        dir,
+		now:        time.Now,
+		statistics: make(map[partition][]StatisticsRow),
+		accounting: make(map[partition][]AccountingRow),
+	}
+}
+
+func (w *ParquetWriter) WriteStatistics(stats *pcf.StatisticsData) error {
+	row := StatisticsRow{Timestamp: stats.Timestamp.UTC()}
+
+	if q := stats.QueueStats; q != nil {
+		row.ObjectType = "queue"
+		row.ObjectName = q.QueueName
+		row.CurrentDepth = &q.CurrentDepth
+		row.HighDepth = &q.HighDepth
+		row.EnqueueCount = &q.EnqueueCount
+		row.DequeueCount = &q.DequeueCount
+		row.InputCount = &q.InputCount
+		row.OutputCount = &q.OutputCount
+	}
+
+	if ch := stats.ChannelStats; ch != nil {
+		row.ObjectType = "channel"
+		row.ObjectName = ch.ChannelName
+		row.ConnectionName = &ch.ConnectionName
+		row.Messages = &ch.Messages
+		row.Bytes = &ch.Bytes
+		row.Batches = &ch.Batches
+	}
+
+	if mqi := stats.MQIStats; mqi != nil {
+		row.ObjectType = "mqi"
+		row.ObjectName = mqi.ApplicationName
+		row.ApplicationName = &mqi.ApplicationName
+		row.Opens = &mqi.Opens
+		row.Closes = &mqi.Closes
+		row.Puts = &mqi.Puts
+		row.Gets = &mqi.Gets
+		row.Commits = &mqi.Commits
+		row.Backouts = &mqi.Backouts
+	}
+
+	key := w.partition("statistics", stats.Timestamp, stats.QueueManager)
+	w.statistics[key] = append(w.statistics[key], row)
+	return nil
+}
+
+func (w *ParquetWriter) WriteAccounting(acct *pcf.AccountingData) error {
+	row := AccountingRow{Timestamp: acct.Timestamp.UTC()}
+
+	if info := acct.ConnectionInfo; info != nil {
+		row.ChannelName = info.ChannelName
+		row.ConnectionName = info.ConnectionName
+		row.ApplicationName = info.ApplicationName
+		row.UserIdentifier = info.UserIdentifier
+		row.ConnectTime = optionalTime(info.ConnectTime)
+		row.DisconnectTime = optionalTime(info.DisconnectTime)
+	}
+
+	if ops := acct.Operations; ops != nil {
+		row.Opens = ops.Opens
+		row.Closes = ops.Closes
+		row.Puts = ops.Puts
+		row.Gets = ops.Gets
+		row.Browses = ops.Browses
+		row.Commits = ops.Commits
+		row.Backouts = ops.Backouts
+		row.PutBytes = ops.PutBytes
+		row.GetBytes = ops.GetBytes
+	}
+
+	key := w.partition("accounting", acct.Timestamp, acct.QueueManager)
+	w.accounting[key] = append(w.accounting[key], row)
+	return nil
+}
+
+// Close writes the buffered records, one part file per partition
+func (w *ParquetWriter) Close() error {
+	_, err := w.Flush()
+	return err
+}
+
+// Flush writes one part file per partition holding buffered records, returning the
+// paths of the files written, and empties the buffers
+func (w *ParquetWriter) Flush() ([]string, error) {
+	name := fmt.Sprintf("part-%s.parquet", w.now().UTC().Format("20060102T150405.000000000"))
+	var written []string
+
+	for _, key := range sortedPartitions(w.statistics) {
+		path, err := w.writePartition(key, name, func(file *os.File) error {
+			return parquet.Write(file, w.statistics[key], parquet.Compression(&parquet.Snappy))
+		})
+		if err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	for _, key := range sortedPartitions(w.accounting) {
+		path, err := w.writePartition(key, name, func(file *os.File) error {
+			return parquet.Write(file, w.accounting[key], parquet.Compression(&parquet.Snappy))
+		})
+		if err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	w.statistics = make(map[partition][]StatisticsRow)
+	w.accounting = make(map[partition][]AccountingRow)
+	return written, nil
+}
+
+// writePartition creates the partition directory and writes a part file through a
+// temporary name, so readers never see a partial file
+func (w *ParquetWriter) writePartition(key partition, name string, write func(*os.File) error) (string, error) {
+	dir := filepath.Join(w.dir,
+		"record_type="+key.recordType,
+		"date="+key.date,
+		"queue_manager="+url.PathEscape(key.queueManager))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create partition directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	tmp, err := os.CreateTemp(dir, ".part-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create parquet file: %w", err)
+	}
+
+	err = write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// partition returns the partition of a record; records without a timestamp are filed
+// under the export date
+func (w *ParquetWriter) partition(recordType string, ts time.Time, qmgr string) partition {
+	if ts.IsZero() {
+		ts = w.now()
+	}
+	if qmgr == "" {
+		qmgr = "unknown"
+	}
+	return partition{recordType: recordType, date: ts.UTC().Format("2006-01-02"), queueManager: qmgr}
+}
+
+func sortedPartitions[T any](rows map[partition][]T) []partition {
+	keys := make([]partition, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		return keys[i].queueManager < keys[j].queueManager
+	})
+	return keys
+}
+
+// optionalTime returns nil for zero times, which are written as nulls
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
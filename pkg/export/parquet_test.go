@@ -0,0 +1,73 @@
+package export
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetWriterPartitions(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewParquetWriter(dir)
+	writer.now = func() time.Time { return time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC) }
+
+	channel := &pcf.StatisticsData{
+		QueueManager: "TESTQM",
+		Timestamp:    time.Date(2024, 3, 1, 12, 5, 0, 0, time.UTC),
+		ChannelStats: &pcf.ChannelStatistics{ChannelName: "TO.QM2", ConnectionName: "10.0.0.2(1414)", Messages: 8, Bytes: 4096},
+	}
+	nextDay := testStatistics()
+	nextDay.Timestamp = nextDay.Timestamp.Add(24 * time.Hour)
+	nextDay.QueueManager = "QM/2"
+
+	require.NoError(t, writer.WriteStatistics(testStatistics()))
+	require.NoError(t, writer.WriteStatistics(channel))
+	require.NoError(t, writer.WriteStatistics(nextDay))
+	require.NoError(t, writer.WriteAccounting(testAccounting()))
+
+	written, err := writer.Flush()
+	require.NoError(t, err)
+
+	part := "part-20240302T080000.000000000.parquet"
+	assert.Equal(t, []string{
+		filepath.Join(dir, "record_type=statistics", "date=2024-03-01", "queue_manager=TESTQM", part),
+		filepath.Join(dir, "record_type=statistics", "date=2024-03-02", "queue_manager=QM%2F2", part),
+		filepath.Join(dir, "record_type=accounting", "date=2024-03-01", "queue_manager=TESTQM", part),
+	}, written)
+
+	stats, err := parquet.ReadFile[StatisticsRow](written[0])
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, "queue", stats[0].ObjectType)
+	assert.Equal(t, "APP.ORDERS", stats[0].ObjectName)
+	require.NotNil(t, stats[0].CurrentDepth)
+	assert.Equal(t, int64(10), *stats[0].CurrentDepth)
+	assert.Nil(t, stats[0].Messages)
+	assert.Equal(t, "channel", stats[1].ObjectType)
+	require.NotNil(t, stats[1].Bytes)
+	assert.Equal(t, int64(4096), *stats[1].Bytes)
+	assert.Nil(t, stats[1].CurrentDepth)
+	assert.True(t, channel.Timestamp.Equal(stats[1].Timestamp))
+
+	acct, err := parquet.ReadFile[AccountingRow](written[2])
+	require.NoError(t, err)
+	require.Len(t, acct, 1)
+	assert.Equal(t, "order-service", acct[0].ApplicationName)
+	assert.Equal(t, testAccounting().Operations.Puts, acct[0].Puts)
+	assert.Nil(t, acct[0].DisconnectTime)
+
+	// Buffers are empty after a flush
+	written, err = writer.Flush()
+	require.NoError(t, err)
+	assert.Empty(t, written)
+}
+
+func TestNewWriterRejectsParquet(t *testing.T) {
+	_, err := NewWriter(FormatParquet, nil)
+	assert.Error(t, err)
+}
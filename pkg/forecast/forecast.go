@@ -0,0 +1,115 @@
+package forecast
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Forecast is the backlog trend of a queue after its latest statistics interval
+type Forecast struct {
+	// DriftPerSecond is the average number of messages enqueued minus dequeued per
+	// second over the window; positive when the queue is filling
+	DriftPerSecond float64
+	// HasDrift is false until two intervals of the queue have been seen
+	HasDrift bool
+	// TimeToFull is the estimated time until the depth reaches MAXDEPTH at the current
+	// drift; only set when Filling
+	TimeToFull time.Duration
+	// Filling is true when the queue is filling and its MAXDEPTH is known
+	Filling bool
+}
+
+// sample is one statistics interval of a queue
+type sample struct {
+	time time.Time
+	net  int64 // enqueued minus dequeued during the interval
+}
+
+// Forecaster estimates per queue how fast the backlog grows from the enqueue and
+// dequeue counts of successive statistics intervals, and when the queue will be full
+type Forecaster struct {
+	window int
+
+	mu       sync.Mutex
+	samples  map[string][]sample
+	maxDepth map[string]int64
+}
+
+// NewForecaster creates a forecaster averaging the drift over window intervals
+func NewForecaster(window int) *Forecaster {
+	if window < 1 {
+		window = 1
+	}
+	return &Forecaster{
+		window:   window,
+		samples:  make(map[string][]sample),
+		maxDepth: make(map[string]int64),
+	}
+}
+
+// SetMaxDepth records the MAXDEPTH attribute of a queue
+func (f *Forecaster) SetMaxDepth(key string, maxDepth int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.maxDepth[key] = maxDepth
+}
+
+// MaxDepth returns the recorded MAXDEPTH of a queue, or 0 if unknown
+func (f *Forecaster) MaxDepth(key string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxDepth[key]
+}
+
+// ResetQueueManager drops the intervals of the queues of a queue manager, such as after it
+// restarted. Their MAXDEPTH is kept.
+func (f *Forecaster) ResetQueueManager(qmgr string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := qmgr + "/"
+	maps.DeleteFunc(f.samples, func(key string, _ []sample) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// Observe adds a statistics interval ending at the given time and returns the queue's
+// forecast. Intervals that are not newer than the previous one are ignored for the drift.
+func (f *Forecaster) Observe(key string, at time.Time, depth, enqueued, dequeued int64) Forecast {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	samples := f.samples[key]
+	if len(samples) == 0 || at.After(samples[len(samples)-1].time) {
+		// The oldest kept sample only marks the start of the window
+		samples = append(samples, sample{time: at, net: enqueued - dequeued})
+		if len(samples) > f.window+1 {
+			samples = samples[len(samples)-f.window-1:]
+		}
+		f.samples[key] = samples
+	}
+
+	var forecast Forecast
+	if len(samples) < 2 {
+		return forecast
+	}
+
+	var net int64
+	for _, s := range samples[1:] {
+		net += s.net
+	}
+	elapsed := samples[len(samples)-1].time.Sub(samples[0].time).Seconds()
+	forecast.DriftPerSecond = float64(net) / elapsed
+	forecast.HasDrift = true
+
+	maxDepth := f.maxDepth[key]
+	if forecast.DriftPerSecond > 0 && maxDepth > 0 {
+		forecast.Filling = true
+		if remaining := maxDepth - depth; remaining > 0 {
+			forecast.TimeToFull = time.Duration(float64(remaining) / forecast.DriftPerSecond * float64(time.Second))
+		}
+	}
+	return forecast
+}
@@ -0,0 +1,75 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastTimeToFull(t *testing.T) {
+	forecaster := NewForecaster(3)
+	forecaster.SetMaxDepth("QM1/APP.ORDERS", 5000)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	forecast := forecaster.Observe("QM1/APP.ORDERS", base, 1000, 600, 600)
+	assert.False(t, forecast.HasDrift, "one interval has no duration")
+
+	// 60 more messages put than got per minute
+	forecast = forecaster.Observe("QM1/APP.ORDERS", base.Add(time.Minute), 1060, 660, 600)
+	assert.True(t, forecast.HasDrift)
+	assert.InDelta(t, 1.0, forecast.DriftPerSecond, 0.0001)
+	assert.True(t, forecast.Filling)
+	assert.Equal(t, 3940*time.Second, forecast.TimeToFull)
+
+	// Drift averages over the window, dropping the oldest intervals
+	forecaster.Observe("QM1/APP.ORDERS", base.Add(2*time.Minute), 1180, 720, 600)
+	forecaster.Observe("QM1/APP.ORDERS", base.Add(3*time.Minute), 1180, 600, 600)
+	forecast = forecaster.Observe("QM1/APP.ORDERS", base.Add(4*time.Minute), 1180, 600, 600)
+	assert.InDelta(t, 120.0/180.0, forecast.DriftPerSecond, 0.0001)
+
+	// A full queue has no time left
+	forecast = forecaster.Observe("QM1/APP.ORDERS", base.Add(5*time.Minute), 6000, 6000, 0)
+	assert.True(t, forecast.Filling)
+	assert.Zero(t, forecast.TimeToFull)
+}
+
+func TestForecastDrainingAndUnknownMaxDepth(t *testing.T) {
+	forecaster := NewForecaster(5)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	forecaster.Observe("QM1/APP.INVOICES", base, 500, 0, 0)
+	forecast := forecaster.Observe("QM1/APP.INVOICES", base.Add(time.Minute), 500, 100, 0)
+	assert.Greater(t, forecast.DriftPerSecond, 0.0)
+	assert.False(t, forecast.Filling, "MAXDEPTH is unknown")
+
+	forecaster.SetMaxDepth("QM1/APP.INVOICES", 1000)
+	assert.Equal(t, int64(1000), forecaster.MaxDepth("QM1/APP.INVOICES"))
+	forecast = forecaster.Observe("QM1/APP.INVOICES", base.Add(2*time.Minute), 300, 0, 400)
+	assert.InDelta(t, -300.0/120.0, forecast.DriftPerSecond, 0.0001)
+	assert.False(t, forecast.Filling)
+}
+
+func TestForecastIgnoresRepeatedIntervals(t *testing.T) {
+	forecaster := NewForecaster(5)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	forecaster.Observe("q", base, 0, 0, 0)
+	forecaster.Observe("q", base.Add(time.Minute), 60, 60, 0)
+	forecast := forecaster.Observe("q", base.Add(time.Minute), 60, 60, 0)
+	assert.InDelta(t, 1.0, forecast.DriftPerSecond, 0.0001)
+	assert.Len(t, forecaster.samples["q"], 2)
+}
+
+func TestForecastResetQueueManager(t *testing.T) {
+	forecaster := NewForecaster(3)
+	forecaster.SetMaxDepth("QM1/APP.ORDERS", 5000)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	forecaster.Observe("QM1/APP.ORDERS", base, 1000, 600, 600)
+	forecaster.ResetQueueManager("QM1")
+
+	forecast := forecaster.Observe("QM1/APP.ORDERS", base.Add(time.Hour), 0, 0, 0)
+	assert.False(t, forecast.HasDrift)
+	assert.Equal(t, int64(5000), forecaster.MaxDepth("QM1/APP.ORDERS"))
+}
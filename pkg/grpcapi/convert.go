@@ -0,0 +1,135 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/grpcapi/ibmmqv1"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toRecords converts the records of a batch to their protobuf form
+func toRecords(batch *sinks.Batch) []*ibmmqv1.Record {
+	records := make([]*ibmmqv1.Record, 0, batch.Len())
+	for _, stats := range batch.Statistics {
+		records = append(records, &ibmmqv1.Record{
+			Record: &ibmmqv1.Record_Statistics{Statistics: toStatistics(batch, stats)},
+		})
+	}
+	for _, acct := range batch.Accounting {
+		records = append(records, &ibmmqv1.Record{
+			Record: &ibmmqv1.Record_Accounting{Accounting: toAccounting(batch, acct)},
+		})
+	}
+	return records
+}
+
+func toStatistics(batch *sinks.Batch, stats *pcf.StatisticsData) *ibmmqv1.StatisticsRecord {
+	record := &ibmmqv1.StatisticsRecord{
+		QueueManager: queueManager(batch, stats.QueueManager),
+		Timestamp:    timestamp(stats.Timestamp),
+	}
+
+	switch {
+	case stats.QueueStats != nil:
+		q := stats.QueueStats
+		record.Object = &ibmmqv1.StatisticsRecord_Queue{Queue: &ibmmqv1.QueueStatistics{
+			QueueName:    q.QueueName,
+			CurrentDepth: q.CurrentDepth,
+			HighDepth:    q.HighDepth,
+			InputCount:   q.InputCount,
+			OutputCount:  q.OutputCount,
+			EnqueueCount: q.EnqueueCount,
+			DequeueCount: q.DequeueCount,
+			HasReaders:   q.HasReaders,
+			HasWriters:   q.HasWriters,
+		}}
+	case stats.ChannelStats != nil:
+		ch := stats.ChannelStats
+		record.Object = &ibmmqv1.StatisticsRecord_Channel{Channel: &ibmmqv1.ChannelStatistics{
+			ChannelName:    ch.ChannelName,
+			ConnectionName: ch.ConnectionName,
+			Messages:       ch.Messages,
+			Bytes:          ch.Bytes,
+			Batches:        ch.Batches,
+		}}
+	case stats.MQIStats != nil:
+		mqi := stats.MQIStats
+		record.Object = &ibmmqv1.StatisticsRecord_Mqi{Mqi: &ibmmqv1.MQIStatistics{
+			ApplicationName: mqi.ApplicationName,
+			Opens:           mqi.Opens,
+			Closes:          mqi.Closes,
+			Puts:            mqi.Puts,
+			Gets:            mqi.Gets,
+			Commits:         mqi.Commits,
+			Backouts:        mqi.Backouts,
+		}}
+	}
+
+	return record
+}
+
+func toAccounting(batch *sinks.Batch, acct *pcf.AccountingData) *ibmmqv1.AccountingRecord {
+	record := &ibmmqv1.AccountingRecord{
+		QueueManager: queueManager(batch, acct.QueueManager),
+		Timestamp:    timestamp(acct.Timestamp),
+	}
+
+	if info := acct.ConnectionInfo; info != nil {
+		record.Connection = &ibmmqv1.ConnectionInfo{
+			ChannelName:     info.ChannelName,
+			ConnectionName:  info.ConnectionName,
+			ApplicationName: info.ApplicationName,
+			UserIdentifier:  info.UserIdentifier,
+			ConnectTime:     timestamp(info.ConnectTime),
+			DisconnectTime:  timestamp(info.DisconnectTime),
+		}
+	}
+
+	if ops := acct.Operations; ops != nil {
+		record.Operations = &ibmmqv1.OperationCounts{
+			Gets:     ops.Gets,
+			Puts:     ops.Puts,
+			Browses:  ops.Browses,
+			Opens:    ops.Opens,
+			Closes:   ops.Closes,
+			Commits:  ops.Commits,
+			Backouts: ops.Backouts,
+			PutBytes: ops.PutBytes,
+			GetBytes: ops.GetBytes,
+		}
+	}
+
+	return record
+}
+
+// objectName returns the queue, channel or application a statistics record describes
+func objectName(record *ibmmqv1.StatisticsRecord) string {
+	switch {
+	case record.GetQueue() != nil:
+		return record.GetQueue().GetQueueName()
+	case record.GetChannel() != nil:
+		return record.GetChannel().GetChannelName()
+	case record.GetMqi() != nil:
+		return record.GetMqi().GetApplicationName()
+	default:
+		return ""
+	}
+}
+
+// timestamp converts a time, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// queueManager returns the record's queue manager, or the batch's configured one
+func queueManager(batch *sinks.Batch, name string) string {
+	if name != "" {
+		return name
+	}
+	return batch.QueueManager
+}
@@ -0,0 +1,986 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: ibmmq/v1/records.proto
+
+package ibmmqv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SubscribeRequest selects the records to stream. An empty request streams everything.
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Record types to include; when both are false, both types are streamed.
+	Statistics bool `protobuf:"varint,1,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	Accounting bool `protobuf:"varint,2,opt,name=accounting,proto3" json:"accounting,omitempty"`
+	// Only statistics records for these queues, channels or applications.
+	Objects       []string `protobuf:"bytes,3,rep,name=objects,proto3" json:"objects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscribeRequest) GetStatistics() bool {
+	if x != nil {
+		return x.Statistics
+	}
+	return false
+}
+
+func (x *SubscribeRequest) GetAccounting() bool {
+	if x != nil {
+		return x.Accounting
+	}
+	return false
+}
+
+func (x *SubscribeRequest) GetObjects() []string {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+// Record is one parsed statistics or accounting record.
+type Record struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Record:
+	//
+	//	*Record_Statistics
+	//	*Record_Accounting
+	Record        isRecord_Record `protobuf_oneof:"record"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Record) GetRecord() isRecord_Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *Record) GetStatistics() *StatisticsRecord {
+	if x != nil {
+		if x, ok := x.Record.(*Record_Statistics); ok {
+			return x.Statistics
+		}
+	}
+	return nil
+}
+
+func (x *Record) GetAccounting() *AccountingRecord {
+	if x != nil {
+		if x, ok := x.Record.(*Record_Accounting); ok {
+			return x.Accounting
+		}
+	}
+	return nil
+}
+
+type isRecord_Record interface {
+	isRecord_Record()
+}
+
+type Record_Statistics struct {
+	Statistics *StatisticsRecord `protobuf:"bytes,1,opt,name=statistics,proto3,oneof"`
+}
+
+type Record_Accounting struct {
+	Accounting *AccountingRecord `protobuf:"bytes,2,opt,name=accounting,proto3,oneof"`
+}
+
+func (*Record_Statistics) isRecord_Record() {}
+
+func (*Record_Accounting) isRecord_Record() {}
+
+// StatisticsRecord mirrors pcf.StatisticsData.
+type StatisticsRecord struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	QueueManager string                 `protobuf:"bytes,1,opt,name=queue_manager,json=queueManager,proto3" json:"queue_manager,omitempty"`
+	Timestamp    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Types that are valid to be assigned to Object:
+	//
+	//	*StatisticsRecord_Queue
+	//	*StatisticsRecord_Channel
+	//	*StatisticsRecord_Mqi
+	Object        isStatisticsRecord_Object `protobuf_oneof:"object"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatisticsRecord) Reset() {
+	*x = StatisticsRecord{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatisticsRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatisticsRecord) ProtoMessage() {}
+
+func (x *StatisticsRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatisticsRecord.ProtoReflect.Descriptor instead.
+func (*StatisticsRecord) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StatisticsRecord) GetQueueManager() string {
+	if x != nil {
+		return x.QueueManager
+	}
+	return ""
+}
+
+func (x *StatisticsRecord) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *StatisticsRecord) GetObject() isStatisticsRecord_Object {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *StatisticsRecord) GetQueue() *QueueStatistics {
+	if x != nil {
+		if x, ok := x.Object.(*StatisticsRecord_Queue); ok {
+			return x.Queue
+		}
+	}
+	return nil
+}
+
+func (x *StatisticsRecord) GetChannel() *ChannelStatistics {
+	if x != nil {
+		if x, ok := x.Object.(*StatisticsRecord_Channel); ok {
+			return x.Channel
+		}
+	}
+	return nil
+}
+
+func (x *StatisticsRecord) GetMqi() *MQIStatistics {
+	if x != nil {
+		if x, ok := x.Object.(*StatisticsRecord_Mqi); ok {
+			return x.Mqi
+		}
+	}
+	return nil
+}
+
+type isStatisticsRecord_Object interface {
+	isStatisticsRecord_Object()
+}
+
+type StatisticsRecord_Queue struct {
+	Queue *QueueStatistics `protobuf:"bytes,3,opt,name=queue,proto3,oneof"`
+}
+
+type StatisticsRecord_Channel struct {
+	Channel *ChannelStatistics `protobuf:"bytes,4,opt,name=channel,proto3,oneof"`
+}
+
+type StatisticsRecord_Mqi struct {
+	Mqi *MQIStatistics `protobuf:"bytes,5,opt,name=mqi,proto3,oneof"`
+}
+
+func (*StatisticsRecord_Queue) isStatisticsRecord_Object() {}
+
+func (*StatisticsRecord_Channel) isStatisticsRecord_Object() {}
+
+func (*StatisticsRecord_Mqi) isStatisticsRecord_Object() {}
+
+type QueueStatistics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueName     string                 `protobuf:"bytes,1,opt,name=queue_name,json=queueName,proto3" json:"queue_name,omitempty"`
+	CurrentDepth  int64                  `protobuf:"varint,2,opt,name=current_depth,json=currentDepth,proto3" json:"current_depth,omitempty"`
+	HighDepth     int64                  `protobuf:"varint,3,opt,name=high_depth,json=highDepth,proto3" json:"high_depth,omitempty"`
+	InputCount    int64                  `protobuf:"varint,4,opt,name=input_count,json=inputCount,proto3" json:"input_count,omitempty"`
+	OutputCount   int64                  `protobuf:"varint,5,opt,name=output_count,json=outputCount,proto3" json:"output_count,omitempty"`
+	EnqueueCount  int64                  `protobuf:"varint,6,opt,name=enqueue_count,json=enqueueCount,proto3" json:"enqueue_count,omitempty"`
+	DequeueCount  int64                  `protobuf:"varint,7,opt,name=dequeue_count,json=dequeueCount,proto3" json:"dequeue_count,omitempty"`
+	HasReaders    bool                   `protobuf:"varint,8,opt,name=has_readers,json=hasReaders,proto3" json:"has_readers,omitempty"`
+	HasWriters    bool                   `protobuf:"varint,9,opt,name=has_writers,json=hasWriters,proto3" json:"has_writers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueStatistics) Reset() {
+	*x = QueueStatistics{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStatistics) ProtoMessage() {}
+
+func (x *QueueStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStatistics.ProtoReflect.Descriptor instead.
+func (*QueueStatistics) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QueueStatistics) GetQueueName() string {
+	if x != nil {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *QueueStatistics) GetCurrentDepth() int64 {
+	if x != nil {
+		return x.CurrentDepth
+	}
+	return 0
+}
+
+func (x *QueueStatistics) GetHighDepth() int64 {
+	if x != nil {
+		return x.HighDepth
+	}
+	return 0
+}
+
+func (x *QueueStatistics) GetInputCount() int64 {
+	if x != nil {
+		return x.InputCount
+	}
+	return 0
+}
+
+func (x *QueueStatistics) GetOutputCount() int64 {
+	if x != nil {
+		return x.OutputCount
+	}
+	return 0
+}
+
+func (x *QueueStatistics) GetEnqueueCount() int64 {
+	if x != nil {
+		return x.EnqueueCount
+	}
+	return 0
+}
+
+func (x *QueueStatistics) GetDequeueCount() int64 {
+	if x != nil {
+		return x.DequeueCount
+	}
+	return 0
+}
+
+func (x *QueueStatistics) GetHasReaders() bool {
+	if x != nil {
+		return x.HasReaders
+	}
+	return false
+}
+
+func (x *QueueStatistics) GetHasWriters() bool {
+	if x != nil {
+		return x.HasWriters
+	}
+	return false
+}
+
+type ChannelStatistics struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChannelName    string                 `protobuf:"bytes,1,opt,name=channel_name,json=channelName,proto3" json:"channel_name,omitempty"`
+	ConnectionName string                 `protobuf:"bytes,2,opt,name=connection_name,json=connectionName,proto3" json:"connection_name,omitempty"`
+	Messages       int64                  `protobuf:"varint,3,opt,name=messages,proto3" json:"messages,omitempty"`
+	Bytes          int64                  `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Batches        int64                  `protobuf:"varint,5,opt,name=batches,proto3" json:"batches,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ChannelStatistics) Reset() {
+	*x = ChannelStatistics{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelStatistics) ProtoMessage() {}
+
+func (x *ChannelStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelStatistics.ProtoReflect.Descriptor instead.
+func (*ChannelStatistics) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChannelStatistics) GetChannelName() string {
+	if x != nil {
+		return x.ChannelName
+	}
+	return ""
+}
+
+func (x *ChannelStatistics) GetConnectionName() string {
+	if x != nil {
+		return x.ConnectionName
+	}
+	return ""
+}
+
+func (x *ChannelStatistics) GetMessages() int64 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+func (x *ChannelStatistics) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *ChannelStatistics) GetBatches() int64 {
+	if x != nil {
+		return x.Batches
+	}
+	return 0
+}
+
+type MQIStatistics struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ApplicationName string                 `protobuf:"bytes,1,opt,name=application_name,json=applicationName,proto3" json:"application_name,omitempty"`
+	Opens           int64                  `protobuf:"varint,2,opt,name=opens,proto3" json:"opens,omitempty"`
+	Closes          int64                  `protobuf:"varint,3,opt,name=closes,proto3" json:"closes,omitempty"`
+	Puts            int64                  `protobuf:"varint,4,opt,name=puts,proto3" json:"puts,omitempty"`
+	Gets            int64                  `protobuf:"varint,5,opt,name=gets,proto3" json:"gets,omitempty"`
+	Commits         int64                  `protobuf:"varint,6,opt,name=commits,proto3" json:"commits,omitempty"`
+	Backouts        int64                  `protobuf:"varint,7,opt,name=backouts,proto3" json:"backouts,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MQIStatistics) Reset() {
+	*x = MQIStatistics{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MQIStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MQIStatistics) ProtoMessage() {}
+
+func (x *MQIStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MQIStatistics.ProtoReflect.Descriptor instead.
+func (*MQIStatistics) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MQIStatistics) GetApplicationName() string {
+	if x != nil {
+		return x.ApplicationName
+	}
+	return ""
+}
+
+func (x *MQIStatistics) GetOpens() int64 {
+	if x != nil {
+		return x.Opens
+	}
+	return 0
+}
+
+func (x *MQIStatistics) GetCloses() int64 {
+	if x != nil {
+		return x.Closes
+	}
+	return 0
+}
+
+func (x *MQIStatistics) GetPuts() int64 {
+	if x != nil {
+		return x.Puts
+	}
+	return 0
+}
+
+func (x *MQIStatistics) GetGets() int64 {
+	if x != nil {
+		return x.Gets
+	}
+	return 0
+}
+
+func (x *MQIStatistics) GetCommits() int64 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *MQIStatistics) GetBackouts() int64 {
+	if x != nil {
+		return x.Backouts
+	}
+	return 0
+}
+
+// AccountingRecord mirrors pcf.AccountingData.
+type AccountingRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueueManager  string                 `protobuf:"bytes,1,opt,name=queue_manager,json=queueManager,proto3" json:"queue_manager,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Connection    *ConnectionInfo        `protobuf:"bytes,3,opt,name=connection,proto3" json:"connection,omitempty"`
+	Operations    *OperationCounts       `protobuf:"bytes,4,opt,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountingRecord) Reset() {
+	*x = AccountingRecord{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountingRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountingRecord) ProtoMessage() {}
+
+func (x *AccountingRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountingRecord.ProtoReflect.Descriptor instead.
+func (*AccountingRecord) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AccountingRecord) GetQueueManager() string {
+	if x != nil {
+		return x.QueueManager
+	}
+	return ""
+}
+
+func (x *AccountingRecord) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AccountingRecord) GetConnection() *ConnectionInfo {
+	if x != nil {
+		return x.Connection
+	}
+	return nil
+}
+
+func (x *AccountingRecord) GetOperations() *OperationCounts {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type ConnectionInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ChannelName     string                 `protobuf:"bytes,1,opt,name=channel_name,json=channelName,proto3" json:"channel_name,omitempty"`
+	ConnectionName  string                 `protobuf:"bytes,2,opt,name=connection_name,json=connectionName,proto3" json:"connection_name,omitempty"`
+	ApplicationName string                 `protobuf:"bytes,3,opt,name=application_name,json=applicationName,proto3" json:"application_name,omitempty"`
+	UserIdentifier  string                 `protobuf:"bytes,4,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	ConnectTime     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=connect_time,json=connectTime,proto3" json:"connect_time,omitempty"`
+	DisconnectTime  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=disconnect_time,json=disconnectTime,proto3" json:"disconnect_time,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ConnectionInfo) Reset() {
+	*x = ConnectionInfo{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionInfo) ProtoMessage() {}
+
+func (x *ConnectionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionInfo.ProtoReflect.Descriptor instead.
+func (*ConnectionInfo) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ConnectionInfo) GetChannelName() string {
+	if x != nil {
+		return x.ChannelName
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetConnectionName() string {
+	if x != nil {
+		return x.ConnectionName
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetApplicationName() string {
+	if x != nil {
+		return x.ApplicationName
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *ConnectionInfo) GetConnectTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConnectTime
+	}
+	return nil
+}
+
+func (x *ConnectionInfo) GetDisconnectTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisconnectTime
+	}
+	return nil
+}
+
+type OperationCounts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gets          int64                  `protobuf:"varint,1,opt,name=gets,proto3" json:"gets,omitempty"`
+	Puts          int64                  `protobuf:"varint,2,opt,name=puts,proto3" json:"puts,omitempty"`
+	Browses       int64                  `protobuf:"varint,3,opt,name=browses,proto3" json:"browses,omitempty"`
+	Opens         int64                  `protobuf:"varint,4,opt,name=opens,proto3" json:"opens,omitempty"`
+	Closes        int64                  `protobuf:"varint,5,opt,name=closes,proto3" json:"closes,omitempty"`
+	Commits       int64                  `protobuf:"varint,6,opt,name=commits,proto3" json:"commits,omitempty"`
+	Backouts      int64                  `protobuf:"varint,7,opt,name=backouts,proto3" json:"backouts,omitempty"`
+	PutBytes      int64                  `protobuf:"varint,8,opt,name=put_bytes,json=putBytes,proto3" json:"put_bytes,omitempty"`
+	GetBytes      int64                  `protobuf:"varint,9,opt,name=get_bytes,json=getBytes,proto3" json:"get_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationCounts) Reset() {
+	*x = OperationCounts{}
+	mi := &file_ibmmq_v1_records_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationCounts) ProtoMessage() {}
+
+func (x *OperationCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_ibmmq_v1_records_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationCounts.ProtoReflect.Descriptor instead.
+func (*OperationCounts) Descriptor() ([]byte, []int) {
+	return file_ibmmq_v1_records_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OperationCounts) GetGets() int64 {
+	if x != nil {
+		return x.Gets
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetPuts() int64 {
+	if x != nil {
+		return x.Puts
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetBrowses() int64 {
+	if x != nil {
+		return x.Browses
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetOpens() int64 {
+	if x != nil {
+		return x.Opens
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetCloses() int64 {
+	if x != nil {
+		return x.Closes
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetCommits() int64 {
+	if x != nil {
+		return x.Commits
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetBackouts() int64 {
+	if x != nil {
+		return x.Backouts
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetPutBytes() int64 {
+	if x != nil {
+		return x.PutBytes
+	}
+	return 0
+}
+
+func (x *OperationCounts) GetGetBytes() int64 {
+	if x != nil {
+		return x.GetBytes
+	}
+	return 0
+}
+
+var File_ibmmq_v1_records_proto protoreflect.FileDescriptor
+
+const file_ibmmq_v1_records_proto_rawDesc = "" +
+	"\n" +
+	"\x16ibmmq/v1/records.proto\x12\bibmmq.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"l\n" +
+	"\x10SubscribeRequest\x12\x1e\n" +
+	"\n" +
+	"statistics\x18\x01 \x01(\bR\n" +
+	"statistics\x12\x1e\n" +
+	"\n" +
+	"accounting\x18\x02 \x01(\bR\n" +
+	"accounting\x12\x18\n" +
+	"\aobjects\x18\x03 \x03(\tR\aobjects\"\x8e\x01\n" +
+	"\x06Record\x12<\n" +
+	"\n" +
+	"statistics\x18\x01 \x01(\v2\x1a.ibmmq.v1.StatisticsRecordH\x00R\n" +
+	"statistics\x12<\n" +
+	"\n" +
+	"accounting\x18\x02 \x01(\v2\x1a.ibmmq.v1.AccountingRecordH\x00R\n" +
+	"accountingB\b\n" +
+	"\x06record\"\x94\x02\n" +
+	"\x10StatisticsRecord\x12#\n" +
+	"\rqueue_manager\x18\x01 \x01(\tR\fqueueManager\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x121\n" +
+	"\x05queue\x18\x03 \x01(\v2\x19.ibmmq.v1.QueueStatisticsH\x00R\x05queue\x127\n" +
+	"\achannel\x18\x04 \x01(\v2\x1b.ibmmq.v1.ChannelStatisticsH\x00R\achannel\x12+\n" +
+	"\x03mqi\x18\x05 \x01(\v2\x17.ibmmq.v1.MQIStatisticsH\x00R\x03mqiB\b\n" +
+	"\x06object\"\xc4\x02\n" +
+	"\x0fQueueStatistics\x12\x1d\n" +
+	"\n" +
+	"queue_name\x18\x01 \x01(\tR\tqueueName\x12#\n" +
+	"\rcurrent_depth\x18\x02 \x01(\x03R\fcurrentDepth\x12\x1d\n" +
+	"\n" +
+	"high_depth\x18\x03 \x01(\x03R\thighDepth\x12\x1f\n" +
+	"\vinput_count\x18\x04 \x01(\x03R\n" +
+	"inputCount\x12!\n" +
+	"\foutput_count\x18\x05 \x01(\x03R\voutputCount\x12#\n" +
+	"\renqueue_count\x18\x06 \x01(\x03R\fenqueueCount\x12#\n" +
+	"\rdequeue_count\x18\a \x01(\x03R\fdequeueCount\x12\x1f\n" +
+	"\vhas_readers\x18\b \x01(\bR\n" +
+	"hasReaders\x12\x1f\n" +
+	"\vhas_writers\x18\t \x01(\bR\n" +
+	"hasWriters\"\xab\x01\n" +
+	"\x11ChannelStatistics\x12!\n" +
+	"\fchannel_name\x18\x01 \x01(\tR\vchannelName\x12'\n" +
+	"\x0fconnection_name\x18\x02 \x01(\tR\x0econnectionName\x12\x1a\n" +
+	"\bmessages\x18\x03 \x01(\x03R\bmessages\x12\x14\n" +
+	"\x05bytes\x18\x04 \x01(\x03R\x05bytes\x12\x18\n" +
+	"\abatches\x18\x05 \x01(\x03R\abatches\"\xc6\x01\n" +
+	"\rMQIStatistics\x12)\n" +
+	"\x10application_name\x18\x01 \x01(\tR\x0fapplicationName\x12\x14\n" +
+	"\x05opens\x18\x02 \x01(\x03R\x05opens\x12\x16\n" +
+	"\x06closes\x18\x03 \x01(\x03R\x06closes\x12\x12\n" +
+	"\x04puts\x18\x04 \x01(\x03R\x04puts\x12\x12\n" +
+	"\x04gets\x18\x05 \x01(\x03R\x04gets\x12\x18\n" +
+	"\acommits\x18\x06 \x01(\x03R\acommits\x12\x1a\n" +
+	"\bbackouts\x18\a \x01(\x03R\bbackouts\"\xe6\x01\n" +
+	"\x10AccountingRecord\x12#\n" +
+	"\rqueue_manager\x18\x01 \x01(\tR\fqueueManager\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x128\n" +
+	"\n" +
+	"connection\x18\x03 \x01(\v2\x18.ibmmq.v1.ConnectionInfoR\n" +
+	"connection\x129\n" +
+	"\n" +
+	"operations\x18\x04 \x01(\v2\x19.ibmmq.v1.OperationCountsR\n" +
+	"operations\"\xb4\x02\n" +
+	"\x0eConnectionInfo\x12!\n" +
+	"\fchannel_name\x18\x01 \x01(\tR\vchannelName\x12'\n" +
+	"\x0fconnection_name\x18\x02 \x01(\tR\x0econnectionName\x12)\n" +
+	"\x10application_name\x18\x03 \x01(\tR\x0fapplicationName\x12'\n" +
+	"\x0fuser_identifier\x18\x04 \x01(\tR\x0euserIdentifier\x12=\n" +
+	"\fconnect_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vconnectTime\x12C\n" +
+	"\x0fdisconnect_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0edisconnectTime\"\xf1\x01\n" +
+	"\x0fOperationCounts\x12\x12\n" +
+	"\x04gets\x18\x01 \x01(\x03R\x04gets\x12\x12\n" +
+	"\x04puts\x18\x02 \x01(\x03R\x04puts\x12\x18\n" +
+	"\abrowses\x18\x03 \x01(\x03R\abrowses\x12\x14\n" +
+	"\x05opens\x18\x04 \x01(\x03R\x05opens\x12\x16\n" +
+	"\x06closes\x18\x05 \x01(\x03R\x06closes\x12\x18\n" +
+	"\acommits\x18\x06 \x01(\x03R\acommits\x12\x1a\n" +
+	"\bbackouts\x18\a \x01(\x03R\bbackouts\x12\x1b\n" +
+	"\tput_bytes\x18\b \x01(\x03R\bputBytes\x12\x1b\n" +
+	"\tget_bytes\x18\t \x01(\x03R\bgetBytes2L\n" +
+	"\rRecordService\x12;\n" +
+	"\tSubscribe\x12\x1a.ibmmq.v1.SubscribeRequest\x1a\x10.ibmmq.v1.Record0\x01BDZBgithub.com/atulksin/ibmmq-go-stat-otel/pkg/grpcapi/ibmmqv1;ibmmqv1b\x06proto3"
+
+var (
+	file_ibmmq_v1_records_proto_rawDescOnce sync.Once
+	file_ibmmq_v1_records_proto_rawDescData []byte
+)
+
+func file_ibmmq_v1_records_proto_rawDescGZIP() []byte {
+	file_ibmmq_v1_records_proto_rawDescOnce.Do(func() {
+		file_ibmmq_v1_records_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ibmmq_v1_records_proto_rawDesc), len(file_ibmmq_v1_records_proto_rawDesc)))
+	})
+	return file_ibmmq_v1_records_proto_rawDescData
+}
+
+var file_ibmmq_v1_records_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_ibmmq_v1_records_proto_goTypes = []any{
+	(*SubscribeRequest)(nil),      // 0: ibmmq.v1.SubscribeRequest
+	(*Record)(nil),                // 1: ibmmq.v1.Record
+	(*StatisticsRecord)(nil),      // 2: ibmmq.v1.StatisticsRecord
+	(*QueueStatistics)(nil),       // 3: ibmmq.v1.QueueStatistics
+	(*ChannelStatistics)(nil),     // 4: ibmmq.v1.ChannelStatistics
+	(*MQIStatistics)(nil),         // 5: ibmmq.v1.MQIStatistics
+	(*AccountingRecord)(nil),      // 6: ibmmq.v1.AccountingRecord
+	(*ConnectionInfo)(nil),        // 7: ibmmq.v1.ConnectionInfo
+	(*OperationCounts)(nil),       // 8: ibmmq.v1.OperationCounts
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_ibmmq_v1_records_proto_depIdxs = []int32{
+	2,  // 0: ibmmq.v1.Record.statistics:type_name -> ibmmq.v1.StatisticsRecord
+	6,  // 1: ibmmq.v1.Record.accounting:type_name -> ibmmq.v1.AccountingRecord
+	9,  // 2: ibmmq.v1.StatisticsRecord.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 3: ibmmq.v1.StatisticsRecord.queue:type_name -> ibmmq.v1.QueueStatistics
+	4,  // 4: ibmmq.v1.StatisticsRecord.channel:type_name -> ibmmq.v1.ChannelStatistics
+	5,  // 5: ibmmq.v1.StatisticsRecord.mqi:type_name -> ibmmq.v1.MQIStatistics
+	9,  // 6: ibmmq.v1.AccountingRecord.timestamp:type_name -> google.protobuf.Timestamp
+	7,  // 7: ibmmq.v1.AccountingRecord.connection:type_name -> ibmmq.v1.ConnectionInfo
+	8,  // 8: ibmmq.v1.AccountingRecord.operations:type_name -> ibmmq.v1.OperationCounts
+	9,  // 9: ibmmq.v1.ConnectionInfo.connect_time:type_name -> google.protobuf.Timestamp
+	9,  // 10: ibmmq.v1.ConnectionInfo.disconnect_time:type_name -> google.protobuf.Timestamp
+	0,  // 11: ibmmq.v1.RecordService.Subscribe:input_type -> ibmmq.v1.SubscribeRequest
+	1,  // 12: ibmmq.v1.RecordService.Subscribe:output_type -> ibmmq.v1.Record
+	12, // [12:13] is the sub-list for method output_type
+	11, // [11:12] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_ibmmq_v1_records_proto_init() }
+func file_ibmmq_v1_records_proto_init() {
+	if File_ibmmq_v1_records_proto != nil {
+		return
+	}
+	file_ibmmq_v1_records_proto_msgTypes[1].OneofWrappers = []any{
+		(*Record_Statistics)(nil),
+		(*Record_Accounting)(nil),
+	}
+	file_ibmmq_v1_records_proto_msgTypes[2].OneofWrappers = []any{
+		(*StatisticsRecord_Queue)(nil),
+		(*StatisticsRecord_Channel)(nil),
+		(*StatisticsRecord_Mqi)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ibmmq_v1_records_proto_rawDesc), len(file_ibmmq_v1_records_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ibmmq_v1_records_proto_goTypes,
+		DependencyIndexes: file_ibmmq_v1_records_proto_depIdxs,
+		MessageInfos:      file_ibmmq_v1_records_proto_msgTypes,
+	}.Build()
+	File_ibmmq_v1_records_proto = out.File
+	file_ibmmq_v1_records_proto_goTypes = nil
+	file_ibmmq_v1_records_proto_depIdxs = nil
+}
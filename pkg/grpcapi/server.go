@@ -0,0 +1,181 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/grpcapi/ibmmqv1"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server streams parsed records to gRPC subscribers. It implements sinks.Sink: each
+// collection cycle is converted once and queued to every subscriber. gRPC flow control
+// pushes back on the server when a client reads slowly; a subscriber whose queue fills up
+// is disconnected rather than stalling collection or silently losing records.
+type Server struct {
+	ibmmqv1.UnimplementedRecordServiceServer
+
+	config   *config.GRPCConfig
+	logger   *logrus.Logger
+	server   *grpc.Server
+	listener net.Listener
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber is one Subscribe call and its queue of pending records
+type subscriber struct {
+	request  *ibmmqv1.SubscribeRequest
+	objects  map[string]bool
+	records  chan *ibmmqv1.Record
+	overflow chan struct{}
+	dropped  bool
+}
+
+// NewServer creates a gRPC record server; Start begins listening
+func NewServer(cfg *config.GRPCConfig, logger *logrus.Logger) *Server {
+	s := &Server{
+		config:      cfg,
+		logger:      logger,
+		server:      grpc.NewServer(),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+	ibmmqv1.RegisterRecordServiceServer(s.server, s)
+	return s
+}
+
+// Start listens on the configured address and serves in the background
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC on %s: %w", s.config.Address, err)
+	}
+	s.listener = listener
+
+	s.logger.WithField("address", listener.Addr().String()).Info("Starting gRPC record server")
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			s.logger.WithError(err).Error("gRPC server failed")
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server listens on, once started
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Name identifies the server in sink logs
+func (s *Server) Name() string {
+	return "grpc"
+}
+
+// Write queues the records of a collection cycle to every subscriber
+func (s *Server) Write(ctx context.Context, batch *sinks.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.subscribers) == 0 {
+		return nil
+	}
+
+	records := toRecords(batch)
+	for sub := range s.subscribers {
+		sub.queue(records)
+	}
+	return nil
+}
+
+// Close stops the server, ending all subscriptions
+func (s *Server) Close() error {
+	s.server.Stop()
+	return nil
+}
+
+// Subscribe streams records matching the request until the client cancels, the server
+// stops or the subscriber falls too far behind
+func (s *Server) Subscribe(req *ibmmqv1.SubscribeRequest, stream grpc.ServerStreamingServer[ibmmqv1.Record]) error {
+	sub := &subscriber{
+		request:  req,
+		objects:  make(map[string]bool),
+		records:  make(chan *ibmmqv1.Record, s.config.BufferSize),
+		overflow: make(chan struct{}),
+	}
+	for _, object := range req.GetObjects() {
+		sub.objects[object] = true
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	count := len(s.subscribers)
+	s.mu.Unlock()
+
+	s.logger.WithField("subscribers", count).Debug("gRPC subscriber connected")
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case record := <-sub.records:
+			if err := stream.Send(record); err != nil {
+				return err
+			}
+		case <-sub.overflow:
+			s.logger.WithField("buffer_size", s.config.BufferSize).Warn("Disconnecting slow gRPC subscriber")
+			return status.Errorf(codes.ResourceExhausted,
+				"subscriber fell more than %d records behind; resubscribe to continue", s.config.BufferSize)
+		}
+	}
+}
+
+// matches reports whether the subscriber asked for a record
+func (sub *subscriber) matches(record *ibmmqv1.Record) bool {
+	req := sub.request
+	all := !req.GetStatistics() && !req.GetAccounting()
+
+	if stats := record.GetStatistics(); stats != nil {
+		if !all && !req.GetStatistics() {
+			return false
+		}
+		return len(sub.objects) == 0 || sub.objects[objectName(stats)]
+	}
+	return all || req.GetAccounting()
+}
+
+// queue adds the matching records to the subscriber's queue without blocking, marking
+// the subscriber as overflowed when it is full; called with the server lock held
+func (sub *subscriber) queue(records []*ibmmqv1.Record) {
+	for _, record := range records {
+		if sub.dropped {
+			return
+		}
+		if !sub.matches(record) {
+			continue
+		}
+		select {
+		case sub.records <- record:
+		default:
+			sub.dropped = true
+			close(sub.overflow)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/grpcapi/ibmmqv1"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func testBatch() *sinks.Batch {
+	at := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	return &sinks.Batch{
+		QueueManager: "QM1",
+		Statistics: []*pcf.StatisticsData{
+			{Timestamp: at, QueueStats: &pcf.QueueStatistics{QueueName: "APP.ORDERS", CurrentDepth: 4}},
+			{Timestamp: at, QueueStats: &pcf.QueueStatistics{QueueName: "APP.INVOICES", CurrentDepth: 1}},
+			{Timestamp: at, ChannelStats: &pcf.ChannelStatistics{ChannelName: "TO.QM2", Messages: 8}},
+		},
+		Accounting: []*pcf.AccountingData{
+			{Timestamp: at, ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "orders", UserIdentifier: "app1"},
+				Operations: &pcf.OperationCounts{Puts: 3}},
+		},
+	}
+}
+
+func startServer(t *testing.T, bufferSize int) (*Server, ibmmqv1.RecordServiceClient) {
+	server := NewServer(&config.GRPCConfig{Address: "127.0.0.1:0", BufferSize: bufferSize}, logrus.New())
+	require.NoError(t, server.Start(context.Background()))
+	t.Cleanup(func() { server.Close() })
+
+	conn, err := grpc.NewClient(server.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return server, ibmmqv1.NewRecordServiceClient(conn)
+}
+
+// subscribe opens a stream and waits until the server has registered it
+func subscribe(t *testing.T, server *Server, client ibmmqv1.RecordServiceClient, req *ibmmqv1.SubscribeRequest) ibmmqv1.RecordService_SubscribeClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	stream, err := client.Subscribe(ctx, req)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return len(server.subscribers) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	return stream
+}
+
+func TestSubscribeStreamsAllRecords(t *testing.T) {
+	server, client := startServer(t, 16)
+	stream := subscribe(t, server, client, &ibmmqv1.SubscribeRequest{})
+
+	require.NoError(t, server.Write(context.Background(), testBatch()))
+
+	var records []*ibmmqv1.Record
+	for i := 0; i < 4; i++ {
+		record, err := stream.Recv()
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+
+	queue := records[0].GetStatistics()
+	require.NotNil(t, queue)
+	assert.Equal(t, "QM1", queue.GetQueueManager())
+	assert.Equal(t, "APP.ORDERS", queue.GetQueue().GetQueueName())
+	assert.Equal(t, int64(4), queue.GetQueue().GetCurrentDepth())
+	assert.Equal(t, int64(1773482400), queue.GetTimestamp().GetSeconds())
+
+	assert.Equal(t, "TO.QM2", records[2].GetStatistics().GetChannel().GetChannelName())
+
+	acct := records[3].GetAccounting()
+	require.NotNil(t, acct)
+	assert.Equal(t, "orders", acct.GetConnection().GetApplicationName())
+	assert.Equal(t, int64(3), acct.GetOperations().GetPuts())
+	assert.Nil(t, acct.GetConnection().GetConnectTime())
+}
+
+func TestSubscribeFilters(t *testing.T) {
+	server, client := startServer(t, 16)
+	stream := subscribe(t, server, client, &ibmmqv1.SubscribeRequest{
+		Statistics: true,
+		Objects:    []string{"APP.INVOICES", "TO.QM2"},
+	})
+
+	require.NoError(t, server.Write(context.Background(), testBatch()))
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "APP.INVOICES", first.GetStatistics().GetQueue().GetQueueName())
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "TO.QM2", second.GetStatistics().GetChannel().GetChannelName())
+
+	// APP.ORDERS and the accounting record were skipped, so the next record is from the second cycle
+	require.NoError(t, server.Write(context.Background(), testBatch()))
+	third, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "APP.INVOICES", third.GetStatistics().GetQueue().GetQueueName())
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	server, client := startServer(t, 2)
+	stream := subscribe(t, server, client, &ibmmqv1.SubscribeRequest{})
+
+	// The client does not read, so once gRPC flow control stops the sends the queue overflows
+	records := toRecords(testBatch())
+	server.mu.Lock()
+	for sub := range server.subscribers {
+		for !sub.dropped {
+			sub.queue(records)
+		}
+	}
+	server.mu.Unlock()
+
+	var err error
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestQueueMarksOverflow(t *testing.T) {
+	sub := &subscriber{
+		request:  &ibmmqv1.SubscribeRequest{Accounting: true},
+		records:  make(chan *ibmmqv1.Record, 1),
+		overflow: make(chan struct{}),
+	}
+
+	sub.queue(toRecords(testBatch()))
+	assert.False(t, sub.dropped, "statistics records should not count against an accounting subscriber")
+	assert.Len(t, sub.records, 1)
+
+	sub.queue(toRecords(testBatch()))
+	assert.True(t, sub.dropped)
+	select {
+	case <-sub.overflow:
+	default:
+		t.Fatal("overflow channel not closed")
+	}
+}
+
+func TestWriteWithoutSubscribers(t *testing.T) {
+	server := NewServer(&config.GRPCConfig{Address: "127.0.0.1:0", BufferSize: 1}, logrus.New())
+	assert.NoError(t, server.Write(context.Background(), testBatch()))
+	assert.Equal(t, "grpc", server.Name())
+}
@@ -0,0 +1,89 @@
+package idle
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the idle state of a queue after its latest statistics interval
+type Status struct {
+	// Idle is true once the queue had no gets and no readers for the configured
+	// number of consecutive intervals
+	Idle bool
+	// IdleFor is how long the queue has had no gets and no readers, 0 while active
+	IdleFor time.Duration
+	// Intervals is the number of consecutive idle intervals
+	Intervals int
+}
+
+// queueState is the current idle streak of a queue
+type queueState struct {
+	intervals int
+	since     time.Time // end of the last active interval
+	last      time.Time // end of the latest interval
+}
+
+// Tracker finds queues that nobody reads: no messages got and no input handles open
+// for a number of consecutive statistics intervals
+type Tracker struct {
+	intervals int
+
+	mu     sync.Mutex
+	queues map[string]*queueState
+}
+
+// NewTracker creates a tracker flagging queues idle after the given number of intervals
+func NewTracker(intervals int) *Tracker {
+	if intervals < 1 {
+		intervals = 1
+	}
+	return &Tracker{intervals: intervals, queues: make(map[string]*queueState)}
+}
+
+// Observe adds a statistics interval of a queue ending at the given time
+func (t *Tracker) Observe(key string, at time.Time, gets int64, hasReaders bool) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.queues[key]
+	if state == nil {
+		// The idle time of a queue first seen idle is counted from this interval
+		state = &queueState{since: at}
+		t.queues[key] = state
+	}
+
+	if gets > 0 || hasReaders {
+		state.intervals = 0
+		state.since = at
+		state.last = at
+		return Status{}
+	}
+
+	if state.intervals == 0 && !state.last.IsZero() {
+		state.since = state.last
+	}
+	state.intervals++
+	if at.After(state.last) {
+		state.last = at
+	}
+
+	return Status{
+		Idle:      state.intervals >= t.intervals,
+		IdleFor:   state.last.Sub(state.since),
+		Intervals: state.intervals,
+	}
+}
+
+// ResetQueueManager drops the idle streaks of the queues of a queue manager, such as after it
+// restarted
+func (t *Tracker) ResetQueueManager(qmgr string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prefix := qmgr + "/"
+	maps.DeleteFunc(t.queues, func(key string, _ *queueState) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
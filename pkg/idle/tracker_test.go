@@ -0,0 +1,61 @@
+package idle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerFlagsIdleQueues(t *testing.T) {
+	tracker := NewTracker(3)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	status := tracker.Observe("QM1/OLD.QUEUE", base, 5, false)
+	assert.False(t, status.Idle)
+	assert.Zero(t, status.IdleFor)
+
+	for i := 1; i <= 2; i++ {
+		status = tracker.Observe("QM1/OLD.QUEUE", base.Add(time.Duration(i)*time.Hour), 0, false)
+		assert.False(t, status.Idle, "interval %d", i)
+	}
+	assert.Equal(t, 2*time.Hour, status.IdleFor)
+
+	status = tracker.Observe("QM1/OLD.QUEUE", base.Add(3*time.Hour), 0, false)
+	assert.True(t, status.Idle)
+	assert.Equal(t, 3, status.Intervals)
+	assert.Equal(t, 3*time.Hour, status.IdleFor)
+
+	// A reader opening the queue ends the streak
+	status = tracker.Observe("QM1/OLD.QUEUE", base.Add(4*time.Hour), 0, true)
+	assert.False(t, status.Idle)
+	assert.Zero(t, status.IdleFor)
+
+	status = tracker.Observe("QM1/OLD.QUEUE", base.Add(5*time.Hour), 0, false)
+	assert.Equal(t, 1, status.Intervals)
+	assert.Equal(t, time.Hour, status.IdleFor)
+}
+
+func TestTrackerQueueFirstSeenIdle(t *testing.T) {
+	tracker := NewTracker(1)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	status := tracker.Observe("q", base, 0, false)
+	assert.True(t, status.Idle)
+	assert.Zero(t, status.IdleFor)
+
+	status = tracker.Observe("q", base.Add(time.Hour), 0, false)
+	assert.Equal(t, time.Hour, status.IdleFor)
+}
+
+func TestTrackerResetQueueManager(t *testing.T) {
+	tracker := NewTracker(2)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tracker.Observe("QM1/OLD.QUEUE", base, 0, false)
+	tracker.ResetQueueManager("QM1")
+
+	status := tracker.Observe("QM1/OLD.QUEUE", base.Add(time.Hour), 0, false)
+	assert.Equal(t, 1, status.Intervals, "the streak starts over")
+	assert.False(t, status.Idle)
+}
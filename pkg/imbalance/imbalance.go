@@ -0,0 +1,108 @@
+package imbalance
+
+import (
+	"maps"
+	"strings"
+	"sync"
+)
+
+// Result is the enqueue/dequeue balance of a queue after its latest statistics interval
+type Result struct {
+	// Ratio is the number of messages enqueued per message dequeued over the window; a
+	// window without dequeues counts as one dequeue, so the ratio stays finite
+	Ratio float64
+	// Intervals is the number of consecutive intervals the ratio exceeded the threshold
+	Intervals int
+	// Sustained is true once the ratio exceeded the threshold for the configured number
+	// of consecutive intervals
+	Sustained bool
+}
+
+// counts is one statistics interval of a queue
+type counts struct {
+	enqueued int64
+	dequeued int64
+}
+
+// queueState is the window and current imbalance streak of a queue
+type queueState struct {
+	window    []counts
+	intervals int
+}
+
+// Tracker compares per queue the messages enqueued with those dequeued over a window of
+// statistics intervals, flagging queues whose producers keep outpacing their consumers
+// before the backlog shows in the depth
+type Tracker struct {
+	window    int
+	threshold float64
+	sustain   int
+
+	mu     sync.Mutex
+	queues map[string]*queueState
+}
+
+// NewTracker creates a tracker computing the ratio over window intervals and flagging
+// queues whose ratio exceeded threshold for sustain consecutive intervals
+func NewTracker(window int, threshold float64, sustain int) *Tracker {
+	if window < 1 {
+		window = 1
+	}
+	if sustain < 1 {
+		sustain = 1
+	}
+	return &Tracker{
+		window:    window,
+		threshold: threshold,
+		sustain:   sustain,
+		queues:    make(map[string]*queueState),
+	}
+}
+
+// Observe adds a statistics interval of a queue and returns its balance
+func (t *Tracker) Observe(key string, enqueued, dequeued int64) Result {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.queues[key]
+	if state == nil {
+		state = &queueState{}
+		t.queues[key] = state
+	}
+
+	state.window = append(state.window, counts{enqueued: enqueued, dequeued: dequeued})
+	if len(state.window) > t.window {
+		state.window = state.window[len(state.window)-t.window:]
+	}
+
+	var enq, deq int64
+	for _, c := range state.window {
+		enq += c.enqueued
+		deq += c.dequeued
+	}
+	ratio := float64(enq) / float64(max(deq, 1))
+
+	if ratio > t.threshold {
+		state.intervals++
+	} else {
+		state.intervals = 0
+	}
+
+	return Result{
+		Ratio:     ratio,
+		Intervals: state.intervals,
+		Sustained: state.intervals >= t.sustain,
+	}
+}
+
+// ResetQueueManager drops the windows of the queues of a queue manager, such as after it
+// restarted
+func (t *Tracker) ResetQueueManager(qmgr string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prefix := qmgr + "/"
+	maps.DeleteFunc(t.queues, func(key string, _ *queueState) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
@@ -0,0 +1,64 @@
+package imbalance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerRatioOverWindow(t *testing.T) {
+	tracker := NewTracker(2, 1.5, 1)
+
+	result := tracker.Observe("QM1/ORDERS", 100, 100)
+	assert.Equal(t, 1.0, result.Ratio)
+	assert.False(t, result.Sustained)
+
+	result = tracker.Observe("QM1/ORDERS", 200, 50)
+	assert.Equal(t, 2.0, result.Ratio, "300 enqueued for 150 dequeued")
+	assert.True(t, result.Sustained)
+
+	// The first interval has left the window
+	result = tracker.Observe("QM1/ORDERS", 0, 150)
+	assert.Equal(t, 1.0, result.Ratio)
+	assert.False(t, result.Sustained)
+}
+
+func TestTrackerSustainedImbalance(t *testing.T) {
+	tracker := NewTracker(1, 1.2, 3)
+
+	for i := 1; i <= 2; i++ {
+		result := tracker.Observe("q", 30, 20)
+		assert.Equal(t, i, result.Intervals)
+		assert.False(t, result.Sustained, "interval %d", i)
+	}
+	result := tracker.Observe("q", 30, 20)
+	assert.True(t, result.Sustained)
+
+	// A balanced interval ends the streak
+	result = tracker.Observe("q", 20, 20)
+	assert.Zero(t, result.Intervals)
+	assert.False(t, result.Sustained)
+}
+
+func TestTrackerWithoutDequeues(t *testing.T) {
+	tracker := NewTracker(5, 1.5, 1)
+
+	result := tracker.Observe("q", 0, 0)
+	assert.Zero(t, result.Ratio)
+	assert.False(t, result.Sustained)
+
+	result = tracker.Observe("q", 12, 0)
+	assert.Equal(t, 12.0, result.Ratio)
+	assert.True(t, result.Sustained)
+}
+
+func TestTrackerResetQueueManager(t *testing.T) {
+	tracker := NewTracker(2, 1.5, 1)
+
+	tracker.Observe("QM1/ORDERS", 300, 0)
+	tracker.ResetQueueManager("QM1")
+
+	result := tracker.Observe("QM1/ORDERS", 100, 100)
+	assert.Equal(t, 1.0, result.Ratio)
+	assert.False(t, result.Sustained)
+}
@@ -0,0 +1,129 @@
+package intervals
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Sources of intervals
+const (
+	Statistics = "statistics"
+	Accounting = "accounting"
+)
+
+// Result is the effect of a record on the intervals of its queue manager
+type Result struct {
+	// New is true when the record starts an interval not seen before
+	New bool
+	// Missed is the number of intervals that never arrived before this one
+	Missed int
+	// Last is the end of the latest interval processed
+	Last time.Time
+}
+
+// key identifies the intervals of one source of a queue manager
+type key struct {
+	queueManager string
+	source       string
+}
+
+// stream is the cadence of one source of a queue manager
+type stream struct {
+	interval time.Duration // expected spacing, 0 until set or learned
+	set      bool          // interval was set rather than learned
+	last     time.Time
+}
+
+// Tracker follows the cadence at which queue managers write statistics and accounting
+// intervals (STATINT and ACCTINT) and counts the intervals that never arrived, whether
+// the collector was down past their expiry or the queue manager did not write them.
+// Records of the same interval share its end time; a record more than half an interval
+// after the latest one starts the next interval. An interval that was not set is learned
+// as the smallest spacing seen.
+type Tracker struct {
+	defaults map[string]time.Duration
+
+	mu      sync.Mutex
+	streams map[key]*stream
+}
+
+// NewTracker creates a tracker expecting the given statistics and accounting intervals of
+// every queue manager; an interval that is not positive is inquired or learned
+func NewTracker(statistics, accounting time.Duration) *Tracker {
+	return &Tracker{
+		defaults: map[string]time.Duration{Statistics: statistics, Accounting: accounting},
+		streams:  make(map[key]*stream),
+	}
+}
+
+// SetInterval sets the expected interval of a source of a queue manager, such as its
+// STATINT. It does not override an interval given to NewTracker.
+func (t *Tracker) SetInterval(qmgr, source string, interval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if interval <= 0 || t.defaults[source] > 0 {
+		return
+	}
+	s := t.stream(qmgr, source)
+	s.interval = interval
+	s.set = true
+}
+
+// Observe adds a record of a source of a queue manager for the interval ending at the
+// given time. Records older than the latest interval, such as accounting records written
+// when an application disconnects, do not change the cadence.
+func (t *Tracker) Observe(qmgr, source string, at time.Time) Result {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.stream(qmgr, source)
+	if s.last.IsZero() {
+		s.last = at
+		return Result{New: true, Last: at}
+	}
+
+	gap := at.Sub(s.last)
+	if gap <= s.interval/2 || gap < time.Second {
+		return Result{Last: s.last}
+	}
+
+	result := Result{New: true, Last: at}
+	if s.interval > 0 {
+		if n := int(math.Round(float64(gap)/float64(s.interval))) - 1; n > 0 {
+			result.Missed = n
+		}
+	}
+	if !s.set && (s.interval == 0 || gap < s.interval) {
+		s.interval = gap
+	}
+	s.last = at
+	return result
+}
+
+// ResetQueueManager starts the intervals of a queue manager over, such as after it
+// restarted, so the intervals lost while it was down are not counted as missed. The
+// expected intervals are kept.
+func (t *Tracker) ResetQueueManager(qmgr string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k, s := range t.streams {
+		if k.queueManager == qmgr {
+			s.last = time.Time{}
+		}
+	}
+}
+
+// stream returns the state of a source of a queue manager, creating it on first use
+func (t *Tracker) stream(qmgr, source string) *stream {
+	k := key{queueManager: qmgr, source: source}
+	s := t.streams[k]
+	if s == nil {
+		s = &stream{interval: t.defaults[source]}
+		s.set = s.interval > 0
+		t.streams[k] = s
+	}
+	return s
+}
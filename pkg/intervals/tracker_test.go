@@ -0,0 +1,78 @@
+package intervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerCountsMissedIntervals(t *testing.T) {
+	tracker := NewTracker(10*time.Minute, 0)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	result := tracker.Observe("QM1", Statistics, base)
+	assert.True(t, result.New)
+	assert.Zero(t, result.Missed)
+
+	// Records of the same interval, a few milliseconds apart
+	result = tracker.Observe("QM1", Statistics, base.Add(20*time.Millisecond))
+	assert.False(t, result.New)
+	assert.Equal(t, base, result.Last)
+
+	result = tracker.Observe("QM1", Statistics, base.Add(10*time.Minute))
+	assert.True(t, result.New)
+	assert.Zero(t, result.Missed)
+
+	// Three intervals never arrived
+	result = tracker.Observe("QM1", Statistics, base.Add(50*time.Minute+3*time.Second))
+	assert.True(t, result.New)
+	assert.Equal(t, 3, result.Missed)
+	assert.Equal(t, base.Add(50*time.Minute+3*time.Second), result.Last)
+
+	// Older records do not move the cadence back
+	result = tracker.Observe("QM1", Statistics, base.Add(5*time.Minute))
+	assert.False(t, result.New)
+	assert.Zero(t, result.Missed)
+
+	// Queue managers are tracked apart
+	assert.True(t, tracker.Observe("QM2", Statistics, base).New)
+}
+
+func TestTrackerLearnsInterval(t *testing.T) {
+	tracker := NewTracker(0, 0)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tracker.Observe("QM1", Accounting, base)
+	assert.Zero(t, tracker.Observe("QM1", Accounting, base.Add(30*time.Minute)).Missed, "nothing to compare the first spacing with")
+	assert.Zero(t, tracker.Observe("QM1", Accounting, base.Add(60*time.Minute)).Missed)
+	assert.Equal(t, 1, tracker.Observe("QM1", Accounting, base.Add(120*time.Minute)).Missed)
+}
+
+func TestTrackerSetInterval(t *testing.T) {
+	tracker := NewTracker(0, time.Hour)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tracker.SetInterval("QM1", Statistics, 15*time.Minute)
+	tracker.Observe("QM1", Statistics, base)
+	assert.Equal(t, 3, tracker.Observe("QM1", Statistics, base.Add(time.Hour)).Missed)
+
+	// A configured interval wins over the queue manager's
+	tracker.SetInterval("QM1", Accounting, 15*time.Minute)
+	tracker.Observe("QM1", Accounting, base)
+	assert.Zero(t, tracker.Observe("QM1", Accounting, base.Add(time.Hour)).Missed)
+}
+
+func TestTrackerResetQueueManager(t *testing.T) {
+	tracker := NewTracker(10*time.Minute, 0)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tracker.Observe("QM1", Statistics, base)
+	tracker.Observe("QM2", Statistics, base)
+	tracker.ResetQueueManager("QM1")
+
+	result := tracker.Observe("QM1", Statistics, base.Add(time.Hour))
+	assert.True(t, result.New)
+	assert.Zero(t, result.Missed, "the outage of a restart is not counted")
+	assert.Equal(t, 5, tracker.Observe("QM2", Statistics, base.Add(time.Hour)).Missed)
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// CycleIDKey is the field holding the ID of the collection cycle an entry was logged in
+const CycleIDKey = "cycle_id"
+
+// Cycle holds the ID of the collection cycle in progress, so the log entries, metric
+// exemplars and summary of one cycle can be correlated. The zero value has no cycle in
+// progress.
+type Cycle struct {
+	id atomic.Pointer[string]
+}
+
+// Start begins a cycle with a new ID and returns the ID
+func (c *Cycle) Start() string {
+	id := NewCycleID()
+	c.id.Store(&id)
+	return id
+}
+
+// End ends the cycle in progress
+func (c *Cycle) End() {
+	c.id.Store(nil)
+}
+
+// ID returns the ID of the cycle in progress, or "" between cycles
+func (c *Cycle) ID() string {
+	if c == nil {
+		return ""
+	}
+	if id := c.id.Load(); id != nil {
+		return *id
+	}
+	return ""
+}
+
+// NewCycleID returns a random ID of 16 hex digits
+func NewCycleID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// cycleLogger adds the ID of the cycle in progress to the entries of a Logger
+type cycleLogger struct {
+	logger Logger
+	cycle  *Cycle
+}
+
+// WithCycle returns a Logger adding the ID of the cycle in progress, if any, to every
+// entry as CycleIDKey. The ID is read as each entry is logged, so loggers derived
+// before a cycle starts log it too.
+func WithCycle(logger Logger, cycle *Cycle) Logger {
+	return cycleLogger{logger: logger, cycle: cycle}
+}
+
+func (l cycleLogger) WithField(key string, value interface{}) Logger {
+	return cycleLogger{logger: l.logger.WithField(key, value), cycle: l.cycle}
+}
+
+func (l cycleLogger) WithFields(fields Fields) Logger {
+	return cycleLogger{logger: l.logger.WithFields(fields), cycle: l.cycle}
+}
+
+func (l cycleLogger) WithError(err error) Logger {
+	return cycleLogger{logger: l.logger.WithError(err), cycle: l.cycle}
+}
+
+func (l cycleLogger) Debug(msg string) { l.current().Debug(msg) }
+func (l cycleLogger) Info(msg string)  { l.current().Info(msg) }
+func (l cycleLogger) Warn(msg string)  { l.current().Warn(msg) }
+func (l cycleLogger) Error(msg string) { l.current().Error(msg) }
+
+// current returns the logger with the ID of the cycle in progress
+func (l cycleLogger) current() Logger {
+	if id := l.cycle.ID(); id != "" {
+		return l.logger.WithField(CycleIDKey, id)
+	}
+	return l.logger
+}
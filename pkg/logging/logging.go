@@ -0,0 +1,21 @@
+package logging
+
+// Fields are the structured fields of a log entry
+type Fields map[string]interface{}
+
+// Logger is the structured logger of the collector packages. A Logger with fields is
+// derived with WithField, WithFields and WithError, and an entry is logged at its level
+// with Debug, Info, Warn or Error. Adapters for logrus, log/slog and zap let programs
+// embedding the packages log through the library they already use.
+type Logger interface {
+	WithField(key string, value interface{}) Logger
+	WithFields(fields Fields) Logger
+	WithError(err error) Logger
+	Debug(msg string)
+	Info(msg string)
+	Warn(msg string)
+	Error(msg string)
+}
+
+// ErrorKey is the field WithError sets, as in logrus
+const ErrorKey = "error"
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decode returns the JSON log lines written to out
+func decode(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(out)
+	for decoder.More() {
+		var entry map[string]interface{}
+		require.NoError(t, decoder.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogrusAdapter(t *testing.T) {
+	var out bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&out)
+	base.SetFormatter(&logrus.JSONFormatter{})
+	base.SetLevel(logrus.InfoLevel)
+
+	logger := NewLogrus(base)
+	logger.WithFields(Fields{"queue": "APP.IN", "depth": 5}).WithError(errors.New("full")).Warn("Queue is filling up")
+	logger.WithField("queue", "APP.IN").Debug("Not logged below the level")
+
+	entries := decode(t, &out)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Queue is filling up", entries[0]["msg"])
+	assert.Equal(t, "warning", entries[0]["level"])
+	assert.Equal(t, "APP.IN", entries[0]["queue"])
+	assert.Equal(t, float64(5), entries[0]["depth"])
+	assert.Equal(t, "full", entries[0]["error"])
+
+	assert.Same(t, base, ToLogrus(logger))
+}
+
+func TestSlogAdapter(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewSlog(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	logger.WithField("queue_manager", "QM1").WithFields(Fields{"queue": "APP.IN"}).Info("Opened queue")
+	logger.WithError(errors.New("refused")).Error("Failed to connect")
+	logger.Debug("Not logged below the level")
+
+	entries := decode(t, &out)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Opened queue", entries[0]["msg"])
+	assert.Equal(t, "INFO", entries[0]["level"])
+	assert.Equal(t, "QM1", entries[0]["queue_manager"])
+	assert.Equal(t, "APP.IN", entries[0]["queue"])
+	assert.Equal(t, "ERROR", entries[1]["level"])
+	assert.Equal(t, "refused", entries[1]["error"])
+}
+
+// sugaredRecorder records the calls of the zap adapter as a zap sugared logger would get them
+type sugaredRecorder struct {
+	calls []string
+	args  [][]interface{}
+}
+
+func (r *sugaredRecorder) record(level, msg string, keysAndValues []interface{}) {
+	r.calls = append(r.calls, level+" "+msg)
+	r.args = append(r.args, keysAndValues)
+}
+
+func (r *sugaredRecorder) Debugw(msg string, kv ...interface{}) { r.record("debug", msg, kv) }
+func (r *sugaredRecorder) Infow(msg string, kv ...interface{})  { r.record("info", msg, kv) }
+func (r *sugaredRecorder) Warnw(msg string, kv ...interface{})  { r.record("warn", msg, kv) }
+func (r *sugaredRecorder) Errorw(msg string, kv ...interface{}) { r.record("error", msg, kv) }
+
+func TestZapAdapter(t *testing.T) {
+	sugar := &sugaredRecorder{}
+	logger := NewZap(sugar)
+
+	withQueue := logger.WithFields(Fields{"queue": "APP.IN", "depth": 5})
+	withQueue.WithError(errors.New("full")).Warn("Queue is filling up")
+	withQueue.Info("Queue drained")
+	logger.Debug("No fields")
+
+	assert.Equal(t, []string{"warn Queue is filling up", "info Queue drained", "debug No fields"}, sugar.calls)
+	assert.Equal(t, []interface{}{"depth", 5, "queue", "APP.IN", "error", errors.New("full")}, sugar.args[0])
+	// Deriving a logger leaves the one it came from unchanged
+	assert.Equal(t, []interface{}{"depth", 5, "queue", "APP.IN"}, sugar.args[1])
+	assert.Empty(t, sugar.args[2])
+}
+
+func TestToLogrusForwards(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewSlog(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	bridge := ToLogrus(logger)
+	bridge.WithField("sink", "splunk").Error("Failed to write records")
+	bridge.Debug("Debug entry")
+
+	entries := decode(t, &out)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Failed to write records", entries[0]["msg"])
+	assert.Equal(t, "ERROR", entries[0]["level"])
+	assert.Equal(t, "splunk", entries[0]["sink"])
+	assert.Equal(t, "DEBUG", entries[1]["level"])
+}
+
+func TestWithCycle(t *testing.T) {
+	var out bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&out)
+	base.SetFormatter(&logrus.JSONFormatter{})
+
+	cycle := &Cycle{}
+	logger := WithCycle(NewLogrus(base), cycle)
+	queueLogger := logger.WithField("queue", "APP.IN") // derived before the cycle starts
+
+	id := cycle.Start()
+	assert.Len(t, id, 16)
+	queueLogger.Info("During the cycle")
+	ToLogrus(logger).Warn("Through the logrus bridge")
+	cycle.End()
+	logger.Info("Between cycles")
+
+	entries := decode(t, &out)
+	require.Len(t, entries, 3)
+	assert.Equal(t, id, entries[0][CycleIDKey])
+	assert.Equal(t, "APP.IN", entries[0]["queue"])
+	assert.Equal(t, id, entries[1][CycleIDKey])
+	assert.NotContains(t, entries[2], CycleIDKey)
+	assert.NotEqual(t, id, cycle.Start())
+}
+
+func TestToLogrusKeepsCycleLoggerLevel(t *testing.T) {
+	base := logrus.New()
+	base.SetLevel(logrus.InfoLevel)
+
+	bridge := ToLogrus(WithCycle(NewLogrus(base), &Cycle{}))
+	assert.Equal(t, logrus.InfoLevel, bridge.GetLevel())
+	assert.False(t, bridge.IsLevelEnabled(logrus.DebugLevel))
+
+	bridge = ToLogrus(WithCycle(NewSlog(slog.Default()), &Cycle{}))
+	assert.Equal(t, logrus.DebugLevel, bridge.GetLevel())
+}
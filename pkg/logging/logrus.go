@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logrusLogger logs through a logrus entry
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+// NewLogrus returns a Logger logging through a logrus logger, with its level, formatter
+// and hooks
+func NewLogrus(logger *logrus.Logger) Logger {
+	return logrusLogger{entry: logrus.NewEntry(logger)}
+}
+
+func (l logrusLogger) WithField(key string, value interface{}) Logger {
+	return logrusLogger{entry: l.entry.WithField(key, value)}
+}
+
+func (l logrusLogger) WithFields(fields Fields) Logger {
+	return logrusLogger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+
+func (l logrusLogger) WithError(err error) Logger {
+	return logrusLogger{entry: l.entry.WithError(err)}
+}
+
+func (l logrusLogger) Debug(msg string) { l.entry.Debug(msg) }
+func (l logrusLogger) Info(msg string)  { l.entry.Info(msg) }
+func (l logrusLogger) Warn(msg string)  { l.entry.Warn(msg) }
+func (l logrusLogger) Error(msg string) { l.entry.Error(msg) }
+
+// ToLogrus returns a logrus logger for the packages that still take one. For a Logger
+// created by NewLogrus it is the underlying logger, without the fields added since;
+// otherwise every entry is forwarded to the Logger and nothing is written by logrus.
+// The bridge has the level of the logrus logger underneath, if any, so level checks
+// such as IsLevelEnabled still hold.
+func ToLogrus(logger Logger) *logrus.Logger {
+	if l, ok := logger.(logrusLogger); ok {
+		return l.entry.Logger
+	}
+
+	bridge := logrus.New()
+	bridge.SetOutput(io.Discard)
+	bridge.SetFormatter(discardFormatter{})
+	bridge.SetLevel(bridgeLevel(logger))
+	bridge.AddHook(forwardHook{logger: logger})
+	return bridge
+}
+
+// bridgeLevel returns the level of the logrus logger a Logger writes through, or
+// DebugLevel for other Loggers, which filter by their own level
+func bridgeLevel(logger Logger) logrus.Level {
+	switch l := logger.(type) {
+	case logrusLogger:
+		return l.entry.Logger.GetLevel()
+	case cycleLogger:
+		return bridgeLevel(l.logger)
+	default:
+		return logrus.DebugLevel
+	}
+}
+
+// forwardHook passes logrus entries on to a Logger
+type forwardHook struct {
+	logger Logger
+}
+
+func (h forwardHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h forwardHook) Fire(entry *logrus.Entry) error {
+	logger := h.logger
+	if len(entry.Data) > 0 {
+		logger = logger.WithFields(Fields(entry.Data))
+	}
+
+	switch entry.Level {
+	case logrus.TraceLevel, logrus.DebugLevel:
+		logger.Debug(entry.Message)
+	case logrus.InfoLevel:
+		logger.Info(entry.Message)
+	case logrus.WarnLevel:
+		logger.Warn(entry.Message)
+	default:
+		logger.Error(entry.Message)
+	}
+	return nil
+}
+
+// discardFormatter skips formatting entries that are written to io.Discard anyway
+type discardFormatter struct{}
+
+func (discardFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
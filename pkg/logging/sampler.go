@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Sampler limits the entries logged for a problem that repeats, such as a parse error
+// on every message of a backlog. Of the entries of one class, the first are logged, then
+// every Nth with the number suppressed since the previous one. A nil Sampler logs every
+// entry.
+type Sampler struct {
+	first int
+	every int
+
+	mu      sync.Mutex
+	classes map[string]*sampledClass
+}
+
+// sampledClass counts the entries of one class
+type sampledClass struct {
+	seen       int  // entries since the class was first seen or last forgotten
+	suppressed int  // entries suppressed since the last one logged
+	active     bool // whether there were entries since the last Flush
+}
+
+// NewSampler creates a sampler logging the first entries of each class, then every
+// every-th, or none with every 0
+func NewSampler(first, every int) *Sampler {
+	return &Sampler{first: first, every: every, classes: make(map[string]*sampledClass)}
+}
+
+// Allow reports whether the next entry of a class is logged, and if so how many entries
+// of the class were suppressed before it
+func (s *Sampler) Allow(class string) (bool, int) {
+	if s == nil {
+		return true, 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.classes[class]
+	if !ok {
+		c = &sampledClass{}
+		s.classes[class] = c
+	}
+	c.seen++
+	c.active = true
+
+	if c.seen <= s.first || (s.every > 0 && (c.seen-s.first)%s.every == 0) {
+		suppressed := c.suppressed
+		c.suppressed = 0
+		return true, suppressed
+	}
+	c.suppressed++
+	return false, 0
+}
+
+// Flush returns, per class, the entries suppressed since its last logged one, for a
+// summary at the end of a collection cycle. Classes without entries since the previous
+// Flush are forgotten, so a problem that comes back is logged in full again.
+func (s *Sampler) Flush() map[string]int {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var suppressed map[string]int
+	for class, c := range s.classes {
+		if c.suppressed > 0 {
+			if suppressed == nil {
+				suppressed = make(map[string]int)
+			}
+			suppressed[class] = c.suppressed
+			c.suppressed = 0
+		}
+		if !c.active {
+			delete(s.classes, class)
+			continue
+		}
+		c.active = false
+	}
+	return suppressed
+}
+
+// numbers matches the parts of an error message that differ between occurrences
+var numbers = regexp.MustCompile(`[0-9]+`)
+
+// ErrorClass returns the class of an error for sampling: its message with the numbers,
+// such as offsets and lengths, left out, so the same failure on different messages is
+// one class
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	return numbers.ReplaceAllString(err.Error(), "#")
+}
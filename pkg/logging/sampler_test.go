@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplerLogsFirstThenEveryNth(t *testing.T) {
+	sampler := NewSampler(2, 3)
+
+	var logged []int
+	var suppressed []int
+	for i := 1; i <= 9; i++ {
+		if ok, n := sampler.Allow("parse"); ok {
+			logged = append(logged, i)
+			suppressed = append(suppressed, n)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 5, 8}, logged)
+	assert.Equal(t, []int{0, 0, 2, 2}, suppressed)
+
+	// Classes are sampled independently
+	ok, _ := sampler.Allow("quarantine")
+	assert.True(t, ok)
+
+	// The entry after the last logged one is left for the summary
+	assert.Equal(t, map[string]int{"parse": 1}, sampler.Flush())
+	assert.Nil(t, sampler.Flush())
+
+	// A class that was quiet for a cycle is forgotten and logged in full again
+	sampler.Flush()
+	ok, n := sampler.Allow("parse")
+	assert.True(t, ok)
+	assert.Zero(t, n)
+}
+
+func TestSamplerWithoutEvery(t *testing.T) {
+	sampler := NewSampler(1, 0)
+	ok, _ := sampler.Allow("parse")
+	assert.True(t, ok)
+	for i := 0; i < 5; i++ {
+		ok, _ = sampler.Allow("parse")
+		assert.False(t, ok)
+	}
+	assert.Equal(t, map[string]int{"parse": 5}, sampler.Flush())
+
+	// A nil sampler logs everything
+	var none *Sampler
+	ok, _ = none.Allow("parse")
+	assert.True(t, ok)
+	assert.Nil(t, none.Flush())
+}
+
+func TestErrorClass(t *testing.T) {
+	assert.Equal(t,
+		ErrorClass(errors.New("parameter at offset 36 has invalid length 12")),
+		ErrorClass(errors.New("parameter at offset 1044 has invalid length 7")))
+	assert.NotEqual(t, ErrorClass(errors.New("message too short")), ErrorClass(errors.New("not a PCF message")))
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// slogLogger logs through a log/slog logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlog returns a Logger logging through a log/slog logger. Fields become attributes.
+func NewSlog(logger *slog.Logger) Logger {
+	return slogLogger{logger: logger}
+}
+
+func (l slogLogger) WithField(key string, value interface{}) Logger {
+	return slogLogger{logger: l.logger.With(key, value)}
+}
+
+func (l slogLogger) WithFields(fields Fields) Logger {
+	return slogLogger{logger: l.logger.With(keyValues(fields)...)}
+}
+
+func (l slogLogger) WithError(err error) Logger {
+	return slogLogger{logger: l.logger.With(ErrorKey, err)}
+}
+
+func (l slogLogger) Debug(msg string) { l.logger.Debug(msg) }
+func (l slogLogger) Info(msg string)  { l.logger.Info(msg) }
+func (l slogLogger) Warn(msg string)  { l.logger.Warn(msg) }
+func (l slogLogger) Error(msg string) { l.logger.Error(msg) }
+
+// keyValues returns fields as alternating keys and values, sorted by key so the output
+// does not depend on map order
+func keyValues(fields Fields) []interface{} {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]interface{}, 0, 2*len(keys))
+	for _, key := range keys {
+		args = append(args, key, fields[key])
+	}
+	return args
+}
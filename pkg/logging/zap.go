@@ -0,0 +1,46 @@
+package logging
+
+// ZapSugaredLogger is the part of a *zap.SugaredLogger the zap adapter logs through,
+// so the adapter does not make every program depend on zap
+type ZapSugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// zapLogger logs through a zap sugared logger, keeping its fields itself
+type zapLogger struct {
+	sugar  ZapSugaredLogger
+	fields []interface{}
+}
+
+// NewZap returns a Logger logging through a zap sugared logger, as returned by
+// zap.Logger.Sugar. Fields become zap fields.
+func NewZap(sugar ZapSugaredLogger) Logger {
+	return zapLogger{sugar: sugar}
+}
+
+// with returns a copy of the logger with more fields
+func (l zapLogger) with(keysAndValues ...interface{}) Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(keysAndValues))
+	fields = append(fields, l.fields...)
+	return zapLogger{sugar: l.sugar, fields: append(fields, keysAndValues...)}
+}
+
+func (l zapLogger) WithField(key string, value interface{}) Logger {
+	return l.with(key, value)
+}
+
+func (l zapLogger) WithFields(fields Fields) Logger {
+	return l.with(keyValues(fields)...)
+}
+
+func (l zapLogger) WithError(err error) Logger {
+	return l.with(ErrorKey, err)
+}
+
+func (l zapLogger) Debug(msg string) { l.sugar.Debugw(msg, l.fields...) }
+func (l zapLogger) Info(msg string)  { l.sugar.Infow(msg, l.fields...) }
+func (l zapLogger) Warn(msg string)  { l.sugar.Warnw(msg, l.fields...) }
+func (l zapLogger) Error(msg string) { l.sugar.Errorw(msg, l.fields...) }
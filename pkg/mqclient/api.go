@@ -0,0 +1,36 @@
+package mqclient
+
+import "time"
+
+// MQClientAPI is the part of MQClient that the collector and the Prometheus exporter use.
+// MQClient implements it against a queue manager; package mqfake implements it in memory,
+// so the collection pipeline can run without one.
+type MQClientAPI interface {
+	Connect() error
+	Disconnect() error
+	IsConnected() bool
+	CheckConnection() error
+
+	OpenStatsQueue(queueName string) error
+	OpenAccountingQueue(queueName string) error
+	GetAllMessages(queueType string) ([]*MQMessage, error)
+	GetStatisticsAndAccounting() (stats, accounting []*MQMessage, err error)
+	DrainStatisticsAndAccounting(fn func(*MQMessage) error) error
+	OpenQueue(queueName string, browse bool) error
+	GetQueueMessage(queueName string, wait time.Duration) (*MQMessage, error)
+	PutQueueMessage(queueName string, msg *MQMessage) error
+	Commit() error
+	Backout() error
+
+	InquireQueueManager() (*QueueManagerSettings, error)
+	InquireQueues(queuePattern string) ([]*QueueInfo, error)
+	InquireQueueDepths(queueNames []string) ([]*QueueInfo, error)
+	ResetQueueStatistics(queuePattern string) ([]*QueueResetStatistics, error)
+	InquireStartTime() (time.Time, error)
+
+	Subscribe(topic string) error
+	GetPublication(topic string, wait time.Duration) (*MQMessage, error)
+	Unsubscribe(topic string) error
+}
+
+var _ MQClientAPI = (*MQClient)(nil)
@@ -0,0 +1,109 @@
+package mqclient
+
+import (
+	"fmt"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// backoutPolicy is the backout threshold and backout queue of a queue read under syncpoint
+type backoutPolicy struct {
+	threshold int32
+	queue     string
+}
+
+// exceeded reports whether a message has been backed out as often as the threshold allows
+func (p backoutPolicy) exceeded(md *ibmmq.MQMD) bool {
+	return p.threshold > 0 && md != nil && md.BackoutCount >= p.threshold
+}
+
+// inquireBackout returns the backout policy of an open queue: its BOTHRESH and BOQNAME,
+// each replaced by the configured value when set. A queue that cannot be inquired keeps
+// the configured values only.
+func (c *MQClient) inquireBackout(queue ibmmq.MQObject, queueName string) backoutPolicy {
+	policy := backoutPolicy{
+		threshold: int32(c.config.BackoutThreshold),
+		queue:     c.config.BackoutQueue,
+	}
+
+	values, err := queue.Inq([]int32{ibmmq.MQIA_BACKOUT_THRESHOLD, ibmmq.MQCA_BACKOUT_REQ_Q_NAME})
+	if err != nil {
+		c.logger.WithError(err).WithField("queue", queueName).Warn("Failed to inquire backout threshold and queue")
+		return policy
+	}
+	if policy.threshold == 0 {
+		policy.threshold = inqInt(values, ibmmq.MQIA_BACKOUT_THRESHOLD)
+	}
+	if policy.queue == "" {
+		policy.queue = inqString(values, ibmmq.MQCA_BACKOUT_REQ_Q_NAME)
+	}
+
+	c.logger.WithFields(logging.Fields{
+		"queue":             queueName,
+		"backout_threshold": policy.threshold,
+		"backout_queue":     policy.queue,
+	}).Debug("Using backout policy")
+	return policy
+}
+
+// backOutMessage moves a message that reached the backout threshold of its queue to the
+// backout queue, within the unit of work it was got in, and reports whether it was moved.
+// Without a backout queue, or when the put fails, the message is left to be processed.
+func (c *MQClient) backOutMessage(queueType string, msg *MQMessage) bool {
+	policy, qmgr := c.statsBackout, c.qmgr
+	if queueType == "accounting" {
+		policy, qmgr = c.acctBackout, c.acctQmgr
+	}
+	if !policy.exceeded(msg.MD) {
+		return false
+	}
+
+	fields := logging.Fields{
+		"queue_type":    queueType,
+		"message_id":    fmt.Sprintf("%x", msg.MD.MsgId),
+		"backout_count": msg.MD.BackoutCount,
+		"backout_queue": policy.queue,
+	}
+	if policy.queue == "" {
+		c.logger.WithFields(fields).Warn("Message reached the backout threshold but there is no backout queue, processing it again")
+		return false
+	}
+	if err := c.putMessage(qmgr, policy.queue, msg, ibmmq.MQPMO_SYNCPOINT); err != nil {
+		c.logger.WithError(err).WithFields(fields).Error("Failed to move message to the backout queue, processing it again")
+		return false
+	}
+
+	c.logger.WithFields(fields).Warn("Moved message to the backout queue")
+	return true
+}
+
+// Commit commits the messages got since the last commit or backout, removing them from
+// their queues. It does nothing unless messages are got under syncpoint.
+func (c *MQClient) Commit() error {
+	return c.endUnitOfWork((*ibmmq.MQQueueManager).Cmit, "commit")
+}
+
+// Backout returns the messages got since the last commit or backout to their queues,
+// incrementing their backout count. It does nothing unless messages are got under
+// syncpoint.
+func (c *MQClient) Backout() error {
+	return c.endUnitOfWork((*ibmmq.MQQueueManager).Back, "back out")
+}
+
+// endUnitOfWork commits or backs out the unit of work of each connection
+func (c *MQClient) endUnitOfWork(end func(*ibmmq.MQQueueManager) error, action string) error {
+	if !c.config.Syncpoint || !c.connected {
+		return nil
+	}
+
+	if err := end(&c.qmgr); err != nil {
+		return wrapError(err, "%s messages", action)
+	}
+	if c.config.ParallelDrain {
+		if err := end(&c.acctQmgr); err != nil {
+			return wrapError(err, "%s accounting messages", action)
+		}
+	}
+	return nil
+}
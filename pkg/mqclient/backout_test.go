@@ -0,0 +1,48 @@
+package mqclient
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoutPolicyExceeded(t *testing.T) {
+	md := ibmmq.NewMQMD()
+	md.BackoutCount = 2
+
+	assert.True(t, backoutPolicy{threshold: 2}.exceeded(md))
+	assert.True(t, backoutPolicy{threshold: 1}.exceeded(md))
+	assert.False(t, backoutPolicy{threshold: 3}.exceeded(md))
+	assert.False(t, backoutPolicy{}.exceeded(md), "a threshold of 0 never moves messages")
+	assert.False(t, backoutPolicy{threshold: 1}.exceeded(nil))
+}
+
+func TestBackOutMessageWithoutBackoutQueue(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM", Syncpoint: true}, logging.NewLogrus(logger))
+	client.statsBackout = backoutPolicy{threshold: 1}
+
+	md := ibmmq.NewMQMD()
+	md.BackoutCount = 5
+	// The message is processed again rather than discarded
+	assert.False(t, client.backOutMessage("stats", &MQMessage{MD: md, Type: "stats"}))
+}
+
+func TestUnitOfWorkRequiresSyncpoint(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logging.NewLogrus(logger))
+	client.connected = true
+	assert.NoError(t, client.Commit())
+	assert.NoError(t, client.Backout())
+
+	client = NewMQClient(&config.MQConfig{QueueManager: "TESTQM", Syncpoint: true}, logging.NewLogrus(logger))
+	assert.NoError(t, client.Commit(), "nothing to commit without a connection")
+}
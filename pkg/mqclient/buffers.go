@@ -0,0 +1,79 @@
+package mqclient
+
+import "sync"
+
+const (
+	// defaultBufferSize is the size of get buffers until messages have been seen
+	defaultBufferSize = 100 * 1024
+	// minBufferSize is the smallest get buffer, enough for most statistics messages
+	minBufferSize = 16 * 1024
+	// bufferGranularity rounds buffer sizes up so small size changes reuse buffers
+	bufferGranularity = 4 * 1024
+	// bufferSizeWindow is the number of gets after which the buffer size follows the
+	// largest message of the window, so one huge message does not pin large buffers
+	bufferSizeWindow = 1024
+)
+
+// bufferPool reuses message buffers across gets. Buffers are sized by the largest
+// message of the recent gets, and callers copy out only the bytes a message used.
+type bufferPool struct {
+	pool sync.Pool
+
+	mu        sync.Mutex
+	size      int
+	windowMax int
+	gets      int
+}
+
+func newBufferPool() *bufferPool {
+	return &bufferPool{size: defaultBufferSize}
+}
+
+// get returns a buffer of at least the current size, or of minimum bytes if larger
+func (p *bufferPool) get(minimum int) *[]byte {
+	size := max(p.bufferSize(), roundBufferSize(minimum))
+	if buf, ok := p.pool.Get().(*[]byte); ok && cap(*buf) >= size {
+		*buf = (*buf)[:cap(*buf)]
+		return buf
+	}
+	buf := make([]byte, size)
+	return &buf
+}
+
+// put returns a buffer to the pool, dropping buffers far larger than the current size
+func (p *bufferPool) put(buf *[]byte) {
+	if cap(*buf) > 4*p.bufferSize() {
+		return
+	}
+	p.pool.Put(buf)
+}
+
+// observe records the length of a message so later buffers fit it
+func (p *bufferPool) observe(length int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.windowMax = max(p.windowMax, length)
+	if size := roundBufferSize(length); size > p.size {
+		p.size = size
+	}
+
+	p.gets++
+	if p.gets >= bufferSizeWindow {
+		p.size = roundBufferSize(p.windowMax)
+		p.windowMax = 0
+		p.gets = 0
+	}
+}
+
+func (p *bufferPool) bufferSize() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
+
+// roundBufferSize rounds a message length up to a buffer size
+func roundBufferSize(length int) int {
+	size := (length + bufferGranularity - 1) / bufferGranularity * bufferGranularity
+	return max(size, minBufferSize)
+}
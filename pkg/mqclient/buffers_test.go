@@ -0,0 +1,32 @@
+package mqclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferPoolFollowsMessageSizes(t *testing.T) {
+	pool := newBufferPool()
+	assert.Len(t, *pool.get(0), defaultBufferSize)
+
+	// A message larger than the buffers grows them
+	pool.observe(300*1024 + 1)
+	assert.Equal(t, 304*1024, pool.bufferSize())
+	assert.GreaterOrEqual(t, len(*pool.get(0)), 304*1024)
+
+	// A window of small messages shrinks them again
+	for i := 0; i < 2*bufferSizeWindow; i++ {
+		pool.observe(2000)
+	}
+	assert.Equal(t, minBufferSize, pool.bufferSize())
+}
+
+func TestBufferPoolMinimum(t *testing.T) {
+	pool := newBufferPool()
+	buf := pool.get(150 * 1024)
+	assert.Len(t, *buf, 152*1024)
+
+	pool.put(buf)
+	assert.GreaterOrEqual(t, len(*pool.get(0)), defaultBufferSize)
+}
@@ -0,0 +1,237 @@
+package mqclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// QueueManagerSettings holds the queue manager attributes that control statistics and accounting
+type QueueManagerSettings struct {
+	Name               string `json:"name"`
+	StatisticsQueue    int32  `json:"statistics_queue"`
+	StatisticsMQI      int32  `json:"statistics_mqi"`
+	StatisticsChannel  int32  `json:"statistics_channel"`
+	StatisticsInterval int32  `json:"statistics_interval"`
+	AccountingQueue    int32  `json:"accounting_queue"`
+	AccountingMQI      int32  `json:"accounting_mqi"`
+	AccountingInterval int32  `json:"accounting_interval"`
+	CommandLevel       int32  `json:"command_level"`
+	DeadLetterQueue    string `json:"dead_letter_queue"`
+}
+
+// MonitoringEnabled reports whether an MQMON attribute value collects data
+func MonitoringEnabled(value int32) bool {
+	return value != ibmmq.MQMON_OFF && value != ibmmq.MQMON_NONE
+}
+
+// MonitoringString returns the MQSC name of an MQMON attribute value (e.g. ON, OFF, NONE)
+func MonitoringString(value int32) string {
+	switch value {
+	case ibmmq.MQMON_ON:
+		return "ON"
+	case ibmmq.MQMON_OFF:
+		return "OFF"
+	case ibmmq.MQMON_NONE:
+		return "NONE"
+	case ibmmq.MQMON_Q_MGR:
+		return "QMGR"
+	case ibmmq.MQMON_LOW:
+		return "LOW"
+	case ibmmq.MQMON_MEDIUM:
+		return "MEDIUM"
+	case ibmmq.MQMON_HIGH:
+		return "HIGH"
+	default:
+		return fmt.Sprintf("%d", value)
+	}
+}
+
+// InquireQueueManager reads the statistics and accounting settings of the connected queue manager
+func (c *MQClient) InquireQueueManager() (*QueueManagerSettings, error) {
+	if !c.connected {
+		return nil, ErrNotConnected
+	}
+
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q_MGR
+
+	qmgrObject, err := c.qmgr.Open(mqod, ibmmq.MQOO_INQUIRE|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return nil, wrapError(err, "open queue manager for inquire")
+	}
+	defer qmgrObject.Close(0)
+
+	values, err := qmgrObject.Inq([]int32{
+		ibmmq.MQCA_Q_MGR_NAME,
+		ibmmq.MQIA_STATISTICS_Q,
+		ibmmq.MQIA_STATISTICS_MQI,
+		ibmmq.MQIA_STATISTICS_CHANNEL,
+		ibmmq.MQIA_STATISTICS_INTERVAL,
+		ibmmq.MQIA_ACCOUNTING_Q,
+		ibmmq.MQIA_ACCOUNTING_MQI,
+		ibmmq.MQIA_ACCOUNTING_INTERVAL,
+		ibmmq.MQIA_COMMAND_LEVEL,
+		ibmmq.MQCA_DEAD_LETTER_Q_NAME,
+	})
+	if err != nil {
+		return nil, wrapError(err, "inquire queue manager attributes")
+	}
+
+	settings := &QueueManagerSettings{
+		Name:               inqString(values, ibmmq.MQCA_Q_MGR_NAME),
+		StatisticsQueue:    inqInt(values, ibmmq.MQIA_STATISTICS_Q),
+		StatisticsMQI:      inqInt(values, ibmmq.MQIA_STATISTICS_MQI),
+		StatisticsChannel:  inqInt(values, ibmmq.MQIA_STATISTICS_CHANNEL),
+		StatisticsInterval: inqInt(values, ibmmq.MQIA_STATISTICS_INTERVAL),
+		AccountingQueue:    inqInt(values, ibmmq.MQIA_ACCOUNTING_Q),
+		AccountingMQI:      inqInt(values, ibmmq.MQIA_ACCOUNTING_MQI),
+		AccountingInterval: inqInt(values, ibmmq.MQIA_ACCOUNTING_INTERVAL),
+		CommandLevel:       inqInt(values, ibmmq.MQIA_COMMAND_LEVEL),
+		DeadLetterQueue:    inqString(values, ibmmq.MQCA_DEAD_LETTER_Q_NAME),
+	}
+
+	c.logger.WithFields(logging.Fields{
+		"queue_manager": settings.Name,
+		"statq":         MonitoringString(settings.StatisticsQueue),
+		"acctq":         MonitoringString(settings.AccountingQueue),
+	}).Debug("Inquired queue manager settings")
+
+	return settings, nil
+}
+
+// CheckQueue opens a queue for input, or browse in browse mode, and inquire, the way the
+// collector uses it, and returns its current depth. The queue is closed again without
+// reading any messages.
+func (c *MQClient) CheckQueue(queueName string) (int32, error) {
+	if !c.connected {
+		return 0, ErrNotConnected
+	}
+
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = queueName
+
+	queue, err := c.qmgr.Open(mqod, c.inputOpenOptions()|ibmmq.MQOO_INQUIRE)
+	if err != nil {
+		return 0, wrapError(err, "open queue %s", queueName)
+	}
+	defer queue.Close(0)
+
+	values, err := queue.Inq([]int32{ibmmq.MQIA_CURRENT_Q_DEPTH})
+	if err != nil {
+		return 0, wrapError(err, "inquire queue %s", queueName)
+	}
+
+	return inqInt(values, ibmmq.MQIA_CURRENT_Q_DEPTH), nil
+}
+
+// PingQueueManager checks that the command server answers PCF requests
+func (c *MQClient) PingQueueManager() error {
+	_, err := c.ExecuteCommand(ibmmq.MQCMD_PING_Q_MGR, nil)
+	return err
+}
+
+// InquireStartTime returns when the connected queue manager was last started, from its
+// status. A later start time than seen before means the queue manager restarted.
+func (c *MQClient) InquireStartTime() (time.Time, error) {
+	responses, err := c.ExecuteCommand(ibmmq.MQCMD_INQUIRE_Q_MGR_STATUS, nil)
+	if err != nil {
+		return time.Time{}, wrapError(err, "inquire queue manager status")
+	}
+
+	for _, response := range responses {
+		date, hasDate := response.GetString(ibmmq.MQCACF_Q_MGR_START_DATE)
+		clock, hasTime := response.GetString(ibmmq.MQCACF_Q_MGR_START_TIME)
+		if hasDate && hasTime {
+			return parseStartTime(date, clock)
+		}
+	}
+	return time.Time{}, fmt.Errorf("queue manager status has no start time")
+}
+
+// parseStartTime parses the start date (yyyy-mm-dd) and time (hh.mm.ss) of a queue
+// manager, which are in its local time
+func parseStartTime(date, clock string) (time.Time, error) {
+	value := strings.TrimSpace(date) + " " + strings.ReplaceAll(strings.TrimSpace(clock), ":", ".")
+	started, err := time.ParseInLocation("2006-01-02 15.04.05", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid queue manager start time %q: %w", value, err)
+	}
+	return started, nil
+}
+
+// CheckConnection inquires the queue manager name on every connection of the client, a
+// call that reaches the queue manager without the command server, to find out whether
+// the connections still work
+func (c *MQClient) CheckConnection() error {
+	if !c.connected {
+		return ErrNotConnected
+	}
+
+	if err := inquireName(c.qmgr); err != nil {
+		return err
+	}
+	if c.config.ParallelDrain {
+		if err := inquireName(c.acctQmgr); err != nil {
+			return fmt.Errorf("accounting queue connection: %w", err)
+		}
+	}
+	return nil
+}
+
+// inquireName opens the queue manager object of a connection and inquires its name
+func inquireName(qmgr ibmmq.MQQueueManager) error {
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q_MGR
+
+	qmgrObject, err := qmgr.Open(mqod, ibmmq.MQOO_INQUIRE|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return wrapError(err, "open queue manager for inquire")
+	}
+	defer qmgrObject.Close(0)
+
+	if _, err := qmgrObject.Inq([]int32{ibmmq.MQCA_Q_MGR_NAME}); err != nil {
+		return wrapError(err, "inquire queue manager name")
+	}
+	return nil
+}
+
+// IsConnectionBroken reports whether an error means the connection to the queue manager
+// is lost and has to be made again, by the ConnectionBroken reason codes
+func IsConnectionBroken(err error) bool {
+	reason, ok := Reason(err)
+	return ok && ConnectionBroken(reason)
+}
+
+// IsNotAuthorized reports whether an error is a failure of authentication or
+// authorization, such as MQRC_NOT_AUTHORIZED, as classified by ClassAuth
+func IsNotAuthorized(err error) bool {
+	return Classify(err) == ClassAuth
+}
+
+// ReasonString returns the MQRC name of a reason code, or the code itself if it has none
+func ReasonString(reason int32) string {
+	if name := ibmmq.MQItoString("RC", int(reason)); name != "" {
+		return name
+	}
+	return strconv.Itoa(int(reason))
+}
+
+func inqInt(values map[int32]interface{}, selector int32) int32 {
+	if v, ok := values[selector].(int32); ok {
+		return v
+	}
+	return 0
+}
+
+func inqString(values map[int32]interface{}, selector int32) string {
+	if v, ok := values[selector].(string); ok {
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package mqclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitoringSettings(t *testing.T) {
+	assert.True(t, MonitoringEnabled(ibmmq.MQMON_ON))
+	assert.True(t, MonitoringEnabled(ibmmq.MQMON_HIGH))
+	assert.False(t, MonitoringEnabled(ibmmq.MQMON_OFF))
+	assert.False(t, MonitoringEnabled(ibmmq.MQMON_NONE))
+
+	assert.Equal(t, "ON", MonitoringString(ibmmq.MQMON_ON))
+	assert.Equal(t, "NONE", MonitoringString(ibmmq.MQMON_NONE))
+	assert.Equal(t, "MEDIUM", MonitoringString(ibmmq.MQMON_MEDIUM))
+	assert.Equal(t, "99", MonitoringString(99))
+}
+
+func TestIsNotAuthorized(t *testing.T) {
+	authErr := &ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_NOT_AUTHORIZED}
+	assert.True(t, IsNotAuthorized(authErr))
+	assert.True(t, IsNotAuthorized(fmt.Errorf("failed to open queue X: %w", authErr)))
+	assert.True(t, IsNotAuthorized(wrapError(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_CONNECTION_NOT_AUTHORIZED}, "connect")))
+	assert.True(t, IsNotAuthorized(&CommandError{Command: ibmmq.MQCMD_RESET_Q_STATS, Reason: ibmmq.MQRC_SECURITY_ERROR}))
+
+	assert.False(t, IsNotAuthorized(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_UNKNOWN_OBJECT_NAME}))
+	assert.False(t, IsNotAuthorized(errors.New("plain error")))
+	assert.False(t, IsNotAuthorized(nil))
+}
+
+func TestCapabilityChecksRequireConnection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logging.NewLogrus(logger))
+
+	_, err := client.InquireQueueManager()
+	assert.ErrorIs(t, err, ErrNotConnected)
+
+	_, err = client.CheckQueue("SYSTEM.ADMIN.STATISTICS.QUEUE")
+	assert.ErrorIs(t, err, ErrNotConnected)
+
+	assert.ErrorIs(t, client.PingQueueManager(), ErrNotConnected)
+	assert.ErrorIs(t, client.CheckConnection(), ErrNotConnected)
+
+	_, err = client.InquireStartTime()
+	assert.Error(t, err)
+}
+
+func TestParseStartTime(t *testing.T) {
+	started, err := parseStartTime("2026-03-14 ", "09.26.53")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 14, 9, 26, 53, 0, time.Local), started)
+
+	started, err = parseStartTime("2026-03-14", "09:26:53")
+	require.NoError(t, err)
+	assert.Equal(t, 53, started.Second())
+
+	_, err = parseStartTime("", "09.26.53")
+	assert.Error(t, err)
+}
+
+func TestIsConnectionBroken(t *testing.T) {
+	brokenErr := &ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_CONNECTION_BROKEN}
+	assert.True(t, IsConnectionBroken(brokenErr))
+	assert.True(t, IsConnectionBroken(fmt.Errorf("failed to inquire queue manager name: %w", brokenErr)))
+	assert.True(t, IsConnectionBroken(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_HCONN_ERROR}))
+	assert.True(t, IsConnectionBroken(ErrNotConnected))
+
+	assert.False(t, IsConnectionBroken(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_NOT_AUTHORIZED}))
+	assert.False(t, IsConnectionBroken(errors.New("plain error")))
+	assert.False(t, IsConnectionBroken(nil))
+}
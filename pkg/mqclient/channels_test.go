@@ -0,0 +1,68 @@
+package mqclient
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelStatusFromResponse(t *testing.T) {
+	response := &CommandResponse{
+		Command: ibmmq.MQCMD_INQUIRE_CHANNEL_STATUS,
+		Parameters: []*ibmmq.PCFParameter{
+			NewStringParameter(ibmmq.MQCACH_CHANNEL_NAME, "APP1.SVRCONN"),
+			NewStringParameter(ibmmq.MQCACH_CONNECTION_NAME, "10.0.0.1"),
+			NewIntParameter(ibmmq.MQIACH_CHANNEL_STATUS, ibmmq.MQCHS_RUNNING),
+			NewIntParameter(ibmmq.MQIACH_MSGS, 42),
+			NewIntParameter(ibmmq.MQIACH_BYTES_SENT, 1024),
+			NewIntParameter(ibmmq.MQIACH_BYTES_RECEIVED, 2048),
+			NewStringParameter(ibmmq.MQCACH_LAST_MSG_DATE, "2024-03-01"),
+			NewStringParameter(ibmmq.MQCACH_LAST_MSG_TIME, "12.30.05"),
+		},
+	}
+
+	status := channelStatusFromResponse(response)
+	require.NotNil(t, status)
+	assert.Equal(t, "APP1.SVRCONN", status.ChannelName)
+	assert.Equal(t, "10.0.0.1", status.ConnectionName)
+	assert.Equal(t, "RUNNING", status.Status)
+	assert.Equal(t, int64(42), status.Messages)
+	assert.Equal(t, int64(1024), status.BytesSent)
+	assert.Equal(t, int64(2048), status.BytesReceived)
+	assert.Equal(t, "2024-03-01 12:30:05", status.LastMessageTime)
+
+	assert.Nil(t, channelStatusFromResponse(&CommandResponse{}))
+}
+
+func TestChannelStatusString(t *testing.T) {
+	assert.Equal(t, "RETRYING", ChannelStatusString(ibmmq.MQCHS_RETRYING))
+	assert.Equal(t, "99", ChannelStatusString(99))
+}
+
+func TestIsCommandReason(t *testing.T) {
+	err := fmt.Errorf("inquiry failed: %w", &CommandError{
+		Command:  ibmmq.MQCMD_INQUIRE_CHANNEL_STATUS,
+		CompCode: ibmmq.MQCC_FAILED,
+		Reason:   ibmmq.MQRCCF_CHL_STATUS_NOT_FOUND,
+	})
+
+	assert.True(t, IsCommandReason(err, ibmmq.MQRCCF_CHL_STATUS_NOT_FOUND))
+	assert.False(t, IsCommandReason(err, ibmmq.MQRC_NOT_AUTHORIZED))
+	assert.False(t, IsCommandReason(fmt.Errorf("other"), ibmmq.MQRCCF_CHL_STATUS_NOT_FOUND))
+}
+
+func TestInquireChannelStatusRequiresConnection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logging.NewLogrus(logger))
+
+	_, err := client.InquireChannelStatus("*")
+	assert.Error(t, err)
+}
@@ -1,30 +1,96 @@
 package mqclient
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
 	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
-	"github.com/sirupsen/logrus"
 )
 
 // MQClient represents an IBM MQ client connection
 type MQClient struct {
 	config     *config.MQConfig
 	qmgr       ibmmq.MQQueueManager
+	acctQmgr   ibmmq.MQQueueManager // second connection for the accounting queue, if parallel
 	connected  bool
-	logger     *logrus.Logger
+	logger     logging.Logger
 	statsQueue ibmmq.MQObject
 	acctQueue  ibmmq.MQObject
+
+	connectionName string // entry of the connection name list connected to
+
+	namedQueues   map[string]*namedQueue
+	subscriptions map[string]*subscription // by topic string
+	buffers       *bufferPool
+
+	// Backout thresholds and queues, used when messages are got under syncpoint
+	statsBackout backoutPolicy
+	acctBackout  backoutPolicy
+}
+
+// ErrStopDrain is returned by the function passed to DrainMessages to end the drain after
+// the message it was passed, leaving the remaining messages on the queue
+var ErrStopDrain = errors.New("stop drain")
+
+// ConnectionError is returned when the connection to the queue manager cannot be established
+type ConnectionError struct {
+	QueueManager string
+	Err          error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("failed to connect to queue manager %s: %v", e.QueueManager, e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// QueueError is returned by DrainStatisticsAndAccounting for a queue that could not be
+// drained. The messages got from the queue before the failure were still passed on.
+type QueueError struct {
+	Type string // "stats" or "accounting"
+	Err  error
+}
+
+func (e *QueueError) Error() string {
+	return fmt.Sprintf("failed to get %s messages: %v", e.Type, e.Err)
+}
+
+func (e *QueueError) Unwrap() error {
+	return e.Err
+}
+
+// FailedQueues returns the types of the queues an error of DrainStatisticsAndAccounting
+// reports as failed
+func FailedQueues(err error) []string {
+	var failed []string
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else if err != nil {
+		errs = []error{err}
+	}
+	for _, e := range errs {
+		var queueErr *QueueError
+		if errors.As(e, &queueErr) {
+			failed = append(failed, queueErr.Type)
+		}
+	}
+	return failed
 }
 
 // NewMQClient creates a new IBM MQ client instance
-func NewMQClient(cfg *config.MQConfig, logger *logrus.Logger) *MQClient {
+func NewMQClient(cfg *config.MQConfig, logger logging.Logger) *MQClient {
 	return &MQClient{
 		config:    cfg,
 		connected: false,
 		logger:    logger,
+		buffers:   newBufferPool(),
 	}
 }
 
@@ -34,28 +100,30 @@ func (c *MQClient) Connect() error {
 		return nil
 	}
 
-	c.logger.WithFields(logrus.Fields{
-		"queue_manager":   c.config.QueueManager,
-		"channel":         c.config.Channel,
-		"connection_name": c.config.GetConnectionName(),
+	c.logger.WithFields(logging.Fields{
+		"queue_manager":    c.config.QueueManager,
+		"channel":          c.config.Channel,
+		"connection_name":  c.config.GetConnectionName(),
+		"application_name": c.config.ApplicationName,
 	}).Info("Connecting to IBM MQ")
 
 	// Create connection options
 	cno := ibmmq.NewMQCNO()
 	cno.Options = ibmmq.MQCNO_CLIENT_BINDING
+	cno.ApplName = c.config.ApplicationName
 
 	// Set channel definition
 	cd := ibmmq.NewMQCD()
 	cd.ChannelName = c.config.Channel
-	cd.ConnectionName = c.config.GetConnectionName()
 	// Note: ChannelType is not available in client MQCD structure
 
-	// Set security options if SSL/TLS is configured
-	if c.config.CipherSpec != "" {
-		cd.SSLCipherSpec = c.config.CipherSpec
-		// Note: SSLKeyRepository is not available in client MQCD structure
-		// SSL configuration is handled differently in client connections
-	}
+	// TLS is negotiated on the channel with its cipher spec, checking the queue manager's
+	// certificate against the peer name and presenting the labelled client certificate,
+	// from the key repository and in the FIPS mode of the connection's security options
+	cd.SSLCipherSpec = c.config.CipherSpec
+	cd.SSLPeerName = c.config.SSLPeerName
+	cd.CertificateLabel = c.config.CertificateLabel
+	cno.SSLConfig = c.securityOptions()
 
 	cno.ClientConn = cd
 
@@ -68,19 +136,65 @@ func (c *MQClient) Connect() error {
 		cno.SecurityParms = csp
 	}
 
-	// Connect to queue manager
-	qmgr, err := ibmmq.Connx(c.config.QueueManager, cno)
+	// The entries of a connection name list are tried in order, on every connection and
+	// reconnection, so the collector follows a multi-instance or HA queue manager to the
+	// instance that is active
+	names := c.config.ConnectionNames()
+	if len(names) == 0 {
+		names = []string{""} // left to the MQSERVER environment variable or channel table
+	}
+	var err error
+	for i, name := range names {
+		cd.ConnectionName = name
+		if err = c.connectTo(cno); err == nil {
+			c.connectionName = name
+			break
+		}
+		if i < len(names)-1 {
+			c.logger.WithError(err).WithField("connection_name", name).Warn("Failed to connect, trying the next connection name")
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to connect to queue manager %s: %w", c.config.QueueManager, err)
+		return &ConnectionError{QueueManager: c.config.QueueManager, Err: err}
 	}
 
-	c.qmgr = qmgr
 	c.connected = true
 
-	c.logger.Info("Successfully connected to IBM MQ")
+	c.logger.WithField("connection_name", c.connectionName).Info("Successfully connected to IBM MQ")
+	return nil
+}
+
+// connectTo connects to the queue manager with the connection options, on two
+// connections when draining in parallel
+func (c *MQClient) connectTo(cno *ibmmq.MQCNO) error {
+	qmgr, err := ibmmq.Connx(c.config.QueueManager, cno)
+	if err != nil {
+		return err
+	}
+
+	// A connection handle serialises its calls, so draining the queues in parallel needs
+	// the accounting queue on a connection of its own
+	if c.config.ParallelDrain {
+		acctQmgr, err := ibmmq.Connx(c.config.QueueManager, cno)
+		if err != nil {
+			qmgr.Disc()
+			return err
+		}
+		c.acctQmgr = acctQmgr
+	} else {
+		c.acctQmgr = qmgr
+	}
+
+	c.qmgr = qmgr
 	return nil
 }
 
+// ConnectionName returns the entry of the connection name list the client is connected
+// through
+func (c *MQClient) ConnectionName() string {
+	return c.connectionName
+}
+
 // Disconnect closes the connection to IBM MQ
 func (c *MQClient) Disconnect() error {
 	if !c.connected {
@@ -96,60 +210,136 @@ func (c *MQClient) Disconnect() error {
 	if c.acctQueue.GetValue() != 0 {
 		c.acctQueue.Close(0)
 	}
+	for name, queue := range c.namedQueues {
+		queue.object.Close(0)
+		delete(c.namedQueues, name)
+	}
+	for topic, sub := range c.subscriptions {
+		sub.queue.Close(0)
+		sub.object.Close(0)
+		delete(c.subscriptions, topic)
+	}
+
+	if c.config.ParallelDrain {
+		if err := c.acctQmgr.Disc(); err != nil {
+			c.logger.WithError(err).Warn("Error disconnecting the accounting queue connection")
+		}
+	}
 
-	// Disconnect from queue manager
+	// Disconnect from queue manager. A broken connection fails to disconnect but is gone
+	// all the same, so the client counts as disconnected either way.
 	err := c.qmgr.Disc()
+	c.connected = false
+	c.statsQueue, c.acctQueue = ibmmq.MQObject{}, ibmmq.MQObject{}
 	if err != nil {
 		c.logger.WithError(err).Error("Error disconnecting from queue manager")
 		return err
 	}
 
-	c.connected = false
 	c.logger.Info("Successfully disconnected from IBM MQ")
 	return nil
 }
 
+// inputOpenOptions returns the options the statistics and accounting queues are opened
+// with. Under syncpoint they are opened for inquire as well, to read their backout policy.
+// In browse mode they are opened for browse only, so other consumers keep their messages.
+func (c *MQClient) inputOpenOptions() int32 {
+	if c.config.Browse {
+		return ibmmq.MQOO_BROWSE | ibmmq.MQOO_FAIL_IF_QUIESCING
+	}
+	openOptions := ibmmq.MQOO_INPUT_AS_Q_DEF | ibmmq.MQOO_FAIL_IF_QUIESCING
+	if c.config.Syncpoint {
+		openOptions |= ibmmq.MQOO_INQUIRE
+	}
+	return openOptions
+}
+
+// getOptions returns the options messages are got from the statistics and accounting
+// queues with, waiting up to the get wait interval for a message when one is set. In
+// browse mode each get moves the browse cursor to the next message,
+// which stays on the queue; the cursor keeps its place from one drain to the next
+// while the queue is open.
+func (c *MQClient) getOptions() *ibmmq.MQGMO {
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT
+	if wait := c.config.GetWaitInterval; wait > 0 {
+		gmo.Options |= ibmmq.MQGMO_WAIT
+		gmo.WaitInterval = int32(wait / time.Millisecond)
+	}
+	if c.config.Syncpoint {
+		gmo.Options |= ibmmq.MQGMO_SYNCPOINT
+	}
+	if c.config.Browse {
+		gmo.Options |= ibmmq.MQGMO_BROWSE_NEXT
+	}
+	return gmo
+}
+
+// underCursor turns a browse for the next message into one of the message under the
+// cursor, where a browse that failed for the size of its buffer left the cursor
+func underCursor(gmo *ibmmq.MQGMO) {
+	if gmo.Options&ibmmq.MQGMO_BROWSE_NEXT != 0 {
+		gmo.Options = gmo.Options&^ibmmq.MQGMO_BROWSE_NEXT | ibmmq.MQGMO_BROWSE_MSG_UNDER_CURSOR
+	}
+}
+
 // OpenStatsQueue opens the statistics queue for reading
 func (c *MQClient) OpenStatsQueue(queueName string) error {
 	if !c.connected {
-		return fmt.Errorf("not connected to queue manager")
+		return ErrNotConnected
 	}
 
 	mqod := ibmmq.NewMQOD()
-	openOptions := ibmmq.MQOO_INPUT_AS_Q_DEF | ibmmq.MQOO_FAIL_IF_QUIESCING
+	openOptions := c.inputOpenOptions()
 
 	mqod.ObjectType = ibmmq.MQOT_Q
 	mqod.ObjectName = queueName
+	mqod.SelectionString = c.config.Selector
 
 	queue, err := c.qmgr.Open(mqod, openOptions)
 	if err != nil {
-		return fmt.Errorf("failed to open statistics queue %s: %w", queueName, err)
+		return wrapError(err, "open statistics queue %s", queueName)
 	}
 
 	c.statsQueue = queue
-	c.logger.WithField("queue", queueName).Info("Opened statistics queue")
+	if c.config.Syncpoint {
+		c.statsBackout = c.inquireBackout(queue, queueName)
+	}
+	c.logger.WithFields(logging.Fields{
+		"queue":    queueName,
+		"browse":   c.config.Browse,
+		"selector": c.config.Selector,
+	}).Info("Opened statistics queue")
 	return nil
 }
 
 // OpenAccountingQueue opens the accounting queue for reading
 func (c *MQClient) OpenAccountingQueue(queueName string) error {
 	if !c.connected {
-		return fmt.Errorf("not connected to queue manager")
+		return ErrNotConnected
 	}
 
 	mqod := ibmmq.NewMQOD()
-	openOptions := ibmmq.MQOO_INPUT_AS_Q_DEF | ibmmq.MQOO_FAIL_IF_QUIESCING
+	openOptions := c.inputOpenOptions()
 
 	mqod.ObjectType = ibmmq.MQOT_Q
 	mqod.ObjectName = queueName
+	mqod.SelectionString = c.config.Selector
 
-	queue, err := c.qmgr.Open(mqod, openOptions)
+	queue, err := c.acctQmgr.Open(mqod, openOptions)
 	if err != nil {
-		return fmt.Errorf("failed to open accounting queue %s: %w", queueName, err)
+		return wrapError(err, "open accounting queue %s", queueName)
 	}
 
 	c.acctQueue = queue
-	c.logger.WithField("queue", queueName).Info("Opened accounting queue")
+	if c.config.Syncpoint {
+		c.acctBackout = c.inquireBackout(queue, queueName)
+	}
+	c.logger.WithFields(logging.Fields{
+		"queue":    queueName,
+		"browse":   c.config.Browse,
+		"selector": c.config.Selector,
+	}).Info("Opened accounting queue")
 	return nil
 }
 
@@ -172,29 +362,29 @@ func (c *MQClient) GetMessage(queueType string) (*ibmmq.MQMD, []byte, error) {
 
 	// Create message descriptor
 	mqmd := ibmmq.NewMQMD()
+	c.applySelectors(mqmd)
 
 	// Create get message options
-	gmo := ibmmq.NewMQGMO()
-	gmo.Options = ibmmq.MQGMO_NO_WAIT | ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT
-	gmo.WaitInterval = 1000 // 1 second wait
+	gmo := c.getOptions()
 
 	// Get message
-	buffer := make([]byte, 100*1024) // 100KB buffer
-	datalen, err := queue.Get(mqmd, gmo, buffer)
-
+	msgData, err := c.getWithBuffer(queue, mqmd, gmo)
 	if err != nil {
-		mqret := err.(*ibmmq.MQReturn)
-		if mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+		var mqret *ibmmq.MQReturn
+		if errors.As(err, &mqret) && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
 			// No message available, not an error
 			return nil, nil, nil
 		}
-		return nil, nil, fmt.Errorf("failed to get message from %s queue: %w", queueType, err)
+		if errors.As(err, &mqret) && mqret.MQRC == ibmmq.MQRC_SYNCPOINT_LIMIT_REACHED {
+			// The unit of work is full; the remaining messages wait for the next one
+			c.logger.WithField("queue_type", queueType).Warn("Unit of work holds the most messages allowed, leaving the rest on the queue")
+			return nil, nil, nil
+		}
+		return nil, nil, wrapError(err, "get message from %s queue", queueType)
 	}
+	datalen := len(msgData)
 
-	// Return actual message data
-	msgData := buffer[:datalen]
-
-	c.logger.WithFields(logrus.Fields{
+	c.logger.WithFields(logging.Fields{
 		"queue_type":   queueType,
 		"message_id":   fmt.Sprintf("%x", mqmd.MsgId),
 		"message_size": datalen,
@@ -205,20 +395,133 @@ func (c *MQClient) GetMessage(queueType string) (*ibmmq.MQMD, []byte, error) {
 	return mqmd, msgData, nil
 }
 
+// getWithBuffer gets a message into a pooled buffer and returns a copy of its bytes. A
+// message larger than the buffer stays on the queue, so it is got again with a buffer
+// of its size, up to the maximum message length. A message that could not be converted,
+// such as one that converting would make larger than the buffer, is got all the same
+// and returned as it was put.
+func (c *MQClient) getWithBuffer(queue ibmmq.MQObject, mqmd *ibmmq.MQMD, gmo *ibmmq.MQGMO) ([]byte, error) {
+	buffer := c.buffers.get(0)
+	defer c.buffers.put(buffer)
+
+	datalen, err := queue.Get(mqmd, gmo, *buffer)
+	var mqret *ibmmq.MQReturn
+	if errors.As(err, &mqret) && mqret.MQRC == ibmmq.MQRC_TRUNCATED_MSG_FAILED {
+		underCursor(gmo)
+		if limit := c.config.MaxMessageLength; limit > 0 && datalen > limit {
+			return c.getTruncated(queue, mqmd, gmo, datalen, err)
+		}
+		c.buffers.observe(datalen)
+		larger := c.buffers.get(datalen)
+		defer c.buffers.put(larger)
+		buffer = larger
+		datalen, err = queue.Get(mqmd, gmo, *buffer)
+	}
+	if reason, ok := notConverted(err); ok {
+		c.logger.WithFields(logging.Fields{
+			"message_id":   fmt.Sprintf("%x", mqmd.MsgId),
+			"message_size": datalen,
+			"reason":       ReasonString(reason),
+		}).Warn("Got message without converting it")
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.buffers.observe(datalen)
+	return append([]byte(nil), (*buffer)[:datalen]...), nil
+}
+
+// notConverted reports whether a get completed with a warning that the message was not
+// converted, returning the reason. The message is got nonetheless, so returning the
+// error would lose it.
+func notConverted(err error) (int32, bool) {
+	var mqret *ibmmq.MQReturn
+	if !errors.As(err, &mqret) || mqret.MQCC != ibmmq.MQCC_WARNING {
+		return 0, false
+	}
+	switch mqret.MQRC {
+	case ibmmq.MQRC_CONVERTED_MSG_TOO_BIG, ibmmq.MQRC_NOT_CONVERTED, ibmmq.MQRC_FORMAT_ERROR,
+		ibmmq.MQRC_SOURCE_CCSID_ERROR, ibmmq.MQRC_TARGET_CCSID_ERROR,
+		ibmmq.MQRC_SOURCE_INTEGER_ENC_ERROR, ibmmq.MQRC_TARGET_INTEGER_ENC_ERROR:
+		return mqret.MQRC, true
+	}
+	return 0, false
+}
+
+// getTruncated gets the first MaxMessageLength bytes of a message of length bytes,
+// removing it from the queue. Without AcceptTruncated, which the configuration requires
+// along with a maximum message length, the failed get is returned with its reason code.
+func (c *MQClient) getTruncated(queue ibmmq.MQObject, mqmd *ibmmq.MQMD, gmo *ibmmq.MQGMO, length int, getErr error) ([]byte, error) {
+	limit := c.config.MaxMessageLength
+	if !c.config.AcceptTruncated {
+		return nil, wrapError(getErr, "get message of %d bytes, over the maximum message length of %d bytes", length, limit)
+	}
+
+	gmo.Options |= ibmmq.MQGMO_ACCEPT_TRUNCATED_MSG
+	buffer := make([]byte, limit)
+	_, err := queue.Get(mqmd, gmo, buffer)
+	var mqret *ibmmq.MQReturn
+	if err != nil && !(errors.As(err, &mqret) && mqret.MQRC == ibmmq.MQRC_TRUNCATED_MSG_ACCEPTED) {
+		return nil, wrapError(err, "get truncated message of %d bytes", length)
+	}
+
+	c.logger.WithFields(logging.Fields{
+		"message_size": length,
+		"kept_bytes":   limit,
+	}).Warn("Accepted truncated message")
+	return buffer, nil
+}
+
 // GetAllMessages retrieves all available messages from the specified queue
 func (c *MQClient) GetAllMessages(queueType string) ([]*MQMessage, error) {
 	var messages []*MQMessage
+	err := c.DrainMessages(queueType, func(msg *MQMessage) error {
+		messages = append(messages, msg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// DrainMessages gets every available message from the specified queue and passes each to
+// fn, stopping at the first error. fn returns ErrStopDrain to stop without an error.
+// Records that IBM MQ split over several messages are joined and passed on as one. Under
+// syncpoint, messages that reached the backout threshold of the queue are moved to its
+// backout queue instead. The drain ends after MaxEmptyGets gets in a row find no message.
+func (c *MQClient) DrainMessages(queueType string, fn func(*MQMessage) error) (err error) {
+	count, backedOut, emptyGets := 0, 0, 0
+	pacer := newPacer(c.config.MaxGetRate, c.config.GetBurst)
+	var assembler partAssembler
+
+	// Parts held when the drain ends are already off the queue, so they are passed on
+	// whatever stopped it
+	defer func() {
+		parts, cut := assembler.flush()
+		c.warnCutRecord(queueType, cut)
+		if flushErr := passOn(parts, fn); err == nil {
+			err = flushErr
+		}
+	}()
 
 	for {
 		mqmd, data, err := c.GetMessage(queueType)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		// No more messages
 		if mqmd == nil {
-			break
+			emptyGets++
+			if emptyGets >= c.config.MaxEmptyGets {
+				break
+			}
+			continue
 		}
+		emptyGets = 0
 
 		msg := &MQMessage{
 			MD:   mqmd,
@@ -226,18 +529,96 @@ func (c *MQClient) GetAllMessages(queueType string) ([]*MQMessage, error) {
 			Type: queueType,
 		}
 
-		messages = append(messages, msg)
-
-		// Add a small delay to prevent tight loop
-		time.Sleep(10 * time.Millisecond)
+		count++
+		if c.config.Syncpoint && c.backOutMessage(queueType, msg) {
+			backedOut++
+			continue
+		}
+		complete, cut := assembler.add(msg)
+		c.warnCutRecord(queueType, cut)
+		stop := false
+		for _, msg := range complete {
+			if err := fn(msg); errors.Is(err, ErrStopDrain) {
+				stop = true
+			} else if err != nil {
+				return err
+			}
+		}
+		if stop {
+			break
+		}
+		pacer.wait()
 	}
 
-	c.logger.WithFields(logrus.Fields{
+	c.logger.WithFields(logging.Fields{
 		"queue_type": queueType,
-		"count":      len(messages),
+		"count":      count,
+		"joined":     assembler.joined,
+		"backed_out": backedOut,
+		"paced":      pacer.paused,
 	}).Info("Retrieved messages from queue")
 
-	return messages, nil
+	return nil
+}
+
+// warnCutRecord logs the parts of a record that ended before its last part
+func (c *MQClient) warnCutRecord(queueType string, parts int) {
+	if parts == 0 {
+		return
+	}
+	c.logger.WithFields(logging.Fields{
+		"queue_type": queueType,
+		"parts":      parts,
+	}).Warn("Record split over several messages is incomplete, passing on its parts separately")
+}
+
+// GetStatisticsAndAccounting drains the statistics queue and then the accounting queue,
+// or both at once when parallel draining is enabled. When a queue fails, the messages
+// got from the other queue and from it before the failure are still returned with the
+// error.
+func (c *MQClient) GetStatisticsAndAccounting() (stats, accounting []*MQMessage, err error) {
+	err = c.DrainStatisticsAndAccounting(func(msg *MQMessage) error {
+		// Each queue is drained by a single goroutine, so each slice has one writer
+		if msg.Type == "stats" {
+			stats = append(stats, msg)
+		} else {
+			accounting = append(accounting, msg)
+		}
+		return nil
+	})
+	return stats, accounting, err
+}
+
+// DrainStatisticsAndAccounting passes every message of the statistics queue and then the
+// accounting queue to fn. When parallel draining is enabled both queues are drained at
+// once, so fn must be safe for concurrent use. The queues fail independently: a queue
+// that cannot be drained does not stop the other, and the error holds a *QueueError
+// for each queue that failed.
+func (c *MQClient) DrainStatisticsAndAccounting(fn func(*MQMessage) error) error {
+	if !c.config.ParallelDrain {
+		statsErr := c.drainQueue("stats", fn)
+		return errors.Join(statsErr, c.drainQueue("accounting", fn))
+	}
+
+	var acctErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		acctErr = c.drainQueue("accounting", fn)
+	}()
+	statsErr := c.drainQueue("stats", fn)
+	wg.Wait()
+
+	return errors.Join(statsErr, acctErr)
+}
+
+// drainQueue drains a queue as DrainMessages, returning a failure as a *QueueError
+func (c *MQClient) drainQueue(queueType string, fn func(*MQMessage) error) error {
+	if err := c.DrainMessages(queueType, fn); err != nil {
+		return &QueueError{Type: queueType, Err: err}
+	}
+	return nil
 }
 
 // IsConnected returns true if connected to IBM MQ
@@ -252,6 +633,15 @@ type MQMessage struct {
 	Type string // "stats" or "accounting"
 }
 
+// Payloads returns the data of each message, in order
+func Payloads(messages []*MQMessage) [][]byte {
+	payloads := make([][]byte, len(messages))
+	for i, msg := range messages {
+		payloads[i] = msg.Data
+	}
+	return payloads
+}
+
 // GetTimestamp returns the message timestamp
 func (m *MQMessage) GetTimestamp() time.Time {
 	// Convert MQ timestamp to Go time
@@ -1,9 +1,14 @@
 package mqclient
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,7 +22,7 @@ func TestNewMQClient(t *testing.T) {
 		Host:           "localhost",
 		Port:           1414,
 	}
-	logger := logrus.New()
+	logger := logging.NewLogrus(logrus.New())
 
 	client := NewMQClient(cfg, logger)
 
@@ -81,7 +86,7 @@ func TestMQClientConfiguration(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client := NewMQClient(tt.config, logger)
+			client := NewMQClient(tt.config, logging.NewLogrus(logger))
 			require.NotNil(t, client)
 
 			// Test that configuration is stored correctly
@@ -107,7 +112,7 @@ func TestMQClientConnectionState(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	client := NewMQClient(cfg, logger)
+	client := NewMQClient(cfg, logging.NewLogrus(logger))
 
 	// Initially not connected
 	assert.False(t, client.IsConnected())
@@ -134,7 +139,7 @@ func TestMQClientQueueOperations(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	client := NewMQClient(cfg, logger)
+	client := NewMQClient(cfg, logging.NewLogrus(logger))
 
 	// Test opening queues without connection (should fail)
 	err := client.OpenStatsQueue("SYSTEM.ADMIN.STATISTICS.QUEUE")
@@ -164,7 +169,7 @@ func TestMQClientMessageTypes(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
 
-	client := NewMQClient(cfg, logger)
+	client := NewMQClient(cfg, logging.NewLogrus(logger))
 
 	// Test invalid message type
 	messages, err := client.GetAllMessages("invalid")
@@ -180,6 +185,81 @@ func TestMQClientMessageTypes(t *testing.T) {
 	}
 }
 
+func TestMQClientGetStatisticsAndAccounting(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	for _, parallel := range []bool{false, true} {
+		cfg := &config.MQConfig{
+			QueueManager:   "TESTQM",
+			Channel:        "TEST.SVRCONN",
+			ConnectionName: "localhost(1414)",
+			ParallelDrain:  parallel,
+		}
+		client := NewMQClient(cfg, logging.NewLogrus(logger))
+
+		// Neither queue is open, so both drains fail
+		stats, accounting, err := client.GetStatisticsAndAccounting()
+		assert.Error(t, err, "parallel %v", parallel)
+		assert.ElementsMatch(t, []string{"stats", "accounting"}, FailedQueues(err))
+		assert.Nil(t, stats)
+		assert.Nil(t, accounting)
+	}
+}
+
+func TestBrowseOptions(t *testing.T) {
+	logger := logging.NewLogrus(logrus.New())
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logger)
+	assert.NotZero(t, client.inputOpenOptions()&ibmmq.MQOO_INPUT_AS_Q_DEF)
+	assert.Zero(t, client.getOptions().Options&ibmmq.MQGMO_BROWSE_NEXT)
+
+	client = NewMQClient(&config.MQConfig{QueueManager: "TESTQM", Browse: true}, logger)
+	assert.Equal(t, ibmmq.MQOO_BROWSE|ibmmq.MQOO_FAIL_IF_QUIESCING, client.inputOpenOptions())
+	gmo := client.getOptions()
+	assert.NotZero(t, gmo.Options&ibmmq.MQGMO_BROWSE_NEXT)
+	assert.Zero(t, gmo.Options&ibmmq.MQGMO_SYNCPOINT)
+
+	// A browse retried with a larger buffer gets the message the cursor was left on
+	underCursor(gmo)
+	assert.Zero(t, gmo.Options&ibmmq.MQGMO_BROWSE_NEXT)
+	assert.NotZero(t, gmo.Options&ibmmq.MQGMO_BROWSE_MSG_UNDER_CURSOR)
+	assert.NotZero(t, gmo.Options&ibmmq.MQGMO_CONVERT)
+}
+
+func TestGetWaitInterval(t *testing.T) {
+	logger := logging.NewLogrus(logrus.New())
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logger)
+	assert.Zero(t, client.getOptions().Options&ibmmq.MQGMO_WAIT)
+
+	client = NewMQClient(&config.MQConfig{QueueManager: "TESTQM", GetWaitInterval: 250 * time.Millisecond}, logger)
+	gmo := client.getOptions()
+	assert.NotZero(t, gmo.Options&ibmmq.MQGMO_WAIT)
+	assert.Equal(t, int32(250), gmo.WaitInterval)
+}
+
+func TestNotConverted(t *testing.T) {
+	reason, ok := notConverted(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_WARNING, MQRC: ibmmq.MQRC_CONVERTED_MSG_TOO_BIG})
+	assert.True(t, ok)
+	assert.Equal(t, ibmmq.MQRC_CONVERTED_MSG_TOO_BIG, reason)
+
+	// A message too large for the buffer is left on the queue to be got again
+	_, ok = notConverted(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_WARNING, MQRC: ibmmq.MQRC_TRUNCATED_MSG_FAILED})
+	assert.False(t, ok)
+	_, ok = notConverted(&ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_NO_MSG_AVAILABLE})
+	assert.False(t, ok)
+	_, ok = notConverted(nil)
+	assert.False(t, ok)
+}
+
+func TestFailedQueues(t *testing.T) {
+	assert.Empty(t, FailedQueues(nil))
+	assert.Equal(t, []string{"accounting"}, FailedQueues(&QueueError{Type: "accounting", Err: errors.New("MQRC_Q_MGR_NOT_AVAILABLE")}))
+	assert.Equal(t, []string{"stats"}, FailedQueues(errors.Join(nil, &QueueError{Type: "stats", Err: errors.New("MQRC_GET_INHIBITED")})))
+	assert.Empty(t, FailedQueues(errors.New("other")))
+}
+
 func TestMQClientConfigurationValidation(t *testing.T) {
 	logger := logrus.New()
 
@@ -207,7 +287,7 @@ func TestMQClientConfigurationValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Current implementation may handle nil gracefully
-			client := NewMQClient(tt.config, logger)
+			client := NewMQClient(tt.config, logging.NewLogrus(logger))
 			if tt.valid {
 				assert.NotNil(t, client)
 			} else {
@@ -229,12 +309,12 @@ func TestMQClientLogging(t *testing.T) {
 
 	tests := []struct {
 		name   string
-		logger *logrus.Logger
+		logger logging.Logger
 		valid  bool
 	}{
 		{
 			name:   "valid logger",
-			logger: logrus.New(),
+			logger: logging.NewLogrus(logrus.New()),
 			valid:  true,
 		},
 		{
@@ -260,3 +340,14 @@ func TestMQClientLogging(t *testing.T) {
 		})
 	}
 }
+
+func TestConnectionError(t *testing.T) {
+	cause := errors.New("MQCONNX: MQCC = MQCC_FAILED [2] MQRC = MQRC_HOST_NOT_AVAILABLE [2538]")
+	err := fmt.Errorf("collector failed: %w", &ConnectionError{QueueManager: "QM1", Err: cause})
+
+	var connErr *ConnectionError
+	require.True(t, errors.As(err, &connErr))
+	assert.Equal(t, "QM1", connErr.QueueManager)
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), "failed to connect to queue manager QM1")
+}
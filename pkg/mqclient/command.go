@@ -0,0 +1,357 @@
+package mqclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Default queues used for PCF administration commands
+const (
+	DefaultCommandQueue     = "SYSTEM.ADMIN.COMMAND.QUEUE"
+	DefaultReplyModelQueue  = "SYSTEM.DEFAULT.MODEL.QUEUE"
+	commandReplyQueuePrefix = "IBMMQSTAT.REPLY.*"
+	commandWaitInterval     = 5 * time.Second // default wait for each command response
+	commandReplyBufferSize  = 100 * 1024
+)
+
+// CommandQueue returns the queue PCF administration commands are put to
+func (c *MQClient) CommandQueue() string {
+	if c.config.CommandQueue != "" {
+		return c.config.CommandQueue
+	}
+	return DefaultCommandQueue
+}
+
+// replyModelQueue returns the model queue the reply queue of each command is created from
+func (c *MQClient) replyModelQueue() string {
+	if c.config.ReplyModelQueue != "" {
+		return c.config.ReplyModelQueue
+	}
+	return DefaultReplyModelQueue
+}
+
+// commandWait returns how long to wait for each command response, in milliseconds
+func (c *MQClient) commandWait() int32 {
+	wait := c.config.CommandTimeout
+	if wait <= 0 {
+		wait = commandWaitInterval
+	}
+	return int32(wait / time.Millisecond)
+}
+
+// CommandResponse is a single PCF response message to an administration command
+type CommandResponse struct {
+	Command    int32
+	CompCode   int32
+	Reason     int32
+	Parameters []*ibmmq.PCFParameter
+}
+
+// GetString returns the value of a string parameter in the response
+func (r *CommandResponse) GetString(parameter int32) (string, bool) {
+	for _, p := range r.Parameters {
+		if p.Parameter == parameter && p.Type == ibmmq.MQCFT_STRING && len(p.String) > 0 {
+			return p.String[0], true
+		}
+	}
+	return "", false
+}
+
+// GetInt returns the value of an integer parameter in the response
+func (r *CommandResponse) GetInt(parameter int32) (int64, bool) {
+	for _, p := range r.Parameters {
+		if p.Parameter == parameter && (p.Type == ibmmq.MQCFT_INTEGER || p.Type == ibmmq.MQCFT_INTEGER64) && len(p.Int64Value) > 0 {
+			return p.Int64Value[0], true
+		}
+	}
+	return 0, false
+}
+
+// CommandError is returned when the command server reports a failure for a PCF command
+type CommandError struct {
+	Command  int32
+	CompCode int32
+	Reason   int32
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("command %d failed: completion code %d, reason %d (%s)",
+		e.Command, e.CompCode, e.Reason, ibmmq.MQItoString("RC", int(e.Reason)))
+}
+
+// IsCommandReason reports whether err is a CommandError with the given reason code
+func IsCommandReason(err error, reason int32) bool {
+	var cmdErr *CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Reason == reason
+}
+
+// NewStringParameter creates a PCF string parameter for a command
+func NewStringParameter(parameter int32, value string) *ibmmq.PCFParameter {
+	return &ibmmq.PCFParameter{
+		Type:      ibmmq.MQCFT_STRING,
+		Parameter: parameter,
+		String:    []string{value},
+	}
+}
+
+// NewIntParameter creates a PCF integer parameter for a command
+func NewIntParameter(parameter int32, value int32) *ibmmq.PCFParameter {
+	return &ibmmq.PCFParameter{
+		Type:       ibmmq.MQCFT_INTEGER,
+		Parameter:  parameter,
+		Int64Value: []int64{int64(value)},
+	}
+}
+
+// ExecuteCommand puts a PCF administration command on the command queue and
+// returns every response message correlated to it. An error is returned if the
+// command server reports a failure in any response.
+func (c *MQClient) ExecuteCommand(command int32, params []*ibmmq.PCFParameter) ([]*CommandResponse, error) {
+	if !c.connected {
+		return nil, ErrNotConnected
+	}
+
+	// Open the command queue for output
+	cmdod := ibmmq.NewMQOD()
+	cmdod.ObjectType = ibmmq.MQOT_Q
+	cmdod.ObjectName = c.CommandQueue()
+
+	cmdQueue, err := c.qmgr.Open(cmdod, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return nil, wrapError(err, "open command queue %s", cmdod.ObjectName)
+	}
+	defer cmdQueue.Close(0)
+
+	// Create a temporary dynamic queue to receive the responses
+	replyod := ibmmq.NewMQOD()
+	replyod.ObjectType = ibmmq.MQOT_Q
+	replyod.ObjectName = c.replyModelQueue()
+	replyod.DynamicQName = commandReplyQueuePrefix
+
+	replyQueue, err := c.qmgr.Open(replyod, ibmmq.MQOO_INPUT_EXCLUSIVE|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return nil, wrapError(err, "open reply queue from %s", replyod.ObjectName)
+	}
+	defer replyQueue.Close(0)
+
+	// Build the command message
+	cfh := ibmmq.NewMQCFH()
+	cfh.Command = command
+	cfh.ParameterCount = int32(len(params))
+
+	buffer := cfh.Bytes()
+	for _, param := range params {
+		buffer = append(buffer, param.Bytes()...)
+	}
+
+	putmqmd := ibmmq.NewMQMD()
+	putmqmd.Format = ibmmq.MQFMT_ADMIN
+	putmqmd.MsgType = ibmmq.MQMT_REQUEST
+	putmqmd.Report = ibmmq.MQRO_PASS_DISCARD_AND_EXPIRY
+	putmqmd.ReplyToQ = replyQueue.Name
+
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_NO_SYNCPOINT | ibmmq.MQPMO_NEW_MSG_ID | ibmmq.MQPMO_NEW_CORREL_ID | ibmmq.MQPMO_FAIL_IF_QUIESCING
+
+	if err := cmdQueue.Put(putmqmd, pmo, buffer); err != nil {
+		return nil, wrapError(err, "put command %d", command)
+	}
+
+	c.logger.WithFields(logging.Fields{
+		"command":     command,
+		"parameters":  len(params),
+		"reply_queue": replyQueue.Name,
+	}).Debug("Sent PCF command")
+
+	// Collect responses until the last one is flagged
+	var responses []*CommandResponse
+	replyBuffer := make([]byte, commandReplyBufferSize)
+
+	for {
+		getmqmd := ibmmq.NewMQMD()
+		getmqmd.CorrelId = putmqmd.MsgId
+
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options = ibmmq.MQGMO_WAIT | ibmmq.MQGMO_NO_SYNCPOINT | ibmmq.MQGMO_CONVERT | ibmmq.MQGMO_FAIL_IF_QUIESCING
+		gmo.MatchOptions = ibmmq.MQMO_MATCH_CORREL_ID
+		gmo.WaitInterval = c.commandWait()
+
+		// A response larger than the buffer stays on the reply queue and is got again
+		// with a buffer of its size
+		datalen, err := replyQueue.Get(getmqmd, gmo, replyBuffer)
+		var mqret *ibmmq.MQReturn
+		if errors.As(err, &mqret) && mqret.MQRC == ibmmq.MQRC_TRUNCATED_MSG_FAILED {
+			replyBuffer = make([]byte, datalen)
+			datalen, err = replyQueue.Get(getmqmd, gmo, replyBuffer)
+		}
+		if err != nil {
+			return responses, wrapError(err, "get response to command %d", command)
+		}
+
+		response, last := parseCommandResponse(replyBuffer[:datalen])
+		responses = append(responses, response)
+
+		if last {
+			break
+		}
+	}
+
+	for _, response := range responses {
+		if response.CompCode != ibmmq.MQCC_OK {
+			return responses, &CommandError{Command: command, CompCode: response.CompCode, Reason: response.Reason}
+		}
+	}
+
+	return responses, nil
+}
+
+// parseCommandResponse decodes a PCF response message and reports whether it is the last one
+func parseCommandResponse(data []byte) (*CommandResponse, bool) {
+	cfh, offset := ibmmq.ReadPCFHeader(data)
+
+	response := &CommandResponse{
+		Command:  cfh.Command,
+		CompCode: cfh.CompCode,
+		Reason:   cfh.Reason,
+	}
+
+	for i := int32(0); i < cfh.ParameterCount && offset < len(data); i++ {
+		param, consumed := ibmmq.ReadPCFParameter(data[offset:])
+		if consumed <= 0 {
+			break
+		}
+		response.Parameters = append(response.Parameters, param)
+		offset += consumed
+	}
+
+	return response, cfh.Control == ibmmq.MQCFC_LAST
+}
+
+// QueueResetStatistics holds the values returned by a Reset Queue Statistics command
+type QueueResetStatistics struct {
+	QueueName      string `json:"queue_name"`
+	HighDepth      int64  `json:"high_depth"`
+	EnqueueCount   int64  `json:"enqueue_count"`
+	DequeueCount   int64  `json:"dequeue_count"`
+	TimeSinceReset int64  `json:"time_since_reset"`
+}
+
+// ResetError is returned by ResetQueueStatistics when the command server fails to reset
+// some of the queues, along with the statistics of the queues it did reset
+type ResetError struct {
+	QueuePattern string
+	Queues       []string // names of the queues that failed, where the responses give them
+	Err          error
+}
+
+func (e *ResetError) Error() string {
+	if len(e.Queues) == 0 {
+		return fmt.Sprintf("failed to reset statistics for some queues matching %s: %v", e.QueuePattern, e.Err)
+	}
+	return fmt.Sprintf("failed to reset statistics for queues %s: %v", strings.Join(e.Queues, ", "), e.Err)
+}
+
+func (e *ResetError) Unwrap() error {
+	return e.Err
+}
+
+// ResetQueueStatistics issues MQCMD_RESET_Q_STATS for all queues matching the
+// (possibly generic) queue name and returns the statistics reported for each. The
+// statistics of a queue are reset on the queue manager as they are reported, so when
+// some of the queues fail the others are still returned, with a *ResetError.
+func (c *MQClient) ResetQueueStatistics(queuePattern string) ([]*QueueResetStatistics, error) {
+	responses, err := c.ExecuteCommand(ibmmq.MQCMD_RESET_Q_STATS, []*ibmmq.PCFParameter{
+		NewStringParameter(ibmmq.MQCA_Q_NAME, queuePattern),
+	})
+	if err != nil && len(responses) == 0 {
+		return nil, wrapError(err, "reset statistics for %s", queuePattern)
+	}
+
+	results, err := parseResetResponses(queuePattern, responses, err)
+
+	c.logger.WithFields(logging.Fields{
+		"queue_pattern": queuePattern,
+		"queues":        len(results),
+	}).Info("Reset queue statistics")
+
+	return results, err
+}
+
+// parseResetResponses returns the statistics of the successful responses to a Reset
+// Queue Statistics command, and a *ResetError naming the queues of the failed ones when
+// the command returned err
+func parseResetResponses(queuePattern string, responses []*CommandResponse, err error) ([]*QueueResetStatistics, error) {
+	var results []*QueueResetStatistics
+	var failed []string
+	for _, response := range responses {
+		name, ok := response.GetString(ibmmq.MQCA_Q_NAME)
+		if response.CompCode != ibmmq.MQCC_OK {
+			if ok {
+				failed = append(failed, name)
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		stats := &QueueResetStatistics{QueueName: name}
+		stats.HighDepth, _ = response.GetInt(ibmmq.MQIA_HIGH_Q_DEPTH)
+		stats.EnqueueCount, _ = response.GetInt(ibmmq.MQIA_MSG_ENQ_COUNT)
+		stats.DequeueCount, _ = response.GetInt(ibmmq.MQIA_MSG_DEQ_COUNT)
+		stats.TimeSinceReset, _ = response.GetInt(ibmmq.MQIA_TIME_SINCE_RESET)
+
+		results = append(results, stats)
+	}
+
+	if err != nil {
+		return results, &ResetError{QueuePattern: queuePattern, Queues: failed, Err: err}
+	}
+	return results, nil
+}
+
+// QueueInfo holds the attributes of a local queue returned by Inquire Queue
+type QueueInfo struct {
+	QueueName       string `json:"queue_name"`
+	Description     string `json:"description,omitempty"`
+	CurrentDepth    int64  `json:"current_depth"`
+	MaxDepth        int64  `json:"max_depth"`
+	OpenInputCount  int64  `json:"open_input_count"`
+	OpenOutputCount int64  `json:"open_output_count"`
+}
+
+// InquireQueues issues MQCMD_INQUIRE_Q for all local queues matching the (possibly
+// generic) queue name and returns their depth and open handle counts
+func (c *MQClient) InquireQueues(queuePattern string) ([]*QueueInfo, error) {
+	responses, err := c.ExecuteCommand(ibmmq.MQCMD_INQUIRE_Q, []*ibmmq.PCFParameter{
+		NewStringParameter(ibmmq.MQCA_Q_NAME, queuePattern),
+		NewIntParameter(ibmmq.MQIA_Q_TYPE, ibmmq.MQQT_LOCAL),
+	})
+	if err != nil {
+		return nil, wrapError(err, "inquire queues %s", queuePattern)
+	}
+
+	var queues []*QueueInfo
+	for _, response := range responses {
+		name, ok := response.GetString(ibmmq.MQCA_Q_NAME)
+		if !ok {
+			continue
+		}
+
+		info := &QueueInfo{QueueName: name}
+		info.Description, _ = response.GetString(ibmmq.MQCA_Q_DESC)
+		info.CurrentDepth, _ = response.GetInt(ibmmq.MQIA_CURRENT_Q_DEPTH)
+		info.MaxDepth, _ = response.GetInt(ibmmq.MQIA_MAX_Q_DEPTH)
+		info.OpenInputCount, _ = response.GetInt(ibmmq.MQIA_OPEN_INPUT_COUNT)
+		info.OpenOutputCount, _ = response.GetInt(ibmmq.MQIA_OPEN_OUTPUT_COUNT)
+
+		queues = append(queues, info)
+	}
+
+	return queues, nil
+}
@@ -0,0 +1,121 @@
+package mqclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandResponseAccessors(t *testing.T) {
+	response := &CommandResponse{
+		Command: ibmmq.MQCMD_RESET_Q_STATS,
+		Parameters: []*ibmmq.PCFParameter{
+			NewStringParameter(ibmmq.MQCA_Q_NAME, "APP.ORDERS"),
+			NewIntParameter(ibmmq.MQIA_HIGH_Q_DEPTH, 120),
+			NewIntParameter(ibmmq.MQIA_MSG_ENQ_COUNT, 5000),
+		},
+	}
+
+	name, ok := response.GetString(ibmmq.MQCA_Q_NAME)
+	assert.True(t, ok)
+	assert.Equal(t, "APP.ORDERS", name)
+
+	depth, ok := response.GetInt(ibmmq.MQIA_HIGH_Q_DEPTH)
+	assert.True(t, ok)
+	assert.Equal(t, int64(120), depth)
+
+	enq, ok := response.GetInt(ibmmq.MQIA_MSG_ENQ_COUNT)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5000), enq)
+
+	// Missing parameters and type mismatches are reported as absent
+	_, ok = response.GetInt(ibmmq.MQIA_MSG_DEQ_COUNT)
+	assert.False(t, ok)
+	_, ok = response.GetString(ibmmq.MQIA_HIGH_Q_DEPTH)
+	assert.False(t, ok)
+}
+
+func TestExecuteCommandRequiresConnection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logging.NewLogrus(logger))
+
+	_, err := client.ExecuteCommand(ibmmq.MQCMD_RESET_Q_STATS, nil)
+	assert.ErrorIs(t, err, ErrNotConnected)
+	assert.Equal(t, ClassTransient, Classify(err))
+
+	_, err = client.ResetQueueStatistics("APP.*")
+	assert.ErrorIs(t, err, ErrNotConnected)
+}
+
+func TestInquireQueuesRequiresConnection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logging.NewLogrus(logger))
+
+	queues, err := client.InquireQueues("*")
+	assert.Error(t, err)
+	assert.Nil(t, queues)
+}
+
+func TestCommandQueues(t *testing.T) {
+	logger := logging.NewLogrus(logrus.New())
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logger)
+	assert.Equal(t, DefaultCommandQueue, client.CommandQueue())
+	assert.Equal(t, DefaultReplyModelQueue, client.replyModelQueue())
+	assert.Equal(t, int32(5000), client.commandWait())
+
+	client = NewMQClient(&config.MQConfig{
+		QueueManager:    "TESTQM",
+		CommandQueue:    "SYSTEM.COMMAND.INPUT",
+		ReplyModelQueue: "COLLECTOR.REPLY.MODEL",
+		CommandTimeout:  30 * time.Second,
+	}, logger)
+	assert.Equal(t, "SYSTEM.COMMAND.INPUT", client.CommandQueue())
+	assert.Equal(t, "COLLECTOR.REPLY.MODEL", client.replyModelQueue())
+	assert.Equal(t, int32(30000), client.commandWait())
+}
+
+func TestParseResetResponsesPartialFailure(t *testing.T) {
+	responses := []*CommandResponse{
+		{
+			Command: ibmmq.MQCMD_RESET_Q_STATS,
+			Parameters: []*ibmmq.PCFParameter{
+				NewStringParameter(ibmmq.MQCA_Q_NAME, "APP.ORDERS"),
+				NewIntParameter(ibmmq.MQIA_HIGH_Q_DEPTH, 12),
+				NewIntParameter(ibmmq.MQIA_MSG_ENQ_COUNT, 300),
+				NewIntParameter(ibmmq.MQIA_MSG_DEQ_COUNT, 290),
+				NewIntParameter(ibmmq.MQIA_TIME_SINCE_RESET, 60),
+			},
+		},
+		{
+			Command:    ibmmq.MQCMD_RESET_Q_STATS,
+			CompCode:   ibmmq.MQCC_FAILED,
+			Reason:     ibmmq.MQRC_NOT_AUTHORIZED,
+			Parameters: []*ibmmq.PCFParameter{NewStringParameter(ibmmq.MQCA_Q_NAME, "APP.PAYMENTS")},
+		},
+	}
+	cmdErr := &CommandError{Command: ibmmq.MQCMD_RESET_Q_STATS, CompCode: ibmmq.MQCC_FAILED, Reason: ibmmq.MQRC_NOT_AUTHORIZED}
+
+	results, err := parseResetResponses("APP.*", responses, cmdErr)
+	assert.Equal(t, []*QueueResetStatistics{
+		{QueueName: "APP.ORDERS", HighDepth: 12, EnqueueCount: 300, DequeueCount: 290, TimeSinceReset: 60},
+	}, results)
+
+	var resetErr *ResetError
+	assert.ErrorAs(t, err, &resetErr)
+	assert.Equal(t, []string{"APP.PAYMENTS"}, resetErr.Queues)
+	assert.True(t, IsCommandReason(err, ibmmq.MQRC_NOT_AUTHORIZED))
+
+	results, err = parseResetResponses("APP.*", responses[:1], nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+}
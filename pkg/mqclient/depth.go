@@ -0,0 +1,78 @@
+package mqclient
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// InquireQueueDepths returns the current and maximum depth and the open handle counts of
+// queues, as they are now rather than at the end of the last statistics interval. Queues
+// named outright are inquired with MQINQ and need only inquire authority; generic names
+// ending in * are inquired through the command server with Inquire Queue. A queue that
+// cannot be inquired does not stop the others: the queues inquired are returned with
+// the errors of the rest.
+func (c *MQClient) InquireQueueDepths(queueNames []string) ([]*QueueInfo, error) {
+	if !c.connected {
+		return nil, ErrNotConnected
+	}
+
+	var queues []*QueueInfo
+	var errs []error
+	for _, name := range queueNames {
+		if strings.HasSuffix(name, "*") {
+			matched, err := c.InquireQueues(name)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			queues = append(queues, matched...)
+			continue
+		}
+
+		info, err := c.inquireQueueDepth(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		queues = append(queues, info)
+	}
+
+	c.logger.WithFields(logging.Fields{
+		"queues": len(queues),
+		"failed": len(errs),
+	}).Debug("Inquired queue depths")
+	return queues, errors.Join(errs...)
+}
+
+// inquireQueueDepth opens a queue for inquire and reads its depth and open handle counts
+func (c *MQClient) inquireQueueDepth(queueName string) (*QueueInfo, error) {
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = queueName
+
+	queue, err := c.qmgr.Open(mqod, ibmmq.MQOO_INQUIRE|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return nil, wrapError(err, "open queue %s for inquire", queueName)
+	}
+	defer queue.Close(0)
+
+	values, err := queue.Inq([]int32{
+		ibmmq.MQIA_CURRENT_Q_DEPTH,
+		ibmmq.MQIA_MAX_Q_DEPTH,
+		ibmmq.MQIA_OPEN_INPUT_COUNT,
+		ibmmq.MQIA_OPEN_OUTPUT_COUNT,
+	})
+	if err != nil {
+		return nil, wrapError(err, "inquire queue %s", queueName)
+	}
+
+	return &QueueInfo{
+		QueueName:       queueName,
+		CurrentDepth:    int64(inqInt(values, ibmmq.MQIA_CURRENT_Q_DEPTH)),
+		MaxDepth:        int64(inqInt(values, ibmmq.MQIA_MAX_Q_DEPTH)),
+		OpenInputCount:  int64(inqInt(values, ibmmq.MQIA_OPEN_INPUT_COUNT)),
+		OpenOutputCount: int64(inqInt(values, ibmmq.MQIA_OPEN_OUTPUT_COUNT)),
+	}, nil
+}
@@ -0,0 +1,21 @@
+package mqclient
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInquireQueueDepthsRequiresConnection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM"}, logging.NewLogrus(logger))
+
+	queues, err := client.InquireQueueDepths([]string{"APP.ORDERS", "APP.*"})
+	assert.Error(t, err)
+	assert.Nil(t, queues)
+}
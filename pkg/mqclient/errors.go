@@ -0,0 +1,147 @@
+package mqclient
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// ErrorClass says what a caller can do about a failed IBM MQ call
+type ErrorClass int
+
+const (
+	// ClassFatal is a failure that retrying does not fix, and any error that is not an
+	// IBM MQ failure
+	ClassFatal ErrorClass = iota
+	// ClassTransient is a failure that may clear by itself, such as a lost connection, a
+	// stopping queue manager or a full queue; the call can be retried later
+	ClassTransient
+	// ClassAuth is a failure of authentication or authorization, fixed by granting access
+	ClassAuth
+	// ClassConfig is a failure caused by the configuration, such as an unknown queue or
+	// channel or a TLS mismatch, fixed by correcting it
+	ClassConfig
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ClassTransient:
+		return "transient"
+	case ClassAuth:
+		return "auth"
+	case ClassConfig:
+		return "config"
+	}
+	return "fatal"
+}
+
+// MQError is an IBM MQ call that failed, with its completion and reason codes
+type MQError struct {
+	Op       string // what failed, such as "open statistics queue SYSTEM.ADMIN.STATISTICS.QUEUE"
+	CompCode int32
+	Reason   int32
+	Err      error
+}
+
+func (e *MQError) Error() string {
+	return fmt.Sprintf("failed to %s: %v", e.Op, e.Err)
+}
+
+func (e *MQError) Unwrap() error {
+	return e.Err
+}
+
+// Class returns the class of the reason code
+func (e *MQError) Class() ErrorClass {
+	return ClassifyReason(e.Reason)
+}
+
+// ErrNotConnected is returned by calls made without a connection to the queue manager.
+// It carries MQRC_HCONN_ERROR, as IBM MQ returns for a call without a valid connection
+// handle, so it classifies as a broken connection.
+var ErrNotConnected error = &MQError{
+	Op:       "call the queue manager",
+	CompCode: ibmmq.MQCC_FAILED,
+	Reason:   ibmmq.MQRC_HCONN_ERROR,
+	Err:      errors.New("not connected to queue manager"),
+}
+
+// wrapError returns the error of an IBM MQ call made to do op as an *MQError, or wraps
+// it in a plain error when it carries no reason code
+func wrapError(err error, format string, args ...interface{}) error {
+	op := fmt.Sprintf(format, args...)
+	if compCode, reason, ok := codes(err); ok {
+		return &MQError{Op: op, CompCode: compCode, Reason: reason, Err: err}
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+// codes returns the completion and reason codes of the first IBM MQ failure in an
+// error chain
+func codes(err error) (compCode, reason int32, ok bool) {
+	var mqErr *MQError
+	var mqret *ibmmq.MQReturn
+	var cmdErr *CommandError
+	switch {
+	case errors.As(err, &mqErr):
+		return mqErr.CompCode, mqErr.Reason, true
+	case errors.As(err, &mqret):
+		return mqret.MQCC, mqret.MQRC, true
+	case errors.As(err, &cmdErr):
+		return cmdErr.CompCode, cmdErr.Reason, true
+	}
+	return 0, 0, false
+}
+
+// Reason returns the reason code of the IBM MQ failure in an error chain
+func Reason(err error) (int32, bool) {
+	_, reason, ok := codes(err)
+	return reason, ok
+}
+
+// Classify returns the class of an error: that of its reason code for an IBM MQ
+// failure, and ClassFatal for any other error
+func Classify(err error) ErrorClass {
+	reason, ok := Reason(err)
+	if !ok {
+		return ClassFatal
+	}
+	return ClassifyReason(reason)
+}
+
+// ClassifyReason returns the class of an IBM MQ reason code. Reason codes not known to
+// be transient, authorization or configuration failures are fatal.
+func ClassifyReason(reason int32) ErrorClass {
+	if ConnectionBroken(reason) {
+		return ClassTransient
+	}
+
+	switch reason {
+	case ibmmq.MQRC_Q_MGR_QUIESCING, ibmmq.MQRC_CALL_INTERRUPTED, ibmmq.MQRC_HOST_NOT_AVAILABLE, ibmmq.MQRC_CHANNEL_NOT_AVAILABLE, ibmmq.MQRC_MAX_CONNS_LIMIT_REACHED,
+		ibmmq.MQRC_Q_FULL, ibmmq.MQRC_Q_SPACE_NOT_AVAILABLE, ibmmq.MQRC_STORAGE_NOT_AVAILABLE,
+		ibmmq.MQRC_RESOURCE_PROBLEM, ibmmq.MQRC_SYNCPOINT_LIMIT_REACHED, ibmmq.MQRC_SYNCPOINT_NOT_AVAILABLE,
+		ibmmq.MQRC_BACKED_OUT, ibmmq.MQRC_OBJECT_IN_USE, ibmmq.MQRC_GET_INHIBITED, ibmmq.MQRC_PUT_INHIBITED:
+		return ClassTransient
+	case ibmmq.MQRC_NOT_AUTHORIZED, ibmmq.MQRC_CONNECTION_NOT_AUTHORIZED, ibmmq.MQRC_SECURITY_ERROR:
+		return ClassAuth
+	case ibmmq.MQRC_UNKNOWN_OBJECT_NAME, ibmmq.MQRC_UNKNOWN_ALIAS_BASE_Q, ibmmq.MQRC_Q_MGR_NAME_ERROR,
+		ibmmq.MQRC_UNKNOWN_CHANNEL_NAME, ibmmq.MQRC_CHANNEL_CONFIG_ERROR, ibmmq.MQRC_CLIENT_CHANNEL_CONFLICT,
+		ibmmq.MQRC_SSL_INITIALIZATION_ERROR, ibmmq.MQRC_SSL_PEER_NAME_MISMATCH, ibmmq.MQRC_SSL_PEER_NAME_ERROR,
+		ibmmq.MQRC_KEY_REPOSITORY_ERROR, ibmmq.MQRC_CHANNEL_SSL_ERROR, ibmmq.MQRC_SELECTOR_SYNTAX_ERROR:
+		return ClassConfig
+	}
+	return ClassFatal
+}
+
+// ConnectionBroken reports whether a reason code means the connection to the queue
+// manager is lost and has to be made again. These reasons are transient.
+func ConnectionBroken(reason int32) bool {
+	switch reason {
+	case ibmmq.MQRC_CONNECTION_BROKEN, ibmmq.MQRC_HCONN_ERROR, ibmmq.MQRC_Q_MGR_NOT_AVAILABLE,
+		ibmmq.MQRC_Q_MGR_STOPPING, ibmmq.MQRC_CONNECTION_QUIESCING, ibmmq.MQRC_CONNECTION_STOPPING,
+		ibmmq.MQRC_RECONNECT_FAILED:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package mqclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapError(t *testing.T) {
+	mqret := &ibmmq.MQReturn{MQCC: ibmmq.MQCC_FAILED, MQRC: ibmmq.MQRC_UNKNOWN_OBJECT_NAME}
+	err := wrapError(mqret, "open queue %s", "APP.QUEUE")
+
+	var mqErr *MQError
+	require.True(t, errors.As(err, &mqErr))
+	assert.Equal(t, "open queue APP.QUEUE", mqErr.Op)
+	assert.Equal(t, ibmmq.MQCC_FAILED, mqErr.CompCode)
+	assert.Equal(t, ibmmq.MQRC_UNKNOWN_OBJECT_NAME, mqErr.Reason)
+	assert.Equal(t, ClassConfig, mqErr.Class())
+	assert.Contains(t, err.Error(), "failed to open queue APP.QUEUE: ")
+	assert.True(t, errors.Is(err, mqret))
+
+	// Errors without a reason code are wrapped as they are
+	plain := wrapError(errors.New("boom"), "open queue %s", "APP.QUEUE")
+	assert.False(t, errors.As(plain, &mqErr))
+	assert.Equal(t, "failed to open queue APP.QUEUE: boom", plain.Error())
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorClass
+	}{
+		{"connection broken", &ibmmq.MQReturn{MQRC: ibmmq.MQRC_CONNECTION_BROKEN}, ClassTransient},
+		{"queue full", wrapError(&ibmmq.MQReturn{MQRC: ibmmq.MQRC_Q_FULL}, "put message"), ClassTransient},
+		{"not authorized", fmt.Errorf("cycle: %w", &ibmmq.MQReturn{MQRC: ibmmq.MQRC_NOT_AUTHORIZED}), ClassAuth},
+		{"unknown queue", &ibmmq.MQReturn{MQRC: ibmmq.MQRC_UNKNOWN_OBJECT_NAME}, ClassConfig},
+		{"command", &CommandError{Command: ibmmq.MQCMD_RESET_Q_STATS, Reason: ibmmq.MQRC_NOT_AUTHORIZED}, ClassAuth},
+		{"not connected", fmt.Errorf("drain: %w", ErrNotConnected), ClassTransient},
+		{"unclassified reason", &ibmmq.MQReturn{MQRC: ibmmq.MQRC_UNEXPECTED_ERROR}, ClassFatal},
+		{"not an MQ error", errors.New("boom"), ClassFatal},
+		{"nil", nil, ClassFatal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classify(tt.err))
+		})
+	}
+
+	assert.Equal(t, "transient", ClassTransient.String())
+	assert.Equal(t, "fatal", ClassFatal.String())
+}
+
+func TestReason(t *testing.T) {
+	reason, ok := Reason(fmt.Errorf("drain: %w", wrapError(&ibmmq.MQReturn{MQRC: ibmmq.MQRC_GET_INHIBITED}, "get message")))
+	assert.True(t, ok)
+	assert.Equal(t, ibmmq.MQRC_GET_INHIBITED, reason)
+
+	_, ok = Reason(errors.New("boom"))
+	assert.False(t, ok)
+}
+
+func TestConnectionBrokenReasonsAreTransient(t *testing.T) {
+	for _, reason := range []int32{
+		ibmmq.MQRC_CONNECTION_BROKEN, ibmmq.MQRC_HCONN_ERROR, ibmmq.MQRC_Q_MGR_NOT_AVAILABLE,
+		ibmmq.MQRC_Q_MGR_STOPPING, ibmmq.MQRC_CONNECTION_QUIESCING, ibmmq.MQRC_CONNECTION_STOPPING,
+		ibmmq.MQRC_RECONNECT_FAILED,
+	} {
+		assert.True(t, ConnectionBroken(reason), ReasonString(reason))
+		assert.Equal(t, ClassTransient, ClassifyReason(reason), ReasonString(reason))
+	}
+	assert.False(t, ConnectionBroken(ibmmq.MQRC_Q_FULL))
+}
@@ -0,0 +1,44 @@
+package mqclient
+
+import "time"
+
+// pacer spaces out the gets of one queue drain to hold it at a maximum rate. The first
+// burst messages are got back to back, so a normal cycle is never slowed down; only a
+// backlog beyond that is paced. Message n past the burst is due n-burst intervals after
+// the start of the drain, so gets that are already slower than the rate never wait.
+type pacer struct {
+	interval time.Duration // between gets, 0 for no limit
+	burst    int
+
+	start  time.Time
+	count  int
+	paused time.Duration
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// newPacer creates a pacer for a drain starting now. A rate that is not positive
+// disables pacing.
+func newPacer(rate, burst int) *pacer {
+	p := &pacer{burst: burst, now: time.Now, sleep: time.Sleep}
+	if rate > 0 {
+		p.interval = time.Second / time.Duration(rate)
+	}
+	p.start = p.now()
+	return p
+}
+
+// wait counts a message got and blocks until the next one is due
+func (p *pacer) wait() {
+	p.count++
+	if p.interval == 0 || p.count < p.burst {
+		return
+	}
+
+	due := p.start.Add(time.Duration(p.count-p.burst+1) * p.interval)
+	if delay := due.Sub(p.now()); delay > 0 {
+		p.sleep(delay)
+		p.paused += delay
+	}
+}
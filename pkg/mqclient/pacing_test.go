@@ -0,0 +1,65 @@
+package mqclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock advances only when the pacer sleeps or the test moves it
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (f *fakeClock) pacer(rate, burst int) *pacer {
+	p := newPacer(rate, burst)
+	p.now = func() time.Time { return f.now }
+	p.sleep = func(d time.Duration) {
+		f.slept = append(f.slept, d)
+		f.now = f.now.Add(d)
+	}
+	p.start = f.now
+	return p
+}
+
+func TestPacerBurstThenRate(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)}
+	p := clock.pacer(100, 3)
+
+	// The burst is got back to back
+	p.wait()
+	p.wait()
+	assert.Empty(t, clock.slept)
+
+	// Beyond it, one message per 10ms
+	for i := 0; i < 4; i++ {
+		p.wait()
+	}
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 10 * time.Millisecond,
+		10 * time.Millisecond, 10 * time.Millisecond}, clock.slept)
+	assert.Equal(t, 40*time.Millisecond, p.paused)
+}
+
+func TestPacerSlowGetsNeverWait(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)}
+	p := clock.pacer(100, 1)
+
+	for i := 0; i < 5; i++ {
+		clock.now = clock.now.Add(20 * time.Millisecond)
+		p.wait()
+	}
+	assert.Empty(t, clock.slept)
+}
+
+func TestPacerUnlimited(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	p := clock.pacer(0, 0)
+
+	for i := 0; i < 1000; i++ {
+		p.wait()
+	}
+	assert.Empty(t, clock.slept)
+	assert.Zero(t, p.paused)
+}
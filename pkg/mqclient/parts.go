@@ -0,0 +1,84 @@
+package mqclient
+
+import (
+	"errors"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// partAssembler joins the records IBM MQ splits over several messages of a queue, so a
+// record is only passed on once complete and a part never stands in for the whole
+// record. Messages that are not part of a longer record pass straight through. Parts
+// of a record that is cut short, by a gap in the sequence or the end of the drain, are
+// passed on one by one, as there is nothing better to do with them once got.
+type partAssembler struct {
+	parts  []*MQMessage
+	part   pcf.Part // of the latest part held
+	joined int      // records joined from parts
+}
+
+// add takes the next message of the queue and returns the messages complete so far
+func (a *partAssembler) add(msg *MQMessage) (complete []*MQMessage, cut int) {
+	part, ok := pcf.ReadPart(msg.Data)
+	if !ok {
+		return a.flushWith(msg)
+	}
+
+	// The next part of the record being held
+	if len(a.parts) > 0 && part.Sequence == a.part.Sequence+1 && part.Command == a.part.Command {
+		a.parts = append(a.parts, msg)
+		a.part = part
+		if !part.Last {
+			return nil, 0
+		}
+		return []*MQMessage{a.join()}, 0
+	}
+
+	// The first part of a record
+	if part.Sequence == 1 && !part.Last {
+		complete, cut = a.flush()
+		a.parts, a.part = []*MQMessage{msg}, part
+		return complete, cut
+	}
+	return a.flushWith(msg)
+}
+
+// flush returns the parts held, if any, as they are
+func (a *partAssembler) flush() (parts []*MQMessage, cut int) {
+	parts = a.parts
+	a.parts = nil
+	if len(parts) > 1 {
+		cut = len(parts)
+	}
+	return parts, cut
+}
+
+// flushWith returns the parts held followed by msg
+func (a *partAssembler) flushWith(msg *MQMessage) ([]*MQMessage, int) {
+	parts, cut := a.flush()
+	return append(parts, msg), cut
+}
+
+// join returns the parts held as one message, with the descriptor of the first
+func (a *partAssembler) join() *MQMessage {
+	data := make([][]byte, len(a.parts))
+	for i, part := range a.parts {
+		data[i] = part.Data
+	}
+	first := a.parts[0]
+	a.parts = nil
+	a.joined++
+	return &MQMessage{MD: first.MD, Data: pcf.JoinParts(data), Type: first.Type}
+}
+
+// passOn passes messages already got off the queue to fn. ErrStopDrain does not stop it,
+// as the messages cannot be left on the queue for the next drain; any other error does
+// and is returned.
+func passOn(messages []*MQMessage, fn func(*MQMessage) error) error {
+	for _, msg := range messages {
+		if err := fn(msg); err != nil && !errors.Is(err, ErrStopDrain) {
+			return err
+		}
+	}
+	return nil
+}
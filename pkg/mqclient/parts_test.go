@@ -0,0 +1,88 @@
+package mqclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statisticsPart(sequence, control int32, queue string) *MQMessage {
+	return &MQMessage{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		SetSequence(sequence, control).
+		AddString(pcf.MQCA_Q_NAME, queue).
+		Bytes()}
+}
+
+func TestPartAssemblerJoinsRecords(t *testing.T) {
+	var assembler partAssembler
+
+	// A record on its own passes through
+	single := statisticsPart(1, pcf.MQCFC_LAST, "A")
+	complete, cut := assembler.add(single)
+	assert.Equal(t, []*MQMessage{single}, complete)
+	assert.Zero(t, cut)
+
+	// A record in three parts is held until the last one
+	for i, control := range []int32{pcf.MQCFC_NOT_LAST, pcf.MQCFC_NOT_LAST} {
+		complete, _ = assembler.add(statisticsPart(int32(i+1), control, "B"))
+		assert.Empty(t, complete)
+	}
+	complete, cut = assembler.add(statisticsPart(3, pcf.MQCFC_LAST, "B"))
+	require.Len(t, complete, 1)
+	assert.Zero(t, cut)
+	assert.Equal(t, 1, assembler.joined)
+	part, _ := pcf.ReadPart(complete[0].Data)
+	assert.True(t, part.Last)
+	assert.Equal(t, "stats", complete[0].Type)
+
+	parts, cut := assembler.flush()
+	assert.Empty(t, parts)
+	assert.Zero(t, cut)
+}
+
+func TestPartAssemblerPassesOnCutRecords(t *testing.T) {
+	var assembler partAssembler
+
+	first, second := statisticsPart(1, pcf.MQCFC_NOT_LAST, "A"), statisticsPart(2, pcf.MQCFC_NOT_LAST, "A")
+	assembler.add(first)
+	assembler.add(second)
+
+	// A new record starts before the last part of the held one
+	next := statisticsPart(1, pcf.MQCFC_LAST, "B")
+	complete, cut := assembler.add(next)
+	assert.Equal(t, []*MQMessage{first, second, next}, complete)
+	assert.Equal(t, 2, cut)
+
+	// Parts held at the end of a drain
+	assembler.add(first)
+	parts, cut := assembler.flush()
+	assert.Equal(t, []*MQMessage{first}, parts)
+	assert.Zero(t, cut, "a single message flagged as not last is no cut record")
+	assert.Zero(t, assembler.joined)
+}
+
+func TestPassOn(t *testing.T) {
+	parts := []*MQMessage{statisticsPart(1, pcf.MQCFC_NOT_LAST, "A"), statisticsPart(2, pcf.MQCFC_NOT_LAST, "A")}
+
+	// Stopping the drain does not drop the parts already got
+	var passed []*MQMessage
+	err := passOn(parts, func(msg *MQMessage) error {
+		passed = append(passed, msg)
+		return ErrStopDrain
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, parts, passed)
+
+	// Any other error stops and is returned
+	failure := errors.New("spool write failed")
+	passed = nil
+	err = passOn(parts, func(msg *MQMessage) error {
+		passed = append(passed, msg)
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Len(t, passed, 1)
+}
@@ -0,0 +1,141 @@
+package mqclient
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// namedQueue is a queue opened by name and whether it is read non-destructively
+type namedQueue struct {
+	object ibmmq.MQObject
+	browse bool
+}
+
+// OpenQueue opens a queue such as an event queue for reading by name. With browse set
+// the queue is opened for browsing only, so messages stay on it for other tools.
+func (c *MQClient) OpenQueue(queueName string, browse bool) error {
+	if !c.connected {
+		return ErrNotConnected
+	}
+
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = queueName
+
+	openOptions := ibmmq.MQOO_INPUT_AS_Q_DEF | ibmmq.MQOO_FAIL_IF_QUIESCING
+	if browse {
+		openOptions = ibmmq.MQOO_BROWSE | ibmmq.MQOO_FAIL_IF_QUIESCING
+	}
+
+	queue, err := c.qmgr.Open(mqod, openOptions)
+	if err != nil {
+		return wrapError(err, "open queue %s", queueName)
+	}
+
+	if c.namedQueues == nil {
+		c.namedQueues = make(map[string]*namedQueue)
+	}
+	c.namedQueues[queueName] = &namedQueue{object: queue, browse: browse}
+
+	c.logger.WithFields(logging.Fields{
+		"queue":  queueName,
+		"browse": browse,
+	}).Info("Opened queue")
+	return nil
+}
+
+// GetQueueMessage reads the next message from a queue opened with OpenQueue, waiting up
+// to wait for one to arrive. It returns nil without an error when no message is available.
+// The message Type is left for the caller to set.
+func (c *MQClient) GetQueueMessage(queueName string, wait time.Duration) (*MQMessage, error) {
+	queue, ok := c.namedQueues[queueName]
+	if !ok {
+		return nil, fmt.Errorf("queue %s is not open", queueName)
+	}
+
+	msg, err := c.getNext(queue.object, queue.browse, wait)
+	if err != nil {
+		return nil, wrapError(err, "get message from queue %s", queueName)
+	}
+	return msg, nil
+}
+
+// getNext gets the next message from an open queue, or browses it, waiting up to wait for
+// one to arrive. It returns nil without an error when no message is available.
+func (c *MQClient) getNext(queue ibmmq.MQObject, browse bool, wait time.Duration) (*MQMessage, error) {
+	mqmd := ibmmq.NewMQMD()
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_FAIL_IF_QUIESCING | ibmmq.MQGMO_CONVERT
+	if browse {
+		gmo.Options |= ibmmq.MQGMO_BROWSE_NEXT
+	}
+	if wait > 0 {
+		gmo.Options |= ibmmq.MQGMO_WAIT
+		gmo.WaitInterval = int32(wait / time.Millisecond)
+	} else {
+		gmo.Options |= ibmmq.MQGMO_NO_WAIT
+	}
+
+	data, err := c.getWithBuffer(queue, mqmd, gmo)
+	if err != nil {
+		var mqret *ibmmq.MQReturn
+		if errors.As(err, &mqret) && mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &MQMessage{
+		MD:   mqmd,
+		Data: data,
+	}, nil
+}
+
+// PutQueueMessage puts a message to a queue outside syncpoint, keeping the format,
+// encoding, CCSID and identifiers of its message descriptor, if it has one. The context
+// fields are set by the queue manager.
+func (c *MQClient) PutQueueMessage(queueName string, msg *MQMessage) error {
+	if !c.connected {
+		return ErrNotConnected
+	}
+	return c.putMessage(c.qmgr, queueName, msg, ibmmq.MQPMO_NO_SYNCPOINT)
+}
+
+// putMessage puts a message to a queue over a connection, as PutQueueMessage, with
+// syncpoint set to MQPMO_SYNCPOINT or MQPMO_NO_SYNCPOINT
+func (c *MQClient) putMessage(qmgr ibmmq.MQQueueManager, queueName string, msg *MQMessage, syncpoint int32) error {
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = queueName
+
+	queue, err := qmgr.Open(mqod, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		return wrapError(err, "open queue %s", queueName)
+	}
+	defer queue.Close(0)
+
+	mqmd := ibmmq.NewMQMD()
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = syncpoint | ibmmq.MQPMO_FAIL_IF_QUIESCING
+	if md := msg.MD; md != nil {
+		mqmd.Format = md.Format
+		mqmd.Encoding = md.Encoding
+		mqmd.CodedCharSetId = md.CodedCharSetId
+		mqmd.MsgType = md.MsgType
+		mqmd.Persistence = md.Persistence
+		mqmd.MsgId = md.MsgId
+		mqmd.CorrelId = md.CorrelId
+	} else {
+		mqmd.Format = ibmmq.MQFMT_ADMIN
+		pmo.Options |= ibmmq.MQPMO_NEW_MSG_ID
+	}
+
+	if err := queue.Put(mqmd, pmo, msg.Data); err != nil {
+		return wrapError(err, "put message to queue %s", queueName)
+	}
+	return nil
+}
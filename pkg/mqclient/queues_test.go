@@ -0,0 +1,30 @@
+package mqclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamedQueueOperationsWithoutConnection(t *testing.T) {
+	cfg := &config.MQConfig{
+		QueueManager:   "TESTQM",
+		Channel:        "TEST.SVRCONN",
+		ConnectionName: "localhost(1414)",
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewMQClient(cfg, logging.NewLogrus(logger))
+
+	err := client.OpenQueue("SYSTEM.ADMIN.PERFM.EVENT", true)
+	assert.Error(t, err, "Should fail to open queue without connection")
+
+	msg, err := client.GetQueueMessage("SYSTEM.ADMIN.PERFM.EVENT", time.Second)
+	assert.Error(t, err, "Should fail to read a queue that is not open")
+	assert.Nil(t, msg)
+}
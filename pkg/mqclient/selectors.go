@@ -0,0 +1,32 @@
+package mqclient
+
+import (
+	"encoding/hex"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// idLength is the length of message and correlation identifiers
+const idLength = 24
+
+// matchID returns the identifier a hex string selects, padded with zeros to its full
+// length, or nil for an empty or invalid string, which matches every message
+func matchID(id string) []byte {
+	decoded, err := hex.DecodeString(id)
+	if id == "" || err != nil || len(decoded) > idLength {
+		return nil
+	}
+	return append(decoded, make([]byte, idLength-len(decoded))...)
+}
+
+// applySelectors makes a get of the statistics or accounting queue match only the
+// messages with the configured correlation and message identifiers. The get options
+// match both identifiers by default, and an identifier of zeros matches every message.
+func (c *MQClient) applySelectors(mqmd *ibmmq.MQMD) {
+	if id := matchID(c.config.MatchCorrelID); id != nil {
+		mqmd.CorrelId = id
+	}
+	if id := matchID(c.config.MatchMsgID); id != nil {
+		mqmd.MsgId = id
+	}
+}
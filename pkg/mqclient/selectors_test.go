@@ -0,0 +1,30 @@
+package mqclient
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchID(t *testing.T) {
+	id := matchID("c0ffee")
+	assert.Len(t, id, idLength)
+	assert.Equal(t, []byte{0xc0, 0xff, 0xee, 0}, id[:4])
+
+	assert.Nil(t, matchID(""))
+	assert.Nil(t, matchID("not hex"))
+}
+
+func TestApplySelectors(t *testing.T) {
+	logger := logging.NewLogrus(logrus.New())
+
+	client := NewMQClient(&config.MQConfig{QueueManager: "TESTQM", MatchCorrelID: "01"}, logger)
+	mqmd := ibmmq.NewMQMD()
+	client.applySelectors(mqmd)
+	assert.Equal(t, byte(1), mqmd.CorrelId[0])
+	assert.Equal(t, ibmmq.NewMQMD().MsgId, mqmd.MsgId, "an unset identifier matches every message")
+}
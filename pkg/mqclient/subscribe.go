@@ -0,0 +1,78 @@
+package mqclient
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// subscription is a managed subscription to a topic and the queue its publications
+// arrive on
+type subscription struct {
+	object ibmmq.MQObject
+	queue  ibmmq.MQObject
+}
+
+// Subscribe creates a managed, non-durable subscription to a topic string. Its
+// publications are got with GetPublication, starting with the retained publication of the
+// topic, if any, such as the resource monitoring metadata. The subscription ends with
+// Unsubscribe or when the client disconnects.
+func (c *MQClient) Subscribe(topic string) error {
+	if !c.connected {
+		return ErrNotConnected
+	}
+	if _, ok := c.subscriptions[topic]; ok {
+		return nil
+	}
+
+	mqsd := ibmmq.NewMQSD()
+	mqsd.Options = ibmmq.MQSO_CREATE | ibmmq.MQSO_NON_DURABLE | ibmmq.MQSO_MANAGED | ibmmq.MQSO_FAIL_IF_QUIESCING
+	mqsd.ObjectString = topic
+
+	var queue ibmmq.MQObject
+	object, err := c.qmgr.Sub(mqsd, &queue)
+	if err != nil {
+		return wrapError(err, "subscribe to topic %s", topic)
+	}
+
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[string]*subscription)
+	}
+	c.subscriptions[topic] = &subscription{object: object, queue: queue}
+
+	c.logger.WithField("topic", topic).Debug("Subscribed to topic")
+	return nil
+}
+
+// GetPublication gets the next publication of a topic subscribed to with Subscribe,
+// waiting up to wait for one to arrive. It returns nil without an error when no
+// publication is available.
+func (c *MQClient) GetPublication(topic string, wait time.Duration) (*MQMessage, error) {
+	sub, ok := c.subscriptions[topic]
+	if !ok {
+		return nil, fmt.Errorf("not subscribed to topic %s", topic)
+	}
+
+	msg, err := c.getNext(sub.queue, false, wait)
+	if err != nil {
+		return nil, wrapError(err, "get publication of topic %s", topic)
+	}
+	return msg, nil
+}
+
+// Unsubscribe ends a subscription made with Subscribe, discarding the publications not
+// got yet
+func (c *MQClient) Unsubscribe(topic string) error {
+	sub, ok := c.subscriptions[topic]
+	if !ok {
+		return nil
+	}
+	delete(c.subscriptions, topic)
+
+	sub.queue.Close(0)
+	if err := sub.object.Close(0); err != nil {
+		return wrapError(err, "unsubscribe from topic %s", topic)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package mqclient
+
+import "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+
+// securityOptions returns the TLS security options of a connection: the key repository
+// holding the certificates, its password, the label of the client certificate and FIPS
+// mode. Without any, it returns nil and the MQ client falls back to the MQSSLKEYR
+// environment variable and the SSL stanza of mqclient.ini.
+func (c *MQClient) securityOptions() *ibmmq.MQSCO {
+	if c.config.KeyRepository == "" && c.config.KeyRepositoryPassword == "" &&
+		c.config.CertificateLabel == "" && !c.config.FIPSRequired {
+		return nil
+	}
+
+	sco := ibmmq.NewMQSCO()
+	sco.KeyRepository = c.config.KeyRepository
+	sco.KeyRepoPassword = c.config.KeyRepositoryPassword
+	sco.CertificateLabel = c.config.CertificateLabel
+	sco.FipsRequired = c.config.FIPSRequired
+	return sco
+}
@@ -0,0 +1,41 @@
+package mqclient
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityOptions(t *testing.T) {
+	logger := logging.NewLogrus(logrus.New())
+
+	// Without TLS settings the MQ client's own configuration applies
+	client := NewMQClient(&config.MQConfig{CipherSpec: "TLS_AES_128_GCM_SHA256"}, logger)
+	assert.Nil(t, client.securityOptions())
+
+	client = NewMQClient(&config.MQConfig{
+		CipherSpec:    "TLS_AES_128_GCM_SHA256",
+		KeyRepository: "/var/mqm/ssl/key",
+		FIPSRequired:  true,
+	}, logger)
+	sco := client.securityOptions()
+	require.NotNil(t, sco)
+	assert.Equal(t, "/var/mqm/ssl/key", sco.KeyRepository)
+	assert.True(t, sco.FipsRequired)
+
+	// A client certificate for SSLCAUTH(REQUIRED) from a repository without a stash file
+	client = NewMQClient(&config.MQConfig{
+		CipherSpec:            "TLS_AES_128_GCM_SHA256",
+		CertificateLabel:      "collector",
+		KeyRepositoryPassword: "kdb-pass",
+	}, logger)
+	sco = client.securityOptions()
+	require.NotNil(t, sco)
+	assert.Equal(t, "collector", sco.CertificateLabel)
+	assert.Equal(t, "kdb-pass", sco.KeyRepoPassword)
+	assert.False(t, sco.FipsRequired)
+}
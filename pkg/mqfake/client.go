@@ -0,0 +1,460 @@
+package mqfake
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/simulator"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Client is an in-memory mqclient.MQClientAPI. Its statistics and accounting queues hold
+// the messages added to them, and optionally an interval of synthetic messages from a
+// simulator before each drain. Messages got are held until Commit, and Backout returns
+// them to their queues, as under syncpoint. It is safe for concurrent use.
+type Client struct {
+	// Generator, if set, adds one interval of statistics and accounting messages to the
+	// queues before each drain
+	Generator *simulator.Generator
+
+	// Returned by the inquiries
+	Settings  *mqclient.QueueManagerSettings
+	Queues    []*mqclient.QueueInfo
+	StartTime time.Time
+
+	// ConnectErr, if set, is returned by Connect instead of connecting
+	ConnectErr error
+
+	// Returned by ResetQueueStatistics for the queues matching the pattern
+	ResetStatistics []*mqclient.QueueResetStatistics
+	// ResetFailures names the queues whose reset fails, with a *mqclient.ResetError
+	ResetFailures []string
+	// SubscribeFailures names the topics whose subscription fails
+	SubscribeFailures []string
+
+	mu        sync.Mutex
+	connected bool
+	open      map[string]bool                  // queue types opened
+	messages  map[string][]*mqclient.MQMessage // by queue type
+	failures  map[string]error                 // next drain failure by queue type
+	pending   []*mqclient.MQMessage            // got since the last commit or backout
+	put       map[string][]*mqclient.MQMessage // by queue name
+	named     map[string][]*mqclient.MQMessage // by queue name, of the queues opened by name
+	commits   int
+	resets    int
+
+	publications  map[string][]*mqclient.MQMessage // by topic string
+	subscriptions map[string]bool
+}
+
+var _ mqclient.MQClientAPI = (*Client)(nil)
+
+// New creates a client of a queue manager with statistics and accounting switched on,
+// serving the synthetic messages of generator, or only those added when it is nil
+func New(generator *simulator.Generator) *Client {
+	return &Client{
+		Generator: generator,
+		Settings: &mqclient.QueueManagerSettings{
+			Name:               "FAKE",
+			StatisticsQueue:    ibmmq.MQMON_ON,
+			StatisticsMQI:      ibmmq.MQMON_ON,
+			StatisticsChannel:  ibmmq.MQMON_ON,
+			StatisticsInterval: 60,
+			AccountingQueue:    ibmmq.MQMON_ON,
+			AccountingMQI:      ibmmq.MQMON_ON,
+			AccountingInterval: 60,
+		},
+		StartTime: time.Now(),
+		open:      make(map[string]bool),
+		messages:  make(map[string][]*mqclient.MQMessage),
+		failures:  make(map[string]error),
+		put:       make(map[string][]*mqclient.MQMessage),
+		named:     make(map[string][]*mqclient.MQMessage),
+
+		publications:  make(map[string][]*mqclient.MQMessage),
+		subscriptions: make(map[string]bool),
+	}
+}
+
+// AddStatistics adds PCF messages to the statistics queue
+func (c *Client) AddStatistics(data ...[]byte) {
+	c.add("stats", data)
+}
+
+// AddAccounting adds PCF messages to the accounting queue
+func (c *Client) AddAccounting(data ...[]byte) {
+	c.add("accounting", data)
+}
+
+func (c *Client) add(queueType string, data [][]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range data {
+		c.messages[queueType] = append(c.messages[queueType], &mqclient.MQMessage{Data: d, Type: queueType})
+	}
+}
+
+// Publish adds PCF messages to the publications of a topic. Publications added before a
+// subscription are got once subscribed, as retained publications are.
+func (c *Client) Publish(topic string, data ...[]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range data {
+		c.publications[topic] = append(c.publications[topic], &mqclient.MQMessage{Data: d})
+	}
+}
+
+// Subscribed returns the topics subscribed to, sorted
+func (c *Client) Subscribed() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var topics []string
+	for topic := range c.subscriptions {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// AddMessages adds PCF messages, such as events, to a queue read by name
+func (c *Client) AddMessages(queueName string, data ...[]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range data {
+		c.named[queueName] = append(c.named[queueName], &mqclient.MQMessage{MD: ibmmq.NewMQMD(), Data: d})
+	}
+}
+
+// Fail makes the next drain of the "stats" or "accounting" queue fail with err
+func (c *Client) Fail(queueType string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[queueType] = err
+}
+
+// Put returns the messages put to a queue
+func (c *Client) Put(queueName string) []*mqclient.MQMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*mqclient.MQMessage(nil), c.put[queueName]...)
+}
+
+// Depth returns the number of messages on the "stats" or "accounting" queue
+func (c *Client) Depth(queueType string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.messages[queueType])
+}
+
+// Commits returns the number of commits with messages to commit
+func (c *Client) Commits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.commits
+}
+
+// Resets returns the number of Reset Queue Statistics commands
+func (c *Client) Resets() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resets
+}
+
+// Connect connects, unless ConnectErr is set
+func (c *Client) Connect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ConnectErr != nil {
+		return c.ConnectErr
+	}
+	c.connected = true
+	return nil
+}
+
+// Disconnect disconnects, backing out the messages not committed and closing the queues
+func (c *Client) Disconnect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.backout()
+	c.connected = false
+	c.open = make(map[string]bool)
+	c.subscriptions = make(map[string]bool)
+	return nil
+}
+
+func (c *Client) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+func (c *Client) CheckConnection() error {
+	if !c.IsConnected() {
+		return mqclient.ErrNotConnected
+	}
+	return nil
+}
+
+func (c *Client) OpenStatsQueue(queueName string) error {
+	return c.openQueue("stats")
+}
+
+func (c *Client) OpenAccountingQueue(queueName string) error {
+	return c.openQueue("accounting")
+}
+
+func (c *Client) openQueue(queueType string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return mqclient.ErrNotConnected
+	}
+	c.open[queueType] = true
+	return nil
+}
+
+// GetAllMessages gets every message of the "stats" or "accounting" queue
+func (c *Client) GetAllMessages(queueType string) ([]*mqclient.MQMessage, error) {
+	var messages []*mqclient.MQMessage
+	err := c.drain(queueType, func(msg *mqclient.MQMessage) error {
+		messages = append(messages, msg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetStatisticsAndAccounting gets every message of the statistics queue and then the
+// accounting queue, as mqclient.MQClient does
+func (c *Client) GetStatisticsAndAccounting() (stats, accounting []*mqclient.MQMessage, err error) {
+	err = c.DrainStatisticsAndAccounting(func(msg *mqclient.MQMessage) error {
+		if msg.Type == "stats" {
+			stats = append(stats, msg)
+		} else {
+			accounting = append(accounting, msg)
+		}
+		return nil
+	})
+	return stats, accounting, err
+}
+
+// DrainStatisticsAndAccounting passes every message of the statistics queue and then the
+// accounting queue to fn, after adding an interval from the generator, if any. A queue
+// that fails is reported as a *mqclient.QueueError, as mqclient.MQClient does.
+func (c *Client) DrainStatisticsAndAccounting(fn func(*mqclient.MQMessage) error) error {
+	if c.Generator != nil {
+		stats, accounting := c.Generator.Next()
+		c.mu.Lock()
+		c.messages["stats"] = append(c.messages["stats"], stats...)
+		c.messages["accounting"] = append(c.messages["accounting"], accounting...)
+		c.mu.Unlock()
+	}
+
+	var errs []error
+	for _, queueType := range []string{"stats", "accounting"} {
+		if err := c.drain(queueType, fn); err != nil {
+			errs = append(errs, &mqclient.QueueError{Type: queueType, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// drain passes the messages of a queue to fn until it returns an error, leaving the
+// rest on the queue. mqclient.ErrStopDrain stops the drain without an error.
+func (c *Client) drain(queueType string, fn func(*mqclient.MQMessage) error) error {
+	c.mu.Lock()
+	if !c.open[queueType] {
+		c.mu.Unlock()
+		return fmt.Errorf("%s queue not open", queueType)
+	}
+	if err := c.failures[queueType]; err != nil {
+		delete(c.failures, queueType)
+		c.mu.Unlock()
+		return err
+	}
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		queue := c.messages[queueType]
+		if len(queue) == 0 {
+			c.mu.Unlock()
+			return nil
+		}
+		msg := queue[0]
+		c.messages[queueType] = queue[1:]
+		c.pending = append(c.pending, msg)
+		c.mu.Unlock()
+
+		if err := fn(msg); errors.Is(err, mqclient.ErrStopDrain) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// OpenQueue opens a queue to read the messages added with AddMessages
+func (c *Client) OpenQueue(queueName string, browse bool) error {
+	return c.openQueue("queue:" + queueName)
+}
+
+// GetQueueMessage gets the next message added to a queue opened with OpenQueue, or nil
+// when there is none. Browsing gets it as well.
+func (c *Client) GetQueueMessage(queueName string, wait time.Duration) (*mqclient.MQMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.open["queue:"+queueName] {
+		return nil, fmt.Errorf("queue %s is not open", queueName)
+	}
+	queue := c.named[queueName]
+	if len(queue) == 0 {
+		return nil, nil
+	}
+	c.named[queueName] = queue[1:]
+	return queue[0], nil
+}
+
+// PutQueueMessage records a message put to a queue
+func (c *Client) PutQueueMessage(queueName string, msg *mqclient.MQMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return mqclient.ErrNotConnected
+	}
+	c.put[queueName] = append(c.put[queueName], msg)
+	return nil
+}
+
+// Commit removes the messages got since the last commit or backout
+func (c *Client) Commit() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) > 0 {
+		c.commits++
+	}
+	c.pending = nil
+	return nil
+}
+
+// Backout returns the messages got since the last commit or backout to their queues
+func (c *Client) Backout() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.backout()
+	return nil
+}
+
+func (c *Client) backout() {
+	for i := len(c.pending) - 1; i >= 0; i-- {
+		msg := c.pending[i]
+		c.messages[msg.Type] = append([]*mqclient.MQMessage{msg}, c.messages[msg.Type]...)
+	}
+	c.pending = nil
+}
+
+func (c *Client) InquireQueueManager() (*mqclient.QueueManagerSettings, error) {
+	return c.Settings, nil
+}
+
+// InquireQueues returns the Queues matching a name or a generic name ending in *
+func (c *Client) InquireQueues(queuePattern string) ([]*mqclient.QueueInfo, error) {
+	prefix, generic := strings.CutSuffix(queuePattern, "*")
+	var queues []*mqclient.QueueInfo
+	for _, q := range c.Queues {
+		if q.QueueName == queuePattern || (generic && strings.HasPrefix(q.QueueName, prefix)) {
+			queues = append(queues, q)
+		}
+	}
+	return queues, nil
+}
+
+// InquireQueueDepths returns the Queues matching each name or generic name
+func (c *Client) InquireQueueDepths(queueNames []string) ([]*mqclient.QueueInfo, error) {
+	var queues []*mqclient.QueueInfo
+	for _, name := range queueNames {
+		matched, _ := c.InquireQueues(name)
+		queues = append(queues, matched...)
+	}
+	return queues, nil
+}
+
+// ResetQueueStatistics returns the ResetStatistics of the queues matching a name or a
+// generic name ending in *
+func (c *Client) ResetQueueStatistics(queuePattern string) ([]*mqclient.QueueResetStatistics, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resets++
+
+	prefix, generic := strings.CutSuffix(queuePattern, "*")
+	var stats []*mqclient.QueueResetStatistics
+	var failed []string
+	for _, s := range c.ResetStatistics {
+		if s.QueueName != queuePattern && (!generic || !strings.HasPrefix(s.QueueName, prefix)) {
+			continue
+		}
+		if slices.Contains(c.ResetFailures, s.QueueName) {
+			failed = append(failed, s.QueueName)
+			continue
+		}
+		stats = append(stats, s)
+	}
+	if len(failed) > 0 {
+		return stats, &mqclient.ResetError{
+			QueuePattern: queuePattern,
+			Queues:       failed,
+			Err:          &mqclient.CommandError{Command: ibmmq.MQCMD_RESET_Q_STATS, CompCode: ibmmq.MQCC_FAILED, Reason: ibmmq.MQRC_NOT_AUTHORIZED},
+		}
+	}
+	return stats, nil
+}
+
+func (c *Client) InquireStartTime() (time.Time, error) {
+	return c.StartTime, nil
+}
+
+// Subscribe subscribes to a topic
+func (c *Client) Subscribe(topic string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.connected {
+		return mqclient.ErrNotConnected
+	}
+	if slices.Contains(c.SubscribeFailures, topic) {
+		return fmt.Errorf("not authorized to subscribe to topic %s", topic)
+	}
+	c.subscriptions[topic] = true
+	return nil
+}
+
+// GetPublication gets the next publication of a topic subscribed to, or nil when there is
+// none
+func (c *Client) GetPublication(topic string, wait time.Duration) (*mqclient.MQMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.subscriptions[topic] {
+		return nil, fmt.Errorf("not subscribed to topic %s", topic)
+	}
+	queue := c.publications[topic]
+	if len(queue) == 0 {
+		return nil, nil
+	}
+	c.publications[topic] = queue[1:]
+	return queue[0], nil
+}
+
+// Unsubscribe ends the subscription to a topic
+func (c *Client) Unsubscribe(topic string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.subscriptions, topic)
+	return nil
+}
@@ -0,0 +1,61 @@
+package mqfake
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientServesGeneratedMessages(t *testing.T) {
+	client := New(simulator.NewGenerator(simulator.DefaultOptions()))
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.OpenStatsQueue("SYSTEM.ADMIN.STATISTICS.QUEUE"))
+	require.NoError(t, client.OpenAccountingQueue("SYSTEM.ADMIN.ACCOUNTING.QUEUE"))
+
+	stats, accounting, err := client.GetStatisticsAndAccounting()
+	require.NoError(t, err)
+	assert.NotEmpty(t, stats)
+	assert.NotEmpty(t, accounting)
+	for _, msg := range stats {
+		assert.Equal(t, "stats", msg.Type)
+	}
+}
+
+func TestClientSyncpointAndFailures(t *testing.T) {
+	client := New(nil)
+	client.AddStatistics([]byte("first"), []byte("second"))
+	client.AddAccounting([]byte("third"))
+
+	// Queues must be opened on a connection
+	assert.Error(t, client.OpenStatsQueue("STATS"))
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.OpenStatsQueue("STATS"))
+
+	// The accounting queue is not open, so only it fails
+	stats, _, err := client.GetStatisticsAndAccounting()
+	assert.Len(t, stats, 2)
+	assert.Equal(t, []string{"accounting"}, mqclient.FailedQueues(err))
+
+	// Backed out messages return to their queue in order
+	require.NoError(t, client.Backout())
+	assert.Equal(t, 2, client.Depth("stats"))
+	messages, err := client.GetAllMessages("stats")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("first"), messages[0].Data)
+	require.NoError(t, client.Commit())
+	assert.Zero(t, client.Depth("stats"))
+	assert.Equal(t, 1, client.Commits())
+
+	client.Fail("stats", errors.New("MQRC_CONNECTION_BROKEN"))
+	_, err = client.GetAllMessages("stats")
+	assert.Error(t, err)
+	_, err = client.GetAllMessages("stats")
+	assert.NoError(t, err)
+
+	require.NoError(t, client.PutQueueMessage("QUARANTINE", &mqclient.MQMessage{Data: []byte("bad")}))
+	assert.Len(t, client.Put("QUARANTINE"), 1)
+}
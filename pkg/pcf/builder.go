@@ -0,0 +1,148 @@
+package pcf
+
+import (
+	"encoding/binary"
+)
+
+// MessageBuilder assembles PCF messages in the little-endian layout understood by Parser.
+// It is used to fabricate statistics and accounting messages without a queue manager.
+type MessageBuilder struct {
+	msgType    int32
+	command    int32
+	msgSeq     int32
+	control    int32
+	compCode   int32
+	reason     int32
+	parameters [][]byte
+}
+
+// NewMessageBuilder creates a builder for a message of the given PCF type and command
+func NewMessageBuilder(msgType, command int32) *MessageBuilder {
+	return &MessageBuilder{
+		msgType: msgType,
+		command: command,
+		msgSeq:  1,
+	}
+}
+
+// SetSequence sets the message sequence number and control flag of the header
+func (b *MessageBuilder) SetSequence(msgSeq, control int32) *MessageBuilder {
+	b.msgSeq = msgSeq
+	b.control = control
+	return b
+}
+
+// SetReason sets the completion and reason codes of the header, as used by event messages
+func (b *MessageBuilder) SetReason(compCode, reason int32) *MessageBuilder {
+	b.compCode = compCode
+	b.reason = reason
+	return b
+}
+
+// AddString appends an MQCFST string parameter, padded to a 4-byte boundary
+func (b *MessageBuilder) AddString(parameter int32, value string) *MessageBuilder {
+	length := 12 + len(value)
+	if length%4 != 0 {
+		length += 4 - (length % 4)
+	}
+
+	data := make([]byte, length)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(parameter))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(MQCFT_STRING))
+	binary.LittleEndian.PutUint32(data[8:12], uint32(length))
+	copy(data[12:], value)
+
+	b.parameters = append(b.parameters, data)
+	return b
+}
+
+// AddInteger appends an MQCFIN integer parameter
+func (b *MessageBuilder) AddInteger(parameter int32, value int32) *MessageBuilder {
+	data := make([]byte, 16)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(parameter))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(MQCFT_INTEGER))
+	binary.LittleEndian.PutUint32(data[8:12], 16)
+	binary.LittleEndian.PutUint32(data[12:16], uint32(value))
+
+	b.parameters = append(b.parameters, data)
+	return b
+}
+
+// AddInteger64 appends an MQCFIN64 integer parameter
+func (b *MessageBuilder) AddInteger64(parameter int32, value int64) *MessageBuilder {
+	data := make([]byte, 24)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(parameter))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(MQCFT_INTEGER64))
+	binary.LittleEndian.PutUint32(data[8:12], 24)
+	binary.LittleEndian.PutUint64(data[16:24], uint64(value))
+
+	b.parameters = append(b.parameters, data)
+	return b
+}
+
+// AddIntegerList appends an MQCFIL integer list parameter
+func (b *MessageBuilder) AddIntegerList(parameter int32, values ...int32) *MessageBuilder {
+	data := make([]byte, 16+4*len(values))
+	binary.LittleEndian.PutUint32(data[0:4], uint32(parameter))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(MQCFT_INTEGER_LIST))
+	binary.LittleEndian.PutUint32(data[8:12], uint32(len(data)))
+	binary.LittleEndian.PutUint32(data[12:16], uint32(len(values)))
+	for i, value := range values {
+		binary.LittleEndian.PutUint32(data[16+4*i:], uint32(value))
+	}
+
+	b.parameters = append(b.parameters, data)
+	return b
+}
+
+// AddInteger64List appends an MQCFIL64 integer list parameter
+func (b *MessageBuilder) AddInteger64List(parameter int32, values ...int64) *MessageBuilder {
+	data := make([]byte, 16+8*len(values))
+	binary.LittleEndian.PutUint32(data[0:4], uint32(parameter))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(MQCFT_INTEGER64_LIST))
+	binary.LittleEndian.PutUint32(data[8:12], uint32(len(data)))
+	binary.LittleEndian.PutUint32(data[12:16], uint32(len(values)))
+	for i, value := range values {
+		binary.LittleEndian.PutUint64(data[16+8*i:], uint64(value))
+	}
+
+	b.parameters = append(b.parameters, data)
+	return b
+}
+
+// AddGroup appends an MQCFGR group parameter holding the count parameters added after it
+func (b *MessageBuilder) AddGroup(parameter int32, count int32) *MessageBuilder {
+	data := make([]byte, 16)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(parameter))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(MQCFT_GROUP))
+	binary.LittleEndian.PutUint32(data[8:12], 16)
+	binary.LittleEndian.PutUint32(data[12:16], uint32(count))
+
+	b.parameters = append(b.parameters, data)
+	return b
+}
+
+// Bytes returns the encoded message including the PCF header
+func (b *MessageBuilder) Bytes() []byte {
+	size := 36
+	for _, param := range b.parameters {
+		size += len(param)
+	}
+
+	data := make([]byte, 36, size)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(b.msgType))
+	binary.LittleEndian.PutUint32(data[4:8], 36) // Structure length
+	binary.LittleEndian.PutUint32(data[8:12], 1) // Version
+	binary.LittleEndian.PutUint32(data[12:16], uint32(b.command))
+	binary.LittleEndian.PutUint32(data[16:20], uint32(b.msgSeq))
+	binary.LittleEndian.PutUint32(data[20:24], uint32(b.control))
+	binary.LittleEndian.PutUint32(data[24:28], uint32(b.compCode))
+	binary.LittleEndian.PutUint32(data[28:32], uint32(b.reason))
+	binary.LittleEndian.PutUint32(data[32:36], uint32(len(b.parameters)))
+
+	for _, param := range b.parameters {
+		data = append(data, param...)
+	}
+
+	return data
+}
@@ -0,0 +1,92 @@
+package pcf
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBuilder_HeaderLayout(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	data := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		SetSequence(3, 1).
+		AddString(MQCA_Q_NAME, "TEST.QUEUE").
+		AddInteger(MQIA_CURRENT_Q_DEPTH, 42).
+		Bytes()
+
+	header, err := parser.parseHeader(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(MQCFT_STATISTICS), header.Type)
+	assert.Equal(t, int32(36), header.StrucLength)
+	assert.Equal(t, int32(MQCMD_STATISTICS_Q), header.Command)
+	assert.Equal(t, int32(3), header.MsgSeqNumber)
+	assert.Equal(t, int32(1), header.Control)
+	assert.Equal(t, int32(2), header.ParameterCount)
+	assert.Equal(t, 0, len(data)%4, "message should be 4-byte aligned")
+}
+
+func TestMessageBuilder_RoundTrip(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	data := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		AddString(MQCA_Q_MGR_NAME, "SIMQM").
+		AddString(MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(MQIA_CURRENT_Q_DEPTH, 17).
+		AddInteger(MQIA_HIGH_Q_DEPTH, 120).
+		AddInteger(MQIA_MSG_ENQ_COUNT, 500).
+		AddInteger(MQIA_MSG_DEQ_COUNT, 483).
+		AddInteger(MQIA_OPEN_INPUT_COUNT, 2).
+		AddInteger(MQIA_OPEN_OUTPUT_COUNT, 0).
+		Bytes()
+
+	result, err := parser.ParseMessage(data, "statistics")
+	require.NoError(t, err)
+
+	stats, ok := result.(*StatisticsData)
+	require.True(t, ok)
+	require.NotNil(t, stats.QueueStats)
+
+	assert.Equal(t, "SIMQM", stats.QueueManager)
+	assert.Equal(t, "APP.ORDERS", stats.QueueStats.QueueName)
+	assert.Equal(t, int64(17), stats.QueueStats.CurrentDepth)
+	assert.Equal(t, int64(120), stats.QueueStats.HighDepth)
+	assert.Equal(t, int64(500), stats.QueueStats.EnqueueCount)
+	assert.Equal(t, int64(483), stats.QueueStats.DequeueCount)
+	assert.True(t, stats.QueueStats.HasReaders)
+	assert.False(t, stats.QueueStats.HasWriters)
+}
+
+func TestMessageBuilder_AccountingIdentityAndBytes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	data := NewMessageBuilder(MQCFT_ACCOUNTING, MQCMD_ACCOUNTING_MQI).
+		AddString(MQCA_APPL_NAME, "orders-service").
+		AddString(MQCACF_USER_IDENTIFIER, "appuser").
+		AddInteger(MQIAMO_PUTS, 10).
+		AddInteger64(MQIAMO64_PUT_BYTES, 5_000_000_000).
+		AddInteger64(MQIAMO64_GET_BYTES, 2048).
+		Bytes()
+
+	result, err := parser.ParseMessage(data, "accounting")
+	require.NoError(t, err)
+
+	acct, ok := result.(*AccountingData)
+	require.True(t, ok)
+
+	assert.Equal(t, "orders-service", acct.ConnectionInfo.ApplicationName)
+	assert.Equal(t, "appuser", acct.ConnectionInfo.UserIdentifier)
+	assert.Equal(t, int64(10), acct.Operations.Puts)
+	assert.Equal(t, int64(5_000_000_000), acct.Operations.PutBytes)
+	assert.Equal(t, int64(2048), acct.Operations.GetBytes)
+}
@@ -0,0 +1,84 @@
+package pcf
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+)
+
+const (
+	// parseChunk is the number of consecutive messages a worker claims at a time
+	parseChunk = 16
+	// minConcurrentMessages is the batch size below which messages are parsed in the
+	// calling goroutine, where starting workers would cost more than it saves
+	minConcurrentMessages = 2 * parseChunk
+)
+
+// Result is the outcome of parsing one message
+type Result struct {
+	Data interface{}
+	Err  error
+}
+
+// ConcurrentParser parses batches of messages on several workers, each with its own
+// parser, returning the results in message order. It is meant for draining large
+// backlogs, such as after an outage, where parsing dominates the collection cycle.
+type ConcurrentParser struct {
+	parsers []*Parser
+}
+
+// NewConcurrentParser creates a parser with the given number of workers, or one per
+// GOMAXPROCS if workers is not positive
+func NewConcurrentParser(logger logging.Logger, workers int) *ConcurrentParser {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	parsers := make([]*Parser, workers)
+	for i := range parsers {
+		parsers[i] = NewParser(logger)
+	}
+	return &ConcurrentParser{parsers: parsers}
+}
+
+// Workers returns the number of parsing workers
+func (cp *ConcurrentParser) Workers() int {
+	return len(cp.parsers)
+}
+
+// ParseMessages parses every message as msgType. Result i belongs to message i.
+func (cp *ConcurrentParser) ParseMessages(messages [][]byte, msgType string) []Result {
+	results := make([]Result, len(messages))
+
+	workers := min(len(cp.parsers), (len(messages)+parseChunk-1)/parseChunk)
+	if workers <= 1 || len(messages) < minConcurrentMessages {
+		for i, data := range messages {
+			results[i].Data, results[i].Err = cp.parsers[0].ParseMessage(data, msgType)
+		}
+		return results
+	}
+
+	// Workers claim chunks of consecutive messages, so a run of large messages does not
+	// leave the other workers idle; each writes only the results of its own chunks
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(parser *Parser) {
+			defer wg.Done()
+			for {
+				start := int(next.Add(parseChunk)) - parseChunk
+				if start >= len(messages) {
+					return
+				}
+				for i := start; i < min(start+parseChunk, len(messages)); i++ {
+					results[i].Data, results[i].Err = parser.ParseMessage(messages[i], msgType)
+				}
+			}
+		}(cp.parsers[w])
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,53 @@
+package pcf
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentParserKeepsOrder(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	var messages [][]byte
+	for i := 0; i < 500; i++ {
+		if i%97 == 0 {
+			messages = append(messages, []byte("not pcf"))
+			continue
+		}
+		message := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+			AddString(MQCA_Q_NAME, fmt.Sprintf("QUEUE.%03d", i)).
+			AddInteger(MQIA_CURRENT_Q_DEPTH, int32(i)).
+			Bytes()
+		messages = append(messages, message)
+	}
+
+	for _, workers := range []int{1, 4, 0} {
+		parser := NewConcurrentParser(logging.NewLogrus(logger), workers)
+		results := parser.ParseMessages(messages, "statistics")
+		require.Len(t, results, len(messages))
+
+		for i, result := range results {
+			if i%97 == 0 {
+				assert.Error(t, result.Err, "message %d", i)
+				continue
+			}
+			require.NoError(t, result.Err, "message %d", i)
+			stats, ok := result.Data.(*StatisticsData)
+			require.True(t, ok)
+			assert.Equal(t, fmt.Sprintf("QUEUE.%03d", i), stats.QueueStats.QueueName)
+			assert.Equal(t, int64(i), stats.QueueStats.CurrentDepth)
+		}
+	}
+}
+
+func TestConcurrentParserWorkers(t *testing.T) {
+	assert.Equal(t, 3, NewConcurrentParser(logging.NewLogrus(logrus.New()), 3).Workers())
+	assert.Positive(t, NewConcurrentParser(logging.NewLogrus(logrus.New()), 0).Workers())
+	assert.Empty(t, NewConcurrentParser(logging.NewLogrus(logrus.New()), 2).ParseMessages(nil, "statistics"))
+}
@@ -0,0 +1,42 @@
+package pcf
+
+import "encoding/binary"
+
+// counter returns the value of a count parameter as int64. MQ counts are never negative,
+// so a negative 32-bit value is a count that passed 2^31 and wrapped; it is read as
+// unsigned instead. Integer lists, such as the non-persistent and persistent counts MQ
+// reports for most operations, are summed.
+func counter(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int32:
+		return int64(uint32(v)), true
+	case int64:
+		return v, true
+	case []int32:
+		var sum int64
+		for _, n := range v {
+			sum += int64(uint32(n))
+		}
+		return sum, true
+	case []int64:
+		var sum int64
+		for _, n := range v {
+			sum += n
+		}
+		return sum, true
+	}
+	return 0, false
+}
+
+// listCount returns the number of values of an integer list parameter, checking that
+// values of the given size fit in the parameter
+func listCount(param PCFParameter, data []byte, offset, size int) (int, bool) {
+	if param.Length < 16 {
+		return 0, false
+	}
+	count := int(binary.LittleEndian.Uint32(data[offset+12 : offset+16]))
+	if count < 0 || count > (int(param.Length)-16)/size {
+		return 0, false
+	}
+	return count, true
+}
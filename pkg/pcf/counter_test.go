@@ -0,0 +1,77 @@
+package pcf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  int64
+		ok    bool
+	}{
+		{"int32", int32(42), 42, true},
+		{"wrapped int32", int32(math.MinInt32), 1 << 31, true},
+		{"int32 at 2^32-1", int32(-1), math.MaxUint32, true},
+		{"int64", int64(1) << 40, 1 << 40, true},
+		{"int32 list", []int32{3, 4}, 7, true},
+		{"wrapped int32 list", []int32{math.MaxInt32, math.MinInt32}, math.MaxUint32, true},
+		{"int64 list", []int64{1 << 33, 1}, 1<<33 + 1, true},
+		{"string", "100", 0, false},
+		{"nil", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := counter(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParserCountersBeyond32Bits(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	// The 64-bit byte count wins over the 32-bit one whichever comes first
+	for _, order := range []string{"32-bit first", "64-bit first"} {
+		t.Run(order, func(t *testing.T) {
+			builder := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_CHANNEL).
+				AddString(MQCA_CHANNEL_NAME, "TO.REMOTE")
+			if order == "32-bit first" {
+				builder.AddInteger(MQIACH_BYTES, math.MinInt32).AddInteger64(MQIAMO64_BYTES, 6<<30)
+			} else {
+				builder.AddInteger64(MQIAMO64_BYTES, 6<<30).AddInteger(MQIACH_BYTES, math.MinInt32)
+			}
+			builder.AddInteger(MQIACH_MSGS, -2)
+
+			data, err := parser.ParseMessage(builder.Bytes(), "statistics")
+			require.NoError(t, err)
+			stats := data.(*StatisticsData).ChannelStats
+			assert.Equal(t, int64(6<<30), stats.Bytes)
+			assert.Equal(t, int64(math.MaxUint32-1), stats.Messages, "wrapped count is read as unsigned")
+		})
+	}
+
+	message := NewMessageBuilder(MQCFT_ACCOUNTING, MQCMD_ACCOUNTING_MQI).
+		AddString(MQCA_APPL_NAME, "payments").
+		AddIntegerList(MQIAMO_PUTS, math.MaxInt32, 10).
+		AddInteger64List(MQIAMO64_PUT_BYTES, 5<<32, 1<<32).
+		Bytes()
+
+	data, err := parser.ParseMessage(message, "accounting")
+	require.NoError(t, err)
+	acct := data.(*AccountingData)
+	assert.Equal(t, int64(math.MaxInt32+10), acct.Operations.Puts, "persistent and non-persistent counts are summed")
+	assert.Equal(t, int64(6<<32), acct.Operations.PutBytes)
+	assert.Equal(t, []int32{math.MaxInt32, 10}, acct.Parameters["param_17"])
+}
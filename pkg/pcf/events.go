@@ -0,0 +1,145 @@
+package pcf
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+)
+
+// Event commands, identifying the category of an instrumentation event
+const (
+	MQCMD_CONFIG_EVENT  = 43
+	MQCMD_Q_MGR_EVENT   = 44
+	MQCMD_PERFM_EVENT   = 45
+	MQCMD_CHANNEL_EVENT = 46
+	MQCMD_LOGGER_EVENT  = 91
+	MQCMD_COMMAND_EVENT = 99
+)
+
+// Events carry the queue manager name in parameter 2015 rather than the
+// MQCA_Q_MGR_NAME used for statistics
+const MQCA_EVENT_Q_MGR_NAME = 2015
+
+var eventCommandNames = map[int32]string{
+	MQCMD_CONFIG_EVENT:  "CONFIG_EVENT",
+	MQCMD_Q_MGR_EVENT:   "Q_MGR_EVENT",
+	MQCMD_PERFM_EVENT:   "PERFM_EVENT",
+	MQCMD_CHANNEL_EVENT: "CHANNEL_EVENT",
+	MQCMD_LOGGER_EVENT:  "LOGGER_EVENT",
+	MQCMD_COMMAND_EVENT: "COMMAND_EVENT",
+}
+
+// Reason codes carried by the common queue manager, performance, channel and configuration events
+var eventReasonNames = map[int32]string{
+	2016: "MQRC_GET_INHIBITED",
+	2035: "MQRC_NOT_AUTHORIZED",
+	2051: "MQRC_PUT_INHIBITED",
+	2053: "MQRC_Q_FULL",
+	2085: "MQRC_UNKNOWN_OBJECT_NAME",
+	2087: "MQRC_UNKNOWN_REMOTE_Q_MGR",
+	2222: "MQRC_Q_MGR_ACTIVE",
+	2223: "MQRC_Q_MGR_NOT_ACTIVE",
+	2224: "MQRC_Q_DEPTH_HIGH",
+	2225: "MQRC_Q_DEPTH_LOW",
+	2226: "MQRC_Q_SERVICE_INTERVAL_HIGH",
+	2227: "MQRC_Q_SERVICE_INTERVAL_OK",
+	2279: "MQRC_CHANNEL_STOPPED_BY_USER",
+	2282: "MQRC_CHANNEL_STARTED",
+	2283: "MQRC_CHANNEL_STOPPED",
+	2284: "MQRC_CHANNEL_CONV_ERROR",
+	2295: "MQRC_CHANNEL_ACTIVATED",
+	2296: "MQRC_CHANNEL_NOT_ACTIVATED",
+	2367: "MQRC_CONFIG_CREATE_OBJECT",
+	2368: "MQRC_CONFIG_CHANGE_OBJECT",
+	2369: "MQRC_CONFIG_DELETE_OBJECT",
+	2370: "MQRC_CONFIG_REFRESH_OBJECT",
+	2371: "MQRC_CHANNEL_SSL_ERROR",
+	2411: "MQRC_LOGGER_STATUS",
+	2412: "MQRC_COMMAND_MQSC",
+	2413: "MQRC_COMMAND_PCF",
+}
+
+// EventData represents a parsed instrumentation event
+type EventData struct {
+	Type         string                 `json:"type"`
+	EventType    string                 `json:"event_type"`
+	Reason       int32                  `json:"reason"`
+	ReasonName   string                 `json:"reason_name"`
+	QueueManager string                 `json:"queue_manager,omitempty"`
+	ObjectName   string                 `json:"object_name,omitempty"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Parameters   map[string]interface{} `json:"parameters"`
+}
+
+// EventTypeName returns the name of an event command (e.g. PERFM_EVENT)
+func EventTypeName(command int32) string {
+	if name, ok := eventCommandNames[command]; ok {
+		return name
+	}
+	return fmt.Sprintf("EVENT_%d", command)
+}
+
+// EventReasonName returns the MQRC name of an event reason code
+func EventReasonName(reason int32) string {
+	if name, ok := eventReasonNames[reason]; ok {
+		return name
+	}
+	return fmt.Sprintf("MQRC_%d", reason)
+}
+
+// ParseEvent parses an event message read from one of the SYSTEM.ADMIN.*.EVENT queues.
+// The reason code in the header identifies the event, e.g. MQRC_Q_DEPTH_HIGH.
+func (p *Parser) ParseEvent(data []byte) (*EventData, error) {
+	if len(data) < 36 {
+		return nil, fmt.Errorf("message too short to be a valid PCF message")
+	}
+
+	header, err := p.parseHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PCF header: %w", err)
+	}
+
+	if header.Type != MQCFT_EVENT {
+		return nil, fmt.Errorf("not an event message (PCF type %d)", header.Type)
+	}
+
+	p.logger.WithFields(logging.Fields{
+		"command":         header.Command,
+		"reason":          header.Reason,
+		"parameter_count": header.ParameterCount,
+	}).Debug("Parsing PCF event")
+
+	buf := p.getParams()
+	parameters, _, err := p.parseParameters(data[36:], header.ParameterCount, *buf)
+	defer p.putParams(buf, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PCF parameters: %w", err)
+	}
+
+	event := &EventData{
+		Type:       "event",
+		EventType:  EventTypeName(header.Command),
+		Reason:     header.Reason,
+		ReasonName: EventReasonName(header.Reason),
+		Timestamp:  time.Now(),
+		Parameters: p.convertParameters(parameters),
+	}
+
+	for _, param := range parameters {
+		val, ok := param.Value.(string)
+		if !ok {
+			continue
+		}
+		switch param.Parameter {
+		case MQCA_EVENT_Q_MGR_NAME:
+			event.QueueManager = val
+		case MQCA_Q_NAME, MQCA_CHANNEL_NAME:
+			if event.ObjectName == "" {
+				event.ObjectName = val
+			}
+		}
+	}
+
+	return event, nil
+}
@@ -0,0 +1,71 @@
+package pcf
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvent(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	data := NewMessageBuilder(MQCFT_EVENT, MQCMD_PERFM_EVENT).
+		SetReason(1, 2224).
+		AddString(MQCA_EVENT_Q_MGR_NAME, "QM1").
+		AddString(MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(MQIA_CURRENT_Q_DEPTH, 4000).
+		Bytes()
+
+	event, err := parser.ParseEvent(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "event", event.Type)
+	assert.Equal(t, "PERFM_EVENT", event.EventType)
+	assert.Equal(t, int32(2224), event.Reason)
+	assert.Equal(t, "MQRC_Q_DEPTH_HIGH", event.ReasonName)
+	assert.Equal(t, "QM1", event.QueueManager)
+	assert.Equal(t, "APP.ORDERS", event.ObjectName)
+	assert.Equal(t, int32(4000), event.Parameters["param_3"])
+}
+
+func TestParseEvent_ChannelEvent(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	data := NewMessageBuilder(MQCFT_EVENT, MQCMD_CHANNEL_EVENT).
+		SetReason(0, 2283).
+		AddString(MQCA_CHANNEL_NAME, "TO.QM2").
+		Bytes()
+
+	event, err := parser.ParseEvent(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, "CHANNEL_EVENT", event.EventType)
+	assert.Equal(t, "MQRC_CHANNEL_STOPPED", event.ReasonName)
+	assert.Equal(t, "TO.QM2", event.ObjectName)
+}
+
+func TestParseEvent_RejectsOtherMessages(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	_, err := parser.ParseEvent(NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).Bytes())
+	assert.Error(t, err)
+
+	_, err = parser.ParseEvent([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestEventNames(t *testing.T) {
+	assert.Equal(t, "Q_MGR_EVENT", EventTypeName(MQCMD_Q_MGR_EVENT))
+	assert.Equal(t, "EVENT_12", EventTypeName(12))
+	assert.Equal(t, "MQRC_NOT_AUTHORIZED", EventReasonName(2035))
+	assert.Equal(t, "MQRC_9999", EventReasonName(9999))
+}
@@ -0,0 +1,44 @@
+package pcf
+
+import "sync"
+
+// maxInternedNames bounds the names a parser keeps. Once reached the cache starts over,
+// so a stream of ever new names, such as dynamic reply queues, cannot grow it unbounded.
+const maxInternedNames = 16384
+
+// internCache hands out one string instance per object name. Queue, channel and
+// application names repeat across thousands of messages a cycle, so interning them saves
+// an allocation per parameter and lets labels and map keys share the same instances.
+type internCache struct {
+	mu    sync.Mutex
+	names map[string]string
+}
+
+// intern returns the cached string equal to b, adding it if it is not cached yet
+func (c *internCache) intern(b []byte) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Indexing with string(b) does not allocate
+	if s, ok := c.names[string(b)]; ok {
+		return s
+	}
+
+	s := string(b)
+	if c.names == nil || len(c.names) >= maxInternedNames {
+		c.names = make(map[string]string)
+	}
+	c.names[s] = s
+	return s
+}
+
+// isObjectName reports whether a string parameter names an object or identity that
+// repeats across messages
+func isObjectName(parameter int32) bool {
+	switch parameter {
+	case MQCA_Q_NAME, MQCA_Q_MGR_NAME, MQCA_CHANNEL_NAME, MQCA_CONNECTION_NAME,
+		MQCA_APPL_NAME, MQCACF_USER_IDENTIFIER:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package pcf
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserInternsObjectNames(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	message := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		AddString(MQCA_Q_NAME, "ORDERS.IN").
+		AddString(MQCACF_COMMAND_TIME, "2026-03-14 10:00:00").
+		Bytes()
+
+	var names, times []string
+	for i := 0; i < 2; i++ {
+		data, err := parser.ParseMessage(message, "statistics")
+		require.NoError(t, err)
+		stats := data.(*StatisticsData)
+		names = append(names, stats.QueueStats.QueueName)
+		times = append(times, stats.Parameters[fmt.Sprintf("param_%d", MQCACF_COMMAND_TIME)].(string))
+	}
+
+	assert.Equal(t, "ORDERS.IN", names[0])
+	assert.Same(t, unsafe.StringData(names[0]), unsafe.StringData(names[1]), "queue names are interned")
+	assert.NotSame(t, unsafe.StringData(times[0]), unsafe.StringData(times[1]), "other strings are not")
+}
+
+func TestInternCacheIsBounded(t *testing.T) {
+	var cache internCache
+	first := cache.intern([]byte("Q0"))
+	assert.Same(t, unsafe.StringData(first), unsafe.StringData(cache.intern([]byte("Q0"))))
+
+	for i := 1; i <= maxInternedNames; i++ {
+		cache.intern([]byte(fmt.Sprintf("Q%d", i)))
+	}
+	assert.LessOrEqual(t, len(cache.names), maxInternedNames)
+	assert.Equal(t, "Q0", cache.intern([]byte("Q0")))
+}
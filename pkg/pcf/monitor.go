@@ -0,0 +1,280 @@
+package pcf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resource monitoring parameters, carried by the metadata and metric publications of the
+// $SYS/MQ/INFO/QMGR/<qmgr>/Monitor topics
+const (
+	MQIAMO_MONITOR_CLASS      = 839
+	MQIAMO_MONITOR_TYPE       = 840
+	MQIAMO_MONITOR_ELEMENT    = 841
+	MQIAMO_MONITOR_DATATYPE   = 842
+	MQIAMO_MONITOR_FLAGS      = 843
+	MQIAMO64_MONITOR_INTERVAL = 845
+	MQCAMO_MONITOR_CLASS      = 2713
+	MQCAMO_MONITOR_TYPE       = 2714
+	MQCAMO_MONITOR_DESC       = 2715
+	MQCA_TOPIC_STRING         = 2094
+	MQIACF_OBJECT_TYPE        = 1016
+
+	// Groups of the metadata, one per class, type or element
+	MQGACF_MONITOR_CLASS   = 8015
+	MQGACF_MONITOR_TYPE    = 8016
+	MQGACF_MONITOR_ELEMENT = 8017
+)
+
+// Data types of monitor elements, giving the unit of their values
+const (
+	MQIAMO_MONITOR_UNIT       = 1
+	MQIAMO_MONITOR_DELTA      = 2
+	MQIAMO_MONITOR_LSN        = 3
+	MQIAMO_MONITOR_HUNDREDTHS = 100
+	MQIAMO_MONITOR_KB         = 1024
+	MQIAMO_MONITOR_PERCENT    = 10000
+	MQIAMO_MONITOR_MICROSEC   = 1000000
+	MQIAMO_MONITOR_MB         = 1048576
+	MQIAMO_MONITOR_GB         = 100000000
+)
+
+// MQIAMO_MONITOR_FLAGS_OBJNAME marks a class published per object, such as STATQ, whose
+// metric topics hold a %s for the object name
+const MQIAMO_MONITOR_FLAGS_OBJNAME = 1
+
+// MonitorClass is a class of resource monitoring metrics, such as CPU, DISK or STATQ
+type MonitorClass struct {
+	ID          int32
+	Name        string
+	Description string
+	PerObject   bool   // published per object, such as per queue for STATQ
+	TypesTopic  string // topic of the metadata of the types of the class
+}
+
+// MonitorType is a type of metrics within a class, such as SystemSummary of CPU or PUT
+// of STATQ
+type MonitorType struct {
+	ID            int32
+	Name          string
+	Description   string
+	ElementsTopic string // topic of the metadata of the elements of the type
+}
+
+// MonitorElement is one metric of a type, identified in the publications by its ID
+type MonitorElement struct {
+	ID          int32
+	DataType    int32 // unit of the values, such as MQIAMO_MONITOR_PERCENT
+	Description string
+}
+
+// Delta reports whether the values of the element count events since the previous
+// publication, so the values of successive publications add up
+func (e MonitorElement) Delta() bool {
+	return e.DataType == MQIAMO_MONITOR_DELTA
+}
+
+// Normalise converts a published value to base units: percentages from hundredths,
+// seconds from microseconds and bytes from megabytes and gigabytes. Negative values,
+// which some queue managers publish for uninitialised elements, become 0.
+func (e MonitorElement) Normalise(value int64) float64 {
+	f := float64(max(value, 0))
+	switch e.DataType {
+	case MQIAMO_MONITOR_PERCENT, MQIAMO_MONITOR_HUNDREDTHS:
+		return f / 100
+	case MQIAMO_MONITOR_MICROSEC:
+		return f / 1e6
+	case MQIAMO_MONITOR_MB:
+		return f * (1 << 20)
+	case MQIAMO_MONITOR_GB:
+		return f * (1 << 30)
+	}
+	return f
+}
+
+// MonitorPublication is one publication of the metrics of a type, for the queue manager
+// or for one object
+type MonitorPublication struct {
+	QueueManager string
+	ObjectName   string // queue name, empty for the metrics of the queue manager
+	Class        int32
+	Type         int32
+	Interval     int64           // microseconds covered by the values
+	Values       map[int32]int64 // by element ID
+}
+
+// ParseMonitorClasses parses the publication of the METADATA/CLASSES topic
+func (p *Parser) ParseMonitorClasses(data []byte) ([]MonitorClass, error) {
+	parameters, err := p.parseMonitor(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []MonitorClass
+	for _, param := range parameters {
+		if param.Type == MQCFT_GROUP {
+			classes = append(classes, MonitorClass{})
+			continue
+		}
+		if len(classes) == 0 {
+			continue
+		}
+
+		class := &classes[len(classes)-1]
+		switch param.Parameter {
+		case MQIAMO_MONITOR_CLASS:
+			class.ID = monitorInt(param)
+		case MQIAMO_MONITOR_FLAGS:
+			class.PerObject = monitorInt(param)&MQIAMO_MONITOR_FLAGS_OBJNAME != 0
+		case MQCAMO_MONITOR_CLASS:
+			class.Name = monitorString(param)
+		case MQCAMO_MONITOR_DESC:
+			class.Description = monitorString(param)
+		case MQCA_TOPIC_STRING:
+			class.TypesTopic = monitorString(param)
+		}
+	}
+	return classes, nil
+}
+
+// ParseMonitorTypes parses the publication of the TYPES metadata topic of a class
+func (p *Parser) ParseMonitorTypes(data []byte) ([]MonitorType, error) {
+	parameters, err := p.parseMonitor(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var types []MonitorType
+	for _, param := range parameters {
+		if param.Type == MQCFT_GROUP {
+			types = append(types, MonitorType{})
+			continue
+		}
+		if len(types) == 0 {
+			continue
+		}
+
+		typ := &types[len(types)-1]
+		switch param.Parameter {
+		case MQIAMO_MONITOR_TYPE:
+			typ.ID = monitorInt(param)
+		case MQCAMO_MONITOR_TYPE:
+			typ.Name = monitorString(param)
+		case MQCAMO_MONITOR_DESC:
+			typ.Description = monitorString(param)
+		case MQCA_TOPIC_STRING:
+			typ.ElementsTopic = monitorString(param)
+		}
+	}
+	return types, nil
+}
+
+// ParseMonitorElements parses the publication of the ELEMENTS metadata topic of a type.
+// It returns the topic the metrics of the type are published on, which holds a %s for
+// the object name in the classes published per object, and the elements of the type.
+func (p *Parser) ParseMonitorElements(data []byte) (string, []MonitorElement, error) {
+	parameters, err := p.parseMonitor(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The metric topic is the one parameter outside the groups of the elements
+	var topic string
+	var elements []MonitorElement
+	for _, param := range parameters {
+		if param.Type == MQCFT_GROUP {
+			elements = append(elements, MonitorElement{})
+			continue
+		}
+		if param.Parameter == MQCA_TOPIC_STRING {
+			topic = monitorString(param)
+			continue
+		}
+		if len(elements) == 0 {
+			continue
+		}
+
+		element := &elements[len(elements)-1]
+		switch param.Parameter {
+		case MQIAMO_MONITOR_ELEMENT:
+			element.ID = monitorInt(param)
+		case MQIAMO_MONITOR_DATATYPE:
+			element.DataType = monitorInt(param)
+		case MQCAMO_MONITOR_DESC:
+			element.Description = monitorString(param)
+		}
+	}
+	return topic, elements, nil
+}
+
+// ParseMonitorPublication parses a publication of the metrics of a type. Every integer
+// parameter besides the class, type, interval and flags is the value of the element
+// with its ID.
+func (p *Parser) ParseMonitorPublication(data []byte) (*MonitorPublication, error) {
+	parameters, err := p.parseMonitor(data)
+	if err != nil {
+		return nil, err
+	}
+
+	publication := &MonitorPublication{
+		Class:  -1,
+		Values: make(map[int32]int64),
+	}
+	for _, param := range parameters {
+		switch param.Parameter {
+		case MQCA_Q_MGR_NAME:
+			publication.QueueManager = monitorString(param)
+		case MQCA_Q_NAME:
+			publication.ObjectName = monitorString(param)
+		case MQIAMO_MONITOR_CLASS:
+			publication.Class = monitorInt(param)
+		case MQIAMO_MONITOR_TYPE:
+			publication.Type = monitorInt(param)
+		case MQIAMO64_MONITOR_INTERVAL:
+			if val, ok := counter(param.Value); ok {
+				publication.Interval = val
+			}
+		case MQIAMO_MONITOR_FLAGS, MQIACF_OBJECT_TYPE:
+		default:
+			if val, ok := counter(param.Value); ok {
+				publication.Values[param.Parameter] = val
+			}
+		}
+	}
+
+	if publication.Class < 0 {
+		return nil, fmt.Errorf("not a resource monitoring publication (no monitor class)")
+	}
+	return publication, nil
+}
+
+// parseMonitor parses the parameters of a resource monitoring publication. Groups are
+// kept as parameters of type MQCFT_GROUP, followed by their members.
+func (p *Parser) parseMonitor(data []byte) ([]PCFParameter, error) {
+	if len(data) < 36 {
+		return nil, fmt.Errorf("message too short to be a valid PCF message")
+	}
+
+	header, err := p.parseHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PCF header: %w", err)
+	}
+
+	parameters, _, err := p.parseParameters(data[36:], header.ParameterCount, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PCF parameters: %w", err)
+	}
+	return parameters, nil
+}
+
+// monitorInt returns the value of an integer parameter
+func monitorInt(param PCFParameter) int32 {
+	val, _ := counter(param.Value)
+	return int32(val)
+}
+
+// monitorString returns the value of a string parameter without its padding
+func monitorString(param PCFParameter) string {
+	val, _ := param.Value.(string)
+	return strings.TrimSpace(val)
+}
@@ -0,0 +1,108 @@
+package pcf
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMonitorMetadata(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	classes, err := parser.ParseMonitorClasses(NewMessageBuilder(MQCFT_STATISTICS, 0).
+		AddGroup(MQGACF_MONITOR_CLASS, 5).
+		AddInteger(MQIAMO_MONITOR_CLASS, 0).
+		AddInteger(MQIAMO_MONITOR_FLAGS, 0).
+		AddString(MQCAMO_MONITOR_CLASS, "CPU").
+		AddString(MQCAMO_MONITOR_DESC, "Platform central processing units").
+		AddString(MQCA_TOPIC_STRING, "$SYS/MQ/INFO/QMGR/QM1/Monitor/METADATA/CPU/TYPES").
+		AddGroup(MQGACF_MONITOR_CLASS, 5).
+		AddInteger(MQIAMO_MONITOR_CLASS, 4).
+		AddInteger(MQIAMO_MONITOR_FLAGS, MQIAMO_MONITOR_FLAGS_OBJNAME).
+		AddString(MQCAMO_MONITOR_CLASS, "STATQ").
+		AddString(MQCAMO_MONITOR_DESC, "API per-queue activity").
+		AddString(MQCA_TOPIC_STRING, "$SYS/MQ/INFO/QMGR/QM1/Monitor/METADATA/STATQ/TYPES").
+		Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []MonitorClass{
+		{ID: 0, Name: "CPU", Description: "Platform central processing units", TypesTopic: "$SYS/MQ/INFO/QMGR/QM1/Monitor/METADATA/CPU/TYPES"},
+		{ID: 4, Name: "STATQ", Description: "API per-queue activity", PerObject: true, TypesTopic: "$SYS/MQ/INFO/QMGR/QM1/Monitor/METADATA/STATQ/TYPES"},
+	}, classes)
+
+	types, err := parser.ParseMonitorTypes(NewMessageBuilder(MQCFT_STATISTICS, 0).
+		AddGroup(MQGACF_MONITOR_TYPE, 4).
+		AddInteger(MQIAMO_MONITOR_TYPE, 1).
+		AddString(MQCAMO_MONITOR_TYPE, "QMgrSummary").
+		AddString(MQCAMO_MONITOR_DESC, "CPU performance - running queue manager").
+		AddString(MQCA_TOPIC_STRING, "$SYS/MQ/INFO/QMGR/QM1/Monitor/METADATA/CPU/QMgrSummary").
+		Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []MonitorType{{
+		ID:            1,
+		Name:          "QMgrSummary",
+		Description:   "CPU performance - running queue manager",
+		ElementsTopic: "$SYS/MQ/INFO/QMGR/QM1/Monitor/METADATA/CPU/QMgrSummary",
+	}}, types)
+
+	topic, elements, err := parser.ParseMonitorElements(NewMessageBuilder(MQCFT_STATISTICS, 0).
+		AddString(MQCA_TOPIC_STRING, "$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/%s/PUT").
+		AddGroup(MQGACF_MONITOR_ELEMENT, 3).
+		AddInteger(MQIAMO_MONITOR_ELEMENT, 0).
+		AddInteger(MQIAMO_MONITOR_DATATYPE, MQIAMO_MONITOR_DELTA).
+		AddString(MQCAMO_MONITOR_DESC, "MQPUT/MQPUT1 count").
+		AddGroup(MQGACF_MONITOR_ELEMENT, 3).
+		AddInteger(MQIAMO_MONITOR_ELEMENT, 2).
+		AddInteger(MQIAMO_MONITOR_DATATYPE, MQIAMO_MONITOR_MICROSEC).
+		AddString(MQCAMO_MONITOR_DESC, "average queue time").
+		Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/%s/PUT", topic)
+	assert.Equal(t, []MonitorElement{
+		{ID: 0, DataType: MQIAMO_MONITOR_DELTA, Description: "MQPUT/MQPUT1 count"},
+		{ID: 2, DataType: MQIAMO_MONITOR_MICROSEC, Description: "average queue time"},
+	}, elements)
+}
+
+func TestParseMonitorPublication(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	publication, err := parser.ParseMonitorPublication(NewMessageBuilder(MQCFT_STATISTICS, 0).
+		AddString(MQCA_Q_MGR_NAME, "QM1").
+		AddString(MQCA_Q_NAME, "APP.ORDERS").
+		AddInteger(MQIACF_OBJECT_TYPE, 1).
+		AddInteger(MQIAMO_MONITOR_CLASS, 4).
+		AddInteger(MQIAMO_MONITOR_TYPE, 2).
+		AddInteger64(MQIAMO64_MONITOR_INTERVAL, 10000000).
+		AddInteger(MQIAMO_MONITOR_FLAGS, 0).
+		AddInteger64(0, 25).
+		AddInteger64(2, 1500).
+		Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, &MonitorPublication{
+		QueueManager: "QM1",
+		ObjectName:   "APP.ORDERS",
+		Class:        4,
+		Type:         2,
+		Interval:     10000000,
+		Values:       map[int32]int64{0: 25, 2: 1500},
+	}, publication)
+
+	_, err = parser.ParseMonitorPublication(NewMessageBuilder(MQCFT_STATISTICS, 0).AddInteger64(0, 25).Bytes())
+	assert.Error(t, err)
+}
+
+func TestMonitorElementNormalise(t *testing.T) {
+	assert.Equal(t, 12.5, MonitorElement{DataType: MQIAMO_MONITOR_PERCENT}.Normalise(1250))
+	assert.Equal(t, 0.0015, MonitorElement{DataType: MQIAMO_MONITOR_MICROSEC}.Normalise(1500))
+	assert.Equal(t, float64(2<<20), MonitorElement{DataType: MQIAMO_MONITOR_MB}.Normalise(2))
+	assert.Equal(t, float64(7), MonitorElement{DataType: MQIAMO_MONITOR_DELTA}.Normalise(7))
+	assert.Equal(t, float64(0), MonitorElement{DataType: MQIAMO_MONITOR_UNIT}.Normalise(-3))
+	assert.True(t, MonitorElement{DataType: MQIAMO_MONITOR_DELTA}.Delta())
+}
@@ -1,11 +1,13 @@
 package pcf
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"sync"
 	"time"
 
-	"github.com/sirupsen/logrus"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
 )
 
 // PCF Parameter Types
@@ -32,6 +34,8 @@ const (
 	MQCFT_GROUP              = 0x00000013
 	MQCFT_STATISTICS         = 0x00000014
 	MQCFT_ACCOUNTING         = 0x00000015
+	MQCFT_INTEGER64          = 0x00000017
+	MQCFT_INTEGER64_LIST     = 0x00000019
 )
 
 // Common IBM MQ Constants
@@ -45,6 +49,11 @@ const (
 	MQCMD_ACCOUNTING_MQI = 0x0000008A
 	MQCMD_ACCOUNTING_Q   = 0x0000008B
 
+	// Completion codes of the PCF header
+	MQCC_OK      = 0
+	MQCC_WARNING = 1
+	MQCC_FAILED  = 2
+
 	// Common Parameters
 	MQCA_Q_NAME            = 2016
 	MQCA_Q_MGR_NAME        = 2002
@@ -65,6 +74,7 @@ const (
 	MQIACH_MSGS    = 1501
 	MQIACH_BYTES   = 1502
 	MQIACH_BATCHES = 1503
+	MQIAMO64_BYTES = 746 // 64-bit byte count, preferred over MQIACH_BYTES
 
 	// MQI Statistics
 	MQIAMO_OPENS    = 3
@@ -74,6 +84,11 @@ const (
 	MQIAMO_COMMITS  = 12
 	MQIAMO_BACKOUTS = 13
 
+	// Accounting identity and volume
+	MQCACF_USER_IDENTIFIER = 3025
+	MQIAMO64_GET_BYTES     = 747
+	MQIAMO64_PUT_BYTES     = 748
+
 	// Time parameters
 	MQCACF_COMMAND_TIME    = 3603
 	MQIACF_SEQUENCE_NUMBER = 1001
@@ -109,39 +124,50 @@ type StatisticsData struct {
 	QueueStats   *QueueStatistics       `json:"queue_stats,omitempty"`
 	ChannelStats *ChannelStatistics     `json:"channel_stats,omitempty"`
 	MQIStats     *MQIStatistics         `json:"mqi_stats,omitempty"`
+	Truncated    bool                   `json:"truncated,omitempty"` // message ended before all its parameters
+	CompCode     int32                  `json:"comp_code,omitempty"` // completion code of the PCF header, MQCC_OK for normal records
+	Reason       int32                  `json:"reason,omitempty"`    // reason code of the PCF header
 }
 
 // QueueStatistics represents queue-specific statistics
 type QueueStatistics struct {
 	QueueName    string `json:"queue_name"`
-	CurrentDepth int32  `json:"current_depth"`
-	HighDepth    int32  `json:"high_depth"`
-	InputCount   int32  `json:"input_count"`
-	OutputCount  int32  `json:"output_count"`
-	EnqueueCount int32  `json:"enqueue_count"`
-	DequeueCount int32  `json:"dequeue_count"`
+	CurrentDepth int64  `json:"current_depth"`
+	HighDepth    int64  `json:"high_depth"`
+	InputCount   int64  `json:"input_count"`
+	OutputCount  int64  `json:"output_count"`
+	EnqueueCount int64  `json:"enqueue_count"`
+	DequeueCount int64  `json:"dequeue_count"`
 	HasReaders   bool   `json:"has_readers"`
 	HasWriters   bool   `json:"has_writers"`
 }
 
+// ProducerWithoutConsumer reports whether messages were put to the queue in the interval
+// while nothing got them and no application had it open for input
+func (q *QueueStatistics) ProducerWithoutConsumer() bool {
+	producing := q.HasWriters || q.OutputCount > 0 || q.EnqueueCount > 0
+	consuming := q.HasReaders || q.InputCount > 0 || q.DequeueCount > 0
+	return producing && !consuming
+}
+
 // ChannelStatistics represents channel-specific statistics
 type ChannelStatistics struct {
 	ChannelName    string `json:"channel_name"`
 	ConnectionName string `json:"connection_name"`
-	Messages       int32  `json:"messages"`
+	Messages       int64  `json:"messages"`
 	Bytes          int64  `json:"bytes"`
-	Batches        int32  `json:"batches"`
+	Batches        int64  `json:"batches"`
 }
 
 // MQIStatistics represents MQI-specific statistics
 type MQIStatistics struct {
 	ApplicationName string `json:"application_name"`
-	Opens           int32  `json:"opens"`
-	Closes          int32  `json:"closes"`
-	Puts            int32  `json:"puts"`
-	Gets            int32  `json:"gets"`
-	Commits         int32  `json:"commits"`
-	Backouts        int32  `json:"backouts"`
+	Opens           int64  `json:"opens"`
+	Closes          int64  `json:"closes"`
+	Puts            int64  `json:"puts"`
+	Gets            int64  `json:"gets"`
+	Commits         int64  `json:"commits"`
+	Backouts        int64  `json:"backouts"`
 }
 
 // AccountingData represents parsed accounting data
@@ -152,6 +178,10 @@ type AccountingData struct {
 	Parameters     map[string]interface{} `json:"parameters"`
 	ConnectionInfo *ConnectionInfo        `json:"connection_info,omitempty"`
 	Operations     *OperationCounts       `json:"operations,omitempty"`
+	Queues         []QueueOperations      `json:"queues,omitempty"`
+	Truncated      bool                   `json:"truncated,omitempty"` // message ended before all its parameters
+	CompCode       int32                  `json:"comp_code,omitempty"` // completion code of the PCF header, MQCC_OK for normal records
+	Reason         int32                  `json:"reason,omitempty"`    // reason code of the PCF header
 }
 
 // ConnectionInfo represents connection-specific accounting data
@@ -159,28 +189,42 @@ type ConnectionInfo struct {
 	ChannelName     string    `json:"channel_name"`
 	ConnectionName  string    `json:"connection_name"`
 	ApplicationName string    `json:"application_name"`
+	UserIdentifier  string    `json:"user_identifier,omitempty"`
 	ConnectTime     time.Time `json:"connect_time"`
 	DisconnectTime  time.Time `json:"disconnect_time"`
 }
 
 // OperationCounts represents operation counts from accounting data
 type OperationCounts struct {
-	Gets     int32 `json:"gets"`
-	Puts     int32 `json:"puts"`
-	Browses  int32 `json:"browses"`
-	Opens    int32 `json:"opens"`
-	Closes   int32 `json:"closes"`
-	Commits  int32 `json:"commits"`
-	Backouts int32 `json:"backouts"`
+	Gets     int64 `json:"gets"`
+	Puts     int64 `json:"puts"`
+	Browses  int64 `json:"browses"`
+	Opens    int64 `json:"opens"`
+	Closes   int64 `json:"closes"`
+	Commits  int64 `json:"commits"`
+	Backouts int64 `json:"backouts"`
+	PutBytes int64 `json:"put_bytes"`
+	GetBytes int64 `json:"get_bytes"`
+}
+
+// QueueOperations are the operation counts of one queue in a queue accounting record
+type QueueOperations struct {
+	QueueName string `json:"queue_name"`
+	Puts      int64  `json:"puts"`
+	Gets      int64  `json:"gets"`
+	PutBytes  int64  `json:"put_bytes"`
+	GetBytes  int64  `json:"get_bytes"`
 }
 
 // Parser handles PCF message parsing
 type Parser struct {
-	logger *logrus.Logger
+	logger logging.Logger
+	params sync.Pool // *[]PCFParameter reused across messages
+	names  internCache
 }
 
 // NewParser creates a new PCF parser instance
-func NewParser(logger *logrus.Logger) *Parser {
+func NewParser(logger logging.Logger) *Parser {
 	return &Parser{
 		logger: logger,
 	}
@@ -197,32 +241,78 @@ func (p *Parser) ParseMessage(data []byte, msgType string) (interface{}, error)
 		return nil, fmt.Errorf("failed to parse PCF header: %w", err)
 	}
 
-	p.logger.WithFields(logrus.Fields{
+	p.logger.WithFields(logging.Fields{
 		"command":         header.Command,
 		"type":            header.Type,
 		"parameter_count": header.ParameterCount,
 		"message_type":    msgType,
 	}).Debug("Parsing PCF message")
 
-	parameters, err := p.parseParameters(data[36:], header.ParameterCount)
+	isAccounting := header.Command == MQCMD_ACCOUNTING_Q || header.Command == MQCMD_ACCOUNTING_MQI
+	if isAccounting && len(data) > streamThreshold {
+		return p.parseAccountingStream(data)
+	}
+
+	buf := p.getParams()
+	parameters, complete, err := p.parseParameters(data[36:], header.ParameterCount, *buf)
+	defer p.putParams(buf, parameters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse PCF parameters: %w", err)
 	}
+	if !complete {
+		// A truncated message still yields the parameters that fit
+		p.logger.WithFields(logging.Fields{
+			"command":         header.Command,
+			"parameter_count": header.ParameterCount,
+			"parsed":          len(parameters),
+			"message_length":  len(data),
+		}).Warn("Message is truncated, processing the complete parameters")
+	}
 
 	// Determine if this is statistics or accounting data based on command
 	switch {
 	case header.Command == MQCMD_STATISTICS_Q || header.Command == MQCMD_STATISTICS_CHANNEL || header.Command == MQCMD_STATISTICS_MQI:
-		return p.parseStatistics(header, parameters)
-	case header.Command == MQCMD_ACCOUNTING_Q || header.Command == MQCMD_ACCOUNTING_MQI:
-		return p.parseAccounting(header, parameters)
+		stats, err := p.parseStatistics(header, parameters)
+		if err != nil {
+			return nil, err
+		}
+		stats.Truncated = !complete
+		stats.CompCode, stats.Reason = header.CompCode, header.Reason
+		return stats, nil
+	case isAccounting:
+		acct, err := p.parseAccounting(header, parameters)
+		if err != nil {
+			return nil, err
+		}
+		acct.Truncated = !complete
+		acct.CompCode, acct.Reason = header.CompCode, header.Reason
+		return acct, nil
 	default:
 		// Generic parsing for other message types
-		return &StatisticsData{
-			Type:       msgType,
-			Timestamp:  time.Now(),
-			Parameters: p.convertParameters(parameters),
-		}, nil
+		stats := newStatisticsData()
+		stats.Type = msgType
+		stats.Timestamp = time.Now()
+		stats.Truncated = !complete
+		stats.CompCode, stats.Reason = header.CompCode, header.Reason
+		p.fillParameters(stats.Parameters, parameters)
+		return stats, nil
+	}
+}
+
+// getParams returns a parameter slice from the pool
+func (p *Parser) getParams() *[]PCFParameter {
+	if buf, ok := p.params.Get().(*[]PCFParameter); ok {
+		return buf
 	}
+	return new([]PCFParameter)
+}
+
+// putParams returns the parameters of a parsed message to the pool. The parsed data only
+// holds copies of the values, so the slice is free once the message has been converted.
+func (p *Parser) putParams(buf *[]PCFParameter, parameters []PCFParameter) {
+	clear(parameters)
+	*buf = parameters[:0]
+	p.params.Put(buf)
 }
 
 // parseHeader parses the PCF header
@@ -246,87 +336,133 @@ func (p *Parser) parseHeader(data []byte) (*PCFHeader, error) {
 	return header, nil
 }
 
-// parseParameters parses PCF parameters
-func (p *Parser) parseParameters(data []byte, count int32) ([]*PCFParameter, error) {
-	var parameters []*PCFParameter
+// parseParameters parses PCF parameters into params, reusing its backing array, and
+// returns the parsed parameters. Byte string values alias data rather than copying it.
+// complete is false when the data ends before the parameters do, as in a truncated
+// message; the parameters before that point are still returned.
+func (p *Parser) parseParameters(data []byte, count int32, params []PCFParameter) (parameters []PCFParameter, complete bool, err error) {
+	// Each parameter takes at least 12 bytes, which bounds a corrupt count
+	if n := min(int(count), len(data)/12); cap(params) < n {
+		params = make([]PCFParameter, 0, n)
+	}
+	parameters = params[:0]
 	offset := 0
 
 	for offset < len(data) {
-		if offset+12 > len(data) {
-			p.logger.WithField("remaining_bytes", len(data)-offset).Debug("Not enough bytes for PCF parameter header")
+		param, next, ok := p.readParameter(data, offset)
+		if !ok {
 			break
 		}
+		parameters = append(parameters, param)
+		offset = next
+	}
 
-		param := &PCFParameter{
-			Parameter: int32(binary.LittleEndian.Uint32(data[offset : offset+4])),
-			Type:      int32(binary.LittleEndian.Uint32(data[offset+4 : offset+8])),
-			Length:    int32(binary.LittleEndian.Uint32(data[offset+8 : offset+12])),
-		}
+	// Group parameters are counted once in the header but parsed with their members, so
+	// only fewer parameters than the count show a message cut at a parameter boundary
+	complete = offset >= len(data) && len(parameters) >= int(count)
+	return parameters, complete, nil
+}
 
-		// Validate parameter length
-		if param.Length < 12 || param.Length > 65536 {
-			p.logger.WithFields(logrus.Fields{
-				"parameter": param.Parameter,
-				"type":      param.Type,
-				"length":    param.Length,
-				"offset":    offset,
-			}).Warn("Invalid parameter length, skipping to next message")
-			break
-		}
+// readParameter decodes the parameter at offset and returns it with the offset of the
+// next one. It returns false when the remaining data holds no valid parameter.
+func (p *Parser) readParameter(data []byte, offset int) (PCFParameter, int, bool) {
+	if offset+12 > len(data) {
+		p.logger.WithField("remaining_bytes", len(data)-offset).Debug("Not enough bytes for PCF parameter header")
+		return PCFParameter{}, offset, false
+	}
 
-		if offset+int(param.Length) > len(data) {
-			p.logger.WithFields(logrus.Fields{
-				"parameter":    param.Parameter,
-				"length":       param.Length,
-				"offset":       offset,
-				"data_length":  len(data),
-				"required_end": offset + int(param.Length),
-			}).Warn("Parameter extends beyond data length")
-			break
-		}
+	param := PCFParameter{
+		Parameter: int32(binary.LittleEndian.Uint32(data[offset : offset+4])),
+		Type:      int32(binary.LittleEndian.Uint32(data[offset+4 : offset+8])),
+		Length:    int32(binary.LittleEndian.Uint32(data[offset+8 : offset+12])),
+	}
 
-		// Parse parameter value based on type
-		switch param.Type {
-		case MQCFT_INTEGER:
-			if param.Length >= 16 {
-				param.Value = int32(binary.LittleEndian.Uint32(data[offset+12 : offset+16]))
+	// Validate parameter length
+	if param.Length < 12 || param.Length > 65536 {
+		p.logger.WithFields(logging.Fields{
+			"parameter": param.Parameter,
+			"type":      param.Type,
+			"length":    param.Length,
+			"offset":    offset,
+		}).Warn("Invalid parameter length, skipping to next message")
+		return PCFParameter{}, offset, false
+	}
+
+	if offset+int(param.Length) > len(data) {
+		p.logger.WithFields(logging.Fields{
+			"parameter":    param.Parameter,
+			"length":       param.Length,
+			"offset":       offset,
+			"data_length":  len(data),
+			"required_end": offset + int(param.Length),
+		}).Warn("Parameter extends beyond data length")
+		return PCFParameter{}, offset, false
+	}
+
+	// Parse parameter value based on type
+	switch param.Type {
+	case MQCFT_INTEGER:
+		if param.Length >= 16 {
+			param.Value = int32(binary.LittleEndian.Uint32(data[offset+12 : offset+16]))
+		}
+	case MQCFT_INTEGER64:
+		// 4 reserved bytes precede the 64-bit value
+		if param.Length >= 24 {
+			param.Value = int64(binary.LittleEndian.Uint64(data[offset+16 : offset+24]))
+		}
+	case MQCFT_INTEGER_LIST:
+		// The number of values precedes them
+		if count, ok := listCount(param, data, offset, 4); ok {
+			values := make([]int32, count)
+			for i := range values {
+				start := offset + 16 + i*4
+				values[i] = int32(binary.LittleEndian.Uint32(data[start : start+4]))
 			}
-		case MQCFT_STRING:
-			if param.Length > 12 {
-				strLen := param.Length - 12
-				str := string(data[offset+12 : offset+12+int(strLen)])
-				// Remove null terminators and trim spaces
-				param.Value = p.cleanString(str)
+			param.Value = values
+		}
+	case MQCFT_INTEGER64_LIST:
+		if count, ok := listCount(param, data, offset, 8); ok {
+			values := make([]int64, count)
+			for i := range values {
+				start := offset + 16 + i*8
+				values[i] = int64(binary.LittleEndian.Uint64(data[start : start+8]))
 			}
-		case MQCFT_BYTE_STRING:
-			if param.Length > 12 {
-				dataLen := param.Length - 12
-				param.Value = data[offset+12 : offset+12+int(dataLen)]
+			param.Value = values
+		}
+	case MQCFT_STRING:
+		if param.Length > 12 {
+			// Cut at the null terminator before converting, so only the used
+			// bytes are copied into the string
+			value := trimNull(data[offset+12 : offset+int(param.Length)])
+			if isObjectName(param.Parameter) {
+				param.Value = p.names.intern(value)
+			} else {
+				param.Value = string(value)
 			}
-		default:
-			// Unknown parameter type, skip
-			param.Value = nil
 		}
-
-		parameters = append(parameters, param)
-		offset += int(param.Length)
-
-		// Ensure 4-byte alignment
-		if offset%4 != 0 {
-			offset += 4 - (offset % 4)
+	case MQCFT_BYTE_STRING:
+		if param.Length > 12 {
+			param.Value = data[offset+12 : offset+int(param.Length) : offset+int(param.Length)]
 		}
+	default:
+		// Unknown parameter type, skip
+		param.Value = nil
 	}
 
-	return parameters, nil
+	next := offset + int(param.Length)
+	// Ensure 4-byte alignment
+	if next%4 != 0 {
+		next += 4 - (next % 4)
+	}
+	return param, next, true
 }
 
 // parseStatistics converts parameters to statistics data structure
-func (p *Parser) parseStatistics(header *PCFHeader, parameters []*PCFParameter) (*StatisticsData, error) {
-	stats := &StatisticsData{
-		Type:       "statistics",
-		Timestamp:  time.Now(),
-		Parameters: p.convertParameters(parameters),
-	}
+func (p *Parser) parseStatistics(header *PCFHeader, parameters []PCFParameter) (*StatisticsData, error) {
+	stats := newStatisticsData()
+	stats.Type = "statistics"
+	stats.Timestamp = time.Now()
+	p.fillParameters(stats.Parameters, parameters)
 
 	// Extract common fields
 	for _, param := range parameters {
@@ -359,42 +495,47 @@ func (p *Parser) parseStatistics(header *PCFHeader, parameters []*PCFParameter)
 }
 
 // parseAccounting converts parameters to accounting data structure
-func (p *Parser) parseAccounting(header *PCFHeader, parameters []*PCFParameter) (*AccountingData, error) {
-	acct := &AccountingData{
-		Type:       "accounting",
-		Timestamp:  time.Now(),
-		Parameters: p.convertParameters(parameters),
-	}
+func (p *Parser) parseAccounting(header *PCFHeader, parameters []PCFParameter) (*AccountingData, error) {
+	acct := newAccountingData()
+	acct.Type = "accounting"
+	acct.Timestamp = time.Now()
+	p.fillParameters(acct.Parameters, parameters)
 
 	// Extract common fields
 	for _, param := range parameters {
-		switch param.Parameter {
-		case MQCA_Q_MGR_NAME:
-			if str, ok := param.Value.(string); ok {
-				acct.QueueManager = str
-			}
-		case MQCACF_COMMAND_TIME:
-			if str, ok := param.Value.(string); ok {
-				if t, err := p.parseMQTimestamp(str); err == nil {
-					acct.Timestamp = t
-				}
-			}
-		}
+		p.applyAccounting(acct, param)
 	}
 
 	// Parse accounting-specific data
 	acct.ConnectionInfo = p.parseConnectionInfo(parameters)
 	acct.Operations = p.parseOperationCounts(parameters)
+	acct.Queues = p.parseQueueOperations(parameters)
 
 	return acct, nil
 }
 
+// applyAccounting sets the record field of an accounting message the parameter holds, if any
+func (p *Parser) applyAccounting(acct *AccountingData, param PCFParameter) {
+	switch param.Parameter {
+	case MQCA_Q_MGR_NAME:
+		if str, ok := param.Value.(string); ok {
+			acct.QueueManager = str
+		}
+	case MQCACF_COMMAND_TIME:
+		if str, ok := param.Value.(string); ok {
+			if t, err := p.parseMQTimestamp(str); err == nil {
+				acct.Timestamp = t
+			}
+		}
+	}
+}
+
 // parseQueueStats extracts queue statistics from parameters
-func (p *Parser) parseQueueStats(parameters []*PCFParameter) *QueueStatistics {
-	stats := &QueueStatistics{}
+func (p *Parser) parseQueueStats(parameters []PCFParameter) *QueueStatistics {
+	stats := queueStatsPool.get()
 
 	for _, param := range parameters {
-		if val, ok := param.Value.(int32); ok {
+		if val, ok := counter(param.Value); ok {
 			switch param.Parameter {
 			case MQIA_CURRENT_Q_DEPTH:
 				stats.CurrentDepth = val
@@ -423,16 +564,22 @@ func (p *Parser) parseQueueStats(parameters []*PCFParameter) *QueueStatistics {
 }
 
 // parseChannelStats extracts channel statistics from parameters
-func (p *Parser) parseChannelStats(parameters []*PCFParameter) *ChannelStatistics {
-	stats := &ChannelStatistics{}
+func (p *Parser) parseChannelStats(parameters []PCFParameter) *ChannelStatistics {
+	stats := channelStatsPool.get()
+	bytes64 := false
 
 	for _, param := range parameters {
-		if val, ok := param.Value.(int32); ok {
+		if val, ok := counter(param.Value); ok {
 			switch param.Parameter {
 			case MQIACH_MSGS:
 				stats.Messages = val
 			case MQIACH_BYTES:
-				stats.Bytes = int64(val)
+				if !bytes64 {
+					stats.Bytes = val
+				}
+			case MQIAMO64_BYTES:
+				stats.Bytes = val
+				bytes64 = true
 			case MQIACH_BATCHES:
 				stats.Batches = val
 			}
@@ -450,11 +597,11 @@ func (p *Parser) parseChannelStats(parameters []*PCFParameter) *ChannelStatistic
 }
 
 // parseMQIStats extracts MQI statistics from parameters
-func (p *Parser) parseMQIStats(parameters []*PCFParameter) *MQIStatistics {
-	stats := &MQIStatistics{}
+func (p *Parser) parseMQIStats(parameters []PCFParameter) *MQIStatistics {
+	stats := mqiStatsPool.get()
 
 	for _, param := range parameters {
-		if val, ok := param.Value.(int32); ok {
+		if val, ok := counter(param.Value); ok {
 			switch param.Parameter {
 			case MQIAMO_OPENS:
 				stats.Opens = val
@@ -481,61 +628,132 @@ func (p *Parser) parseMQIStats(parameters []*PCFParameter) *MQIStatistics {
 }
 
 // parseConnectionInfo extracts connection information from parameters
-func (p *Parser) parseConnectionInfo(parameters []*PCFParameter) *ConnectionInfo {
-	info := &ConnectionInfo{}
+func (p *Parser) parseConnectionInfo(parameters []PCFParameter) *ConnectionInfo {
+	info := connectionInfoPool.get()
 
 	for _, param := range parameters {
-		if str, ok := param.Value.(string); ok {
-			switch param.Parameter {
-			case MQCA_CHANNEL_NAME:
-				info.ChannelName = str
-			case MQCA_CONNECTION_NAME:
-				info.ConnectionName = str
-			case MQCA_APPL_NAME:
-				info.ApplicationName = str
-			}
-		}
+		info.apply(param)
 	}
 
 	return info
 }
 
+// apply sets the connection field the parameter holds, if any
+func (info *ConnectionInfo) apply(param PCFParameter) {
+	if str, ok := param.Value.(string); ok {
+		switch param.Parameter {
+		case MQCA_CHANNEL_NAME:
+			info.ChannelName = str
+		case MQCA_CONNECTION_NAME:
+			info.ConnectionName = str
+		case MQCA_APPL_NAME:
+			info.ApplicationName = str
+		case MQCACF_USER_IDENTIFIER:
+			info.UserIdentifier = str
+		}
+	}
+}
+
 // parseOperationCounts extracts operation counts from parameters
-func (p *Parser) parseOperationCounts(parameters []*PCFParameter) *OperationCounts {
-	ops := &OperationCounts{}
+func (p *Parser) parseOperationCounts(parameters []PCFParameter) *OperationCounts {
+	ops := operationCountsPool.get()
 
 	for _, param := range parameters {
-		if val, ok := param.Value.(int32); ok {
-			switch param.Parameter {
-			case MQIAMO_GETS:
-				ops.Gets = val
-			case MQIAMO_PUTS:
-				ops.Puts = val
-			case MQIAMO_OPENS:
-				ops.Opens = val
-			case MQIAMO_CLOSES:
-				ops.Closes = val
-			case MQIAMO_COMMITS:
-				ops.Commits = val
-			case MQIAMO_BACKOUTS:
-				ops.Backouts = val
+		ops.apply(param)
+	}
+
+	return ops
+}
+
+// apply sets the operation count the parameter holds, if any
+func (ops *OperationCounts) apply(param PCFParameter) {
+	val, ok := counter(param.Value)
+	if !ok {
+		return
+	}
+
+	switch param.Parameter {
+	case MQIAMO_GETS:
+		ops.Gets = val
+	case MQIAMO_PUTS:
+		ops.Puts = val
+	case MQIAMO_OPENS:
+		ops.Opens = val
+	case MQIAMO_CLOSES:
+		ops.Closes = val
+	case MQIAMO_COMMITS:
+		ops.Commits = val
+	case MQIAMO_BACKOUTS:
+		ops.Backouts = val
+	case MQIAMO64_PUT_BYTES:
+		ops.PutBytes = val
+	case MQIAMO64_GET_BYTES:
+		ops.GetBytes = val
+	}
+}
+
+// parseQueueOperations extracts the per-queue counts of a queue accounting record. Each
+// queue's group starts with its name, so the counts that follow a name belong to it.
+func (p *Parser) parseQueueOperations(parameters []PCFParameter) []QueueOperations {
+	var queues []QueueOperations
+
+	for _, param := range parameters {
+		if param.Parameter == MQCA_Q_NAME {
+			if name, ok := param.Value.(string); ok {
+				queues = append(queues, QueueOperations{QueueName: name})
 			}
+			continue
 		}
+		if len(queues) == 0 {
+			continue
+		}
+
+		queues[len(queues)-1].apply(param)
 	}
 
-	return ops
+	return queues
+}
+
+// apply sets the queue count the parameter holds, if any
+func (q *QueueOperations) apply(param PCFParameter) {
+	val, ok := counter(param.Value)
+	if !ok {
+		return
+	}
+
+	switch param.Parameter {
+	case MQIAMO_PUTS:
+		q.Puts = val
+	case MQIAMO_GETS:
+		q.Gets = val
+	case MQIAMO64_PUT_BYTES:
+		q.PutBytes = val
+	case MQIAMO64_GET_BYTES:
+		q.GetBytes = val
+	}
 }
 
 // convertParameters converts PCF parameters to a map for JSON serialization
-func (p *Parser) convertParameters(parameters []*PCFParameter) map[string]interface{} {
+func (p *Parser) convertParameters(parameters []PCFParameter) map[string]interface{} {
 	result := make(map[string]interface{})
+	p.fillParameters(result, parameters)
+	return result
+}
 
+// fillParameters adds PCF parameters to a map for JSON serialization
+func (p *Parser) fillParameters(result map[string]interface{}, parameters []PCFParameter) {
 	for _, param := range parameters {
 		key := fmt.Sprintf("param_%d", param.Parameter)
 		result[key] = param.Value
 	}
+}
 
-	return result
+// trimNull cuts b at its first null byte
+func trimNull(b []byte) []byte {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return b[:i]
+	}
+	return b
 }
 
 // cleanString removes null terminators and trims whitespace
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,7 +13,7 @@ import (
 func TestPCFParser_ParseHeader(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel) // Reduce noise in tests
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	tests := []struct {
 		name     string
@@ -62,12 +63,12 @@ func TestPCFParser_ParseHeader(t *testing.T) {
 func TestPCFParser_ParseParameters(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	// Create test parameter data
 	data := createTestPCFParameter(MQCA_Q_NAME, MQCFT_STRING, "TEST.QUEUE")
 
-	params, err := parser.parseParameters(data, 1)
+	params, _, err := parser.parseParameters(data, 1, nil)
 	require.NoError(t, err)
 	require.Len(t, params, 1)
 
@@ -77,12 +78,35 @@ func TestPCFParser_ParseParameters(t *testing.T) {
 	assert.Equal(t, "TEST.QUEUE", param.Value)
 }
 
+func TestPCFParser_ParseParametersReusesSlice(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	data := append(createTestPCFParameter(MQCA_Q_NAME, MQCFT_STRING, "TEST.QUEUE\x00\x00\x00\x00"),
+		createTestPCFParameter(MQCA_Q_MGR_NAME, MQCFT_STRING, "QM1")...)
+
+	scratch := make([]PCFParameter, 1, 8)
+	params, _, err := parser.parseParameters(data, 2, scratch)
+	require.NoError(t, err)
+	require.Len(t, params, 2)
+	assert.Same(t, &scratch[0], &params[0], "the caller's backing array is reused")
+	assert.Equal(t, "TEST.QUEUE", params[0].Value)
+	assert.Equal(t, "QM1", params[1].Value)
+
+	// A corrupt count does not pre-allocate more than the data can hold
+	params, _, err = parser.parseParameters(data, 1<<30, nil)
+	require.NoError(t, err)
+	assert.Len(t, params, 2)
+	assert.LessOrEqual(t, cap(params), len(data)/12)
+}
+
 func TestPCFParser_ParseQueueStats(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
-	parameters := []*PCFParameter{
+	parameters := []PCFParameter{
 		{Parameter: MQCA_Q_NAME, Type: MQCFT_STRING, Value: "TEST.QUEUE"},
 		{Parameter: MQIA_CURRENT_Q_DEPTH, Type: MQCFT_INTEGER, Value: int32(100)},
 		{Parameter: MQIA_HIGH_Q_DEPTH, Type: MQCFT_INTEGER, Value: int32(500)},
@@ -96,12 +120,12 @@ func TestPCFParser_ParseQueueStats(t *testing.T) {
 	require.NotNil(t, stats)
 
 	assert.Equal(t, "TEST.QUEUE", stats.QueueName)
-	assert.Equal(t, int32(100), stats.CurrentDepth)
-	assert.Equal(t, int32(500), stats.HighDepth)
-	assert.Equal(t, int32(2), stats.InputCount)
-	assert.Equal(t, int32(1), stats.OutputCount)
-	assert.Equal(t, int32(1000), stats.EnqueueCount)
-	assert.Equal(t, int32(900), stats.DequeueCount)
+	assert.Equal(t, int64(100), stats.CurrentDepth)
+	assert.Equal(t, int64(500), stats.HighDepth)
+	assert.Equal(t, int64(2), stats.InputCount)
+	assert.Equal(t, int64(1), stats.OutputCount)
+	assert.Equal(t, int64(1000), stats.EnqueueCount)
+	assert.Equal(t, int64(900), stats.DequeueCount)
 	assert.True(t, stats.HasReaders)
 	assert.True(t, stats.HasWriters)
 }
@@ -109,14 +133,14 @@ func TestPCFParser_ParseQueueStats(t *testing.T) {
 func TestPCFParser_ParseChannelStats(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
-	parameters := []*PCFParameter{
+	parameters := []PCFParameter{
 		{Parameter: MQCA_CHANNEL_NAME, Type: MQCFT_STRING, Value: "TEST.SVRCONN"},
 		{Parameter: MQCA_CONNECTION_NAME, Type: MQCFT_STRING, Value: "192.168.1.1"},
-		{Parameter: MQIACH_MSGS, Type: MQCFT_INTEGER, Value: int32(1000)},
-		{Parameter: MQIACH_BYTES, Type: MQCFT_INTEGER, Value: int32(50000)},
-		{Parameter: MQIACH_BATCHES, Type: MQCFT_INTEGER, Value: int32(100)},
+		{Parameter: MQIACH_MSGS, Type: MQCFT_INTEGER, Value: int64(1000)},
+		{Parameter: MQIACH_BYTES, Type: MQCFT_INTEGER, Value: int64(50000)},
+		{Parameter: MQIACH_BATCHES, Type: MQCFT_INTEGER, Value: int64(100)},
 	}
 
 	stats := parser.parseChannelStats(parameters)
@@ -124,42 +148,42 @@ func TestPCFParser_ParseChannelStats(t *testing.T) {
 
 	assert.Equal(t, "TEST.SVRCONN", stats.ChannelName)
 	assert.Equal(t, "192.168.1.1", stats.ConnectionName)
-	assert.Equal(t, int32(1000), stats.Messages)
+	assert.Equal(t, int64(1000), stats.Messages)
 	assert.Equal(t, int64(50000), stats.Bytes)
-	assert.Equal(t, int32(100), stats.Batches)
+	assert.Equal(t, int64(100), stats.Batches)
 }
 
 func TestPCFParser_ParseMQIStats(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
-	parameters := []*PCFParameter{
+	parameters := []PCFParameter{
 		{Parameter: MQCA_APPL_NAME, Type: MQCFT_STRING, Value: "TestApp"},
-		{Parameter: MQIAMO_OPENS, Type: MQCFT_INTEGER, Value: int32(10)},
-		{Parameter: MQIAMO_CLOSES, Type: MQCFT_INTEGER, Value: int32(8)},
-		{Parameter: MQIAMO_PUTS, Type: MQCFT_INTEGER, Value: int32(500)},
-		{Parameter: MQIAMO_GETS, Type: MQCFT_INTEGER, Value: int32(450)},
-		{Parameter: MQIAMO_COMMITS, Type: MQCFT_INTEGER, Value: int32(50)},
-		{Parameter: MQIAMO_BACKOUTS, Type: MQCFT_INTEGER, Value: int32(5)},
+		{Parameter: MQIAMO_OPENS, Type: MQCFT_INTEGER, Value: int64(10)},
+		{Parameter: MQIAMO_CLOSES, Type: MQCFT_INTEGER, Value: int64(8)},
+		{Parameter: MQIAMO_PUTS, Type: MQCFT_INTEGER, Value: int64(500)},
+		{Parameter: MQIAMO_GETS, Type: MQCFT_INTEGER, Value: int64(450)},
+		{Parameter: MQIAMO_COMMITS, Type: MQCFT_INTEGER, Value: int64(50)},
+		{Parameter: MQIAMO_BACKOUTS, Type: MQCFT_INTEGER, Value: int64(5)},
 	}
 
 	stats := parser.parseMQIStats(parameters)
 	require.NotNil(t, stats)
 
 	assert.Equal(t, "TestApp", stats.ApplicationName)
-	assert.Equal(t, int32(10), stats.Opens)
-	assert.Equal(t, int32(8), stats.Closes)
-	assert.Equal(t, int32(500), stats.Puts)
-	assert.Equal(t, int32(450), stats.Gets)
-	assert.Equal(t, int32(50), stats.Commits)
-	assert.Equal(t, int32(5), stats.Backouts)
+	assert.Equal(t, int64(10), stats.Opens)
+	assert.Equal(t, int64(8), stats.Closes)
+	assert.Equal(t, int64(500), stats.Puts)
+	assert.Equal(t, int64(450), stats.Gets)
+	assert.Equal(t, int64(50), stats.Commits)
+	assert.Equal(t, int64(5), stats.Backouts)
 }
 
 func TestPCFParser_ParseMessage_Statistics(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	// Create a complete statistics message
 	data := createCompleteStatsMessage()
@@ -181,7 +205,7 @@ func TestPCFParser_ParseMessage_Statistics(t *testing.T) {
 func TestPCFParser_ParseMessage_Accounting(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	// Create a complete accounting message
 	data := createCompleteAccountingMessage()
@@ -200,7 +224,7 @@ func TestPCFParser_ParseMessage_Accounting(t *testing.T) {
 
 func TestPCFParser_CleanString(t *testing.T) {
 	logger := logrus.New()
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	tests := []struct {
 		input    string
@@ -220,7 +244,7 @@ func TestPCFParser_CleanString(t *testing.T) {
 
 func TestPCFParser_ParseMQTimestamp(t *testing.T) {
 	logger := logrus.New()
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	tests := []struct {
 		input   string
@@ -251,7 +275,7 @@ func TestPCFParser_ParseMQTimestamp(t *testing.T) {
 func TestPCFParser_ErrorHandling(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	tests := []struct {
 		name    string
@@ -308,7 +332,7 @@ func TestPCFParser_ErrorHandling(t *testing.T) {
 func TestPCFParser_LargeMessages(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	// Create a large message with many parameters
 	header := createTestPCFHeader(MQCFT_STATISTICS, MQCMD_STATISTICS_Q, 10)
@@ -335,7 +359,7 @@ func TestPCFParser_LargeMessages(t *testing.T) {
 func TestPCFParser_MessageTypes(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	tests := []struct {
 		name     string
@@ -383,7 +407,7 @@ func TestPCFParser_MessageTypes(t *testing.T) {
 func TestPCFParser_ParameterExtraction(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	// Test various parameter types
 	tests := []struct {
@@ -418,7 +442,7 @@ func TestPCFParser_ParameterExtraction(t *testing.T) {
 				binary.LittleEndian.PutUint32(data[12:16], uint32(tt.value.(int32)))
 			}
 
-			params, err := parser.parseParameters(data, 1)
+			params, _, err := parser.parseParameters(data, 1, nil)
 			require.NoError(t, err)
 			require.Len(t, params, 1)
 
@@ -437,7 +461,7 @@ func TestPCFParser_ParameterExtraction(t *testing.T) {
 func TestPCFParser_ReaderWriterDetection(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	parser := NewParser(logger)
+	parser := NewParser(logging.NewLogrus(logger))
 
 	tests := []struct {
 		name        string
@@ -478,7 +502,7 @@ func TestPCFParser_ReaderWriterDetection(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			parameters := []*PCFParameter{
+			parameters := []PCFParameter{
 				{Parameter: MQCA_Q_NAME, Type: MQCFT_STRING, Value: "TEST.QUEUE"},
 				{Parameter: MQIA_OPEN_INPUT_COUNT, Type: MQCFT_INTEGER, Value: tt.inputCount},
 				{Parameter: MQIA_OPEN_OUTPUT_COUNT, Type: MQCFT_INTEGER, Value: tt.outputCount},
@@ -489,12 +513,118 @@ func TestPCFParser_ReaderWriterDetection(t *testing.T) {
 
 			assert.Equal(t, tt.hasReaders, stats.HasReaders)
 			assert.Equal(t, tt.hasWriters, stats.HasWriters)
-			assert.Equal(t, tt.inputCount, stats.InputCount)
-			assert.Equal(t, tt.outputCount, stats.OutputCount)
+			assert.Equal(t, int64(tt.inputCount), stats.InputCount)
+			assert.Equal(t, int64(tt.outputCount), stats.OutputCount)
+		})
+	}
+}
+
+func TestPCFParser_ParseQueueOperations(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	parameters := []PCFParameter{
+		{Parameter: MQIAMO_PUTS, Type: MQCFT_INTEGER, Value: int32(99)},
+		{Parameter: MQCA_Q_NAME, Type: MQCFT_STRING, Value: "ORDERS.IN"},
+		{Parameter: MQIAMO_PUTS, Type: MQCFT_INTEGER, Value: int32(10)},
+		{Parameter: MQIAMO64_PUT_BYTES, Type: MQCFT_INTEGER64, Value: int64(1000)},
+		{Parameter: MQCA_Q_NAME, Type: MQCFT_STRING, Value: "ORDERS.OUT"},
+		{Parameter: MQIAMO_GETS, Type: MQCFT_INTEGER, Value: int32(4)},
+		{Parameter: MQIAMO64_GET_BYTES, Type: MQCFT_INTEGER64, Value: int64(400)},
+	}
+
+	queues := parser.parseQueueOperations(parameters)
+	assert.Equal(t, []QueueOperations{
+		{QueueName: "ORDERS.IN", Puts: 10, PutBytes: 1000},
+		{QueueName: "ORDERS.OUT", Gets: 4, GetBytes: 400},
+	}, queues, "counts before the first queue name belong to no queue")
+
+	assert.Empty(t, parser.parseQueueOperations(parameters[:1]))
+}
+
+func TestQueueStatistics_ProducerWithoutConsumer(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats QueueStatistics
+		want  bool
+	}{
+		{"puts without readers", QueueStatistics{EnqueueCount: 10}, true},
+		{"writer open without readers", QueueStatistics{HasWriters: true, OutputCount: 1}, true},
+		{"reader open", QueueStatistics{EnqueueCount: 10, HasReaders: true, InputCount: 1}, false},
+		{"messages got", QueueStatistics{EnqueueCount: 10, DequeueCount: 10}, false},
+		{"unused", QueueStatistics{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.stats.ProducerWithoutConsumer())
 		})
 	}
 }
 
+func TestPCFParser_TruncatedMessage(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	message := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		AddString(MQCA_Q_NAME, "ORDERS.IN").
+		AddInteger(MQIA_CURRENT_Q_DEPTH, 42).
+		AddInteger(MQIA_MSG_ENQ_COUNT, 7).
+		Bytes()
+
+	tests := []struct {
+		name      string
+		length    int
+		truncated bool
+		enqueued  int64
+	}{
+		{"complete", len(message), false, 7},
+		{"cut inside a parameter", len(message) - 6, true, 0},
+		{"cut at a parameter boundary", len(message) - 16, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parser.ParseMessage(message[:tt.length], "statistics")
+			require.NoError(t, err)
+
+			stats := data.(*StatisticsData)
+			assert.Equal(t, tt.truncated, stats.Truncated)
+			assert.Equal(t, "ORDERS.IN", stats.QueueStats.QueueName)
+			assert.Equal(t, int64(42), stats.QueueStats.CurrentDepth, "complete parameters are kept")
+			assert.Equal(t, tt.enqueued, stats.QueueStats.EnqueueCount)
+		})
+	}
+}
+
+func TestPCFParser_HeaderReason(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	data, err := parser.ParseMessage(NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		SetReason(MQCC_FAILED, 2035).
+		Bytes(), "statistics")
+	require.NoError(t, err)
+	stats := data.(*StatisticsData)
+	assert.Equal(t, int32(MQCC_FAILED), stats.CompCode)
+	assert.Equal(t, int32(2035), stats.Reason)
+
+	data, err = parser.ParseMessage(NewMessageBuilder(MQCFT_ACCOUNTING, MQCMD_ACCOUNTING_MQI).
+		SetReason(MQCC_WARNING, 2079).
+		Bytes(), "accounting")
+	require.NoError(t, err)
+	acct := data.(*AccountingData)
+	assert.Equal(t, int32(MQCC_WARNING), acct.CompCode)
+	assert.Equal(t, int32(2079), acct.Reason)
+
+	data, err = parser.ParseMessage(NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).Bytes(), "statistics")
+	require.NoError(t, err)
+	assert.Zero(t, data.(*StatisticsData).Reason)
+}
+
 // Helper functions to create test data
 
 func createTestPCFHeader(msgType, command, paramCount int32) []byte {
@@ -0,0 +1,50 @@
+package pcf
+
+import "encoding/binary"
+
+// Control flags of the PCF header
+const (
+	MQCFC_NOT_LAST = 0
+	MQCFC_LAST     = 1
+)
+
+// Part identifies a message as one part of a PCF record that IBM MQ may split over
+// several messages. The parts of a record have consecutive sequence numbers starting at
+// 1, and only the last carries MQCFC_LAST.
+type Part struct {
+	Command  int32
+	Sequence int32
+	Last     bool
+}
+
+// ReadPart reads the part a message is from its PCF header
+func ReadPart(data []byte) (Part, bool) {
+	if len(data) < 36 {
+		return Part{}, false
+	}
+	return Part{
+		Command:  int32(binary.LittleEndian.Uint32(data[12:16])),
+		Sequence: int32(binary.LittleEndian.Uint32(data[16:20])),
+		Last:     int32(binary.LittleEndian.Uint32(data[20:24])) == MQCFC_LAST,
+	}, true
+}
+
+// JoinParts joins the parts of a record into a single message: the header of the first
+// part, marked as the last, with the parameters of every part
+func JoinParts(parts [][]byte) []byte {
+	size := 36
+	for _, part := range parts {
+		size += len(part) - 36
+	}
+
+	joined := make([]byte, 36, size)
+	copy(joined, parts[0][:36])
+	var count uint32
+	for _, part := range parts {
+		count += binary.LittleEndian.Uint32(part[32:36])
+		joined = append(joined, part[36:]...)
+	}
+	binary.LittleEndian.PutUint32(joined[20:24], MQCFC_LAST)
+	binary.LittleEndian.PutUint32(joined[32:36], count)
+	return joined
+}
@@ -0,0 +1,44 @@
+package pcf
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinParts(t *testing.T) {
+	first := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		SetSequence(1, MQCFC_NOT_LAST).
+		AddString(MQCA_Q_MGR_NAME, "QM1").
+		AddString(MQCA_Q_NAME, "APP.ORDERS").
+		Bytes()
+	last := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		SetSequence(2, MQCFC_LAST).
+		AddInteger(MQIA_CURRENT_Q_DEPTH, 42).
+		Bytes()
+
+	part, ok := ReadPart(first)
+	require.True(t, ok)
+	assert.Equal(t, Part{Command: MQCMD_STATISTICS_Q, Sequence: 1, Last: false}, part)
+	part, _ = ReadPart(last)
+	assert.True(t, part.Last)
+	_, ok = ReadPart(first[:20])
+	assert.False(t, ok)
+
+	joined := JoinParts([][]byte{first, last})
+	part, _ = ReadPart(joined)
+	assert.Equal(t, Part{Command: MQCMD_STATISTICS_Q, Sequence: 1, Last: true}, part)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	data, err := NewParser(logging.NewLogrus(logger)).ParseMessage(joined, "statistics")
+	require.NoError(t, err)
+	stats := data.(*StatisticsData)
+	assert.False(t, stats.Truncated)
+	assert.Equal(t, "QM1", stats.QueueManager)
+	assert.Equal(t, "APP.ORDERS", stats.QueueStats.QueueName)
+	assert.Equal(t, int64(42), stats.QueueStats.CurrentDepth)
+}
@@ -0,0 +1,92 @@
+package pcf
+
+import "sync"
+
+// pool is a sync.Pool of *T that zeroes values as they are put back
+type pool[T any] struct {
+	pool sync.Pool
+}
+
+func (p *pool[T]) get() *T {
+	if v, ok := p.pool.Get().(*T); ok {
+		return v
+	}
+	return new(T)
+}
+
+func (p *pool[T]) put(v *T) {
+	var zero T
+	*v = zero
+	p.pool.Put(v)
+}
+
+// Parsed data is pooled across messages and parsers, so catching up on a backlog
+// reuses the records of messages already processed instead of allocating new ones
+var (
+	statisticsPool      pool[StatisticsData]
+	accountingPool      pool[AccountingData]
+	queueStatsPool      pool[QueueStatistics]
+	channelStatsPool    pool[ChannelStatistics]
+	mqiStatsPool        pool[MQIStatistics]
+	connectionInfoPool  pool[ConnectionInfo]
+	operationCountsPool pool[OperationCounts]
+)
+
+// newStatisticsData returns an empty statistics record with an empty parameter map
+func newStatisticsData() *StatisticsData {
+	stats := statisticsPool.get()
+	if stats.Parameters == nil {
+		stats.Parameters = make(map[string]interface{})
+	}
+	return stats
+}
+
+// newAccountingData returns an empty accounting record with an empty parameter map
+func newAccountingData() *AccountingData {
+	acct := accountingPool.get()
+	if acct.Parameters == nil {
+		acct.Parameters = make(map[string]interface{})
+	}
+	return acct
+}
+
+// Release returns a record returned by ParseMessage to the pools for the following
+// messages. Neither the record nor anything it points to may be used afterwards, so only
+// records that were not handed on, for example to sinks, can be released.
+func Release(data interface{}) {
+	switch d := data.(type) {
+	case *StatisticsData:
+		if d.QueueStats != nil {
+			queueStatsPool.put(d.QueueStats)
+		}
+		if d.ChannelStats != nil {
+			channelStatsPool.put(d.ChannelStats)
+		}
+		if d.MQIStats != nil {
+			mqiStatsPool.put(d.MQIStats)
+		}
+		// The parameter map keeps its buckets for the next record
+		clear(d.Parameters)
+		*d = StatisticsData{Parameters: d.Parameters}
+		statisticsPool.pool.Put(d)
+	case *AccountingData:
+		if d.ConnectionInfo != nil {
+			connectionInfoPool.put(d.ConnectionInfo)
+		}
+		if d.Operations != nil {
+			operationCountsPool.put(d.Operations)
+		}
+		clear(d.Parameters)
+		*d = AccountingData{Parameters: d.Parameters}
+		accountingPool.pool.Put(d)
+	}
+}
+
+// ReleaseResults releases the data of every result, as Release
+func ReleaseResults(results []Result) {
+	for _, result := range results {
+		if result.Err == nil {
+			Release(result.Data)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package pcf
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseResetsPooledRecords(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	first := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		AddString(MQCA_Q_NAME, "ORDERS.IN").
+		AddInteger(MQIA_CURRENT_Q_DEPTH, 42).
+		AddInteger(MQIA_OPEN_INPUT_COUNT, 1).
+		Bytes()
+	second := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).
+		AddString(MQCA_Q_NAME, "ORDERS.OUT").
+		Bytes()
+
+	for i := 0; i < 10; i++ {
+		data, err := parser.ParseMessage(first, "statistics")
+		require.NoError(t, err)
+		Release(data)
+
+		// Nothing of the released record leaks into the next one
+		data, err = parser.ParseMessage(second, "statistics")
+		require.NoError(t, err)
+		stats := data.(*StatisticsData)
+		assert.Equal(t, QueueStatistics{QueueName: "ORDERS.OUT"}, *stats.QueueStats)
+		assert.Len(t, stats.Parameters, 1)
+		Release(data)
+	}
+
+	acct, err := parser.ParseMessage(createQueueAccountingMessage(2), "accounting")
+	require.NoError(t, err)
+	ReleaseResults([]Result{{Data: acct}, {Err: assert.AnError}})
+
+	data, err := parser.ParseMessage(NewMessageBuilder(MQCFT_ACCOUNTING, MQCMD_ACCOUNTING_MQI).Bytes(), "accounting")
+	require.NoError(t, err)
+	record := data.(*AccountingData)
+	assert.Empty(t, record.QueueManager)
+	assert.Empty(t, record.Parameters)
+	assert.Empty(t, record.Queues)
+	assert.Equal(t, OperationCounts{}, *record.Operations)
+}
@@ -0,0 +1,135 @@
+package pcf
+
+import (
+	"fmt"
+	"time"
+)
+
+// streamThreshold is the size above which ParseMessage reads accounting messages as a
+// stream instead of decoding all their parameters first
+const streamThreshold = 1 << 20
+
+// AccountingStream reads an accounting message one queue group at a time, decoding each
+// parameter as it is reached instead of building the full parameter list first. The
+// message itself stays in memory, as do the groups the caller keeps.
+type AccountingStream struct {
+	parser *Parser
+	header *PCFHeader
+	data   []byte
+	offset int
+	record *AccountingData
+
+	queue   QueueOperations // group returned by Queue
+	current QueueOperations // group being read
+	open    bool            // whether current has started
+	done    bool
+}
+
+// StreamAccounting starts reading an accounting message. The queue groups are returned
+// by Next and Queue; the fields outside the groups by Record.
+func (p *Parser) StreamAccounting(data []byte) (*AccountingStream, error) {
+	if len(data) < 36 { // Minimum PCF header size
+		return nil, fmt.Errorf("message too short to be a valid PCF message")
+	}
+
+	header, err := p.parseHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PCF header: %w", err)
+	}
+	if header.Command != MQCMD_ACCOUNTING_Q && header.Command != MQCMD_ACCOUNTING_MQI {
+		return nil, fmt.Errorf("not an accounting message: command %d", header.Command)
+	}
+
+	record := newAccountingData()
+	record.Type = "accounting"
+	record.Timestamp = time.Now()
+	record.ConnectionInfo = connectionInfoPool.get()
+	record.Operations = operationCountsPool.get()
+	record.CompCode, record.Reason = header.CompCode, header.Reason
+
+	return &AccountingStream{
+		parser: p,
+		header: header,
+		data:   data[36:],
+		record: record,
+	}, nil
+}
+
+// Header returns the PCF header of the message
+func (s *AccountingStream) Header() *PCFHeader {
+	return s.header
+}
+
+// Next reads up to the end of the next queue group and reports whether there was one
+func (s *AccountingStream) Next() bool {
+	for !s.done {
+		param, next, ok := s.parser.readParameter(s.data, s.offset)
+		if !ok {
+			s.done = true
+			s.record.Truncated = s.offset < len(s.data)
+			break
+		}
+		s.offset = next
+		s.apply(param)
+
+		// Each queue's group starts with its name, which also ends the previous group
+		if name, ok := param.Value.(string); ok && param.Parameter == MQCA_Q_NAME {
+			previous, started := s.current, s.open
+			s.current = QueueOperations{QueueName: name}
+			s.open = true
+			if started {
+				s.queue = previous
+				return true
+			}
+			continue
+		}
+		if s.open {
+			s.current.apply(param)
+		}
+	}
+
+	if s.open {
+		s.queue = s.current
+		s.open = false
+		return true
+	}
+	return false
+}
+
+// Queue returns the queue group read by the last call to Next
+func (s *AccountingStream) Queue() QueueOperations {
+	return s.queue
+}
+
+// Record returns the accounting record without its queue groups. It is complete once
+// Next has returned false.
+func (s *AccountingStream) Record() *AccountingData {
+	return s.record
+}
+
+// apply adds a parameter to the record fields, as parseAccounting would
+func (s *AccountingStream) apply(param PCFParameter) {
+	s.record.Parameters[fmt.Sprintf("param_%d", param.Parameter)] = param.Value
+	s.parser.applyAccounting(s.record, param)
+	s.record.ConnectionInfo.apply(param)
+	s.record.Operations.apply(param)
+}
+
+// parseAccountingStream parses a large accounting message through a stream, which spares
+// the full parameter list but still gathers the per-queue counts of every group into
+// the record
+func (p *Parser) parseAccountingStream(data []byte) (*AccountingData, error) {
+	stream, err := p.StreamAccounting(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var queues []QueueOperations
+	for stream.Next() {
+		queues = append(queues, stream.Queue())
+	}
+
+	acct := stream.Record()
+	acct.Queues = queues
+	return acct, nil
+}
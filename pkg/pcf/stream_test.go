@@ -0,0 +1,96 @@
+package pcf
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createQueueAccountingMessage builds a queue accounting record with the given number of
+// queue groups
+func createQueueAccountingMessage(queues int) []byte {
+	builder := NewMessageBuilder(MQCFT_ACCOUNTING, MQCMD_ACCOUNTING_Q).
+		AddString(MQCA_Q_MGR_NAME, "QM1").
+		AddString(MQCA_APPL_NAME, "orders-app").
+		AddString(MQCACF_COMMAND_TIME, "2026-03-14 10:00:00")
+	for i := 0; i < queues; i++ {
+		builder.AddString(MQCA_Q_NAME, fmt.Sprintf("APP.QUEUE.%05d", i)).
+			AddInteger(MQIAMO_PUTS, int32(i)).
+			AddInteger(MQIAMO_GETS, int32(2*i)).
+			AddInteger64(MQIAMO64_PUT_BYTES, int64(100*i)).
+			AddInteger64(MQIAMO64_GET_BYTES, int64(200*i))
+	}
+	return builder.Bytes()
+}
+
+func TestAccountingStreamYieldsGroups(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	stream, err := parser.StreamAccounting(createQueueAccountingMessage(3))
+	require.NoError(t, err)
+	assert.Equal(t, int32(MQCMD_ACCOUNTING_Q), stream.Header().Command)
+
+	var queues []QueueOperations
+	for stream.Next() {
+		queues = append(queues, stream.Queue())
+	}
+	assert.Equal(t, []QueueOperations{
+		{QueueName: "APP.QUEUE.00000"},
+		{QueueName: "APP.QUEUE.00001", Puts: 1, Gets: 2, PutBytes: 100, GetBytes: 200},
+		{QueueName: "APP.QUEUE.00002", Puts: 2, Gets: 4, PutBytes: 200, GetBytes: 400},
+	}, queues)
+	assert.False(t, stream.Next())
+
+	record := stream.Record()
+	assert.Equal(t, "QM1", record.QueueManager)
+	assert.Equal(t, "orders-app", record.ConnectionInfo.ApplicationName)
+	assert.Equal(t, 2026, record.Timestamp.Year())
+	assert.Empty(t, record.Queues)
+}
+
+func TestAccountingStreamMatchesParseMessage(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := NewParser(logging.NewLogrus(logger))
+
+	// Large enough to take the streaming path of ParseMessage
+	message := createQueueAccountingMessage(15000)
+	require.Greater(t, len(message), streamThreshold)
+
+	data, err := parser.ParseMessage(message, "accounting")
+	require.NoError(t, err)
+	streamed, ok := data.(*AccountingData)
+	require.True(t, ok)
+
+	header, err := parser.parseHeader(message)
+	require.NoError(t, err)
+	parameters, _, err := parser.parseParameters(message[36:], header.ParameterCount, nil)
+	require.NoError(t, err)
+	decoded, err := parser.parseAccounting(header, parameters)
+	require.NoError(t, err)
+
+	assert.Len(t, streamed.Queues, 15000)
+	assert.Equal(t, decoded.Queues, streamed.Queues)
+	assert.Equal(t, decoded.QueueManager, streamed.QueueManager)
+	assert.Equal(t, decoded.Timestamp, streamed.Timestamp)
+	assert.Equal(t, decoded.ConnectionInfo, streamed.ConnectionInfo)
+	assert.Equal(t, decoded.Operations, streamed.Operations)
+	assert.Equal(t, decoded.Parameters, streamed.Parameters)
+}
+
+func TestStreamAccountingRejectsOtherMessages(t *testing.T) {
+	parser := NewParser(logging.NewLogrus(logrus.New()))
+
+	_, err := parser.StreamAccounting([]byte("short"))
+	assert.Error(t, err)
+
+	statistics := NewMessageBuilder(MQCFT_STATISTICS, MQCMD_STATISTICS_Q).Bytes()
+	_, err = parser.StreamAccounting(statistics)
+	assert.Error(t, err)
+}
@@ -0,0 +1,393 @@
+package peaks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Window is the period the peaks are taken over
+const Window = 24 * time.Hour
+
+// bucketSize is the resolution of the window; peaks leave the window an hour at a time
+const bucketSize = time.Hour
+
+// Source is where application operation counts come from
+type Source int
+
+// Sources of application operation counts
+const (
+	SourceStatistics Source = iota
+	SourceAccounting
+)
+
+// QueuePeak is the highest depth of a queue in the window
+type QueuePeak struct {
+	QueueManager string `json:"queue_manager"`
+	Queue        string `json:"queue"`
+	Depth        int64  `json:"depth"`
+}
+
+// ChannelPeak is the highest throughput of a channel instance in the window, per second
+type ChannelPeak struct {
+	QueueManager string  `json:"queue_manager"`
+	Channel      string  `json:"channel"`
+	Connection   string  `json:"connection"`
+	MessageRate  float64 `json:"message_rate"`
+	ByteRate     float64 `json:"byte_rate"`
+}
+
+// ApplicationPeak is the busiest application of a queue manager in the window
+type ApplicationPeak struct {
+	QueueManager string `json:"queue_manager"`
+	Application  string `json:"application"`
+	Operations   int64  `json:"operations"`
+}
+
+// Summary holds the peaks of the window, sorted by queue manager and object
+type Summary struct {
+	Queues   []QueuePeak
+	Channels []ChannelPeak
+	Busiest  []ApplicationPeak
+}
+
+type objectKey struct {
+	queueManager string
+	name         string
+}
+
+type channelKey struct {
+	queueManager string
+	channel      string
+	connection   string
+}
+
+// rates are the peak rates of a channel in a bucket
+type rates struct {
+	messages float64
+	bytes    float64
+}
+
+// bucket holds the peaks of one hour
+type bucket struct {
+	start      time.Time
+	queues     map[objectKey]int64
+	channels   map[channelKey]rates
+	statistics map[objectKey]int64 // application operations from MQI statistics
+	accounting map[objectKey]int64 // application operations from accounting records
+}
+
+func newBucket(start time.Time) *bucket {
+	return &bucket{
+		start:      start,
+		queues:     make(map[objectKey]int64),
+		channels:   make(map[channelKey]rates),
+		statistics: make(map[objectKey]int64),
+		accounting: make(map[objectKey]int64),
+	}
+}
+
+// Tracker keeps the peak queue depths, peak channel throughput and busiest applications
+// of the last 24 hours in hourly buckets
+type Tracker struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	buckets     []*bucket // oldest first
+	lastChannel map[channelKey]time.Time
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{now: time.Now, lastChannel: make(map[channelKey]time.Time)}
+}
+
+// ObserveQueue records the depth of a queue at the given time
+func (t *Tracker) ObserveQueue(qmgr, queue string, at time.Time, depth int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if b := t.bucket(at); b != nil {
+		key := objectKey{queueManager: qmgr, name: queue}
+		b.queues[key] = max(b.queues[key], depth)
+	}
+}
+
+// ObserveChannel records the messages and bytes of a channel statistics interval ending
+// at the given time. The rate is taken over the time since the channel's previous
+// interval, so the first interval of a channel only marks the start of the next one.
+func (t *Tracker) ObserveChannel(qmgr, channel, connection string, at time.Time, messages, bytes int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := channelKey{queueManager: qmgr, channel: channel, connection: connection}
+	last, seen := t.lastChannel[key]
+	if seen && !at.After(last) {
+		return
+	}
+	t.lastChannel[key] = at
+	if !seen {
+		return
+	}
+
+	if b := t.bucket(at); b != nil {
+		elapsed := at.Sub(last).Seconds()
+		peak := b.channels[key]
+		peak.messages = max(peak.messages, float64(messages)/elapsed)
+		peak.bytes = max(peak.bytes, float64(bytes)/elapsed)
+		b.channels[key] = peak
+	}
+}
+
+// ObserveApplication adds MQI operations of an application at the given time. Counts of
+// the two sources are kept apart and an application is judged by whichever reports more,
+// so collecting both statistics and accounting does not double its operations.
+func (t *Tracker) ObserveApplication(source Source, qmgr, application string, at time.Time, operations int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if b := t.bucket(at); b != nil {
+		key := objectKey{queueManager: qmgr, name: application}
+		if source == SourceAccounting {
+			b.accounting[key] += operations
+		} else {
+			b.statistics[key] += operations
+		}
+	}
+}
+
+// bucket returns the bucket of the given time, or nil if the time is outside the window
+func (t *Tracker) bucket(at time.Time) *bucket {
+	cutoff := t.now().Add(-Window)
+	t.prune(cutoff)
+	if !at.After(cutoff) {
+		return nil
+	}
+
+	start := at.Truncate(bucketSize)
+	i := sort.Search(len(t.buckets), func(i int) bool { return !t.buckets[i].start.Before(start) })
+	if i < len(t.buckets) && t.buckets[i].start.Equal(start) {
+		return t.buckets[i]
+	}
+
+	b := newBucket(start)
+	t.buckets = append(t.buckets, nil)
+	copy(t.buckets[i+1:], t.buckets[i:])
+	t.buckets[i] = b
+	return b
+}
+
+// prune drops the buckets that ended before the cutoff
+func (t *Tracker) prune(cutoff time.Time) {
+	drop := 0
+	for drop < len(t.buckets) && !t.buckets[drop].start.Add(bucketSize).After(cutoff) {
+		drop++
+	}
+	t.buckets = t.buckets[drop:]
+}
+
+// Summary returns the peaks of the last 24 hours
+func (t *Tracker) Summary() Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(t.now().Add(-Window))
+
+	queues := make(map[objectKey]int64)
+	channels := make(map[channelKey]rates)
+	statistics := make(map[objectKey]int64)
+	accounting := make(map[objectKey]int64)
+	for _, b := range t.buckets {
+		for key, depth := range b.queues {
+			queues[key] = max(queues[key], depth)
+		}
+		for key, r := range b.channels {
+			peak := channels[key]
+			peak.messages = max(peak.messages, r.messages)
+			peak.bytes = max(peak.bytes, r.bytes)
+			channels[key] = peak
+		}
+		for key, ops := range b.statistics {
+			statistics[key] += ops
+		}
+		for key, ops := range b.accounting {
+			accounting[key] += ops
+		}
+	}
+
+	var summary Summary
+	for key, depth := range queues {
+		summary.Queues = append(summary.Queues, QueuePeak{QueueManager: key.queueManager, Queue: key.name, Depth: depth})
+	}
+	sort.Slice(summary.Queues, func(i, j int) bool {
+		a, b := summary.Queues[i], summary.Queues[j]
+		if a.QueueManager != b.QueueManager {
+			return a.QueueManager < b.QueueManager
+		}
+		return a.Queue < b.Queue
+	})
+
+	for key, r := range channels {
+		summary.Channels = append(summary.Channels, ChannelPeak{
+			QueueManager: key.queueManager,
+			Channel:      key.channel,
+			Connection:   key.connection,
+			MessageRate:  r.messages,
+			ByteRate:     r.bytes,
+		})
+	}
+	sort.Slice(summary.Channels, func(i, j int) bool {
+		a, b := summary.Channels[i], summary.Channels[j]
+		if a.QueueManager != b.QueueManager {
+			return a.QueueManager < b.QueueManager
+		}
+		if a.Channel != b.Channel {
+			return a.Channel < b.Channel
+		}
+		return a.Connection < b.Connection
+	})
+
+	for key, ops := range accounting {
+		statistics[key] = max(statistics[key], ops)
+	}
+	busiest := make(map[string]ApplicationPeak)
+	for key, ops := range statistics {
+		current, ok := busiest[key.queueManager]
+		if !ok || ops > current.Operations || (ops == current.Operations && key.name < current.Application) {
+			busiest[key.queueManager] = ApplicationPeak{QueueManager: key.queueManager, Application: key.name, Operations: ops}
+		}
+	}
+	for _, app := range busiest {
+		summary.Busiest = append(summary.Busiest, app)
+	}
+	sort.Slice(summary.Busiest, func(i, j int) bool {
+		return summary.Busiest[i].QueueManager < summary.Busiest[j].QueueManager
+	})
+
+	return summary
+}
+
+// savedApplication is the operation counts of an application in a saved bucket
+type savedApplication struct {
+	QueueManager string `json:"queue_manager"`
+	Application  string `json:"application"`
+	Statistics   int64  `json:"statistics"`
+	Accounting   int64  `json:"accounting"`
+}
+
+// savedBucket is a bucket as written to the state file
+type savedBucket struct {
+	Start        time.Time          `json:"start"`
+	Queues       []QueuePeak        `json:"queues"`
+	Channels     []ChannelPeak      `json:"channels"`
+	Applications []savedApplication `json:"applications"`
+}
+
+// Save writes the buckets of the window to path as JSON, replacing the file atomically
+func (t *Tracker) Save(path string) error {
+	t.mu.Lock()
+	saved := make([]savedBucket, 0, len(t.buckets))
+	for _, b := range t.buckets {
+		s := savedBucket{Start: b.start}
+		for key, depth := range b.queues {
+			s.Queues = append(s.Queues, QueuePeak{QueueManager: key.queueManager, Queue: key.name, Depth: depth})
+		}
+		for key, r := range b.channels {
+			s.Channels = append(s.Channels, ChannelPeak{
+				QueueManager: key.queueManager,
+				Channel:      key.channel,
+				Connection:   key.connection,
+				MessageRate:  r.messages,
+				ByteRate:     r.bytes,
+			})
+		}
+		apps := make(map[objectKey]*savedApplication)
+		for _, counts := range []map[objectKey]int64{b.statistics, b.accounting} {
+			for key := range counts {
+				apps[key] = &savedApplication{
+					QueueManager: key.queueManager,
+					Application:  key.name,
+					Statistics:   b.statistics[key],
+					Accounting:   b.accounting[key],
+				}
+			}
+		}
+		for _, app := range apps {
+			s.Applications = append(s.Applications, *app)
+		}
+		saved = append(saved, s)
+	}
+	t.mu.Unlock()
+
+	data, err := json.Marshal(map[string]interface{}{"buckets": saved})
+	if err != nil {
+		return fmt.Errorf("failed to encode peaks: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save peaks: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save peaks: %w", err)
+	}
+	return nil
+}
+
+// Load replaces the buckets with those saved in path, dropping any that have left the
+// window. A missing file is not an error.
+func (t *Tracker) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read peaks: %w", err)
+	}
+
+	var saved struct {
+		Buckets []savedBucket `json:"buckets"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("invalid peaks in %s: %w", path, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buckets = nil
+	for _, s := range saved.Buckets {
+		b := newBucket(s.Start.Truncate(bucketSize))
+		for _, q := range s.Queues {
+			b.queues[objectKey{queueManager: q.QueueManager, name: q.Queue}] = q.Depth
+		}
+		for _, c := range s.Channels {
+			b.channels[channelKey{queueManager: c.QueueManager, channel: c.Channel, connection: c.Connection}] = rates{messages: c.MessageRate, bytes: c.ByteRate}
+		}
+		for _, app := range s.Applications {
+			key := objectKey{queueManager: app.QueueManager, name: app.Application}
+			if app.Statistics != 0 {
+				b.statistics[key] = app.Statistics
+			}
+			if app.Accounting != 0 {
+				b.accounting[key] = app.Accounting
+			}
+		}
+		t.buckets = append(t.buckets, b)
+	}
+	sort.Slice(t.buckets, func(i, j int) bool { return t.buckets[i].start.Before(t.buckets[j].start) })
+	t.prune(t.now().Add(-Window))
+	return nil
+}
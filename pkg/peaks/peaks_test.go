@@ -0,0 +1,85 @@
+package peaks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTracker(now *time.Time) *Tracker {
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestTrackerQueueAndChannelPeaks(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	tracker := testTracker(&now)
+
+	tracker.ObserveQueue("QM1", "ORDERS", now.Add(-3*time.Hour), 900)
+	tracker.ObserveQueue("QM1", "ORDERS", now.Add(-time.Hour), 300)
+	tracker.ObserveQueue("QM1", "ORDERS", now.Add(-30*time.Hour), 5000)
+
+	tracker.ObserveChannel("QM1", "TO.QM2", "10.0.0.1", now.Add(-2*time.Hour), 999, 999)
+	tracker.ObserveChannel("QM1", "TO.QM2", "10.0.0.1", now.Add(-2*time.Hour+time.Minute), 600, 60000)
+	tracker.ObserveChannel("QM1", "TO.QM2", "10.0.0.1", now.Add(-2*time.Hour+2*time.Minute), 120, 12000)
+
+	summary := tracker.Summary()
+	require.Len(t, summary.Queues, 1)
+	assert.Equal(t, int64(900), summary.Queues[0].Depth, "the older depth has left the window")
+
+	require.Len(t, summary.Channels, 1)
+	assert.Equal(t, 10.0, summary.Channels[0].MessageRate)
+	assert.Equal(t, 1000.0, summary.Channels[0].ByteRate)
+
+	// Peaks leave the window with their hour
+	now = now.Add(23 * time.Hour)
+	summary = tracker.Summary()
+	require.Len(t, summary.Queues, 1)
+	assert.Equal(t, int64(300), summary.Queues[0].Depth)
+	assert.Empty(t, summary.Channels)
+}
+
+func TestTrackerBusiestApplication(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	tracker := testTracker(&now)
+
+	tracker.ObserveApplication(SourceStatistics, "QM1", "orders", now, 40)
+	tracker.ObserveApplication(SourceAccounting, "QM1", "orders", now, 30)
+	tracker.ObserveApplication(SourceAccounting, "QM1", "orders", now.Add(-time.Hour), 30)
+	tracker.ObserveApplication(SourceAccounting, "QM1", "billing", now, 55)
+	tracker.ObserveApplication(SourceStatistics, "QM2", "batch", now, 7)
+
+	summary := tracker.Summary()
+	require.Len(t, summary.Busiest, 2)
+	assert.Equal(t, ApplicationPeak{QueueManager: "QM1", Application: "orders", Operations: 60}, summary.Busiest[0])
+	assert.Equal(t, "batch", summary.Busiest[1].Application)
+}
+
+func TestTrackerPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peaks.json")
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	tracker := testTracker(&now)
+	require.NoError(t, tracker.Load(path), "a missing file starts empty")
+	tracker.ObserveQueue("QM1", "ORDERS", now.Add(-2*time.Hour), 900)
+	tracker.ObserveApplication(SourceAccounting, "QM1", "orders", now, 30)
+	require.NoError(t, tracker.Save(path))
+
+	restored := testTracker(&now)
+	require.NoError(t, restored.Load(path))
+	restored.ObserveApplication(SourceAccounting, "QM1", "orders", now, 5)
+
+	summary := restored.Summary()
+	require.Len(t, summary.Queues, 1)
+	assert.Equal(t, int64(900), summary.Queues[0].Depth)
+	require.Len(t, summary.Busiest, 1)
+	assert.Equal(t, int64(35), summary.Busiest[0].Operations)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	assert.Error(t, restored.Load(path))
+}
@@ -0,0 +1,71 @@
+package prometheus
+
+import (
+	"hash/fnv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxSeenRecords is the number of accounting records remembered per generation. A record
+// is recognised as a duplicate for at least this many records after it was first seen.
+const maxSeenRecords = 65536
+
+// recordSet remembers the accounting records already counted by a hash of their content.
+// MQ writes every record with its connection ID and interval times, so two records with
+// the same content are the same record delivered again, for example after a cycle was
+// backed out or a message was browsed before being got. It holds two generations and
+// drops the older one when the newer is full, so it stays bounded.
+type recordSet struct {
+	current  map[uint64]struct{}
+	previous map[uint64]struct{}
+}
+
+// add records the record and reports whether it was not seen before
+func (s *recordSet) add(data []byte) bool {
+	h := fnv.New64a()
+	h.Write(data)
+	key := h.Sum64()
+
+	if _, ok := s.current[key]; ok {
+		return false
+	}
+	if _, ok := s.previous[key]; ok {
+		return false
+	}
+
+	if s.current == nil || len(s.current) >= maxSeenRecords {
+		s.previous, s.current = s.current, make(map[uint64]struct{})
+	}
+	s.current[key] = struct{}{}
+	return true
+}
+
+// accountingSeries holds the children of the accounting counters of one application
+type accountingSeries struct {
+	opens    prometheus.Counter
+	closes   prometheus.Counter
+	puts     prometheus.Counter
+	gets     prometheus.Counter
+	commits  prometheus.Counter
+	backouts prometheus.Counter
+}
+
+// accountingSeriesFor returns the cached accounting counters of an application, creating
+// them on first use
+func (c *MetricsCollector) accountingSeriesFor(qmgr, application string) *accountingSeries {
+	if s, ok := c.accountingSeries[[2]string{qmgr, application}]; ok {
+		return s
+	}
+
+	labels := []string{qmgr, application}
+	s := &accountingSeries{
+		opens:    c.accountingOpens.WithLabelValues(labels...),
+		closes:   c.accountingCloses.WithLabelValues(labels...),
+		puts:     c.accountingPuts.WithLabelValues(labels...),
+		gets:     c.accountingGets.WithLabelValues(labels...),
+		commits:  c.accountingCommits.WithLabelValues(labels...),
+		backouts: c.accountingBackouts.WithLabelValues(labels...),
+	}
+	c.accountingSeries[[2]string{qmgr, application}] = s
+	return s
+}
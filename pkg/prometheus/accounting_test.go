@@ -0,0 +1,99 @@
+package prometheus
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// record returns distinct record data for i
+func record(i int) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(i))
+}
+
+func TestRecordSetGenerations(t *testing.T) {
+	var set recordSet
+	for i := 0; i < maxSeenRecords; i++ {
+		require.True(t, set.add(record(i)))
+	}
+	assert.False(t, set.add(record(0)))
+
+	// A full generation is kept as the previous one while the next fills
+	assert.True(t, set.add(record(maxSeenRecords)))
+	assert.Len(t, set.previous, maxSeenRecords)
+	assert.False(t, set.add(record(0)))
+	assert.False(t, set.add(record(maxSeenRecords)))
+
+	// and dropped once that is full as well
+	for i := maxSeenRecords + 1; i < 2*maxSeenRecords+1; i++ {
+		require.True(t, set.add(record(i)))
+	}
+	assert.Len(t, set.previous, maxSeenRecords)
+	assert.True(t, set.add(record(0)))
+	assert.False(t, set.add(record(2*maxSeenRecords)))
+}
+
+// mqiAccounting returns an MQI accounting record of QM1 for an application connected at
+func mqiAccounting(application, at string, puts int32) *mqclient.MQMessage {
+	return &mqclient.MQMessage{Type: "accounting", Data: pcf.NewMessageBuilder(pcf.MQCFT_ACCOUNTING, pcf.MQCMD_ACCOUNTING_MQI).
+		AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+		AddString(pcf.MQCA_APPL_NAME, application).
+		AddString(pcf.MQCACF_COMMAND_TIME, at).
+		AddInteger(pcf.MQIAMO_PUTS, puts).
+		Bytes()}
+}
+
+func TestAccountingCountsEachRecordOnce(t *testing.T) {
+	tests := []struct {
+		name       string
+		batches    [][]*mqclient.MQMessage
+		puts       string
+		duplicates string
+	}{
+		{
+			name: "distinct records",
+			batches: [][]*mqclient.MQMessage{{
+				mqiAccounting("orders-app", "2026-03-14 10:00:00", 5),
+				mqiAccounting("orders-app", "2026-03-14 10:30:00", 7),
+			}},
+			puts: `ibmmq_accounting_puts_total{application_name="orders-app",queue_manager="QM1"} 12`,
+		},
+		{
+			name: "record delivered again in a later cycle",
+			batches: [][]*mqclient.MQMessage{
+				{mqiAccounting("orders-app", "2026-03-14 10:00:00", 5)},
+				{mqiAccounting("orders-app", "2026-03-14 10:00:00", 5), mqiAccounting("billing-app", "2026-03-14 10:00:00", 2)},
+			},
+			puts: `ibmmq_accounting_puts_total{application_name="billing-app",queue_manager="QM1"} 2
+ibmmq_accounting_puts_total{application_name="orders-app",queue_manager="QM1"} 5`,
+			duplicates: `ibmmq_accounting_duplicate_records_total{queue_manager="QM1"} 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCollector(config.DefaultConfig())
+			for _, batch := range tt.batches {
+				c.ProcessMessages(nil, batch)
+			}
+
+			expected := "# HELP ibmmq_accounting_puts_total MQI PUT operations reported in IBM MQ accounting records\n" +
+				"# TYPE ibmmq_accounting_puts_total counter\n" + tt.puts + "\n"
+			require.NoError(t, testutil.CollectAndCompare(c.accountingPuts, strings.NewReader(expected)))
+
+			expected = ""
+			if tt.duplicates != "" {
+				expected = "# HELP ibmmq_accounting_duplicate_records_total Accounting records seen before and not counted again\n" +
+					"# TYPE ibmmq_accounting_duplicate_records_total counter\n" + tt.duplicates + "\n"
+			}
+			require.NoError(t, testutil.CollectAndCompare(c.duplicateRecords, strings.NewReader(expected)))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package prometheus
+
+import (
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/accounting"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// applicationCollector exports the per-application accounting aggregates: the counts of
+// the last interval with records as gauges and the running totals as counters
+type applicationCollector struct {
+	aggregator *accounting.Aggregator
+
+	operationsInterval  *prometheus.Desc
+	operationsTotal     *prometheus.Desc
+	bytesInterval       *prometheus.Desc
+	bytesTotal          *prometheus.Desc
+	connectionsInterval *prometheus.Desc
+	connectionsTotal    *prometheus.Desc
+	lastSeen            *prometheus.Desc
+}
+
+func newApplicationCollector(aggregator *accounting.Aggregator, namespace, subsystem string) *applicationCollector {
+	labels := []string{"queue_manager", "application_name"}
+	name := func(name string) string {
+		return prometheus.BuildFQName(namespace, subsystem, name)
+	}
+
+	return &applicationCollector{
+		aggregator: aggregator,
+		operationsInterval: prometheus.NewDesc(name("application_operations_interval"),
+			"MQI operations of the application in its last accounting interval, by operation",
+			append(labels, "operation"), nil),
+		operationsTotal: prometheus.NewDesc(name("application_operations_total"),
+			"MQI operations of the application across all accounting records, by operation",
+			append(labels, "operation"), nil),
+		bytesInterval: prometheus.NewDesc(name("application_bytes_interval"),
+			"Message bytes put or got by the application in its last accounting interval",
+			append(labels, "direction"), nil),
+		bytesTotal: prometheus.NewDesc(name("application_bytes_total"),
+			"Message bytes put or got by the application across all accounting records",
+			append(labels, "direction"), nil),
+		connectionsInterval: prometheus.NewDesc(name("application_connections_interval"),
+			"Accounting records (connections) of the application in its last accounting interval",
+			labels, nil),
+		connectionsTotal: prometheus.NewDesc(name("application_connections_total"),
+			"Accounting records (connections) of the application across all intervals",
+			labels, nil),
+		lastSeen: prometheus.NewDesc(name("application_last_seen_timestamp"),
+			"Time the last accounting record of the application was collected",
+			labels, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *applicationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.operationsInterval
+	ch <- c.operationsTotal
+	ch <- c.bytesInterval
+	ch <- c.bytesTotal
+	ch <- c.connectionsInterval
+	ch <- c.connectionsTotal
+	ch <- c.lastSeen
+}
+
+// Collect implements prometheus.Collector
+func (c *applicationCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, app := range c.aggregator.Applications() {
+		labels := []string{app.QueueManager, app.Application}
+
+		operations := []struct {
+			name            string
+			interval, total int64
+		}{
+			{"open", app.Interval.Opens, app.Total.Opens},
+			{"close", app.Interval.Closes, app.Total.Closes},
+			{"put", app.Interval.Puts, app.Total.Puts},
+			{"get", app.Interval.Gets, app.Total.Gets},
+			{"browse", app.Interval.Browses, app.Total.Browses},
+			{"commit", app.Interval.Commits, app.Total.Commits},
+			{"backout", app.Interval.Backouts, app.Total.Backouts},
+		}
+		for _, op := range operations {
+			ch <- prometheus.MustNewConstMetric(c.operationsInterval, prometheus.GaugeValue, float64(op.interval), append(labels, op.name)...)
+			ch <- prometheus.MustNewConstMetric(c.operationsTotal, prometheus.CounterValue, float64(op.total), append(labels, op.name)...)
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.bytesInterval, prometheus.GaugeValue, float64(app.Interval.PutBytes), append(labels, "put")...)
+		ch <- prometheus.MustNewConstMetric(c.bytesInterval, prometheus.GaugeValue, float64(app.Interval.GetBytes), append(labels, "get")...)
+		ch <- prometheus.MustNewConstMetric(c.bytesTotal, prometheus.CounterValue, float64(app.Total.PutBytes), append(labels, "put")...)
+		ch <- prometheus.MustNewConstMetric(c.bytesTotal, prometheus.CounterValue, float64(app.Total.GetBytes), append(labels, "get")...)
+
+		ch <- prometheus.MustNewConstMetric(c.connectionsInterval, prometheus.GaugeValue, float64(app.Interval.Connections), labels...)
+		ch <- prometheus.MustNewConstMetric(c.connectionsTotal, prometheus.CounterValue, float64(app.Total.Connections), labels...)
+		ch <- prometheus.MustNewConstMetric(c.lastSeen, prometheus.GaugeValue, float64(app.LastSeen.Unix()), labels...)
+	}
+}
@@ -3,12 +3,29 @@ package prometheus
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/accounting"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/anomaly"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/baseline"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/correlation"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/forecast"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/idle"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/imbalance"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/intervals"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
 	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/peaks"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/quarantine"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/resmon"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/throughput"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
@@ -16,10 +33,11 @@ import (
 // MetricsCollector handles collection and export of IBM MQ metrics to Prometheus
 type MetricsCollector struct {
 	config    *config.Config
-	mqClient  *mqclient.MQClient
-	pcfParser *pcf.Parser
+	mqClient  mqclient.MQClientAPI
+	pcfParser *pcf.ConcurrentParser
 	logger    *logrus.Logger
 	registry  *prometheus.Registry
+	snapshot  atomic.Pointer[snapshot] // served exposition, swapped in at the end of each cycle
 
 	// Prometheus metrics
 	queueDepthGauge       *prometheus.GaugeVec
@@ -30,6 +48,7 @@ type MetricsCollector struct {
 	queueOutputCountGauge *prometheus.GaugeVec
 	queueReadersGauge     *prometheus.GaugeVec
 	queueWritersGauge     *prometheus.GaugeVec
+	queueNoConsumerGauge  *prometheus.GaugeVec
 
 	channelMessagesGauge *prometheus.GaugeVec
 	channelBytesGauge    *prometheus.GaugeVec
@@ -42,25 +61,163 @@ type MetricsCollector struct {
 	mqiCommitsGauge  *prometheus.GaugeVec
 	mqiBackoutsGauge *prometheus.GaugeVec
 
+	// Children of the gauges above by label values, cleared with the gauges
+	queueSeries   map[[2]string]*queueSeries
+	channelSeries map[[3]string]*channelSeries
+	mqiSeries     map[[2]string]*mqiSeries
+
+	// Names of the families of the series above, stamped with their interval end
+	intervalFamilies map[string]bool
+
+	// Operations counted from accounting records, each record once. Unlike the gauges
+	// above they accumulate for the life of the collector and are never reset.
+	accountingOpens    *prometheus.CounterVec
+	accountingCloses   *prometheus.CounterVec
+	accountingPuts     *prometheus.CounterVec
+	accountingGets     *prometheus.CounterVec
+	accountingCommits  *prometheus.CounterVec
+	accountingBackouts *prometheus.CounterVec
+	accountingSeries   map[[2]string]*accountingSeries
+	accountingRecords  recordSet
+	duplicateRecords   *prometheus.CounterVec
+
 	collectionInfoGauge *prometheus.GaugeVec
 	lastCollectionTime  *prometheus.GaugeVec
-
-	mu sync.RWMutex
+	buildInfoGauge      *prometheus.GaugeVec
+	truncatedMessages   *prometheus.CounterVec
+	reasonCodes         *prometheus.CounterVec
+	drainErrors         *prometheus.CounterVec
+	drainSuccess        *prometheus.GaugeVec
+	restarts            *prometheus.CounterVec
+	statisticsEnabled   *prometheus.GaugeVec
+
+	// Instances summed into the queue series of each dynamic queue pattern, nil when disabled
+	dynamicQueueInstances *prometheus.GaugeVec
+
+	// Queue depth anomaly detection, nil when disabled
+	anomalyDetector     *anomaly.Detector
+	queueAnomalyScore   *prometheus.GaugeVec
+	queueAnomalousGauge *prometheus.GaugeVec
+
+	// Queue backlog forecasting, nil when disabled
+	forecaster           *forecast.Forecaster
+	queueMaxDepthGauge   *prometheus.GaugeVec
+	queueDriftGauge      *prometheus.GaugeVec
+	queueTimeToFullGauge *prometheus.GaugeVec
+
+	// Idle queue detection, nil when disabled
+	idleTracker      *idle.Tracker
+	queueIdleGauge   *prometheus.GaugeVec
+	queueIdleSeconds *prometheus.GaugeVec
+
+	// Queue depths inquired in every cycle, nil when disabled
+	liveDepthGauge       *prometheus.GaugeVec
+	liveMaxDepthGauge    *prometheus.GaugeVec
+	liveInputCountGauge  *prometheus.GaugeVec
+	liveOutputCountGauge *prometheus.GaugeVec
+
+	// Values returned by Reset Queue Statistics after each cycle, nil when disabled
+	resetHighDepthGauge *prometheus.GaugeVec
+	resetEnqueueGauge   *prometheus.GaugeVec
+	resetDequeueGauge   *prometheus.GaugeVec
+	resetIntervalGauge  *prometheus.GaugeVec
+
+	// Performance events consumed from the event queue, nil when disabled
+	performanceEvents    *prometheus.CounterVec
+	performanceEventTime *prometheus.GaugeVec
+
+	// Resource monitoring metrics published on the $SYS topics, nil when disabled
+	resourceGauge      *prometheus.GaugeVec
+	resourceQueueGauge *prometheus.GaugeVec
+
+	// Enqueue/dequeue imbalance, nil when disabled
+	imbalanceTracker        *imbalance.Tracker
+	queueImbalanceRatio     *prometheus.GaugeVec
+	queueImbalanceSustained *prometheus.GaugeVec
+
+	// Channel throughput percentiles, nil when disabled
+	throughputTracker            *throughput.Tracker
+	channelMessageRate           *prometheus.GaugeVec
+	channelByteRate              *prometheus.GaugeVec
+	channelMessageRatePercentile *prometheus.GaugeVec
+	channelByteRatePercentile    *prometheus.GaugeVec
+
+	// Weekly seasonality baselines, nil when disabled
+	baseline         *baseline.Learner
+	queueBaseline    *prometheus.GaugeVec
+	queueBaselineDev *prometheus.GaugeVec
+
+	// Per-queue application traffic from correlated records, nil when disabled. The
+	// records are copies of the fields correlated, collected over the batches of a cycle.
+	queueApplicationPuts *prometheus.GaugeVec
+	queueApplicationGets *prometheus.GaugeVec
+	cycleStatistics      []*pcf.StatisticsData
+	cycleAccounting      []*pcf.AccountingData
+
+	// Peak usage of the last 24 hours, nil when disabled
+	peaks *peaks.Tracker
+
+	// Per-application accounting aggregates, nil when disabled
+	aggregates *accounting.Aggregator
+
+	// Statistics and accounting interval gaps, nil when disabled
+	intervalTracker  *intervals.Tracker
+	missedIntervals  *prometheus.CounterVec
+	lastIntervalTime *prometheus.GaugeVec
+
+	// Messages that could not be parsed, nil when disabled
+	quarantine          *quarantine.Quarantine
+	quarantinedMessages *prometheus.CounterVec
+
+	parseErrors atomic.Int64
+
+	// Samples the entries logged for every message, nil when disabled
+	sampler *logging.Sampler
+
+	// The cycle in progress, whose ID is the exemplar of the error counters
+	cycle *logging.Cycle
+
+	// Messages passed to ProcessResults since the last EndCycle
+	cycleStatsMessages      int
+	cycleAccountingMessages int
+
+	// The metric state is locked in shards, so a drain from IBM MQ and the updates from
+	// outside a cycle do not wait on each other: drainMu serialises the drains, mu the
+	// metrics derived from messages and their publishing by EndCycle, and buildMu the
+	// build details.
+	// The forecaster and the gauge vectors lock themselves. Scrapes take no lock at all.
+	drainMu          sync.Mutex
+	mu               sync.Mutex
+	buildMu          sync.Mutex
+	collectorVersion string
 }
 
 // NewMetricsCollector creates a new Prometheus metrics collector
-func NewMetricsCollector(cfg *config.Config, mqClient *mqclient.MQClient, logger *logrus.Logger) *MetricsCollector {
+func NewMetricsCollector(cfg *config.Config, mqClient mqclient.MQClientAPI, logger *logrus.Logger) *MetricsCollector {
 	registry := prometheus.NewRegistry()
 
 	collector := &MetricsCollector{
 		config:    cfg,
 		mqClient:  mqClient,
-		pcfParser: pcf.NewParser(logger),
+		pcfParser: pcf.NewConcurrentParser(logging.NewLogrus(logger), cfg.Collector.ParseWorkers),
 		logger:    logger,
 		registry:  registry,
+
+		queueSeries:   make(map[[2]string]*queueSeries),
+		channelSeries: make(map[[3]string]*channelSeries),
+		mqiSeries:     make(map[[2]string]*mqiSeries),
+
+		accountingSeries: make(map[[2]string]*accountingSeries),
+
+		collectorVersion: "unknown",
+	}
+
+	if sampling := cfg.Logging.Sampling; sampling.Enabled {
+		collector.sampler = logging.NewSampler(sampling.First, sampling.Every)
 	}
 
 	collector.initMetrics()
+	collector.publish()
 	return collector
 }
 
@@ -68,6 +225,7 @@ func NewMetricsCollector(cfg *config.Config, mqClient *mqclient.MQClient, logger
 func (c *MetricsCollector) initMetrics() {
 	namespace := c.config.Prometheus.Namespace
 	subsystem := c.config.Prometheus.Subsystem
+	c.intervalFamilies = intervalFamilyNames(namespace, subsystem)
 
 	// Queue metrics
 	c.queueDepthGauge = prometheus.NewGaugeVec(
@@ -150,6 +308,16 @@ func (c *MetricsCollector) initMetrics() {
 		[]string{"queue_manager", "queue_name"},
 	)
 
+	c.queueNoConsumerGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_producer_without_consumer",
+			Help:      "Whether IBM MQ queue was written to in the interval but had no gets and no readers (1=yes, 0=no)",
+		},
+		[]string{"queue_manager", "queue_name"},
+	)
+
 	// Channel metrics
 	c.channelMessagesGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -242,6 +410,77 @@ func (c *MetricsCollector) initMetrics() {
 		[]string{"queue_manager", "application_name"},
 	)
 
+	// Accounting metrics, counted once per record
+	c.accountingOpens = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "accounting_opens_total",
+			Help:      "MQI OPEN operations reported in IBM MQ accounting records",
+		},
+		[]string{"queue_manager", "application_name"},
+	)
+
+	c.accountingCloses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "accounting_closes_total",
+			Help:      "MQI CLOSE operations reported in IBM MQ accounting records",
+		},
+		[]string{"queue_manager", "application_name"},
+	)
+
+	c.accountingPuts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "accounting_puts_total",
+			Help:      "MQI PUT operations reported in IBM MQ accounting records",
+		},
+		[]string{"queue_manager", "application_name"},
+	)
+
+	c.accountingGets = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "accounting_gets_total",
+			Help:      "MQI GET operations reported in IBM MQ accounting records",
+		},
+		[]string{"queue_manager", "application_name"},
+	)
+
+	c.accountingCommits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "accounting_commits_total",
+			Help:      "MQI COMMIT operations reported in IBM MQ accounting records",
+		},
+		[]string{"queue_manager", "application_name"},
+	)
+
+	c.accountingBackouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "accounting_backouts_total",
+			Help:      "MQI BACKOUT operations reported in IBM MQ accounting records",
+		},
+		[]string{"queue_manager", "application_name"},
+	)
+
+	c.duplicateRecords = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "accounting_duplicate_records_total",
+			Help:      "Accounting records seen before and not counted again",
+		},
+		[]string{"queue_manager"},
+	)
+
 	// Collection info metrics
 	c.collectionInfoGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -263,6 +502,75 @@ func (c *MetricsCollector) initMetrics() {
 		[]string{"queue_manager"},
 	)
 
+	c.buildInfoGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "collector_build_info",
+			Help:      "Build information of the running collector (always 1)",
+		},
+		[]string{"version", "commit", "build_date", "go_version"},
+	)
+
+	c.truncatedMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "truncated_messages_total",
+			Help:      "Messages that ended before all their parameters, processed as far as they go",
+		},
+		[]string{"queue_manager", "type"},
+	)
+
+	c.reasonCodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "pcf_reason_codes_total",
+			Help:      "Messages whose PCF header carries a non-zero completion or reason code",
+		},
+		[]string{"queue_manager", "type", "reason"},
+	)
+
+	c.drainErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_collection_errors_total",
+			Help:      "Collection cycles in which the statistics or accounting queue could not be drained",
+		},
+		[]string{"queue_manager", "type"},
+	)
+
+	c.restarts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_manager_restarts_total",
+			Help:      "Restarts of the IBM MQ queue manager detected on reconnecting",
+		},
+		[]string{"queue_manager"},
+	)
+
+	c.statisticsEnabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "statistics_enabled",
+			Help:      "Whether the IBM MQ queue manager writes statistics or accounting data of a type (1) or not (0), inquired after cycles without messages",
+		},
+		[]string{"queue_manager", "type"},
+	)
+
+	c.drainSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_collection_success",
+			Help:      "Whether the statistics or accounting queue was drained in the last cycle (1) or failed (0)",
+		},
+		[]string{"queue_manager", "type"},
+	)
+
 	// Register all metrics
 	c.registry.MustRegister(
 		c.queueDepthGauge,
@@ -273,6 +581,7 @@ func (c *MetricsCollector) initMetrics() {
 		c.queueOutputCountGauge,
 		c.queueReadersGauge,
 		c.queueWritersGauge,
+		c.queueNoConsumerGauge,
 		c.channelMessagesGauge,
 		c.channelBytesGauge,
 		c.channelBatchesGauge,
@@ -282,35 +591,651 @@ func (c *MetricsCollector) initMetrics() {
 		c.mqiGetsGauge,
 		c.mqiCommitsGauge,
 		c.mqiBackoutsGauge,
+		c.accountingOpens,
+		c.accountingCloses,
+		c.accountingPuts,
+		c.accountingGets,
+		c.accountingCommits,
+		c.accountingBackouts,
+		c.duplicateRecords,
 		c.collectionInfoGauge,
 		c.lastCollectionTime,
+		c.truncatedMessages,
+		c.reasonCodes,
+		c.drainErrors,
+		c.drainSuccess,
+		c.restarts,
+		c.statisticsEnabled,
+		c.buildInfoGauge,
 	)
+
+	if c.config.DynamicQueues.Enabled {
+		c.dynamicQueueInstances = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "dynamic_queue_instances",
+				Help:      "IBM MQ dynamic queues matching the pattern in the queue_name label whose statistics are summed into its series",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+		c.registry.MustRegister(c.dynamicQueueInstances)
+	}
+
+	if c.config.Anomaly.Enabled {
+		c.anomalyDetector = anomaly.NewDetector(&c.config.Anomaly)
+
+		c.queueAnomalyScore = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_depth_anomaly_score",
+				Help:      "Standard deviations the IBM MQ queue depth lies above its learnt baseline",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.queueAnomalousGauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_depth_anomalous",
+				Help:      "Whether IBM MQ queue depth is anomalously high (1=yes, 0=no)",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.registry.MustRegister(c.queueAnomalyScore, c.queueAnomalousGauge)
+	}
+
+	if c.config.Forecast.Enabled {
+		c.forecaster = forecast.NewForecaster(c.config.Forecast.Window)
+
+		c.queueMaxDepthGauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_depth_max",
+				Help:      "Maximum depth (MAXDEPTH) of IBM MQ queue",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.queueDriftGauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_depth_drift_rate",
+				Help:      "Messages enqueued minus dequeued per second on IBM MQ queue, averaged over recent intervals",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.queueTimeToFullGauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_time_to_full_seconds",
+				Help:      "Estimated seconds until IBM MQ queue reaches its maximum depth; only present while the queue is filling",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.registry.MustRegister(c.queueMaxDepthGauge, c.queueDriftGauge, c.queueTimeToFullGauge)
+	}
+
+	if c.config.Idle.Enabled {
+		c.idleTracker = idle.NewTracker(c.config.Idle.Intervals)
+
+		c.queueIdleGauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_idle",
+				Help:      "Whether IBM MQ queue had no gets and no readers for the configured number of intervals (1=yes, 0=no)",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.queueIdleSeconds = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_idle_seconds",
+				Help:      "Seconds since IBM MQ queue last had gets or readers, 0 while active",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.registry.MustRegister(c.queueIdleGauge, c.queueIdleSeconds)
+	}
+
+	if c.config.LiveDepth.Enabled {
+		liveGauge := func(name, help string) *prometheus.GaugeVec {
+			return prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: namespace,
+					Subsystem: subsystem,
+					Name:      name,
+					Help:      help,
+				},
+				[]string{"queue_manager", "queue_name"},
+			)
+		}
+		c.liveDepthGauge = liveGauge("queue_live_depth", "Depth (CURDEPTH) of IBM MQ queue when last inquired")
+		c.liveMaxDepthGauge = liveGauge("queue_live_max_depth", "Maximum depth (MAXDEPTH) of IBM MQ queue when last inquired")
+		c.liveInputCountGauge = liveGauge("queue_live_open_input_count", "Handles open for input (IPPROCS) on IBM MQ queue when last inquired")
+		c.liveOutputCountGauge = liveGauge("queue_live_open_output_count", "Handles open for output (OPPROCS) on IBM MQ queue when last inquired")
+
+		c.registry.MustRegister(c.liveDepthGauge, c.liveMaxDepthGauge, c.liveInputCountGauge, c.liveOutputCountGauge)
+	}
+
+	if c.config.PerformanceEvents.Enabled {
+		c.performanceEvents = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "performance_events_total",
+				Help:      "Performance events raised by IBM MQ queue manager for a queue, such as q_depth_high",
+			},
+			[]string{"queue_manager", "queue_name", "event"},
+		)
+		c.performanceEventTime = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "performance_event_last_timestamp_seconds",
+				Help:      "Unix time IBM MQ queue manager last raised the performance event for a queue",
+			},
+			[]string{"queue_manager", "queue_name", "event"},
+		)
+
+		c.registry.MustRegister(c.performanceEvents, c.performanceEventTime)
+	}
+
+	if c.config.ResourceMonitor.Enabled {
+		c.resourceGauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "resource_metric",
+				Help:      "Resource monitoring metric published by IBM MQ queue manager, in base units",
+			},
+			[]string{"queue_manager", "class", "type", "element"},
+		)
+		c.resourceQueueGauge = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "resource_queue_metric",
+				Help:      "Resource monitoring metric published by IBM MQ queue manager for a queue, in base units",
+			},
+			[]string{"queue_manager", "queue_name", "type", "element"},
+		)
+
+		c.registry.MustRegister(c.resourceGauge, c.resourceQueueGauge)
+	}
+
+	if c.config.Collector.ResetStats {
+		resetGauge := func(name, help string) *prometheus.GaugeVec {
+			return prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: namespace,
+					Subsystem: subsystem,
+					Name:      name,
+					Help:      help,
+				},
+				[]string{"queue_manager", "queue_name"},
+			)
+		}
+		c.resetHighDepthGauge = resetGauge("queue_reset_high_depth", "Highest depth of IBM MQ queue since its statistics were last reset")
+		c.resetEnqueueGauge = resetGauge("queue_reset_enqueue_count", "Messages enqueued to IBM MQ queue since its statistics were last reset")
+		c.resetDequeueGauge = resetGauge("queue_reset_dequeue_count", "Messages dequeued from IBM MQ queue since its statistics were last reset")
+		c.resetIntervalGauge = resetGauge("queue_reset_interval_seconds", "Seconds between the last two resets of the statistics of IBM MQ queue")
+
+		c.registry.MustRegister(c.resetHighDepthGauge, c.resetEnqueueGauge, c.resetDequeueGauge, c.resetIntervalGauge)
+	}
+
+	if i := c.config.Imbalance; i.Enabled {
+		c.imbalanceTracker = imbalance.NewTracker(i.Window, i.Threshold, i.Sustain)
+
+		c.queueImbalanceRatio = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_imbalance_ratio",
+				Help:      "Messages enqueued per message dequeued on IBM MQ queue over the imbalance window",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.queueImbalanceSustained = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_imbalance_sustained",
+				Help:      "Whether IBM MQ queue has been enqueued faster than dequeued for the configured number of intervals (1=yes, 0=no)",
+			},
+			[]string{"queue_manager", "queue_name"},
+		)
+
+		c.registry.MustRegister(c.queueImbalanceRatio, c.queueImbalanceSustained)
+	}
+
+	if c.config.Throughput.Enabled {
+		c.throughputTracker = throughput.NewTracker(c.config.Throughput.Window)
+		channelLabels := []string{"queue_manager", "channel_name", "connection_name"}
+
+		c.channelMessageRate = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "channel_message_rate",
+				Help:      "Messages per second through IBM MQ channel in its last statistics interval",
+			},
+			channelLabels,
+		)
+
+		c.channelByteRate = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "channel_byte_rate",
+				Help:      "Bytes per second through IBM MQ channel in its last statistics interval",
+			},
+			channelLabels,
+		)
+
+		c.channelMessageRatePercentile = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "channel_message_rate_percentile",
+				Help:      "Percentile of the messages per second through IBM MQ channel over the throughput window",
+			},
+			append(channelLabels, "quantile"),
+		)
+
+		c.channelByteRatePercentile = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "channel_byte_rate_percentile",
+				Help:      "Percentile of the bytes per second through IBM MQ channel over the throughput window",
+			},
+			append(channelLabels, "quantile"),
+		)
+
+		c.registry.MustRegister(c.channelMessageRate, c.channelByteRate,
+			c.channelMessageRatePercentile, c.channelByteRatePercentile)
+	}
+
+	if c.config.Baseline.Enabled {
+		learner, err := baseline.NewLearner(&c.config.Baseline)
+		if err != nil {
+			c.logger.WithError(err).Warn("Baseline learning disabled")
+		} else {
+			c.baseline = learner
+			if path := c.config.Baseline.StateFile; path != "" {
+				if err := c.baseline.Load(path); err != nil {
+					c.logger.WithError(err).Warn("Failed to restore baselines, learning from scratch")
+				}
+			}
+
+			c.queueBaseline = prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: namespace,
+					Subsystem: subsystem,
+					Name:      "queue_baseline",
+					Help:      "Usual value of IBM MQ queue measure for the current hour of the week",
+				},
+				[]string{"queue_manager", "queue_name", "measure"},
+			)
+
+			c.queueBaselineDev = prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: namespace,
+					Subsystem: subsystem,
+					Name:      "queue_baseline_deviation",
+					Help:      "Standard deviations IBM MQ queue measure lies above (positive) or below (negative) its baseline for the hour of the week",
+				},
+				[]string{"queue_manager", "queue_name", "measure"},
+			)
+
+			c.registry.MustRegister(c.queueBaseline, c.queueBaselineDev)
+		}
+	}
+
+	if c.config.Correlation.Metrics {
+		c.queueApplicationPuts = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_application_puts",
+				Help:      "Messages put to IBM MQ queue by the application according to the last cycle's queue accounting records",
+			},
+			[]string{"queue_manager", "queue_name", "application_name"},
+		)
+
+		c.queueApplicationGets = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "queue_application_gets",
+				Help:      "Messages got from IBM MQ queue by the application according to the last cycle's queue accounting records",
+			},
+			[]string{"queue_manager", "queue_name", "application_name"},
+		)
+
+		c.registry.MustRegister(c.queueApplicationPuts, c.queueApplicationGets)
+	}
+
+	if c.config.Peaks.Enabled {
+		c.peaks = peaks.NewTracker()
+		if path := c.config.Peaks.StateFile; path != "" {
+			if err := c.peaks.Load(path); err != nil {
+				c.logger.WithError(err).Warn("Failed to restore peak usage, starting from zero")
+			}
+		}
+		c.registry.MustRegister(newPeaksCollector(c.peaks, namespace, subsystem))
+	}
+
+	if c.config.Aggregates.Enabled {
+		c.aggregates = accounting.NewAggregator()
+		if path := c.config.Aggregates.StateFile; path != "" {
+			if err := c.aggregates.Load(path); err != nil {
+				c.logger.WithError(err).Warn("Failed to restore application aggregates, starting from zero")
+			}
+		}
+		c.registry.MustRegister(newApplicationCollector(c.aggregates, namespace, subsystem))
+	}
+
+	if i := c.config.Intervals; i.Enabled {
+		c.intervalTracker = intervals.NewTracker(i.Statistics, i.Accounting)
+
+		c.missedIntervals = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "missed_intervals_total",
+				Help:      "Statistics or accounting intervals of the queue manager that never arrived",
+			},
+			[]string{"queue_manager", "type"},
+		)
+
+		c.lastIntervalTime = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "last_interval_timestamp",
+				Help:      "End of the latest statistics or accounting interval processed",
+			},
+			[]string{"queue_manager", "type"},
+		)
+
+		c.registry.MustRegister(c.missedIntervals, c.lastIntervalTime)
+	}
+
+	if c.config.Quarantine.Enabled {
+		var put quarantine.PutFunc
+		if c.mqClient != nil {
+			put = c.mqClient.PutQueueMessage
+		}
+		store, err := quarantine.New(&c.config.Quarantine, put)
+		if err != nil {
+			c.logger.WithError(err).Warn("Message quarantine disabled")
+		} else {
+			c.quarantine = store
+
+			c.quarantinedMessages = prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Namespace: namespace,
+					Subsystem: subsystem,
+					Name:      "quarantined_messages_total",
+					Help:      "Messages that could not be parsed, kept in the quarantine",
+				},
+				[]string{"queue_manager", "type"},
+			)
+
+			c.registry.MustRegister(c.quarantinedMessages)
+		}
+	}
 }
 
-// CollectMetrics collects metrics from IBM MQ and updates Prometheus gauges
-func (c *MetricsCollector) CollectMetrics(ctx context.Context) error {
+// SetMaxDepth records the MAXDEPTH of a queue for the time-to-full forecast
+func (c *MetricsCollector) SetMaxDepth(qmgr, queueName string, maxDepth int64) {
+	if c.forecaster == nil {
+		return
+	}
+
+	c.forecaster.SetMaxDepth(qmgr+"/"+queueName, maxDepth)
+	c.queueMaxDepthGauge.WithLabelValues(qmgr, queueName).Set(float64(maxDepth))
+}
+
+// SetLiveDepths replaces the inquired depths and open handle counts of the queues of a
+// queue manager, so queues no longer inquired, such as deleted ones, are dropped
+func (c *MetricsCollector) SetLiveDepths(qmgr string, queues []*mqclient.QueueInfo) {
+	if c.liveDepthGauge == nil {
+		return
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	gauges := []*prometheus.GaugeVec{c.liveDepthGauge, c.liveMaxDepthGauge, c.liveInputCountGauge, c.liveOutputCountGauge}
+	for _, gauge := range gauges {
+		gauge.DeletePartialMatch(prometheus.Labels{"queue_manager": qmgr})
+	}
+	for _, queue := range queues {
+		c.liveDepthGauge.WithLabelValues(qmgr, queue.QueueName).Set(float64(queue.CurrentDepth))
+		c.liveMaxDepthGauge.WithLabelValues(qmgr, queue.QueueName).Set(float64(queue.MaxDepth))
+		c.liveInputCountGauge.WithLabelValues(qmgr, queue.QueueName).Set(float64(queue.OpenInputCount))
+		c.liveOutputCountGauge.WithLabelValues(qmgr, queue.QueueName).Set(float64(queue.OpenOutputCount))
+	}
+}
+
+// AddPerformanceEvents counts performance events of a queue manager and records when
+// each was last raised for its queue. Events carrying the name of another queue manager
+// are counted under that one.
+func (c *MetricsCollector) AddPerformanceEvents(qmgr string, events []*pcf.EventData) {
+	if c.performanceEvents == nil || len(events) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, event := range events {
+		eventQmgr := qmgr
+		if event.QueueManager != "" {
+			eventQmgr = event.QueueManager
+		}
+		name := performanceEventName(event.Reason)
+		c.performanceEvents.WithLabelValues(eventQmgr, event.ObjectName, name).Inc()
+		// Events are read in the order they were raised
+		c.performanceEventTime.WithLabelValues(eventQmgr, event.ObjectName, name).Set(float64(event.Timestamp.Unix()))
+	}
+}
+
+// performanceEventName returns the event label of a performance event reason code: its
+// MQRC name in lower case without the prefix, such as q_depth_high
+func performanceEventName(reason int32) string {
+	return strings.ToLower(strings.TrimPrefix(pcf.EventReasonName(reason), "MQRC_"))
+}
+
+// SetResourceMetrics replaces the resource monitoring metrics of a queue manager
+func (c *MetricsCollector) SetResourceMetrics(qmgr string, metrics []resmon.Metric) {
+	if c.resourceGauge == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resourceGauge.DeletePartialMatch(prometheus.Labels{"queue_manager": qmgr})
+	c.resourceQueueGauge.DeletePartialMatch(prometheus.Labels{"queue_manager": qmgr})
+	for _, metric := range metrics {
+		if metric.Queue != "" {
+			c.resourceQueueGauge.WithLabelValues(qmgr, metric.Queue, metric.Type, metric.Element).Set(metric.Value)
+			continue
+		}
+		c.resourceGauge.WithLabelValues(qmgr, metric.Class, metric.Type, metric.Element).Set(metric.Value)
+	}
+}
+
+// SetResetStatistics replaces the values returned by the last Reset Queue Statistics
+// commands for the queues of a queue manager
+func (c *MetricsCollector) SetResetStatistics(qmgr string, stats []*mqclient.QueueResetStatistics) {
+	if c.resetHighDepthGauge == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	gauges := []*prometheus.GaugeVec{c.resetHighDepthGauge, c.resetEnqueueGauge, c.resetDequeueGauge, c.resetIntervalGauge}
+	for _, gauge := range gauges {
+		gauge.DeletePartialMatch(prometheus.Labels{"queue_manager": qmgr})
+	}
+	for _, s := range stats {
+		c.resetHighDepthGauge.WithLabelValues(qmgr, s.QueueName).Set(float64(s.HighDepth))
+		c.resetEnqueueGauge.WithLabelValues(qmgr, s.QueueName).Set(float64(s.EnqueueCount))
+		c.resetDequeueGauge.WithLabelValues(qmgr, s.QueueName).Set(float64(s.DequeueCount))
+		c.resetIntervalGauge.WithLabelValues(qmgr, s.QueueName).Set(float64(s.TimeSinceReset))
+	}
+}
+
+// SetBuildInfo sets the collector build details on the build_info gauge
+func (c *MetricsCollector) SetBuildInfo(version, commit, date string) {
+	c.buildMu.Lock()
+	c.collectorVersion = version
+	c.buildInfoGauge.Reset()
+	c.buildInfoGauge.WithLabelValues(version, commit, date, runtime.Version()).Set(1)
+	c.buildMu.Unlock()
+}
+
+// SetCycle sets the cycle whose ID the error counters carry as their exemplar, so the
+// increase of an error counter leads to the log entries of the cycle that caused it
+func (c *MetricsCollector) SetCycle(cycle *logging.Cycle) {
+	c.cycle = cycle
+}
+
+// countError increments an error counter, with the ID of the cycle in progress as its
+// exemplar when there is one
+func (c *MetricsCollector) countError(counter prometheus.Counter) {
+	if id := c.cycle.ID(); id != "" {
+		if adder, ok := counter.(prometheus.ExemplarAdder); ok {
+			adder.AddWithExemplar(1, prometheus.Labels{logging.CycleIDKey: id})
+			return
+		}
+	}
+	counter.Inc()
+}
+
+// SetDrainResult records whether each of the statistics and accounting queues was
+// drained, from the error of DrainStatisticsAndAccounting
+func (c *MetricsCollector) SetDrainResult(err error) {
+	failed := mqclient.FailedQueues(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	qmgr := c.config.MQ.QueueManager
+	for _, queueType := range []string{"stats", "accounting"} {
+		label := queueTypeLabel(queueType)
+		if slices.Contains(failed, queueType) {
+			c.countError(c.drainErrors.WithLabelValues(qmgr, label))
+			c.drainSuccess.WithLabelValues(qmgr, label).Set(0)
+		} else {
+			c.drainSuccess.WithLabelValues(qmgr, label).Set(1)
+		}
+	}
+}
+
+// SetMonitoringSettings records which statistics and accounting data the queue manager
+// writes, from its STATQ, STATMQI, STATCHL, ACCTQ and ACCTMQI attributes. STATQ(OFF) and
+// similar count as not written, as only objects enabled one by one are reported then.
+func (c *MetricsCollector) SetMonitoringSettings(qmgr string, settings *mqclient.QueueManagerSettings) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, setting := range []struct {
+		label string
+		value int32
+	}{
+		{"queue_statistics", settings.StatisticsQueue},
+		{"mqi_statistics", settings.StatisticsMQI},
+		{"channel_statistics", settings.StatisticsChannel},
+		{"queue_accounting", settings.AccountingQueue},
+		{"mqi_accounting", settings.AccountingMQI},
+	} {
+		enabled := 0.0
+		if mqclient.MonitoringEnabled(setting.value) {
+			enabled = 1
+		}
+		c.statisticsEnabled.WithLabelValues(qmgr, setting.label).Set(enabled)
+	}
+}
+
+// QueueManagerRestarted counts a restart of a queue manager and drops what the trackers
+// learnt from its intervals before it, so rates, trends and baselines start over instead
+// of spanning the outage. The weekly baselines and the peaks are kept.
+func (c *MetricsCollector) QueueManagerRestarted(qmgr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.restarts.WithLabelValues(qmgr).Inc()
+	if c.anomalyDetector != nil {
+		c.anomalyDetector.ResetQueueManager(qmgr)
+	}
+	if c.forecaster != nil {
+		c.forecaster.ResetQueueManager(qmgr)
+	}
+	if c.idleTracker != nil {
+		c.idleTracker.ResetQueueManager(qmgr)
+	}
+	if c.imbalanceTracker != nil {
+		c.imbalanceTracker.ResetQueueManager(qmgr)
+	}
+	if c.throughputTracker != nil {
+		c.throughputTracker.ResetQueueManager(qmgr)
+	}
+	if c.intervalTracker != nil {
+		c.intervalTracker.ResetQueueManager(qmgr)
+	}
+}
+
+// queueTypeLabel returns the type label of the messages of a queue type
+func queueTypeLabel(queueType string) string {
+	if queueType == "stats" {
+		return "statistics"
+	}
+	return queueType
+}
+
+// CollectMetrics collects metrics from IBM MQ and updates Prometheus gauges. A queue
+// that cannot be drained fails the collection only if the other one failed as well.
+func (c *MetricsCollector) CollectMetrics(ctx context.Context) error {
 	c.logger.Info("Starting metrics collection")
 
-	statsMessages, err := c.collectMessages("stats")
+	// The metrics stay readable while the queues are drained
+	c.drainMu.Lock()
+	statsMessages, accountingMessages, err := c.mqClient.GetStatisticsAndAccounting()
+	c.drainMu.Unlock()
+	c.SetDrainResult(err)
 	if err != nil {
-		c.logger.WithError(err).Error("Failed to collect statistics messages")
-		return err
+		c.logger.WithError(err).Error("Failed to collect messages")
 	}
 
-	accountingMessages, err := c.collectMessages("accounting")
-	if err != nil {
-		c.logger.WithError(err).Error("Failed to collect accounting messages")
+	// Update metrics from collected data. Messages got before a failure are already off
+	// their queue, so they are processed as well.
+	c.ProcessMessages(statsMessages, accountingMessages)
+	if err := c.mqClient.Commit(); err != nil {
+		return err
+	}
+	if len(mqclient.FailedQueues(err)) == 2 {
 		return err
 	}
-
-	// Update metrics from collected data
-	c.updateMetricsFromMessages(statsMessages, accountingMessages)
-
-	// Update collection timestamp
-	c.lastCollectionTime.WithLabelValues(c.config.MQ.QueueManager).Set(float64(time.Now().Unix()))
 
 	c.logger.WithFields(logrus.Fields{
 		"stats_messages":      len(statsMessages),
@@ -320,50 +1245,210 @@ func (c *MetricsCollector) CollectMetrics(ctx context.Context) error {
 	return nil
 }
 
-// collectMessages collects messages from specified queue type
-func (c *MetricsCollector) collectMessages(queueType string) ([]*mqclient.MQMessage, error) {
-	messages, err := c.mqClient.GetAllMessages(queueType)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get %s messages: %w", queueType, err)
+// ProcessMessages updates metrics from the messages of a whole cycle obtained outside of
+// CollectMetrics, such as captured or simulated PCF data, and ends the cycle
+func (c *MetricsCollector) ProcessMessages(statsMessages, accountingMessages []*mqclient.MQMessage) {
+	// Parse in parallel, then update the metrics in message order. Nothing else sees the
+	// parsed records, so they go back to the pools at the end.
+	statsResults := c.pcfParser.ParseMessages(mqclient.Payloads(statsMessages), "statistics")
+	defer pcf.ReleaseResults(statsResults)
+	accountingResults := c.pcfParser.ParseMessages(mqclient.Payloads(accountingMessages), "accounting")
+	defer pcf.ReleaseResults(accountingResults)
+
+	c.ProcessResults(statsMessages, statsResults, accountingMessages, accountingResults)
+	c.EndCycle()
+}
+
+// ProcessResults updates metrics from messages already parsed, result i belonging to
+// message i, so that the records can be shared with other consumers. A cycle may pass
+// its messages in several batches; the updates are only served once EndCycle is called.
+// The metrics keep nothing of the records, which the caller releases once every
+// consumer is done.
+func (c *MetricsCollector) ProcessResults(statsMessages []*mqclient.MQMessage, statsResults []pcf.Result,
+	accountingMessages []*mqclient.MQMessage, accountingResults []pcf.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.updateMetrics(statsMessages, statsResults, accountingMessages, accountingResults)
+	c.cycleStatsMessages += len(statsMessages)
+	c.cycleAccountingMessages += len(accountingMessages)
+	c.lastCollectionTime.WithLabelValues(c.config.MQ.QueueManager).Set(float64(time.Now().Unix()))
+}
+
+// EndCycle ends a collection cycle: it finishes the metrics derived from all the records
+// of the cycle, saves the state of the trackers and swaps in a snapshot of the registry,
+// so scrapes see every update of the cycle at once
+func (c *MetricsCollector) EndCycle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logSuppressed()
+
+	if c.queueApplicationPuts != nil {
+		c.updateCorrelationMetrics()
 	}
 
-	c.logger.WithFields(logrus.Fields{
-		"queue_type": queueType,
-		"count":      len(messages),
-	}).Debug("Collected messages")
+	if c.aggregates != nil {
+		c.aggregates.EndCycle()
+		if path := c.config.Aggregates.StateFile; path != "" && c.cycleAccountingMessages > 0 {
+			if err := c.aggregates.Save(path); err != nil {
+				c.logger.WithError(err).Warn("Failed to save application aggregates")
+			}
+		}
+	}
 
-	return messages, nil
+	if c.baseline != nil {
+		if path := c.config.Baseline.StateFile; path != "" && c.cycleStatsMessages > 0 {
+			if err := c.baseline.Save(path); err != nil {
+				c.logger.WithError(err).Warn("Failed to save baselines")
+			}
+		}
+	}
+
+	if c.peaks != nil {
+		if path := c.config.Peaks.StateFile; path != "" && c.cycleStatsMessages+c.cycleAccountingMessages > 0 {
+			if err := c.peaks.Save(path); err != nil {
+				c.logger.WithError(err).Warn("Failed to save peak usage")
+			}
+		}
+	}
+
+	c.cycleStatsMessages, c.cycleAccountingMessages = 0, 0
+	c.publish()
 }
 
-// updateMetricsFromMessages processes messages and updates Prometheus metrics
-func (c *MetricsCollector) updateMetricsFromMessages(statsMessages, accountingMessages []*mqclient.MQMessage) {
-	// Process statistics messages
-	for _, msg := range statsMessages {
-		c.processStatisticsMessage(msg)
+// updateMetrics updates the Prometheus metrics from the parsed messages, in message order
+func (c *MetricsCollector) updateMetrics(statsMessages []*mqclient.MQMessage, statsResults []pcf.Result,
+	accountingMessages []*mqclient.MQMessage, accountingResults []pcf.Result) {
+	for i, result := range statsResults {
+		if result.Err != nil && c.quarantine != nil {
+			c.quarantineMessage(statsMessages[i], result.Err)
+		}
+		c.processStatisticsMessage(result)
 	}
 
-	// Process accounting messages
-	for _, msg := range accountingMessages {
-		c.processAccountingMessage(msg)
+	for i, result := range accountingResults {
+		if result.Err != nil && c.quarantine != nil {
+			c.quarantineMessage(accountingMessages[i], result.Err)
+		}
+		c.processAccountingMessage(result, accountingMessages[i].Data)
 	}
 
 	// Update collection info
+	c.buildMu.Lock()
+	version := c.collectorVersion
+	c.buildMu.Unlock()
 	c.collectionInfoGauge.WithLabelValues(
 		c.config.MQ.QueueManager,
 		c.config.MQ.Channel,
-		"1.0.0", // collector version
+		version,
 	).Set(1)
 }
 
-// processStatisticsMessage processes a single statistics message
-func (c *MetricsCollector) processStatisticsMessage(msg *mqclient.MQMessage) {
-	data, err := c.pcfParser.ParseMessage(msg.Data, "statistics")
-	if err != nil {
-		c.logger.WithError(err).Error("Failed to parse statistics message")
+// updateCorrelationMetrics replaces the per-queue application traffic with that of the
+// records of all batches of the cycle. Cycles without accounting records keep the
+// previous values, since accounting intervals are usually longer than statistics
+// intervals.
+func (c *MetricsCollector) updateCorrelationMetrics() {
+	defer func() {
+		c.cycleStatistics = nil
+		c.cycleAccounting = nil
+	}()
+	if len(c.cycleAccounting) == 0 {
 		return
 	}
 
-	stats, ok := data.(*pcf.StatisticsData)
+	c.queueApplicationPuts.Reset()
+	c.queueApplicationGets.Reset()
+	for _, q := range correlation.Correlate(c.config.MQ.QueueManager, c.cycleStatistics, c.cycleAccounting) {
+		puts := make(map[string]int64)
+		gets := make(map[string]int64)
+		for _, app := range q.Applications {
+			puts[app.Application] += app.Puts
+			gets[app.Application] += app.Gets
+		}
+		// Dynamic queues add up under their pattern
+		queue := c.config.DynamicQueues.Collapse(q.Queue)
+		for app := range puts {
+			c.queueApplicationPuts.WithLabelValues(q.QueueManager, queue, app).Add(float64(puts[app]))
+			c.queueApplicationGets.WithLabelValues(q.QueueManager, queue, app).Add(float64(gets[app]))
+		}
+	}
+}
+
+// quarantineMessage keeps a message that could not be parsed
+func (c *MetricsCollector) quarantineMessage(msg *mqclient.MQMessage, cause error) {
+	fields := logrus.Fields{"type": msg.Type, "size": len(msg.Data)}
+	if err := c.quarantine.Add(msg, cause); err != nil {
+		if entry := c.sampled("quarantine failure: " + logging.ErrorClass(err)); entry != nil {
+			entry.WithError(err).WithFields(fields).Warn("Failed to quarantine message")
+		}
+		return
+	}
+	if entry := c.sampled("quarantine " + msg.Type); entry != nil {
+		entry.WithFields(fields).Info("Quarantined message that could not be parsed")
+	}
+	c.countError(c.quarantinedMessages.WithLabelValues(c.config.MQ.QueueManager, msg.Type))
+}
+
+// failedResponse counts a message whose PCF header carries a completion or reason code
+// and reports whether it failed. A failed message is an error response rather than a
+// record, so its parameters are not used as statistics.
+func (c *MetricsCollector) failedResponse(qmgr, recordType string, compCode, reason int32) bool {
+	if compCode == pcf.MQCC_OK && reason == 0 {
+		return false
+	}
+
+	c.countError(c.reasonCodes.WithLabelValues(qmgr, recordType, mqclient.ReasonString(reason)))
+	if compCode != pcf.MQCC_FAILED {
+		return false
+	}
+	if entry := c.sampled(fmt.Sprintf("failed %s response %d", recordType, reason)); entry != nil {
+		entry.WithFields(logrus.Fields{
+			"queue_manager": qmgr,
+			"type":          recordType,
+			"comp_code":     compCode,
+			"reason":        reason,
+		}).Warn("Skipping failed PCF response")
+	}
+	return true
+}
+
+// sampled returns the entry to log a problem that may repeat on every message with, or
+// nil while the entries of its class are suppressed
+func (c *MetricsCollector) sampled(class string) *logrus.Entry {
+	ok, suppressed := c.sampler.Allow(class)
+	if !ok {
+		return nil
+	}
+	entry := logrus.NewEntry(c.logger)
+	if suppressed > 0 {
+		entry = entry.WithField("suppressed", suppressed)
+	}
+	return entry
+}
+
+// logSuppressed logs how many entries of each class were suppressed in the cycle
+func (c *MetricsCollector) logSuppressed() {
+	for class, suppressed := range c.sampler.Flush() {
+		c.logger.WithFields(logrus.Fields{
+			"class":      class,
+			"suppressed": suppressed,
+		}).Warn("Suppressed repeated log entries")
+	}
+}
+
+// processStatisticsMessage processes a single parsed statistics message
+func (c *MetricsCollector) processStatisticsMessage(result pcf.Result) {
+	if result.Err != nil {
+		if entry := c.sampled("statistics parse: " + logging.ErrorClass(result.Err)); entry != nil {
+			entry.WithError(result.Err).Error("Failed to parse statistics message")
+		}
+		c.parseErrors.Add(1)
+		return
+	}
+
+	stats, ok := result.Data.(*pcf.StatisticsData)
 	if !ok {
 		c.logger.Error("Invalid statistics data type")
 		return
@@ -373,63 +1458,185 @@ func (c *MetricsCollector) processStatisticsMessage(msg *mqclient.MQMessage) {
 	if qmgr == "" {
 		qmgr = c.config.MQ.QueueManager
 	}
+	if c.failedResponse(qmgr, "statistics", stats.CompCode, stats.Reason) {
+		return
+	}
+
+	if c.queueApplicationPuts != nil && stats.QueueStats != nil {
+		// The record goes back to its pool after the batch, but is correlated with the
+		// accounting records of the whole cycle
+		queueStats := *stats.QueueStats
+		c.cycleStatistics = append(c.cycleStatistics, &pcf.StatisticsData{QueueManager: qmgr, QueueStats: &queueStats})
+	}
+	if stats.Truncated {
+		c.countError(c.truncatedMessages.WithLabelValues(qmgr, "statistics"))
+	}
+	if c.intervalTracker != nil {
+		c.observeInterval(qmgr, intervals.Statistics, stats.Timestamp, stats.Parameters)
+	}
 
-	// Update queue statistics
-	if queueStats := stats.QueueStats; queueStats != nil {
-		labels := []string{qmgr, queueStats.QueueName}
+	// Update queue statistics; those of dynamic queues are summed per pattern instead
+	if queueStats := stats.QueueStats; queueStats != nil && !c.addDynamicQueue(qmgr, stats.Timestamp, queueStats) {
+		series := c.queueSeriesFor(qmgr, queueStats.QueueName)
+		series.observed(stats.Timestamp)
+		labels := series.labels
 
-		c.queueDepthGauge.WithLabelValues(labels...).Set(float64(queueStats.CurrentDepth))
-		c.queueHighDepthGauge.WithLabelValues(labels...).Set(float64(queueStats.HighDepth))
-		c.queueEnqueueGauge.WithLabelValues(labels...).Set(float64(queueStats.EnqueueCount))
-		c.queueDequeueGauge.WithLabelValues(labels...).Set(float64(queueStats.DequeueCount))
-		c.queueInputCountGauge.WithLabelValues(labels...).Set(float64(queueStats.InputCount))
-		c.queueOutputCountGauge.WithLabelValues(labels...).Set(float64(queueStats.OutputCount))
+		series.depth.Set(float64(queueStats.CurrentDepth))
+		series.highDepth.Set(float64(queueStats.HighDepth))
+		series.enqueued.Set(float64(queueStats.EnqueueCount))
+		series.dequeued.Set(float64(queueStats.DequeueCount))
+		series.inputCount.Set(float64(queueStats.InputCount))
+		series.outputCount.Set(float64(queueStats.OutputCount))
 
 		// Set reader/writer flags
 		if queueStats.HasReaders {
-			c.queueReadersGauge.WithLabelValues(labels...).Set(1)
+			series.readers.Set(1)
 		} else {
-			c.queueReadersGauge.WithLabelValues(labels...).Set(0)
+			series.readers.Set(0)
 		}
 
 		if queueStats.HasWriters {
-			c.queueWritersGauge.WithLabelValues(labels...).Set(1)
+			series.writers.Set(1)
 		} else {
-			c.queueWritersGauge.WithLabelValues(labels...).Set(0)
+			series.writers.Set(0)
+		}
+
+		if queueStats.ProducerWithoutConsumer() {
+			series.noConsumer.Set(1)
+		} else {
+			series.noConsumer.Set(0)
+		}
+
+		if c.anomalyDetector != nil {
+			score, anomalous := c.anomalyDetector.Observe(series.key, float64(queueStats.CurrentDepth))
+			c.queueAnomalyScore.WithLabelValues(labels...).Set(score)
+			if anomalous {
+				c.queueAnomalousGauge.WithLabelValues(labels...).Set(1)
+			} else {
+				c.queueAnomalousGauge.WithLabelValues(labels...).Set(0)
+			}
+		}
+
+		if c.forecaster != nil {
+			trend := c.forecaster.Observe(series.key, stats.Timestamp,
+				int64(queueStats.CurrentDepth), int64(queueStats.EnqueueCount), int64(queueStats.DequeueCount))
+			if trend.HasDrift {
+				c.queueDriftGauge.WithLabelValues(labels...).Set(trend.DriftPerSecond)
+			}
+			if trend.Filling {
+				c.queueTimeToFullGauge.WithLabelValues(labels...).Set(trend.TimeToFull.Seconds())
+			} else {
+				c.queueTimeToFullGauge.DeleteLabelValues(labels...)
+			}
+		}
+
+		if c.idleTracker != nil {
+			status := c.idleTracker.Observe(series.key, stats.Timestamp,
+				int64(queueStats.DequeueCount), queueStats.HasReaders)
+			if status.Idle {
+				c.queueIdleGauge.WithLabelValues(labels...).Set(1)
+			} else {
+				c.queueIdleGauge.WithLabelValues(labels...).Set(0)
+			}
+			c.queueIdleSeconds.WithLabelValues(labels...).Set(status.IdleFor.Seconds())
+		}
+
+		if c.baseline != nil {
+			measures := []struct {
+				name  string
+				value int64
+			}{
+				{"depth", queueStats.CurrentDepth},
+				{"enqueued", queueStats.EnqueueCount},
+				{"dequeued", queueStats.DequeueCount},
+			}
+			for _, m := range measures {
+				measureLabels := append(labels, m.name)
+				deviation := c.baseline.Observe(series.key+"/"+m.name, stats.Timestamp, float64(m.value))
+				c.queueBaseline.WithLabelValues(measureLabels...).Set(deviation.Expected)
+				if deviation.Ready {
+					c.queueBaselineDev.WithLabelValues(measureLabels...).Set(deviation.Score)
+				} else {
+					c.queueBaselineDev.DeleteLabelValues(measureLabels...)
+				}
+			}
+		}
+
+		if c.peaks != nil {
+			c.peaks.ObserveQueue(qmgr, queueStats.QueueName, stats.Timestamp,
+				int64(max(queueStats.CurrentDepth, queueStats.HighDepth)))
+		}
+
+		if c.imbalanceTracker != nil {
+			result := c.imbalanceTracker.Observe(series.key,
+				int64(queueStats.EnqueueCount), int64(queueStats.DequeueCount))
+			c.queueImbalanceRatio.WithLabelValues(labels...).Set(result.Ratio)
+			if result.Sustained {
+				c.queueImbalanceSustained.WithLabelValues(labels...).Set(1)
+			} else {
+				c.queueImbalanceSustained.WithLabelValues(labels...).Set(0)
+			}
 		}
 	}
 
 	// Update channel statistics
 	if channelStats := stats.ChannelStats; channelStats != nil {
-		labels := []string{qmgr, channelStats.ChannelName, channelStats.ConnectionName}
+		series := c.channelSeriesFor(qmgr, channelStats.ChannelName, channelStats.ConnectionName)
+		series.observed(stats.Timestamp)
+		labels := series.labels
+
+		series.messages.Set(float64(channelStats.Messages))
+		series.bytes.Set(float64(channelStats.Bytes))
+		series.batches.Set(float64(channelStats.Batches))
 
-		c.channelMessagesGauge.WithLabelValues(labels...).Set(float64(channelStats.Messages))
-		c.channelBytesGauge.WithLabelValues(labels...).Set(float64(channelStats.Bytes))
-		c.channelBatchesGauge.WithLabelValues(labels...).Set(float64(channelStats.Batches))
+		if c.peaks != nil {
+			c.peaks.ObserveChannel(qmgr, channelStats.ChannelName, channelStats.ConnectionName, stats.Timestamp,
+				int64(channelStats.Messages), channelStats.Bytes)
+		}
+
+		if c.throughputTracker != nil {
+			if rates, ok := c.throughputTracker.Observe(series.key, stats.Timestamp, int64(channelStats.Messages), channelStats.Bytes); ok {
+				c.channelMessageRate.WithLabelValues(labels...).Set(rates.Messages)
+				c.channelByteRate.WithLabelValues(labels...).Set(rates.Bytes)
+				c.channelMessageRatePercentile.WithLabelValues(append(labels, "0.5")...).Set(rates.MessagesP50)
+				c.channelMessageRatePercentile.WithLabelValues(append(labels, "0.95")...).Set(rates.MessagesP95)
+				c.channelByteRatePercentile.WithLabelValues(append(labels, "0.5")...).Set(rates.BytesP50)
+				c.channelByteRatePercentile.WithLabelValues(append(labels, "0.95")...).Set(rates.BytesP95)
+			}
+		}
 	}
 
 	// Update MQI statistics
 	if mqiStats := stats.MQIStats; mqiStats != nil {
-		labels := []string{qmgr, mqiStats.ApplicationName}
-
-		c.mqiOpensGauge.WithLabelValues(labels...).Set(float64(mqiStats.Opens))
-		c.mqiClosesGauge.WithLabelValues(labels...).Set(float64(mqiStats.Closes))
-		c.mqiPutsGauge.WithLabelValues(labels...).Set(float64(mqiStats.Puts))
-		c.mqiGetsGauge.WithLabelValues(labels...).Set(float64(mqiStats.Gets))
-		c.mqiCommitsGauge.WithLabelValues(labels...).Set(float64(mqiStats.Commits))
-		c.mqiBackoutsGauge.WithLabelValues(labels...).Set(float64(mqiStats.Backouts))
+		series := c.mqiSeriesFor(qmgr, mqiStats.ApplicationName)
+		series.observed(stats.Timestamp)
+
+		series.opens.Set(float64(mqiStats.Opens))
+		series.closes.Set(float64(mqiStats.Closes))
+		series.puts.Set(float64(mqiStats.Puts))
+		series.gets.Set(float64(mqiStats.Gets))
+		series.commits.Set(float64(mqiStats.Commits))
+		series.backouts.Set(float64(mqiStats.Backouts))
+
+		if c.peaks != nil {
+			c.peaks.ObserveApplication(peaks.SourceStatistics, qmgr, mqiStats.ApplicationName, stats.Timestamp,
+				int64(mqiStats.Puts)+int64(mqiStats.Gets))
+		}
 	}
 }
 
-// processAccountingMessage processes a single accounting message
-func (c *MetricsCollector) processAccountingMessage(msg *mqclient.MQMessage) {
-	data, err := c.pcfParser.ParseMessage(msg.Data, "accounting")
-	if err != nil {
-		c.logger.WithError(err).Error("Failed to parse accounting message")
+// processAccountingMessage processes a single parsed accounting message. A record that
+// was processed before, as identified by its data, is skipped.
+func (c *MetricsCollector) processAccountingMessage(result pcf.Result, data []byte) {
+	if result.Err != nil {
+		if entry := c.sampled("accounting parse: " + logging.ErrorClass(result.Err)); entry != nil {
+			entry.WithError(result.Err).Error("Failed to parse accounting message")
+		}
+		c.parseErrors.Add(1)
 		return
 	}
 
-	acct, ok := data.(*pcf.AccountingData)
+	acct, ok := result.Data.(*pcf.AccountingData)
 	if !ok {
 		c.logger.Error("Invalid accounting data type")
 		return
@@ -439,30 +1646,80 @@ func (c *MetricsCollector) processAccountingMessage(msg *mqclient.MQMessage) {
 	if qmgr == "" {
 		qmgr = c.config.MQ.QueueManager
 	}
+	if !c.accountingRecords.add(data) {
+		c.logger.WithField("queue_manager", qmgr).Debug("Skipping accounting record already processed")
+		c.duplicateRecords.WithLabelValues(qmgr).Inc()
+		return
+	}
+	if c.failedResponse(qmgr, "accounting", acct.CompCode, acct.Reason) {
+		return
+	}
 
-	// Update MQI operation counts from accounting data
-	if ops := acct.Operations; ops != nil {
+	if c.queueApplicationPuts != nil {
+		// Kept until the end of the cycle like the statistics records
+		record := &pcf.AccountingData{QueueManager: qmgr, Queues: slices.Clone(acct.Queues)}
+		if info := acct.ConnectionInfo; info != nil {
+			connection := *info
+			record.ConnectionInfo = &connection
+		}
+		c.cycleAccounting = append(c.cycleAccounting, record)
+	}
+
+	if acct.Truncated {
+		c.countError(c.truncatedMessages.WithLabelValues(qmgr, "accounting"))
+	}
+	if c.intervalTracker != nil {
+		c.observeInterval(qmgr, intervals.Accounting, acct.Timestamp, acct.Parameters)
+	}
+
+	if c.aggregates != nil {
+		c.aggregates.Add(qmgr, acct)
+	}
+
+	if c.peaks != nil && acct.Operations != nil {
 		appName := ""
 		if acct.ConnectionInfo != nil {
 			appName = acct.ConnectionInfo.ApplicationName
 		}
+		c.peaks.ObserveApplication(peaks.SourceAccounting, qmgr, appName, acct.Timestamp,
+			int64(acct.Operations.Puts)+int64(acct.Operations.Gets))
+	}
 
-		labels := []string{qmgr, appName}
+	// Count the MQI operations of the record. The MQI gauges hold the values of the
+	// latest statistics interval and are left to the statistics records.
+	if ops := acct.Operations; ops != nil {
+		appName := ""
+		if acct.ConnectionInfo != nil {
+			appName = acct.ConnectionInfo.ApplicationName
+		}
 
-		c.mqiOpensGauge.WithLabelValues(labels...).Add(float64(ops.Opens))
-		c.mqiClosesGauge.WithLabelValues(labels...).Add(float64(ops.Closes))
-		c.mqiPutsGauge.WithLabelValues(labels...).Add(float64(ops.Puts))
-		c.mqiGetsGauge.WithLabelValues(labels...).Add(float64(ops.Gets))
-		c.mqiCommitsGauge.WithLabelValues(labels...).Add(float64(ops.Commits))
-		c.mqiBackoutsGauge.WithLabelValues(labels...).Add(float64(ops.Backouts))
+		series := c.accountingSeriesFor(qmgr, appName)
+		series.opens.Add(float64(ops.Opens))
+		series.closes.Add(float64(ops.Closes))
+		series.puts.Add(float64(ops.Puts))
+		series.gets.Add(float64(ops.Gets))
+		series.commits.Add(float64(ops.Commits))
+		series.backouts.Add(float64(ops.Backouts))
 	}
 }
 
-// GetRegistry returns the Prometheus registry
+// GetRegistry returns the Prometheus registry. It is updated during a cycle; scrapes
+// should be served from Gatherer.
 func (c *MetricsCollector) GetRegistry() *prometheus.Registry {
 	return c.registry
 }
 
+// ParseErrors returns the number of messages that could not be parsed since the collector was created
+func (c *MetricsCollector) ParseErrors() int64 {
+	return c.parseErrors.Load()
+}
+
+// WriteTextfile writes the metrics as of the end of the last cycle to path in the
+// Prometheus text format
+func (c *MetricsCollector) WriteTextfile(path string) error {
+	return prometheus.WriteToTextfile(path, c.Gatherer())
+}
+
 // ResetMetrics clears all metrics
 func (c *MetricsCollector) ResetMetrics() {
 	c.mu.Lock()
@@ -478,6 +1735,7 @@ func (c *MetricsCollector) ResetMetrics() {
 	c.queueOutputCountGauge.Reset()
 	c.queueReadersGauge.Reset()
 	c.queueWritersGauge.Reset()
+	c.queueNoConsumerGauge.Reset()
 	c.channelMessagesGauge.Reset()
 	c.channelBytesGauge.Reset()
 	c.channelBatchesGauge.Reset()
@@ -487,6 +1745,65 @@ func (c *MetricsCollector) ResetMetrics() {
 	c.mqiGetsGauge.Reset()
 	c.mqiCommitsGauge.Reset()
 	c.mqiBackoutsGauge.Reset()
+	clear(c.queueSeries)
+	clear(c.channelSeries)
+	clear(c.mqiSeries)
+	if c.dynamicQueueInstances != nil {
+		c.dynamicQueueInstances.Reset()
+	}
+	if c.anomalyDetector != nil {
+		c.queueAnomalyScore.Reset()
+		c.queueAnomalousGauge.Reset()
+	}
+	if c.forecaster != nil {
+		c.queueMaxDepthGauge.Reset()
+		c.queueDriftGauge.Reset()
+		c.queueTimeToFullGauge.Reset()
+	}
+	if c.idleTracker != nil {
+		c.queueIdleGauge.Reset()
+		c.queueIdleSeconds.Reset()
+	}
+	if c.imbalanceTracker != nil {
+		c.queueImbalanceRatio.Reset()
+		c.queueImbalanceSustained.Reset()
+	}
+	if c.performanceEventTime != nil {
+		c.performanceEventTime.Reset()
+	}
+	if c.resourceGauge != nil {
+		c.resourceGauge.Reset()
+		c.resourceQueueGauge.Reset()
+	}
+	if c.resetHighDepthGauge != nil {
+		c.resetHighDepthGauge.Reset()
+		c.resetEnqueueGauge.Reset()
+		c.resetDequeueGauge.Reset()
+		c.resetIntervalGauge.Reset()
+	}
+	if c.liveDepthGauge != nil {
+		c.liveDepthGauge.Reset()
+		c.liveMaxDepthGauge.Reset()
+		c.liveInputCountGauge.Reset()
+		c.liveOutputCountGauge.Reset()
+	}
+	if c.queueApplicationPuts != nil {
+		c.queueApplicationPuts.Reset()
+		c.queueApplicationGets.Reset()
+	}
+	if c.baseline != nil {
+		c.queueBaseline.Reset()
+		c.queueBaselineDev.Reset()
+	}
+	if c.intervalTracker != nil {
+		c.lastIntervalTime.Reset()
+	}
+	if c.throughputTracker != nil {
+		c.channelMessageRate.Reset()
+		c.channelByteRate.Reset()
+		c.channelMessageRatePercentile.Reset()
+		c.channelByteRatePercentile.Reset()
+	}
 
 	c.logger.Info("Reset all metrics")
 }
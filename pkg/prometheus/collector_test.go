@@ -0,0 +1,125 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queueAccounting returns a queue accounting record of QM1 for an application that put
+// puts messages to a queue
+func queueAccounting(application, queue string, puts int32) *mqclient.MQMessage {
+	return &mqclient.MQMessage{Type: "accounting", Data: pcf.NewMessageBuilder(pcf.MQCFT_ACCOUNTING, pcf.MQCMD_ACCOUNTING_Q).
+		AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+		AddString(pcf.MQCA_APPL_NAME, application).
+		AddString(pcf.MQCACF_COMMAND_TIME, "2026-03-14 10:00:00").
+		AddString(pcf.MQCA_Q_NAME, queue).
+		AddInteger(pcf.MQIAMO_PUTS, puts).
+		Bytes()}
+}
+
+func TestCorrelationSpansTheBatchesOfACycle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Correlation.Metrics = true
+	c := newTestCollector(cfg)
+
+	// A cycle spilled to disk comes in several batches, with the statistics of a queue in
+	// one and its accounting in another
+	processResults(c, []*mqclient.MQMessage{queueStatistics("APP.ORDERS", "2026-03-14 10:00:00", 4, 12)},
+		[]*mqclient.MQMessage{queueAccounting("payments-app", "APP.PAYMENTS", 3)})
+	processResults(c, nil, []*mqclient.MQMessage{
+		queueAccounting("orders-app", "APP.ORDERS", 5),
+		queueAccounting("orders-app", "APP.ORDERS", 7),
+	})
+	c.EndCycle()
+
+	expected := `
+# HELP ibmmq_queue_application_puts Messages put to IBM MQ queue by the application according to the last cycle's queue accounting records
+# TYPE ibmmq_queue_application_puts gauge
+ibmmq_queue_application_puts{application_name="orders-app",queue_manager="QM1",queue_name="APP.ORDERS"} 12
+ibmmq_queue_application_puts{application_name="payments-app",queue_manager="QM1",queue_name="APP.PAYMENTS"} 3
+`
+	require.NoError(t, testutil.GatherAndCompare(c.Gatherer(), strings.NewReader(expected), "ibmmq_queue_application_puts"))
+
+	// A cycle without accounting records keeps the values
+	processResults(c, []*mqclient.MQMessage{queueStatistics("APP.ORDERS", "2026-03-14 10:10:00", 2, 9)}, nil)
+	c.EndCycle()
+	require.NoError(t, testutil.GatherAndCompare(c.Gatherer(), strings.NewReader(expected), "ibmmq_queue_application_puts"))
+}
+
+func TestErrorCounterExemplars(t *testing.T) {
+	statistics := queueStatistics("APP.ORDERS", "2026-03-14 10:00:00", 5, 1).Data
+	truncated := []*mqclient.MQMessage{{Type: "stats", Data: statistics[:len(statistics)-4]}}
+
+	tests := []struct {
+		name    string
+		inCycle bool
+		update  func(c *MetricsCollector)
+		family  string
+	}{
+		{
+			name:    "drain error",
+			inCycle: true,
+			update: func(c *MetricsCollector) {
+				c.SetDrainResult(&mqclient.QueueError{Type: "stats", Err: mqclient.ErrNotConnected})
+			},
+			family: "ibmmq_queue_collection_errors_total",
+		},
+		{
+			name:    "truncated message",
+			inCycle: true,
+			update:  func(c *MetricsCollector) { c.ProcessMessages(truncated, nil) },
+			family:  "ibmmq_truncated_messages_total",
+		},
+		{
+			name:   "truncated message outside a cycle",
+			update: func(c *MetricsCollector) { c.ProcessMessages(truncated, nil) },
+			family: "ibmmq_truncated_messages_total",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCollector(config.DefaultConfig())
+			cycle := &logging.Cycle{}
+			c.SetCycle(cycle)
+			var id string
+			if tt.inCycle {
+				id = cycle.Start()
+			}
+			tt.update(c)
+			cycle.End()
+			c.EndCycle()
+
+			families, err := c.Gatherer().Gather()
+			require.NoError(t, err)
+			var counter *dto.Counter
+			for _, family := range families {
+				if family.GetName() == tt.family {
+					require.Len(t, family.GetMetric(), 1)
+					counter = family.GetMetric()[0].GetCounter()
+				}
+			}
+			require.NotNil(t, counter)
+			assert.Equal(t, 1.0, counter.GetValue())
+
+			if !tt.inCycle {
+				assert.Nil(t, counter.GetExemplar())
+				return
+			}
+			require.NotNil(t, counter.GetExemplar())
+			labels := counter.GetExemplar().GetLabel()
+			require.Len(t, labels, 1)
+			assert.Equal(t, logging.CycleIDKey, labels[0].GetName())
+			assert.Equal(t, id, labels[0].GetValue())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// addDynamicQueue adds the statistics of a queue matching a dynamic queue pattern to the
+// series of the pattern and reports whether it did. The instances of one statistics
+// interval are summed, each once even when its record is delivered again, as after a
+// backout, and a newer interval starts the sums over. The per-queue trackers skip these
+// series, since the instances behind them come and go.
+func (c *MetricsCollector) addDynamicQueue(qmgr string, at time.Time, queueStats *pcf.QueueStatistics) bool {
+	pattern := c.config.DynamicQueues.Collapse(queueStats.QueueName)
+	if pattern == queueStats.QueueName {
+		return false
+	}
+
+	series := c.queueSeriesFor(qmgr, pattern)
+	instances := c.dynamicQueueInstances.WithLabelValues(series.labels...)
+
+	// Records of one interval are written within moments of each other
+	switch {
+	case at.Sub(series.end) >= time.Second:
+		for _, gauge := range []interface{ Set(float64) }{
+			series.depth, series.highDepth, series.enqueued, series.dequeued,
+			series.inputCount, series.outputCount, series.readers, series.writers,
+			series.noConsumer, instances,
+		} {
+			gauge.Set(0)
+		}
+		series.instances = make(map[string]bool)
+	case series.end.Sub(at) >= time.Second:
+		return true // an instance of an interval already replaced
+	}
+	if series.instances[queueStats.QueueName] {
+		return true // already summed
+	}
+	series.instances[queueStats.QueueName] = true
+	series.observed(at)
+
+	instances.Inc()
+	series.depth.Add(float64(queueStats.CurrentDepth))
+	series.highDepth.Add(float64(queueStats.HighDepth))
+	series.enqueued.Add(float64(queueStats.EnqueueCount))
+	series.dequeued.Add(float64(queueStats.DequeueCount))
+	series.inputCount.Add(float64(queueStats.InputCount))
+	series.outputCount.Add(float64(queueStats.OutputCount))
+	if queueStats.HasReaders {
+		series.readers.Set(1)
+	}
+	if queueStats.HasWriters {
+		series.writers.Set(1)
+	}
+	if queueStats.ProducerWithoutConsumer() {
+		series.noConsumer.Set(1)
+	}
+	return true
+}
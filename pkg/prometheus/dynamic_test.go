@@ -0,0 +1,84 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicQueueCollapse(t *testing.T) {
+	tests := []struct {
+		name      string
+		batches   [][]*mqclient.MQMessage
+		depth     string
+		instances string
+	}{
+		{
+			name: "instances of one interval are summed",
+			batches: [][]*mqclient.MQMessage{{
+				queueStatistics("AMQ.5F3A1B2C00010203", "2026-03-14 10:00:00", 2, 1),
+				queueStatistics("AMQ.5F3A1B2C00010204", "2026-03-14 10:00:00", 3, 1),
+				queueStatistics("APP.ORDERS", "2026-03-14 10:00:00", 7, 1),
+			}},
+			depth: `ibmmq_queue_depth_current{queue_manager="QM1",queue_name="AMQ.*"} 5
+ibmmq_queue_depth_current{queue_manager="QM1",queue_name="APP.ORDERS"} 7`,
+			instances: `ibmmq_dynamic_queue_instances{queue_manager="QM1",queue_name="AMQ.*"} 2`,
+		},
+		{
+			name: "a record delivered again is summed once",
+			batches: [][]*mqclient.MQMessage{
+				{queueStatistics("AMQ.5F3A1B2C00010203", "2026-03-14 10:00:00", 2, 1)},
+				{
+					queueStatistics("AMQ.5F3A1B2C00010203", "2026-03-14 10:00:00", 2, 1),
+					queueStatistics("AMQ.5F3A1B2C00010204", "2026-03-14 10:00:00", 3, 1),
+				},
+			},
+			depth:     `ibmmq_queue_depth_current{queue_manager="QM1",queue_name="AMQ.*"} 5`,
+			instances: `ibmmq_dynamic_queue_instances{queue_manager="QM1",queue_name="AMQ.*"} 2`,
+		},
+		{
+			name: "a newer interval starts the sums over",
+			batches: [][]*mqclient.MQMessage{
+				{
+					queueStatistics("AMQ.5F3A1B2C00010203", "2026-03-14 10:00:00", 2, 1),
+					queueStatistics("AMQ.5F3A1B2C00010204", "2026-03-14 10:00:00", 3, 1),
+				},
+				{queueStatistics("AMQ.5F3A1B2C00010203", "2026-03-14 10:10:00", 4, 1)},
+			},
+			depth:     `ibmmq_queue_depth_current{queue_manager="QM1",queue_name="AMQ.*"} 4`,
+			instances: `ibmmq_dynamic_queue_instances{queue_manager="QM1",queue_name="AMQ.*"} 1`,
+		},
+		{
+			name: "an instance of an interval already replaced is skipped",
+			batches: [][]*mqclient.MQMessage{{
+				queueStatistics("AMQ.5F3A1B2C00010203", "2026-03-14 10:10:00", 4, 1),
+				queueStatistics("AMQ.5F3A1B2C00010204", "2026-03-14 10:00:00", 3, 1),
+			}},
+			depth:     `ibmmq_queue_depth_current{queue_manager="QM1",queue_name="AMQ.*"} 4`,
+			instances: `ibmmq_dynamic_queue_instances{queue_manager="QM1",queue_name="AMQ.*"} 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.DynamicQueues = config.DynamicQueuesConfig{Enabled: true, Patterns: []string{"AMQ.*"}}
+			c := newTestCollector(cfg)
+			for _, batch := range tt.batches {
+				c.ProcessMessages(batch, nil)
+			}
+
+			expected := "# HELP ibmmq_queue_depth_current Current depth of IBM MQ queue\n" +
+				"# TYPE ibmmq_queue_depth_current gauge\n" + tt.depth + "\n"
+			require.NoError(t, testutil.CollectAndCompare(c.queueDepthGauge, strings.NewReader(expected)))
+
+			expected = "# HELP ibmmq_dynamic_queue_instances IBM MQ dynamic queues matching the pattern in the queue_name label whose statistics are summed into its series\n" +
+				"# TYPE ibmmq_dynamic_queue_instances gauge\n" + tt.instances + "\n"
+			require.NoError(t, testutil.CollectAndCompare(c.dynamicQueueInstances, strings.NewReader(expected)))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/intervals"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+)
+
+// commandTimeKey is the parameter holding the time a record was written. Records
+// without it are stamped with the time they were parsed, which says nothing about
+// their interval.
+var commandTimeKey = fmt.Sprintf("param_%d", pcf.MQCACF_COMMAND_TIME)
+
+// SetExpectedIntervals records the STATINT and ACCTINT of a queue manager for the
+// interval gap detection. Intervals configured explicitly take precedence.
+func (c *MetricsCollector) SetExpectedIntervals(qmgr string, statistics, accounting time.Duration) {
+	if c.intervalTracker == nil {
+		return
+	}
+
+	c.intervalTracker.SetInterval(qmgr, intervals.Statistics, statistics)
+	c.intervalTracker.SetInterval(qmgr, intervals.Accounting, accounting)
+}
+
+// observeInterval adds a record to the cadence of its queue manager, counting the
+// intervals missed before it
+func (c *MetricsCollector) observeInterval(qmgr, source string, at time.Time, parameters map[string]interface{}) {
+	if _, ok := parameters[commandTimeKey]; !ok {
+		return
+	}
+
+	result := c.intervalTracker.Observe(qmgr, source, at)
+	if !result.New {
+		return
+	}
+	if result.Missed > 0 {
+		c.logger.WithFields(logrus.Fields{
+			"queue_manager": qmgr,
+			"type":          source,
+			"missed":        result.Missed,
+		}).Warn("Intervals missing before the latest one")
+	}
+	c.missedIntervals.WithLabelValues(qmgr, source).Add(float64(result.Missed))
+	c.lastIntervalTime.WithLabelValues(qmgr, source).Set(float64(result.Last.Unix()))
+}
@@ -0,0 +1,69 @@
+package prometheus
+
+import (
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/peaks"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// peaksCollector exports the peak usage of the last 24 hours with a window label
+type peaksCollector struct {
+	tracker *peaks.Tracker
+	window  string
+
+	queueDepth      *prometheus.Desc
+	channelMessages *prometheus.Desc
+	channelBytes    *prometheus.Desc
+	busiest         *prometheus.Desc
+}
+
+func newPeaksCollector(tracker *peaks.Tracker, namespace, subsystem string) *peaksCollector {
+	name := func(name string) string {
+		return prometheus.BuildFQName(namespace, subsystem, name)
+	}
+	channelLabels := []string{"queue_manager", "channel_name", "connection_name", "window"}
+
+	return &peaksCollector{
+		tracker: tracker,
+		window:  "24h",
+		queueDepth: prometheus.NewDesc(name("queue_depth_peak"),
+			"Highest depth of IBM MQ queue in the window",
+			[]string{"queue_manager", "queue_name", "window"}, nil),
+		channelMessages: prometheus.NewDesc(name("channel_message_rate_peak"),
+			"Highest messages per second through IBM MQ channel in a statistics interval of the window",
+			channelLabels, nil),
+		channelBytes: prometheus.NewDesc(name("channel_byte_rate_peak"),
+			"Highest bytes per second through IBM MQ channel in a statistics interval of the window",
+			channelLabels, nil),
+		busiest: prometheus.NewDesc(name("busiest_application_operations"),
+			"MQI puts and gets of the busiest application of the queue manager in the window",
+			[]string{"queue_manager", "application_name", "window"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *peaksCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.queueDepth
+	ch <- c.channelMessages
+	ch <- c.channelBytes
+	ch <- c.busiest
+}
+
+// Collect implements prometheus.Collector
+func (c *peaksCollector) Collect(ch chan<- prometheus.Metric) {
+	summary := c.tracker.Summary()
+
+	for _, q := range summary.Queues {
+		ch <- prometheus.MustNewConstMetric(c.queueDepth, prometheus.GaugeValue, float64(q.Depth),
+			q.QueueManager, q.Queue, c.window)
+	}
+	for _, channel := range summary.Channels {
+		ch <- prometheus.MustNewConstMetric(c.channelMessages, prometheus.GaugeValue, channel.MessageRate,
+			channel.QueueManager, channel.Channel, channel.Connection, c.window)
+		ch <- prometheus.MustNewConstMetric(c.channelBytes, prometheus.GaugeValue, channel.ByteRate,
+			channel.QueueManager, channel.Channel, channel.Connection, c.window)
+	}
+	for _, app := range summary.Busiest {
+		ch <- prometheus.MustNewConstMetric(c.busiest, prometheus.GaugeValue, float64(app.Operations),
+			app.QueueManager, app.Application, c.window)
+	}
+}
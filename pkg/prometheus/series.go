@@ -0,0 +1,124 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// interval is the end of the latest statistics or accounting interval a series was set from
+type interval struct {
+	end time.Time
+}
+
+// observed records a record of the interval ending at the given time
+func (i *interval) observed(end time.Time) {
+	if end.After(i.end) {
+		i.end = end
+	}
+}
+
+// queueSeries holds the children of the per-queue gauges of one queue. Statistics
+// messages of a known queue set them directly instead of hashing the label values
+// into every vector again.
+type queueSeries struct {
+	interval
+	labels []string
+	key    string // queue manager and queue, as keyed by the per-queue trackers
+
+	depth       prometheus.Gauge
+	highDepth   prometheus.Gauge
+	enqueued    prometheus.Gauge
+	dequeued    prometheus.Gauge
+	inputCount  prometheus.Gauge
+	outputCount prometheus.Gauge
+	readers     prometheus.Gauge
+	writers     prometheus.Gauge
+	noConsumer  prometheus.Gauge
+
+	// Dynamic queues summed into the series of a pattern in its latest interval
+	instances map[string]bool
+}
+
+// channelSeries holds the children of the per-channel gauges of one channel instance
+type channelSeries struct {
+	interval
+	labels []string
+	key    string
+
+	messages prometheus.Gauge
+	bytes    prometheus.Gauge
+	batches  prometheus.Gauge
+}
+
+// mqiSeries holds the children of the MQI gauges of one application
+type mqiSeries struct {
+	interval
+	opens    prometheus.Gauge
+	closes   prometheus.Gauge
+	puts     prometheus.Gauge
+	gets     prometheus.Gauge
+	commits  prometheus.Gauge
+	backouts prometheus.Gauge
+}
+
+// queueSeriesFor returns the cached gauges of a queue, creating them on first use
+func (c *MetricsCollector) queueSeriesFor(qmgr, queue string) *queueSeries {
+	if s, ok := c.queueSeries[[2]string{qmgr, queue}]; ok {
+		return s
+	}
+
+	labels := []string{qmgr, queue}
+	s := &queueSeries{
+		labels:      labels,
+		key:         qmgr + "/" + queue,
+		depth:       c.queueDepthGauge.WithLabelValues(labels...),
+		highDepth:   c.queueHighDepthGauge.WithLabelValues(labels...),
+		enqueued:    c.queueEnqueueGauge.WithLabelValues(labels...),
+		dequeued:    c.queueDequeueGauge.WithLabelValues(labels...),
+		inputCount:  c.queueInputCountGauge.WithLabelValues(labels...),
+		outputCount: c.queueOutputCountGauge.WithLabelValues(labels...),
+		readers:     c.queueReadersGauge.WithLabelValues(labels...),
+		writers:     c.queueWritersGauge.WithLabelValues(labels...),
+		noConsumer:  c.queueNoConsumerGauge.WithLabelValues(labels...),
+	}
+	c.queueSeries[[2]string{qmgr, queue}] = s
+	return s
+}
+
+// channelSeriesFor returns the cached gauges of a channel instance, creating them on first use
+func (c *MetricsCollector) channelSeriesFor(qmgr, channel, connection string) *channelSeries {
+	if s, ok := c.channelSeries[[3]string{qmgr, channel, connection}]; ok {
+		return s
+	}
+
+	labels := []string{qmgr, channel, connection}
+	s := &channelSeries{
+		labels:   labels,
+		key:      qmgr + "/" + channel + "/" + connection,
+		messages: c.channelMessagesGauge.WithLabelValues(labels...),
+		bytes:    c.channelBytesGauge.WithLabelValues(labels...),
+		batches:  c.channelBatchesGauge.WithLabelValues(labels...),
+	}
+	c.channelSeries[[3]string{qmgr, channel, connection}] = s
+	return s
+}
+
+// mqiSeriesFor returns the cached MQI gauges of an application, creating them on first use
+func (c *MetricsCollector) mqiSeriesFor(qmgr, application string) *mqiSeries {
+	if s, ok := c.mqiSeries[[2]string{qmgr, application}]; ok {
+		return s
+	}
+
+	labels := []string{qmgr, application}
+	s := &mqiSeries{
+		opens:    c.mqiOpensGauge.WithLabelValues(labels...),
+		closes:   c.mqiClosesGauge.WithLabelValues(labels...),
+		puts:     c.mqiPutsGauge.WithLabelValues(labels...),
+		gets:     c.mqiGetsGauge.WithLabelValues(labels...),
+		commits:  c.mqiCommitsGauge.WithLabelValues(labels...),
+		backouts: c.mqiBackoutsGauge.WithLabelValues(labels...),
+	}
+	c.mqiSeries[[2]string{qmgr, application}] = s
+	return s
+}
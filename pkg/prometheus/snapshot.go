@@ -0,0 +1,110 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// snapshot is the exposition of the registry as of the end of a cycle
+type snapshot struct {
+	families []*dto.MetricFamily
+	err      error
+}
+
+// publish gathers the registry into a snapshot and swaps it in for the scrapes. The
+// registry is the staging area a cycle updates gauge by gauge; scrapes only ever see
+// the snapshot, so they never observe a mixture of old and new series.
+func (c *MetricsCollector) publish() {
+	families, err := c.registry.Gather()
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to gather metrics for exposition")
+	}
+	if c.config.Prometheus.IntervalTimestamps {
+		c.stampIntervals(families)
+	}
+	c.snapshot.Store(&snapshot{families: families, err: err})
+}
+
+// intervalFamilies are the gauges set from statistics and accounting records, through
+// the queue, channel and MQI series. Other gauges labelled the same, such as the live
+// depths inquired every cycle or the values of Reset Queue Statistics, which cover the
+// time since the previous reset rather than an interval, are current as of the scrape.
+var intervalFamilies = []string{
+	"queue_depth_current", "queue_depth_high", "queue_enqueue_count", "queue_dequeue_count",
+	"queue_input_handles", "queue_output_handles", "queue_has_readers", "queue_has_writers",
+	"queue_producer_without_consumer",
+	"channel_messages_total", "channel_bytes_total", "channel_batches_total",
+	"mqi_opens_total", "mqi_closes_total", "mqi_puts_total", "mqi_gets_total",
+	"mqi_commits_total", "mqi_backouts_total",
+}
+
+// intervalFamilyNames returns the fully qualified names of the intervalFamilies
+func intervalFamilyNames(namespace, subsystem string) map[string]bool {
+	names := make(map[string]bool, len(intervalFamilies))
+	for _, name := range intervalFamilies {
+		names[prometheus.BuildFQName(namespace, subsystem, name)] = true
+	}
+	return names
+}
+
+// stampIntervals gives the samples of a queue, channel or application in the
+// intervalFamilies the end of the interval they were last set from as their timestamp.
+// All other samples keep the scrape time.
+func (c *MetricsCollector) stampIntervals(families []*dto.MetricFamily) {
+	for _, family := range families {
+		if !c.intervalFamilies[family.GetName()] {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if end := c.intervalEnd(metric.GetLabel()); !end.IsZero() {
+				ms := end.UnixMilli()
+				metric.TimestampMs = &ms
+			}
+		}
+	}
+}
+
+// intervalEnd returns the interval end of the series a label set identifies, if any
+func (c *MetricsCollector) intervalEnd(labels []*dto.LabelPair) time.Time {
+	values := make(map[string]string, len(labels))
+	for _, label := range labels {
+		values[label.GetName()] = label.GetValue()
+	}
+	qmgr, ok := values["queue_manager"]
+	if !ok {
+		return time.Time{}
+	}
+
+	switch len(values) {
+	case 2:
+		if queue, ok := values["queue_name"]; ok {
+			if s := c.queueSeries[[2]string{qmgr, queue}]; s != nil {
+				return s.end
+			}
+		}
+		if application, ok := values["application_name"]; ok {
+			if s := c.mqiSeries[[2]string{qmgr, application}]; s != nil {
+				return s.end
+			}
+		}
+	case 3:
+		channel, hasChannel := values["channel_name"]
+		connection, hasConnection := values["connection_name"]
+		if hasChannel && hasConnection {
+			if s := c.channelSeries[[3]string{qmgr, channel, connection}]; s != nil {
+				return s.end
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Gatherer returns the metrics as of the end of the last cycle, for serving scrapes
+func (c *MetricsCollector) Gatherer() prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		s := c.snapshot.Load()
+		return s.families, s.err
+	})
+}
@@ -0,0 +1,128 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCollector returns a metrics collector of cfg that logs nothing
+func newTestCollector(cfg *config.Config) *MetricsCollector {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	return NewMetricsCollector(cfg, nil, logger)
+}
+
+// queueStatistics returns a queue statistics message of QM1 for the interval ending at
+func queueStatistics(queue, at string, depth, enqueued int32) *mqclient.MQMessage {
+	return &mqclient.MQMessage{Type: "stats", Data: pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_MGR_NAME, "QM1").
+		AddString(pcf.MQCACF_COMMAND_TIME, at).
+		AddString(pcf.MQCA_Q_NAME, queue).
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, depth).
+		AddInteger(pcf.MQIA_MSG_ENQ_COUNT, enqueued).
+		Bytes()}
+}
+
+// processResults parses messages and passes them to ProcessResults as one batch
+func processResults(c *MetricsCollector, statsMessages, accountingMessages []*mqclient.MQMessage) {
+	statsResults := c.pcfParser.ParseMessages(mqclient.Payloads(statsMessages), "statistics")
+	defer pcf.ReleaseResults(statsResults)
+	accountingResults := c.pcfParser.ParseMessages(mqclient.Payloads(accountingMessages), "accounting")
+	defer pcf.ReleaseResults(accountingResults)
+	c.ProcessResults(statsMessages, statsResults, accountingMessages, accountingResults)
+}
+
+func TestScrapeDuringCycleSeesPreviousSnapshot(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Collector.ResetStats = true
+	c := newTestCollector(cfg)
+
+	depth := func(orders, payments string) string {
+		text := "# HELP ibmmq_queue_depth_current Current depth of IBM MQ queue\n# TYPE ibmmq_queue_depth_current gauge\n"
+		if orders != "" {
+			text += `ibmmq_queue_depth_current{queue_manager="QM1",queue_name="APP.ORDERS"} ` + orders + "\n"
+		}
+		if payments != "" {
+			text += `ibmmq_queue_depth_current{queue_manager="QM1",queue_name="APP.PAYMENTS"} ` + payments + "\n"
+		}
+		return text
+	}
+	scrape := func(expected string) {
+		t.Helper()
+		require.NoError(t, testutil.GatherAndCompare(c.Gatherer(), strings.NewReader(expected), "ibmmq_queue_depth_current"))
+	}
+
+	processResults(c, []*mqclient.MQMessage{queueStatistics("APP.ORDERS", "2026-03-14 10:00:00", 7, 10)}, nil)
+	c.EndCycle()
+	scrape(depth("7", ""))
+
+	// A cycle in two batches, with other updates in between, is served as a whole
+	c.SetDrainResult(nil)
+	processResults(c, []*mqclient.MQMessage{queueStatistics("APP.ORDERS", "2026-03-14 10:10:00", 3, 10)}, nil)
+	scrape(depth("7", ""))
+	c.SetResetStatistics("QM1", []*mqclient.QueueResetStatistics{{QueueName: "APP.ORDERS", EnqueueCount: 30}})
+	processResults(c, []*mqclient.MQMessage{queueStatistics("APP.PAYMENTS", "2026-03-14 10:10:00", 5, 10)}, nil)
+	scrape(depth("7", ""))
+	count, err := testutil.GatherAndCount(c.Gatherer(), "ibmmq_queue_reset_enqueue_count")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	c.EndCycle()
+	scrape(depth("3", "5"))
+	count, err = testutil.GatherAndCount(c.Gatherer(), "ibmmq_queue_reset_enqueue_count")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestIntervalTimestamps(t *testing.T) {
+	end := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC).UnixMilli()
+
+	tests := []struct {
+		name      string
+		enabled   bool
+		family    string
+		timestamp int64
+	}{
+		{name: "interval gauge", enabled: true, family: "ibmmq_queue_depth_current", timestamp: end},
+		{name: "interval gauge without the option", family: "ibmmq_queue_depth_current"},
+		{name: "reset statistics", enabled: true, family: "ibmmq_queue_reset_enqueue_count"},
+		{name: "live depth", enabled: true, family: "ibmmq_queue_live_depth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Collector.ResetStats = true
+			cfg.LiveDepth.Enabled = true
+			cfg.Prometheus.IntervalTimestamps = tt.enabled
+			c := newTestCollector(cfg)
+
+			processResults(c, []*mqclient.MQMessage{queueStatistics("APP.ORDERS", "2026-03-14 10:00:00", 7, 10)}, nil)
+			c.SetResetStatistics("QM1", []*mqclient.QueueResetStatistics{{QueueName: "APP.ORDERS", EnqueueCount: 30}})
+			c.SetLiveDepths("QM1", []*mqclient.QueueInfo{{QueueName: "APP.ORDERS", CurrentDepth: 7}})
+			c.EndCycle()
+
+			families, err := c.Gatherer().Gather()
+			require.NoError(t, err)
+			var family *dto.MetricFamily
+			for _, f := range families {
+				if f.GetName() == tt.family {
+					family = f
+				}
+			}
+			require.NotNil(t, family)
+			require.Len(t, family.GetMetric(), 1)
+			assert.Equal(t, tt.timestamp, family.GetMetric()[0].GetTimestampMs())
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package quarantine
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+)
+
+// payloadSuffix and entrySuffix name the two files of a message in the directory
+const (
+	payloadSuffix = ".pcf"
+	entrySuffix   = ".json"
+)
+
+// ErrFull is returned by Add when the directory holds the maximum number of messages and
+// there is no queue to put the message to
+var ErrFull = errors.New("quarantine directory is full")
+
+// PutFunc puts a message to a queue, as MQClient.PutQueueMessage
+type PutFunc func(queueName string, msg *mqclient.MQMessage) error
+
+// Quarantine keeps the messages that could not be parsed instead of dropping them, so a
+// parser bug can be reproduced from the exact bytes IBM MQ wrote. Each message is written
+// to the directory as its payload and a JSON file of its message descriptor and the
+// parse error, and put to the queue, whichever are configured.
+type Quarantine struct {
+	dir         string
+	queue       string
+	maxMessages int
+	put         PutFunc
+
+	mu     sync.Mutex
+	stored int // messages in the directory
+	seq    int
+}
+
+// Entry is the description of a quarantined message written next to its payload
+type Entry struct {
+	Type        string      `json:"type"`
+	Error       string      `json:"error"`
+	Quarantined time.Time   `json:"quarantined"`
+	Length      int         `json:"length"`
+	MD          *Descriptor `json:"mqmd,omitempty"` // missing for messages read back from the spool
+}
+
+// Descriptor holds the message descriptor fields of a quarantined message
+type Descriptor struct {
+	Format         string    `json:"format"`
+	Encoding       int32     `json:"encoding"`
+	CodedCharSetID int32     `json:"ccsid"`
+	MsgType        int32     `json:"msg_type"`
+	Persistence    int32     `json:"persistence"`
+	MsgID          string    `json:"msg_id"`
+	CorrelID       string    `json:"correl_id"`
+	BackoutCount   int32     `json:"backout_count"`
+	PutApplName    string    `json:"put_appl_name"`
+	PutDateTime    time.Time `json:"put_date_time"`
+	UserIdentifier string    `json:"user_identifier"`
+}
+
+// New creates a quarantine from its configuration. put is used when a queue is
+// configured; without it the queue is ignored. The directory is created if it does not
+// exist, and the messages already in it count towards the limit.
+func New(cfg *config.QuarantineConfig, put PutFunc) (*Quarantine, error) {
+	q := &Quarantine{
+		dir:         cfg.Directory,
+		maxMessages: cfg.MaxMessages,
+	}
+	if put != nil {
+		q.queue, q.put = cfg.Queue, put
+	}
+
+	if q.dir != "" {
+		if err := os.MkdirAll(q.dir, 0750); err != nil {
+			return nil, fmt.Errorf("failed to create quarantine directory: %w", err)
+		}
+		existing, err := filepath.Glob(filepath.Join(q.dir, "*"+payloadSuffix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list quarantine directory: %w", err)
+		}
+		q.stored = len(existing)
+	}
+	return q, nil
+}
+
+// Add quarantines a message that failed to parse with cause. It is kept by every
+// destination that can take it; the error reports those that could not. Once the
+// directory is full, messages only go to the queue.
+func (q *Quarantine) Add(msg *mqclient.MQMessage, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var errs []error
+	full := q.maxMessages > 0 && q.stored >= q.maxMessages
+	if q.dir != "" && !full {
+		if err := q.write(msg, cause); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if q.queue != "" {
+		if err := q.put(q.queue, msg); err != nil {
+			errs = append(errs, fmt.Errorf("failed to put message to quarantine queue: %w", err))
+		}
+	} else if q.dir != "" && full {
+		errs = append(errs, ErrFull)
+	}
+	return errors.Join(errs...)
+}
+
+// write writes the payload and description of a message to the directory
+func (q *Quarantine) write(msg *mqclient.MQMessage, cause error) error {
+	now := time.Now().UTC()
+	q.seq++
+	name := fmt.Sprintf("%s-%s-%d", now.Format("20060102T150405.000000000"), strings.ToLower(msg.Type), q.seq)
+	base := filepath.Join(q.dir, name)
+
+	entry := Entry{
+		Type:        msg.Type,
+		Error:       cause.Error(),
+		Quarantined: now,
+		Length:      len(msg.Data),
+	}
+	if md := msg.MD; md != nil {
+		entry.MD = &Descriptor{
+			Format:         strings.TrimSpace(md.Format),
+			Encoding:       md.Encoding,
+			CodedCharSetID: md.CodedCharSetId,
+			MsgType:        md.MsgType,
+			Persistence:    md.Persistence,
+			MsgID:          hex.EncodeToString(md.MsgId),
+			CorrelID:       hex.EncodeToString(md.CorrelId),
+			BackoutCount:   md.BackoutCount,
+			PutApplName:    strings.TrimSpace(md.PutApplName),
+			PutDateTime:    md.PutDateTime,
+			UserIdentifier: strings.TrimSpace(md.UserIdentifier),
+		}
+	}
+	description, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode quarantine entry: %w", err)
+	}
+
+	if err := os.WriteFile(base+payloadSuffix, msg.Data, 0600); err != nil {
+		return fmt.Errorf("failed to write quarantined message: %w", err)
+	}
+	if err := os.WriteFile(base+entrySuffix, description, 0600); err != nil {
+		return fmt.Errorf("failed to write quarantine entry: %w", err)
+	}
+	q.stored++
+	return nil
+}
@@ -0,0 +1,74 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuarantineWritesPayloadAndDescriptor(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "quarantine")
+	q, err := New(&config.QuarantineConfig{Directory: dir, MaxMessages: 1}, nil)
+	require.NoError(t, err)
+
+	md := ibmmq.NewMQMD()
+	md.Format = "MQADMIN "
+	md.MsgId = []byte{0x01, 0xab}
+	msg := &mqclient.MQMessage{MD: md, Data: []byte{0x01, 0x02, 0x03}, Type: "stats"}
+	require.NoError(t, q.Add(msg, errors.New("message too short")))
+
+	payloads, err := filepath.Glob(filepath.Join(dir, "*.pcf"))
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	data, err := os.ReadFile(payloads[0])
+	require.NoError(t, err)
+	assert.Equal(t, msg.Data, data)
+
+	description, err := os.ReadFile(payloads[0][:len(payloads[0])-len(".pcf")] + ".json")
+	require.NoError(t, err)
+	var entry Entry
+	require.NoError(t, json.Unmarshal(description, &entry))
+	assert.Equal(t, "stats", entry.Type)
+	assert.Equal(t, "message too short", entry.Error)
+	assert.Equal(t, 3, entry.Length)
+	require.NotNil(t, entry.MD)
+	assert.Equal(t, "MQADMIN", entry.MD.Format)
+	assert.Equal(t, "01ab", entry.MD.MsgID)
+
+	// The directory is full, also for a quarantine opened afterwards
+	assert.ErrorIs(t, q.Add(msg, errors.New("again")), ErrFull)
+	reopened, err := New(&config.QuarantineConfig{Directory: dir, MaxMessages: 1}, nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, reopened.Add(msg, errors.New("again")), ErrFull)
+}
+
+func TestQuarantinePutsToQueue(t *testing.T) {
+	var queues []string
+	put := func(queueName string, msg *mqclient.MQMessage) error {
+		queues = append(queues, queueName)
+		return nil
+	}
+
+	dir := t.TempDir()
+	q, err := New(&config.QuarantineConfig{Directory: dir, Queue: "COLLECTOR.QUARANTINE", MaxMessages: 1}, put)
+	require.NoError(t, err)
+
+	msg := &mqclient.MQMessage{Data: []byte{0x01}, Type: "accounting"}
+	require.NoError(t, q.Add(msg, errors.New("bad")))
+	// Once the directory is full the queue still takes the message
+	require.NoError(t, q.Add(msg, errors.New("bad")))
+	assert.Equal(t, []string{"COLLECTOR.QUARANTINE", "COLLECTOR.QUARANTINE"}, queues)
+
+	// Without a way to put, the queue is ignored
+	q, err = New(&config.QuarantineConfig{Queue: "COLLECTOR.QUARANTINE"}, nil)
+	require.NoError(t, err)
+	assert.NoError(t, q.Add(msg, errors.New("bad")))
+}
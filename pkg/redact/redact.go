@@ -0,0 +1,120 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Mask replaces secrets in log entries and messages
+const Mask = "******"
+
+// sensitiveKeys are the parts of log field names whose values are always masked
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "credential"}
+
+var (
+	mu       sync.RWMutex
+	secrets  []string
+	replacer = strings.NewReplacer()
+)
+
+// Register adds secret values to be masked wherever they appear. Configuration loading
+// registers the passwords and tokens it reads, so they are masked in every log entry
+// and error message printed afterwards, whichever part of the program produced them.
+func Register(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, value := range values {
+		if value == "" || value == Mask || slices.Contains(secrets, value) {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+
+	// Longest first, so a secret that contains another is masked as a whole
+	slices.SortStableFunc(secrets, func(a, b string) int { return len(b) - len(a) })
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		pairs = append(pairs, secret, Mask)
+	}
+	replacer = strings.NewReplacer(pairs...)
+}
+
+// String returns s with every registered secret masked
+func String(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return replacer.Replace(s)
+}
+
+// Hook is a logrus hook masking registered secrets in the message and fields of every
+// entry, and the whole value of fields whose name suggests a credential
+type Hook struct{}
+
+// NewHook creates a redaction hook; add it to every logger with AddHook
+func NewHook() *Hook {
+	return &Hook{}
+}
+
+// Levels returns all levels, since debug entries are as likely to hold secrets
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire masks the secrets of an entry before it is formatted
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	entry.Message = String(entry.Message)
+
+	// Data may be shared with the logger's other entries, so it is replaced, not changed
+	data := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		data[key] = field(key, value)
+	}
+	entry.Data = data
+	return nil
+}
+
+// field returns the value of a log field with its secrets masked
+func field(key string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if sensitive(key) {
+		return Mask
+	}
+
+	switch v := value.(type) {
+	case string:
+		return String(v)
+	case error:
+		if masked := String(v.Error()); masked != v.Error() {
+			return errors.New(masked)
+		}
+		return v
+	case bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return v
+	}
+
+	// Other values are formatted once to find out whether they hold a secret
+	text := fmt.Sprint(value)
+	if masked := String(text); masked != text {
+		return masked
+	}
+	return value
+}
+
+// sensitive reports whether a field name suggests a credential
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeys {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,321 @@
+package resmon
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+)
+
+// metadataWait is how long each metadata publication is waited for. They are retained,
+// so they arrive as soon as they are subscribed to unless the queue manager does not
+// publish them at all.
+const metadataWait = 5 * time.Second
+
+// Metric is the latest value of one element of a resource monitoring type, for the queue
+// manager or for one queue
+type Metric struct {
+	Class   string  // such as CPU, DISK or STATQ
+	Type    string  // such as SystemSummary, Log or PUT
+	Element string  // the description of the element as a metric name, such as user_cpu_time_percentage
+	Queue   string  // queue name, empty for the metrics of the queue manager
+	Value   float64 // in base units: percentages, seconds and bytes
+}
+
+// monitorType is a type subscribed to, with the class it belongs to and its elements
+type monitorType struct {
+	class    pcf.MonitorClass
+	typ      pcf.MonitorType
+	elements map[int32]pcf.MonitorElement
+}
+
+// typeKey identifies a type in the publications
+type typeKey struct {
+	class, typ int32
+}
+
+// Monitor subscribes to the resource monitoring topics that queue managers from IBM MQ
+// 9.0 publish under $SYS/MQ/INFO/QMGR/<qmgr>/Monitor, which report CPU, memory, log
+// and per-queue activity that never appears on the statistics queue. It reads the
+// published metadata to learn the classes, types and elements, subscribes to the metric
+// topics of the configured classes and turns their publications into metrics.
+type Monitor struct {
+	client mqclient.MQClientAPI
+	parser *pcf.Parser
+	logger logging.Logger
+	config config.ResourceMonitorConfig
+	qmgr   string
+
+	types  map[typeKey]*monitorType
+	topics []string // metric topics subscribed to
+}
+
+// NewMonitor creates a monitor of the queue manager named qmgr, or of the queue manager
+// connected to when it is empty, subscribing through client
+func NewMonitor(cfg config.ResourceMonitorConfig, qmgr string, client mqclient.MQClientAPI, logger logging.Logger) *Monitor {
+	return &Monitor{
+		client: client,
+		parser: pcf.NewParser(logger),
+		logger: logger,
+		config: cfg,
+		qmgr:   qmgr,
+	}
+}
+
+// Subscribed reports whether the monitor has subscribed to the metric topics
+func (m *Monitor) Subscribed() bool {
+	return m.types != nil
+}
+
+// Reset forgets the subscriptions, which end with the connection they were made on, so
+// the next Subscribe makes them again
+func (m *Monitor) Reset() {
+	m.types = nil
+	m.topics = nil
+}
+
+// Subscribe reads the metadata published for the configured classes and subscribes to
+// the topics of their metrics; those of STATQ for each configured queue. A class the queue
+// manager does not publish is logged and left out. When a subscription fails, those
+// made before it are ended, so Subscribe can be retried.
+func (m *Monitor) Subscribe() error {
+	m.topics = nil
+
+	qmgr := m.qmgr
+	if qmgr == "" {
+		settings, err := m.client.InquireQueueManager()
+		if err != nil {
+			return fmt.Errorf("failed to inquire the queue manager name: %w", err)
+		}
+		qmgr = settings.Name
+	}
+
+	data, err := m.metadata(fmt.Sprintf("$SYS/MQ/INFO/QMGR/%s/Monitor/METADATA/CLASSES", qmgr))
+	if err != nil {
+		return err
+	}
+	classes, err := m.parser.ParseMonitorClasses(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse the monitor classes: %w", err)
+	}
+
+	queues, err := m.queueNames()
+	if err != nil {
+		return err
+	}
+
+	types := make(map[typeKey]*monitorType)
+	for _, name := range m.config.Classes {
+		i := slices.IndexFunc(classes, func(class pcf.MonitorClass) bool { return class.Name == name })
+		if i < 0 {
+			m.logger.WithField("class", name).Warn("Queue manager publishes no resource monitoring class of this name")
+			continue
+		}
+		if err := m.subscribeClass(classes[i], queues, types); err != nil {
+			m.unsubscribe()
+			return err
+		}
+	}
+
+	m.types = types
+	m.logger.WithFields(logging.Fields{
+		"queue_manager": qmgr,
+		"types":         len(types),
+		"topics":        len(m.topics),
+	}).Info("Subscribed to resource monitoring topics")
+	return nil
+}
+
+// subscribeClass subscribes to the metric topics of the types of a class, per queue when
+// the class is published per object
+func (m *Monitor) subscribeClass(class pcf.MonitorClass, queues []string, types map[typeKey]*monitorType) error {
+	data, err := m.metadata(class.TypesTopic)
+	if err != nil {
+		return err
+	}
+	classTypes, err := m.parser.ParseMonitorTypes(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse the types of monitor class %s: %w", class.Name, err)
+	}
+
+	for _, typ := range classTypes {
+		data, err := m.metadata(typ.ElementsTopic)
+		if err != nil {
+			return err
+		}
+		topic, elements, err := m.parser.ParseMonitorElements(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse the elements of monitor type %s/%s: %w", class.Name, typ.Name, err)
+		}
+
+		t := &monitorType{class: class, typ: typ, elements: make(map[int32]pcf.MonitorElement)}
+		for _, element := range elements {
+			t.elements[element.ID] = element
+		}
+		types[typeKey{class.ID, typ.ID}] = t
+
+		topics := []string{topic}
+		if class.PerObject {
+			topics = nil
+			for _, queue := range queues {
+				topics = append(topics, fmt.Sprintf(topic, queue))
+			}
+		}
+		for _, topic := range topics {
+			if err := m.client.Subscribe(topic); err != nil {
+				return err
+			}
+			m.topics = append(m.topics, topic)
+		}
+	}
+	return nil
+}
+
+// unsubscribe ends the subscriptions to the metric topics
+func (m *Monitor) unsubscribe() {
+	for _, topic := range m.topics {
+		if err := m.client.Unsubscribe(topic); err != nil {
+			m.logger.WithError(err).WithField("topic", topic).Warn("Failed to unsubscribe from resource monitoring topic")
+		}
+	}
+	m.topics = nil
+}
+
+// metadata subscribes to a metadata topic and returns its retained publication
+func (m *Monitor) metadata(topic string) ([]byte, error) {
+	if err := m.client.Subscribe(topic); err != nil {
+		return nil, err
+	}
+	defer m.client.Unsubscribe(topic)
+
+	msg, err := m.client.GetPublication(topic, metadataWait)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("no publication on %s; resource monitoring needs IBM MQ 9.0 or later", topic)
+	}
+	return msg.Data, nil
+}
+
+// queueNames returns the names of the configured queues, with generic names resolved
+// through the command server
+func (m *Monitor) queueNames() ([]string, error) {
+	var names []string
+	for _, queue := range m.config.Queues {
+		if !strings.HasSuffix(queue, "*") {
+			names = append(names, queue)
+			continue
+		}
+		infos, err := m.client.InquireQueues(queue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve queues %s: %w", queue, err)
+		}
+		for _, info := range infos {
+			names = append(names, info.QueueName)
+		}
+	}
+	return names, nil
+}
+
+// Collect gets the publications received since the previous call and returns the latest
+// value of each element, sorted by class, type, queue and element. The values of delta
+// elements, which count events since the previous publication, are summed over the
+// publications instead. Publications that cannot be parsed are logged and skipped.
+func (m *Monitor) Collect() ([]Metric, error) {
+	type metricKey struct {
+		typ     typeKey
+		element int32
+		queue   string
+	}
+	values := make(map[metricKey]*Metric)
+
+	for _, topic := range m.topics {
+		for {
+			msg, err := m.client.GetPublication(topic, 0)
+			if err != nil {
+				return nil, err
+			}
+			if msg == nil {
+				break
+			}
+
+			publication, err := m.parser.ParseMonitorPublication(msg.Data)
+			if err != nil {
+				m.logger.WithError(err).WithField("topic", topic).Warn("Skipping resource monitoring publication")
+				continue
+			}
+			key := typeKey{publication.Class, publication.Type}
+			t, ok := m.types[key]
+			if !ok {
+				continue
+			}
+
+			for id, value := range publication.Values {
+				element, ok := t.elements[id]
+				if !ok {
+					continue
+				}
+				k := metricKey{key, id, publication.ObjectName}
+				metric, seen := values[k]
+				if !seen {
+					metric = &Metric{
+						Class:   t.class.Name,
+						Type:    t.typ.Name,
+						Element: metricName(element.Description),
+						Queue:   publication.ObjectName,
+					}
+					values[k] = metric
+				}
+				if seen && element.Delta() {
+					metric.Value += element.Normalise(value)
+				} else {
+					metric.Value = element.Normalise(value)
+				}
+			}
+		}
+	}
+
+	metrics := make([]Metric, 0, len(values))
+	for _, metric := range values {
+		metrics = append(metrics, *metric)
+	}
+	slices.SortFunc(metrics, func(a, b Metric) int {
+		for _, c := range []int{
+			strings.Compare(a.Class, b.Class),
+			strings.Compare(a.Type, b.Type),
+			strings.Compare(a.Queue, b.Queue),
+		} {
+			if c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.Element, b.Element)
+	})
+	return metrics, nil
+}
+
+// metricName turns the description of an element into a metric name: lower case, with
+// each run of other characters than letters and digits replaced by an underscore, so
+// "MQPUT/MQPUT1 count" becomes mqput_mqput1_count
+func metricName(description string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(description) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		underscore = true
+	}
+	return b.String()
+}
@@ -0,0 +1,161 @@
+package resmon
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqfake"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const metadataTopic = "$SYS/MQ/INFO/QMGR/QM1/Monitor/METADATA/"
+
+// publishMetadata publishes the metadata of a CPU class with one queue manager type and
+// a STATQ class with one per-queue type
+func publishMetadata(client *mqfake.Client) {
+	client.Publish(metadataTopic+"CLASSES", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddGroup(pcf.MQGACF_MONITOR_CLASS, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_CLASS, 0).
+		AddString(pcf.MQCAMO_MONITOR_CLASS, "CPU").
+		AddString(pcf.MQCA_TOPIC_STRING, metadataTopic+"CPU/TYPES").
+		AddGroup(pcf.MQGACF_MONITOR_CLASS, 4).
+		AddInteger(pcf.MQIAMO_MONITOR_CLASS, 4).
+		AddInteger(pcf.MQIAMO_MONITOR_FLAGS, pcf.MQIAMO_MONITOR_FLAGS_OBJNAME).
+		AddString(pcf.MQCAMO_MONITOR_CLASS, "STATQ").
+		AddString(pcf.MQCA_TOPIC_STRING, metadataTopic+"STATQ/TYPES").
+		Bytes())
+
+	client.Publish(metadataTopic+"CPU/TYPES", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddGroup(pcf.MQGACF_MONITOR_TYPE, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_TYPE, 1).
+		AddString(pcf.MQCAMO_MONITOR_TYPE, "QMgrSummary").
+		AddString(pcf.MQCA_TOPIC_STRING, metadataTopic+"CPU/QMgrSummary").
+		Bytes())
+	client.Publish(metadataTopic+"CPU/QMgrSummary", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddString(pcf.MQCA_TOPIC_STRING, "$SYS/MQ/INFO/QMGR/QM1/Monitor/CPU/QMgrSummary").
+		AddGroup(pcf.MQGACF_MONITOR_ELEMENT, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_ELEMENT, 0).
+		AddInteger(pcf.MQIAMO_MONITOR_DATATYPE, pcf.MQIAMO_MONITOR_PERCENT).
+		AddString(pcf.MQCAMO_MONITOR_DESC, "User CPU time - percentage estimate for queue manager").
+		Bytes())
+
+	client.Publish(metadataTopic+"STATQ/TYPES", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddGroup(pcf.MQGACF_MONITOR_TYPE, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_TYPE, 2).
+		AddString(pcf.MQCAMO_MONITOR_TYPE, "PUT").
+		AddString(pcf.MQCA_TOPIC_STRING, metadataTopic+"STATQ/PUT").
+		Bytes())
+	client.Publish(metadataTopic+"STATQ/PUT", pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+		AddString(pcf.MQCA_TOPIC_STRING, "$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/%s/PUT").
+		AddGroup(pcf.MQGACF_MONITOR_ELEMENT, 3).
+		AddInteger(pcf.MQIAMO_MONITOR_ELEMENT, 0).
+		AddInteger(pcf.MQIAMO_MONITOR_DATATYPE, pcf.MQIAMO_MONITOR_DELTA).
+		AddString(pcf.MQCAMO_MONITOR_DESC, "MQPUT/MQPUT1 count").
+		Bytes())
+}
+
+func TestMonitor(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	client := mqfake.New(nil)
+	client.Queues = []*mqclient.QueueInfo{{QueueName: "APP.ORDERS"}, {QueueName: "APP.PAYMENTS"}}
+	publishMetadata(client)
+	require.NoError(t, client.Connect())
+
+	cfg := config.ResourceMonitorConfig{Enabled: true, Classes: []string{"CPU", "STATQ", "NHAREPLICA"}, Queues: []string{"APP.*"}}
+	monitor := NewMonitor(cfg, "QM1", client, logging.NewLogrus(logger))
+	require.NoError(t, monitor.Subscribe())
+	assert.True(t, monitor.Subscribed())
+	assert.Equal(t, []string{
+		"$SYS/MQ/INFO/QMGR/QM1/Monitor/CPU/QMgrSummary",
+		"$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/APP.ORDERS/PUT",
+		"$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/APP.PAYMENTS/PUT",
+	}, client.Subscribed())
+
+	client.Publish("$SYS/MQ/INFO/QMGR/QM1/Monitor/CPU/QMgrSummary",
+		pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+			AddInteger(pcf.MQIAMO_MONITOR_CLASS, 0).
+			AddInteger(pcf.MQIAMO_MONITOR_TYPE, 1).
+			AddInteger64(0, 1250).
+			Bytes(),
+		pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+			AddInteger(pcf.MQIAMO_MONITOR_CLASS, 0).
+			AddInteger(pcf.MQIAMO_MONITOR_TYPE, 1).
+			AddInteger64(0, 300).
+			Bytes())
+	for _, count := range []int64{10, 15} {
+		client.Publish("$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/APP.ORDERS/PUT",
+			pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, 0).
+				AddString(pcf.MQCA_Q_NAME, "APP.ORDERS").
+				AddInteger(pcf.MQIAMO_MONITOR_CLASS, 4).
+				AddInteger(pcf.MQIAMO_MONITOR_TYPE, 2).
+				AddInteger64(0, count).
+				Bytes())
+	}
+
+	metrics, err := monitor.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{Class: "CPU", Type: "QMgrSummary", Element: "user_cpu_time_percentage_estimate_for_queue_manager", Value: 3},
+		{Class: "STATQ", Type: "PUT", Element: "mqput_mqput1_count", Queue: "APP.ORDERS", Value: 25},
+	}, metrics)
+
+	metrics, err = monitor.Collect()
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+
+	monitor.Reset()
+	assert.False(t, monitor.Subscribed())
+}
+
+func TestMonitorSubscribeRetry(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	client := mqfake.New(nil)
+	client.Queues = []*mqclient.QueueInfo{{QueueName: "APP.ORDERS"}, {QueueName: "APP.PAYMENTS"}}
+	client.SubscribeFailures = []string{"$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/APP.PAYMENTS/PUT"}
+	require.NoError(t, client.Connect())
+
+	cfg := config.ResourceMonitorConfig{Enabled: true, Classes: []string{"CPU", "STATQ"}, Queues: []string{"APP.*"}}
+	monitor := NewMonitor(cfg, "QM1", client, logging.NewLogrus(logger))
+	publishMetadata(client)
+	assert.ErrorContains(t, monitor.Subscribe(), "APP.PAYMENTS")
+	assert.False(t, monitor.Subscribed())
+	assert.Empty(t, client.Subscribed())
+	assert.Empty(t, monitor.topics)
+
+	client.SubscribeFailures = nil
+	publishMetadata(client)
+	require.NoError(t, monitor.Subscribe())
+	assert.Equal(t, []string{
+		"$SYS/MQ/INFO/QMGR/QM1/Monitor/CPU/QMgrSummary",
+		"$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/APP.ORDERS/PUT",
+		"$SYS/MQ/INFO/QMGR/QM1/Monitor/STATQ/APP.PAYMENTS/PUT",
+	}, client.Subscribed())
+	assert.Equal(t, client.Subscribed(), monitor.topics)
+}
+
+func TestMonitorWithoutMetadata(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	client := mqfake.New(nil)
+	require.NoError(t, client.Connect())
+
+	monitor := NewMonitor(config.ResourceMonitorConfig{Enabled: true, Classes: []string{"CPU"}}, "QM1", client, logging.NewLogrus(logger))
+	assert.ErrorContains(t, monitor.Subscribe(), "IBM MQ 9.0 or later")
+	assert.False(t, monitor.Subscribed())
+}
+
+func TestMetricName(t *testing.T) {
+	assert.Equal(t, "mqput_mqput1_count", metricName("MQPUT/MQPUT1 count"))
+	assert.Equal(t, "ram_free_percentage", metricName("RAM free percentage"))
+	assert.Equal(t, "log_write_latency", metricName(" Log - write latency "))
+}
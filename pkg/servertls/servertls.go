@@ -0,0 +1,75 @@
+package servertls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+)
+
+// fipsCipherSuites are the FIPS-approved TLS 1.2 cipher suites. TLS 1.3 suites are not
+// configurable; in FIPS 140-3 mode Go offers the AES-GCM ones only.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// New builds the TLS configuration of a server from its settings. The certificate and
+// CA bundle are read once, so a server fails at start rather than on its first
+// connection. With a client CA bundle, connections without a client certificate signed
+// by one of its CAs are refused, as are those whose certificate common name is not in
+// the allowed list when there is one. With fips, only FIPS-approved cipher suites and
+// curves are negotiated.
+func New(cfg *config.ServerTLSConfig, fips bool) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if fips {
+		tlsConfig.CipherSuites = fipsCipherSuites
+		tlsConfig.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+	}
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	bundle, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("client CA bundle %s holds no PEM certificates", cfg.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+	if allowed := cfg.AllowedClientCNs; len(allowed) > 0 {
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyCommonName(state, allowed)
+		}
+	}
+	return tlsConfig, nil
+}
+
+// verifyCommonName checks that the verified client certificate has an allowed common name
+func verifyCommonName(state tls.ConnectionState, allowed []string) error {
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("client certificate required")
+	}
+	cn := state.PeerCertificates[0].Subject.CommonName
+	if !slices.Contains(allowed, cn) {
+		return fmt.Errorf("client certificate common name %q is not allowed", cn)
+	}
+	return nil
+}
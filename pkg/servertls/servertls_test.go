@@ -0,0 +1,201 @@
+package servertls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA signs the server and client certificates of a test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a certificate and key in PEM signed by the CA
+func (ca *testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+// startServer serves 200 OK over TLS with the configuration built from cfg
+func startServer(t *testing.T, cfg *config.ServerTLSConfig) *httptest.Server {
+	tlsConfig, err := New(cfg, false)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// get requests the server with the client certificate, if any
+func get(ca *testCA, server *httptest.Server, clientCert *tls.Certificate) error {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	tlsConfig := &tls.Config{RootCAs: roots}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestNewRequiresAllowedClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "collector", x509.ExtKeyUsageServerAuth)
+	cfg := &config.ServerTLSConfig{
+		CertFile:         writeFile(t, dir, "server.crt", serverCert),
+		KeyFile:          writeFile(t, dir, "server.key", serverKey),
+		ClientCAFile:     writeFile(t, dir, "ca.crt", ca.pem),
+		AllowedClientCNs: []string{"prometheus"},
+	}
+	server := startServer(t, cfg)
+
+	client := func(cn string) *tls.Certificate {
+		certPEM, keyPEM := ca.issue(t, cn, x509.ExtKeyUsageClientAuth)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, err)
+		return &cert
+	}
+
+	assert.NoError(t, get(ca, server, client("prometheus")))
+	assert.Error(t, get(ca, server, client("intruder")))
+	assert.Error(t, get(ca, server, nil))
+
+	// A certificate from another CA is refused whatever its name
+	other := newTestCA(t)
+	certPEM, keyPEM := other.issue(t, "prometheus", x509.ExtKeyUsageClientAuth)
+	foreign, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	assert.Error(t, get(ca, server, &foreign))
+}
+
+func TestNewWithoutClientCA(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "collector", x509.ExtKeyUsageServerAuth)
+	server := startServer(t, &config.ServerTLSConfig{
+		CertFile: writeFile(t, dir, "server.crt", serverCert),
+		KeyFile:  writeFile(t, dir, "server.key", serverKey),
+	})
+
+	assert.NoError(t, get(ca, server, nil))
+}
+
+func TestNewRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "collector", x509.ExtKeyUsageServerAuth)
+	certFile := writeFile(t, dir, "server.crt", serverCert)
+	keyFile := writeFile(t, dir, "server.key", serverKey)
+
+	_, err := New(&config.ServerTLSConfig{CertFile: certFile, KeyFile: filepath.Join(dir, "missing.key")}, false)
+	assert.Error(t, err)
+
+	_, err = New(&config.ServerTLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: filepath.Join(dir, "missing.crt")}, false)
+	assert.Error(t, err)
+
+	_, err = New(&config.ServerTLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: writeFile(t, dir, "empty.crt", []byte("not a certificate"))}, false)
+	assert.Error(t, err)
+}
+
+func TestNewRestrictsCipherSuitesForFIPS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "collector", x509.ExtKeyUsageServerAuth)
+	cfg := &config.ServerTLSConfig{
+		CertFile: writeFile(t, dir, "server.crt", serverCert),
+		KeyFile:  writeFile(t, dir, "server.key", serverKey),
+	}
+
+	tlsConfig, err := New(cfg, true)
+	require.NoError(t, err)
+	assert.Equal(t, fipsCipherSuites, tlsConfig.CipherSuites)
+	assert.Equal(t, []tls.CurveID{tls.CurveP256, tls.CurveP384}, tlsConfig.CurvePreferences)
+	for _, id := range tlsConfig.CipherSuites {
+		assert.Contains(t, tls.CipherSuiteName(id), "_GCM_")
+	}
+
+	// A client limited to a non-approved suite cannot connect
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256},
+	}}}
+	_, err = client.Get(server.URL)
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package simulator
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Options controls the shape of the simulated queue manager
+type Options struct {
+	QueueManager string
+	Queues       int
+	Channels     int
+	Applications int
+	Seed         int64
+}
+
+// DefaultOptions returns options producing a small but varied queue manager
+func DefaultOptions() Options {
+	return Options{
+		QueueManager: "SIMQM",
+		Queues:       5,
+		Channels:     2,
+		Applications: 3,
+		Seed:         time.Now().UnixNano(),
+	}
+}
+
+// queueState tracks the evolving state of a simulated queue between intervals
+type queueState struct {
+	name      string
+	depth     int32
+	rate      int32 // typical messages per interval
+	readers   int32
+	writers   int32
+	stalled   bool // consumer stopped, depth grows
+	highDepth int32
+}
+
+// channelState tracks a simulated channel
+type channelState struct {
+	name           string
+	connectionName string
+	msgSize        int64
+}
+
+// Generator fabricates statistics and accounting PCF messages that look like
+// the output of a busy queue manager, one interval at a time
+type Generator struct {
+	opts     Options
+	rnd      *rand.Rand
+	queues   []*queueState
+	channels []*channelState
+	apps     []string
+}
+
+var queueNames = []string{
+	"APP.ORDERS.IN", "APP.ORDERS.OUT", "APP.PAYMENTS.REQUEST", "APP.PAYMENTS.REPLY",
+	"APP.INVENTORY.EVENTS", "APP.SHIPPING.IN", "APP.AUDIT.LOG", "APP.NOTIFY.EMAIL",
+}
+
+var applicationNames = []string{
+	"order-service", "payment-gateway", "inventory-sync", "shipping-worker", "audit-writer",
+}
+
+// NewGenerator creates a generator for the given options
+func NewGenerator(opts Options) *Generator {
+	if opts.QueueManager == "" {
+		opts.QueueManager = "SIMQM"
+	}
+
+	g := &Generator{
+		opts: opts,
+		rnd:  rand.New(rand.NewSource(opts.Seed)),
+	}
+
+	for i := 0; i < opts.Queues; i++ {
+		name := queueNames[i%len(queueNames)]
+		if i >= len(queueNames) {
+			name = fmt.Sprintf("%s.%d", name, i/len(queueNames))
+		}
+		g.queues = append(g.queues, &queueState{
+			name:    name,
+			rate:    int32(50 + g.rnd.Intn(950)),
+			readers: int32(1 + g.rnd.Intn(3)),
+			writers: int32(1 + g.rnd.Intn(2)),
+		})
+	}
+
+	for i := 0; i < opts.Channels; i++ {
+		g.channels = append(g.channels, &channelState{
+			name:           fmt.Sprintf("APP%d.SVRCONN", i+1),
+			connectionName: fmt.Sprintf("10.0.%d.%d", 1+i/250, 10+i%250),
+			msgSize:        int64(256 + g.rnd.Intn(4096)),
+		})
+	}
+
+	for i := 0; i < opts.Applications; i++ {
+		name := applicationNames[i%len(applicationNames)]
+		if i >= len(applicationNames) {
+			name = fmt.Sprintf("%s-%d", name, i/len(applicationNames))
+		}
+		g.apps = append(g.apps, name)
+	}
+
+	return g
+}
+
+// Next advances the simulation by one statistics interval and returns the
+// statistics and accounting messages that MQ would have written for it
+func (g *Generator) Next() (statsMessages, accountingMessages []*mqclient.MQMessage) {
+	now := time.Now().UTC()
+
+	for _, q := range g.queues {
+		statsMessages = append(statsMessages, g.message("stats", now, g.queueStatistics(q)))
+	}
+
+	for _, ch := range g.channels {
+		statsMessages = append(statsMessages, g.message("stats", now, g.channelStatistics(ch)))
+	}
+
+	for _, app := range g.apps {
+		statsMessages = append(statsMessages, g.message("stats", now, g.mqiStatistics(app)))
+		accountingMessages = append(accountingMessages, g.message("accounting", now, g.accounting(app)))
+	}
+
+	return statsMessages, accountingMessages
+}
+
+// queueStatistics evolves a queue and encodes its MQCMD_STATISTICS_Q message
+func (g *Generator) queueStatistics(q *queueState) []byte {
+	// Occasionally stall or recover a consumer so depth alerts have something to fire on
+	if g.rnd.Intn(20) == 0 {
+		q.stalled = !q.stalled
+	}
+
+	enq := q.rate/2 + int32(g.rnd.Intn(int(q.rate)+1))
+	deq := enq - int32(g.rnd.Intn(int(enq/10)+1)) + int32(g.rnd.Intn(int(enq/10)+1))
+	readers := q.readers
+	if q.stalled {
+		deq = 0
+		readers = 0
+	}
+	if deq > q.depth+enq {
+		deq = q.depth + enq
+	}
+
+	q.depth += enq - deq
+	if q.depth > q.highDepth {
+		q.highDepth = q.depth
+	}
+
+	return pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_Q).
+		AddString(pcf.MQCA_Q_MGR_NAME, g.opts.QueueManager).
+		AddString(pcf.MQCA_Q_NAME, q.name).
+		AddInteger(pcf.MQIA_CURRENT_Q_DEPTH, q.depth).
+		AddInteger(pcf.MQIA_HIGH_Q_DEPTH, q.highDepth).
+		AddInteger(pcf.MQIA_MSG_ENQ_COUNT, enq).
+		AddInteger(pcf.MQIA_MSG_DEQ_COUNT, deq).
+		AddInteger(pcf.MQIA_OPEN_INPUT_COUNT, readers).
+		AddInteger(pcf.MQIA_OPEN_OUTPUT_COUNT, q.writers).
+		Bytes()
+}
+
+// channelStatistics encodes an MQCMD_STATISTICS_CHANNEL message
+func (g *Generator) channelStatistics(ch *channelState) []byte {
+	msgs := int32(100 + g.rnd.Intn(5000))
+	batches := msgs/int32(1+g.rnd.Intn(50)) + 1
+
+	return pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_CHANNEL).
+		AddString(pcf.MQCA_Q_MGR_NAME, g.opts.QueueManager).
+		AddString(pcf.MQCA_CHANNEL_NAME, ch.name).
+		AddString(pcf.MQCA_CONNECTION_NAME, ch.connectionName).
+		AddInteger(pcf.MQIACH_MSGS, msgs).
+		AddInteger(pcf.MQIACH_BYTES, int32(int64(msgs)*ch.msgSize)).
+		AddInteger(pcf.MQIACH_BATCHES, batches).
+		Bytes()
+}
+
+// mqiStatistics encodes an MQCMD_STATISTICS_MQI message for an application
+func (g *Generator) mqiStatistics(app string) []byte {
+	puts := int32(g.rnd.Intn(2000))
+	gets := int32(g.rnd.Intn(2000))
+
+	return pcf.NewMessageBuilder(pcf.MQCFT_STATISTICS, pcf.MQCMD_STATISTICS_MQI).
+		AddString(pcf.MQCA_Q_MGR_NAME, g.opts.QueueManager).
+		AddString(pcf.MQCA_APPL_NAME, app).
+		AddInteger(pcf.MQIAMO_OPENS, int32(1+g.rnd.Intn(20))).
+		AddInteger(pcf.MQIAMO_CLOSES, int32(1+g.rnd.Intn(20))).
+		AddInteger(pcf.MQIAMO_PUTS, puts).
+		AddInteger(pcf.MQIAMO_GETS, gets).
+		AddInteger(pcf.MQIAMO_COMMITS, (puts+gets)/10).
+		AddInteger(pcf.MQIAMO_BACKOUTS, int32(g.rnd.Intn(5))).
+		Bytes()
+}
+
+// accounting encodes an MQCMD_ACCOUNTING_MQI message for an application connection
+func (g *Generator) accounting(app string) []byte {
+	builder := pcf.NewMessageBuilder(pcf.MQCFT_ACCOUNTING, pcf.MQCMD_ACCOUNTING_MQI).
+		AddString(pcf.MQCA_Q_MGR_NAME, g.opts.QueueManager).
+		AddString(pcf.MQCA_APPL_NAME, app)
+
+	if len(g.channels) > 0 {
+		ch := g.channels[g.rnd.Intn(len(g.channels))]
+		builder.AddString(pcf.MQCA_CHANNEL_NAME, ch.name).
+			AddString(pcf.MQCA_CONNECTION_NAME, ch.connectionName)
+	}
+
+	puts := int32(g.rnd.Intn(500))
+	gets := int32(g.rnd.Intn(500))
+
+	return builder.
+		AddInteger(pcf.MQIAMO_OPENS, int32(1+g.rnd.Intn(5))).
+		AddInteger(pcf.MQIAMO_CLOSES, int32(1+g.rnd.Intn(5))).
+		AddInteger(pcf.MQIAMO_PUTS, puts).
+		AddInteger(pcf.MQIAMO_GETS, gets).
+		AddInteger(pcf.MQIAMO_COMMITS, (puts+gets)/10).
+		AddInteger(pcf.MQIAMO_BACKOUTS, int32(g.rnd.Intn(3))).
+		Bytes()
+}
+
+// message wraps a PCF payload in an MQMessage with a plausible message descriptor
+func (g *Generator) message(queueType string, now time.Time, data []byte) *mqclient.MQMessage {
+	md := ibmmq.NewMQMD()
+	md.Format = ibmmq.MQFMT_ADMIN
+	md.PutDate = now.Format("20060102")
+	md.PutTime = now.Format("15040500")
+	md.PutDateTime = now
+
+	return &mqclient.MQMessage{
+		MD:   md,
+		Data: data,
+		Type: queueType,
+	}
+}
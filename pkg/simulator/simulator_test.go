@@ -0,0 +1,82 @@
+package simulator
+
+import (
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/logging"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorProducesParseableMessages(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	parser := pcf.NewParser(logging.NewLogrus(logger))
+
+	opts := Options{QueueManager: "TESTQM", Queues: 3, Channels: 2, Applications: 2, Seed: 1}
+	gen := NewGenerator(opts)
+
+	statsMessages, accountingMessages := gen.Next()
+
+	// One message per queue, per channel and per application MQI record
+	assert.Len(t, statsMessages, 3+2+2)
+	assert.Len(t, accountingMessages, 2)
+
+	queues := 0
+	for _, msg := range statsMessages {
+		assert.True(t, msg.IsStatistics())
+
+		result, err := parser.ParseMessage(msg.Data, "statistics")
+		require.NoError(t, err)
+
+		stats, ok := result.(*pcf.StatisticsData)
+		require.True(t, ok)
+		assert.Equal(t, "TESTQM", stats.QueueManager)
+
+		if stats.QueueStats != nil {
+			queues++
+			assert.NotEmpty(t, stats.QueueStats.QueueName)
+			assert.GreaterOrEqual(t, stats.QueueStats.CurrentDepth, int64(0))
+			assert.GreaterOrEqual(t, stats.QueueStats.HighDepth, stats.QueueStats.CurrentDepth)
+		}
+	}
+	assert.Equal(t, 3, queues)
+
+	for _, msg := range accountingMessages {
+		assert.True(t, msg.IsAccounting())
+
+		result, err := parser.ParseMessage(msg.Data, "accounting")
+		require.NoError(t, err)
+
+		acct, ok := result.(*pcf.AccountingData)
+		require.True(t, ok)
+		require.NotNil(t, acct.ConnectionInfo)
+		assert.NotEmpty(t, acct.ConnectionInfo.ApplicationName)
+		assert.NotEmpty(t, acct.ConnectionInfo.ChannelName)
+	}
+}
+
+func TestGeneratorIsDeterministicForSeed(t *testing.T) {
+	opts := Options{QueueManager: "TESTQM", Queues: 2, Channels: 1, Applications: 1, Seed: 42}
+
+	first, _ := NewGenerator(opts).Next()
+	second, _ := NewGenerator(opts).Next()
+
+	require.Equal(t, len(first), len(second))
+	for i := range first {
+		assert.Equal(t, first[i].Data, second[i].Data)
+	}
+}
+
+func TestGeneratorUniqueQueueNames(t *testing.T) {
+	gen := NewGenerator(Options{Queues: 20, Seed: 7})
+
+	seen := make(map[string]bool)
+	for _, q := range gen.queues {
+		assert.False(t, seen[q.name], "duplicate queue name %s", q.name)
+		seen[q.name] = true
+	}
+	assert.Equal(t, "SIMQM", gen.opts.QueueManager)
+}
@@ -0,0 +1,120 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token audience for the Azure Monitor metrics ingestion API
+const azureMonitorResource = "https://monitoring.azure.com/"
+
+// Default Azure identity endpoints
+const (
+	defaultAzureIMDSEndpoint  = "http://169.254.169.254"
+	defaultAzureLoginEndpoint = "https://login.microsoftonline.com"
+)
+
+// azureTokenProvider obtains bearer tokens from the instance managed identity or with a
+// service principal secret, caching each token until shortly before it expires
+type azureTokenProvider struct {
+	auth          string
+	tenantID      string
+	clientID      string
+	clientSecret  string
+	imdsEndpoint  string
+	loginEndpoint string
+	client        *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newAzureTokenProvider(auth, tenantID, clientID, clientSecret string, client *http.Client) *azureTokenProvider {
+	return &azureTokenProvider{
+		auth:          auth,
+		tenantID:      tenantID,
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+		imdsEndpoint:  defaultAzureIMDSEndpoint,
+		loginEndpoint: defaultAzureLoginEndpoint,
+		client:        client,
+	}
+}
+
+// get returns a valid access token
+func (p *azureTokenProvider) get(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Until(p.expires) > 5*time.Minute {
+		return p.token, nil
+	}
+
+	var req *http.Request
+	var err error
+	if p.auth == "service_principal" {
+		form := url.Values{}
+		form.Set("grant_type", "client_credentials")
+		form.Set("client_id", p.clientID)
+		form.Set("client_secret", p.clientSecret)
+		form.Set("resource", azureMonitorResource)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost,
+			fmt.Sprintf("%s/%s/oauth2/token", p.loginEndpoint, url.PathEscape(p.tenantID)), strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		query := url.Values{}
+		query.Set("api-version", "2018-02-01")
+		query.Set("resource", azureMonitorResource)
+		if p.clientID != "" {
+			// Selects a user-assigned identity
+			query.Set("client_id", p.clientID)
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet,
+			p.imdsEndpoint+"/metadata/identity/oauth2/token?"+query.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata", "true")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request azure token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read azure token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("azure token request returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"` // Unix seconds as a string
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("failed to parse azure token response: %w", err)
+	}
+
+	p.token = token.AccessToken
+	p.expires = time.Now().Add(time.Hour)
+	if seconds, err := strconv.ParseInt(token.ExpiresOn, 10, 64); err == nil {
+		p.expires = time.Unix(seconds, 0)
+	}
+	return p.token, nil
+}
@@ -0,0 +1,206 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Azure Monitor only accepts custom metrics timestamped within the last 20 minutes
+const azureMaxSampleAge = 20 * time.Minute
+
+// AzureMonitorSink publishes samples as Azure Monitor custom metrics attached to the
+// configured resource (typically the VM hosting the queue manager). Sample labels become
+// metric dimensions through the configured mapping.
+type AzureMonitorSink struct {
+	config *config.AzureMonitorConfig
+	client *http.Client
+	tokens *azureTokenProvider
+	logger *logrus.Logger
+
+	// Overrides the regional ingestion endpoint in tests
+	endpoint string
+}
+
+// azureMetric is the body of one custom metrics request
+type azureMetric struct {
+	Time string `json:"time"`
+	Data struct {
+		BaseData struct {
+			Metric    string         `json:"metric"`
+			Namespace string         `json:"namespace"`
+			DimNames  []string       `json:"dimNames,omitempty"`
+			Series    []*azureSeries `json:"series"`
+		} `json:"baseData"`
+	} `json:"data"`
+}
+
+// azureSeries is the aggregate of the samples sharing the same dimension values
+type azureSeries struct {
+	DimValues []string `json:"dimValues,omitempty"`
+	Min       float64  `json:"min"`
+	Max       float64  `json:"max"`
+	Sum       float64  `json:"sum"`
+	Count     int      `json:"count"`
+}
+
+// NewAzureMonitorSink creates an Azure Monitor sink
+func NewAzureMonitorSink(cfg *config.AzureMonitorConfig, logger *logrus.Logger) *AzureMonitorSink {
+	client := &http.Client{Timeout: cfg.Timeout}
+	return &AzureMonitorSink{
+		config: cfg,
+		client: client,
+		tokens: newAzureTokenProvider(cfg.Auth, cfg.TenantID, cfg.ClientID, cfg.ClientSecret, client),
+		logger: logger,
+	}
+}
+
+// Name identifies the sink in logs
+func (s *AzureMonitorSink) Name() string {
+	return "azure_monitor"
+}
+
+// Write publishes the samples of the batch, one request per metric and dimension set
+func (s *AzureMonitorSink) Write(ctx context.Context, batch *Batch) error {
+	metrics := s.metrics(samples(batch), time.Now())
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	token, err := s.tokens.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, metric := range metrics {
+		if err := s.post(ctx, token, metric); err != nil {
+			return err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":    s.Name(),
+		"metrics": len(metrics),
+	}).Debug("Published metrics")
+
+	return nil
+}
+
+// Close releases idle connections
+func (s *AzureMonitorSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// metrics groups samples by metric name, dimension names and minute. Samples older than
+// Azure accepts are published at the current time instead of being rejected.
+func (s *AzureMonitorSink) metrics(data []sample, now time.Time) []*azureMetric {
+	var out []*azureMetric
+	byKey := make(map[string]*azureMetric)
+	series := make(map[string]*azureSeries)
+
+	for _, smp := range data {
+		ts := smp.timestamp
+		if ts.IsZero() || now.Sub(ts) > azureMaxSampleAge {
+			ts = now
+		}
+		timestamp := ts.UTC().Truncate(time.Minute).Format(time.RFC3339)
+
+		var names, values []string
+		for _, dim := range s.dimensions(smp) {
+			names = append(names, dim[0])
+			values = append(values, dim[1])
+		}
+
+		key := strings.Join([]string{smp.name, timestamp, strings.Join(names, ",")}, "|")
+		metric := byKey[key]
+		if metric == nil {
+			metric = &azureMetric{Time: timestamp}
+			metric.Data.BaseData.Metric = smp.name
+			metric.Data.BaseData.Namespace = s.config.Namespace
+			metric.Data.BaseData.DimNames = names
+			byKey[key] = metric
+			out = append(out, metric)
+		}
+
+		seriesKey := key + "|" + strings.Join(values, "\x00")
+		if existing := series[seriesKey]; existing != nil {
+			if smp.value < existing.Min {
+				existing.Min = smp.value
+			}
+			if smp.value > existing.Max {
+				existing.Max = smp.value
+			}
+			existing.Sum += smp.value
+			existing.Count++
+			continue
+		}
+
+		entry := &azureSeries{DimValues: values, Min: smp.value, Max: smp.value, Sum: smp.value, Count: 1}
+		series[seriesKey] = entry
+		metric.Data.BaseData.Series = append(metric.Data.BaseData.Series, entry)
+	}
+
+	return out
+}
+
+// dimensions maps sample labels to dimension name/value pairs, sorted by name
+func (s *AzureMonitorSink) dimensions(smp sample) [][2]string {
+	var dims [][2]string
+	for label, value := range smp.labels {
+		name := s.config.Dimensions[label]
+		if name == "" || value == "" {
+			continue
+		}
+		dims = append(dims, [2]string{name, value})
+	}
+	sort.Slice(dims, func(i, j int) bool { return dims[i][0] < dims[j][0] })
+	return dims
+}
+
+// post sends one custom metric to the ingestion endpoint
+func (s *AzureMonitorSink) post(ctx context.Context, token string, metric *azureMetric) error {
+	body, err := json.Marshal(metric)
+	if err != nil {
+		return fmt.Errorf("failed to encode metric %s: %w", metric.Data.BaseData.Metric, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.metricsURL(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("custom metrics request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("custom metrics request for %s returned %s: %s",
+			metric.Data.BaseData.Metric, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// metricsURL returns the regional custom metrics URL for the configured resource
+func (s *AzureMonitorSink) metricsURL() string {
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.monitoring.azure.com", s.config.Region)
+	}
+	return endpoint + "/" + strings.Trim(s.config.ResourceID, "/") + "/metrics"
+}
@@ -0,0 +1,116 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureMonitorSinkWrite(t *testing.T) {
+	tokenRequests := 0
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		if r.Header.Get("Metadata") != "true" || r.URL.Path != "/metadata/identity/oauth2/token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, azureMonitorResource, r.URL.Query().Get("resource"))
+		expires := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+		w.Write([]byte(`{"access_token":"mi-token","expires_on":"` + expires + `"}`))
+	}))
+	defer imds.Close()
+
+	var metrics []azureMetric
+	var paths []string
+	ingest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer mi-token", r.Header.Get("Authorization"))
+		var metric azureMetric
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&metric))
+		metrics = append(metrics, metric)
+		paths = append(paths, r.URL.Path)
+	}))
+	defer ingest.Close()
+
+	cfg := config.DefaultConfig().Sinks.AzureMonitor
+	cfg.Enabled = true
+	cfg.Region = "westeurope"
+	cfg.ResourceID = "/subscriptions/1/resourceGroups/mq/providers/Microsoft.Compute/virtualMachines/mqvm1"
+	sink := NewAzureMonitorSink(&cfg, logrus.New())
+	sink.tokens.imdsEndpoint = imds.URL
+	sink.endpoint = ingest.URL
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	assert.Equal(t, 1, tokenRequests, "token is cached")
+	require.Len(t, metrics, 12, "six queue metrics per batch")
+
+	metric := metrics[0]
+	assert.Equal(t, cfg.ResourceID+"/metrics", paths[0])
+	assert.Equal(t, "queue.depth", metric.Data.BaseData.Metric)
+	assert.Equal(t, "IBMMQ", metric.Data.BaseData.Namespace)
+	assert.Equal(t, []string{"Queue", "QueueManager"}, metric.Data.BaseData.DimNames)
+	require.Len(t, metric.Data.BaseData.Series, 1)
+	assert.Equal(t, azureSeries{DimValues: []string{"APP.ORDERS", "QM1"}, Min: 4, Max: 4, Sum: 4, Count: 1},
+		*metric.Data.BaseData.Series[0])
+
+	// The test record is older than Azure accepts, so it is published at collection time
+	ts, err := time.Parse(time.RFC3339, metric.Time)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), ts, 2*time.Minute)
+}
+
+func TestAzureMonitorMetricsAggregateSeries(t *testing.T) {
+	cfg := config.DefaultConfig().Sinks.AzureMonitor
+	sink := NewAzureMonitorSink(&cfg, logrus.New())
+
+	now := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)
+	labels := map[string]string{"qmgr": "QM1", "application": "orders"}
+	metrics := sink.metrics([]sample{
+		{name: "mqi.puts", value: 10, labels: labels, timestamp: now.Add(-time.Minute)},
+		{name: "mqi.puts", value: 4, labels: labels, timestamp: now.Add(-time.Minute)},
+		{name: "mqi.puts", value: 7, labels: map[string]string{"qmgr": "QM1", "application": "billing"}, timestamp: now.Add(-time.Minute)},
+	}, now)
+
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "2026-03-14T10:29:00Z", metrics[0].Time)
+	series := metrics[0].Data.BaseData.Series
+	require.Len(t, series, 2)
+	assert.Equal(t, azureSeries{DimValues: []string{"orders", "QM1"}, Min: 4, Max: 10, Sum: 14, Count: 2}, *series[0])
+	assert.Equal(t, []string{"billing", "QM1"}, series[1].DimValues)
+}
+
+func TestAzureServicePrincipalToken(t *testing.T) {
+	login := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.URL.Path != "/tenant-1/oauth2/token" || r.PostForm.Get("client_secret") != "s3cr3t" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "app-1", r.PostForm.Get("client_id"))
+		w.Write([]byte(`{"access_token":"sp-token","expires_on":"4102444800"}`))
+	}))
+	defer login.Close()
+
+	provider := newAzureTokenProvider("service_principal", "tenant-1", "app-1", "s3cr3t", login.Client())
+	provider.loginEndpoint = login.URL
+
+	token, err := provider.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "sp-token", token)
+
+	provider = newAzureTokenProvider("service_principal", "tenant-1", "app-1", "wrong", login.Client())
+	provider.loginEndpoint = login.URL
+	_, err = provider.get(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_client")
+}
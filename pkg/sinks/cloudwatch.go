@@ -0,0 +1,161 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// CloudWatchSink publishes samples as custom metrics with the CloudWatch PutMetricData
+// API. Sample labels become dimensions through the configured mapping, so MQ metrics
+// can drive native CloudWatch alarms.
+type CloudWatchSink struct {
+	config      *config.CloudWatchConfig
+	client      *http.Client
+	credentials *awsCredentialProvider
+	logger      *logrus.Logger
+}
+
+// NewCloudWatchSink creates a CloudWatch sink
+func NewCloudWatchSink(cfg *config.CloudWatchConfig, logger *logrus.Logger) *CloudWatchSink {
+	client := &http.Client{Timeout: cfg.Timeout}
+	return &CloudWatchSink{
+		config:      cfg,
+		client:      client,
+		credentials: newAWSCredentialProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken, client),
+		logger:      logger,
+	}
+}
+
+// Name identifies the sink in logs
+func (s *CloudWatchSink) Name() string {
+	return "cloudwatch"
+}
+
+// Write publishes the samples of the batch, up to batch_size metrics per request
+func (s *CloudWatchSink) Write(ctx context.Context, batch *Batch) error {
+	data := samples(batch)
+	for start := 0; start < len(data); start += s.config.BatchSize {
+		end := start + s.config.BatchSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := s.putMetricData(ctx, data[start:end]); err != nil {
+			return err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":    s.Name(),
+		"metrics": len(data),
+	}).Debug("Published metrics")
+
+	return nil
+}
+
+// Close releases idle connections
+func (s *CloudWatchSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// putMetricData sends one PutMetricData request
+func (s *CloudWatchSink) putMetricData(ctx context.Context, data []sample) error {
+	form := s.metricDataForm(data)
+	body := []byte(form.Encode())
+
+	creds, err := s.credentials.retrieve(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint(), strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequest(req, body, creds, s.signingRegion(), "monitoring", time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("PutMetricData request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("PutMetricData returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// metricDataForm builds the PutMetricData query parameters
+func (s *CloudWatchSink) metricDataForm(data []sample) url.Values {
+	form := url.Values{}
+	form.Set("Action", "PutMetricData")
+	form.Set("Version", "2010-08-01")
+	form.Set("Namespace", s.config.Namespace)
+
+	for i, smp := range data {
+		prefix := fmt.Sprintf("MetricData.member.%d.", i+1)
+		form.Set(prefix+"MetricName", smp.name)
+		form.Set(prefix+"Value", strconv.FormatFloat(smp.value, 'f', -1, 64))
+		form.Set(prefix+"Unit", cloudWatchUnit(smp.name))
+		if !smp.timestamp.IsZero() {
+			form.Set(prefix+"Timestamp", smp.timestamp.UTC().Format(time.RFC3339))
+		}
+
+		for j, dim := range s.dimensions(smp) {
+			dimPrefix := fmt.Sprintf("%sDimensions.member.%d.", prefix, j+1)
+			form.Set(dimPrefix+"Name", dim[0])
+			form.Set(dimPrefix+"Value", dim[1])
+		}
+	}
+	return form
+}
+
+// dimensions maps sample labels to dimension name/value pairs, sorted by name
+func (s *CloudWatchSink) dimensions(smp sample) [][2]string {
+	var dims [][2]string
+	for label, value := range smp.labels {
+		name := s.config.Dimensions[label]
+		if name == "" || value == "" {
+			continue
+		}
+		dims = append(dims, [2]string{name, value})
+	}
+	sort.Slice(dims, func(i, j int) bool { return dims[i][0] < dims[j][0] })
+	return dims
+}
+
+func (s *CloudWatchSink) endpoint() string {
+	if s.config.Endpoint != "" {
+		return s.config.Endpoint
+	}
+	return fmt.Sprintf("https://monitoring.%s.amazonaws.com/", s.config.Region)
+}
+
+func (s *CloudWatchSink) signingRegion() string {
+	if s.config.Region != "" {
+		return s.config.Region
+	}
+	return "us-east-1"
+}
+
+// cloudWatchUnit returns the CloudWatch unit for a sample
+func cloudWatchUnit(name string) string {
+	if strings.HasSuffix(name, "bytes") {
+		return "Bytes"
+	}
+	return "Count"
+}
@@ -0,0 +1,128 @@
+package sinks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAWSRequest(t *testing.T) {
+	// Example request from the AWS Signature Version 4 documentation
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(req, nil, creds, "us-east-1", "iam", now)
+
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		req.Header.Get("Authorization"))
+}
+
+func TestCloudWatchSinkWrite(t *testing.T) {
+	var forms []url.Values
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		forms = append(forms, r.PostForm)
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write([]byte(`<PutMetricDataResponse/>`))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig().Sinks.CloudWatch
+	cfg.Enabled = true
+	cfg.Region = "eu-west-1"
+	cfg.Endpoint = server.URL
+	cfg.AccessKeyID = "AKID"
+	cfg.SecretAccessKey = "secret"
+	cfg.BatchSize = 4
+	sink := NewCloudWatchSink(&cfg, logrus.New())
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	require.Len(t, forms, 2, "six queue metrics in batches of four")
+
+	form := forms[0]
+	assert.Equal(t, "PutMetricData", form.Get("Action"))
+	assert.Equal(t, "IBM/MQ", form.Get("Namespace"))
+	assert.Equal(t, "queue.depth", form.Get("MetricData.member.1.MetricName"))
+	assert.Equal(t, "4", form.Get("MetricData.member.1.Value"))
+	assert.Equal(t, "Count", form.Get("MetricData.member.1.Unit"))
+	assert.Equal(t, "2026-03-14T10:30:00Z", form.Get("MetricData.member.1.Timestamp"))
+	assert.Equal(t, "Queue", form.Get("MetricData.member.1.Dimensions.member.1.Name"))
+	assert.Equal(t, "APP.ORDERS", form.Get("MetricData.member.1.Dimensions.member.1.Value"))
+	assert.Equal(t, "QueueManager", form.Get("MetricData.member.1.Dimensions.member.2.Name"))
+	assert.Equal(t, "QM1", form.Get("MetricData.member.1.Dimensions.member.2.Value"))
+	assert.Empty(t, form.Get("MetricData.member.5.MetricName"))
+
+	assert.True(t, strings.HasPrefix(auth[0], "AWS4-HMAC-SHA256 Credential=AKID/"))
+	assert.Contains(t, auth[0], "/eu-west-1/monitoring/aws4_request")
+}
+
+func TestCloudWatchSinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<ErrorResponse><Error><Code>InvalidClientTokenId</Code></Error></ErrorResponse>", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig().Sinks.CloudWatch
+	cfg.Endpoint = server.URL
+	cfg.AccessKeyID = "AKID"
+	sink := NewCloudWatchSink(&cfg, logrus.New())
+
+	err := sink.Write(context.Background(), testBatch())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "InvalidClientTokenId")
+}
+
+func TestAWSInstanceRoleCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+
+	requests := 0
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			w.Write([]byte("imds-token"))
+		case r.Header.Get("X-aws-ec2-metadata-token") != "imds-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+			w.Write([]byte("mq-collector-role"))
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/mq-collector-role":
+			w.Write([]byte(`{"AccessKeyId":"ASIA","SecretAccessKey":"s3cr3t","Token":"session","Expiration":"` +
+				time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer imds.Close()
+
+	provider := newAWSCredentialProvider("", "", "", imds.Client())
+	provider.imdsEndpoint = imds.URL
+
+	creds, err := provider.retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIA", creds.AccessKeyID)
+	assert.Equal(t, "session", creds.SessionToken)
+
+	_, err = provider.retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests, "credentials are cached until close to expiry")
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAENV")
+	creds, err = provider.retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "AKIAENV", creds.AccessKeyID, "environment variables take precedence over the instance role")
+}
@@ -0,0 +1,273 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+)
+
+// ElasticsearchSink bulk-indexes statistics and accounting records into time-based indices
+// named <prefix>-statistics-<date> and <prefix>-accounting-<date>. It works with
+// Elasticsearch 7.8+ and OpenSearch, which share the bulk and index template APIs.
+type ElasticsearchSink struct {
+	config *config.ElasticsearchConfig
+	client *http.Client
+	logger *logrus.Logger
+
+	// Index of the URL that answered last; requests fail over to the next one
+	current           int
+	templateInstalled bool
+}
+
+// statisticsDocument is the indexed form of a statistics record
+type statisticsDocument struct {
+	Timestamp  time.Time `json:"@timestamp"`
+	RecordType string    `json:"record_type"`
+	*pcf.StatisticsData
+}
+
+// accountingDocument is the indexed form of an accounting record
+type accountingDocument struct {
+	Timestamp  time.Time `json:"@timestamp"`
+	RecordType string    `json:"record_type"`
+	*pcf.AccountingData
+}
+
+// bulkItem is one document and the index it goes to
+type bulkItem struct {
+	index    string
+	document interface{}
+}
+
+// bulkResponse is the part of the _bulk response needed to detect rejected documents
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// NewElasticsearchSink creates an Elasticsearch/OpenSearch sink
+func NewElasticsearchSink(cfg *config.ElasticsearchConfig, logger *logrus.Logger) *ElasticsearchSink {
+	return &ElasticsearchSink{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		logger: logger,
+	}
+}
+
+// Name identifies the sink in logs
+func (s *ElasticsearchSink) Name() string {
+	return "elasticsearch"
+}
+
+// Write indexes the batch, installing the index template first if configured
+func (s *ElasticsearchSink) Write(ctx context.Context, batch *Batch) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	if s.config.InstallTemplate && !s.templateInstalled {
+		if err := s.installTemplate(ctx); err != nil {
+			return fmt.Errorf("failed to install index template: %w", err)
+		}
+		s.templateInstalled = true
+	}
+
+	items := s.items(batch)
+	for start := 0; start < len(items); start += s.config.BatchSize {
+		end := start + s.config.BatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		if err := s.bulk(ctx, items[start:end]); err != nil {
+			return err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":      s.Name(),
+		"documents": len(items),
+	}).Debug("Indexed records")
+
+	return nil
+}
+
+// Close releases resources; the sink does not buffer between batches
+func (s *ElasticsearchSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// items converts the batch into documents with their target indices
+func (s *ElasticsearchSink) items(batch *Batch) []bulkItem {
+	now := time.Now().UTC()
+	items := make([]bulkItem, 0, batch.Len())
+
+	for _, stats := range batch.Statistics {
+		ts := recordTime(stats.Timestamp, now)
+		items = append(items, bulkItem{
+			index:    s.indexName("statistics", ts),
+			document: statisticsDocument{Timestamp: ts, RecordType: "statistics", StatisticsData: stats},
+		})
+	}
+
+	for _, acct := range batch.Accounting {
+		ts := recordTime(acct.Timestamp, now)
+		items = append(items, bulkItem{
+			index:    s.indexName("accounting", ts),
+			document: accountingDocument{Timestamp: ts, RecordType: "accounting", AccountingData: acct},
+		})
+	}
+
+	return items
+}
+
+// indexName returns the time-based index for a record type
+func (s *ElasticsearchSink) indexName(recordType string, ts time.Time) string {
+	name := fmt.Sprintf("%s-%s", s.config.IndexPrefix, recordType)
+	if s.config.IndexDateFormat != "" {
+		name += "-" + ts.UTC().Format(s.config.IndexDateFormat)
+	}
+	return name
+}
+
+// bulk sends one _bulk request and reports documents the cluster rejected
+func (s *ElasticsearchSink) bulk(ctx context.Context, items []bulkItem) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, item := range items {
+		action := map[string]map[string]string{"index": {"_index": item.index}}
+		if err := encoder.Encode(action); err != nil {
+			return fmt.Errorf("failed to encode bulk action: %w", err)
+		}
+		if err := encoder.Encode(item.document); err != nil {
+			return fmt.Errorf("failed to encode document: %w", err)
+		}
+	}
+
+	respBody, err := s.do(ctx, http.MethodPost, "/_bulk", body.Bytes(), "application/x-ndjson")
+	if err != nil {
+		return fmt.Errorf("bulk request failed: %w", err)
+	}
+
+	var resp bulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to parse bulk response: %w", err)
+	}
+	if !resp.Errors {
+		return nil
+	}
+
+	failed := 0
+	reason := ""
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status >= 300 {
+				failed++
+				if reason == "" {
+					reason = fmt.Sprintf("%s: %s", result.Error.Type, result.Error.Reason)
+				}
+			}
+		}
+	}
+	return fmt.Errorf("%d of %d documents rejected (%s)", failed, len(items), reason)
+}
+
+// installTemplate creates or updates the index template covering the sink's indices
+func (s *ElasticsearchSink) installTemplate(ctx context.Context) error {
+	template := map[string]interface{}{
+		"index_patterns": []string{s.config.IndexPrefix + "-*"},
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{
+				"dynamic_templates": []interface{}{
+					map[string]interface{}{
+						"strings_as_keywords": map[string]interface{}{
+							"match_mapping_type": "string",
+							"mapping":            map[string]string{"type": "keyword"},
+						},
+					},
+				},
+				"properties": map[string]interface{}{
+					"@timestamp":    map[string]string{"type": "date"},
+					"timestamp":     map[string]string{"type": "date"},
+					"record_type":   map[string]string{"type": "keyword"},
+					"queue_manager": map[string]string{"type": "keyword"},
+					// Raw PCF parameters are kept in _source but not indexed
+					"parameters": map[string]interface{}{"type": "object", "enabled": false},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.do(ctx, http.MethodPut, "/_index_template/"+s.config.IndexPrefix, body, "application/json")
+	return err
+}
+
+// do sends a request to the first URL that answers, starting with the last one that worked
+func (s *ElasticsearchSink) do(ctx context.Context, method, path string, body []byte, contentType string) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt < len(s.config.URLs); attempt++ {
+		i := (s.current + attempt) % len(s.config.URLs)
+		url := strings.TrimRight(s.config.URLs[i], "/") + path
+
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		if s.config.Username != "" {
+			req.SetBasicAuth(s.config.Username, s.config.Password)
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		// Server errors may be limited to one node; client errors will not improve elsewhere
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("%s returned %s", s.config.URLs[i], resp.Status)
+			continue
+		}
+		s.current = i
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("%s returned %s: %s", s.config.URLs[i], resp.Status, strings.TrimSpace(string(respBody)))
+		}
+		return respBody, nil
+	}
+	return nil, lastErr
+}
+
+// recordTime returns the record timestamp, or fallback for records without one
+func recordTime(ts, fallback time.Time) time.Time {
+	if ts.IsZero() {
+		return fallback
+	}
+	return ts.UTC()
+}
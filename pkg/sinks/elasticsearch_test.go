@@ -0,0 +1,173 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCluster records the requests an Elasticsearch sink sends
+type fakeCluster struct {
+	mu        sync.Mutex
+	templates []string
+	bulkLines [][]string
+	auth      []string
+	reject    bool
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user, _, _ := r.BasicAuth()
+	f.auth = append(f.auth, user)
+
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/_index_template/"):
+		f.templates = append(f.templates, strings.TrimPrefix(r.URL.Path, "/_index_template/"))
+		w.Write([]byte(`{"acknowledged":true}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/_bulk":
+		var lines []string
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		f.bulkLines = append(f.bulkLines, lines)
+		if f.reject {
+			w.Write([]byte(`{"errors":true,"items":[{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad field"}}}]}`))
+			return
+		}
+		w.Write([]byte(`{"errors":false,"items":[]}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func testElasticsearchConfig(urls ...string) *config.ElasticsearchConfig {
+	cfg := config.DefaultConfig().Sinks.Elasticsearch
+	cfg.Enabled = true
+	cfg.URLs = urls
+	return &cfg
+}
+
+func testBatch() *Batch {
+	ts := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)
+	return &Batch{
+		Statistics: []*pcf.StatisticsData{{
+			Type:         "statistics",
+			QueueManager: "QM1",
+			Timestamp:    ts,
+			QueueStats:   &pcf.QueueStatistics{QueueName: "APP.ORDERS", CurrentDepth: 4},
+		}},
+		Accounting: []*pcf.AccountingData{{
+			Type:           "accounting",
+			QueueManager:   "QM1",
+			Timestamp:      ts.Add(24 * time.Hour),
+			ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "orders-service"},
+		}},
+	}
+}
+
+func TestElasticsearchSinkWrite(t *testing.T) {
+	cluster := &fakeCluster{}
+	server := httptest.NewServer(cluster)
+	defer server.Close()
+
+	cfg := testElasticsearchConfig(server.URL)
+	cfg.Username = "collector"
+	cfg.BatchSize = 1
+	sink := NewElasticsearchSink(cfg, logrus.New())
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+
+	assert.Equal(t, []string{"ibmmq"}, cluster.templates, "the template is installed once")
+	require.Len(t, cluster.bulkLines, 4, "one bulk request per document with a batch size of 1")
+
+	var action map[string]map[string]string
+	require.NoError(t, json.Unmarshal([]byte(cluster.bulkLines[0][0]), &action))
+	assert.Equal(t, "ibmmq-statistics-2026.03.14", action["index"]["_index"])
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(cluster.bulkLines[0][1]), &doc))
+	assert.Equal(t, "statistics", doc["record_type"])
+	assert.Equal(t, "2026-03-14T10:30:00Z", doc["@timestamp"])
+	assert.Equal(t, "APP.ORDERS", doc["queue_stats"].(map[string]interface{})["queue_name"])
+
+	require.NoError(t, json.Unmarshal([]byte(cluster.bulkLines[1][0]), &action))
+	assert.Equal(t, "ibmmq-accounting-2026.03.15", action["index"]["_index"])
+
+	for _, user := range cluster.auth {
+		assert.Equal(t, "collector", user)
+	}
+	assert.NoError(t, sink.Close())
+}
+
+func TestElasticsearchSinkRejectedDocuments(t *testing.T) {
+	cluster := &fakeCluster{reject: true}
+	server := httptest.NewServer(cluster)
+	defer server.Close()
+
+	sink := NewElasticsearchSink(testElasticsearchConfig(server.URL), logrus.New())
+
+	err := sink.Write(context.Background(), testBatch())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 documents rejected")
+	assert.Contains(t, err.Error(), "mapper_parsing_exception")
+}
+
+func TestElasticsearchSinkFailover(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	cluster := &fakeCluster{}
+	up := httptest.NewServer(cluster)
+	defer up.Close()
+
+	cfg := testElasticsearchConfig(down.URL, up.URL)
+	cfg.InstallTemplate = false
+	sink := NewElasticsearchSink(cfg, logrus.New())
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	assert.Len(t, cluster.bulkLines, 1)
+	assert.Empty(t, cluster.templates)
+	assert.Equal(t, 1, sink.current, "later requests go to the node that answered")
+}
+
+func TestNewFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig().Sinks
+
+	sinks, err := NewFromConfig(&cfg, logrus.New())
+	require.NoError(t, err)
+	assert.Empty(t, sinks)
+
+	cfg.Elasticsearch.Enabled = true
+	cfg.Elasticsearch.URLs = []string{"http://localhost:9200"}
+	sinks, err = NewFromConfig(&cfg, logrus.New())
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+	assert.Equal(t, "elasticsearch", sinks[0].Name())
+
+	cfg.StatsD.Enabled = true
+	sinks, err = NewFromConfig(&cfg, logrus.New())
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	assert.Equal(t, "statsd", sinks[1].Name())
+	assert.NoError(t, sinks[1].Close())
+}
@@ -0,0 +1,266 @@
+package sinks
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/export"
+	"github.com/sirupsen/logrus"
+)
+
+// Layout of the timestamp in rotated file names
+const rotatedTimeFormat = "20060102T150405"
+
+// FileSink appends every record as one JSON line, in the format of the export command,
+// to a local file. The file is rotated once it reaches max_size_mb or is older than
+// rotate_interval; rotated files get a timestamp suffix, are optionally gzipped and
+// only the newest max_files are kept.
+type FileSink struct {
+	config *config.FileConfig
+	logger *logrus.Logger
+	now    func() time.Time
+
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// NewFileSink creates a file sink, appending to the file if it already exists
+func NewFileSink(cfg *config.FileConfig, logger *logrus.Logger) (*FileSink, error) {
+	s := &FileSink{config: cfg, logger: logger, now: time.Now}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Name identifies the sink in logs
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+// Write appends the records of the batch and syncs the file to disk
+func (s *FileSink) Write(ctx context.Context, batch *Batch) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	// Reopen after an earlier failed rotation
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	if s.config.RotateInterval > 0 && s.size > 0 && s.now().Sub(s.opened) >= s.config.RotateInterval {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	var line bytes.Buffer
+	writer, err := export.NewWriter(export.FormatJSON, &line)
+	if err != nil {
+		return err
+	}
+
+	for _, stats := range batch.Statistics {
+		record := *stats
+		record.QueueManager = batch.queueManager(stats.QueueManager)
+		line.Reset()
+		if err := writer.WriteStatistics(&record); err != nil {
+			return fmt.Errorf("failed to encode statistics record: %w", err)
+		}
+		if err := s.writeLine(line.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	for _, acct := range batch.Accounting {
+		record := *acct
+		record.QueueManager = batch.queueManager(acct.QueueManager)
+		line.Reset()
+		if err := writer.WriteAccounting(&record); err != nil {
+			return fmt.Errorf("failed to encode accounting record: %w", err)
+		}
+		if err := s.writeLine(line.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", s.config.Path, err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":    s.Name(),
+		"records": batch.Len(),
+		"path":    s.config.Path,
+	}).Debug("Wrote records")
+
+	return nil
+}
+
+// Close syncs and closes the current file
+func (s *FileSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Sync()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	s.file = nil
+	return err
+}
+
+// writeLine appends one line, rotating first if it would exceed the size limit
+func (s *FileSink) writeLine(line []byte) error {
+	maxSize := int64(s.config.MaxSizeMB) * 1024 * 1024
+	if maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.config.Path, err)
+	}
+	return nil
+}
+
+// open opens the current file for appending
+func (s *FileSink) open() error {
+	if dir := filepath.Dir(s.config.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	file, err := os.OpenFile(s.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", s.config.Path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat %s: %w", s.config.Path, err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+	s.opened = s.now()
+	return nil
+}
+
+// rotate renames the current file with a timestamp suffix, compresses it if configured,
+// removes the oldest rotated files and starts a new file
+func (s *FileSink) rotate() error {
+	if err := s.Close(); err != nil {
+		return fmt.Errorf("failed to close %s for rotation: %w", s.config.Path, err)
+	}
+
+	rotated := s.rotatedName()
+	if err := os.Rename(s.config.Path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", s.config.Path, err)
+	}
+
+	if s.config.Compress {
+		if err := gzipFile(rotated); err != nil {
+			s.logger.WithError(err).WithField("path", rotated).Warn("Failed to compress rotated file, keeping it uncompressed")
+		} else {
+			rotated += ".gz"
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink": s.Name(),
+		"path": rotated,
+	}).Info("Rotated record file")
+
+	s.prune()
+	return s.open()
+}
+
+// rotatedName returns an unused name for the current file with a timestamp suffix
+func (s *FileSink) rotatedName() string {
+	ext := filepath.Ext(s.config.Path)
+	base := strings.TrimSuffix(s.config.Path, ext)
+	stamp := s.now().UTC().Format(rotatedTimeFormat)
+
+	name := fmt.Sprintf("%s-%s%s", base, stamp, ext)
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, stamp, i, ext)
+	}
+	return name
+}
+
+// prune removes the oldest rotated files beyond max_files
+func (s *FileSink) prune() {
+	if s.config.MaxFiles <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(s.config.Path)
+	base := strings.TrimSuffix(s.config.Path, ext)
+	matches, err := filepath.Glob(base + "-*" + ext + "*")
+	if err != nil {
+		return
+	}
+
+	// Timestamp suffixes sort chronologically
+	sort.Strings(matches)
+	for len(matches) > s.config.MaxFiles {
+		if err := os.Remove(matches[0]); err != nil {
+			s.logger.WithError(err).WithField("path", matches[0]).Warn("Failed to remove old record file")
+		}
+		matches = matches[1:]
+	}
+}
+
+// gzipFile compresses path to path.gz and removes the original
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	gz.Name = filepath.Base(path)
+	_, err = io.Copy(gz, in)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	in.Close()
+	return os.Remove(path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
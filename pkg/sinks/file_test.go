@@ -0,0 +1,136 @@
+package sinks
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readLines decodes every JSON line of a plain or gzipped file
+func readLines(t *testing.T, path string) []map[string]interface{} {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var scanner *bufio.Scanner
+	if filepath.Ext(path) == ".gz" {
+		gz, err := gzip.NewReader(file)
+		require.NoError(t, err)
+		scanner = bufio.NewScanner(gz)
+	} else {
+		scanner = bufio.NewScanner(file)
+	}
+
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func rotatedFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "records-*"))
+	require.NoError(t, err)
+	sort.Strings(matches)
+	return matches
+}
+
+func TestFileSinkAppendsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "records.jsonl")
+	cfg := &config.FileConfig{Path: path}
+
+	sink, err := NewFileSink(cfg, logrus.New())
+	require.NoError(t, err)
+	batch := testBatch()
+	batch.QueueManager = "QMDEFAULT"
+	batch.Statistics[0].QueueManager = ""
+	require.NoError(t, sink.Write(context.Background(), batch))
+	require.NoError(t, sink.Close())
+
+	// A restarted sink appends to the existing file
+	sink, err = NewFileSink(cfg, logrus.New())
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	require.NoError(t, sink.Close())
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 4)
+	assert.Equal(t, "statistics", lines[0]["type"])
+	assert.Equal(t, "QMDEFAULT", lines[0]["queue_manager"])
+	assert.Equal(t, "APP.ORDERS", lines[0]["queue_stats"].(map[string]interface{})["queue_name"])
+	assert.Equal(t, "accounting", lines[1]["type"])
+	assert.Equal(t, "QM1", lines[2]["queue_manager"])
+	assert.Empty(t, batch.Statistics[0].QueueManager, "the batch must not be modified")
+}
+
+func TestFileSinkRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.FileConfig{Path: filepath.Join(dir, "records.jsonl"), MaxSizeMB: 1, MaxFiles: 2, Compress: true}
+
+	sink, err := NewFileSink(cfg, logrus.New())
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+
+	// Write several megabytes, a minute apart so rotated names differ
+	batch := testBatch()
+	for i := 0; i < 12; i++ {
+		batch.Statistics = append(batch.Statistics, batch.Statistics...)
+	}
+	for i := 0; i < 4; i++ {
+		require.NoError(t, sink.Write(context.Background(), batch))
+		now = now.Add(time.Minute)
+	}
+	require.NoError(t, sink.Close())
+
+	rotated := rotatedFiles(t, dir)
+	require.Len(t, rotated, 2, "only max_files rotated files are kept")
+	for _, name := range rotated {
+		assert.Equal(t, ".gz", filepath.Ext(name))
+		lines := readLines(t, name)
+		assert.NotEmpty(t, lines)
+	}
+
+	info, err := os.Stat(cfg.Path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(1024*1024))
+}
+
+func TestFileSinkRotatesByAge(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.FileConfig{Path: filepath.Join(dir, "records.jsonl"), RotateInterval: time.Hour}
+
+	sink, err := NewFileSink(cfg, logrus.New())
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+	sink.opened = now
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	assert.Empty(t, rotatedFiles(t, dir))
+
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	require.NoError(t, sink.Close())
+
+	rotated := rotatedFiles(t, dir)
+	require.Equal(t, []string{filepath.Join(dir, "records-20260314T110000.jsonl")}, rotated)
+	assert.Len(t, readLines(t, rotated[0]), 4)
+	assert.Len(t, readLines(t, cfg.Path), 2)
+}
@@ -0,0 +1,197 @@
+package sinks
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth scope for writing Cloud Monitoring time series
+const gcpMonitoringScope = "https://www.googleapis.com/auth/monitoring.write"
+
+// Default address of the GCE metadata server
+const defaultGCPMetadataEndpoint = "http://metadata.google.internal"
+
+// gcpServiceAccount is the part of a service account key file needed to request tokens
+type gcpServiceAccount struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// gcpTokenProvider obtains access tokens with a service account key file or, without one,
+// from the metadata server of the GCE instance or GKE node. Tokens are cached until shortly
+// before they expire.
+type gcpTokenProvider struct {
+	account          *gcpServiceAccount
+	key              *rsa.PrivateKey
+	metadataEndpoint string
+	client           *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newGCPTokenProvider(credentialsFile string, client *http.Client) (*gcpTokenProvider, error) {
+	p := &gcpTokenProvider{metadataEndpoint: defaultGCPMetadataEndpoint, client: client}
+	if credentialsFile == "" {
+		return p, nil
+	}
+
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read google credentials file: %w", err)
+	}
+
+	var account gcpServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse google credentials file: %w", err)
+	}
+	if account.Type != "service_account" {
+		return nil, fmt.Errorf("google credentials file must contain a service account key, not %q", account.Type)
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("google credentials file has no PEM private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("service account private key is not an RSA key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	p.account = &account
+	p.key = key
+	return p, nil
+}
+
+// projectID returns the project of the service account, if one was loaded
+func (p *gcpTokenProvider) projectID() string {
+	if p.account != nil {
+		return p.account.ProjectID
+	}
+	return ""
+}
+
+// get returns a valid access token
+func (p *gcpTokenProvider) get(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Until(p.expires) > 5*time.Minute {
+		return p.token, nil
+	}
+
+	var body string
+	var err error
+	if p.account != nil {
+		body, err = p.serviceAccountToken(ctx)
+	} else {
+		body, err = p.metadata(ctx, "instance/service-accounts/default/token?scopes="+url.QueryEscape(gcpMonitoringScope))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get google access token: %w", err)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal([]byte(body), &token); err != nil {
+		return "", fmt.Errorf("failed to parse google token response: %w", err)
+	}
+
+	p.token = token.AccessToken
+	p.expires = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return p.token, nil
+}
+
+// serviceAccountToken exchanges a signed JWT assertion for an access token
+func (p *gcpTokenProvider) serviceAccountToken(ctx context.Context) (string, error) {
+	assertion, err := p.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return p.do(req)
+}
+
+// assertion builds the RS256 JWT that authenticates the service account
+func (p *gcpTokenProvider) assertion(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   p.account.ClientEmail,
+		"scope": gcpMonitoringScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token assertion: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// metadata reads a value from the metadata server, e.g. project/project-id
+func (p *gcpTokenProvider) metadata(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadataEndpoint+"/computeMetadata/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	return p.do(req)
+}
+
+func (p *gcpTokenProvider) do(req *http.Request) (string, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return string(body), nil
+}
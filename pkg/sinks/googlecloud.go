@@ -0,0 +1,355 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Default Cloud Monitoring API endpoint
+const defaultGCPMonitoringEndpoint = "https://monitoring.googleapis.com"
+
+// gcpResourceLabels lists the labels each supported monitored resource type requires,
+// besides project_id
+var gcpResourceLabels = map[string][]string{
+	"global":        nil,
+	"generic_node":  {"location", "namespace", "node_id"},
+	"gce_instance":  {"instance_id", "zone"},
+	"k8s_container": {"location", "cluster_name", "namespace_name", "pod_name", "container_name"},
+}
+
+// GoogleCloudSink writes samples as Cloud Monitoring custom metrics. Metric names map to
+// metric types below the configured prefix (queue.depth becomes
+// custom.googleapis.com/ibmmq/queue/depth) and labels are sanitized to the rules Cloud
+// Monitoring enforces.
+type GoogleCloudSink struct {
+	config *config.GoogleCloudConfig
+	client *http.Client
+	tokens *gcpTokenProvider
+	logger *logrus.Logger
+
+	// Overrides the API endpoint in tests
+	endpoint string
+
+	mu       sync.Mutex
+	project  string
+	resource map[string]string // resolved on first write
+}
+
+// gcpTimeSeries is one entry of a timeSeries.create request
+type gcpTimeSeries struct {
+	Metric struct {
+		Type   string            `json:"type"`
+		Labels map[string]string `json:"labels,omitempty"`
+	} `json:"metric"`
+	Resource struct {
+		Type   string            `json:"type"`
+		Labels map[string]string `json:"labels"`
+	} `json:"resource"`
+	MetricKind string     `json:"metricKind"`
+	ValueType  string     `json:"valueType"`
+	Points     []gcpPoint `json:"points"`
+}
+
+// gcpPoint is a single double value at the end of its interval
+type gcpPoint struct {
+	Interval struct {
+		EndTime string `json:"endTime"`
+	} `json:"interval"`
+	Value struct {
+		DoubleValue float64 `json:"doubleValue"`
+	} `json:"value"`
+}
+
+// NewGoogleCloudSink creates a Google Cloud Monitoring sink
+func NewGoogleCloudSink(cfg *config.GoogleCloudConfig, logger *logrus.Logger) (*GoogleCloudSink, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+	tokens, err := newGCPTokenProvider(cfg.CredentialsFile, client)
+	if err != nil {
+		return nil, err
+	}
+	return &GoogleCloudSink{
+		config: cfg,
+		client: client,
+		tokens: tokens,
+		logger: logger,
+	}, nil
+}
+
+// Name identifies the sink in logs
+func (s *GoogleCloudSink) Name() string {
+	return "google_cloud"
+}
+
+// Write publishes the samples of the batch, up to batch_size time series per request
+func (s *GoogleCloudSink) Write(ctx context.Context, batch *Batch) error {
+	data := samples(batch)
+	if len(data) == 0 {
+		return nil
+	}
+
+	project, resource, err := s.resolveResource(ctx)
+	if err != nil {
+		return err
+	}
+
+	series := s.timeSeries(data, resource, time.Now())
+	for start := 0; start < len(series); start += s.config.BatchSize {
+		end := start + s.config.BatchSize
+		if end > len(series) {
+			end = len(series)
+		}
+		if err := s.create(ctx, project, series[start:end]); err != nil {
+			return err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":        s.Name(),
+		"time_series": len(series),
+	}).Debug("Published metrics")
+
+	return nil
+}
+
+// Close releases idle connections
+func (s *GoogleCloudSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// timeSeries converts samples into time series. A series may only receive one point per
+// request, so repeated samples are merged: counters are summed and gauges keep the latest value.
+func (s *GoogleCloudSink) timeSeries(data []sample, resource map[string]string, now time.Time) []*gcpTimeSeries {
+	var out []*gcpTimeSeries
+	byKey := make(map[string]*gcpTimeSeries)
+	latest := make(map[string]time.Time)
+
+	for _, smp := range data {
+		ts := smp.timestamp
+		if ts.IsZero() {
+			ts = now
+		}
+
+		labels := make(map[string]string)
+		for name, value := range smp.labels {
+			if value == "" {
+				continue
+			}
+			labels[gcpLabelKey(name)] = gcpLabelValue(value)
+		}
+
+		metricType := s.config.MetricPrefix + "/" + strings.ReplaceAll(smp.name, ".", "/")
+		key := metricType + "|" + gcpLabelString(labels)
+
+		if existing := byKey[key]; existing != nil {
+			point := &existing.Points[0]
+			if smp.kind == kindCounter {
+				point.Value.DoubleValue += smp.value
+			} else if !ts.Before(latest[key]) {
+				point.Value.DoubleValue = smp.value
+			}
+			if ts.After(latest[key]) {
+				latest[key] = ts
+				point.Interval.EndTime = ts.UTC().Format(time.RFC3339)
+			}
+			continue
+		}
+
+		series := &gcpTimeSeries{MetricKind: "GAUGE", ValueType: "DOUBLE"}
+		series.Metric.Type = metricType
+		if len(labels) > 0 {
+			series.Metric.Labels = labels
+		}
+		series.Resource.Type = s.config.ResourceType
+		series.Resource.Labels = s.resourceLabels(resource, smp)
+
+		var point gcpPoint
+		point.Interval.EndTime = ts.UTC().Format(time.RFC3339)
+		point.Value.DoubleValue = smp.value
+		series.Points = []gcpPoint{point}
+
+		byKey[key] = series
+		latest[key] = ts
+		out = append(out, series)
+	}
+
+	return out
+}
+
+// resourceLabels returns the monitored resource labels for a sample. A generic_node without
+// a configured node_id is identified by the queue manager.
+func (s *GoogleCloudSink) resourceLabels(resource map[string]string, smp sample) map[string]string {
+	if s.config.ResourceType != "generic_node" || resource["node_id"] != "" {
+		return resource
+	}
+	labels := make(map[string]string, len(resource)+1)
+	for k, v := range resource {
+		labels[k] = v
+	}
+	labels["node_id"] = gcpLabelValue(smp.labels["qmgr"])
+	return labels
+}
+
+// resolveResource determines the project and the monitored resource labels once, filling
+// labels that are not configured from defaults and the metadata server
+func (s *GoogleCloudSink) resolveResource(ctx context.Context) (string, map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resource != nil {
+		return s.project, s.resource, nil
+	}
+
+	project := s.config.ProjectID
+	if project == "" {
+		project = s.tokens.projectID()
+	}
+	if project == "" {
+		id, err := s.tokens.metadata(ctx, "project/project-id")
+		if err != nil {
+			return "", nil, fmt.Errorf("google cloud sink has no project_id and the metadata server is unavailable: %w", err)
+		}
+		project = strings.TrimSpace(id)
+	}
+
+	resource := map[string]string{"project_id": project}
+	for k, v := range s.config.ResourceLabels {
+		resource[k] = v
+	}
+
+	defaults := map[string]string{}
+	switch s.config.ResourceType {
+	case "generic_node":
+		defaults["location"] = "global"
+		defaults["namespace"] = "ibmmq"
+	case "gce_instance":
+		defaults["instance_id"] = "instance/id"
+		defaults["zone"] = "instance/zone"
+	case "k8s_container":
+		defaults["location"] = "instance/attributes/cluster-location"
+		defaults["cluster_name"] = "instance/attributes/cluster-name"
+	}
+
+	for _, label := range gcpResourceLabels[s.config.ResourceType] {
+		if resource[label] != "" {
+			continue
+		}
+		value := defaults[label]
+		if strings.HasPrefix(value, "instance/") {
+			fetched, err := s.tokens.metadata(ctx, value)
+			if err != nil {
+				return "", nil, fmt.Errorf("resource label %s is not configured and could not be read from the metadata server: %w", label, err)
+			}
+			// Zones are returned as projects/<number>/zones/<zone>
+			value = path.Base(strings.TrimSpace(fetched))
+		}
+		if value == "" && !(s.config.ResourceType == "generic_node" && label == "node_id") {
+			return "", nil, fmt.Errorf("resource type %s requires resource label %s", s.config.ResourceType, label)
+		}
+		if value != "" {
+			resource[label] = value
+		}
+	}
+
+	s.project = project
+	s.resource = resource
+	return project, resource, nil
+}
+
+// create sends one timeSeries.create request
+func (s *GoogleCloudSink) create(ctx context.Context, project string, series []*gcpTimeSeries) error {
+	body, err := json.Marshal(map[string]interface{}{"timeSeries": series})
+	if err != nil {
+		return fmt.Errorf("failed to encode time series: %w", err)
+	}
+
+	token, err := s.tokens.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = defaultGCPMonitoringEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/v3/projects/%s/timeSeries", endpoint, project), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("timeSeries.create request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("timeSeries.create returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// gcpLabelKey converts a label name to the [a-z][a-z0-9_]* form Cloud Monitoring accepts
+func gcpLabelKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	key := b.String()
+	if key == "" || key[0] < 'a' || key[0] > 'z' {
+		key = "l_" + key
+	}
+	if len(key) > 100 {
+		key = key[:100]
+	}
+	return key
+}
+
+// gcpLabelValue trims a label value to the 1024 byte limit without splitting a character
+func gcpLabelValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) <= 1024 {
+		return value
+	}
+	cut := 1024
+	for cut > 0 && value[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	return value[:cut]
+}
+
+// gcpLabelString returns a stable representation of a label set
+func gcpLabelString(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+labels[k])
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,199 @@
+package sinks
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGCPMetadata serves the metadata server paths the sink reads
+func fakeGCPMetadata(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/computeMetadata/v1/project/project-id":
+			w.Write([]byte("mq-project"))
+		case "/computeMetadata/v1/instance/id":
+			w.Write([]byte("4520031799277581759"))
+		case "/computeMetadata/v1/instance/zone":
+			w.Write([]byte("projects/123456/zones/europe-west1-b"))
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			assert.Equal(t, gcpMonitoringScope, r.URL.Query().Get("scopes"))
+			w.Write([]byte(`{"access_token":"metadata-token","expires_in":3600}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGoogleCloudSinkWrite(t *testing.T) {
+	metadata := fakeGCPMetadata(t)
+	defer metadata.Close()
+
+	var requests []map[string][]gcpTimeSeries
+	var paths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer metadata-token", r.Header.Get("Authorization"))
+		var body map[string][]gcpTimeSeries
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{}`))
+	}))
+	defer api.Close()
+
+	cfg := config.DefaultConfig().Sinks.GoogleCloud
+	cfg.Enabled = true
+	cfg.BatchSize = 4
+	sink, err := NewGoogleCloudSink(&cfg, logrus.New())
+	require.NoError(t, err)
+	sink.tokens.metadataEndpoint = metadata.URL
+	sink.endpoint = api.URL
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	require.Len(t, requests, 2, "six queue metrics in batches of four")
+	assert.Equal(t, "/v3/projects/mq-project/timeSeries", paths[0])
+
+	series := requests[0]["timeSeries"]
+	require.Len(t, series, 4)
+	assert.Equal(t, "custom.googleapis.com/ibmmq/queue/depth", series[0].Metric.Type)
+	assert.Equal(t, map[string]string{"qmgr": "QM1", "queue": "APP.ORDERS"}, series[0].Metric.Labels)
+	assert.Equal(t, "generic_node", series[0].Resource.Type)
+	assert.Equal(t, map[string]string{
+		"project_id": "mq-project",
+		"location":   "global",
+		"namespace":  "ibmmq",
+		"node_id":    "QM1",
+	}, series[0].Resource.Labels)
+	assert.Equal(t, "GAUGE", series[0].MetricKind)
+	require.Len(t, series[0].Points, 1)
+	assert.Equal(t, 4.0, series[0].Points[0].Value.DoubleValue)
+	assert.Equal(t, "2026-03-14T10:30:00Z", series[0].Points[0].Interval.EndTime)
+}
+
+func TestGoogleCloudResourceFromMetadata(t *testing.T) {
+	metadata := fakeGCPMetadata(t)
+	defer metadata.Close()
+
+	cfg := config.DefaultConfig().Sinks.GoogleCloud
+	cfg.ProjectID = "configured-project"
+	cfg.ResourceType = "gce_instance"
+	sink, err := NewGoogleCloudSink(&cfg, logrus.New())
+	require.NoError(t, err)
+	sink.tokens.metadataEndpoint = metadata.URL
+
+	project, resource, err := sink.resolveResource(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "configured-project", project)
+	assert.Equal(t, map[string]string{
+		"project_id":  "configured-project",
+		"instance_id": "4520031799277581759",
+		"zone":        "europe-west1-b",
+	}, resource)
+
+	cfg.ResourceType = "k8s_container"
+	sink, err = NewGoogleCloudSink(&cfg, logrus.New())
+	require.NoError(t, err)
+	sink.tokens.metadataEndpoint = metadata.URL
+	_, _, err = sink.resolveResource(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "location")
+}
+
+func TestGoogleCloudTimeSeriesMergesSamples(t *testing.T) {
+	cfg := config.DefaultConfig().Sinks.GoogleCloud
+	sink, err := NewGoogleCloudSink(&cfg, logrus.New())
+	require.NoError(t, err)
+
+	now := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)
+	labels := map[string]string{"qmgr": "QM1", "queue": "APP.ORDERS"}
+	series := sink.timeSeries([]sample{
+		{name: "queue.enqueued", value: 5, kind: kindCounter, labels: labels, timestamp: now.Add(-time.Minute)},
+		{name: "queue.enqueued", value: 7, kind: kindCounter, labels: labels, timestamp: now},
+		{name: "queue.depth", value: 9, kind: kindGauge, labels: labels, timestamp: now},
+		{name: "queue.depth", value: 3, kind: kindGauge, labels: labels, timestamp: now.Add(-time.Minute)},
+	}, map[string]string{"project_id": "p", "node_id": "n"}, now)
+
+	require.Len(t, series, 2)
+	assert.Equal(t, 12.0, series[0].Points[0].Value.DoubleValue, "counters are summed")
+	assert.Equal(t, "2026-03-14T10:30:00Z", series[0].Points[0].Interval.EndTime)
+	assert.Equal(t, 9.0, series[1].Points[0].Value.DoubleValue, "gauges keep the latest value")
+}
+
+func TestGCPLabelSanitization(t *testing.T) {
+	assert.Equal(t, "queue", gcpLabelKey("queue"))
+	assert.Equal(t, "queue_manager", gcpLabelKey("Queue-Manager"))
+	assert.Equal(t, "l_1st", gcpLabelKey("1st"))
+	assert.Len(t, gcpLabelKey(strings.Repeat("a", 150)), 100)
+
+	assert.Equal(t, "APP.ORDERS", gcpLabelValue(" APP.ORDERS "))
+	long := strings.Repeat("a", 1023) + "é"
+	assert.Equal(t, strings.Repeat("a", 1023), gcpLabelValue(long), "multi-byte characters are not split")
+}
+
+func TestGCPServiceAccountToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	token := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.Contains(t, string(claims), `"iss":"collector@mq-project.iam.gserviceaccount.com"`)
+		assert.Contains(t, string(claims), gcpMonitoringScope)
+
+		w.Write([]byte(`{"access_token":"sa-token","expires_in":3600}`))
+	}))
+	defer token.Close()
+
+	account, err := json.Marshal(gcpServiceAccount{
+		Type:        "service_account",
+		ProjectID:   "mq-project",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ClientEmail: "collector@mq-project.iam.gserviceaccount.com",
+		TokenURI:    token.URL,
+	})
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(file, account, 0600))
+
+	provider, err := newGCPTokenProvider(file, token.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "mq-project", provider.projectID())
+
+	accessToken, err := provider.get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "sa-token", accessToken)
+}
@@ -0,0 +1,155 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Placeholders in a Graphite path template, e.g. {qmgr} or {metric}
+var templatePlaceholder = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// GraphiteSink sends samples over TCP in the Graphite plaintext protocol
+// ("<path> <value> <timestamp>"), naming them with a configurable path template.
+//
+// Besides the sample labels (qmgr, queue, channel, conname, application) a template can
+// use {group} (queue, channel, mqi or accounting), {metric} (e.g. depth), {object} (the
+// queue, channel or application name) and {name} ({group}.{metric}). Samples missing a
+// label used by the template are skipped, so "mq.{qmgr}.{queue}.{metric}" sends only
+// queue metrics.
+type GraphiteSink struct {
+	config *config.GraphiteConfig
+	logger *logrus.Logger
+	conn   net.Conn
+}
+
+// NewGraphiteSink creates a Graphite sink; the connection is opened on the first write
+func NewGraphiteSink(cfg *config.GraphiteConfig, logger *logrus.Logger) *GraphiteSink {
+	return &GraphiteSink{config: cfg, logger: logger}
+}
+
+// Name identifies the sink in logs
+func (s *GraphiteSink) Name() string {
+	return "graphite"
+}
+
+// Write sends the samples of the batch in chunks of batch_size lines
+func (s *GraphiteSink) Write(ctx context.Context, batch *Batch) error {
+	now := time.Now()
+	var lines []string
+	skipped := 0
+	for _, smp := range samples(batch) {
+		path, ok := s.path(smp)
+		if !ok {
+			skipped++
+			continue
+		}
+		ts := smp.timestamp
+		if ts.IsZero() {
+			ts = now
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %d\n", path, strconv.FormatFloat(smp.value, 'f', -1, 64), ts.Unix()))
+	}
+
+	for start := 0; start < len(lines); start += s.config.BatchSize {
+		end := start + s.config.BatchSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if err := s.send(ctx, []byte(strings.Join(lines[start:end], ""))); err != nil {
+			return err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":    s.Name(),
+		"metrics": len(lines),
+		"skipped": skipped,
+	}).Debug("Sent metrics")
+
+	return nil
+}
+
+// Close closes the connection
+func (s *GraphiteSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// send writes a chunk, reconnecting once if the existing connection was dropped
+func (s *GraphiteSink) send(ctx context.Context, data []byte) error {
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			dialer := net.Dialer{Timeout: s.config.Timeout}
+			conn, err := dialer.DialContext(ctx, "tcp", s.config.Address)
+			if err != nil {
+				return fmt.Errorf("failed to connect to graphite at %s: %w", s.config.Address, err)
+			}
+			s.conn = conn
+		}
+
+		s.conn.SetWriteDeadline(time.Now().Add(s.config.Timeout))
+		if _, err := bytes.NewReader(data).WriteTo(s.conn); err != nil {
+			lastErr = err
+			s.Close()
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to send metrics to graphite: %w", lastErr)
+}
+
+// path expands the path template for a sample, reporting false if a placeholder has no value
+func (s *GraphiteSink) path(smp sample) (string, bool) {
+	group, metric := smp.name, ""
+	if i := strings.Index(smp.name, "."); i >= 0 {
+		group, metric = smp.name[:i], smp.name[i+1:]
+	}
+
+	ok := true
+	path := templatePlaceholder.ReplaceAllStringFunc(s.config.PathTemplate, func(placeholder string) string {
+		var value string
+		switch key := placeholder[1 : len(placeholder)-1]; key {
+		case "group":
+			value = group
+		case "metric":
+			value = metric
+		case "name":
+			return smp.name
+		case "object":
+			value = smp.labels["queue"] + smp.labels["channel"] + smp.labels["application"]
+		default:
+			value = smp.labels[key]
+		}
+		if value == "" {
+			ok = false
+		}
+		return graphiteSegment(value)
+	})
+	return path, ok
+}
+
+// graphiteSegment replaces the characters that would split a path node or a line
+func graphiteSegment(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ' ', '\t', '\n', '/', '(', ')':
+			return '_'
+		default:
+			return r
+		}
+	}, value)
+}
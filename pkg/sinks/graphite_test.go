@@ -0,0 +1,137 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphiteServer accepts plaintext connections and records the lines and connections received
+type graphiteServer struct {
+	listener    net.Listener
+	mu          sync.Mutex
+	lines       []string
+	connections int
+}
+
+func newGraphiteServer(t *testing.T) *graphiteServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &graphiteServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.mu.Lock()
+			server.connections++
+			server.mu.Unlock()
+
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					server.mu.Lock()
+					server.lines = append(server.lines, scanner.Text())
+					server.mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return server
+}
+
+// received waits until n lines have arrived and returns them
+func (g *graphiteServer) received(t *testing.T, n int) []string {
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return len(g.lines) >= n
+	}, 2*time.Second, 10*time.Millisecond)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.lines...)
+}
+
+func testGraphiteConfig(address string) *config.GraphiteConfig {
+	cfg := config.DefaultConfig().Sinks.Graphite
+	cfg.Enabled = true
+	cfg.Address = address
+	return &cfg
+}
+
+func TestGraphiteSinkDefaultTemplate(t *testing.T) {
+	server := newGraphiteServer(t)
+
+	cfg := testGraphiteConfig(server.listener.Addr().String())
+	cfg.BatchSize = 2
+	sink := NewGraphiteSink(cfg, logrus.New())
+	defer sink.Close()
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+
+	lines := server.received(t, 6)
+	ts := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC).Unix()
+	assert.Contains(t, lines, "ibmmq.QM1.queue.APP_ORDERS.depth 4 "+strconv.FormatInt(ts, 10))
+	assert.Contains(t, lines, "ibmmq.QM1.queue.APP_ORDERS.enqueued 0 "+strconv.FormatInt(ts, 10))
+	assert.Equal(t, 1, server.connections, "batches share one connection")
+}
+
+func TestGraphiteSinkTemplateSelectsSamples(t *testing.T) {
+	server := newGraphiteServer(t)
+
+	cfg := testGraphiteConfig(server.listener.Addr().String())
+	cfg.PathTemplate = "mq.{qmgr}.{queue}.depth"
+	sink := NewGraphiteSink(cfg, logrus.New())
+	defer sink.Close()
+
+	smp := sample{name: "channel.messages", labels: map[string]string{"qmgr": "QM1", "channel": "TO.QM2"}}
+	_, ok := sink.path(smp)
+	assert.False(t, ok, "channel samples have no queue label")
+
+	smp = sample{name: "queue.depth", labels: map[string]string{"qmgr": "QM1", "queue": "APP.ORDERS"}}
+	path, ok := sink.path(smp)
+	assert.True(t, ok)
+	assert.Equal(t, "mq.QM1.APP_ORDERS.depth", path)
+}
+
+func TestGraphiteSinkReconnects(t *testing.T) {
+	server := newGraphiteServer(t)
+
+	sink := NewGraphiteSink(testGraphiteConfig(server.listener.Addr().String()), logrus.New())
+	defer sink.Close()
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	server.received(t, 6)
+
+	// A closed connection is replaced on the next write
+	sink.conn.Close()
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	server.received(t, 12)
+	assert.Equal(t, 2, server.connections)
+}
+
+func TestGraphiteSinkUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	sink := NewGraphiteSink(testGraphiteConfig(address), logrus.New())
+	err = sink.Write(context.Background(), testBatch())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to graphite")
+}
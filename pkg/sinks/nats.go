@@ -0,0 +1,656 @@
+package sinks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// JetStream error code returned when creating a stream that already exists
+const natsStreamNameInUse = 10058
+
+// NATSSink publishes every record as JSON to a NATS subject named after the record
+// type and queue manager, <prefix>.<statistics|accounting>.<qmgr>. With jetstream
+// enabled each publish waits for the stream's acknowledgement, so records are only
+// reported delivered once persisted. The client speaks the NATS text protocol
+// directly over one long-lived connection.
+type NATSSink struct {
+	config    *config.NATSConfig
+	logger    *logrus.Logger
+	tlsConfig *tls.Config
+	conn      *natsConn
+}
+
+// natsConn is a connection to a NATS server. A background reader answers server
+// pings and hands pongs, errors and inbox replies to the publisher. It never waits for
+// the publisher, so pings are answered while a large batch is still being written.
+type natsConn struct {
+	conn    net.Conn
+	inbox   string
+	batches int // batches published, numbering the reply subjects of each
+
+	mu     sync.Mutex // guards writer
+	writer *bufio.Writer
+
+	repliesMu sync.Mutex // guards replies
+	replies   []natsReply
+	replied   chan struct{} // signalled when replies are added
+
+	pongs chan struct{}
+	errs  chan error
+	done  chan struct{}
+	err   error // read error, set before done is closed
+}
+
+// natsReply is a message received on the connection's inbox
+type natsReply struct {
+	subject string
+	status  string // status code of a header-only reply, e.g. 503 for no responders
+	data    []byte
+}
+
+// jetStreamAck is the reply to a JetStream publish or API request
+type jetStreamAck struct {
+	Stream string          `json:"stream"`
+	Seq    uint64          `json:"seq"`
+	Error  *jetStreamError `json:"error"`
+}
+
+// jetStreamError describes a JetStream request that failed
+type jetStreamError struct {
+	Code        int    `json:"code"`
+	ErrCode     int    `json:"err_code"`
+	Description string `json:"description"`
+}
+
+// natsRejection is an error reported by the server for requests on a working
+// connection, such as a permissions violation or a record JetStream did not store
+type natsRejection struct {
+	message string
+}
+
+func (e *natsRejection) Error() string {
+	return e.message
+}
+
+// NewNATSSink creates a NATS sink; the connection is opened on the first write
+func NewNATSSink(cfg *config.NATSConfig, logger *logrus.Logger) (*NATSSink, error) {
+	s := &NATSSink{config: cfg, logger: logger}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL %s: %w", cfg.URL, err)
+	}
+	if u.Scheme == "tls" || cfg.CAFile != "" {
+		s.tlsConfig = &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: cfg.InsecureSkipVerify}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read NATS CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in NATS CA file %s", cfg.CAFile)
+			}
+			s.tlsConfig.RootCAs = pool
+		}
+	}
+
+	return s, nil
+}
+
+// Name identifies the sink in logs
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+// Write publishes the records of the batch, reconnecting once if the connection was lost
+func (s *NATSSink) Write(ctx context.Context, batch *Batch) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	messages, err := s.messages(batch)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if err := s.connect(ctx); err != nil {
+				return err
+			}
+		}
+		if lastErr = s.publish(ctx, messages); lastErr == nil {
+			break
+		}
+		// A rejection leaves the connection usable; anything else may have left it
+		// with unanswered pings or replies, so start over on a new one
+		var rejection *natsRejection
+		if errors.As(lastErr, &rejection) {
+			return lastErr
+		}
+		s.Close()
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":      s.Name(),
+		"messages":  len(messages),
+		"jetstream": s.config.JetStream,
+	}).Debug("Published records")
+
+	return nil
+}
+
+// Close closes the connection
+func (s *NATSSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// natsMessage is one record ready to publish
+type natsMessage struct {
+	subject string
+	data    []byte
+}
+
+// messages encodes the records of the batch with their subjects
+func (s *NATSSink) messages(batch *Batch) ([]natsMessage, error) {
+	messages := make([]natsMessage, 0, batch.Len())
+
+	for _, stats := range batch.Statistics {
+		record := *stats
+		record.QueueManager = batch.queueManager(stats.QueueManager)
+		data, err := json.Marshal(&record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode statistics record: %w", err)
+		}
+		messages = append(messages, natsMessage{subject: s.subject("statistics", record.QueueManager), data: data})
+	}
+
+	for _, acct := range batch.Accounting {
+		record := *acct
+		record.QueueManager = batch.queueManager(acct.QueueManager)
+		data, err := json.Marshal(&record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode accounting record: %w", err)
+		}
+		messages = append(messages, natsMessage{subject: s.subject("accounting", record.QueueManager), data: data})
+	}
+
+	return messages, nil
+}
+
+// subject returns the subject of a record type and queue manager
+func (s *NATSSink) subject(recordType, qmgr string) string {
+	return fmt.Sprintf("%s.%s.%s", s.config.SubjectPrefix, recordType, natsToken(qmgr))
+}
+
+// connect dials the server, authenticates and, for JetStream, subscribes to the reply
+// inbox and creates the configured stream
+func (s *NATSSink) connect(ctx context.Context) error {
+	u, _ := url.Parse(s.config.URL)
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+	raw, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS at %s: %w", address, err)
+	}
+
+	conn, err := s.handshake(raw, u)
+	if err != nil {
+		raw.Close()
+		return fmt.Errorf("NATS handshake with %s failed: %w", address, err)
+	}
+	s.conn = conn
+
+	if err := s.conn.flush(ctx, s.config.Timeout); err != nil {
+		s.Close()
+		return fmt.Errorf("NATS server %s rejected the connection: %w", address, err)
+	}
+
+	if s.config.JetStream && s.config.Stream != "" {
+		if err := s.createStream(ctx); err != nil {
+			s.Close()
+			return err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":    s.Name(),
+		"address": address,
+	}).Info("Connected to NATS")
+
+	return nil
+}
+
+// handshake reads the server INFO, upgrades to TLS if required and sends CONNECT
+func (s *NATSSink) handshake(raw net.Conn, u *url.URL) (*natsConn, error) {
+	raw.SetDeadline(time.Now().Add(s.config.Timeout))
+	reader := bufio.NewReader(raw)
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return nil, fmt.Errorf("unexpected greeting %q", strings.TrimSpace(line))
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	if err := json.Unmarshal([]byte(line[5:]), &info); err != nil {
+		return nil, fmt.Errorf("invalid INFO: %w", err)
+	}
+
+	conn := raw
+	if info.TLSRequired || s.tlsConfig != nil {
+		tlsConfig := s.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: u.Hostname()}
+		}
+		tlsConn := tls.Client(raw, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		conn = tlsConn
+		reader = bufio.NewReader(conn)
+	}
+	raw.SetDeadline(time.Time{})
+
+	options := map[string]interface{}{
+		"verbose":       false,
+		"pedantic":      false,
+		"lang":          "go",
+		"version":       "1.0",
+		"protocol":      1,
+		"name":          "ibmmq-collector",
+		"headers":       true,
+		"no_responders": true,
+	}
+	if s.config.Username != "" {
+		options["user"] = s.config.Username
+		options["pass"] = s.config.Password
+	}
+	if s.config.Token != "" {
+		options["auth_token"] = s.config.Token
+	}
+	if u.User != nil && s.config.Username == "" {
+		options["user"] = u.User.Username()
+		options["pass"], _ = u.User.Password()
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	nc := &natsConn{
+		conn:    conn,
+		inbox:   "_INBOX." + hex.EncodeToString(id),
+		writer:  bufio.NewWriter(conn),
+		replied: make(chan struct{}, 1),
+		pongs:   make(chan struct{}, 16),
+		errs:    make(chan error, 16),
+		done:    make(chan struct{}),
+	}
+	fmt.Fprintf(nc.writer, "CONNECT %s\r\n", connect)
+	if s.config.JetStream {
+		fmt.Fprintf(nc.writer, "SUB %s.> 1\r\n", nc.inbox)
+	}
+	if err := nc.writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	go nc.read(reader)
+	return nc, nil
+}
+
+// createStream creates the configured stream over the record subjects, accepting an
+// existing stream of the same name
+func (s *NATSSink) createStream(ctx context.Context) error {
+	request, _ := json.Marshal(map[string]interface{}{
+		"name":     s.config.Stream,
+		"subjects": []string{s.config.SubjectPrefix + ".>"},
+	})
+
+	prefix := s.conn.inbox + ".stream."
+	if err := s.conn.publish("$JS.API.STREAM.CREATE."+s.config.Stream, prefix+"0", request); err != nil {
+		return err
+	}
+	if err := s.conn.flushWriter(); err != nil {
+		return err
+	}
+
+	acks, err := s.conn.awaitAcks(ctx, s.config.Timeout, prefix, 1)
+	if err != nil {
+		return fmt.Errorf("failed to create JetStream stream %s: %w", s.config.Stream, err)
+	}
+	if ack := acks[0]; ack.Error != nil && ack.Error.ErrCode != natsStreamNameInUse {
+		return fmt.Errorf("failed to create JetStream stream %s: %s", s.config.Stream, ack.Error.Description)
+	}
+	return nil
+}
+
+// publish sends the messages and waits until the server has processed them: a PING
+// round trip for core NATS, or one acknowledgement per message for JetStream. Each
+// message of a JetStream batch replies to <inbox>.<batch>.<index>, so the late
+// acknowledgements of an earlier batch are told apart.
+func (s *NATSSink) publish(ctx context.Context, messages []natsMessage) error {
+	s.conn.discard()
+	s.conn.batches++
+	prefix := fmt.Sprintf("%s.%d.", s.conn.inbox, s.conn.batches)
+
+	for i, msg := range messages {
+		reply := ""
+		if s.config.JetStream {
+			reply = prefix + strconv.Itoa(i)
+		}
+		if err := s.conn.publish(msg.subject, reply, msg.data); err != nil {
+			return fmt.Errorf("failed to publish to NATS: %w", err)
+		}
+	}
+
+	if !s.config.JetStream {
+		if err := s.conn.flush(ctx, s.config.Timeout); err != nil {
+			return fmt.Errorf("failed to publish to NATS: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.conn.flushWriter(); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+
+	acks, err := s.conn.awaitAcks(ctx, s.config.Timeout, prefix, len(messages))
+	if err != nil {
+		return fmt.Errorf("JetStream acknowledgement not received: %w", err)
+	}
+	failed := 0
+	reason := ""
+	for _, ack := range acks {
+		if ack.Error != nil {
+			failed++
+			if reason == "" {
+				reason = ack.Error.Description
+			}
+		}
+	}
+	if failed > 0 {
+		return &natsRejection{fmt.Sprintf("%d of %d records not stored by JetStream: %s", failed, len(messages), reason)}
+	}
+	return nil
+}
+
+// publish writes one PUB to the buffer
+func (c *natsConn) publish(subject, reply string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(30 * time.Second))
+	if reply != "" {
+		fmt.Fprintf(c.writer, "PUB %s %s %d\r\n", subject, reply, len(data))
+	} else {
+		fmt.Fprintf(c.writer, "PUB %s %d\r\n", subject, len(data))
+	}
+	c.writer.Write(data)
+	_, err := c.writer.WriteString("\r\n")
+	return err
+}
+
+func (c *natsConn) flushWriter() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writer.Flush()
+}
+
+// flush sends a PING and waits for the PONG, returning any error the server reported
+// for the commands sent before it
+func (c *natsConn) flush(ctx context.Context, timeout time.Duration) error {
+	c.mu.Lock()
+	c.writer.WriteString("PING\r\n")
+	err := c.writer.Flush()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case err := <-c.errs:
+			return err
+		case <-c.pongs:
+			// An error sent just before the PONG is already queued
+			select {
+			case err := <-c.errs:
+				return err
+			default:
+				return nil
+			}
+		case <-c.done:
+			return c.err
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return fmt.Errorf("no PONG within %s", timeout)
+		}
+	}
+}
+
+// awaitAcks waits for the JetStream replies to n requests, whose reply subjects are
+// prefix followed by their index, and returns them by index. Replies to other subjects,
+// such as those of an earlier batch that timed out, are dropped. Each reply is waited
+// for up to timeout.
+func (c *natsConn) awaitAcks(ctx context.Context, timeout time.Duration, prefix string, n int) ([]*jetStreamAck, error) {
+	acks := make([]*jetStreamAck, n)
+	received := 0
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		progress := false
+		for _, reply := range c.takeReplies() {
+			index, ok := strings.CutPrefix(reply.subject, prefix)
+			if !ok {
+				continue
+			}
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i >= n || acks[i] != nil {
+				continue
+			}
+			if acks[i], err = reply.ack(); err != nil {
+				return nil, err
+			}
+			received++
+			progress = true
+		}
+		if received == n {
+			return acks, nil
+		}
+		if progress {
+			timer.Reset(timeout)
+		}
+
+		select {
+		case <-c.replied:
+		case err := <-c.errs:
+			return nil, err
+		case <-c.done:
+			return nil, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, fmt.Errorf("%d of %d replies not received within %s", n-received, n, timeout)
+		}
+	}
+}
+
+// ack decodes a JetStream reply
+func (r natsReply) ack() (*jetStreamAck, error) {
+	if r.status == "503" {
+		return &jetStreamAck{Error: &jetStreamError{Code: 503, Description: "no JetStream stream matches the subject"}}, nil
+	}
+	var ack jetStreamAck
+	if err := json.Unmarshal(r.data, &ack); err != nil {
+		return nil, fmt.Errorf("invalid JetStream reply: %w", err)
+	}
+	return &ack, nil
+}
+
+// addReply queues a reply received on the inbox, without waiting for the publisher
+func (c *natsConn) addReply(reply natsReply) {
+	c.repliesMu.Lock()
+	c.replies = append(c.replies, reply)
+	c.repliesMu.Unlock()
+
+	select {
+	case c.replied <- struct{}{}:
+	default:
+	}
+}
+
+// takeReplies returns the replies received since the previous call
+func (c *natsConn) takeReplies() []natsReply {
+	c.repliesMu.Lock()
+	defer c.repliesMu.Unlock()
+	replies := c.replies
+	c.replies = nil
+	return replies
+}
+
+// discard drops replies, pongs and errors left over from an earlier batch
+func (c *natsConn) discard() {
+	c.takeReplies()
+	for {
+		select {
+		case <-c.replied:
+		case <-c.pongs:
+		case <-c.errs:
+		default:
+			return
+		}
+	}
+}
+
+// read processes server messages until the connection fails
+func (c *natsConn) read(reader *bufio.Reader) {
+	defer close(c.done)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			c.err = fmt.Errorf("NATS connection lost: %w", err)
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		op, args, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(op) {
+		case "PING":
+			c.mu.Lock()
+			c.writer.WriteString("PONG\r\n")
+			c.writer.Flush()
+			c.mu.Unlock()
+		case "PONG":
+			select {
+			case c.pongs <- struct{}{}:
+			default:
+			}
+		case "-ERR":
+			select {
+			case c.errs <- &natsRejection{"NATS server error: " + strings.Trim(args, " '")}:
+			default:
+			}
+		case "MSG", "HMSG":
+			reply, err := readNATSMessage(reader, strings.ToUpper(op) == "HMSG", strings.Fields(args))
+			if err != nil {
+				c.err = fmt.Errorf("NATS connection lost: %w", err)
+				return
+			}
+			c.addReply(reply)
+		}
+	}
+}
+
+// readNATSMessage reads the payload of a MSG or HMSG whose arguments are
+// <subject> <sid> [reply] [header bytes] <total bytes>
+func readNATSMessage(reader *bufio.Reader, headers bool, args []string) (natsReply, error) {
+	if len(args) < 3 {
+		return natsReply{}, fmt.Errorf("malformed message arguments %q", args)
+	}
+	total, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		return natsReply{}, fmt.Errorf("malformed message size %q", args[len(args)-1])
+	}
+	headerSize := 0
+	if headers {
+		if headerSize, err = strconv.Atoi(args[len(args)-2]); err != nil || headerSize > total {
+			return natsReply{}, fmt.Errorf("malformed header size %q", args[len(args)-2])
+		}
+	}
+
+	payload := make([]byte, total+2)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return natsReply{}, err
+	}
+
+	reply := natsReply{subject: args[0], data: payload[headerSize:total]}
+	if headers {
+		// The first header line is NATS/1.0 followed by an optional status code
+		status := bytes.Fields(bytes.SplitN(payload[:headerSize], []byte("\r\n"), 2)[0])
+		if len(status) > 1 {
+			reply.status = string(status[1])
+		}
+	}
+	return reply, nil
+}
+
+// natsToken replaces the characters that are not allowed in a subject token
+func natsToken(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,294 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATS is a minimal NATS server recording CONNECT options and published messages
+type fakeNATS struct {
+	listener net.Listener
+	token    string // required auth_token, if set
+	reject   string // JetStream subject whose publishes are not stored
+	stale    bool   // send a failed ack to the previous reply subject before each ack
+
+	mu       sync.Mutex
+	connects []map[string]interface{}
+	messages []natsMessage
+	streams  []string
+	conns    []net.Conn
+}
+
+func newFakeNATS(t *testing.T) *fakeNATS {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeNATS{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.mu.Lock()
+			server.conns = append(server.conns, conn)
+			server.mu.Unlock()
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (f *fakeNATS) url() string {
+	return "nats://" + f.listener.Addr().String()
+}
+
+// dropConnections closes every client connection
+func (f *fakeNATS) dropConnections() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+	f.conns = nil
+}
+
+func (f *fakeNATS) published() []natsMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]natsMessage(nil), f.messages...)
+}
+
+func (f *fakeNATS) serve(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"headers\":true}\r\n")
+
+	reader := bufio.NewReader(conn)
+	sid := ""
+	seq := 0
+	previous := "" // reply subject of the previous JetStream publish
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "CONNECT":
+			var options map[string]interface{}
+			json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "CONNECT ")), &options)
+			f.mu.Lock()
+			f.connects = append(f.connects, options)
+			f.mu.Unlock()
+			if f.token != "" && options["auth_token"] != f.token {
+				fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+				return
+			}
+		case "SUB":
+			sid = fields[2]
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "PUB":
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			subject := fields[1]
+			if len(fields) < 4 {
+				f.mu.Lock()
+				f.messages = append(f.messages, natsMessage{subject: subject, data: payload[:size]})
+				f.mu.Unlock()
+				continue
+			}
+
+			reply := fields[2]
+			f.mu.Lock()
+			reject, stale := f.reject, f.stale
+			f.mu.Unlock()
+			if stale && previous != "" {
+				late := `{"error":{"code":503,"err_code":10077,"description":"late"}}`
+				fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", previous, sid, len(late), late)
+			}
+			previous = reply
+			var ack string
+			switch {
+			case strings.HasPrefix(subject, "$JS.API.STREAM.CREATE."):
+				f.mu.Lock()
+				f.streams = append(f.streams, string(payload[:size]))
+				f.mu.Unlock()
+				ack = `{"type":"io.nats.jetstream.api.v1.stream_create_response","config":{}}`
+			case subject == reject:
+				ack = `{"error":{"code":503,"err_code":10077,"description":"maximum messages exceeded"}}`
+			default:
+				seq++
+				f.mu.Lock()
+				f.messages = append(f.messages, natsMessage{subject: subject, data: payload[:size]})
+				f.mu.Unlock()
+				ack = fmt.Sprintf(`{"stream":"IBMMQ","seq":%d}`, seq)
+			}
+			fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+		}
+	}
+}
+
+func testNATSConfig(url string) *config.NATSConfig {
+	return &config.NATSConfig{
+		Enabled:       true,
+		URL:           url,
+		SubjectPrefix: "ibmmq",
+		Timeout:       5 * time.Second,
+	}
+}
+
+func TestNATSSinkPublishesBySubject(t *testing.T) {
+	server := newFakeNATS(t)
+	cfg := testNATSConfig(server.url())
+	cfg.Username = "collector"
+	cfg.Password = "secret"
+
+	sink, err := NewNATSSink(cfg, logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	batch := testBatch()
+	batch.QueueManager = "QM.DEFAULT"
+	batch.Accounting[0].QueueManager = ""
+	require.NoError(t, sink.Write(context.Background(), batch))
+
+	// Core NATS publishes are complete once the PING after them is answered
+	messages := server.published()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "ibmmq.statistics.QM1", messages[0].subject)
+	assert.Equal(t, "ibmmq.accounting.QM_DEFAULT", messages[1].subject)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(messages[1].data, &record))
+	assert.Equal(t, "QM.DEFAULT", record["queue_manager"])
+
+	server.mu.Lock()
+	assert.Equal(t, "collector", server.connects[0]["user"])
+	assert.Equal(t, "secret", server.connects[0]["pass"])
+	server.mu.Unlock()
+}
+
+func TestNATSSinkJetStream(t *testing.T) {
+	server := newFakeNATS(t)
+	cfg := testNATSConfig(server.url())
+	cfg.JetStream = true
+	cfg.Stream = "IBMMQ"
+
+	sink, err := NewNATSSink(cfg, logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	assert.Len(t, server.published(), 2)
+
+	server.mu.Lock()
+	require.Len(t, server.streams, 1)
+	assert.JSONEq(t, `{"name":"IBMMQ","subjects":["ibmmq.>"]}`, server.streams[0])
+	server.mu.Unlock()
+
+	// A record the stream refuses fails the write but keeps the connection
+	server.mu.Lock()
+	server.reject = "ibmmq.accounting.QM1"
+	server.mu.Unlock()
+	err = sink.Write(context.Background(), testBatch())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 records not stored by JetStream: maximum messages exceeded")
+
+	server.mu.Lock()
+	server.reject = ""
+	server.mu.Unlock()
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	assert.Len(t, server.published(), 5)
+	server.mu.Lock()
+	assert.Len(t, server.connects, 1)
+	server.mu.Unlock()
+}
+
+func TestNATSSinkJetStreamMatchesAcks(t *testing.T) {
+	server := newFakeNATS(t)
+	server.stale = true
+	cfg := testNATSConfig(server.url())
+	cfg.JetStream = true
+
+	sink, err := NewNATSSink(cfg, logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	// Acks for other reply subjects, including those of an earlier batch, are not
+	// counted against the batch
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+
+	// A batch of more acks than ever fit a buffer
+	batch := testBatch()
+	for len(batch.Statistics) < 1000 {
+		batch.Statistics = append(batch.Statistics, batch.Statistics[0])
+	}
+	require.NoError(t, sink.Write(context.Background(), batch))
+	assert.Len(t, server.published(), 1005)
+}
+
+func TestNATSSinkReconnects(t *testing.T) {
+	server := newFakeNATS(t)
+	sink, err := NewNATSSink(testNATSConfig(server.url()), logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	server.dropConnections()
+	require.Eventually(t, func() bool {
+		select {
+		case <-sink.conn.done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	assert.Len(t, server.published(), 4)
+}
+
+func TestNATSSinkAuthorizationFailure(t *testing.T) {
+	server := newFakeNATS(t)
+	server.token = "right"
+	cfg := testNATSConfig(server.url())
+	cfg.Token = "wrong"
+
+	sink, err := NewNATSSink(cfg, logrus.New())
+	require.NoError(t, err)
+
+	err = sink.Write(context.Background(), testBatch())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Authorization Violation")
+	assert.Nil(t, sink.conn)
+}
+
+func TestNATSToken(t *testing.T) {
+	assert.Equal(t, "QM1", natsToken("QM1"))
+	assert.Equal(t, "QM_A_B_", natsToken("QM.A*B>"))
+	assert.Equal(t, "unknown", natsToken(""))
+}
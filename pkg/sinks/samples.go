@@ -0,0 +1,83 @@
+package sinks
+
+import "time"
+
+// Sample kinds
+const (
+	kindGauge   = "gauge"
+	kindCounter = "counter"
+)
+
+// sample is one numeric value derived from a record, for the metric-oriented sinks.
+// Counters hold the activity within the statistics or accounting interval.
+type sample struct {
+	name      string // e.g. queue.depth
+	value     float64
+	kind      string
+	labels    map[string]string
+	timestamp time.Time
+}
+
+// samples flattens the records of a batch into gauges and counters labelled with the queue
+// manager (qmgr) and the queue, channel (plus conname) or application they describe
+func samples(batch *Batch) []sample {
+	var out []sample
+	add := func(name string, value float64, kind string, labels map[string]string, ts time.Time) {
+		out = append(out, sample{name: name, value: value, kind: kind, labels: labels, timestamp: ts})
+	}
+
+	for _, stats := range batch.Statistics {
+		qmgr := batch.queueManager(stats.QueueManager)
+
+		if q := stats.QueueStats; q != nil {
+			labels := map[string]string{"qmgr": qmgr, "queue": q.QueueName}
+			add("queue.depth", float64(q.CurrentDepth), kindGauge, labels, stats.Timestamp)
+			add("queue.high_depth", float64(q.HighDepth), kindGauge, labels, stats.Timestamp)
+			add("queue.input_handles", float64(q.InputCount), kindGauge, labels, stats.Timestamp)
+			add("queue.output_handles", float64(q.OutputCount), kindGauge, labels, stats.Timestamp)
+			add("queue.enqueued", float64(q.EnqueueCount), kindCounter, labels, stats.Timestamp)
+			add("queue.dequeued", float64(q.DequeueCount), kindCounter, labels, stats.Timestamp)
+		}
+
+		if ch := stats.ChannelStats; ch != nil {
+			labels := map[string]string{"qmgr": qmgr, "channel": ch.ChannelName, "conname": ch.ConnectionName}
+			add("channel.messages", float64(ch.Messages), kindCounter, labels, stats.Timestamp)
+			add("channel.bytes", float64(ch.Bytes), kindCounter, labels, stats.Timestamp)
+			add("channel.batches", float64(ch.Batches), kindCounter, labels, stats.Timestamp)
+		}
+
+		if mqi := stats.MQIStats; mqi != nil {
+			labels := map[string]string{"qmgr": qmgr, "application": mqi.ApplicationName}
+			add("mqi.opens", float64(mqi.Opens), kindCounter, labels, stats.Timestamp)
+			add("mqi.closes", float64(mqi.Closes), kindCounter, labels, stats.Timestamp)
+			add("mqi.puts", float64(mqi.Puts), kindCounter, labels, stats.Timestamp)
+			add("mqi.gets", float64(mqi.Gets), kindCounter, labels, stats.Timestamp)
+			add("mqi.commits", float64(mqi.Commits), kindCounter, labels, stats.Timestamp)
+			add("mqi.backouts", float64(mqi.Backouts), kindCounter, labels, stats.Timestamp)
+		}
+	}
+
+	for _, acct := range batch.Accounting {
+		ops := acct.Operations
+		if ops == nil {
+			continue
+		}
+		application := ""
+		if acct.ConnectionInfo != nil {
+			application = acct.ConnectionInfo.ApplicationName
+		}
+
+		labels := map[string]string{"qmgr": batch.queueManager(acct.QueueManager), "application": application}
+		add("accounting.puts", float64(ops.Puts), kindCounter, labels, acct.Timestamp)
+		add("accounting.gets", float64(ops.Gets), kindCounter, labels, acct.Timestamp)
+		add("accounting.browses", float64(ops.Browses), kindCounter, labels, acct.Timestamp)
+		add("accounting.opens", float64(ops.Opens), kindCounter, labels, acct.Timestamp)
+		add("accounting.closes", float64(ops.Closes), kindCounter, labels, acct.Timestamp)
+		add("accounting.commits", float64(ops.Commits), kindCounter, labels, acct.Timestamp)
+		add("accounting.backouts", float64(ops.Backouts), kindCounter, labels, acct.Timestamp)
+		add("accounting.put_bytes", float64(ops.PutBytes), kindCounter, labels, acct.Timestamp)
+		add("accounting.get_bytes", float64(ops.GetBytes), kindCounter, labels, acct.Timestamp)
+	}
+
+	return out
+}
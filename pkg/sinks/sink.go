@@ -0,0 +1,109 @@
+package sinks
+
+import (
+	"context"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+)
+
+// Batch holds the records parsed in one collection cycle
+type Batch struct {
+	// QueueManager is the configured queue manager, used for records that do not name one
+	QueueManager string
+	Statistics   []*pcf.StatisticsData
+	Accounting   []*pcf.AccountingData
+}
+
+// Len returns the number of records in the batch
+func (b *Batch) Len() int {
+	return len(b.Statistics) + len(b.Accounting)
+}
+
+// queueManager returns the record's queue manager, or the configured one if it is empty
+func (b *Batch) queueManager(recordQueueManager string) string {
+	if recordQueueManager != "" {
+		return recordQueueManager
+	}
+	return b.QueueManager
+}
+
+// Sink receives every batch of records collected, in addition to the Prometheus and
+// OpenTelemetry exporters. A failing sink does not fail the collection cycle.
+type Sink interface {
+	// Name identifies the sink in logs
+	Name() string
+	// Write delivers a batch of records. The records are shared with the other sinks and
+	// must not be modified; they are never reused, so a sink may keep them.
+	Write(ctx context.Context, batch *Batch) error
+	// Close flushes buffered records and releases resources
+	Close() error
+}
+
+// NewFromConfig creates the sinks enabled in the configuration
+func NewFromConfig(cfg *config.SinksConfig, logger *logrus.Logger) ([]Sink, error) {
+	var sinks []Sink
+
+	if cfg.Elasticsearch.Enabled {
+		sinks = append(sinks, NewElasticsearchSink(&cfg.Elasticsearch, logger))
+	}
+
+	if cfg.StatsD.Enabled {
+		sink, err := NewStatsDSink(&cfg.StatsD, logger)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.Graphite.Enabled {
+		sinks = append(sinks, NewGraphiteSink(&cfg.Graphite, logger))
+	}
+
+	if cfg.CloudWatch.Enabled {
+		sinks = append(sinks, NewCloudWatchSink(&cfg.CloudWatch, logger))
+	}
+
+	if cfg.AzureMonitor.Enabled {
+		sinks = append(sinks, NewAzureMonitorSink(&cfg.AzureMonitor, logger))
+	}
+
+	if cfg.GoogleCloud.Enabled {
+		sink, err := NewGoogleCloudSink(&cfg.GoogleCloud, logger)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.Splunk.Enabled {
+		sinks = append(sinks, NewSplunkSink(&cfg.Splunk, logger))
+	}
+
+	if cfg.Syslog.Enabled {
+		sink, err := NewSyslogSink(&cfg.Syslog, logger)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.File.Enabled {
+		sink, err := NewFileSink(&cfg.File, logger)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.NATS.Enabled {
+		sink, err := NewNATSSink(&cfg.NATS, logger)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
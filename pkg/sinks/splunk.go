@@ -0,0 +1,220 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+)
+
+// SplunkSink posts statistics and accounting records to a Splunk HTTP Event Collector.
+// Each record is one event with sourcetype <sourcetype>:statistics or
+// <sourcetype>:accounting. Requests that fail with a transport error, 429 or a server
+// error are retried with exponential backoff.
+type SplunkSink struct {
+	config *config.SplunkConfig
+	client *http.Client
+	host   string
+	logger *logrus.Logger
+}
+
+// splunkEvent is one event in the HEC JSON format
+type splunkEvent struct {
+	Time       float64     `json:"time"`
+	Host       string      `json:"host,omitempty"`
+	Source     string      `json:"source,omitempty"`
+	SourceType string      `json:"sourcetype,omitempty"`
+	Index      string      `json:"index,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+// splunkStatistics is the event body of a statistics record
+type splunkStatistics struct {
+	RecordType string `json:"record_type"`
+	*pcf.StatisticsData
+}
+
+// splunkAccounting is the event body of an accounting record
+type splunkAccounting struct {
+	RecordType string `json:"record_type"`
+	*pcf.AccountingData
+}
+
+// splunkResponse is the HEC acknowledgement body
+type splunkResponse struct {
+	Text string `json:"text"`
+	Code int    `json:"code"`
+}
+
+// NewSplunkSink creates a Splunk HEC sink
+func NewSplunkSink(cfg *config.SplunkConfig, logger *logrus.Logger) *SplunkSink {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	host := cfg.Host
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	return &SplunkSink{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		host:   host,
+		logger: logger,
+	}
+}
+
+// Name identifies the sink in logs
+func (s *SplunkSink) Name() string {
+	return "splunk"
+}
+
+// Write posts the records of the batch, up to batch_size events per request
+func (s *SplunkSink) Write(ctx context.Context, batch *Batch) error {
+	events := s.events(batch)
+	for start := 0; start < len(events); start += s.config.BatchSize {
+		end := start + s.config.BatchSize
+		if end > len(events) {
+			end = len(events)
+		}
+		if err := s.send(ctx, events[start:end]); err != nil {
+			return err
+		}
+	}
+
+	if len(events) > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"sink":   s.Name(),
+			"events": len(events),
+		}).Debug("Sent events")
+	}
+
+	return nil
+}
+
+// Close releases idle connections
+func (s *SplunkSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// events converts the records of the batch into HEC events
+func (s *SplunkSink) events(batch *Batch) []splunkEvent {
+	now := time.Now()
+	events := make([]splunkEvent, 0, batch.Len())
+
+	for _, stats := range batch.Statistics {
+		events = append(events, s.event("statistics", recordTime(stats.Timestamp, now),
+			splunkStatistics{RecordType: "statistics", StatisticsData: stats}))
+	}
+	for _, acct := range batch.Accounting {
+		events = append(events, s.event("accounting", recordTime(acct.Timestamp, now),
+			splunkAccounting{RecordType: "accounting", AccountingData: acct}))
+	}
+
+	return events
+}
+
+func (s *SplunkSink) event(recordType string, ts time.Time, body interface{}) splunkEvent {
+	return splunkEvent{
+		Time:       float64(ts.UnixMilli()) / 1000,
+		Host:       s.host,
+		Source:     s.config.Source,
+		SourceType: s.config.SourceType + ":" + recordType,
+		Index:      s.config.Index,
+		Event:      body,
+	}
+}
+
+// send posts one batch of events, retrying transient failures
+func (s *SplunkSink) send(ctx context.Context, events []splunkEvent) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := s.config.RetryBackoff << (attempt - 1)
+			s.logger.WithFields(logrus.Fields{
+				"sink":    s.Name(),
+				"attempt": attempt,
+				"backoff": backoff,
+				"error":   lastErr,
+			}).Debug("Retrying HEC request")
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+
+		retry, err := s.post(ctx, body.Bytes())
+		if err == nil {
+			return nil
+		}
+		if !retry {
+			return err
+		}
+		lastErr = err
+	}
+
+	return fmt.Errorf("HEC request failed after %d attempts: %w", s.config.MaxRetries+1, lastErr)
+}
+
+// post sends the request once and reports whether a failure is worth retrying
+func (s *SplunkSink) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Splunk "+s.config.Token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusOK {
+		return false, nil
+	}
+
+	detail := strings.TrimSpace(string(respBody))
+	var hecResp splunkResponse
+	if json.Unmarshal(respBody, &hecResp) == nil && hecResp.Text != "" {
+		detail = fmt.Sprintf("%s (code %d)", hecResp.Text, hecResp.Code)
+	}
+	err = fmt.Errorf("HEC returned %s: %s", resp.Status, detail)
+
+	// Busy or unavailable indexers recover; bad tokens and malformed events do not
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// endpoint returns the event endpoint, accepting either the base URL or the full path
+func (s *SplunkSink) endpoint() string {
+	url := strings.TrimRight(s.config.URL, "/")
+	if strings.Contains(url, "/services/collector") {
+		return url
+	}
+	return url + "/services/collector/event"
+}
@@ -0,0 +1,121 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHEC records the events it receives and answers with the given status codes in turn
+type fakeHEC struct {
+	statuses []int
+	requests int
+	events   []map[string]interface{}
+}
+
+func (f *fakeHEC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests++
+	status := http.StatusOK
+	if len(f.statuses) > 0 {
+		status, f.statuses = f.statuses[0], f.statuses[1:]
+	}
+
+	if r.URL.Path != "/services/collector/event" || r.Header.Get("Authorization") != "Splunk hec-token" {
+		status = http.StatusUnauthorized
+	}
+	switch status {
+	case http.StatusOK:
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var event map[string]interface{}
+			if json.Unmarshal(scanner.Bytes(), &event) == nil {
+				f.events = append(f.events, event)
+			}
+		}
+		w.Write([]byte(`{"text":"Success","code":0}`))
+	case http.StatusUnauthorized:
+		w.WriteHeader(status)
+		w.Write([]byte(`{"text":"Invalid token","code":4}`))
+	default:
+		w.WriteHeader(status)
+		w.Write([]byte(`{"text":"Server is busy","code":9}`))
+	}
+}
+
+func newTestSplunkSink(url string) *SplunkSink {
+	cfg := config.DefaultConfig().Sinks.Splunk
+	cfg.Enabled = true
+	cfg.URL = url
+	cfg.Token = "hec-token"
+	cfg.Index = "mq"
+	cfg.Host = "mqhost1"
+	cfg.RetryBackoff = time.Millisecond
+	return NewSplunkSink(&cfg, logrus.New())
+}
+
+func TestSplunkSinkWrite(t *testing.T) {
+	hec := &fakeHEC{}
+	server := httptest.NewServer(hec)
+	defer server.Close()
+
+	sink := newTestSplunkSink(server.URL)
+	sink.config.BatchSize = 1
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+
+	assert.Equal(t, 2, hec.requests, "one event per request")
+	require.Len(t, hec.events, 2)
+
+	stats := hec.events[0]
+	assert.Equal(t, "ibmmq:statistics", stats["sourcetype"])
+	assert.Equal(t, "ibmmq-collector", stats["source"])
+	assert.Equal(t, "mq", stats["index"])
+	assert.Equal(t, "mqhost1", stats["host"])
+	assert.Equal(t, float64(time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC).Unix()), stats["time"])
+
+	event := stats["event"].(map[string]interface{})
+	assert.Equal(t, "statistics", event["record_type"])
+	assert.Equal(t, "QM1", event["queue_manager"])
+	assert.Equal(t, "APP.ORDERS", event["queue_stats"].(map[string]interface{})["queue_name"])
+
+	assert.Equal(t, "ibmmq:accounting", hec.events[1]["sourcetype"])
+}
+
+func TestSplunkSinkRetries(t *testing.T) {
+	hec := &fakeHEC{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	server := httptest.NewServer(hec)
+	defer server.Close()
+
+	sink := newTestSplunkSink(server.URL + "/")
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+	assert.Equal(t, 3, hec.requests)
+	assert.Len(t, hec.events, 2)
+
+	hec.requests = 0
+	hec.statuses = []int{503, 503, 503, 503}
+	err := sink.Write(context.Background(), testBatch())
+	require.Error(t, err)
+	assert.Equal(t, 4, hec.requests, "first attempt plus max_retries")
+	assert.Contains(t, err.Error(), "Server is busy")
+}
+
+func TestSplunkSinkDoesNotRetryClientErrors(t *testing.T) {
+	hec := &fakeHEC{}
+	server := httptest.NewServer(hec)
+	defer server.Close()
+
+	sink := newTestSplunkSink(server.URL)
+	sink.config.Token = "wrong"
+	err := sink.Write(context.Background(), testBatch())
+	require.Error(t, err)
+	assert.Equal(t, 1, hec.requests)
+	assert.Contains(t, err.Error(), "Invalid token (code 4)")
+}
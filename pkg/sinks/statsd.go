@@ -0,0 +1,150 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Order in which sample labels become tags or metric name segments
+var labelOrder = []string{"qmgr", "queue", "channel", "conname", "application"}
+
+// StatsDSink sends gauges and counters over UDP in the StatsD line protocol. The dogstatsd
+// flavor attaches the labels as tags (#qmgr:QM1,queue:APP.IN); plain statsd has no tags,
+// so the labels are appended to the metric name instead.
+type StatsDSink struct {
+	config *config.StatsDConfig
+	logger *logrus.Logger
+	conn   net.Conn
+}
+
+// NewStatsDSink creates a StatsD sink. UDP is connectionless, so this only resolves the address.
+func NewStatsDSink(cfg *config.StatsDConfig, logger *logrus.Logger) (*StatsDSink, error) {
+	conn, err := net.Dial("udp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve statsd address %s: %w", cfg.Address, err)
+	}
+	return &StatsDSink{config: cfg, logger: logger, conn: conn}, nil
+}
+
+// Name identifies the sink in logs
+func (s *StatsDSink) Name() string {
+	return "statsd"
+}
+
+// Write sends the samples of the batch, packing as many lines as fit into each datagram
+func (s *StatsDSink) Write(ctx context.Context, batch *Batch) error {
+	var packet bytes.Buffer
+	sent := 0
+
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := s.conn.Write(packet.Bytes())
+		packet.Reset()
+		return err
+	}
+
+	for _, smp := range samples(batch) {
+		line := s.line(smp)
+		if packet.Len() > 0 && packet.Len()+1+len(line) > s.config.MaxPacketSize {
+			if err := flush(); err != nil {
+				return fmt.Errorf("failed to send statsd packet: %w", err)
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+		sent++
+	}
+	if err := flush(); err != nil {
+		return fmt.Errorf("failed to send statsd packet: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":    s.Name(),
+		"metrics": sent,
+	}).Debug("Sent metrics")
+
+	return nil
+}
+
+// Close closes the UDP socket
+func (s *StatsDSink) Close() error {
+	return s.conn.Close()
+}
+
+// line formats a sample in the configured flavor
+func (s *StatsDSink) line(smp sample) string {
+	var b strings.Builder
+
+	if s.config.Prefix != "" {
+		b.WriteString(s.config.Prefix)
+		b.WriteByte('.')
+	}
+	b.WriteString(smp.name)
+
+	if s.config.Flavor == "statsd" {
+		for _, key := range labelOrder {
+			if value := smp.labels[key]; value != "" {
+				b.WriteByte('.')
+				b.WriteString(statsdNameSegment(value))
+			}
+		}
+	}
+
+	b.WriteByte(':')
+	b.WriteString(strconv.FormatFloat(smp.value, 'f', -1, 64))
+	if smp.kind == kindCounter {
+		b.WriteString("|c")
+	} else {
+		b.WriteString("|g")
+	}
+
+	if s.config.Flavor == "dogstatsd" {
+		tags := append([]string(nil), s.config.Tags...)
+		for _, key := range labelOrder {
+			if value := smp.labels[key]; value != "" {
+				tags = append(tags, key+":"+statsdTagValue(value))
+			}
+		}
+		if len(tags) > 0 {
+			b.WriteString("|#")
+			b.WriteString(strings.Join(tags, ","))
+		}
+	}
+
+	return b.String()
+}
+
+// statsdNameSegment replaces characters that would split or end a metric name
+func statsdNameSegment(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+}
+
+// statsdTagValue replaces the characters that separate tags and fields
+func statsdTagValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '|', '#', ' ', '\n':
+			return '_'
+		default:
+			return r
+		}
+	}, value)
+}
@@ -0,0 +1,111 @@
+package sinks
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listenStatsD starts a UDP listener and returns its address and a function returning the packets received
+func listenStatsD(t *testing.T) (string, func() []string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn.LocalAddr().String(), func() []string {
+		var packets []string
+		buf := make([]byte, 65536)
+		for {
+			conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return packets
+			}
+			packets = append(packets, string(buf[:n]))
+		}
+	}
+}
+
+// statsdLines splits packets into metric lines
+func statsdLines(packets []string) []string {
+	var lines []string
+	for _, packet := range packets {
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	return lines
+}
+
+func testStatsDConfig(address string) *config.StatsDConfig {
+	cfg := config.DefaultConfig().Sinks.StatsD
+	cfg.Enabled = true
+	cfg.Address = address
+	return &cfg
+}
+
+func TestStatsDSinkDogStatsD(t *testing.T) {
+	address, received := listenStatsD(t)
+
+	cfg := testStatsDConfig(address)
+	cfg.Tags = []string{"env:test"}
+	sink, err := NewStatsDSink(cfg, logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+
+	lines := statsdLines(received())
+	assert.Contains(t, lines, "ibmmq.queue.depth:4|g|#env:test,qmgr:QM1,queue:APP.ORDERS")
+	assert.Contains(t, lines, "ibmmq.queue.enqueued:0|c|#env:test,qmgr:QM1,queue:APP.ORDERS")
+	assert.Len(t, lines, 6, "accounting records without operation counts produce no metrics")
+}
+
+func TestStatsDSinkPlainStatsD(t *testing.T) {
+	address, received := listenStatsD(t)
+
+	cfg := testStatsDConfig(address)
+	cfg.Flavor = "statsd"
+	cfg.Prefix = "mq"
+	sink, err := NewStatsDSink(cfg, logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	batch := &Batch{
+		QueueManager: "QM1",
+		Statistics: []*pcf.StatisticsData{{
+			ChannelStats: &pcf.ChannelStatistics{ChannelName: "TO.QM2", ConnectionName: "10.0.0.1(1414)", Messages: 12},
+		}},
+	}
+	require.NoError(t, sink.Write(context.Background(), batch))
+
+	assert.Contains(t, statsdLines(received()), "mq.channel.messages.QM1.TO_QM2.10_0_0_1_1414_:12|c")
+}
+
+func TestStatsDSinkPacketSize(t *testing.T) {
+	address, received := listenStatsD(t)
+
+	cfg := testStatsDConfig(address)
+	cfg.MaxPacketSize = 100
+	sink, err := NewStatsDSink(cfg, logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Write(context.Background(), testBatch()))
+
+	packets := received()
+	assert.Greater(t, len(packets), 1)
+	assert.Len(t, statsdLines(packets), 6, "no lines are lost when splitting")
+	for _, packet := range packets {
+		// A single line may exceed the limit on its own, but lines are never combined past it
+		if strings.Contains(packet, "\n") {
+			assert.LessOrEqual(t, len(packet), cfg.MaxPacketSize)
+		}
+	}
+}
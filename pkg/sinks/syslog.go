@@ -0,0 +1,383 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+)
+
+// Syslog facility codes by name
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "daemon": 3, "auth": 4, "syslog": 5, "authpriv": 10,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// Syslog severity of connection events (informational)
+const syslogSeverityInfo = 6
+
+// Fields of the CEF and LEEF headers
+const (
+	securityVendor        = "IBM"
+	securityProduct       = "MQ Statistics Collector"
+	securityVersion       = "1.0"
+	securityEventID       = "MQCONN"
+	securityEventName     = "MQ application connection"
+	securityEventSeverity = "3"
+)
+
+// SyslogSink sends accounting connection records to a syslog receiver as RFC 5424
+// messages whose body is a CEF or LEEF event, so SIEM tools can ingest MQ application
+// connection activity with their standard parsers. Statistics records are ignored.
+type SyslogSink struct {
+	config    *config.SyslogConfig
+	logger    *logrus.Logger
+	facility  int
+	hostname  string
+	tlsConfig *tls.Config
+	conn      net.Conn
+}
+
+// NewSyslogSink creates a syslog sink; the connection is opened on the first write
+func NewSyslogSink(cfg *config.SyslogConfig, logger *logrus.Logger) (*SyslogSink, error) {
+	facility, ok := syslogFacilities[strings.ToLower(cfg.Facility)]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility: %s", cfg.Facility)
+	}
+
+	hostname := cfg.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	s := &SyslogSink{config: cfg, logger: logger, facility: facility, hostname: hostname}
+
+	if cfg.Network == "tls" {
+		s.tlsConfig = &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read syslog CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in syslog CA file %s", cfg.CAFile)
+			}
+			s.tlsConfig.RootCAs = pool
+		}
+	}
+
+	return s, nil
+}
+
+// Name identifies the sink in logs
+func (s *SyslogSink) Name() string {
+	return "syslog"
+}
+
+// Write sends one message per accounting record that carries connection details
+func (s *SyslogSink) Write(ctx context.Context, batch *Batch) error {
+	now := time.Now()
+	var messages []string
+	for _, acct := range batch.Accounting {
+		if acct.ConnectionInfo == nil {
+			continue
+		}
+		messages = append(messages, s.message(batch, acct, now))
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := s.send(ctx, messages); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sink":   s.Name(),
+		"events": len(messages),
+	}).Debug("Sent connection events")
+
+	return nil
+}
+
+// Close closes the connection
+func (s *SyslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// message formats an accounting record as an RFC 5424 syslog message
+func (s *SyslogSink) message(batch *Batch, acct *pcf.AccountingData, now time.Time) string {
+	ts := recordTime(acct.Timestamp, now)
+
+	var body string
+	if s.config.Format == "leef" {
+		body = leefEvent(batch, acct, ts)
+	} else {
+		body = cefEvent(batch, acct, ts)
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		s.facility*8+syslogSeverityInfo,
+		ts.Format("2006-01-02T15:04:05.000Z07:00"),
+		syslogHeaderField(s.hostname),
+		syslogHeaderField(s.config.AppName),
+		securityEventID,
+		body)
+}
+
+// send writes the messages, reconnecting once if the existing connection was dropped.
+// Stream transports use octet-counting framing (RFC 6587); UDP sends one datagram each.
+func (s *SyslogSink) send(ctx context.Context, messages []string) error {
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			conn, err := s.dial(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to connect to syslog at %s: %w", s.config.Address, err)
+			}
+			s.conn = conn
+		}
+
+		s.conn.SetWriteDeadline(time.Now().Add(s.config.Timeout))
+		if err := s.writeMessages(messages); err != nil {
+			lastErr = err
+			s.Close()
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to send syslog messages: %w", lastErr)
+}
+
+func (s *SyslogSink) writeMessages(messages []string) error {
+	if s.config.Network == "udp" {
+		for _, msg := range messages {
+			if _, err := s.conn.Write([]byte(msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, msg := range messages {
+		fmt.Fprintf(&buf, "%d %s", len(msg), msg)
+	}
+	_, err := buf.WriteTo(s.conn)
+	return err
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+	switch s.config.Network {
+	case "tls":
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}
+		return tlsDialer.DialContext(ctx, "tcp", s.config.Address)
+	case "tcp":
+		return dialer.DialContext(ctx, "tcp", s.config.Address)
+	default:
+		return dialer.DialContext(ctx, "udp", s.config.Address)
+	}
+}
+
+// connectionFields are the event fields shared by the CEF and LEEF formats
+type connectionFields struct {
+	queueManager string
+	channel      string
+	conname      string
+	application  string
+	user         string
+	address      string // IP address or host name from the connection name
+	port         string
+	connectTime  time.Time
+	disconnTime  time.Time
+	ops          pcf.OperationCounts
+}
+
+func newConnectionFields(batch *Batch, acct *pcf.AccountingData) connectionFields {
+	info := acct.ConnectionInfo
+	fields := connectionFields{
+		queueManager: batch.queueManager(acct.QueueManager),
+		channel:      info.ChannelName,
+		conname:      info.ConnectionName,
+		application:  info.ApplicationName,
+		user:         info.UserIdentifier,
+		connectTime:  info.ConnectTime,
+		disconnTime:  info.DisconnectTime,
+	}
+	if acct.Operations != nil {
+		fields.ops = *acct.Operations
+	}
+
+	// CONNAME is an address optionally followed by the port in parentheses
+	fields.address = info.ConnectionName
+	if open := strings.Index(info.ConnectionName, "("); open >= 0 && strings.HasSuffix(info.ConnectionName, ")") {
+		fields.address = info.ConnectionName[:open]
+		fields.port = info.ConnectionName[open+1 : len(info.ConnectionName)-1]
+	}
+	return fields
+}
+
+// cefEvent formats an accounting record as an ArcSight Common Event Format event
+func cefEvent(batch *Batch, acct *pcf.AccountingData, ts time.Time) string {
+	f := newConnectionFields(batch, acct)
+
+	var ext []string
+	add := func(key, value string) {
+		if value != "" {
+			ext = append(ext, key+"="+cefValue(value))
+		}
+	}
+	addTime := func(key string, t time.Time) {
+		if !t.IsZero() {
+			add(key, strconv.FormatInt(t.UnixMilli(), 10))
+		}
+	}
+
+	addTime("rt", ts)
+	addTime("start", f.connectTime)
+	addTime("end", f.disconnTime)
+	if net.ParseIP(f.address) != nil {
+		add("src", f.address)
+	} else {
+		add("shost", f.address)
+	}
+	add("spt", f.port)
+	add("suser", f.user)
+	add("sproc", f.application)
+	if f.queueManager != "" {
+		add("cs1Label", "queueManager")
+		add("cs1", f.queueManager)
+	}
+	if f.channel != "" {
+		add("cs2Label", "channel")
+		add("cs2", f.channel)
+	}
+	if f.conname != "" {
+		add("cs3Label", "connectionName")
+		add("cs3", f.conname)
+	}
+	if acct.Operations != nil {
+		add("cn1Label", "puts")
+		add("cn1", strconv.Itoa(int(f.ops.Puts)))
+		add("cn2Label", "gets")
+		add("cn2", strconv.Itoa(int(f.ops.Gets)))
+		add("cn3Label", "backouts")
+		add("cn3", strconv.Itoa(int(f.ops.Backouts)))
+		add("in", strconv.FormatInt(f.ops.PutBytes, 10))
+		add("out", strconv.FormatInt(f.ops.GetBytes, 10))
+	}
+
+	return strings.Join([]string{
+		"CEF:0",
+		cefHeader(securityVendor),
+		cefHeader(securityProduct),
+		cefHeader(securityVersion),
+		cefHeader(securityEventID),
+		cefHeader(securityEventName),
+		securityEventSeverity,
+		strings.Join(ext, " "),
+	}, "|")
+}
+
+// leefEvent formats an accounting record as an IBM QRadar Log Event Extended Format event
+func leefEvent(batch *Batch, acct *pcf.AccountingData, ts time.Time) string {
+	f := newConnectionFields(batch, acct)
+
+	var attrs []string
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, key+"="+leefValue(value))
+		}
+	}
+	// Default LEEF devTime layout (MMM dd yyyy HH:mm:ss.SSS zzz)
+	const leefTime = "Jan 02 2006 15:04:05.000 MST"
+	addTime := func(key string, t time.Time) {
+		if !t.IsZero() {
+			add(key, t.UTC().Format(leefTime))
+		}
+	}
+
+	addTime("devTime", ts)
+	add("cat", "ApplicationConnection")
+	add("sev", securityEventSeverity)
+	if net.ParseIP(f.address) != nil {
+		add("src", f.address)
+	} else {
+		add("srcHost", f.address)
+	}
+	add("srcPort", f.port)
+	add("usrName", f.user)
+	add("application", f.application)
+	add("queueManager", f.queueManager)
+	add("channel", f.channel)
+	add("connectionName", f.conname)
+	addTime("connectTime", f.connectTime)
+	addTime("disconnectTime", f.disconnTime)
+	if acct.Operations != nil {
+		add("puts", strconv.Itoa(int(f.ops.Puts)))
+		add("gets", strconv.Itoa(int(f.ops.Gets)))
+		add("browses", strconv.Itoa(int(f.ops.Browses)))
+		add("opens", strconv.Itoa(int(f.ops.Opens)))
+		add("closes", strconv.Itoa(int(f.ops.Closes)))
+		add("commits", strconv.Itoa(int(f.ops.Commits)))
+		add("backouts", strconv.Itoa(int(f.ops.Backouts)))
+		add("putBytes", strconv.FormatInt(f.ops.PutBytes, 10))
+		add("getBytes", strconv.FormatInt(f.ops.GetBytes, 10))
+	}
+
+	return strings.Join([]string{
+		"LEEF:1.0",
+		cefHeader(securityVendor),
+		cefHeader(securityProduct),
+		cefHeader(securityVersion),
+		cefHeader(securityEventID),
+		strings.Join(attrs, "\t"),
+	}, "|")
+}
+
+// cefHeader escapes a CEF or LEEF header field
+func cefHeader(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`).Replace(value)
+}
+
+// cefValue escapes a CEF extension value
+func cefValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`).Replace(value)
+}
+
+// leefValue removes the attribute delimiter and line breaks from a LEEF value
+func leefValue(value string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(value)
+}
+
+// syslogHeaderField returns a printable RFC 5424 header field, or - when empty
+func syslogHeaderField(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	}
+	return value
+}
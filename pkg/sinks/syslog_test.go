@@ -0,0 +1,152 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connectionBatch returns one accounting connection record and a statistics record
+func connectionBatch() *Batch {
+	ts := time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)
+	batch := testBatch()
+	batch.Accounting = []*pcf.AccountingData{{
+		Type:         "accounting",
+		QueueManager: "QM1",
+		Timestamp:    ts,
+		ConnectionInfo: &pcf.ConnectionInfo{
+			ChannelName:     "APP.SVRCONN",
+			ConnectionName:  "10.1.2.3(51234)",
+			ApplicationName: "orders-service",
+			UserIdentifier:  "appuser",
+			ConnectTime:     ts.Add(-time.Hour),
+			DisconnectTime:  ts,
+		},
+		Operations: &pcf.OperationCounts{Puts: 12, Gets: 10, Backouts: 1, PutBytes: 4096, GetBytes: 2048},
+	}, {
+		Type:         "accounting",
+		QueueManager: "QM1",
+		Timestamp:    ts,
+	}}
+	return batch
+}
+
+func TestCEFEvent(t *testing.T) {
+	batch := connectionBatch()
+	event := cefEvent(batch, batch.Accounting[0], batch.Accounting[0].Timestamp)
+
+	assert.Equal(t, "CEF:0|IBM|MQ Statistics Collector|1.0|MQCONN|MQ application connection|3|"+
+		"rt=1773484200000 start=1773480600000 end=1773484200000 src=10.1.2.3 spt=51234 suser=appuser "+
+		"sproc=orders-service cs1Label=queueManager cs1=QM1 cs2Label=channel cs2=APP.SVRCONN "+
+		"cs3Label=connectionName cs3=10.1.2.3(51234) cn1Label=puts cn1=12 cn2Label=gets cn2=10 "+
+		"cn3Label=backouts cn3=1 in=4096 out=2048", event)
+}
+
+func TestLEEFEvent(t *testing.T) {
+	batch := connectionBatch()
+	batch.Accounting[0].ConnectionInfo.ConnectionName = "mqclient.example.com"
+	event := leefEvent(batch, batch.Accounting[0], batch.Accounting[0].Timestamp)
+
+	require.True(t, strings.HasPrefix(event, "LEEF:1.0|IBM|MQ Statistics Collector|1.0|MQCONN|"))
+	attrs := strings.Split(strings.SplitN(event, "|", 6)[5], "\t")
+	assert.Contains(t, attrs, "devTime=Mar 14 2026 10:30:00.000 UTC")
+	assert.Contains(t, attrs, "srcHost=mqclient.example.com")
+	assert.Contains(t, attrs, "usrName=appuser")
+	assert.Contains(t, attrs, "application=orders-service")
+	assert.Contains(t, attrs, "queueManager=QM1")
+	assert.Contains(t, attrs, "puts=12")
+	assert.Contains(t, attrs, "putBytes=4096")
+	assert.NotContains(t, event, "srcPort=")
+}
+
+func TestSecurityEventEscaping(t *testing.T) {
+	assert.Equal(t, `a\|b\\c`, cefHeader(`a|b\c`))
+	assert.Equal(t, `k\=v\nx`, cefValue("k=v\nx"))
+	assert.Equal(t, "a b c", leefValue("a\tb\nc"))
+	assert.Equal(t, "-", syslogHeaderField(""))
+	assert.Equal(t, "mqhost", syslogHeaderField("mq host"))
+}
+
+func TestSyslogSinkTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Read octet-counted frames until the sink closes the connection
+		var frames []string
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				break
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			frame := make([]byte, n)
+			if _, err := io.ReadFull(reader, frame); err != nil {
+				break
+			}
+			frames = append(frames, string(frame))
+		}
+		received <- frames
+	}()
+
+	cfg := config.DefaultConfig().Sinks.Syslog
+	cfg.Enabled = true
+	cfg.Network = "tcp"
+	cfg.Address = listener.Addr().String()
+	cfg.Hostname = "mqhost1"
+	sink, err := NewSyslogSink(&cfg, logrus.New())
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), connectionBatch()))
+	require.NoError(t, sink.Close())
+
+	frames := <-received
+	require.Len(t, frames, 1, "statistics and records without connection details are skipped")
+	assert.True(t, strings.HasPrefix(frames[0],
+		"<134>1 2026-03-14T10:30:00.000Z mqhost1 ibmmq-collector - MQCONN - CEF:0|IBM|"), frames[0])
+}
+
+func TestSyslogSinkUDP(t *testing.T) {
+	addr, read := listenStatsD(t)
+
+	cfg := config.DefaultConfig().Sinks.Syslog
+	cfg.Address = addr
+	cfg.Format = "leef"
+	cfg.Facility = "auth"
+	sink, err := NewSyslogSink(&cfg, logrus.New())
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Write(context.Background(), connectionBatch()))
+	packets := read()
+	require.Len(t, packets, 1)
+	assert.True(t, strings.HasPrefix(packets[0], "<38>1 "), packets[0])
+	assert.Contains(t, packets[0], " - LEEF:1.0|IBM|")
+}
+
+func TestSyslogSinkUnknownFacility(t *testing.T) {
+	cfg := config.DefaultConfig().Sinks.Syslog
+	cfg.Facility = "local9"
+	_, err := NewSyslogSink(&cfg, logrus.New())
+	assert.Error(t, err)
+}
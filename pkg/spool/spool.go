@@ -0,0 +1,183 @@
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+)
+
+// Buffer holds the raw messages drained in a collection cycle. Messages are kept in
+// memory up to a limit on their data size; once it is reached every further message is
+// spilled to a file in the spool directory and later read back in batches of about the
+// limit, so a backlog of any size is processed in bounded memory.
+type Buffer struct {
+	dir   string
+	limit int64 // bytes of message data held in memory, 0 for no limit
+
+	mu       sync.Mutex
+	messages []*mqclient.MQMessage
+	size     int64
+	counts   map[string]int
+	file     *os.File
+	writer   *bufio.Writer
+	spilled  int
+}
+
+// NewBuffer creates a buffer spilling to dir, or the system temporary directory if dir is
+// empty, beyond limit bytes of message data. A limit that is not positive never spills.
+func NewBuffer(dir string, limit int64) *Buffer {
+	return &Buffer{dir: dir, limit: limit, counts: make(map[string]int)}
+}
+
+// Add buffers a message. It is safe for concurrent use, as by a parallel drain.
+func (b *Buffer) Add(msg *mqclient.MQMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.counts[msg.Type]++
+	if b.file == nil && (b.limit <= 0 || b.size+int64(len(msg.Data)) <= b.limit) {
+		b.messages = append(b.messages, msg)
+		b.size += int64(len(msg.Data))
+		return nil
+	}
+
+	if b.file == nil {
+		file, err := os.CreateTemp(b.dir, "ibmmq-spool-*")
+		if err != nil {
+			return fmt.Errorf("failed to create spool file: %w", err)
+		}
+		b.file = file
+		b.writer = bufio.NewWriter(file)
+	}
+	if err := writeMessage(b.writer, msg); err != nil {
+		return fmt.Errorf("failed to spool message: %w", err)
+	}
+	b.spilled++
+	return nil
+}
+
+// Count returns the number of messages added of the given queue type
+func (b *Buffer) Count(queueType string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.counts[queueType]
+}
+
+// Spilled returns the number of messages written to disk
+func (b *Buffer) Spilled() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spilled
+}
+
+// Batches passes the messages to fn in the order they were added: first those held in
+// memory, then the spilled ones in batches of about the memory limit. Spilled messages
+// come back without their message descriptor.
+func (b *Buffer) Batches(fn func([]*mqclient.MQMessage) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.messages) > 0 {
+		messages := b.messages
+		b.messages, b.size = nil, 0
+		if err := fn(messages); err != nil {
+			return err
+		}
+	}
+	if b.file == nil {
+		return nil
+	}
+
+	if err := b.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush spool file: %w", err)
+	}
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind spool file: %w", err)
+	}
+
+	reader := bufio.NewReader(b.file)
+	for {
+		var batch []*mqclient.MQMessage
+		var size int64
+		for size < b.limit {
+			msg, err := readMessage(reader)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read spool file: %w", err)
+			}
+			batch = append(batch, msg)
+			size += int64(len(msg.Data))
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+}
+
+// Close removes the spool file, if any
+func (b *Buffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.messages = nil
+	if b.file == nil {
+		return nil
+	}
+	name := b.file.Name()
+	b.file.Close()
+	b.file, b.writer = nil, nil
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("failed to remove spool file: %w", err)
+	}
+	return nil
+}
+
+// writeMessage writes a message as its type length, type, data length and data
+func writeMessage(w *bufio.Writer, msg *mqclient.MQMessage) error {
+	var header [5]byte
+	header[0] = byte(len(msg.Type))
+	binary.LittleEndian.PutUint32(header[1:], uint32(len(msg.Data)))
+
+	if _, err := w.Write(header[:1]); err != nil {
+		return err
+	}
+	if _, err := w.WriteString(msg.Type); err != nil {
+		return err
+	}
+	if _, err := w.Write(header[1:]); err != nil {
+		return err
+	}
+	_, err := w.Write(msg.Data)
+	return err
+}
+
+// readMessage reads a message written by writeMessage
+func readMessage(r *bufio.Reader) (*mqclient.MQMessage, error) {
+	typeLength, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, int(typeLength)+4)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	msg := &mqclient.MQMessage{Type: string(buf[:typeLength])}
+
+	msg.Data = make([]byte, binary.LittleEndian.Uint32(buf[typeLength:]))
+	if _, err := io.ReadFull(r, msg.Data); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return msg, nil
+}
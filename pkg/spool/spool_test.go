@@ -0,0 +1,88 @@
+package spool
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/mqclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func message(queueType string, size int, fill byte) *mqclient.MQMessage {
+	return &mqclient.MQMessage{Type: queueType, Data: bytes.Repeat([]byte{fill}, size)}
+}
+
+func TestBufferSpillsBeyondLimit(t *testing.T) {
+	dir := t.TempDir()
+	buffer := NewBuffer(dir, 100)
+
+	for i := 0; i < 10; i++ {
+		queueType := "stats"
+		if i%2 == 1 {
+			queueType = "accounting"
+		}
+		require.NoError(t, buffer.Add(message(queueType, 40, byte(i))))
+	}
+	assert.Equal(t, 5, buffer.Count("stats"))
+	assert.Equal(t, 5, buffer.Count("accounting"))
+	assert.Equal(t, 8, buffer.Spilled(), "two messages fit in memory")
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	var batches []int
+	var fills []byte
+	require.NoError(t, buffer.Batches(func(messages []*mqclient.MQMessage) error {
+		batches = append(batches, len(messages))
+		for _, msg := range messages {
+			require.Len(t, msg.Data, 40)
+			fills = append(fills, msg.Data[0])
+		}
+		return nil
+	}))
+	assert.Equal(t, []int{2, 3, 3, 2}, batches)
+	assert.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, fills, "messages keep their order")
+
+	require.NoError(t, buffer.Close())
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files, "the spool file is removed")
+}
+
+func TestBufferWithoutLimitNeverSpills(t *testing.T) {
+	dir := t.TempDir()
+	buffer := NewBuffer(dir, 0)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, buffer.Add(message("stats", 1024, 1)))
+	}
+	assert.Zero(t, buffer.Spilled())
+
+	calls := 0
+	require.NoError(t, buffer.Batches(func(messages []*mqclient.MQMessage) error {
+		calls++
+		assert.Len(t, messages, 100)
+		return nil
+	}))
+	assert.Equal(t, 1, calls)
+	require.NoError(t, buffer.Close())
+}
+
+func TestBufferLargeMessage(t *testing.T) {
+	buffer := NewBuffer(t.TempDir(), 10)
+	defer buffer.Close()
+
+	require.NoError(t, buffer.Add(message("accounting", 50, 7)))
+	assert.Equal(t, 1, buffer.Spilled())
+
+	var got []*mqclient.MQMessage
+	require.NoError(t, buffer.Batches(func(messages []*mqclient.MQMessage) error {
+		got = append(got, messages...)
+		return nil
+	}))
+	require.Len(t, got, 1)
+	assert.Equal(t, "accounting", got[0].Type)
+	assert.Len(t, got[0].Data, 50)
+}
@@ -0,0 +1,353 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+
+	// Pure Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// Statistics record kinds, by the object a record describes
+const (
+	KindQueue   = "queue"
+	KindChannel = "channel"
+	KindMQI     = "mqi"
+	KindOther   = "other"
+)
+
+// How often expired records are deleted while writing
+const pruneInterval = time.Hour
+
+const schema = `
+CREATE TABLE IF NOT EXISTS statistics (
+	id            INTEGER PRIMARY KEY,
+	record_time   INTEGER NOT NULL,
+	queue_manager TEXT NOT NULL,
+	kind          TEXT NOT NULL,
+	object        TEXT NOT NULL,
+	data          TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS statistics_object ON statistics (kind, object, record_time);
+CREATE INDEX IF NOT EXISTS statistics_time ON statistics (record_time);
+
+CREATE TABLE IF NOT EXISTS accounting (
+	id            INTEGER PRIMARY KEY,
+	record_time   INTEGER NOT NULL,
+	queue_manager TEXT NOT NULL,
+	application   TEXT NOT NULL,
+	user_id       TEXT NOT NULL,
+	data          TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS accounting_time ON accounting (record_time);
+`
+
+// Store keeps parsed statistics and accounting records in a local SQLite database, so
+// recent history survives gaps in Prometheus scraping and can feed the report commands.
+// It implements sinks.Sink and is written to once per collection cycle.
+type Store struct {
+	db        *sql.DB
+	retention time.Duration
+	logger    *logrus.Logger
+	lastPrune time.Time
+}
+
+// Query selects stored records; zero fields match everything
+type Query struct {
+	Kind   string // statistics only: queue, channel, mqi or other
+	Object string // statistics only: queue, channel or application name
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// QueuePoint is the state of a queue at the end of one statistics interval
+type QueuePoint struct {
+	Time         time.Time `json:"time"`
+	QueueManager string    `json:"queue_manager"`
+	Depth        int64     `json:"depth"`
+	HighDepth    int64     `json:"high_depth"`
+	Enqueued     int64     `json:"enqueued"`
+	Dequeued     int64     `json:"dequeued"`
+}
+
+// Open opens or creates the database at cfg.Path and removes expired records
+func Open(cfg *config.StoreConfig, logger *logrus.Logger) (*Store, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", cfg.Path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history store: %w", err)
+	}
+	// SQLite allows one writer; a single connection avoids SQLITE_BUSY between our own writes
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create history store schema in %s: %w", cfg.Path, err)
+	}
+
+	s := &Store{db: db, retention: cfg.Retention, logger: logger}
+	if err := s.pruneExpired(context.Background(), time.Now()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"path":      cfg.Path,
+		"retention": cfg.Retention,
+	}).Info("Opened history store")
+
+	return s, nil
+}
+
+// Name identifies the store in sink logs
+func (s *Store) Name() string {
+	return "store"
+}
+
+// Write persists the records of a collection cycle in one transaction
+func (s *Store) Write(ctx context.Context, batch *sinks.Batch) error {
+	now := time.Now()
+	if batch.Len() > 0 {
+		if err := s.insert(ctx, batch, now); err != nil {
+			return err
+		}
+	}
+
+	if now.Sub(s.lastPrune) >= pruneInterval {
+		return s.pruneExpired(ctx, now)
+	}
+	return nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) insert(ctx context.Context, batch *sinks.Batch, now time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin history store transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, stats := range batch.Statistics {
+		// Stored records always name their queue manager
+		record := *stats
+		record.QueueManager = queueManager(batch, stats.QueueManager)
+		data, err := json.Marshal(&record)
+		if err != nil {
+			return fmt.Errorf("failed to encode statistics record: %w", err)
+		}
+		kind, object := statisticsObject(stats)
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO statistics (record_time, queue_manager, kind, object, data) VALUES (?, ?, ?, ?, ?)`,
+			recordTime(stats.Timestamp, now), record.QueueManager, kind, object, string(data)); err != nil {
+			return fmt.Errorf("failed to store statistics record: %w", err)
+		}
+	}
+
+	for _, acct := range batch.Accounting {
+		record := *acct
+		record.QueueManager = queueManager(batch, acct.QueueManager)
+		data, err := json.Marshal(&record)
+		if err != nil {
+			return fmt.Errorf("failed to encode accounting record: %w", err)
+		}
+		application, user := "", ""
+		if acct.ConnectionInfo != nil {
+			application = acct.ConnectionInfo.ApplicationName
+			user = acct.ConnectionInfo.UserIdentifier
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO accounting (record_time, queue_manager, application, user_id, data) VALUES (?, ?, ?, ?, ?)`,
+			recordTime(acct.Timestamp, now), record.QueueManager, application, user, string(data)); err != nil {
+			return fmt.Errorf("failed to store accounting record: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit history store transaction: %w", err)
+	}
+	return nil
+}
+
+// pruneExpired deletes records older than the retention period
+func (s *Store) pruneExpired(ctx context.Context, now time.Time) error {
+	s.lastPrune = now
+	if s.retention <= 0 {
+		return nil
+	}
+
+	deleted, err := s.Prune(ctx, now.Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		s.logger.WithField("records", deleted).Debug("Pruned expired history records")
+	}
+	return nil
+}
+
+// Prune deletes records older than before and returns how many were removed
+func (s *Store) Prune(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for _, table := range []string{"statistics", "accounting"} {
+		result, err := s.db.ExecContext(ctx, "DELETE FROM "+table+" WHERE record_time < ?", before.UnixMilli())
+		if err != nil {
+			return total, fmt.Errorf("failed to prune %s history: %w", table, err)
+		}
+		n, _ := result.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
+
+// Statistics returns the stored statistics records matching q, oldest first
+func (s *Store) Statistics(ctx context.Context, q Query) ([]*pcf.StatisticsData, error) {
+	where, args := q.conditions()
+	if q.Kind != "" {
+		where = append(where, "kind = ?")
+		args = append(args, q.Kind)
+	}
+	if q.Object != "" {
+		where = append(where, "object = ?")
+		args = append(args, q.Object)
+	}
+
+	var records []*pcf.StatisticsData
+	err := s.query(ctx, "statistics", where, args, q.Limit, func(data []byte) error {
+		var stats pcf.StatisticsData
+		if err := json.Unmarshal(data, &stats); err != nil {
+			return err
+		}
+		records = append(records, &stats)
+		return nil
+	})
+	return records, err
+}
+
+// Accounting returns the stored accounting records within the time range of q, oldest first
+func (s *Store) Accounting(ctx context.Context, q Query) ([]*pcf.AccountingData, error) {
+	where, args := q.conditions()
+	if q.Object != "" {
+		where = append(where, "application = ?")
+		args = append(args, q.Object)
+	}
+
+	var records []*pcf.AccountingData
+	err := s.query(ctx, "accounting", where, args, q.Limit, func(data []byte) error {
+		var acct pcf.AccountingData
+		if err := json.Unmarshal(data, &acct); err != nil {
+			return err
+		}
+		records = append(records, &acct)
+		return nil
+	})
+	return records, err
+}
+
+// QueueHistory returns the depth and throughput of a queue per statistics interval
+func (s *Store) QueueHistory(ctx context.Context, queue string, since, until time.Time) ([]QueuePoint, error) {
+	records, err := s.Statistics(ctx, Query{Kind: KindQueue, Object: queue, Since: since, Until: until})
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]QueuePoint, 0, len(records))
+	for _, stats := range records {
+		q := stats.QueueStats
+		points = append(points, QueuePoint{
+			Time:         stats.Timestamp,
+			QueueManager: stats.QueueManager,
+			Depth:        q.CurrentDepth,
+			HighDepth:    q.HighDepth,
+			Enqueued:     q.EnqueueCount,
+			Dequeued:     q.DequeueCount,
+		})
+	}
+	return points, nil
+}
+
+// conditions returns the time range filter shared by both tables
+func (q Query) conditions() ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+	if !q.Since.IsZero() {
+		where = append(where, "record_time >= ?")
+		args = append(args, q.Since.UnixMilli())
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "record_time < ?")
+		args = append(args, q.Until.UnixMilli())
+	}
+	return where, args
+}
+
+func (s *Store) query(ctx context.Context, table string, where []string, args []interface{}, limit int, scan func([]byte) error) error {
+	query := "SELECT data FROM " + table
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY record_time, id"
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query %s history: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return fmt.Errorf("failed to read %s history: %w", table, err)
+		}
+		if err := scan(data); err != nil {
+			return fmt.Errorf("failed to decode stored %s record: %w", table, err)
+		}
+	}
+	return rows.Err()
+}
+
+// statisticsObject classifies a statistics record by the object it describes
+func statisticsObject(stats *pcf.StatisticsData) (string, string) {
+	switch {
+	case stats.QueueStats != nil:
+		return KindQueue, stats.QueueStats.QueueName
+	case stats.ChannelStats != nil:
+		return KindChannel, stats.ChannelStats.ChannelName
+	case stats.MQIStats != nil:
+		return KindMQI, stats.MQIStats.ApplicationName
+	default:
+		return KindOther, ""
+	}
+}
+
+// recordTime returns the record timestamp in Unix milliseconds, or now for records without one
+func recordTime(ts, now time.Time) int64 {
+	if ts.IsZero() {
+		return now.UnixMilli()
+	}
+	return ts.UnixMilli()
+}
+
+// queueManager returns the record's queue manager, or the batch's configured one
+func queueManager(batch *sinks.Batch, name string) string {
+	if name != "" {
+		return name
+	}
+	return batch.QueueManager
+}
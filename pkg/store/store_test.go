@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/config"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/pcf"
+	"github.com/atulksin/ibmmq-go-stat-otel/pkg/sinks"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestStore(t *testing.T, retention time.Duration) (*Store, string) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	s, err := Open(&config.StoreConfig{Enabled: true, Path: path, Retention: retention}, logrus.New())
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s, path
+}
+
+func queueRecord(queue string, depth int64, at time.Time) *pcf.StatisticsData {
+	return &pcf.StatisticsData{
+		Type:       "statistics",
+		Timestamp:  at,
+		QueueStats: &pcf.QueueStatistics{QueueName: queue, CurrentDepth: depth, EnqueueCount: depth * 2},
+	}
+}
+
+func TestStoreWriteAndQuery(t *testing.T) {
+	s, _ := openTestStore(t, 0)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	batch := &sinks.Batch{
+		QueueManager: "QM1",
+		Statistics: []*pcf.StatisticsData{
+			queueRecord("APP.ORDERS", 5, base.Add(time.Minute)),
+			queueRecord("APP.ORDERS", 3, base),
+			queueRecord("APP.INVOICES", 9, base),
+			{Type: "statistics", Timestamp: base, ChannelStats: &pcf.ChannelStatistics{ChannelName: "TO.QM2", Messages: 40}},
+		},
+		Accounting: []*pcf.AccountingData{{
+			Type:           "accounting",
+			QueueManager:   "QM1",
+			Timestamp:      base.Add(2 * time.Minute),
+			ConnectionInfo: &pcf.ConnectionInfo{ApplicationName: "orders", UserIdentifier: "app1"},
+			Operations:     &pcf.OperationCounts{Puts: 7},
+		}},
+	}
+	require.NoError(t, s.Write(ctx, batch))
+
+	history, err := s.QueueHistory(ctx, "APP.ORDERS", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, int64(3), history[0].Depth, "oldest first")
+	assert.Equal(t, int64(5), history[1].Depth)
+	assert.Equal(t, int64(10), history[1].Enqueued)
+	assert.Equal(t, "QM1", history[1].QueueManager, "configured queue manager fills in missing names")
+	assert.True(t, history[0].Time.Equal(base))
+
+	channels, err := s.Statistics(ctx, Query{Kind: KindChannel})
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	assert.Equal(t, int64(40), channels[0].ChannelStats.Messages)
+
+	recent, err := s.Statistics(ctx, Query{Since: base.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, recent, 1)
+
+	limited, err := s.Statistics(ctx, Query{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, limited, 2)
+
+	acct, err := s.Accounting(ctx, Query{Object: "orders"})
+	require.NoError(t, err)
+	require.Len(t, acct, 1)
+	assert.Equal(t, int64(7), acct[0].Operations.Puts)
+	assert.Equal(t, "app1", acct[0].ConnectionInfo.UserIdentifier)
+
+	none, err := s.Accounting(ctx, Query{Object: "billing"})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestStoreRetention(t *testing.T) {
+	s, path := openTestStore(t, 24*time.Hour)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, s.Write(ctx, &sinks.Batch{Statistics: []*pcf.StatisticsData{
+		queueRecord("APP.ORDERS", 1, now.Add(-48*time.Hour)),
+		queueRecord("APP.ORDERS", 2, now.Add(-time.Hour)),
+	}}))
+
+	// Expired records are removed when the store is reopened
+	require.NoError(t, s.Close())
+	s, err := Open(&config.StoreConfig{Path: path, Retention: 24 * time.Hour}, logrus.New())
+	require.NoError(t, err)
+	defer s.Close()
+
+	history, err := s.QueueHistory(ctx, "APP.ORDERS", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, int64(2), history[0].Depth)
+
+	deleted, err := s.Prune(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
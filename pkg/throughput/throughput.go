@@ -0,0 +1,110 @@
+package throughput
+
+import (
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rates are the throughput of a channel in its latest statistics interval and the
+// percentiles of its rates over the window, all per second
+type Rates struct {
+	Messages    float64
+	Bytes       float64
+	MessagesP50 float64
+	MessagesP95 float64
+	BytesP50    float64
+	BytesP95    float64
+	// Samples is the number of intervals in the window
+	Samples int
+}
+
+// channelState is the rate history of a channel
+type channelState struct {
+	last     time.Time
+	messages []float64
+	bytes    []float64
+}
+
+// Tracker keeps the message and byte rates of every channel over a window of statistics
+// intervals, so the current throughput can be compared with its history
+type Tracker struct {
+	window int
+
+	mu       sync.Mutex
+	channels map[string]*channelState
+}
+
+// NewTracker creates a tracker keeping the rates of the last window intervals
+func NewTracker(window int) *Tracker {
+	if window < 1 {
+		window = 1
+	}
+	return &Tracker{window: window, channels: make(map[string]*channelState)}
+}
+
+// Observe adds a statistics interval of a channel ending at the given time. The first
+// interval of a channel only marks the start of the next one, as are intervals that are
+// not newer than the previous one; ok is false until a rate is known.
+func (t *Tracker) Observe(key string, at time.Time, messages, bytes int64) (rates Rates, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state := t.channels[key]
+	if state == nil {
+		t.channels[key] = &channelState{last: at}
+		return Rates{}, false
+	}
+	if !at.After(state.last) {
+		return Rates{}, false
+	}
+
+	elapsed := at.Sub(state.last).Seconds()
+	state.last = at
+	rates.Messages = float64(messages) / elapsed
+	rates.Bytes = float64(bytes) / elapsed
+
+	state.messages = appendWindow(state.messages, rates.Messages, t.window)
+	state.bytes = appendWindow(state.bytes, rates.Bytes, t.window)
+
+	rates.MessagesP50, rates.MessagesP95 = percentiles(state.messages)
+	rates.BytesP50, rates.BytesP95 = percentiles(state.bytes)
+	rates.Samples = len(state.messages)
+	return rates, true
+}
+
+// ResetQueueManager drops the rate history of the channels of a queue manager, such as after it
+// restarted, so the outage is not taken for an interval
+func (t *Tracker) ResetQueueManager(qmgr string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prefix := qmgr + "/"
+	maps.DeleteFunc(t.channels, func(key string, _ *channelState) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// appendWindow appends a rate, dropping the oldest beyond window
+func appendWindow(rates []float64, rate float64, window int) []float64 {
+	rates = append(rates, rate)
+	if len(rates) > window {
+		rates = rates[len(rates)-window:]
+	}
+	return rates
+}
+
+// percentiles returns the nearest-rank p50 and p95 of the rates
+func percentiles(rates []float64) (p50, p95 float64) {
+	sorted := append([]float64(nil), rates...)
+	sort.Float64s(sorted)
+	return nearestRank(sorted, 0.50), nearestRank(sorted, 0.95)
+}
+
+// nearestRank returns the p-th percentile of sorted values
+func nearestRank(sorted []float64, p float64) float64 {
+	rank := int(float64(len(sorted))*p+0.5) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
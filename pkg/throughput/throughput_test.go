@@ -0,0 +1,62 @@
+package throughput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerRates(t *testing.T) {
+	tracker := NewTracker(100)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	_, ok := tracker.Observe("QM1/TO.QM2", base, 500, 5000)
+	assert.False(t, ok, "the first interval has no length")
+
+	var rates Rates
+	for i := 1; i <= 20; i++ {
+		rates, ok = tracker.Observe("QM1/TO.QM2", base.Add(time.Duration(i)*time.Minute), int64(i*60), int64(i*6000))
+		require.True(t, ok)
+	}
+
+	assert.Equal(t, 20.0, rates.Messages)
+	assert.Equal(t, 2000.0, rates.Bytes)
+	assert.Equal(t, 20, rates.Samples)
+	assert.Equal(t, 10.0, rates.MessagesP50)
+	assert.Equal(t, 19.0, rates.MessagesP95)
+	assert.Equal(t, 1000.0, rates.BytesP50)
+	assert.Equal(t, 1900.0, rates.BytesP95)
+}
+
+func TestTrackerWindow(t *testing.T) {
+	tracker := NewTracker(2)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tracker.Observe("ch", base, 0, 0)
+	tracker.Observe("ch", base.Add(time.Second), 1000, 0)
+	tracker.Observe("ch", base.Add(2*time.Second), 10, 0)
+	rates, ok := tracker.Observe("ch", base.Add(3*time.Second), 20, 0)
+	require.True(t, ok)
+	assert.Equal(t, 2, rates.Samples)
+	assert.Equal(t, 20.0, rates.MessagesP95, "the busy interval has left the window")
+
+	_, ok = tracker.Observe("ch", base.Add(3*time.Second), 20, 0)
+	assert.False(t, ok, "a repeated interval is ignored")
+}
+
+func TestTrackerResetQueueManager(t *testing.T) {
+	tracker := NewTracker(10)
+	base := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tracker.Observe("QM1/TO.QM2/conn", base, 0, 0)
+	tracker.Observe("QM2/TO.QM1/conn", base, 0, 0)
+	tracker.ResetQueueManager("QM1")
+
+	// The interval after the outage only marks the start of the next one again
+	_, ok := tracker.Observe("QM1/TO.QM2/conn", base.Add(time.Hour), 600, 0)
+	assert.False(t, ok)
+	_, ok = tracker.Observe("QM2/TO.QM1/conn", base.Add(time.Minute), 600, 0)
+	assert.True(t, ok)
+}